@@ -12,7 +12,11 @@
 package excelize
 
 import (
+	"encoding/xml"
+	"strconv"
 	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
 )
 
 type adjustDirection bool
@@ -23,8 +27,8 @@ const (
 )
 
 // adjustHelper provides a function to adjust rows and columns dimensions,
-// hyperlinks, merged cells and auto filter when inserting or deleting rows or
-// columns.
+// hyperlinks, merged cells, auto filter, tables and formula references when
+// inserting or deleting rows or columns.
 //
 // sheet: Worksheet name that we're editing
 // column: Index number of the column we're inserting/deleting before
@@ -38,6 +42,9 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 	if err != nil {
 		return err
 	}
+	if err = checkAdjustLimits(ws, dir, num, offset); err != nil {
+		return err
+	}
 	sheetID := f.getSheetID(sheet)
 	if dir == rows {
 		f.adjustRowDimensions(ws, num, offset)
@@ -54,8 +61,17 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 	if err = f.adjustCalcChain(dir, num, offset, sheetID); err != nil {
 		return err
 	}
+	if err = f.adjustFormulas(sheet, dir, num, offset); err != nil {
+		return err
+	}
 	checkSheet(ws)
 	_ = checkRow(ws)
+	if err = f.adjustTables(ws, sheet, dir, num, offset); err != nil {
+		return err
+	}
+	if err = f.adjustAnnotations(sheet, dir, num, offset); err != nil {
+		return err
+	}
 
 	if ws.MergeCells != nil && len(ws.MergeCells.Cells) == 0 {
 		ws.MergeCells = nil
@@ -64,6 +80,29 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 	return nil
 }
 
+// checkAdjustLimits provides a function to check if the rows or columns will
+// exceed the maximum limit of the worksheet after inserting rows or columns.
+func checkAdjustLimits(ws *xlsxWorksheet, dir adjustDirection, num, offset int) error {
+	if offset <= 0 {
+		return nil
+	}
+	for _, row := range ws.SheetData.Row {
+		if dir == rows {
+			if row.R >= num && row.R+offset > TotalRows {
+				return ErrMaxRows
+			}
+			continue
+		}
+		if len(row.C) == 0 {
+			continue
+		}
+		if col, _, err := CellNameToCoordinates(row.C[len(row.C)-1].R); err == nil && col >= num && col+offset > TotalColumns {
+			return ErrColumnNumber
+		}
+	}
+	return nil
+}
+
 // adjustColDimensions provides a function to update column dimensions when
 // inserting or deleting rows or columns.
 func (f *File) adjustColDimensions(ws *xlsxWorksheet, col, offset int) {
@@ -194,6 +233,160 @@ func (f *File) adjustAutoFilterHelper(dir adjustDirection, coordinates []int, nu
 	return coordinates
 }
 
+// adjustTables provides a function to update the ranges of the tables in the
+// worksheet when inserting or deleting rows or columns. The table will be
+// expanded or shrunk if the rows or columns are inserted or deleted inside
+// the table, and the table columns will be added or removed accordingly.
+func (f *File) adjustTables(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) error {
+	if ws.TableParts == nil || len(ws.TableParts.TableParts) == 0 {
+		return nil
+	}
+	paths, err := f.getSheetTablePaths(sheet)
+	if err != nil {
+		return err
+	}
+	for _, tableXML := range paths {
+		tables, err := f.getTables(tableXML)
+		if err != nil {
+			return err
+		}
+		if len(tables) != 1 {
+			continue
+		}
+		table := tables[0]
+		coordinates, err := f.areaRefToCoordinates(table.Ref)
+		if err != nil {
+			return err
+		}
+		if dir == rows {
+			coordinates[1], coordinates[3] = adjustTableAxis(coordinates[1], num, offset, false), adjustTableAxis(coordinates[3], num, offset, true)
+			if coordinates[3] <= coordinates[1] {
+				coordinates[3] = coordinates[1] + 1
+			}
+		} else if !f.adjustTableColumns(table, sheet, coordinates, num, offset) {
+			continue
+		}
+		if table.Ref, err = f.coordinatesToAreaRef(coordinates); err != nil {
+			return err
+		}
+		if table.AutoFilter != nil {
+			table.AutoFilter.Ref = table.Ref
+		}
+		content, _ := xml.Marshal(table)
+		f.saveFileList(tableXML, content)
+	}
+	return nil
+}
+
+// adjustAnnotations provides a function to update the ranges of the cell
+// annotations in the worksheet when inserting or deleting rows or columns,
+// the annotations of the deleted cells will be removed.
+func (f *File) adjustAnnotations(sheet string, dir adjustDirection, num, offset int) error {
+	annotationsPath := f.getAnnotationsPath()
+	if annotationsPath == "" {
+		return nil
+	}
+	annotations, err := f.annotationsReader(annotationsPath)
+	if err != nil {
+		return err
+	}
+	var changed bool
+	result := annotations.Annotation[:0]
+	for _, a := range annotations.Annotation {
+		if !strings.EqualFold(a.Sheet, sheet) {
+			result = append(result, a)
+			continue
+		}
+		ref := adjustCellRef(a.Ref, dir, num, offset)
+		if ref == formulaErrorREF {
+			changed = true
+			continue
+		}
+		if ref != a.Ref {
+			a.Ref, changed = ref, true
+		}
+		result = append(result, a)
+	}
+	if !changed {
+		return nil
+	}
+	annotations.Annotation = result
+	return f.annotationsWriter(annotationsPath, annotations)
+}
+
+// adjustTableAxis provides a function to calculate the row or column number
+// of the table boundary after inserting or deleting rows or columns. The
+// start boundary in the deleted rows or columns will be moved to the first
+// row or column after the deleted rows or columns, and the end boundary will
+// be moved to the last row or column before the deleted rows or columns.
+func adjustTableAxis(axis, num, offset int, end bool) int {
+	if axis < num {
+		return axis
+	}
+	if offset < 0 && axis < num-offset {
+		if end {
+			return num - 1
+		}
+		return num
+	}
+	return axis + offset
+}
+
+// adjustTableColumns provides a function to update the coordinates and the
+// columns of the table when inserting or deleting columns, returns false if
+// all columns of the table will be deleted and the table is kept unchanged.
+// The header cells of the inserted table columns will be set by the default
+// column names.
+func (f *File) adjustTableColumns(table *xlsxTable, sheet string, coordinates []int, num, offset int) bool {
+	if table.TableColumns == nil || num > coordinates[2] {
+		return true
+	}
+	if num <= coordinates[0] && (offset > 0 || coordinates[0] >= num-offset) {
+		coordinates[0], coordinates[2] = coordinates[0]+offset, coordinates[2]+offset
+		return true
+	}
+	columns := table.TableColumns.TableColumn
+	if offset < 0 {
+		from, to := num-coordinates[0], num-offset-coordinates[0]
+		if from < 0 {
+			from = 0
+		}
+		if to > len(columns) {
+			to = len(columns)
+		}
+		if to-from >= len(columns) {
+			return false
+		}
+		columns = append(columns[:from:from], columns[to:]...)
+		coordinates[0] = adjustTableAxis(coordinates[0], num, offset, false)
+		coordinates[2] = coordinates[0] + len(columns) - 1
+	} else {
+		var maxID int
+		for _, column := range columns {
+			if column.ID > maxID {
+				maxID = column.ID
+			}
+		}
+		idx := num - coordinates[0]
+		inserted := make([]*xlsxTableColumn, offset)
+		for i := range inserted {
+			maxID++
+			name := "Column" + strconv.Itoa(maxID)
+			for getTableColumnIndex(table, name) != -1 {
+				maxID++
+				name = "Column" + strconv.Itoa(maxID)
+			}
+			inserted[i] = &xlsxTableColumn{ID: maxID, Name: name}
+			cell, _ := CoordinatesToCellName(num+i, coordinates[1])
+			_ = f.SetCellStr(sheet, cell, name)
+		}
+		columns = append(columns[:idx:idx], append(inserted, columns[idx:]...)...)
+		coordinates[2] += offset
+	}
+	table.TableColumns.TableColumn, table.TableColumns.Count = columns, len(columns)
+	return true
+}
+
 // areaRefToCoordinates provides a function to convert area reference to a
 // pair of coordinates.
 func (f *File) areaRefToCoordinates(ref string) ([]int, error) {
@@ -318,24 +511,231 @@ func (f *File) adjustCalcChain(dir adjustDirection, num, offset, sheetID int) er
 	if f.CalcChain == nil {
 		return nil
 	}
-	for index, c := range f.CalcChain.C {
+	calc := make([]xlsxCalcChainC, 0, len(f.CalcChain.C))
+	for _, c := range f.CalcChain.C {
 		if c.I != sheetID {
+			calc = append(calc, c)
 			continue
 		}
 		colNum, rowNum, err := CellNameToCoordinates(c.R)
 		if err != nil {
 			return err
 		}
+		// Remove the cell references in the deleted row or column.
+		if offset < 0 && ((dir == rows && num == rowNum) || (dir == columns && num == colNum)) {
+			continue
+		}
 		if dir == rows && num <= rowNum {
 			if newRow := rowNum + offset; newRow > 0 {
-				f.CalcChain.C[index].R, _ = CoordinatesToCellName(colNum, newRow)
+				c.R, _ = CoordinatesToCellName(colNum, newRow)
 			}
 		}
 		if dir == columns && num <= colNum {
 			if newCol := colNum + offset; newCol > 0 {
-				f.CalcChain.C[index].R, _ = CoordinatesToCellName(newCol, rowNum)
+				c.R, _ = CoordinatesToCellName(newCol, rowNum)
+			}
+		}
+		calc = append(calc, c)
+	}
+	f.CalcChain.C = calc
+	return nil
+}
+
+// adjustFormulas provides a function to update the cell and range references
+// in the formulas of the cells and the defined names in the workbook, which
+// refer to the worksheet when inserting or deleting rows or columns. Both of
+// the absolute and relative references will be adjusted, and the references
+// to the deleted cells will be replaced by the #REF! error.
+func (f *File) adjustFormulas(sheet string, dir adjustDirection, num, offset int) error {
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			if _, ok := err.(ErrNotWorksheet); ok {
+				continue
+			}
+			return err
+		}
+		for i := range ws.SheetData.Row {
+			for j := range ws.SheetData.Row[i].C {
+				c := &ws.SheetData.Row[i].C[j]
+				if c.F == nil {
+					continue
+				}
+				if c.F.Content != "" {
+					c.F.Content = adjustFormulaRefs(c.F.Content, name, sheet, dir, num, offset)
+				}
+				if c.F.Ref != "" && name == sheet {
+					if ref := adjustCellRef(c.F.Ref, dir, num, offset); ref != formulaErrorREF {
+						c.F.Ref = ref
+					}
+				}
 			}
 		}
 	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			dn := &wb.DefinedNames.DefinedName[idx]
+			dn.Data = adjustFormulaRefs(dn.Data, "", sheet, dir, num, offset)
+		}
+	}
 	return nil
 }
+
+// adjustFormulaRefs provides a function to update the cell and range
+// references in the formula by given worksheet name of the formula, the
+// worksheet name of the inserted or deleted rows or columns, the adjust
+// direction, operation axis and offset. The references without worksheet
+// name refer to the worksheet of the formula. The formula will be returned
+// without changes if it doesn't contain the affected references or it can't
+// be parsed.
+func adjustFormulaRefs(formula, formulaSheet, sheet string, dir adjustDirection, num, offset int) string {
+	ast, err := parse.Formula(strings.TrimPrefix(formula, "="))
+	if err != nil {
+		return formula
+	}
+	var changed bool
+	ast.RewriteReferences(func(ref parse.Reference) parse.Reference {
+		refSheet := ref.Sheet
+		if refSheet == "" {
+			refSheet = formulaSheet
+		}
+		if (ref.Type != parse.ReferenceCell && ref.Type != parse.ReferenceRange) || !strings.EqualFold(refSheet, sheet) {
+			return ref
+		}
+		if adjusted := adjustCellRef(ref.Ref, dir, num, offset); adjusted != ref.Ref {
+			changed, ref.Ref = true, adjusted
+		}
+		return ref
+	})
+	if !changed {
+		return formula
+	}
+	if strings.HasPrefix(formula, "=") {
+		return "=" + ast.String()
+	}
+	return ast.String()
+}
+
+// cellRefPart directly maps the cell reference, the column reference or the
+// row reference of the reference in the A1 reference style. The zero column
+// or row number means the part doesn't specify the column or row.
+type cellRefPart struct {
+	col, row       int
+	absCol, absRow bool
+}
+
+// parseCellRefParts provides a function to parse the cell reference, the
+// range reference, the whole columns or the whole rows reference in the A1
+// reference style into the parts by the same regular expressions which used
+// to convert the references in the formulas, such as $A$1, A1:B2, A:C or
+// $1:$3.
+func parseCellRefParts(ref string) ([]cellRefPart, bool) {
+	if m := a1ColsRefRegexp.FindStringSubmatch(ref); m != nil && len(m[0]) == len(ref) {
+		from, err := ColumnNameToNumber(m[2])
+		if err != nil {
+			return nil, false
+		}
+		to, err := ColumnNameToNumber(m[4])
+		return []cellRefPart{{col: from, absCol: m[1] == "$"}, {col: to, absCol: m[3] == "$"}}, err == nil
+	}
+	if m := a1RowsRefRegexp.FindStringSubmatch(ref); m != nil && len(m[0]) == len(ref) {
+		from, _ := strconv.Atoi(m[2])
+		to, _ := strconv.Atoi(m[4])
+		return []cellRefPart{{row: from, absRow: m[1] == "$"}, {row: to, absRow: m[3] == "$"}}, from > 0 && to > 0
+	}
+	cells := strings.Split(ref, ":")
+	if len(cells) > 2 {
+		return nil, false
+	}
+	parts := make([]cellRefPart, len(cells))
+	for idx, cell := range cells {
+		m := a1CellRefRegexp.FindStringSubmatch(cell)
+		if m == nil || len(m[0]) != len(cell) {
+			return nil, false
+		}
+		col, err := ColumnNameToNumber(m[2])
+		if err != nil {
+			return nil, false
+		}
+		row, _ := strconv.Atoi(m[4])
+		if row < 1 {
+			return nil, false
+		}
+		parts[idx] = cellRefPart{col: col, row: row, absCol: m[1] == "$", absRow: m[3] == "$"}
+	}
+	return parts, true
+}
+
+// String returns the reference text of the cell reference part.
+func (p cellRefPart) String() string {
+	var sb strings.Builder
+	if p.col != 0 {
+		if p.absCol {
+			sb.WriteString("$")
+		}
+		name, _ := ColumnNumberToName(p.col)
+		sb.WriteString(name)
+	}
+	if p.row != 0 {
+		if p.absRow {
+			sb.WriteString("$")
+		}
+		sb.WriteString(strconv.Itoa(p.row))
+	}
+	return sb.String()
+}
+
+// adjustCellRef provides a function to update the cell reference or the
+// range reference by given adjust direction, operation axis and offset, it
+// returns #REF! if all the referenced cells are deleted. The range will be
+// expanded or shrunk if the rows or columns are inserted or deleted inside
+// the range.
+func adjustCellRef(ref string, dir adjustDirection, num, offset int) string {
+	parts, ok := parseCellRefParts(ref)
+	if !ok {
+		return ref
+	}
+	first, last := &parts[0].row, &parts[len(parts)-1].row
+	limit := TotalRows
+	if dir == columns {
+		first, last, limit = &parts[0].col, &parts[len(parts)-1].col, TotalColumns
+	}
+	if *first == 0 || *last == 0 {
+		return ref
+	}
+	if offset > 0 {
+		if *first >= num && *first+offset > limit {
+			return formulaErrorREF
+		}
+		if *first >= num {
+			*first += offset
+		}
+		if len(parts) > 1 && *last >= num {
+			if *last += offset; *last > limit {
+				*last = limit
+			}
+		}
+	} else {
+		from, to := num, num-offset-1
+		if *first >= from && *last <= to {
+			return formulaErrorREF
+		}
+		if *first > to {
+			*first += offset
+		} else if *first >= from {
+			*first = from
+		}
+		if len(parts) > 1 {
+			if *last > to {
+				*last += offset
+			} else if *last >= from {
+				*last = from - 1
+			}
+		}
+	}
+	var refs []string
+	for _, part := range parts {
+		refs = append(refs, part.String())
+	}
+	return strings.Join(refs, ":")
+}
@@ -116,3 +116,173 @@ func TestCoordinatesToAreaRef(t *testing.T) {
 func TestSortCoordinates(t *testing.T) {
 	assert.EqualError(t, sortCoordinates(make([]int, 3)), ErrCoordinates.Error())
 }
+
+func TestCheckAdjustLimits(t *testing.T) {
+	ws := &xlsxWorksheet{SheetData: xlsxSheetData{Row: []xlsxRow{{R: TotalRows - 1, C: []xlsxC{{R: "XFC1048575"}}}}}}
+	assert.NoError(t, checkAdjustLimits(ws, rows, 1, 1))
+	assert.EqualError(t, checkAdjustLimits(ws, rows, 1, 2), ErrMaxRows.Error())
+	assert.NoError(t, checkAdjustLimits(ws, rows, TotalRows, 2))
+	assert.NoError(t, checkAdjustLimits(ws, columns, 1, 1))
+	assert.EqualError(t, checkAdjustLimits(ws, columns, 1, 2), ErrColumnNumber.Error())
+	assert.NoError(t, checkAdjustLimits(ws, columns, TotalColumns, 2))
+	assert.NoError(t, checkAdjustLimits(ws, rows, 1, -1))
+	// Test insert rows or columns exceeds maximum limit.
+	f := NewFile()
+	sheet, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	sheet.SheetData = ws.SheetData
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), ErrMaxRows.Error())
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	assert.EqualError(t, f.InsertCol("Sheet1", "A"), ErrColumnNumber.Error())
+}
+
+func TestAdjustTables(t *testing.T) {
+	f := NewFile()
+	for idx, header := range []string{"Price", "Qty", "Amount"} {
+		cell, _ := CoordinatesToCellName(idx+2, 2)
+		assert.NoError(t, f.SetCellStr("Sheet1", cell, header))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "B2", "D5", `{"table_name":"Sales"}`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "SUM(Sales[Amount])"))
+	getTable := func() *xlsxTable {
+		table, _, err := f.getSheetTable("Sheet1", "Sales")
+		assert.NoError(t, err)
+		return table
+	}
+	// Test insert rows before and inside the table.
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.Equal(t, "B3:D6", getTable().Ref)
+	assert.NoError(t, f.InsertRow("Sheet1", 5))
+	assert.Equal(t, "B3:D7", getTable().Ref)
+	assert.Equal(t, "B3:D7", getTable().AutoFilter.Ref)
+	assert.NoError(t, f.InsertRow("Sheet1", 8))
+	assert.Equal(t, "B3:D7", getTable().Ref)
+	// Test remove rows inside the table.
+	assert.NoError(t, f.RemoveRow("Sheet1", 7))
+	assert.Equal(t, "B3:D6", getTable().Ref)
+	// Test insert columns before and inside the table.
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	assert.Equal(t, "C3:E6", getTable().Ref)
+	assert.NoError(t, f.InsertCol("Sheet1", "D"))
+	table := getTable()
+	assert.Equal(t, "C3:F6", table.Ref)
+	assert.Equal(t, 4, table.TableColumns.Count)
+	assert.Equal(t, "Column4", table.TableColumns.TableColumn[1].Name)
+	header, err := f.GetCellValue("Sheet1", "D3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Column4", header)
+	// Test remove columns inside the table.
+	assert.NoError(t, f.RemoveCol("Sheet1", "E"))
+	table = getTable()
+	assert.Equal(t, "C3:E6", table.Ref)
+	assert.Equal(t, []string{"Price", "Column4", "Amount"}, []string{table.TableColumns.TableColumn[0].Name, table.TableColumns.TableColumn[1].Name, table.TableColumns.TableColumn[2].Name})
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	assert.Equal(t, "C3:D6", getTable().Ref)
+	// Test the structured references after inserting rows and columns.
+	assert.NoError(t, f.SetCellValue("Sheet1", "D4", 10))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D6", 5))
+	result, err := f.CalcCellValue("Sheet1", "F2")
+	assert.NoError(t, err)
+	assert.Equal(t, "15", result)
+	// Test remove rows with the header row of the table.
+	assert.NoError(t, f.RemoveRow("Sheet1", 3))
+	assert.Equal(t, "C3:D5", getTable().Ref)
+	// Test adjust the table with invalid range reference.
+	f.Pkg.Store("xl/tables/table1.xml", []byte(`<table name="Sales" ref="C3"></table>`))
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), "parameter is invalid")
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAdjustFormulas(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet 2")
+	for cell, formula := range map[string]string{
+		"A1": "SUM(B2:B5)+$C$3",
+		"A2": "'Sheet 2'!B3*2",
+		"A3": "B3",
+		"A4": "SUM(B:B)+SUM(2:3)",
+		"A5": `"B3"`,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet 2", "A1", "=Sheet1!B3+SUM(Sheet1!$B$2:$B$5)+B3"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$B$2:$B$5"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "'Sheet 2'!$B$3"}))
+	formulas := func(sheet string, cells ...string) []string {
+		var result []string
+		for _, cell := range cells {
+			formula, err := f.GetCellFormula(sheet, cell)
+			assert.NoError(t, err)
+			result = append(result, formula)
+		}
+		return result
+	}
+	// Test insert rows before and inside the referenced ranges.
+	assert.NoError(t, f.InsertRow("Sheet1", 3))
+	assert.Equal(t, []string{"SUM(B2:B6)+$C$4", "'Sheet 2'!B3*2", "", "B4", "SUM(B:B)+SUM(2:4)", `"B3"`}, formulas("Sheet1", "A1", "A2", "A3", "A4", "A5", "A6"))
+	assert.Equal(t, []string{"=Sheet1!B4+SUM(Sheet1!$B$2:$B$6)+B3"}, formulas("Sheet 2", "A1"))
+	assert.Equal(t, "Sheet1!$B$2:$B$6", f.GetDefinedName()[0].RefersTo)
+	assert.Equal(t, "'Sheet 2'!$B$3", f.GetDefinedName()[1].RefersTo)
+	// Test remove rows with the referenced cells.
+	assert.NoError(t, f.RemoveRow("Sheet1", 4))
+	assert.Equal(t, []string{"SUM(B2:B5)+#REF!", "'Sheet 2'!B3*2", "", "SUM(B:B)+SUM(2:3)", `"B3"`}, formulas("Sheet1", "A1", "A2", "A3", "A4", "A5"))
+	assert.Equal(t, []string{"=Sheet1!#REF!+SUM(Sheet1!$B$2:$B$5)+B3"}, formulas("Sheet 2", "A1"))
+	// Test insert and remove columns on the other worksheet.
+	assert.NoError(t, f.InsertCol("Sheet 2", "A"))
+	assert.Equal(t, []string{"SUM(B2:B5)+#REF!", "'Sheet 2'!C3*2"}, formulas("Sheet1", "A1", "A2"))
+	assert.Equal(t, []string{"=Sheet1!#REF!+SUM(Sheet1!$B$2:$B$5)+C3"}, formulas("Sheet 2", "B1"))
+	assert.Equal(t, "'Sheet 2'!$C$3", f.GetDefinedName()[1].RefersTo)
+	assert.NoError(t, f.RemoveCol("Sheet 2", "C"))
+	assert.Equal(t, []string{"'Sheet 2'!#REF!*2"}, formulas("Sheet1", "A2"))
+	assert.Equal(t, "'Sheet 2'!#REF!", f.GetDefinedName()[1].RefersTo)
+	// Test remove columns inside the referenced ranges.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "SUM(B1:D1)"))
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	assert.Equal(t, []string{"SUM(B1:C1)"}, formulas("Sheet1", "D1"))
+	// Test insert rows with the shared formula.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "=G1*2", FormulaOpts{Type: stringPtr(STCellFormulaTypeShared), Ref: stringPtr("F1:F3")}))
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "=G2*2", ws.SheetData.Row[1].C[5].F.Content)
+	assert.Equal(t, "F2:F4", ws.SheetData.Row[1].C[5].F.Ref)
+	// Test adjust the formula which can't be parsed.
+	assert.Equal(t, "SUM(B1", adjustFormulaRefs("SUM(B1", "Sheet1", "Sheet1", rows, 1, 1))
+	assert.Equal(t, "Sales[Amount]", adjustFormulaRefs("Sales[Amount]", "Sheet1", "Sheet1", rows, 1, 1))
+}
+
+func TestAdjustCellRef(t *testing.T) {
+	for _, c := range []struct {
+		ref      string
+		dir      adjustDirection
+		num      int
+		offset   int
+		expected string
+	}{
+		{"A1", rows, 1, 1, "A2"},
+		{"$A$1", rows, 1, 2, "$A$3"},
+		{"A1", rows, 2, 1, "A1"},
+		{"A1", columns, 1, 1, "B1"},
+		{"$B$1:$D$3", columns, 3, 1, "$B$1:$E$3"},
+		{"B1:D3", columns, 2, -1, "B1:C3"},
+		{"B1:D3", columns, 1, -1, "A1:C3"},
+		{"B1:D3", columns, 4, -1, "B1:C3"},
+		{"B1:B3", columns, 2, -1, "#REF!"},
+		{"B1:D3", rows, 1, -1, "B1:D2"},
+		{"B2:D3", rows, 2, -2, "#REF!"},
+		{"A:C", columns, 2, 1, "A:D"},
+		{"A:C", rows, 2, 1, "A:C"},
+		{"$2:$4", rows, 3, -1, "$2:$3"},
+		{"2:4", columns, 3, -1, "2:4"},
+		{"A1048576", rows, 1, 1, "#REF!"},
+		{"A1:A1048576", rows, 1, 1, "A2:A1048576"},
+		{"B1:XFD1", columns, 1, 1, "C1:XFD1"},
+		{"A1:B2:C3", rows, 1, 1, "A1:B2:C3"},
+		{"A0", rows, 1, 1, "A0"},
+		{"XFE1", rows, 1, 1, "XFE1"},
+	} {
+		assert.Equal(t, c.expected, adjustCellRef(c.ref, c.dir, c.num, c.offset), c.ref)
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// getAnnotationsPath provides a function to get the path of the custom XML
+// part which stores the cell annotations from the workbook relationships.
+func (f *File) getAnnotationsPath() string {
+	wbDir := path.Dir(f.getWorkbookPath())
+	if rels := f.relsReader(f.getWorkbookRelsPath()); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type != SourceRelationshipCustomXML {
+				continue
+			}
+			target := strings.TrimPrefix(path.Join(wbDir, rel.Target), "/")
+			if strings.HasPrefix(rel.Target, "/") {
+				target = strings.TrimPrefix(rel.Target, "/")
+			}
+			var root struct {
+				XMLName xml.Name
+			}
+			if err := f.xmlNewDecoder(bytes.NewReader(f.readXML(target))).Decode(&root); err == nil &&
+				root.XMLName.Space == NameSpaceCellAnnotations {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// annotationsReader provides a function to get the pointer to the structure
+// after deserialization of the custom XML part which stores the cell
+// annotations.
+func (f *File) annotationsReader(annotationsPath string) (*xlsxAnnotations, error) {
+	annotations := &xlsxAnnotations{XMLNS: NameSpaceCellAnnotations}
+	if annotationsPath == "" {
+		return annotations, nil
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(f.readXML(annotationsPath))).
+		Decode(annotations); err != nil && err != io.EOF {
+		return annotations, err
+	}
+	annotations.XMLNS = NameSpaceCellAnnotations
+	return annotations, nil
+}
+
+// annotationsWriter provides a function to save the custom XML part which
+// stores the cell annotations, the custom XML part and its properties part
+// will be created if it doesn't exist.
+func (f *File) annotationsWriter(annotationsPath string, annotations *xlsxAnnotations) error {
+	if annotationsPath == "" {
+		itemID, err := newGUID()
+		if err != nil {
+			return err
+		}
+		var itemCount int
+		f.Pkg.Range(func(k, v interface{}) bool {
+			if strings.HasPrefix(k.(string), "customXml/item") && !strings.HasPrefix(k.(string), "customXml/itemProps") {
+				itemCount++
+			}
+			return true
+		})
+		itemName := fmt.Sprintf("item%d.xml", itemCount+1)
+		propsName := fmt.Sprintf("itemProps%d.xml", itemCount+1)
+		isExist := func(name string) bool {
+			_, ok := f.Pkg.Load("customXml/" + name)
+			return ok
+		}
+		for isExist(itemName) || isExist(propsName) {
+			itemCount++
+			itemName = fmt.Sprintf("item%d.xml", itemCount+1)
+			propsName = fmt.Sprintf("itemProps%d.xml", itemCount+1)
+		}
+		annotationsPath = "customXml/" + itemName
+		f.addRels("customXml/_rels/"+itemName+".rels", SourceRelationshipCustomXMLProperties, propsName, "")
+		f.setContentTypes("/customXml/"+propsName, ContentTypeCustomXMLProperties)
+		props, _ := xml.Marshal(xlsxDatastoreItem{
+			XMLNSDs: NameSpaceCustomXMLDataProperties,
+			ItemID:  itemID,
+			SchemaRefs: &xlsxDatastoreSchemaRefs{
+				SchemaRef: []*xlsxDatastoreSchemaRef{{URI: NameSpaceCellAnnotations}},
+			},
+		})
+		f.saveFileList("customXml/"+propsName, props)
+		target := "/" + annotationsPath
+		if wbDir := path.Dir(f.getWorkbookPath()); wbDir != "." {
+			target = strings.Repeat("../", len(strings.Split(wbDir, "/"))) + annotationsPath
+		}
+		f.addRels(f.getWorkbookRelsPath(), SourceRelationshipCustomXML, target, "")
+	}
+	output, err := xml.Marshal(annotations)
+	f.saveFileList(annotationsPath, output)
+	return err
+}
+
+// updateAnnotationsSheet provides a function to update the worksheet name of
+// the cell annotations on renaming the worksheet, the annotations of the
+// worksheet will be removed if the new name is empty.
+func (f *File) updateAnnotationsSheet(oldName, newName string) {
+	annotationsPath := f.getAnnotationsPath()
+	if annotationsPath == "" {
+		return
+	}
+	annotations, err := f.annotationsReader(annotationsPath)
+	if err != nil {
+		return
+	}
+	var changed bool
+	result := annotations.Annotation[:0]
+	for _, a := range annotations.Annotation {
+		if strings.EqualFold(a.Sheet, oldName) {
+			changed = true
+			if newName == "" {
+				continue
+			}
+			a.Sheet = newName
+		}
+		result = append(result, a)
+	}
+	if changed {
+		annotations.Annotation = result
+		_ = f.annotationsWriter(annotationsPath, annotations)
+	}
+}
+
+// prepareAnnotationRange provides a function to validate the worksheet name
+// and the cell or range reference of the annotation, returns the normalized
+// reference and its coordinates.
+func (f *File) prepareAnnotationRange(sheet, rangeRef string) (string, []int, error) {
+	if f.GetSheetIndex(sheet) == -1 {
+		return "", nil, ErrSheetNotExist{sheet}
+	}
+	rangeRef = strings.Replace(rangeRef, "$", "", -1)
+	if !strings.Contains(rangeRef, ":") {
+		col, row, err := CellNameToCoordinates(rangeRef)
+		if err != nil {
+			return "", nil, err
+		}
+		cell, err := CoordinatesToCellName(col, row)
+		return cell, []int{col, row, col, row}, err
+	}
+	coordinates, err := f.areaRefToCoordinates(rangeRef)
+	if err != nil {
+		return "", nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	ref, err := f.coordinatesToAreaRef(coordinates)
+	return ref, coordinates, err
+}
+
+// getAnnotation provides a function to convert the annotation element to
+// the cell annotation.
+func (a *xlsxAnnotation) getAnnotation() (Annotation, error) {
+	annotation := Annotation{Range: a.Ref, Source: a.Source}
+	if a.Time != "" {
+		t, err := time.Parse(time.RFC3339Nano, a.Time)
+		if err != nil {
+			return annotation, err
+		}
+		annotation.Time = t
+	}
+	if len(a.Property) > 0 {
+		annotation.Properties = make(map[string]string, len(a.Property))
+		for _, p := range a.Property {
+			annotation.Properties[p.Name] = p.Value
+		}
+	}
+	return annotation, nil
+}
+
+// SetCellAnnotation provides a function to attach the machine-readable
+// provenance metadata to the cell or range by given worksheet name and
+// annotation settings, such as where and when the data of the cells came
+// from. The annotations are stored in a custom XML part of the workbook
+// keyed by the worksheet name and the range, which is invisible in the
+// spreadsheet applications and will be preserved on saving the workbook.
+// The existing annotation of the same range in the worksheet will be
+// replaced. The annotations follow the cells on inserting or deleting rows
+// and columns, renaming or deleting the worksheet. For example, record that
+// the numbers in Sheet1!B2:B10 came from a query:
+//
+//    err := f.SetCellAnnotation("Sheet1", &excelize.Annotation{
+//        Range:      "B2:B10",
+//        Source:     "SELECT amount FROM sales WHERE year = 2021",
+//        Time:       time.Now(),
+//        Properties: map[string]string{"database": "warehouse"},
+//    })
+//
+func (f *File) SetCellAnnotation(sheet string, annotation *Annotation) error {
+	if annotation == nil {
+		return ErrParameterRequired
+	}
+	ref, _, err := f.prepareAnnotationRange(sheet, annotation.Range)
+	if err != nil {
+		return err
+	}
+	annotationsPath := f.getAnnotationsPath()
+	annotations, err := f.annotationsReader(annotationsPath)
+	if err != nil {
+		return err
+	}
+	item := &xlsxAnnotation{Sheet: sheet, Ref: ref, Source: annotation.Source}
+	if !annotation.Time.IsZero() {
+		item.Time = annotation.Time.UTC().Format(time.RFC3339Nano)
+	}
+	names := make([]string, 0, len(annotation.Properties))
+	for name := range annotation.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		item.Property = append(item.Property, &xlsxAnnotationProperty{Name: name, Value: annotation.Properties[name]})
+	}
+	var replaced bool
+	for idx, a := range annotations.Annotation {
+		if strings.EqualFold(a.Sheet, sheet) && a.Ref == ref {
+			annotations.Annotation[idx], replaced = item, true
+			break
+		}
+	}
+	if !replaced {
+		annotations.Annotation = append(annotations.Annotation, item)
+	}
+	return f.annotationsWriter(annotationsPath, annotations)
+}
+
+// GetAnnotations provides a function to get all cell annotations in the
+// worksheet by given worksheet name, in the order they were added.
+func (f *File) GetAnnotations(sheet string) ([]Annotation, error) {
+	var result []Annotation
+	if f.GetSheetIndex(sheet) == -1 {
+		return result, ErrSheetNotExist{sheet}
+	}
+	annotations, err := f.annotationsReader(f.getAnnotationsPath())
+	if err != nil {
+		return result, err
+	}
+	for _, a := range annotations.Annotation {
+		if !strings.EqualFold(a.Sheet, sheet) {
+			continue
+		}
+		annotation, err := a.getAnnotation()
+		if err != nil {
+			return result, err
+		}
+		result = append(result, annotation)
+	}
+	return result, nil
+}
+
+// GetCellAnnotations provides a function to get the cell annotations which
+// cover the given cell in the worksheet. For example, get the provenance
+// metadata of the cell Sheet1!B5:
+//
+//    annotations, err := f.GetCellAnnotations("Sheet1", "B5")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, annotation := range annotations {
+//        fmt.Println(annotation.Range, annotation.Source, annotation.Time)
+//    }
+//
+func (f *File) GetCellAnnotations(sheet, cell string) ([]Annotation, error) {
+	var result []Annotation
+	if strings.Contains(cell, ":") {
+		return result, ErrParameterInvalid
+	}
+	_, coordinates, err := f.prepareAnnotationRange(sheet, cell)
+	if err != nil {
+		return result, err
+	}
+	annotations, err := f.GetAnnotations(sheet)
+	if err != nil {
+		return result, err
+	}
+	for _, annotation := range annotations {
+		_, ref, err := f.prepareAnnotationRange(sheet, annotation.Range)
+		if err != nil {
+			return result, err
+		}
+		if cellInRef(coordinates, ref) {
+			result = append(result, annotation)
+		}
+	}
+	return result, nil
+}
+
+// DeleteCellAnnotation provides a function to delete the cell annotation by
+// given worksheet name and the range of the annotation.
+func (f *File) DeleteCellAnnotation(sheet, rangeRef string) error {
+	ref, _, err := f.prepareAnnotationRange(sheet, rangeRef)
+	if err != nil {
+		return err
+	}
+	annotationsPath := f.getAnnotationsPath()
+	if annotationsPath == "" {
+		return nil
+	}
+	annotations, err := f.annotationsReader(annotationsPath)
+	if err != nil {
+		return err
+	}
+	for idx, a := range annotations.Annotation {
+		if strings.EqualFold(a.Sheet, sheet) && a.Ref == ref {
+			annotations.Annotation = append(annotations.Annotation[:idx], annotations.Annotation[idx+1:]...)
+			return f.annotationsWriter(annotationsPath, annotations)
+		}
+	}
+	return nil
+}
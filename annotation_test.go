@@ -0,0 +1,145 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCellAnnotation(t *testing.T) {
+	f := NewFile()
+	annotations, err := f.GetAnnotations("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, annotations)
+	assert.NoError(t, f.DeleteCellAnnotation("Sheet1", "A1"))
+
+	ts := time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC)
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{
+		Range:      "$B$10:$B$2",
+		Source:     "SELECT amount FROM sales",
+		Time:       ts,
+		Properties: map[string]string{"database": "warehouse", "rows": "9"},
+	}))
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "B5", Source: "manual"}))
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "C1", Source: "api"}))
+	// Test replace the annotation of the same range
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "C1", Source: "file"}))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellAnnotation("Sheet2", &Annotation{Range: "B5", Source: "other"}))
+
+	file := filepath.Join("test", "TestCellAnnotation.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+
+	annotations, err = f.GetAnnotations("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{
+		{Range: "B2:B10", Source: "SELECT amount FROM sales", Time: ts, Properties: map[string]string{"database": "warehouse", "rows": "9"}},
+		{Range: "B5", Source: "manual"},
+		{Range: "C1", Source: "file"},
+	}, annotations)
+	annotations, err = f.GetCellAnnotations("Sheet1", "B5")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 2)
+	annotations, err = f.GetCellAnnotations("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Empty(t, annotations)
+	annotations, err = f.GetCellAnnotations("Sheet2", "B5")
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{{Range: "B5", Source: "other"}}, annotations)
+
+	// Test add annotation to the workbook with existing custom XML part
+	assert.NoError(t, f.DeleteCellAnnotation("Sheet1", "B5"))
+	assert.NoError(t, f.DeleteCellAnnotation("Sheet1", "D1"))
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "D1"}))
+	annotations, err = f.GetAnnotations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 3)
+	assert.Equal(t, "D1", annotations[2].Range)
+	var items int
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if k.(string) == "customXml/item1.xml" || k.(string) == "customXml/itemProps1.xml" {
+			items++
+		}
+		return true
+	})
+	assert.Equal(t, 2, items)
+
+	// Test cell annotation with invalid parameters
+	assert.EqualError(t, f.SetCellAnnotation("Sheet1", nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetCellAnnotation("SheetN", &Annotation{Range: "A1"}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "A1:B"}), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
+	_, err = f.GetAnnotations("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	_, err = f.GetCellAnnotations("Sheet1", "A1:B2")
+	assert.EqualError(t, err, ErrParameterInvalid.Error())
+	_, err = f.GetCellAnnotations("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.EqualError(t, f.DeleteCellAnnotation("SheetN", "A1"), "sheet SheetN is not exist")
+
+	// Test get cell annotations with invalid time and unsupported charset
+	f = NewFile()
+	assert.NoError(t, f.SetCellAnnotation("Sheet1", &Annotation{Range: "A1"}))
+	f.Pkg.Store("customXml/item1.xml", []byte(`<annotations xmlns="`+NameSpaceCellAnnotations+`"><annotation sheet="Sheet1" ref="A1" time="x"/></annotations>`))
+	_, err = f.GetCellAnnotations("Sheet1", "A1")
+	assert.EqualError(t, err, `parsing time "x" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "x" as "2006"`)
+	f.Pkg.Store("customXml/item1.xml", []byte(`<annotations xmlns="`+NameSpaceCellAnnotations+`"><annotation sheet="Sheet1" ref="A"/></annotations>`))
+	_, err = f.GetCellAnnotations("Sheet1", "A1")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	f.Pkg.Store("customXml/item1.xml", []byte(`<annotations xmlns="`+NameSpaceCellAnnotations+`">`+`<annotation`))
+	_, err = f.annotationsReader("customXml/item1.xml")
+	assert.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+}
+
+func TestAdjustAnnotations(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	// Test add annotations without overwriting the existing custom XML part
+	f.Pkg.Store("customXml/item1.xml", []byte(`<root/>`))
+	for _, annotation := range []Annotation{
+		{Range: "B2:B10", Source: "range"},
+		{Range: "C5", Source: "cell"},
+		{Range: "D3", Source: "deleted"},
+	} {
+		assert.NoError(t, f.SetCellAnnotation("Sheet1", &annotation))
+	}
+	assert.NoError(t, f.SetCellAnnotation("Sheet2", &Annotation{Range: "C5", Source: "other"}))
+	assert.Equal(t, "customXml/item2.xml", f.getAnnotationsPath())
+	assert.Equal(t, []byte(`<root/>`), f.readXML("customXml/item1.xml"))
+
+	getRanges := func(sheet string) []string {
+		annotations, err := f.GetAnnotations(sheet)
+		assert.NoError(t, err)
+		var ranges []string
+		for _, annotation := range annotations {
+			ranges = append(ranges, annotation.Range)
+		}
+		return ranges
+	}
+	assert.NoError(t, f.InsertRow("Sheet1", 3))
+	assert.NoError(t, f.InsertRow("Sheet1", 3))
+	assert.Equal(t, []string{"B2:B12", "C7", "D5"}, getRanges("Sheet1"))
+	assert.Equal(t, []string{"C5"}, getRanges("Sheet2"))
+	assert.NoError(t, f.RemoveRow("Sheet1", 5))
+	assert.Equal(t, []string{"B2:B11", "C6"}, getRanges("Sheet1"))
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	assert.Equal(t, []string{"C2:C11", "D6"}, getRanges("Sheet1"))
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	assert.Equal(t, []string{"C6"}, getRanges("Sheet1"))
+	annotations, err := f.GetCellAnnotations("Sheet1", "C6")
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{{Range: "C6", Source: "cell"}}, annotations)
+
+	// Test annotations follow the renamed and deleted worksheet
+	f.SetSheetName("Sheet1", "Data")
+	assert.Equal(t, []string{"C6"}, getRanges("Data"))
+	f.DeleteSheet("Sheet2")
+	content, err := f.annotationsReader(f.getAnnotationsPath())
+	assert.NoError(t, err)
+	assert.Len(t, content.Annotation, 1)
+	assert.Equal(t, "Data", content.Annotation[0].Sheet)
+}
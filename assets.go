@@ -0,0 +1,135 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Asset directly maps the embedded or linked asset in the workbook. The Type
+// is one of "media", "oleObject", "font", "hyperlink" and "externalLink", the
+// Path is the part path in the package of the embedded asset, or the target
+// of the external asset. The Size is the size in bytes of the embedded asset,
+// and the Sheets are the names of the sheets which referencing the asset in
+// workbook order, it's empty for the workbook level or unused assets.
+type Asset struct {
+	Type     string
+	Path     string
+	Size     int
+	External bool
+	Sheets   []string
+}
+
+// assetRelTypes defined the asset types of the relationships.
+var assetRelTypes = map[string]string{
+	SourceRelationshipImage:            "media",
+	SourceRelationshipOLEObject:        "oleObject",
+	SourceRelationshipPackage:          "oleObject",
+	SourceRelationshipFont:             "font",
+	SourceRelationshipHyperLink:        "hyperlink",
+	SourceRelationshipExternalLinkPath: "externalLink",
+}
+
+// assetPartPrefixes defined the asset types of the parts by the prefix of
+// the part path, which used to find the unused assets in the package.
+var assetPartPrefixes = []struct{ prefix, typ string }{
+	{"xl/media/", "media"},
+	{"xl/embeddings/", "oleObject"},
+	{"xl/fonts/", "font"},
+}
+
+// ListAssets provides a function to get all embedded media, OLE objects,
+// fonts, hyperlinks and external link targets in the workbook, with the sizes
+// and the referencing sheets of each asset, the unused embedded assets are
+// also included with empty sheets. The assets are sorted by the type and
+// path. For example, print the embedded assets of the workbook:
+//
+//    for _, asset := range f.ListAssets() {
+//        if !asset.External {
+//            fmt.Println(asset.Type, asset.Path, asset.Size, asset.Sheets)
+//        }
+//    }
+//
+func (f *File) ListAssets() []Asset {
+	assets := make(map[string]*Asset)
+	addAsset := func(typ, target string, external bool, sheet string) {
+		key := typ + ":" + target
+		asset, ok := assets[key]
+		if !ok {
+			asset = &Asset{Type: typ, Path: target, External: external}
+			if content, ok := f.Pkg.Load(target); ok && !external {
+				asset.Size = len(content.([]byte))
+			}
+			assets[key] = asset
+		}
+		if sheet != "" && inStrSlice(asset.Sheets, sheet) == -1 {
+			asset.Sheets = append(asset.Sheets, sheet)
+		}
+	}
+	sheets := make(map[string]bool)
+	for _, info := range f.GetSheets() {
+		sheets[info.Path] = true
+	}
+	var walk func(part, sheet string, visited map[string]bool)
+	walk = func(part, sheet string, visited map[string]bool) {
+		visited[part] = true
+		relsPath := getPartRelsPath(part)
+		rels := f.relsReader(relsPath)
+		if rels == nil {
+			return
+		}
+		rels.Lock()
+		relationships := append([]xlsxRelationship{}, rels.Relationships...)
+		rels.Unlock()
+		for _, rel := range relationships {
+			external := rel.TargetMode == "External"
+			target := rel.Target
+			if !external {
+				target = getRelsTargetPath(relsPath, rel.Target)
+			}
+			if typ, ok := assetRelTypes[rel.Type]; ok {
+				if typ != "hyperlink" || external {
+					addAsset(typ, target, external, sheet)
+				}
+				continue
+			}
+			if !external && !visited[target] && !sheets[target] {
+				walk(target, sheet, visited)
+			}
+		}
+	}
+	for _, info := range f.GetSheets() {
+		walk(info.Path, info.Name, make(map[string]bool))
+	}
+	walk(f.getWorkbookPath(), "", make(map[string]bool))
+	f.Pkg.Range(func(k, v interface{}) bool {
+		for _, p := range assetPartPrefixes {
+			if strings.HasPrefix(k.(string), p.prefix) && path.Ext(k.(string)) != ".rels" {
+				addAsset(p.typ, k.(string), false, "")
+			}
+		}
+		return true
+	})
+	list := make([]Asset, 0, len(assets))
+	for _, asset := range assets {
+		list = append(list, *asset)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return list[i].Path < list[j].Path
+	})
+	return list
+}
@@ -0,0 +1,49 @@
+package excelize
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAssets(t *testing.T) {
+	f := NewFile()
+	assert.Empty(t, f.ListAssets())
+
+	img, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddPictureFromBytes("Sheet2", "A1", "", "logo", ".png", img))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "logo", ".png", img))
+	assert.NoError(t, f.SetSheetBackground("Sheet1", filepath.Join("test", "images", "background.jpg")))
+	assert.NoError(t, f.SetHeaderFooter("Sheet2", &FormatHeaderFooter{OddHeader: "&L&G"}))
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet2", &FormatHeaderFooterImage{Position: "left", Extension: ".png", File: img}))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B1", "https://github.com/xuri/excelize", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B2", "Sheet2!A1", "Location"))
+	assert.NoError(t, f.AddEmbeddedFont(newTestFont("\x00\x01\x00\x00", "Custom Sans"), false))
+	// Prepare an external link and an unused media part in the workbook.
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`))
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipExternalLink, "externalLinks/externalLink1.xml", "")
+	f.addRels("xl/externalLinks/_rels/externalLink1.xml.rels", SourceRelationshipExternalLinkPath, "file:///C:/Book2.xlsx", "External")
+	f.Pkg.Store("xl/media/image100.png", img)
+	f.Pkg.Store("xl/embeddings/oleObject1.bin", []byte("ole"))
+	f.addRels("xl/worksheets/_rels/sheet2.xml.rels", SourceRelationshipOLEObject, "../embeddings/oleObject1.bin", "")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestListAssets.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestListAssets.xlsx"))
+	assert.NoError(t, err)
+	background, err := ioutil.ReadFile(filepath.Join("test", "images", "background.jpg"))
+	assert.NoError(t, err)
+	assets := f.ListAssets()
+	assert.Equal(t, []Asset{
+		{Type: "externalLink", Path: "file:///C:/Book2.xlsx", External: true},
+		{Type: "font", Path: "xl/fonts/font1.ttf", Size: len(newTestFont("\x00\x01\x00\x00", "Custom Sans"))},
+		{Type: "hyperlink", Path: "https://github.com/xuri/excelize", External: true, Sheets: []string{"Sheet1"}},
+		{Type: "media", Path: "xl/media/image1.png", Size: len(img), Sheets: []string{"Sheet1", "Sheet2"}},
+		{Type: "media", Path: "xl/media/image100.png", Size: len(img)},
+		{Type: "media", Path: "xl/media/image2.jpeg", Size: len(background), Sheets: []string{"Sheet1"}},
+		{Type: "oleObject", Path: "xl/embeddings/oleObject1.bin", Size: 3, Sheets: []string{"Sheet2"}},
+	}, assets)
+}
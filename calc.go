@@ -29,6 +29,7 @@ import (
 	"unicode"
 	"unsafe"
 
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
 	"github.com/xuri/efp"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -48,6 +49,42 @@ const (
 	formulaErrorGETTINGDATA = "#GETTING_DATA"
 )
 
+// formulaErrors defined the error values which could be the result of the
+// formulas.
+var formulaErrors = []string{
+	formulaErrorDIV, formulaErrorNAME, formulaErrorNA, formulaErrorNUM, formulaErrorVALUE,
+	formulaErrorREF, formulaErrorNULL, formulaErrorSPILL, formulaErrorCALC, formulaErrorGETTINGDATA,
+}
+
+// calcError defined the error of the formula which is evaluated to an error
+// value, the code is the error value of the formula, such as #N/A, and the
+// message is the detail of the error, such as "VLOOKUP no result found".
+type calcError struct {
+	code, message string
+}
+
+// Error returns the message of the formula error.
+func (err calcError) Error() string {
+	return err.message
+}
+
+// formulaErrorCode returns the error value of the formula by given error of
+// the evaluation, the empty string will be returned if the error isn't
+// caused by an error value of the formula.
+func formulaErrorCode(err error) string {
+	var e calcError
+	if errors.As(err, &e) {
+		if inStrSlice(formulaErrors, e.code) != -1 {
+			return e.code
+		}
+		return formulaErrorVALUE
+	}
+	if inStrSlice(formulaErrors, err.Error()) != -1 {
+		return err.Error()
+	}
+	return ""
+}
+
 // Numeric precision correct numeric values as legacy Excel application
 // https://en.wikipedia.org/wiki/Numeric_precision_in_Microsoft_Excel In the
 // top figure the fraction 1/9000 in Excel is displayed. Although this number
@@ -104,6 +141,10 @@ const (
 	ArgEmpty
 )
 
+// FormulaArg is the argument of a formula or function, which is used in the
+// custom formula functions registered by the RegisterCalcFunc function.
+type FormulaArg = formulaArg
+
 // formulaArg is the argument of a formula or function.
 type formulaArg struct {
 	SheetName            string
@@ -115,6 +156,7 @@ type formulaArg struct {
 	Error                string
 	Type                 ArgType
 	cellRefs, cellRanges *list.List
+	lambda               *formulaLambda
 }
 
 // Value returns a string data type of the formula argument.
@@ -191,6 +233,8 @@ func (fa formulaArg) ToList() []formulaArg {
 type formulaFuncs struct {
 	f           *File
 	sheet, cell string
+	depth       int
+	err         error
 }
 
 // tokenPriority defined basic arithmetic operator priority.
@@ -211,7 +255,9 @@ var tokenPriority = map[string]int{
 
 // CalcCellValue provides a function to get calculated cell value. This
 // feature is currently in working processing. Array formula, table formula
-// and some other formulas are not supported currently.
+// and some other formulas are not supported currently. Use the
+// RegisterCalcFunc function to provide the implementations of the other
+// formula functions.
 //
 // Supported formula functions:
 //
@@ -242,6 +288,8 @@ var tokenPriority = map[string]int{
 //    BITOR
 //    BITRSHIFT
 //    BITXOR
+//    BYCOL
+//    BYROW
 //    CEILING
 //    CEILING.MATH
 //    CEILING.PRECISE
@@ -286,6 +334,7 @@ var tokenPriority = map[string]int{
 //    FACT
 //    FACTDOUBLE
 //    FALSE
+//    FILTER
 //    FIND
 //    FINDB
 //    FISHER
@@ -346,17 +395,21 @@ var tokenPriority = map[string]int{
 //    ISO.CEILING
 //    ISPMT
 //    KURT
+//    LAMBDA
 //    LARGE
 //    LCM
 //    LEFT
 //    LEFTB
 //    LEN
 //    LENB
+//    LET
 //    LN
 //    LOG
 //    LOG10
 //    LOOKUP
 //    LOWER
+//    MAKEARRAY
+//    MAP
 //    MAX
 //    MDETERM
 //    MEDIAN
@@ -408,6 +461,7 @@ var tokenPriority = map[string]int{
 //    RADIANS
 //    RAND
 //    RANDBETWEEN
+//    REDUCE
 //    REPLACE
 //    REPLACEB
 //    REPT
@@ -419,14 +473,17 @@ var tokenPriority = map[string]int{
 //    ROUNDUP
 //    ROW
 //    ROWS
+//    SCAN
 //    SEC
 //    SECH
+//    SEQUENCE
 //    SHEET
 //    SIGN
 //    SIN
 //    SINH
 //    SKEW
 //    SMALL
+//    SORT
 //    SQRT
 //    SQRTPI
 //    STDEV
@@ -445,6 +502,7 @@ var tokenPriority = map[string]int{
 //    TRUNC
 //    UNICHAR
 //    UNICODE
+//    UNIQUE
 //    UPPER
 //    VAR.P
 //    VARP
@@ -458,16 +516,15 @@ func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
 	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
 		return
 	}
-	ps := efp.ExcelParser()
-	tokens := ps.Parse(formula)
-	if tokens == nil {
-		return
-	}
-	if token, err = f.evalInfixExp(sheet, cell, tokens); err != nil {
+	if token, _, err = f.evalFormula(sheet, cell, formula); err != nil {
 		return
 	}
 	result = token.TValue
 	isNum, precision := isNumeric(result)
+	if isNum && f.isPrecisionAsDisplayed() {
+		result, err = f.roundCellValueAsDisplayed(sheet, cell, result)
+		return
+	}
 	if isNum && precision > 15 {
 		num, _ := roundPrecision(result)
 		result = strings.ToUpper(num)
@@ -475,6 +532,527 @@ func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
 	return
 }
 
+// getCellCalcValue provides a function to get the value of the cell which is
+// referenced by the formula. The numeric value will be rounded to the
+// precision of the number format of the cell if the "Set precision as
+// displayed" option of the workbook is enabled.
+func (f *File) getCellCalcValue(sheet, cell string) (string, error) {
+	if !f.isPrecisionAsDisplayed() {
+		return f.GetCellValue(sheet, cell)
+	}
+	raw, err := f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		if c.T == "" || c.T == "n" {
+			return c.V, true, nil
+		}
+		return "", true, nil
+	})
+	if err != nil || raw == "" {
+		return f.GetCellValue(sheet, cell)
+	}
+	if value, err := f.roundCellValueAsDisplayed(sheet, cell, raw); err != nil || value != raw {
+		return value, err
+	}
+	return f.GetCellValue(sheet, cell)
+}
+
+// calcNode directly maps the formula cell in the dependency graph of the
+// workbook recalculation.
+type calcNode struct {
+	sheet      string
+	cell       string
+	col, row   int
+	spill      []int
+	dependents []int
+	inDegree   int
+}
+
+// calcGraph directly maps the dependency graph of the formulas in the
+// workbook, the formula cells are indexed by the lower case worksheet names
+// and the formula cells of each worksheet are sorted by the rows.
+type calcGraph struct {
+	nodes   []*calcNode
+	index   map[string]map[string]int
+	sheets  map[string][]int
+	spills  map[string][]int
+	names   []DefinedName
+	visited map[string]bool
+}
+
+// CalcAll provides a function to recalculate all formulas in the workbook
+// and write the calculated results back into the cached values of the cells.
+// The formulas are calculated in the order of the dependencies between the
+// formulas, so the formulas which referenced the other formula cells will
+// use the recalculated values. This is useful for the spreadsheets generated
+// by excelize to show correct values in the viewers which don't recalculate
+// the formulas, such as the file preview of the operating system and the
+// cloud storage. For example:
+//
+//    if err := f.CalcAll(); err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := f.SaveAs("Book1.xlsx"); err != nil {
+//        fmt.Println(err)
+//    }
+//
+// The cells with the formulas which could not be calculated will keep their
+// original cached values, and the first error will be returned after all
+// other formulas are calculated. The ErrCircularReference will be returned
+// if the formulas have circular references, and the cells in the cycle will
+// not be calculated.
+func (f *File) CalcAll() error {
+	g, err := f.buildCalcGraph()
+	if err != nil {
+		return err
+	}
+	var queue []int
+	for i, node := range g.nodes {
+		if node.inDegree == 0 {
+			queue = append(queue, i)
+		}
+	}
+	var calcErr error
+	for calculated := 0; len(queue) > 0; calculated++ {
+		node := g.nodes[queue[0]]
+		queue = queue[1:]
+		if err = f.calcNodeValue(node); err != nil && calcErr == nil {
+			calcErr = fmt.Errorf("cannot calculate cell %s!%s: %v", node.sheet, node.cell, err)
+		}
+		for _, dependent := range node.dependents {
+			if g.nodes[dependent].inDegree--; g.nodes[dependent].inDegree == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	if calcErr != nil {
+		return calcErr
+	}
+	for _, node := range g.nodes {
+		if node.inDegree > 0 {
+			return ErrCircularReference
+		}
+	}
+	return nil
+}
+
+// calcNodeValue provides a function to calculate the formula of the node in
+// the dependency graph and write the result into the cached value of the
+// cell, the results of the dynamic array formulas will be spilled into the
+// neighboring cells.
+func (f *File) calcNodeValue(node *calcNode) error {
+	if node.spill != nil {
+		return f.spillDynamicArray(node.sheet, node.cell)
+	}
+	result, err := f.CalcCellValue(node.sheet, node.cell)
+	if err != nil {
+		// the error values of the formulas will be cached as the results
+		if result = formulaErrorCode(err); result == "" {
+			return err
+		}
+	}
+	ws, err := f.workSheetReader(node.sheet)
+	if err != nil {
+		return err
+	}
+	prepareSheetXML(ws, node.col, node.row)
+	setCalcCellValue(&ws.SheetData.Row[node.row-1].C[node.col-1], result)
+	return err
+}
+
+// setCalcCellValue provides a function to set the cached value of the
+// formula cell by given calculated result.
+func setCalcCellValue(c *xlsxC, result string) {
+	c.IS = nil
+	switch {
+	case result == "TRUE" || result == "FALSE":
+		c.T, c.V = "b", map[string]string{"TRUE": "1", "FALSE": "0"}[result]
+	case inStrSlice(formulaErrors, result) != -1:
+		c.T, c.V = "e", result
+	default:
+		if isNum, _ := isNumeric(result); isNum {
+			c.T, c.V = "", result
+			return
+		}
+		c.T, c.V = "str", result
+	}
+}
+
+// dynamicArrayFuncPrefixes defined the prefixes of the dynamic array
+// functions in the formula text, which are required by the spreadsheet
+// applications for treating the formula as a dynamic array formula.
+var dynamicArrayFuncPrefixes = map[string]string{
+	"ANCHORARRAY": "_xlfn.",
+	"FILTER":      "_xlfn._xlws.",
+	"RANDARRAY":   "_xlfn.",
+	"SEQUENCE":    "_xlfn.",
+	"SINGLE":      "_xlfn.",
+	"SORT":        "_xlfn._xlws.",
+	"SORTBY":      "_xlfn.",
+	"UNIQUE":      "_xlfn.",
+	"XLOOKUP":     "_xlfn.",
+	"XMATCH":      "_xlfn.",
+}
+
+// prefixDynamicArrayFormula provides a function to add the required prefixes
+// for the dynamic array functions in the formula. The formula will be
+// returned without change if it couldn't be parsed.
+func prefixDynamicArrayFormula(formula string) string {
+	ast, err := parse.Formula(strings.TrimPrefix(formula, "="))
+	if err != nil {
+		return formula
+	}
+	ast.Walk(func(node *parse.Node) bool {
+		if node.Type == parse.NodeFunction {
+			if prefix, ok := dynamicArrayFuncPrefixes[strings.ToUpper(node.Value)]; ok {
+				node.Value = prefix + strings.ToUpper(node.Value)
+			}
+		}
+		return true
+	})
+	return ast.String()
+}
+
+// getSpillRange provides a function to get the coordinates of the spill
+// range of the dynamic array formula cell.
+func getSpillRange(c *xlsxC) ([]int, bool) {
+	if c.F == nil || c.F.T != STCellFormulaTypeArray || c.CM == 0 {
+		return nil, false
+	}
+	ref := c.F.Ref
+	if ref == "" {
+		ref = c.R
+	}
+	return condFmtRefCoordinates(ref)
+}
+
+// spillDynamicArray provides a function to calculate the dynamic array
+// formula of the cell and spill the results into the neighboring cells. The
+// previous spill range of the formula will be cleared, and the #SPILL! error
+// will be set on the formula cell if the spill range isn't blank or out of
+// the worksheet.
+func (f *File) spillDynamicArray(sheet, cell string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	prepareSheetXML(ws, col, row)
+	anchor := &ws.SheetData.Row[row-1].C[col-1]
+	if anchor.F == nil {
+		return err
+	}
+	if spill, ok := getSpillRange(anchor); ok {
+		clearSpillRange(ws, spill, col, row)
+	}
+	token, arg, err := f.evalFormula(sheet, cell, anchor.F.Content)
+	if err != nil {
+		code := formulaErrorCode(err)
+		if code == "" {
+			return err
+		}
+		anchor.F.Ref = cell
+		setCalcCellValue(anchor, code)
+		return nil
+	}
+	matrix := [][]formulaArg{{newStringFormulaArg(token.TValue)}}
+	if arg.Type != ArgUnknown {
+		matrix = toFormulaMatrix(arg)
+	}
+	rows, cols := len(matrix), 0
+	for _, rowArgs := range matrix {
+		if len(rowArgs) > cols {
+			cols = len(rowArgs)
+		}
+	}
+	if rows == 0 || cols == 0 {
+		matrix, rows, cols = [][]formulaArg{{newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)}}, 1, 1
+	}
+	lastCol, lastRow := col+cols-1, row+rows-1
+	if lastCol > TotalColumns || lastRow > TotalRows || isSpillBlocked(ws, []int{col, row, lastCol, lastRow}, col, row) {
+		anchor.F.Ref = cell
+		setCalcCellValue(anchor, formulaErrorSPILL)
+		return err
+	}
+	for r, rowArgs := range matrix {
+		for c, value := range rowArgs {
+			prepareSheetXML(ws, col+c, row+r)
+			spillCell := &ws.SheetData.Row[row+r-1].C[col+c-1]
+			spillCell.IS = nil
+			result := value.Value()
+			if value.Type == ArgError {
+				result = value.String
+			}
+			if result == "" {
+				spillCell.T, spillCell.V = "", ""
+				continue
+			}
+			if isNum, precision := isNumeric(result); isNum && precision > 15 {
+				num, _ := roundPrecision(result)
+				result = strings.ToUpper(num)
+			}
+			setCalcCellValue(spillCell, result)
+		}
+	}
+	ws.SheetData.Row[row-1].C[col-1].F.Ref = cell
+	if rows > 1 || cols > 1 {
+		lastCell, _ := CoordinatesToCellName(lastCol, lastRow)
+		ws.SheetData.Row[row-1].C[col-1].F.Ref = cell + ":" + lastCell
+	}
+	return err
+}
+
+// clearSpillRange provides a function to clear the cached values of the
+// cells in the spill range of the dynamic array formula, exclude the formula
+// cell and the cells with formulas.
+func clearSpillRange(ws *xlsxWorksheet, spill []int, col, row int) {
+	for r := spill[1]; r <= spill[3] && r <= len(ws.SheetData.Row); r++ {
+		for i := range ws.SheetData.Row[r-1].C {
+			c := &ws.SheetData.Row[r-1].C[i]
+			cellCol, cellRow, err := CellNameToCoordinates(c.R)
+			if err != nil || c.F != nil || (cellCol == col && cellRow == row) {
+				continue
+			}
+			if cellCol >= spill[0] && cellCol <= spill[2] {
+				c.T, c.V, c.IS = "", "", nil
+			}
+		}
+	}
+}
+
+// isSpillBlocked provides a function to check if any cell in the spill range
+// of the dynamic array formula isn't blank, exclude the formula cell.
+func isSpillBlocked(ws *xlsxWorksheet, spill []int, col, row int) bool {
+	for r := spill[1]; r <= spill[3] && r <= len(ws.SheetData.Row); r++ {
+		for _, c := range ws.SheetData.Row[r-1].C {
+			cellCol, cellRow, err := CellNameToCoordinates(c.R)
+			if err != nil || (cellCol == col && cellRow == row) {
+				continue
+			}
+			if cellCol >= spill[0] && cellCol <= spill[2] && (c.V != "" || c.F != nil || c.IS != nil) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildCalcGraph provides a function to build the dependency graph of all
+// formulas in the workbook.
+func (f *File) buildCalcGraph() (*calcGraph, error) {
+	g := &calcGraph{
+		index:  make(map[string]map[string]int),
+		sheets: make(map[string][]int),
+		spills: make(map[string][]int),
+		names:  f.GetDefinedName(),
+	}
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			return g, err
+		}
+		key := strings.ToLower(sheet)
+		g.index[key] = make(map[string]int)
+		for i := range ws.SheetData.Row {
+			for j := range ws.SheetData.Row[i].C {
+				c := &ws.SheetData.Row[i].C[j]
+				if c.F == nil {
+					continue
+				}
+				col, row, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return g, err
+				}
+				node := &calcNode{sheet: sheet, cell: c.R, col: col, row: row}
+				if spill, ok := getSpillRange(c); ok {
+					node.spill = spill
+					g.spills[key] = append(g.spills[key], len(g.nodes))
+				}
+				g.index[key][c.R] = len(g.nodes)
+				g.sheets[key] = append(g.sheets[key], len(g.nodes))
+				g.nodes = append(g.nodes, node)
+			}
+		}
+		// sort the formula cells by the rows to find the cells in the ranges
+		// by the binary search
+		nodes := g.sheets[key]
+		sort.SliceStable(nodes, func(i, j int) bool { return g.nodes[nodes[i]].row < g.nodes[nodes[j]].row })
+	}
+	for i, node := range g.nodes {
+		formula, err := f.GetCellFormula(node.sheet, node.cell)
+		if err != nil {
+			return g, err
+		}
+		if resolved, err := f.resolveFormulaStructRefs(node.sheet, node.cell, formula); err == nil {
+			formula = resolved
+		}
+		precedents := make(map[int]bool)
+		g.visited = make(map[string]bool)
+		g.addPrecedents(formula, node.sheet, precedents)
+		for precedent := range precedents {
+			if precedent == i {
+				continue
+			}
+			g.nodes[precedent].dependents = append(g.nodes[precedent].dependents, i)
+			node.inDegree++
+		}
+	}
+	for _, node := range g.nodes {
+		sort.Ints(node.dependents)
+	}
+	return g, nil
+}
+
+// addPrecedents provides a function to find the formula cells which are
+// referenced by the formula in the worksheet, the references of the defined
+// names in the formula are also resolved.
+func (g *calcGraph) addPrecedents(formula, sheet string, precedents map[int]bool) {
+	ast, err := parse.Formula(strings.TrimPrefix(formula, "="))
+	if err != nil {
+		return
+	}
+	for _, ref := range ast.References() {
+		refSheet := strings.ToLower(ref.Sheet)
+		if refSheet == "" {
+			refSheet = strings.ToLower(sheet)
+		}
+		switch ref.Type {
+		case parse.ReferenceCell:
+			if idx, ok := g.index[refSheet][strings.ToUpper(strings.Replace(ref.Ref, "$", "", -1))]; ok {
+				precedents[idx] = true
+			}
+			g.addSpillPrecedents(ref.Ref, refSheet, precedents)
+		case parse.ReferenceRange:
+			if coordinates, ok := condFmtRefCoordinates(ref.Ref); ok {
+				nodes := g.sheets[refSheet]
+				for i := sort.Search(len(nodes), func(i int) bool { return g.nodes[nodes[i]].row >= coordinates[1] }); i < len(nodes) && g.nodes[nodes[i]].row <= coordinates[3]; i++ {
+					if node := g.nodes[nodes[i]]; node.col >= coordinates[0] && node.col <= coordinates[2] {
+						precedents[nodes[i]] = true
+					}
+				}
+			}
+			g.addSpillPrecedents(ref.Ref, refSheet, precedents)
+		case parse.ReferenceName:
+			if refersTo, ok := g.getDefinedName(ref, sheet); ok && !g.visited[refersTo] {
+				g.visited[refersTo] = true
+				g.addPrecedents(refersTo, sheet, precedents)
+			}
+		}
+	}
+}
+
+// addSpillPrecedents provides a function to find the dynamic array formula
+// cells which spill the results into the cell or range reference.
+func (g *calcGraph) addSpillPrecedents(ref, sheet string, precedents map[int]bool) {
+	coordinates, ok := condFmtRefCoordinates(ref)
+	if !ok {
+		return
+	}
+	for _, idx := range g.spills[sheet] {
+		if spill := g.nodes[idx].spill; spill[0] <= coordinates[2] && spill[2] >= coordinates[0] && spill[1] <= coordinates[3] && spill[3] >= coordinates[1] {
+			precedents[idx] = true
+		}
+	}
+}
+
+// getDefinedName provides a function to get the reference of the defined
+// name in the scope of the worksheet or the workbook.
+func (g *calcGraph) getDefinedName(ref parse.Reference, sheet string) (string, bool) {
+	scope := sheet
+	if ref.Sheet != "" {
+		scope = ref.Sheet
+	}
+	var refersTo string
+	var ok bool
+	for _, dn := range g.names {
+		if !strings.EqualFold(dn.Name, ref.Ref) {
+			continue
+		}
+		if strings.EqualFold(dn.Scope, scope) {
+			return dn.RefersTo, true
+		}
+		if dn.Scope == "Workbook" && ref.Sheet == "" {
+			refersTo, ok = dn.RefersTo, true
+		}
+	}
+	return refersTo, ok
+}
+
+// roundCellValueAsDisplayed provides a function to round the numeric value
+// to the precision of the number format of the given cell. The value will be
+// returned without change if the number format of the cell isn't a number
+// format with digit placeholders.
+func (f *File) roundCellValueAsDisplayed(sheet, cell, value string) (string, error) {
+	styleIdx, err := f.GetCellStyle(sheet, cell)
+	if err != nil {
+		return value, err
+	}
+	num, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value, nil
+	}
+	if rounded, ok := roundNumberAsDisplayed(num, f.getNumFmtCode(styleIdx)); ok {
+		return strconv.FormatFloat(rounded, 'f', -1, 64), nil
+	}
+	return value, nil
+}
+
+// getNumFmtCode provides a function to get the number format code by given
+// style index.
+func (f *File) getNumFmtCode(styleIdx int) string {
+	styleSheet := f.stylesReader()
+	if styleSheet.CellXfs == nil || styleIdx >= len(styleSheet.CellXfs.Xf) || styleSheet.CellXfs.Xf[styleIdx].NumFmtID == nil {
+		return ""
+	}
+	numFmtID := *styleSheet.CellXfs.Xf[styleIdx].NumFmtID
+	if code, ok := builtInNumFmt[numFmtID]; ok {
+		return code
+	}
+	if styleSheet.NumFmts != nil {
+		for _, numFmt := range styleSheet.NumFmts.NumFmt {
+			if numFmt.NumFmtID == numFmtID {
+				return numFmt.FormatCode
+			}
+		}
+	}
+	return ""
+}
+
+// roundNumberAsDisplayed provides a function to round the number to the
+// displayed precision by given number format code, the percent sign and the
+// thousands scaling commas in the number format are taken into account. The
+// second return value will be false if the number format code doesn't contain
+// any digit placeholder, or it's a date, time, scientific notation or
+// fraction number format.
+func roundNumberAsDisplayed(num float64, format string) (float64, bool) {
+	sections := splitNumFmtSections(format)
+	section := sections[0]
+	if num < 0 && len(sections) > 1 && sections[1] != "" {
+		section = sections[1]
+	} else if num == 0 && len(sections) > 2 && sections[2] != "" {
+		section = sections[2]
+	}
+	codes := numFmtBracketRegexp.ReplaceAllString(numFmtQuotedTextRegexp.ReplaceAllString(section, ""), "")
+	if strings.ContainsAny(strings.ToLower(codes), "ymdhs/e") {
+		return num, false
+	}
+	fmtSection := parseNumFmtSection(section)
+	if !fmtSection.hasDigits {
+		return num, false
+	}
+	digits := fmtSection.fracDigits - 3*fmtSection.scaling
+	if fmtSection.percent {
+		digits += 2
+	}
+	if digits < 0 {
+		scale := math.Pow10(-digits)
+		return math.Round(num/scale) * scale, true
+	}
+	scale := math.Pow10(digits)
+	return math.Round(num*scale) / scale, true
+}
+
 // getPriority calculate arithmetic operator priority.
 func getPriority(token efp.Token) (pri int) {
 	pri = tokenPriority[token.TValue]
@@ -544,7 +1122,41 @@ func newEmptyFormulaArg() formulaArg {
 // TODO: handle subtypes: Nothing, Text, Logical, Error, Concatenation, Intersection, Union
 //
 func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token, error) {
-	var err error
+	token, _, err := f.evalInfixExpArg(sheet, cell, tokens)
+	return token, err
+}
+
+// evalFormula evaluate the formula and returns the result token and the
+// result formula argument of the formula. The formula which contains the
+// LET, the LAMBDA, the LAMBDA helper functions or the named LAMBDA functions
+// will be evaluated by the LAMBDA evaluator with the lazy evaluation of the
+// arguments. The structured references of the tables in the formula will be
+// converted to the range references before the evaluation.
+func (f *File) evalFormula(sheet, cell, formula string) (efp.Token, formulaArg, error) {
+	formula, err := f.resolveFormulaStructRefs(sheet, cell, formula)
+	if err != nil {
+		return efp.Token{}, formulaArg{}, errors.New(formulaErrorREF)
+	}
+	if ast, ok := f.parseLambdaFormula(sheet, formula); ok {
+		return f.evalLambdaFormula(sheet, cell, ast)
+	}
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(formula)
+	if tokens == nil {
+		return efp.Token{}, formulaArg{}, nil
+	}
+	return f.evalInfixExpArg(sheet, cell, tokens)
+}
+
+// evalInfixExpArg evaluate the infix expression and returns the result token
+// and the result formula argument of the expression. The result formula
+// argument is available when the expression only contains a formula function
+// call, which could be the array result of the dynamic array functions.
+func (f *File) evalInfixExpArg(sheet, cell string, tokens []efp.Token) (efp.Token, formulaArg, error) {
+	var (
+		err    error
+		result formulaArg
+	)
 	opdStack, optStack, opfStack, opfdStack, opftStack, argsStack := NewStack(), NewStack(), NewStack(), NewStack(), NewStack(), NewStack()
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
@@ -552,7 +1164,7 @@ func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token,
 		// out of function stack
 		if opfStack.Len() == 0 {
 			if err = f.parseToken(sheet, token, opdStack, optStack); err != nil {
-				return efp.Token{}, err
+				return efp.Token{}, result, err
 			}
 		}
 
@@ -576,10 +1188,10 @@ func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token,
 					// parse reference: must reference at here
 					result, err := f.parseReference(sheet, token.TValue)
 					if err != nil {
-						return efp.Token{TValue: formulaErrorNAME}, err
+						return efp.Token{TValue: formulaErrorNAME}, result, err
 					}
 					if result.Type != ArgString {
-						return efp.Token{}, errors.New(formulaErrorVALUE)
+						return efp.Token{}, result, errors.New(formulaErrorVALUE)
 					}
 					opfdStack.Push(efp.Token{
 						TType:    efp.TokenTypeOperand,
@@ -596,10 +1208,10 @@ func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token,
 					}
 					result, err := f.parseReference(sheet, token.TValue)
 					if err != nil {
-						return efp.Token{TValue: formulaErrorNAME}, err
+						return efp.Token{TValue: formulaErrorNAME}, result, err
 					}
 					if result.Type == ArgUnknown {
-						return efp.Token{}, errors.New(formulaErrorVALUE)
+						return efp.Token{}, result, errors.New(formulaErrorVALUE)
 					}
 					argsStack.Peek().(*list.List).PushBack(result)
 					continue
@@ -608,7 +1220,7 @@ func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token,
 
 			// check current token is opft
 			if err = f.parseToken(sheet, token, opfdStack, opftStack); err != nil {
-				return efp.Token{}, err
+				return efp.Token{}, result, err
 			}
 
 			// current token is arg
@@ -632,35 +1244,44 @@ func (f *File) evalInfixExp(sheet, cell string, tokens []efp.Token) (efp.Token,
 				argsStack.Peek().(*list.List).PushBack(newStringFormulaArg(token.TValue))
 			}
 
-			if err = f.evalInfixExpFunc(sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, argsStack); err != nil {
-				return efp.Token{}, err
+			arg, err := f.evalInfixExpFunc(sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, argsStack)
+			if err != nil {
+				return efp.Token{}, result, err
+			}
+			if arg.Type != ArgUnknown {
+				result = arg
 			}
 		}
 	}
+	if optStack.Len() != 0 || opdStack.Len() != 1 {
+		result = formulaArg{}
+	}
 	for optStack.Len() != 0 {
 		topOpt := optStack.Peek().(efp.Token)
 		if err = calculate(opdStack, topOpt); err != nil {
-			return efp.Token{}, err
+			return efp.Token{}, result, err
 		}
 		optStack.Pop()
 	}
 	if opdStack.Len() == 0 {
-		return efp.Token{}, ErrInvalidFormula
+		return efp.Token{}, result, ErrInvalidFormula
 	}
-	return opdStack.Peek().(efp.Token), err
+	return opdStack.Peek().(efp.Token), result, err
 }
 
-// evalInfixExpFunc evaluate formula function in the infix expression.
-func (f *File) evalInfixExpFunc(sheet, cell string, token, nextToken efp.Token, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) error {
+// evalInfixExpFunc evaluate formula function in the infix expression, and
+// returns the result of the formula function which is out of the function
+// stack.
+func (f *File) evalInfixExpFunc(sheet, cell string, token, nextToken efp.Token, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) (formulaArg, error) {
 	if !isFunctionStopToken(token) {
-		return nil
+		return formulaArg{}, nil
 	}
 	// current token is function stop
 	for !opftStack.Empty() {
 		// calculate trigger
 		topOpt := opftStack.Peek().(efp.Token)
 		if err := calculate(opfdStack, topOpt); err != nil {
-			return err
+			return formulaArg{}, err
 		}
 		opftStack.Pop()
 	}
@@ -670,11 +1291,13 @@ func (f *File) evalInfixExpFunc(sheet, cell string, token, nextToken efp.Token,
 		argsStack.Peek().(*list.List).PushBack(newStringFormulaArg(opfdStack.Pop().(efp.Token).TValue))
 	}
 	// call formula function to evaluate
-	arg := callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell}, strings.NewReplacer(
-		"_xlfn.", "", ".", "dot").Replace(opfStack.Peek().(efp.Token).TValue),
-		[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	fn := &formulaFuncs{f: f, sheet: sheet, cell: cell}
+	arg := fn.callFunc(opfStack.Peek().(efp.Token).TValue, argsStack.Peek().(*list.List))
+	if fn.err != nil {
+		return formulaArg{}, fn.err
+	}
 	if arg.Type == ArgError && opfStack.Len() == 1 {
-		return errors.New(arg.Value())
+		return formulaArg{}, calcError{code: arg.String, message: arg.Value()}
 	}
 	argsStack.Pop()
 	opfStack.Pop()
@@ -685,10 +1308,14 @@ func (f *File) evalInfixExpFunc(sheet, cell string, token, nextToken efp.Token,
 		} else {
 			argsStack.Peek().(*list.List).PushBack(arg)
 		}
-	} else {
-		opdStack.Push(efp.Token{TValue: arg.Value(), TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber})
+		return formulaArg{}, nil
 	}
-	return nil
+	value := arg.Value()
+	if matrix := toFormulaMatrix(arg); arg.Type == ArgMatrix && len(matrix) > 0 && len(matrix[0]) > 0 {
+		value = matrix[0][0].Value()
+	}
+	opdStack.Push(efp.Token{TValue: value, TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber})
+	return arg, nil
 }
 
 // calcPow evaluate exponentiation arithmetic operations.
@@ -843,6 +1470,18 @@ func calcDiv(rOpd, lOpd string, opdStack *Stack) error {
 	return nil
 }
 
+// calcOperandError returns the error value of the operands if any operand
+// is an error value, such as the cell reference to the cell with the #N/A
+// error, which will be the result of the operation.
+func calcOperandError(opds ...efp.Token) error {
+	for _, opd := range opds {
+		if inStrSlice(formulaErrors, opd.TValue) != -1 {
+			return errors.New(opd.TValue)
+		}
+	}
+	return nil
+}
+
 // calculate evaluate basic arithmetic operations.
 func calculate(opdStack *Stack, opt efp.Token) error {
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorPrefix {
@@ -850,6 +1489,9 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 			return ErrInvalidFormula
 		}
 		opd := opdStack.Pop().(efp.Token)
+		if err := calcOperandError(opd); err != nil {
+			return err
+		}
 		opdVal, err := strconv.ParseFloat(opd.TValue, 64)
 		if err != nil {
 			return err
@@ -876,6 +1518,9 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(efp.Token)
 		lOpd := opdStack.Pop().(efp.Token)
+		if err := calcOperandError(lOpd, rOpd); err != nil {
+			return err
+		}
 		if err := calcSubtract(rOpd.TValue, lOpd.TValue, opdStack); err != nil {
 			return err
 		}
@@ -887,6 +1532,9 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(efp.Token)
 		lOpd := opdStack.Pop().(efp.Token)
+		if err := calcOperandError(lOpd, rOpd); err != nil {
+			return err
+		}
 		if err := fn(rOpd.TValue, lOpd.TValue, opdStack); err != nil {
 			return err
 		}
@@ -1026,6 +1674,12 @@ func (f *File) parseReference(sheet, reference string) (arg formulaArg, err erro
 		cr := cellRef{}
 		if len(tokens) == 2 { // have a worksheet name
 			cr.Sheet = tokens[0]
+			// the worksheet names are case-insensitive in the references
+			if _, ok := f.sheetMap[trimSheetName(cr.Sheet)]; !ok {
+				if idx := f.GetSheetIndex(cr.Sheet); idx != -1 {
+					cr.Sheet = f.GetSheetName(idx)
+				}
+			}
 			// cast to cell coordinates
 			if cr.Col, cr.Row, err = CellNameToCoordinates(tokens[1]); err != nil {
 				// cast to column
@@ -1157,7 +1811,7 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 				if cell, err = CoordinatesToCellName(col, row); err != nil {
 					return
 				}
-				if value, err = f.GetCellValue(sheet, cell); err != nil {
+				if value, err = f.getCellCalcValue(sheet, cell); err != nil {
 					return
 				}
 				matrixRow = append(matrixRow, formulaArg{
@@ -1176,7 +1830,7 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 		if cell, err = CoordinatesToCellName(cr.Col, cr.Row); err != nil {
 			return
 		}
-		if arg.String, err = f.GetCellValue(cr.Sheet, cell); err != nil {
+		if arg.String, err = f.getCellCalcValue(cr.Sheet, cell); err != nil {
 			return
 		}
 		arg.Type = ArgString
@@ -1184,6 +1838,23 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 	return
 }
 
+// callFunc calls the formula function by given function name and arguments,
+// the registered custom function takes precedence over the built-in
+// function. The unsupported function is an evaluation failure rather than an
+// error value of the formula, which will be recorded as the error of the
+// formula functions.
+func (fn *formulaFuncs) callFunc(name string, argsList *list.List) formulaArg {
+	if calcFn, ok := fn.f.getCalcFunc(name); ok {
+		return callCalcFunc(calcFn, argsList)
+	}
+	name = strings.NewReplacer("_xlfn.", "", "_xlws.", "", ".", "dot").Replace(name)
+	arg := callFuncByName(fn, name, []reflect.Value{reflect.ValueOf(argsList)})
+	if !reflect.ValueOf(fn).MethodByName(name).IsValid() && fn.err == nil {
+		fn.err = errors.New(arg.Error)
+	}
+	return arg
+}
+
 // callFuncByName calls the no error or only error return function with
 // reflect by given receiver, name and parameters.
 func callFuncByName(receiver interface{}, name string, params []reflect.Value) (arg formulaArg) {
@@ -1199,6 +1870,73 @@ func callFuncByName(receiver interface{}, name string, params []reflect.Value) (
 	return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("not support %s function", name))
 }
 
+// calcFuncNameRegexp defined the regular expression to match the valid name
+// of the custom formula function.
+var calcFuncNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// RegisterCalcFunc provides a function to register the custom formula
+// function by given function name and implementation, which will be resolved
+// by the CalcCellValue function when evaluating the formulas. The function
+// name is case-insensitive, and the registered function takes precedence over
+// the built-in function with the same name. The arguments of the function are
+// the evaluated arguments in the formula, the cell references are passed as
+// the string arguments and the range references are passed as the matrix
+// arguments. Return the argument with ArgError type and the error code in the
+// String field to indicate a formula error. Use nil implementation to
+// unregister the function. For example, register the function COMMISSION to
+// calculate the commission with the rate of 5 percent:
+//
+//    err := f.RegisterCalcFunc("COMMISSION", func(args ...excelize.FormulaArg) excelize.FormulaArg {
+//        if len(args) != 1 {
+//            return excelize.FormulaArg{Type: excelize.ArgError, String: "#VALUE!", Error: "COMMISSION requires 1 argument"}
+//        }
+//        num := args[0].ToNumber()
+//        if num.Type != excelize.ArgNumber {
+//            return num
+//        }
+//        return excelize.FormulaArg{Type: excelize.ArgNumber, Number: num.Number * 0.05}
+//    })
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.SetCellFormula("Sheet1", "B1", "COMMISSION(A1)")
+//    result, err := f.CalcCellValue("Sheet1", "B1")
+//
+func (f *File) RegisterCalcFunc(name string, fn func(args ...FormulaArg) FormulaArg) error {
+	if !calcFuncNameRegexp.MatchString(name) {
+		return ErrCalcFuncName
+	}
+	if fn == nil {
+		f.calcFuncs.Delete(strings.ToUpper(name))
+		return nil
+	}
+	f.calcFuncs.Store(strings.ToUpper(name), fn)
+	return nil
+}
+
+// getCalcFunc provides a function to get the registered custom formula
+// function by given function name in the formula.
+func (f *File) getCalcFunc(name string) (func(args ...FormulaArg) FormulaArg, bool) {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"_XLFN.", "_XLUDF."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if fn, ok := f.calcFuncs.Load(name); ok {
+		return fn.(func(args ...FormulaArg) FormulaArg), true
+	}
+	return nil, false
+}
+
+// callCalcFunc calls the custom formula function by given arguments list.
+func callCalcFunc(fn func(args ...FormulaArg) FormulaArg, argsList *list.List) formulaArg {
+	args := make([]formulaArg, 0, argsList.Len())
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		args = append(args, arg.Value.(formulaArg))
+	}
+	return fn(args...)
+}
+
 // formulaCriteriaParser parse formula criteria.
 func formulaCriteriaParser(exp string) (fc *formulaCriteria) {
 	fc = &formulaCriteria{}
@@ -4426,6 +5164,61 @@ func (fn *formulaFuncs) SUMIF(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(sum)
 }
 
+// SUMPRODUCT function returns the sum of the products of the corresponding
+// values in a set of supplied arrays, the arrays should have the same size,
+// and the non-numeric values are treated as zeros. The syntax of the
+// function is:
+//
+//    SUMPRODUCT(array1,[array2],[array3],...)
+//
+func (fn *formulaFuncs) SUMPRODUCT(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SUMPRODUCT requires at least 1 argument")
+	}
+	var products [][]float64
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		token := arg.Value.(formulaArg)
+		if token.Type == ArgError {
+			return token
+		}
+		matrix := toFormulaMatrix(token)
+		if products == nil {
+			products = make([][]float64, len(matrix))
+			for r, row := range matrix {
+				products[r] = make([]float64, len(row))
+				for c := range row {
+					products[r][c] = 1
+				}
+			}
+		}
+		if len(matrix) != len(products) {
+			return newErrorFormulaArg(formulaErrorVALUE, "SUMPRODUCT requires the arrays have the same size")
+		}
+		for r, row := range matrix {
+			if len(row) != len(products[r]) {
+				return newErrorFormulaArg(formulaErrorVALUE, "SUMPRODUCT requires the arrays have the same size")
+			}
+			for c, value := range row {
+				if value.Type == ArgError {
+					return value
+				}
+				num := value.ToNumber()
+				if num.Type != ArgNumber || value.Boolean {
+					num.Number = 0
+				}
+				products[r][c] *= num.Number
+			}
+		}
+	}
+	var sum float64
+	for _, row := range products {
+		for _, product := range row {
+			sum += product
+		}
+	}
+	return newNumberFormulaArg(sum)
+}
+
 // SUMSQ function returns the sum of squares of a supplied set of values. The
 // syntax of the function is:
 //
@@ -7329,6 +8122,884 @@ func (fn *formulaFuncs) ROWS(argsList *list.List) formulaArg {
 	return newStringFormulaArg(strconv.Itoa(result))
 }
 
+// Dynamic Array Functions
+
+// toFormulaMatrix converts the formula argument into the matrix, the list
+// will be converted to a single row matrix and the other arguments will be
+// converted to the matrix with a single value.
+func toFormulaMatrix(arg formulaArg) [][]formulaArg {
+	switch arg.Type {
+	case ArgMatrix:
+		return arg.Matrix
+	case ArgList:
+		return [][]formulaArg{arg.List}
+	}
+	return [][]formulaArg{{arg}}
+}
+
+// transposeFormulaMatrix returns the transposed matrix of the formula
+// arguments.
+func transposeFormulaMatrix(matrix [][]formulaArg) [][]formulaArg {
+	if len(matrix) == 0 {
+		return matrix
+	}
+	transposed := make([][]formulaArg, len(matrix[0]))
+	for i := range transposed {
+		transposed[i] = make([]formulaArg, len(matrix))
+		for j := range matrix {
+			if i < len(matrix[j]) {
+				transposed[i][j] = matrix[j][i]
+			}
+		}
+	}
+	return transposed
+}
+
+// isFormulaArgTruthy checks if the formula argument is TRUE or nonzero
+// number.
+func isFormulaArgTruthy(arg formulaArg) bool {
+	if arg.Type == ArgNumber {
+		return arg.Number != 0
+	}
+	if num := arg.ToNumber(); num.Type == ArgNumber {
+		return num.Number != 0
+	}
+	return strings.EqualFold(arg.Value(), "TRUE")
+}
+
+// sortRank returns the sort rank of the formula argument, the numbers are
+// sorted before the texts, the texts are sorted before the logical values,
+// and the empty values are always sorted to the end.
+func sortRank(arg formulaArg) (int, float64, string) {
+	value := arg.Value()
+	if arg.Type == ArgNumber && !arg.Boolean {
+		return 0, arg.Number, ""
+	}
+	if arg.Type == ArgString {
+		if num, err := strconv.ParseFloat(value, 64); err == nil {
+			return 0, num, ""
+		}
+	}
+	if value == "" {
+		return 3, 0, ""
+	}
+	if arg.Boolean || strings.EqualFold(value, "TRUE") || strings.EqualFold(value, "FALSE") {
+		return 2, 0, strings.ToUpper(value)
+	}
+	return 1, 0, strings.ToLower(value)
+}
+
+// SEQUENCE function generates a list of sequential numbers in an array. The
+// syntax of the function is:
+//
+//   SEQUENCE(rows,[columns],[start],[step])
+//
+func (fn *formulaFuncs) SEQUENCE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SEQUENCE requires at least 1 argument and at most 4 arguments")
+	}
+	params := []float64{0, 1, 1, 1}
+	for i, arg := 0, argsList.Front(); arg != nil; i, arg = i+1, arg.Next() {
+		if arg.Value.(formulaArg).Value() == "" && i > 0 {
+			continue
+		}
+		num := arg.Value.(formulaArg).ToNumber()
+		if num.Type != ArgNumber {
+			return num
+		}
+		params[i] = num.Number
+	}
+	rows, cols := int(params[0]), int(params[1])
+	if rows < 1 || cols < 1 {
+		return newErrorFormulaArg(formulaErrorCALC, "SEQUENCE requires rows and columns greater than 0")
+	}
+	if rows*cols > TotalRows*TotalColumns/1024 {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	matrix := make([][]formulaArg, rows)
+	for i := range matrix {
+		matrix[i] = make([]formulaArg, cols)
+		for j := range matrix[i] {
+			matrix[i][j] = newNumberFormulaArg(params[2] + float64(i*cols+j)*params[3])
+		}
+	}
+	return newMatrixFormulaArg(matrix)
+}
+
+// SORT function sorts the contents of a range or array. The syntax of the
+// function is:
+//
+//   SORT(array,[sort_index],[sort_order],[by_col])
+//
+func (fn *formulaFuncs) SORT(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORT requires at least 1 argument and at most 4 arguments")
+	}
+	matrix := toFormulaMatrix(argsList.Front().Value.(formulaArg))
+	sortIndex, sortOrder, byCol := 1, 1, false
+	if arg := argsList.Front().Next(); arg != nil && arg.Value.(formulaArg).Value() != "" {
+		num := arg.Value.(formulaArg).ToNumber()
+		if num.Type != ArgNumber {
+			return num
+		}
+		sortIndex = int(num.Number)
+	}
+	if argsList.Len() > 2 && argsList.Front().Next().Next().Value.(formulaArg).Value() != "" {
+		num := argsList.Front().Next().Next().Value.(formulaArg).ToNumber()
+		if num.Type != ArgNumber {
+			return num
+		}
+		if sortOrder = int(num.Number); sortOrder != 1 && sortOrder != -1 {
+			return newErrorFormulaArg(formulaErrorVALUE, "SORT requires sort_order to be 1 or -1")
+		}
+	}
+	if argsList.Len() > 3 {
+		b := argsList.Back().Value.(formulaArg).ToBool()
+		if b.Type != ArgNumber {
+			return b
+		}
+		byCol = b.Number == 1
+	}
+	if byCol {
+		matrix = transposeFormulaMatrix(matrix)
+	}
+	if len(matrix) == 0 || sortIndex < 1 || sortIndex > len(matrix[0]) {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORT requires sort_index in the range of the array")
+	}
+	sorted := make([][]formulaArg, len(matrix))
+	copy(sorted, matrix)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, ni, si := sortRank(sorted[i][sortIndex-1])
+		rj, nj, sj := sortRank(sorted[j][sortIndex-1])
+		if ri == 3 || rj == 3 {
+			return ri < rj
+		}
+		less := ri < rj || ri == rj && (ni < nj || ni == nj && si < sj)
+		if sortOrder == -1 {
+			return ri > rj || ri == rj && (ni > nj || ni == nj && si > sj)
+		}
+		return less
+	})
+	if byCol {
+		sorted = transposeFormulaMatrix(sorted)
+	}
+	return newMatrixFormulaArg(sorted)
+}
+
+// UNIQUE function returns a list of unique values in a list or range. The
+// syntax of the function is:
+//
+//   UNIQUE(array,[by_col],[exactly_once])
+//
+func (fn *formulaFuncs) UNIQUE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "UNIQUE requires at least 1 argument and at most 3 arguments")
+	}
+	matrix := toFormulaMatrix(argsList.Front().Value.(formulaArg))
+	var opts [2]bool
+	for i, arg := 0, argsList.Front().Next(); arg != nil; i, arg = i+1, arg.Next() {
+		b := arg.Value.(formulaArg).ToBool()
+		if b.Type != ArgNumber {
+			return b
+		}
+		opts[i] = b.Number == 1
+	}
+	if opts[0] {
+		matrix = transposeFormulaMatrix(matrix)
+	}
+	keys, counts := make([]string, len(matrix)), make(map[string]int)
+	for i, row := range matrix {
+		values := make([]string, len(row))
+		for j, cell := range row {
+			values[j] = strings.ToLower(cell.Value())
+		}
+		keys[i] = strings.Join(values, "\x00")
+		counts[keys[i]]++
+	}
+	var unique [][]formulaArg
+	seen := make(map[string]bool)
+	for i, row := range matrix {
+		if seen[keys[i]] || opts[1] && counts[keys[i]] > 1 {
+			continue
+		}
+		seen[keys[i]] = true
+		unique = append(unique, row)
+	}
+	if len(unique) == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if opts[0] {
+		unique = transposeFormulaMatrix(unique)
+	}
+	return newMatrixFormulaArg(unique)
+}
+
+// FILTER function filters a range of data based on the supplied criteria.
+// The include argument should be a range or an array of the logical values
+// which has the same height or width as the array. The syntax of the
+// function is:
+//
+//   FILTER(array,include,[if_empty])
+//
+func (fn *formulaFuncs) FILTER(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 || argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FILTER requires at least 2 arguments and at most 3 arguments")
+	}
+	matrix := toFormulaMatrix(argsList.Front().Value.(formulaArg))
+	include := toFormulaMatrix(argsList.Front().Next().Value.(formulaArg))
+	byCol := len(include) == 1 && len(include[0]) > 1
+	if byCol {
+		matrix, include = transposeFormulaMatrix(matrix), transposeFormulaMatrix(include)
+	}
+	if len(include) != len(matrix) || len(include[0]) != 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FILTER requires include to be a single row or column with the same size of the array")
+	}
+	var filtered [][]formulaArg
+	for i, row := range matrix {
+		if isFormulaArgTruthy(include[i][0]) {
+			filtered = append(filtered, row)
+		}
+	}
+	if len(filtered) == 0 {
+		if argsList.Len() == 3 {
+			return argsList.Back().Value.(formulaArg)
+		}
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if byCol {
+		filtered = transposeFormulaMatrix(filtered)
+	}
+	return newMatrixFormulaArg(filtered)
+}
+
+// Lambda Functions
+
+// maxLambdaDepth defined the maximum depth of the nested LAMBDA function
+// calls, which avoids the infinite recursion of the named LAMBDA functions.
+const maxLambdaDepth = 256
+
+// formulaLambda directly maps the LAMBDA function in the formula, which
+// contains the parameters, the calculation and the scope of the names where
+// the function was created.
+type formulaLambda struct {
+	params []string
+	body   *parse.Node
+	scope  *formulaScope
+}
+
+// formulaScope directly maps the names which are defined by the LET function
+// and the parameters of the LAMBDA function.
+type formulaScope struct {
+	names  map[string]formulaArg
+	parent *formulaScope
+}
+
+// lambdaFuncs defined the formula functions which evaluate the arguments
+// lazily, which are the LET, the LAMBDA and the LAMBDA helper functions.
+var lambdaFuncs = map[string]bool{
+	"BYCOL": true, "BYROW": true, "LAMBDA": true, "LET": true,
+	"MAKEARRAY": true, "MAP": true, "REDUCE": true, "SCAN": true,
+}
+
+// lookup provides a function to find the value of the name in the scope and
+// the parent scopes, the names are case-insensitive.
+func (s *formulaScope) lookup(name string) (formulaArg, bool) {
+	name = strings.ToUpper(strings.TrimPrefix(strings.ToLower(name), "_xlpm."))
+	for ; s != nil; s = s.parent {
+		if arg, ok := s.names[name]; ok {
+			return arg, true
+		}
+	}
+	return formulaArg{}, false
+}
+
+// lambdaFuncName returns the upper case name of the function without the
+// prefixes of the future functions.
+func lambdaFuncName(name string) string {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"_XLFN.", "_XLWS.", "_XLUDF."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// lambdaParamName returns the name of the LET name or the LAMBDA parameter
+// by given node, the name should be a reference without the worksheet name.
+func lambdaParamName(node *parse.Node) (string, bool) {
+	if node.Type != parse.NodeReference {
+		return "", false
+	}
+	if ref := parse.ParseReference(node.Value); ref.Sheet != "" || ref.Type != parse.ReferenceName {
+		return "", false
+	}
+	return strings.ToUpper(strings.TrimPrefix(strings.ToLower(node.Value), "_xlpm.")), true
+}
+
+// parseLambdaFormula provides a function to parse the formula which should
+// be evaluated by the LAMBDA evaluator, which contains the LET, the LAMBDA,
+// the LAMBDA helper functions, the named LAMBDA functions or the operators
+// on the arrays, which should be evaluated element by element.
+func (f *File) parseLambdaFormula(sheet, formula string) (parse.AST, bool) {
+	upper := strings.ToUpper(formula)
+	candidate := strings.ContainsAny(formula, ":{") || len(f.GetDefinedName()) > 0
+	for name := range lambdaFuncs {
+		if strings.Contains(upper, name+"(") {
+			candidate = true
+		}
+	}
+	for name := range dynamicArrayFuncPrefixes {
+		if strings.Contains(upper, name+"(") {
+			candidate = true
+		}
+	}
+	if !candidate {
+		return parse.AST{}, false
+	}
+	ast, err := parse.Formula(formula)
+	if err != nil {
+		return ast, false
+	}
+	var found bool
+	ast.Walk(func(node *parse.Node) bool {
+		switch node.Type {
+		case parse.NodeFunction:
+			name := lambdaFuncName(node.Value)
+			if lambdaFuncs[name] || f.getDefinedNameRefTo(name, sheet) != "" {
+				found = true
+			}
+		case parse.NodeCall:
+			found = true
+		case parse.NodePrefix, parse.NodeInfix, parse.NodePostfix:
+			if isArrayOperator(node) && f.isArrayNode(sheet, node) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return ast, found
+}
+
+// isArrayOperator returns if the operator node should be evaluated element
+// by element on the arrays, exclude the reference operators.
+func isArrayOperator(node *parse.Node) bool {
+	return node.Type != parse.NodeInfix || inStrSlice([]string{",", " ", ":"}, node.Value) == -1
+}
+
+// isArrayNode returns if the node could be evaluated to an array, which are
+// the range references, the defined names refer to the ranges, the array
+// constants, the dynamic array functions and the operations on them.
+func (f *File) isArrayNode(sheet string, node *parse.Node) bool {
+	switch node.Type {
+	case parse.NodeArray:
+		return true
+	case parse.NodeReference:
+		ref := parse.ParseReference(node.Value)
+		if ref.Type == parse.ReferenceName {
+			return strings.Contains(f.getDefinedNameRefTo(node.Value, sheet), ":")
+		}
+		return ref.Type == parse.ReferenceRange || ref.Type == parse.ReferenceStructured
+	case parse.NodeFunction:
+		_, ok := dynamicArrayFuncPrefixes[lambdaFuncName(node.Value)]
+		return ok
+	case parse.NodeParen, parse.NodePrefix, parse.NodeInfix, parse.NodePostfix:
+		for _, child := range node.Children {
+			if f.isArrayNode(sheet, child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// evalLambdaFormula evaluate the formula which contains the LET, the LAMBDA
+// or the LAMBDA helper functions, and returns the result token and the result
+// formula argument of the formula.
+func (f *File) evalLambdaFormula(sheet, cell string, ast parse.AST) (efp.Token, formulaArg, error) {
+	fn := &formulaFuncs{f: f, sheet: sheet, cell: cell}
+	arg := fn.evalNode(ast.Root, nil)
+	if fn.err != nil {
+		return efp.Token{}, arg, fn.err
+	}
+	if arg.lambda != nil {
+		arg = newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if arg.Type == ArgError {
+		return efp.Token{}, arg, calcError{code: arg.String, message: arg.Value()}
+	}
+	value := arg.Value()
+	if matrix := toFormulaMatrix(arg); arg.Type == ArgMatrix && len(matrix) > 0 && len(matrix[0]) > 0 {
+		value = matrix[0][0].Value()
+	}
+	return efp.Token{TValue: value, TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber}, arg, nil
+}
+
+// evalNode evaluate the node of the formula in the scope of the names, the
+// literal values are evaluated as the string arguments like the arguments
+// of the formula functions in the infix expression.
+func (fn *formulaFuncs) evalNode(node *parse.Node, scope *formulaScope) formulaArg {
+	switch node.Type {
+	case parse.NodeNumber, parse.NodeText, parse.NodeLogical:
+		return newStringFormulaArg(node.Value)
+	case parse.NodeError:
+		return newErrorFormulaArg(node.Value, node.Value)
+	case parse.NodeEmpty:
+		return newEmptyFormulaArg()
+	case parse.NodeParen:
+		return fn.evalNode(node.Children[0], scope)
+	case parse.NodeArray:
+		matrix := make([][]formulaArg, len(node.Children))
+		for i, row := range node.Children {
+			for _, element := range row.Children {
+				matrix[i] = append(matrix[i], fn.evalNode(element, scope))
+			}
+		}
+		return newMatrixFormulaArg(matrix)
+	case parse.NodeReference:
+		return fn.evalReferenceNode(node, scope)
+	case parse.NodeFunction:
+		return fn.evalFunctionNode(node, scope)
+	case parse.NodePrefix, parse.NodeInfix, parse.NodePostfix:
+		return fn.evalOperatorNode(node, scope)
+	case parse.NodeCall:
+		callee := fn.evalNode(node.Children[0], scope)
+		args := make([]formulaArg, 0, len(node.Children)-1)
+		for _, child := range node.Children[1:] {
+			args = append(args, fn.evalNode(child, scope))
+		}
+		return fn.callLambda(callee, args...)
+	}
+	return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+}
+
+// evalReferenceNode evaluate the reference node, the reference will be
+// resolved as the name in the scope, the defined name or the cell reference.
+func (fn *formulaFuncs) evalReferenceNode(node *parse.Node, scope *formulaScope) formulaArg {
+	if _, ok := lambdaParamName(node); ok {
+		if arg, ok := scope.lookup(node.Value); ok {
+			return arg
+		}
+	}
+	reference := node.Value
+	if refersTo := fn.f.getDefinedNameRefTo(reference, fn.sheet); refersTo != "" {
+		if ast, err := parse.Formula(refersTo); err == nil && ast.Root.Type == parse.NodeFunction {
+			return fn.evalNode(ast.Root, nil)
+		}
+		reference = refersTo
+	}
+	arg, err := fn.f.parseReference(fn.sheet, reference)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorNAME, formulaErrorNAME)
+	}
+	return arg
+}
+
+// evalFunctionNode evaluate the function node, the LET, the LAMBDA and the
+// LAMBDA helper functions will be evaluated with the unevaluated arguments,
+// and the arguments of the other functions will be evaluated before calling
+// the functions.
+func (fn *formulaFuncs) evalFunctionNode(node *parse.Node, scope *formulaScope) formulaArg {
+	name := lambdaFuncName(node.Value)
+	if lambdaFuncs[name] {
+		return fn.callLambdaFunc(name, node.Children, scope)
+	}
+	var args []formulaArg
+	for _, child := range node.Children {
+		if child.Type != parse.NodeEmpty {
+			args = append(args, fn.evalNode(child, scope))
+		}
+	}
+	if arg, ok := scope.lookup(node.Value); ok {
+		return fn.callLambda(arg, args...)
+	}
+	if refersTo := fn.f.getDefinedNameRefTo(name, fn.sheet); refersTo != "" {
+		ast, err := parse.Formula(refersTo)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorNAME, formulaErrorNAME)
+		}
+		return fn.callLambda(fn.evalNode(ast.Root, nil), args...)
+	}
+	argsList := list.New()
+	for _, arg := range args {
+		argsList.PushBack(arg)
+	}
+	return fn.callFunc(node.Value, argsList)
+}
+
+// evalOperatorNode evaluate the prefix, infix and postfix operator node by
+// the basic arithmetic operations. The operation will be evaluated element
+// by element if any operand is an array, the single row or single column
+// operand will be expanded to the size of the result, and the elements
+// beyond the size of the smaller operand will be the #N/A error.
+func (fn *formulaFuncs) evalOperatorNode(node *parse.Node, scope *formulaScope) formulaArg {
+	args := make([]formulaArg, 0, len(node.Children))
+	var isMatrix bool
+	for _, child := range node.Children {
+		arg := fn.evalNode(child, scope)
+		if arg.Type == ArgError {
+			return arg
+		}
+		isMatrix = isMatrix || arg.Type == ArgMatrix
+		args = append(args, arg)
+	}
+	if !isMatrix || node.Type == parse.NodeInfix && !isArrayOperator(node) {
+		return calcOperatorNode(node, args)
+	}
+	matrices, rows, cols := make([][][]formulaArg, len(args)), 0, 0
+	for i, arg := range args {
+		if matrices[i] = [][]formulaArg{{arg}}; arg.Type == ArgMatrix {
+			matrices[i] = arg.Matrix
+		}
+		if len(matrices[i]) == 0 || len(matrices[i][0]) == 0 {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		if len(matrices[i]) > rows {
+			rows = len(matrices[i])
+		}
+		if len(matrices[i][0]) > cols {
+			cols = len(matrices[i][0])
+		}
+	}
+	result := make([][]formulaArg, rows)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			values := make([]formulaArg, 0, len(matrices))
+			for _, matrix := range matrices {
+				row, col := r, c
+				if len(matrix) == 1 {
+					row = 0
+				}
+				if len(matrix[0]) == 1 {
+					col = 0
+				}
+				if row >= len(matrix) || col >= len(matrix[row]) {
+					break
+				}
+				values = append(values, matrix[row][col])
+			}
+			if len(values) != len(matrices) {
+				result[r] = append(result[r], newErrorFormulaArg(formulaErrorNA, formulaErrorNA))
+				continue
+			}
+			result[r] = append(result[r], calcOperatorNode(node, values))
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// calcOperatorNode evaluate the prefix, infix and postfix operator node by
+// given single value operands.
+func calcOperatorNode(node *parse.Node, args []formulaArg) formulaArg {
+	opdStack := NewStack()
+	for _, arg := range args {
+		if arg.Type == ArgError {
+			return arg
+		}
+		value := arg.Value()
+		if matrix := toFormulaMatrix(arg); arg.Type == ArgMatrix && len(matrix) > 0 && len(matrix[0]) > 0 {
+			value = matrix[0][0].Value()
+		}
+		if inStrSlice([]string{"&", "=", "<>"}, node.Value) == -1 {
+			if value == "" {
+				value = "0"
+			}
+			if b := newStringFormulaArg(value).ToBool(); b.Type == ArgNumber && !strings.ContainsAny(value, "0123456789") {
+				value = strconv.Itoa(int(b.Number))
+			}
+		}
+		opdStack.Push(efp.Token{TValue: value, TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber})
+	}
+	opt := efp.Token{TValue: node.Value, TType: efp.TokenTypeOperatorInfix}
+	switch node.Type {
+	case parse.NodePrefix:
+		if node.Value == "+" {
+			return newStringFormulaArg(opdStack.Pop().(efp.Token).TValue)
+		}
+		opt.TType = efp.TokenTypeOperatorPrefix
+	case parse.NodePostfix:
+		opdStack.Push(efp.Token{TValue: "100", TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber})
+		opt.TValue = "/"
+	}
+	if err := calculate(opdStack, opt); err != nil {
+		if code := formulaErrorCode(err); code != "" {
+			return newErrorFormulaArg(code, code)
+		}
+		return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+	}
+	if opdStack.Len() != 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	return newStringFormulaArg(opdStack.Pop().(efp.Token).TValue)
+}
+
+// callLambdaFunc calls the LET, the LAMBDA or the LAMBDA helper function by
+// given function name and the unevaluated arguments.
+func (fn *formulaFuncs) callLambdaFunc(name string, args []*parse.Node, scope *formulaScope) formulaArg {
+	switch name {
+	case "BYCOL":
+		return fn.byRowOrCol(name, args, scope, true)
+	case "BYROW":
+		return fn.byRowOrCol(name, args, scope, false)
+	case "LAMBDA":
+		return fn.lambda(args, scope)
+	case "LET":
+		return fn.let(args, scope)
+	case "MAKEARRAY":
+		return fn.makeArray(args, scope)
+	case "MAP":
+		return fn.mapLambda(args, scope)
+	case "REDUCE":
+		return fn.reduce(args, scope)
+	}
+	return fn.scan(args, scope)
+}
+
+// callLambda calls the LAMBDA function by given arguments, the parameters of
+// the LAMBDA function will be defined in a new scope.
+func (fn *formulaFuncs) callLambda(arg formulaArg, args ...formulaArg) formulaArg {
+	if arg.Type == ArgError {
+		return arg
+	}
+	if arg.lambda == nil {
+		return newErrorFormulaArg(formulaErrorVALUE, "formula argument should be a LAMBDA function")
+	}
+	if len(args) != len(arg.lambda.params) {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("LAMBDA requires %d arguments", len(arg.lambda.params)))
+	}
+	if fn.depth >= maxLambdaDepth {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	scope := &formulaScope{names: make(map[string]formulaArg), parent: arg.lambda.scope}
+	for i, param := range arg.lambda.params {
+		scope.names[param] = args[i]
+	}
+	fn.depth++
+	defer func() { fn.depth-- }()
+	return fn.evalNode(arg.lambda.body, scope)
+}
+
+// evalLambdaArg evaluate the last argument of the LAMBDA helper functions,
+// which should be a LAMBDA function with the given number of parameters.
+func (fn *formulaFuncs) evalLambdaArg(name string, node *parse.Node, scope *formulaScope, params int) formulaArg {
+	arg := fn.evalNode(node, scope)
+	if arg.Type == ArgError {
+		return arg
+	}
+	if arg.lambda == nil || len(arg.lambda.params) != params {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires a LAMBDA function with %d parameters", name, params))
+	}
+	return arg
+}
+
+// let function assigns names to the calculation results, and returns the
+// result of the calculation which could use the names. The syntax of the
+// function is:
+//
+//   LET(name1,name_value1,[name2,name_value2,...],calculation)
+//
+func (fn *formulaFuncs) let(args []*parse.Node, scope *formulaScope) formulaArg {
+	if len(args) < 3 || len(args)%2 == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LET requires an odd number of arguments and at least 3 arguments")
+	}
+	letScope := &formulaScope{names: make(map[string]formulaArg), parent: scope}
+	for i := 0; i < len(args)-1; i += 2 {
+		name, ok := lambdaParamName(args[i])
+		if !ok {
+			return newErrorFormulaArg(formulaErrorNAME, "LET requires the names to be valid names")
+		}
+		letScope.names[name] = fn.evalNode(args[i+1], letScope)
+	}
+	return fn.evalNode(args[len(args)-1], letScope)
+}
+
+// lambda function creates a custom function with the parameters and the
+// calculation, which could be called by the LAMBDA helper functions, the
+// names defined by the LET function, the defined names or directly called by
+// the arguments after the function, such as LAMBDA(x,x+1)(1). The syntax of
+// the function is:
+//
+//   LAMBDA([parameter1,parameter2,...],calculation)
+//
+func (fn *formulaFuncs) lambda(args []*parse.Node, scope *formulaScope) formulaArg {
+	if len(args) < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LAMBDA requires at least 1 argument")
+	}
+	lambda := &formulaLambda{body: args[len(args)-1], scope: scope}
+	for _, param := range args[:len(args)-1] {
+		name, ok := lambdaParamName(param)
+		if !ok {
+			return newErrorFormulaArg(formulaErrorVALUE, "LAMBDA requires the parameters to be valid names")
+		}
+		lambda.params = append(lambda.params, name)
+	}
+	return formulaArg{Type: ArgUnknown, lambda: lambda}
+}
+
+// mapLambda function returns an array formed by mapping each value in the
+// arrays to a new value by applying a LAMBDA function. The syntax of the
+// function is:
+//
+//   MAP(array1,[array2,...],lambda)
+//
+func (fn *formulaFuncs) mapLambda(args []*parse.Node, scope *formulaScope) formulaArg {
+	if len(args) < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "MAP requires at least 2 arguments")
+	}
+	lambda := fn.evalLambdaArg("MAP", args[len(args)-1], scope, len(args)-1)
+	if lambda.Type == ArgError {
+		return lambda
+	}
+	var arrays [][][]formulaArg
+	for _, node := range args[:len(args)-1] {
+		arg := fn.evalNode(node, scope)
+		if arg.Type == ArgError {
+			return arg
+		}
+		arrays = append(arrays, toFormulaMatrix(arg))
+	}
+	result := make([][]formulaArg, len(arrays[0]))
+	for i := range arrays[0] {
+		for j := range arrays[0][i] {
+			values := make([]formulaArg, len(arrays))
+			for k, array := range arrays {
+				if i >= len(array) || j >= len(array[i]) {
+					return newErrorFormulaArg(formulaErrorVALUE, "MAP requires the arrays to have the same size")
+				}
+				values[k] = array[i][j]
+			}
+			result[i] = append(result[i], fn.callLambda(lambda, values...))
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// reduceLambda reduces the array to an accumulated value by applying a
+// LAMBDA function to each value, and returns the accumulated values of each
+// step in the shape of the array.
+func (fn *formulaFuncs) reduceLambda(name string, args []*parse.Node, scope *formulaScope) (formulaArg, [][]formulaArg) {
+	if len(args) != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires 3 arguments", name)), nil
+	}
+	lambda := fn.evalLambdaArg(name, args[2], scope, 2)
+	if lambda.Type == ArgError {
+		return lambda, nil
+	}
+	acc, array := fn.evalNode(args[0], scope), fn.evalNode(args[1], scope)
+	if array.Type == ArgError {
+		return array, nil
+	}
+	matrix := toFormulaMatrix(array)
+	steps := make([][]formulaArg, len(matrix))
+	for i, row := range matrix {
+		for _, value := range row {
+			acc = fn.callLambda(lambda, acc, value)
+			steps[i] = append(steps[i], acc)
+		}
+	}
+	return acc, steps
+}
+
+// reduce function reduces an array to an accumulated value by applying a
+// LAMBDA function to each value and returning the total value in the
+// accumulator. The syntax of the function is:
+//
+//   REDUCE([initial_value],array,lambda)
+//
+func (fn *formulaFuncs) reduce(args []*parse.Node, scope *formulaScope) formulaArg {
+	acc, _ := fn.reduceLambda("REDUCE", args, scope)
+	return acc
+}
+
+// scan function scans an array by applying a LAMBDA function to each value
+// and returns an array that has each intermediate value. The syntax of the
+// function is:
+//
+//   SCAN([initial_value],array,lambda)
+//
+func (fn *formulaFuncs) scan(args []*parse.Node, scope *formulaScope) formulaArg {
+	acc, steps := fn.reduceLambda("SCAN", args, scope)
+	if steps == nil {
+		return acc
+	}
+	return newMatrixFormulaArg(steps)
+}
+
+// byRowOrCol is an implementation of the formula functions BYROW and BYCOL.
+// The BYROW function applies a LAMBDA function to each row and returns an
+// array of the results, and the BYCOL function applies a LAMBDA function to
+// each column. The syntax of the functions are:
+//
+//   BYROW(array,lambda)
+//   BYCOL(array,lambda)
+//
+func (fn *formulaFuncs) byRowOrCol(name string, args []*parse.Node, scope *formulaScope, byCol bool) formulaArg {
+	if len(args) != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires 2 arguments", name))
+	}
+	lambda := fn.evalLambdaArg(name, args[1], scope, 1)
+	if lambda.Type == ArgError {
+		return lambda
+	}
+	array := fn.evalNode(args[0], scope)
+	if array.Type == ArgError {
+		return array
+	}
+	matrix := toFormulaMatrix(array)
+	if byCol {
+		matrix = transposeFormulaMatrix(matrix)
+	}
+	result := make([][]formulaArg, len(matrix))
+	for i, row := range matrix {
+		vector := [][]formulaArg{row}
+		if byCol {
+			vector = transposeFormulaMatrix(vector)
+		}
+		arg := fn.callLambda(lambda, newMatrixFormulaArg(vector))
+		if arg.Type == ArgMatrix || arg.lambda != nil {
+			arg = newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+		}
+		result[i] = []formulaArg{arg}
+	}
+	if byCol {
+		result = transposeFormulaMatrix(result)
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// makeArray function returns a calculated array of a specified row and
+// column size, by applying a LAMBDA function. The syntax of the function is:
+//
+//   MAKEARRAY(rows,cols,lambda)
+//
+func (fn *formulaFuncs) makeArray(args []*parse.Node, scope *formulaScope) formulaArg {
+	if len(args) != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "MAKEARRAY requires 3 arguments")
+	}
+	lambda := fn.evalLambdaArg("MAKEARRAY", args[2], scope, 2)
+	if lambda.Type == ArgError {
+		return lambda
+	}
+	var size [2]int
+	for i, node := range args[:2] {
+		num := fn.evalNode(node, scope).ToNumber()
+		if num.Type != ArgNumber {
+			return num
+		}
+		if size[i] = int(num.Number); size[i] < 1 {
+			return newErrorFormulaArg(formulaErrorVALUE, "MAKEARRAY requires rows and cols greater than 0")
+		}
+	}
+	if size[0]*size[1] > TotalRows*TotalColumns/1024 {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	result := make([][]formulaArg, size[0])
+	for r := range result {
+		for c := 0; c < size[1]; c++ {
+			result[r] = append(result[r], fn.callLambda(lambda, newNumberFormulaArg(float64(r+1)), newNumberFormulaArg(float64(c+1))))
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // Web Functions
 
 // ENCODEURL function returns a URL-encoded string, replacing certain
@@ -2,6 +2,7 @@ package excelize
 
 import (
 	"container/list"
+	"encoding/xml"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -552,7 +553,7 @@ func TestCalcCellValue(t *testing.T) {
 		`=MULTINOMIAL("",3,1,2,5)`:     "27720",
 		"=MULTINOMIAL(MULTINOMIAL(1))": "1",
 		// _xlfn.MUNIT
-		"=_xlfn.MUNIT(4)": "",
+		"=_xlfn.MUNIT(4)": "1",
 		// ODD
 		"=ODD(22)":     "23",
 		"=ODD(1.22)":   "3",
@@ -2334,6 +2335,64 @@ func TestCalculate(t *testing.T) {
 	assert.EqualError(t, calculate(opd, opt), err)
 }
 
+func TestCalcPrecisionAsDisplayed(t *testing.T) {
+	f := NewFile()
+	for cell, c := range map[string]struct {
+		value  float64
+		numFmt string
+	}{
+		"A1": {1.26, "0.0"},
+		"A2": {0.1234, "0.0%"},
+		"A3": {12345, "#,##0.0,"},
+		"A4": {-1.26, "0.0;(0.00)"},
+		"B1": {0, "0.0"},
+	} {
+		numFmt := c.numFmt
+		style, err := f.NewStyle(&Style{CustomNumFmt: &numFmt})
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, style))
+		if c.value != 0 {
+			assert.NoError(t, f.SetCellValue("Sheet1", cell, c.value))
+		}
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=A1+A2+A3+A4"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "=SUM(A1:A4)"))
+	for cell, expected := range map[string]string{"B1": "12345.1234", "B2": "12345.1234"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	// Test calculate with the precision as displayed.
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{FullPrecision: boolPtr(false)}))
+	for cell, expected := range map[string]string{"B1": "12300.2", "B2": "12300.163"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	_, err := f.CalcCellValue("SheetN", "B1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	for _, c := range []struct {
+		num, expected float64
+		format        string
+		ok            bool
+	}{
+		{1.25, 1.3, "0.0", true},
+		{-1.25, -1.25, "0.0;[Red]-0.00", true},
+		{0, 0, `0.0;-0.0;"zero"`, false},
+		{0.12345, 0.1235, "0.00%", true},
+		{1234567, 1200000, "0.0,,", true},
+		{1.25, 1.25, "General", false},
+		{43528.75, 43528.75, "yyyy-mm-dd", false},
+		{12345, 12345, "0.00E+00", false},
+		{1.25, 1.25, "# ?/?", false},
+	} {
+		num, ok := roundNumberAsDisplayed(c.num, c.format)
+		assert.Equal(t, c.expected, num, c.format)
+		assert.Equal(t, c.ok, ok, c.format)
+	}
+}
+
 func TestCalcWithDefinedName(t *testing.T) {
 	cellData := [][]interface{}{
 		{"A1 value", "B1 value", nil},
@@ -2608,3 +2667,431 @@ func TestCalcMIRR(t *testing.T) {
 		assert.Equal(t, "", result, formula)
 	}
 }
+
+func TestRegisterCalcFunc(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 200))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 300))
+	assert.NoError(t, f.RegisterCalcFunc("commission", func(args ...FormulaArg) FormulaArg {
+		if len(args) != 1 {
+			return newErrorFormulaArg(formulaErrorVALUE, "COMMISSION requires 1 argument")
+		}
+		num := args[0].ToNumber()
+		if num.Type != ArgNumber {
+			return num
+		}
+		return newNumberFormulaArg(num.Number * 0.05)
+	}))
+	assert.NoError(t, f.RegisterCalcFunc("MY.TOTAL", func(args ...FormulaArg) FormulaArg {
+		var total float64
+		for _, arg := range args {
+			for _, cell := range arg.ToList() {
+				if num := cell.ToNumber(); num.Type == ArgNumber {
+					total += num.Number
+				}
+			}
+		}
+		return newNumberFormulaArg(total)
+	}))
+	for formula, expected := range map[string]string{
+		"=COMMISSION(A1)":              "10",
+		"=Commission(A1)+1":            "11",
+		"=SUM(COMMISSION(A1),A2)":      "310",
+		"=_xludf.MY.TOTAL(A1:A2,5)":    "505",
+		"=MY.TOTAL(A1:A2)*2":           "1000",
+		"=COMMISSION(MY.TOTAL(A1:A2))": "25",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, err := f.CalcCellValue("Sheet1", "B1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test custom function returns the error
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=COMMISSION()"))
+	_, err := f.CalcCellValue("Sheet1", "B1")
+	assert.EqualError(t, err, "COMMISSION requires 1 argument")
+	// Test custom function overrides the built-in function
+	assert.NoError(t, f.RegisterCalcFunc("ABS", func(args ...FormulaArg) FormulaArg {
+		return newNumberFormulaArg(42)
+	}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=ABS(-1)"))
+	result, err := f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", result)
+	// Test unregister the custom function
+	assert.NoError(t, f.RegisterCalcFunc("ABS", nil))
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", result)
+	// Test register custom function with invalid name
+	for _, name := range []string{"", "1ABC", "A-B", "A B"} {
+		assert.EqualError(t, f.RegisterCalcFunc(name, nil), ErrCalcFuncName.Error())
+	}
+}
+
+func TestCalcAll(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	for cell, formula := range map[string]string{
+		"A2": "A1*2",
+		"A3": "A2+A4",
+		"A4": "SUM(A1:A2)",
+		"B1": "Total*10",
+		"B2": "A1>1",
+		"B3": "\"Total: \"&A3",
+		"B4": "Sheet2!A1+1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3*2"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$A$3"}))
+	assert.NoError(t, f.CalcAll())
+	for cell, expected := range map[string]string{"A2": "4", "A3": "10", "A4": "6", "B1": "100", "B2": "1", "B3": "Total: 10", "B4": "21"} {
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", ws.SheetData.Row[1].C[1].T)
+	assert.Equal(t, "str", ws.SheetData.Row[2].C[1].T)
+	// Test recalculate after changes the value of the precedent cell
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.CalcAll())
+	result, err := f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", result)
+
+	// Test recalculate with the case-insensitive worksheet names and the
+	// range references of the formula cells
+	assert.NoError(t, f.SetCellFormula("Sheet2", "B1", "sheet1!A3+SUM(SHEET1!A2:A4)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "C1", "SUM(B1:B1)+B3"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "B3", "B1*2"))
+	assert.NoError(t, f.CalcAll())
+	for cell, expected := range map[string]string{"B1": "15", "B3": "30", "C1": "45"} {
+		result, err = f.GetCellValue("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+
+	// Test recalculate with the formula which could not be calculated
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "UNSUPPORTEDFUNC(A1)"))
+	assert.EqualError(t, f.CalcAll(), "cannot calculate cell Sheet1!C1: not support UNSUPPORTEDFUNC function")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", ""))
+
+	// Test recalculate with the circular references
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "D2+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "D1+1"))
+	assert.EqualError(t, f.CalcAll(), ErrCircularReference.Error())
+	result, err = f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", result)
+
+	// Test recalculate with the invalid cell reference
+	f = NewFile()
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A", F: &xlsxF{Content: "1+1"}}}}}
+	assert.EqualError(t, f.CalcAll(), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestCalcAllErrorValues(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D1", "a"))
+	for cell, formula := range map[string]string{
+		"A1": "1/0",
+		"A2": "NA()",
+		"A3": "VLOOKUP(9,C1:D1,2,FALSE)",
+		"A4": "A1+1",
+		"B1": "1+1",
+		"B2": "B1*C1",
+		"B3": "VLOOKUP(1,C1:D1,2,FALSE)",
+		"B5": "SEQUENCE(2)/0",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "E1", "SEQUENCE(2)/0"))
+	assert.NoError(t, f.CalcAll())
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{
+		"A1": "#DIV/0!", "A2": "#N/A", "A3": "#N/A", "A4": "#DIV/0!", "B5": "#DIV/0!", "E1": "#DIV/0!",
+	} {
+		col, row, _ := CellNameToCoordinates(cell)
+		c := ws.SheetData.Row[row-1].C[col-1]
+		assert.Equal(t, "e", c.T, cell)
+		assert.Equal(t, expected, c.V, cell)
+	}
+	for cell, expected := range map[string]string{"B1": "2", "B2": "2", "B3": "a"} {
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	// Test recalculate with the unsupported function
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B6", "SUM(UNSUPPORTEDFUNC(1))"))
+	assert.EqualError(t, f.CalcAll(), "cannot calculate cell Sheet1!B6: not support UNSUPPORTEDFUNC function")
+}
+
+func TestCalcDynamicArrayFunctions(t *testing.T) {
+	cellData := [][]interface{}{
+		{"Name", "Score", "Pass"},
+		{"b", 3, true},
+		{"a", 1, false},
+		{"c", 2, true},
+		{"a", 1, false},
+		{false, true, true},
+	}
+	f := prepareCalcData(cellData)
+	formulaList := map[string]string{
+		"=SEQUENCE(3)":                           "1",
+		"=SEQUENCE(2,3,10,5)":                    "10",
+		"=SORT(A2:A5)":                           "a",
+		"=SORT(B2:B5,1,-1)":                      "3",
+		"=SORT(A2:B5,2)":                         "a",
+		"=SORT(A2:B2,1,1,TRUE)":                  "3",
+		"=UNIQUE(A2:A5)":                         "b",
+		"=UNIQUE(A2:A5,FALSE,TRUE)":              "b",
+		"=UNIQUE(A2:C2,TRUE)":                    "b",
+		"=FILTER(A2:A5,C2:C5)":                   "b",
+		"=FILTER(A2:C2,A6:C6)":                   "3",
+		"=FILTER(A3,C3,\"None\")":                "None",
+		"=_xlfn._xlws.SORT(_xlfn.UNIQUE(A2:A5))": "a",
+		"=FILTER(A2:A5,B2:B5>1)":                 "b",
+		"=FILTER(A2:A5,B2:B5<2)":                 "a",
+		"=FILTER(A2:A5,(B2:B5>1)*C2:C5)":         "b",
+		"=COUNT(FILTER(B2:B5,A2:A5=\"a\"))":      "2",
+		"=SORT(B2:B5*-1)":                        "-3",
+		"=SORT(-B2:B5,1,-1)":                     "-1",
+		"=UNIQUE(A2:A5&\"x\")":                   "bx",
+		"=UNIQUE(B2:B5+1,FALSE,TRUE)":            "4",
+		"=SUM(B2:B5*2)":                          "14",
+		"=SUM(B2:B3*{1,2})":                      "12",
+		"=SUM(B2:B5%)":                           "0.07",
+		"=SUMPRODUCT(--(B2:B5>1))":               "2",
+		"=SUMPRODUCT(B2:B5,B2:B5)":               "15",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	mathCalcError := map[string]string{
+		"=SEQUENCE()":              "SEQUENCE requires at least 1 argument and at most 4 arguments",
+		"=SEQUENCE(\"\")":          "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=SEQUENCE(0)":             "SEQUENCE requires rows and columns greater than 0",
+		"=SEQUENCE(1048576,16384)": "#NUM!",
+		"=SORT()":                  "SORT requires at least 1 argument and at most 4 arguments",
+		"=SORT(A2:A5,\"x\")":       "strconv.ParseFloat: parsing \"x\": invalid syntax",
+		"=SORT(A2:A5,1,\"x\")":     "strconv.ParseFloat: parsing \"x\": invalid syntax",
+		"=SORT(A2:A5,1,2)":         "SORT requires sort_order to be 1 or -1",
+		"=SORT(A2:A5,1,1,\"x\")":   "strconv.ParseBool: parsing \"x\": invalid syntax",
+		"=SORT(A2:A5,2)":           "SORT requires sort_index in the range of the array",
+		"=UNIQUE()":                "UNIQUE requires at least 1 argument and at most 3 arguments",
+		"=UNIQUE(A2:A5,\"x\")":     "strconv.ParseBool: parsing \"x\": invalid syntax",
+		"=FILTER()":                "FILTER requires at least 2 arguments and at most 3 arguments",
+		"=FILTER(A2:B5,C2:D5)":     "FILTER requires include to be a single row or column with the same size of the array",
+		"=FILTER(A3,C3)":           "#CALC!",
+		"=SUMPRODUCT()":            "SUMPRODUCT requires at least 1 argument",
+		"=SUMPRODUCT(B2:B5,B2:B4)": "SUMPRODUCT requires the arrays have the same size",
+		"=SUMPRODUCT(B2:B5,1/0)":   "#DIV/0!",
+		"=SUMPRODUCT(B2:B4+{1;2})": "#N/A",
+	}
+	for formula, expected := range mathCalcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, "", result, formula)
+	}
+}
+
+func TestCalcLambdaFunctions(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 2, 3},
+		{4, 5, 6},
+		{"a", "b", ""},
+	}
+	f := prepareCalcData(cellData)
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "DOUBLE", RefersTo: "LAMBDA(x,x*2)"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "FACT2", RefersTo: "_xlfn.LAMBDA(_xlpm.n,IF(_xlpm.n<=1,1,_xlpm.n*FACT2(_xlpm.n-1)))"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "LOOP", RefersTo: "LAMBDA(n,LOOP(n))"}))
+	formulaList := map[string]string{
+		"=LET(x,2,x+1)":                           "3",
+		"=LET(x,2,y,x*3,x+y)":                     "8",
+		"=LET(x,A1:C1,SUM(x))":                    "6",
+		"=_xlfn.LET(_xlpm.x,10,_xlpm.x/4)":        "2.5",
+		"=LET(x,5,IF(x>1,\"big\",\"small\"))":     "big",
+		"=LET(x,50%,x*-2)":                        "-1",
+		"=LET(x,TRUE,x+1)":                        "2",
+		"=LET(x,A3,x&\"!\")":                      "a!",
+		"=LET(x,C3,x+1)":                          "1",
+		"=LET(x,1,+x)":                            "1",
+		"=LET(x,1,(x))":                           "1",
+		"=LET(f,LAMBDA(a,b,a*b),f(3,4))":          "12",
+		"=LET(n,10,f,LAMBDA(x,x+n),f(1))":         "11",
+		"=DOUBLE(21)":                             "42",
+		"=FACT2(5)":                               "120",
+		"=MAP(A1:C1,LAMBDA(x,x*10))":              "10",
+		"=SUM(MAP(A1:C1,A2:C2,LAMBDA(a,b,a+b)))":  "21",
+		"=REDUCE(0,A1:C2,LAMBDA(acc,x,acc+x))":    "21",
+		"=REDUCE(,A1:C1,LAMBDA(acc,x,acc+x))":     "6",
+		"=SUM(SCAN(0,A1:C1,LAMBDA(acc,x,acc+x)))": "10",
+		"=MAX(BYROW(A1:C2,LAMBDA(r,SUM(r))))":     "15",
+		"=MIN(BYCOL(A1:C2,LAMBDA(c,MAX(c))))":     "4",
+		"=SUM(MAKEARRAY(2,3,LAMBDA(r,c,r*c)))":    "18",
+		"=SUM(MAKEARRAY(2,2,LAMBDA(r,c,r+c)))":    "12",
+		"=IFERROR(LET(x,1,x/0),\"none\")":         "none",
+		"=LET(x,{1,2;3,4},SUM(x))":                "10",
+		"=SUM(MAP(A1:C1,DOUBLE))":                 "12",
+		"=LAMBDA(x,x+1)(1)":                       "2",
+		"=LAMBDA(a,b,a+b)(2,3)":                   "5",
+		"=_xlfn.LAMBDA(_xlpm.x,_xlpm.x*2)(A2)":    "8",
+		"=LAMBDA(x,LAMBDA(y,x*y))(2)(3)":          "6",
+		"=(LAMBDA(x,x))(\"a\")":                   "a",
+		"=SUM(LAMBDA(x,x*2)(A1:C1))":              "12",
+		"=LET(x,A1:C1,x*2)":                       "2",
+		"=SUM(LET(x,A1:C1,x*2))":                  "12",
+		"=LET(x,A1:C2,SUM(x*x))":                  "91",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	mathCalcError := map[string]string{
+		"=LET(x,1)":                               "LET requires an odd number of arguments and at least 3 arguments",
+		"=LET(A1,1,A1)":                           "LET requires the names to be valid names",
+		"=LET(x,1,x/0)":                           "#DIV/0!",
+		"=LET(x,\"a\",x*2)":                       "strconv.ParseFloat: parsing \"a\": invalid syntax",
+		"=LET(x,#N/A,x+1)":                        "#N/A",
+		"=LAMBDA()":                               "LAMBDA requires at least 1 argument",
+		"=LAMBDA(1,2)":                            "LAMBDA requires the parameters to be valid names",
+		"=LAMBDA(x,x)":                            "#CALC!",
+		"=LET(f,LAMBDA(x,x),f(1,2))":              "LAMBDA requires 1 arguments",
+		"=LET(f,1,f(1))":                          "formula argument should be a LAMBDA function",
+		"=LAMBDA(x,x)(1,2)":                       "LAMBDA requires 1 arguments",
+		"=LAMBDA(x,x)(1/0)":                       "#DIV/0!",
+		"=LET(x,1,x)(2)":                          "formula argument should be a LAMBDA function",
+		"=LOOP(1)":                                "#NUM!",
+		"=MAP(A1:C1)":                             "MAP requires at least 2 arguments",
+		"=MAP(A1:C1,1)":                           "MAP requires a LAMBDA function with 1 parameters",
+		"=MAP(A1:C1,LAMBDA(x,y,x))":               "MAP requires a LAMBDA function with 1 parameters",
+		"=MAP(A1:C2,A1:B1,LAMBDA(x,y,x))":         "MAP requires the arrays to have the same size",
+		"=MAP(#N/A,LAMBDA(x,x))":                  "#N/A",
+		"=REDUCE(0,A1:C1)":                        "REDUCE requires 3 arguments",
+		"=REDUCE(0,A1:C1,LAMBDA(x,x))":            "REDUCE requires a LAMBDA function with 2 parameters",
+		"=REDUCE(0,#N/A,LAMBDA(a,b,a))":           "#N/A",
+		"=SCAN(0,A1:C1)":                          "SCAN requires 3 arguments",
+		"=BYROW(A1:C2)":                           "BYROW requires 2 arguments",
+		"=BYCOL(A1:C2,LAMBDA(a,b,a))":             "BYCOL requires a LAMBDA function with 1 parameters",
+		"=BYROW(#N/A,LAMBDA(r,r))":                "#N/A",
+		"=MAKEARRAY(2,2)":                         "MAKEARRAY requires 3 arguments",
+		"=MAKEARRAY(2,2,LAMBDA(r,r))":             "MAKEARRAY requires a LAMBDA function with 2 parameters",
+		"=MAKEARRAY(\"x\",2,LAMBDA(r,c,r))":       "strconv.ParseFloat: parsing \"x\": invalid syntax",
+		"=MAKEARRAY(0,2,LAMBDA(r,c,r))":           "MAKEARRAY requires rows and cols greater than 0",
+		"=MAKEARRAY(1048576,16384,LAMBDA(r,c,r))": "#NUM!",
+		"=LET(x,UNDEFINEDNAME,x)":                 "#NAME?",
+		"=LET(x,1,UNSUPPORTED(x))":                "not support UNSUPPORTED function",
+	}
+	for formula, expected := range mathCalcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, "", result, formula)
+	}
+	// Test LAMBDA functions with the custom formula functions
+	assert.NoError(t, f.RegisterCalcFunc("TWICE", func(args ...FormulaArg) FormulaArg {
+		num := args[0].ToNumber()
+		return FormulaArg{Type: ArgNumber, Number: num.Number * 2}
+	}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "LET(x,4,TWICE(x))"))
+	result, err := f.CalcCellValue("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", result)
+	// Test spill the results of the LAMBDA helper functions
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "A5", "MAP(A1:C1,LAMBDA(x,x*x))"))
+	for cell, expected := range map[string]string{"A5": "1", "B5": "4", "C5": "9"} {
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+}
+
+func TestCalcStructuredReferences(t *testing.T) {
+	f := NewFile()
+	for r, row := range [][]interface{}{
+		{"Region", "Price", "Qty", "Unit Price", "Amount"},
+		{"East", 2, 3, 1},
+		{"West", 4, 5, 2},
+		{"East", 6, 7, 3},
+		{"Total"},
+	} {
+		cell, _ := CoordinatesToCellName(1, r+1)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "E5", `{"table_name":"Sales"}`))
+	table, tableXML, err := f.getSheetTable("Sheet1", "Sales")
+	assert.NoError(t, err)
+	table.TotalsRowCount = 1
+	content, _ := xml.Marshal(table)
+	f.saveFileList(tableXML, content)
+	for _, cell := range []string{"E2", "E3", "E4"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, "[@Price]*[@Qty]"))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E5", "SUM([Amount])"))
+	f.NewSheet("Sheet2")
+	for cell, expected := range map[string]string{
+		"SUM(Sales[Price])":                          "12",
+		"SUM(sales[[Price]:[Qty]])":                  "27",
+		"SUMIF(Sales[Region],\"East\",Sales[Price])": "8",
+		"COUNTA(Sales[#All])":                        "18",
+		"COUNTA(Sales[#Headers])":                    "5",
+		"COUNTA(Sales[[#Headers],[#Data],[Region]])": "4",
+		"Sales[[#Totals],[Region]]":                  "Total",
+		"SUM(Sales[Unit Price])":                     "6",
+		"SUM(Sales[])":                               "33",
+		"\"Sales[Price]\"":                           "Sales[Price]",
+	} {
+		for _, sheet := range []string{"Sheet1", "Sheet2"} {
+			assert.NoError(t, f.SetCellFormula(sheet, "G1", cell))
+			result, err := f.CalcCellValue(sheet, "G1")
+			assert.NoError(t, err, cell)
+			assert.Equal(t, expected, result, cell)
+		}
+	}
+	for cell, expected := range map[string]string{"E2": "6", "E3": "20", "E4": "42"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, expected, result, cell)
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "Sales[@[Unit Price]]+Sales[[#This Row],[Price]]"))
+	result, err := f.CalcCellValue("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result)
+	// Test calculate all formulas with the structured references.
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 10))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", ""))
+	assert.NoError(t, f.CalcAll())
+	for cell, expected := range map[string]string{"E2": "30", "E3": "20", "E5": "92"} {
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, expected, result, cell)
+	}
+	// Test the structured references with not exist table, column, the
+	// totals row, or this row outside of the table.
+	for _, formula := range []string{"SUM(Table1[Price])", "SUM(Sales[Cost])", "[@Price]", "Sales[@Price]", "SUM(Sales[[#Nope],[Price]])"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "G1", formula))
+		result, err := f.CalcCellValue("Sheet1", "G1")
+		assert.EqualError(t, err, formulaErrorREF, formula)
+		assert.Equal(t, "", result, formula)
+	}
+	table.TotalsRowCount = 0
+	content, _ = xml.Marshal(table)
+	f.saveFileList(tableXML, content)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "Sales[#Totals]"))
+	_, err = f.CalcCellValue("Sheet1", "G1")
+	assert.EqualError(t, err, formulaErrorREF)
+}
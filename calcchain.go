@@ -19,7 +19,9 @@ import (
 )
 
 // calcChainReader provides a function to get the pointer to the structure
-// after deserialization of xl/calcChain.xml.
+// after deserialization of xl/calcChain.xml. The omitted sheet ID of the cell
+// in the calculation chain will be filled by the sheet ID of the previous
+// cell.
 func (f *File) calcChainReader() *xlsxCalcChain {
 	var err error
 
@@ -29,20 +31,116 @@ func (f *File) calcChainReader() *xlsxCalcChain {
 			Decode(f.CalcChain); err != nil && err != io.EOF {
 			log.Printf("xml decode error: %s", err)
 		}
+		for idx := range f.CalcChain.C {
+			if idx > 0 && f.CalcChain.C[idx].I == 0 {
+				f.CalcChain.C[idx].I = f.CalcChain.C[idx-1].I
+			}
+			f.CalcChain.C[idx].R = f.intern(f.CalcChain.C[idx].R)
+		}
 	}
 
 	return f.CalcChain
 }
 
 // calcChainWriter provides a function to save xl/calcChain.xml after
-// serialize structure.
+// serialize structure. The cells without formula in the loaded worksheets
+// and the cells of the deleted worksheets will be pruned from the
+// calculation chain before saving.
 func (f *File) calcChainWriter() {
+	if f.CalcChain != nil && f.CalcChain.C != nil {
+		f.pruneCalcChain()
+	}
 	if f.CalcChain != nil && f.CalcChain.C != nil {
 		output, _ := xml.Marshal(f.CalcChain)
 		f.saveFileList("xl/calcChain.xml", output)
 	}
 }
 
+// pruneCalcChain provides a function to remove the dangling cell references
+// on the calculation chain, which reference the cells without formula in
+// the loaded worksheets or the cells of the deleted worksheets.
+func (f *File) pruneCalcChain() {
+	sheets, formulas := map[int]string{}, map[int]map[string]bool{}
+	for _, sheet := range f.workbookReader().Sheets.Sheet {
+		sheets[sheet.SheetID] = f.sheetMap[trimSheetName(sheet.Name)]
+	}
+	f.CalcChain.C = xlsxCalcChainCollection(f.CalcChain.C).Filter(func(c xlsxCalcChainC) bool {
+		sheetXML, ok := sheets[c.I]
+		if !ok {
+			return false
+		}
+		cells, ok := formulas[c.I]
+		if !ok {
+			if ws, loaded := f.Sheet.Load(sheetXML); loaded && ws != nil {
+				cells = getFormulaCells(ws.(*xlsxWorksheet))
+			}
+			formulas[c.I] = cells
+		}
+		return cells == nil || cells[c.R]
+	})
+	if len(f.CalcChain.C) == 0 {
+		f.deleteCalcChainPart()
+	}
+}
+
+// getFormulaCells provides a function to get the references of the cells
+// with formula in the worksheet.
+func getFormulaCells(ws *xlsxWorksheet) map[string]bool {
+	cells := map[string]bool{}
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil {
+				cells[c.R] = true
+			}
+		}
+	}
+	return cells
+}
+
+// RebuildCalcChain provides a function to regenerate the calculation chain
+// of the workbook by the cells with formula in all worksheets, the
+// spreadsheet application will recalculate the formulas in the order of the
+// worksheets and cells. The calculation chain will be removed if there are
+// no formulas in the workbook. For example:
+//
+//    err := f.RebuildCalcChain()
+//
+func (f *File) RebuildCalcChain() error {
+	calc := &xlsxCalcChain{}
+	for _, sheet := range f.GetSheets() {
+		if sheet.Type != "worksheet" {
+			continue
+		}
+		ws, err := f.workSheetReader(sheet.Name)
+		if err != nil {
+			return err
+		}
+		for _, row := range ws.SheetData.Row {
+			for _, c := range row.C {
+				if c.F != nil {
+					calc.C = append(calc.C, xlsxCalcChainC{R: c.R, I: sheet.SheetID, A: c.F.T == STCellFormulaTypeArray})
+				}
+			}
+		}
+	}
+	if len(calc.C) == 0 {
+		f.deleteCalcChainPart()
+		return nil
+	}
+	f.CalcChain = calc
+	f.addContentTypeOverride("/xl/calcChain.xml", ContentTypeSpreadSheetMLCalcChain)
+	relsPath := f.getWorkbookRelsPath()
+	if rels := f.relsReader(relsPath); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipCalcChain {
+				return nil
+			}
+		}
+	}
+	f.addRels(relsPath, SourceRelationshipCalcChain, "calcChain.xml", "")
+	return nil
+}
+
 // deleteCalcChain provides a function to remove cell reference on the
 // calculation chain.
 func (f *File) deleteCalcChain(index int, axis string) {
@@ -53,14 +151,32 @@ func (f *File) deleteCalcChain(index int, axis string) {
 		})
 	}
 	if len(calc.C) == 0 {
-		f.CalcChain = nil
-		f.Pkg.Delete("xl/calcChain.xml")
-		content := f.contentTypesReader()
-		content.Lock()
-		defer content.Unlock()
-		for k, v := range content.Overrides {
-			if v.PartName == "/xl/calcChain.xml" {
-				content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
+		f.deleteCalcChainPart()
+	}
+}
+
+// deleteCalcChainPart provides a function to remove the calculation chain
+// part, the content type and the relationship of the calculation chain in
+// the workbook.
+func (f *File) deleteCalcChainPart() {
+	f.CalcChain = nil
+	f.Pkg.Delete("xl/calcChain.xml")
+	content := f.contentTypesReader()
+	content.Lock()
+	for k, v := range content.Overrides {
+		if v.PartName == "/xl/calcChain.xml" {
+			content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
+			break
+		}
+	}
+	content.Unlock()
+	if rels := f.relsReader(f.getWorkbookRelsPath()); rels != nil {
+		rels.Lock()
+		defer rels.Unlock()
+		for k, v := range rels.Relationships {
+			if v.Type == SourceRelationshipCalcChain {
+				rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
+				break
 			}
 		}
 	}
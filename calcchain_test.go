@@ -1,6 +1,11 @@
 package excelize
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestCalcChainReader(t *testing.T) {
 	f := NewFile()
@@ -17,3 +22,74 @@ func TestDeleteCalcChain(t *testing.T) {
 	})
 	f.deleteCalcChain(1, "A1")
 }
+
+func TestPruneCalcChain(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "=A1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "=Sheet1!A1"))
+	// Test fill the omitted sheet ID by the previous cell.
+	f.CalcChain = nil
+	f.Pkg.Store("xl/calcChain.xml", []byte(`<calcChain xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><c r="A1" i="1"/><c r="A2"/><c r="A3"/><c r="A1" i="2"/><c r="A1" i="3"/></calcChain>`))
+	assert.Equal(t, []xlsxCalcChainC{{R: "A1", I: 1}, {R: "A2", I: 1}, {R: "A3", I: 1}, {R: "A1", I: 2}, {R: "A1", I: 3}}, f.calcChainReader().C)
+	// Test remove the cell references without formula or in the deleted
+	// rows on the calculation chain.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].F = nil
+	assert.NoError(t, f.RemoveRow("Sheet2", 1))
+	f.calcChainWriter()
+	assert.Equal(t, []xlsxCalcChainC{{R: "A2", I: 1}}, f.CalcChain.C)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPruneCalcChain.xlsx")))
+
+	// Test remove the calculation chain part when all cell references are
+	// pruned.
+	f = NewFile()
+	f.CalcChain = &xlsxCalcChain{C: []xlsxCalcChainC{{R: "A1", I: 1}}}
+	f.addContentTypeOverride("/xl/calcChain.xml", ContentTypeSpreadSheetMLCalcChain)
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipCalcChain, "calcChain.xml", "")
+	_, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	f.calcChainWriter()
+	assert.Nil(t, f.CalcChain)
+	for _, rel := range f.relsReader(f.getWorkbookRelsPath()).Relationships {
+		assert.NotEqual(t, SourceRelationshipCalcChain, rel.Type)
+	}
+	for _, override := range f.contentTypesReader().Overrides {
+		assert.NotEqual(t, "/xl/calcChain.xml", override.PartName)
+	}
+}
+
+func TestRebuildCalcChain(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$A$1","values":"Sheet1!$A$1"}]}`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "=1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "=Sheet1!B2", FormulaOpts{Type: stringPtr(STCellFormulaTypeArray), Ref: stringPtr("A1")}))
+	assert.NoError(t, f.RebuildCalcChain())
+	assert.Equal(t, []xlsxCalcChainC{{R: "B2", I: 1}, {R: "A1", I: 2, A: true}}, f.CalcChain.C)
+	// Test rebuild the calculation chain without duplicate relationship.
+	assert.NoError(t, f.RebuildCalcChain())
+	var count int
+	for _, rel := range f.relsReader(f.getWorkbookRelsPath()).Relationships {
+		if rel.Type == SourceRelationshipCalcChain {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRebuildCalcChain.xlsx")))
+
+	// Test rebuild the calculation chain without formulas.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", ""))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", ""))
+	f.CalcChain = &xlsxCalcChain{C: []xlsxCalcChainC{{R: "B2", I: 1}}}
+	assert.NoError(t, f.RebuildCalcChain())
+	assert.Nil(t, f.CalcChain)
+
+	// Test rebuild the calculation chain with invalid worksheet.
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RebuildCalcChain(), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
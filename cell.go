@@ -15,9 +15,11 @@ import (
 	"encoding/xml"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf16"
 )
 
 const (
@@ -31,13 +33,75 @@ const (
 	STCellFormulaTypeShared = "shared"
 )
 
+// CellType is the type of cell value type.
+type CellType byte
+
+// Cell value types enumeration.
+const (
+	CellTypeUnset CellType = iota
+	CellTypeBool
+	CellTypeDate
+	CellTypeError
+	CellTypeFormula
+	CellTypeInlineString
+	CellTypeNumber
+	CellTypeSharedString
+)
+
+// cellTypes defined the mapping of the cell data type attribute value and the
+// cell value types.
+var cellTypes = map[string]CellType{
+	"b":         CellTypeBool,
+	"d":         CellTypeDate,
+	"n":         CellTypeNumber,
+	"e":         CellTypeError,
+	"s":         CellTypeSharedString,
+	"str":       CellTypeFormula,
+	"inlineStr": CellTypeInlineString,
+}
+
+// getCellType provides a function to get the value type of the cell, a cell
+// without data type attribute will be a number cell if it has a value.
+func (c *xlsxC) getCellType() CellType {
+	if c.T == "" {
+		if c.V == "" {
+			return CellTypeUnset
+		}
+		return CellTypeNumber
+	}
+	return cellTypes[c.T]
+}
+
+// getRawValueFrom provides a function to get the unformatted value of the
+// cell, the shared string index and inline rich text will be resolved to the
+// plain text.
+func (c *xlsxC) getRawValueFrom(d *xlsxSST) string {
+	switch c.T {
+	case "s":
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(d.SI) {
+			return d.SI[idx].String()
+		}
+	case "inlineStr":
+		if c.IS != nil {
+			return c.IS.String()
+		}
+	}
+	return c.V
+}
+
 // GetCellValue provides a function to get formatted value from cell by given
 // worksheet name and axis in spreadsheet file. If it is possible to apply a
 // format to the cell value, it will do so, if not then an error will be
-// returned, along with the raw value of the cell.
-func (f *File) GetCellValue(sheet, axis string) (string, error) {
+// returned, along with the raw value of the cell. The culture used to format
+// the cell value could be specified by the options, which overrides the
+// culture of the workbook. For example, get the cell value formatted in the
+// de-DE culture:
+//
+//    value, err := f.GetCellValue("Sheet1", "A1", excelize.Options{CultureInfo: excelize.CultureNameDeDE})
+//
+func (f *File) GetCellValue(sheet, axis string, opts ...Options) (string, error) {
 	return f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
-		val, err := c.getValueFrom(f, f.sharedStringsReader())
+		val, err := c.getValueFrom(f, f.sharedStringsReader(), opts...)
 		return val, true, err
 	})
 }
@@ -64,10 +128,18 @@ func (f *File) GetCellValue(sheet, axis string) (string, error) {
 //    time.Duration
 //    time.Time
 //    bool
+//    Percent
+//    Money
 //    nil
 //
 // Note that default date format is m/d/yy h:mm of time.Time type value. You can
-// set numbers format by SetCellStyle() method.
+// set numbers format by SetCellStyle() method. The Percent and Money type
+// values will be stored as numbers with the percentage and currency number
+// formats if the cell has no style, for example:
+//
+//    err := f.SetCellValue("Sheet1", "A1", excelize.Percent(0.15))
+//    err := f.SetCellValue("Sheet1", "A2", excelize.Money{Amount: 1234.5, Currency: "USD"})
+//
 func (f *File) SetCellValue(sheet, axis string, value interface{}) error {
 	var err error
 	switch v := value.(type) {
@@ -78,6 +150,12 @@ func (f *File) SetCellValue(sheet, axis string, value interface{}) error {
 	case float64:
 		err = f.SetCellFloat(sheet, axis, v, -1, 64)
 	case string:
+		if f.parseStr {
+			var ok bool
+			if ok, err = f.setCellParsedStr(sheet, axis, v); ok || err != nil {
+				return err
+			}
+		}
 		err = f.SetCellStr(sheet, axis, v)
 	case []byte:
 		err = f.SetCellStr(sheet, axis, string(v))
@@ -92,6 +170,14 @@ func (f *File) SetCellValue(sheet, axis string, value interface{}) error {
 		err = f.setCellTimeFunc(sheet, axis, v)
 	case bool:
 		err = f.SetCellBool(sheet, axis, v)
+	case Percent:
+		err = f.setCellNumFmtValue(sheet, axis, float64(v), "0.00%")
+	case Money:
+		var numFmt string
+		if numFmt, err = v.numFmt(); err != nil {
+			return err
+		}
+		err = f.setCellNumFmtValue(sheet, axis, v.Amount, numFmt)
 	case nil:
 		err = f.SetCellDefault(sheet, axis, "")
 	default:
@@ -144,6 +230,7 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	ws.Unlock()
 
 	var isNum bool
+	cellData.IS = nil
 	cellData.T, cellData.V, isNum, err = setCellTime(value)
 	if err != nil {
 		return err
@@ -195,6 +282,7 @@ func (f *File) SetCellInt(sheet, axis string, value int) error {
 	ws.Lock()
 	defer ws.Unlock()
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
+	cellData.IS = nil
 	cellData.T, cellData.V = setCellInt(value)
 	return err
 }
@@ -220,6 +308,7 @@ func (f *File) SetCellBool(sheet, axis string, value bool) error {
 	ws.Lock()
 	defer ws.Unlock()
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
+	cellData.IS = nil
 	cellData.T, cellData.V = setCellBool(value)
 	return err
 }
@@ -257,6 +346,7 @@ func (f *File) SetCellFloat(sheet, axis string, value float64, prec, bitSize int
 	ws.Lock()
 	defer ws.Unlock()
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
+	cellData.IS = nil
 	cellData.T, cellData.V = setCellFloat(value, prec, bitSize)
 	return err
 }
@@ -269,7 +359,8 @@ func setCellFloat(value float64, prec, bitSize int) (t string, v string) {
 }
 
 // SetCellStr provides a function to set string type value of a cell. Total
-// number of characters that a cell can contain 32767 characters.
+// number of characters that a cell can contain 32767 characters, the error
+// ErrCellCharsLength will be returned if the value exceeds the limit.
 func (f *File) SetCellStr(sheet, axis, value string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -282,15 +373,28 @@ func (f *File) SetCellStr(sheet, axis, value string) error {
 	ws.Lock()
 	defer ws.Unlock()
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = f.setCellString(value)
+	if f.inlineStr || cellData.T == "inlineStr" {
+		t, is, err := setCellInlineStr(value)
+		if err != nil {
+			return err
+		}
+		cellData.T, cellData.V, cellData.IS = t, "", is
+		return err
+	}
+	t, v, err := f.setCellString(value)
+	if err != nil {
+		return err
+	}
+	cellData.T, cellData.V, cellData.IS = t, v, nil
 	return err
 }
 
 // setCellString provides a function to set string type to shared string
 // table.
-func (f *File) setCellString(value string) (t string, v string) {
-	if len(value) > TotalCellChars {
-		value = value[0:TotalCellChars]
+func (f *File) setCellString(value string) (t string, v string, err error) {
+	if len(utf16.Encode([]rune(value))) > TotalCellChars {
+		err = ErrCellCharsLength
+		return
 	}
 	t = "s"
 	v = strconv.Itoa(f.setSharedString(value))
@@ -322,10 +426,30 @@ func (f *File) setSharedString(val string) int {
 	return sst.UniqueCount - 1
 }
 
+// setCellInlineStr provides a function to set string type to cell as inline
+// string.
+func setCellInlineStr(value string) (t string, is *xlsxSI, err error) {
+	if len(utf16.Encode([]rune(value))) > TotalCellChars {
+		err = ErrCellCharsLength
+		return
+	}
+	val := xlsxT{Val: bstrMarshal(value)}
+	// Leading and ending space(s) character detection.
+	if len(value) > 0 && (value[0] == 32 || value[len(value)-1] == 32) {
+		val.Space = xml.Attr{
+			Name:  xml.Name{Space: NameSpaceXML, Local: "space"},
+			Value: "preserve",
+		}
+	}
+	t, is = "inlineStr", &xlsxSI{T: &val}
+	return
+}
+
 // setCellStr provides a function to set string type to cell.
-func setCellStr(value string) (t string, v string, ns xml.Attr) {
-	if len(value) > TotalCellChars {
-		value = value[0:TotalCellChars]
+func setCellStr(value string) (t string, v string, ns xml.Attr, err error) {
+	if len(utf16.Encode([]rune(value))) > TotalCellChars {
+		err = ErrCellCharsLength
+		return
 	}
 	// Leading and ending space(s) character detection.
 	if len(value) > 0 && (value[0] == 32 || value[len(value)-1] == 32) {
@@ -353,6 +477,7 @@ func (f *File) SetCellDefault(sheet, axis, value string) error {
 	ws.Lock()
 	defer ws.Unlock()
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
+	cellData.IS = nil
 	cellData.T, cellData.V = setCellDefault(value)
 	return err
 }
@@ -380,12 +505,23 @@ func (f *File) GetCellFormula(sheet, axis string) (string, error) {
 
 // FormulaOpts can be passed to SetCellFormula to use other formula types.
 type FormulaOpts struct {
-	Type *string // Formula type
-	Ref  *string // Shared formula ref
+	Type    *string // Formula type
+	Ref     *string // Shared formula ref
+	RefMode *string // Reference mode of the formula, A1 or R1C1
 }
 
 // SetCellFormula provides a function to set cell formula by given string and
-// worksheet name.
+// worksheet name. The formula in the R1C1 reference style could be set with
+// the RefMode option, the references will be converted to the A1 reference
+// style relative to the cell, since the formulas are always stored in the A1
+// reference style in the workbook. For example, set the formula of the cell
+// B3 to sum the two cells above it:
+//
+//    refMode := "R1C1"
+//    err := f.SetCellFormula("Sheet1", "B3", "SUM(R[-2]C:R[-1]C)", excelize.FormulaOpts{RefMode: &refMode})
+//
+// Use the SetCalcProps function to set the reference mode of the workbook
+// which the spreadsheet applications display the formulas in.
 func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -401,6 +537,20 @@ func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts)
 		return err
 	}
 
+	for _, o := range opts {
+		if o.RefMode == nil {
+			continue
+		}
+		if inStrSlice(supportedRefMode, *o.RefMode) == -1 {
+			return newInvalidOptionalValue("RefMode", *o.RefMode, supportedRefMode)
+		}
+		if *o.RefMode == "R1C1" {
+			if formula, err = R1C1ToA1(formula, axis); err != nil {
+				return err
+			}
+		}
+	}
+
 	if cellData.F != nil {
 		cellData.F.Content = formula
 	} else {
@@ -420,6 +570,48 @@ func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts)
 	return err
 }
 
+// SetCellDynamicArrayFormula provides a function to set the dynamic array
+// formula on the cell is taken according to the given worksheet name and
+// cell coordinates. The formula will be calculated and the results will be
+// spilled into the neighboring cells, and the cell metadata of the dynamic
+// array will be created, so that the spreadsheet applications treat the
+// formula as a dynamic array formula rather than the implicit intersection.
+// The required prefixes of the dynamic array functions will be added, such
+// as the FILTER, SORT, UNIQUE and SEQUENCE. For example, get the unique
+// values of the range Sheet1!A1:A10 and spill the results from the cell C1:
+//
+//    err := f.SetCellDynamicArrayFormula("Sheet1", "C1", "=SORT(UNIQUE(A1:A10))")
+//
+// The #SPILL! error will be set as the cached value of the cell if the spill
+// range of the results isn't blank. The spill ranges of the dynamic array
+// formulas will be recalculated by the CalcAll function.
+func (f *File) SetCellDynamicArrayFormula(sheet, axis, formula string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cellData, col, row, err := f.prepareCell(ws, sheet, axis)
+	if err != nil {
+		return err
+	}
+	if spill, ok := getSpillRange(cellData); ok {
+		clearSpillRange(ws, spill, col, row)
+	}
+	cell, _ := CoordinatesToCellName(col, row)
+	if formula == "" {
+		cellData.F, cellData.CM = nil, 0
+		f.deleteCalcChain(f.getSheetID(sheet), cell)
+		return err
+	}
+	cm, err := f.getDynamicArrayCellMetadata()
+	if err != nil {
+		return err
+	}
+	cellData.F = &xlsxF{Content: prefixDynamicArrayFormula(formula), T: STCellFormulaTypeArray, Ref: cell}
+	cellData.CM = cm
+	return f.spillDynamicArray(sheet, cell)
+}
+
 // GetCellHyperLink provides a function to get cell hyperlink by given
 // worksheet name and axis. Boolean type value link will be ture if the cell
 // has a hyperlink and the target is the address of the hyperlink. Otherwise,
@@ -535,7 +727,8 @@ func (f *File) SetCellHyperLink(sheet, axis, link, linkType string, opts ...Hype
 }
 
 // GetCellRichText provides a function to get rich text of cell by given
-// worksheet.
+// worksheet. The cell with the plain shared string or inline string will be
+// returned as a single rich text run without font settings.
 func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err error) {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -545,46 +738,144 @@ func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err erro
 	if err != nil {
 		return
 	}
-	siIdx, err := strconv.Atoi(cellData.V)
-	if nil != err {
+	return f.getCellRichTextRuns(cellData, f.sharedStringsReader(), false)
+}
+
+// getCellRichTextRuns provides a function to get the rich text runs of the
+// cell by given cell and shared strings table. The cell with the plain shared
+// string or inline string will be returned as a single rich text run without
+// font settings, and so do the other cells with value when the plain is true,
+// the text of the run is the cell value with number format applied, and the
+// empty strings will be skipped in this case.
+func (f *File) getCellRichTextRuns(c *xlsxC, sst *xlsxSST, plain bool) (runs []RichTextRun, err error) {
+	var si xlsxSI
+	switch c.T {
+	case "inlineStr":
+		if c.IS == nil {
+			return
+		}
+		si = *c.IS
+	case "s":
+		var siIdx int
+		if siIdx, err = strconv.Atoi(c.V); err != nil || len(sst.SI) <= siIdx || siIdx < 0 {
+			return
+		}
+		si = sst.SI[siIdx]
+	default:
+		if plain {
+			if val, _ := c.getValueFrom(f, sst); val != "" {
+				runs = []RichTextRun{{Text: val}}
+			}
+		}
 		return
 	}
-	sst := f.sharedStringsReader()
-	if len(sst.SI) <= siIdx || siIdx < 0 {
-		return
+	if len(si.R) == 0 && si.T != nil {
+		if plain && si.T.Val == "" {
+			return
+		}
+		return []RichTextRun{{Text: si.T.Val}}, err
 	}
-	si := sst.SI[siIdx]
 	for _, v := range si.R {
-		run := RichTextRun{
-			Text: v.T.Val,
+		run := RichTextRun{}
+		if v.T != nil {
+			run.Text = v.T.Val
 		}
 		if nil != v.RPr {
-			font := Font{Underline: "none"}
-			font.Bold = v.RPr.B != nil
-			font.Italic = v.RPr.I != nil
-			if v.RPr.U != nil {
-				font.Underline = "single"
-				if v.RPr.U.Val != nil {
-					font.Underline = *v.RPr.U.Val
-				}
-			}
-			if v.RPr.RFont != nil && v.RPr.RFont.Val != nil {
-				font.Family = *v.RPr.RFont.Val
-			}
-			if v.RPr.Sz != nil && v.RPr.Sz.Val != nil {
-				font.Size = *v.RPr.Sz.Val
-			}
-			font.Strike = v.RPr.Strike != nil
-			if nil != v.RPr.Color {
-				font.Color = strings.TrimPrefix(v.RPr.Color.RGB, "FF")
-			}
-			run.Font = &font
+			run.Font = newRichTextFont(v.RPr)
 		}
 		runs = append(runs, run)
 	}
 	return
 }
 
+// newRichTextFont provides a function to create the font settings of the
+// rich text run by given run properties.
+func newRichTextFont(rPr *xlsxRPr) *Font {
+	font := Font{Underline: "none"}
+	font.Bold = rPr.B != nil
+	font.Italic = rPr.I != nil
+	if rPr.U != nil {
+		font.Underline = "single"
+		if rPr.U.Val != nil {
+			font.Underline = *rPr.U.Val
+		}
+	}
+	if rPr.RFont != nil && rPr.RFont.Val != nil {
+		font.Family = *rPr.RFont.Val
+	}
+	if rPr.Sz != nil && rPr.Sz.Val != nil {
+		font.Size = *rPr.Sz.Val
+	}
+	font.Strike = rPr.Strike != nil
+	if nil != rPr.Color {
+		font.Color = strings.TrimPrefix(rPr.Color.RGB, "FF")
+	}
+	if rPr.VertAlign != nil && rPr.VertAlign.Val != nil {
+		font.VertAlign = *rPr.VertAlign.Val
+	}
+	if rPr.Charset != nil && rPr.Charset.Val != nil {
+		font.Charset = intPtr(*rPr.Charset.Val)
+	}
+	font.Condense = rPr.Condense != nil
+	font.Extend = rPr.Extend != nil
+	font.Outline = rPr.Outline != nil
+	font.Shadow = rPr.Shadow != nil
+	if rPr.Scheme != nil && rPr.Scheme.Val != nil {
+		font.Scheme = *rPr.Scheme.Val
+	}
+	return &font
+}
+
+// newRpr provides a function to create the run properties of the rich text
+// run by given font settings.
+func newRpr(fnt *Font) *xlsxRPr {
+	rpr := xlsxRPr{}
+	trueVal := ""
+	if fnt.Bold {
+		rpr.B = &trueVal
+	}
+	if fnt.Italic {
+		rpr.I = &trueVal
+	}
+	if fnt.Strike {
+		rpr.Strike = &trueVal
+	}
+	if fnt.Outline {
+		rpr.Outline = &trueVal
+	}
+	if fnt.Shadow {
+		rpr.Shadow = &trueVal
+	}
+	if fnt.Condense {
+		rpr.Condense = &trueVal
+	}
+	if fnt.Extend {
+		rpr.Extend = &trueVal
+	}
+	if fnt.Underline != "" {
+		rpr.U = &attrValString{Val: stringPtr(fnt.Underline)}
+	}
+	if fnt.Family != "" {
+		rpr.RFont = &attrValString{Val: stringPtr(fnt.Family)}
+	}
+	if fnt.Charset != nil {
+		rpr.Charset = &attrValInt{Val: intPtr(*fnt.Charset)}
+	}
+	if fnt.Size > 0.0 {
+		rpr.Sz = &attrValFloat{Val: float64Ptr(fnt.Size)}
+	}
+	if fnt.Color != "" {
+		rpr.Color = &xlsxColor{RGB: getPaletteColor(fnt.Color)}
+	}
+	if fnt.VertAlign != "" {
+		rpr.VertAlign = &attrValString{Val: stringPtr(fnt.VertAlign)}
+	}
+	if fnt.Scheme != "" {
+		rpr.Scheme = &attrValString{Val: stringPtr(fnt.Scheme)}
+	}
+	return &rpr
+}
+
 // SetCellRichText provides a function to set cell with rich text by given
 // worksheet. For example, set rich text on the A1 cell of the worksheet named
 // Sheet1:
@@ -698,6 +989,13 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	if err != nil {
 		return err
 	}
+	var textLen int
+	for _, textRun := range runs {
+		textLen += len(utf16.Encode([]rune(textRun.Text)))
+	}
+	if textLen > TotalCellChars {
+		return ErrCellCharsLength
+	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
 	si := xlsxSI{}
 	sst := f.sharedStringsReader()
@@ -707,36 +1005,17 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		if strings.ContainsAny(textRun.Text, "\r\n ") {
 			run.T.Space = xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}
 		}
-		fnt := textRun.Font
-		if fnt != nil {
-			rpr := xlsxRPr{}
-			trueVal := ""
-			if fnt.Bold {
-				rpr.B = &trueVal
-			}
-			if fnt.Italic {
-				rpr.I = &trueVal
-			}
-			if fnt.Strike {
-				rpr.Strike = &trueVal
-			}
-			if fnt.Underline != "" {
-				rpr.U = &attrValString{Val: &fnt.Underline}
-			}
-			if fnt.Family != "" {
-				rpr.RFont = &attrValString{Val: &fnt.Family}
-			}
-			if fnt.Size > 0.0 {
-				rpr.Sz = &attrValFloat{Val: &fnt.Size}
-			}
-			if fnt.Color != "" {
-				rpr.Color = &xlsxColor{RGB: getPaletteColor(fnt.Color)}
-			}
-			run.RPr = &rpr
+		if textRun.Font != nil {
+			run.RPr = newRpr(textRun.Font)
 		}
 		textRuns = append(textRuns, run)
 	}
 	si.R = textRuns
+	if f.inlineStr || cellData.T == "inlineStr" {
+		cellData.T, cellData.V, cellData.IS = "inlineStr", "", &si
+		return err
+	}
+	cellData.IS = nil
 	for idx, strItem := range sst.SI {
 		if reflect.DeepEqual(strItem, si) {
 			cellData.T, cellData.V = "s", strconv.Itoa(idx)
@@ -750,35 +1029,147 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	return err
 }
 
+// UpdateCellRichTextRun provides a function to update a single run of the
+// rich text of cell by given worksheet, cell name, zero-based run index and
+// the new run, the other runs of the rich text will be kept. For example,
+// change the text and font of the second run of the rich text on the A1 cell
+// of the worksheet named Sheet1:
+//
+//    err := f.UpdateCellRichTextRun("Sheet1", "A1", 1, excelize.RichTextRun{
+//        Text: "italic",
+//        Font: &excelize.Font{Italic: true, VertAlign: "superscript"},
+//    })
+//
+func (f *File) UpdateCellRichTextRun(sheet, cell string, idx int, run RichTextRun) error {
+	runs, err := f.GetCellRichText(sheet, cell)
+	if err != nil {
+		return err
+	}
+	if idx < 0 || idx >= len(runs) {
+		return ErrRichTextRunIdx
+	}
+	runs[idx] = run
+	return f.SetCellRichText(sheet, cell, runs)
+}
+
 // SetSheetRow writes an array to row by given worksheet name, starting
-// coordinate and a pointer to array type 'slice'. For example, writes an
-// array to row 6 start with the cell B6 on Sheet1:
+// coordinate and a pointer to array type 'slice'. The optional style IDs are
+// parallel to the array, the style ID at the same index will be applied to
+// each cell. The value of the array could be the Cell type to write the
+// value, formula and style of the cell, the non-zero style ID of the Cell
+// overrides the parallel style ID. For example, writes an array to row 6
+// start with the cell B6 on Sheet1:
 //
 //     err := f.SetSheetRow("Sheet1", "B6", &[]interface{}{"1", nil, 2})
 //
-func (f *File) SetSheetRow(sheet, axis string, slice interface{}) error {
+// Writes a row with the styles and a formula cell to row 7 start with the
+// cell B7 on Sheet1:
+//
+//     err := f.SetSheetRow("Sheet1", "B7", &[]interface{}{
+//         "Total", 2, excelize.Cell{Formula: "SUM(C1:C6)"},
+//     }, headerStyle, numberStyle, numberStyle)
+//
+// The 'slice' could also be a pointer to a struct, the exported fields of the
+// struct will be written in order, and the number format of the cell could be
+// specified by the 'numFmt' tag of the field, which will be applied if the
+// cell has no style. For example, writes a struct to row 8 start with the
+// cell B8 on Sheet1:
+//
+//     type Order struct {
+//         Product  string
+//         Discount float64 `numFmt:"0.00%"`
+//         Price    float64 `numFmt:"#,##0.00"`
+//     }
+//     err := f.SetSheetRow("Sheet1", "B8", &Order{"Apple", 0.15, 1234.5})
+//
+func (f *File) SetSheetRow(sheet, axis string, slice interface{}, styleIDs ...int) error {
+	return f.setSheetCells(sheet, axis, slice, styleIDs, false)
+}
+
+// SetSheetCol writes an array to column by given worksheet name, starting
+// coordinate and a pointer to array type 'slice'. The optional style IDs and
+// the Cell type values work like the SetSheetRow function. For example,
+// writes an array to column B start with the cell B6 on Sheet1:
+//
+//     err := f.SetSheetCol("Sheet1", "B6", &[]interface{}{"1", nil, 2})
+//
+func (f *File) SetSheetCol(sheet, axis string, slice interface{}, styleIDs ...int) error {
+	return f.setSheetCells(sheet, axis, slice, styleIDs, true)
+}
+
+// setSheetCells provides a function to write an array to row or column by
+// given worksheet name, starting coordinate, a pointer to array type
+// 'slice', the parallel style IDs and the direction of the array.
+func (f *File) setSheetCells(sheet, axis string, slice interface{}, styleIDs []int, vertical bool) error {
 	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return err
 	}
 
-	// Make sure 'slice' is a Ptr to Slice
+	// Make sure 'slice' is a Ptr to Slice or Struct
 	v := reflect.ValueOf(slice)
-	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+	if v.Kind() != reflect.Ptr || (v.Elem().Kind() != reflect.Slice && v.Elem().Kind() != reflect.Struct) {
 		return ErrParameterInvalid
 	}
 	v = v.Elem()
+	if v.Kind() == reflect.Struct {
+		v = getStructCellValues(v)
+	}
 
 	for i := 0; i < v.Len(); i++ {
-		cell, err := CoordinatesToCellName(col+i, row)
+		var cell string
+		if vertical {
+			cell, err = CoordinatesToCellName(col, row+i)
+		} else {
+			cell, err = CoordinatesToCellName(col+i, row)
+		}
 		// Error should never happens here. But keep checking to early detect regresions
 		// if it will be introduced in future.
 		if err != nil {
 			return err
 		}
-		if err := f.SetCellValue(sheet, cell, v.Index(i).Interface()); err != nil {
+		var formula string
+		val, styleID, hasStyle := v.Index(i).Interface(), 0, i < len(styleIDs)
+		if hasStyle {
+			styleID = styleIDs[i]
+		}
+		if c, ok := val.(*Cell); ok && c != nil {
+			val = *c
+		}
+		if c, ok := val.(Cell); ok {
+			val, formula = c.Value, c.Formula
+			if c.StyleID != 0 {
+				styleID, hasStyle = c.StyleID, true
+			}
+		}
+		if c, ok := val.(numFmtValue); ok && !hasStyle {
+			s, err := f.GetCellStyle(sheet, cell)
+			if err != nil {
+				return err
+			}
+			if s == 0 {
+				if styleID, err = f.newNumFmtStyle(c.numFmt); err != nil {
+					return err
+				}
+				hasStyle = true
+			}
+		}
+		if c, ok := val.(numFmtValue); ok {
+			val = c.value
+		}
+		if err := f.SetCellValue(sheet, cell, val); err != nil {
 			return err
 		}
+		if formula != "" {
+			if err := f.SetCellFormula(sheet, cell, formula); err != nil {
+				return err
+			}
+		}
+		if hasStyle {
+			if err := f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
 	}
 	return err
 }
@@ -854,14 +1245,15 @@ func (f *File) getCellStringFunc(sheet, axis string, fn func(x *xlsxWorksheet, c
 
 // formattedValue provides a function to returns a value after formatted. If
 // it is possible to apply a format to the cell value, it will do so, if not
-// then an error will be returned, along with the raw value of the cell.
-func (f *File) formattedValue(s int, v string) string {
+// then an error will be returned, along with the raw value of the cell. The
+// culture specific settings will be used if the culture has been specified
+// by the options or the workbook.
+func (f *File) formattedValue(s int, v string, opts ...Options) string {
 	if s == 0 {
 		return v
 	}
 	styleSheet := f.stylesReader()
-
-	if s >= len(styleSheet.CellXfs.Xf) {
+	if styleSheet.CellXfs == nil || s >= len(styleSheet.CellXfs.Xf) {
 		return v
 	}
 	var numFmtID int
@@ -869,6 +1261,10 @@ func (f *File) formattedValue(s int, v string) string {
 		numFmtID = *styleSheet.CellXfs.Xf[s].NumFmtID
 	}
 
+	culture := f.getCultureInfo(opts...)
+	if fn, ok := cultureNumFmtFunc[numFmtID]; ok && culture != nil {
+		return fn(v, builtInNumFmt[numFmtID], culture)
+	}
 	ok := builtInNumFmtFunc[numFmtID]
 	if ok != nil {
 		return ok(v, builtInNumFmt[numFmtID])
@@ -879,8 +1275,12 @@ func (f *File) formattedValue(s int, v string) string {
 	for _, xlsxFmt := range styleSheet.NumFmts.NumFmt {
 		if xlsxFmt.NumFmtID == numFmtID {
 			format := strings.ToLower(xlsxFmt.FormatCode)
-			if strings.Contains(format, "y") || strings.Contains(format, "m") || strings.Contains(strings.Replace(format, "red", "", -1), "d") || strings.Contains(format, "h") {
-				return parseTime(v, format)
+			codes := numFmtQuotedTextRegexp.ReplaceAllString(format, "")
+			if strings.Contains(codes, "y") || strings.Contains(codes, "m") || strings.Contains(strings.Replace(codes, "red", "", -1), "d") || strings.Contains(codes, "h") {
+				return formatTime(v, format, culture)
+			}
+			if culture != nil {
+				return formatNumberWithCulture(v, xlsxFmt.FormatCode, culture)
 			}
 			return v
 		}
@@ -947,14 +1347,8 @@ func cellInRef(cell, ref []int) bool {
 
 // isOverlap find if the given two rectangles overlap or not.
 func isOverlap(rect1, rect2 []int) bool {
-	return cellInRef([]int{rect1[0], rect1[1]}, rect2) ||
-		cellInRef([]int{rect1[2], rect1[1]}, rect2) ||
-		cellInRef([]int{rect1[0], rect1[3]}, rect2) ||
-		cellInRef([]int{rect1[2], rect1[3]}, rect2) ||
-		cellInRef([]int{rect2[0], rect2[1]}, rect1) ||
-		cellInRef([]int{rect2[2], rect2[1]}, rect1) ||
-		cellInRef([]int{rect2[0], rect2[3]}, rect1) ||
-		cellInRef([]int{rect2[2], rect2[3]}, rect1)
+	return rect1[0] <= rect2[2] && rect2[0] <= rect1[2] &&
+		rect1[1] <= rect2[3] && rect2[1] <= rect1[3]
 }
 
 // getSharedForumula find a cell contains the same formula as another cell,
@@ -975,3 +1369,233 @@ func getSharedForumula(ws *xlsxWorksheet, si string) string {
 	}
 	return ""
 }
+
+// DateLayout directly maps the layout to parse the date and time strings by
+// the SetCellValue function when the ParseStrings was enabled. Layout
+// specifies the layout of the string in the Go time layout, and NumFmt
+// specifies the number format of the cell, for example:
+//
+//    excelize.DateLayout{Layout: "02.01.2006", NumFmt: "dd.mm.yyyy"}
+//
+type DateLayout struct {
+	Layout string
+	NumFmt string
+}
+
+var (
+	// defaultDateLayouts defined the default date layouts to parse the date
+	// and time strings.
+	defaultDateLayouts = []DateLayout{
+		{Layout: "2006-01-02", NumFmt: "yyyy-mm-dd"},
+		{Layout: "2006-01-02 15:04:05", NumFmt: "yyyy-mm-dd hh:mm:ss"},
+		{Layout: time.RFC3339, NumFmt: "yyyy-mm-dd hh:mm:ss"},
+		{Layout: "1/2/2006", NumFmt: "m/d/yyyy"},
+		{Layout: "15:04:05", NumFmt: "hh:mm:ss"},
+	}
+	// parseNumberRegexp defined the regular expression to match the plain
+	// numbers and the numbers with thousands separators.
+	parseNumberRegexp = regexp.MustCompile(`^[-+]?(\d{1,3}(,\d{3})+|\d+)?(\.\d+)?([eE][-+]?\d+)?$`)
+)
+
+// setCellParsedStr provides a function to parse the string value and set the
+// typed value and number format of the cell by given worksheet name, cell
+// coordinates and the string value. It returns false if the value can't be
+// parsed.
+func (f *File) setCellParsedStr(sheet, axis, value string) (bool, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "TRUE") || strings.EqualFold(value, "FALSE") {
+		return true, f.SetCellBool(sheet, axis, strings.EqualFold(value, "TRUE"))
+	}
+	num, numFmt, ok := f.parseStrValue(value)
+	if !ok {
+		return false, nil
+	}
+	if err := f.SetCellFloat(sheet, axis, num, -1, 64); err != nil {
+		return true, err
+	}
+	return true, f.setDefaultNumFmtStyle(sheet, axis, numFmt)
+}
+
+// parseStrValue provides a function to parse the number, percentage, date
+// and time string to the number, and returns the number format of the value.
+func (f *File) parseStrValue(value string) (float64, string, bool) {
+	if num, numFmt, ok := parseStrNumber(strings.TrimSuffix(value, "%")); ok && value != "" {
+		if strings.HasSuffix(value, "%") {
+			numFmt = "0%"
+			if strings.Contains(value, ".") {
+				numFmt = "0.00%"
+			}
+			return num / 100, numFmt, true
+		}
+		return num, numFmt, true
+	}
+	layouts := f.dateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout.Layout, value)
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 {
+			clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+			return float64(clock) / float64(24*time.Hour), layout.NumFmt, true
+		}
+		if num, err := timeToExcelTime(t); err == nil && num > 0 {
+			return num, layout.NumFmt, true
+		}
+	}
+	return 0, "", false
+}
+
+// parseStrNumber provides a function to parse the plain number or the number
+// with thousands separators, and returns the number format of the value.
+func parseStrNumber(value string) (float64, string, bool) {
+	matches := parseNumberRegexp.FindStringSubmatch(value)
+	if matches == nil || (matches[1] == "" && matches[3] == "") {
+		return 0, "", false
+	}
+	// Keep the numbers with leading zeros as strings, such as the zip codes
+	// and the identifiers, except the zero and the decimals less than one.
+	if len(matches[1]) > 1 && matches[1][0] == '0' {
+		return 0, "", false
+	}
+	num, err := strconv.ParseFloat(strings.Replace(value, ",", "", -1), 64)
+	if err != nil {
+		return 0, "", false
+	}
+	var numFmt string
+	if matches[2] != "" {
+		numFmt = "#,##0"
+		if matches[3] != "" {
+			numFmt = "#,##0.00"
+		}
+	}
+	if matches[4] != "" {
+		numFmt = "0.00e+00"
+	}
+	return num, numFmt, true
+}
+
+// Percent is the percentage value, which will be stored as a number with the
+// 0.00% number format by the SetCellValue function.
+type Percent float64
+
+// Money directly maps the currency value, which will be stored as a number
+// with the currency number format by the SetCellValue function. Currency
+// specifies the ISO 4217 currency code, such as USD, EUR and GBP, the
+// SetCellValue function returns ErrCurrencyCode on the unknown code.
+type Money struct {
+	Amount   float64
+	Currency string
+}
+
+// currencySymbols defined the mapping of the currency codes and the symbols.
+var currencySymbols = map[string]string{
+	"CNY": "¥",
+	"EUR": "€",
+	"GBP": "£",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"RUB": "₽",
+	"USD": "$",
+}
+
+// currencyCodes defined the ISO 4217 codes of the currencies, the codes of
+// the funds, the precious metals and the codes reserved for testing and no
+// currency are not included.
+var currencyCodes = strings.Fields(`AED AFN ALL AMD ANG AOA ARS AUD AWG AZN
+	BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD CAD CDF CHF
+	CLP CNY COP CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP
+	GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HRK HTG HUF IDR ILS INR IQD IRR
+	ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL
+	LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO
+	NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD
+	SCR SDG SEK SGD SHP SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP
+	TRY TTD TWD TZS UAH UGX USD UYU UZS VES VND VUV WST XAF XCD XOF XPF YER
+	ZAR ZMW ZWL`)
+
+// numFmt provides a function to get the currency number format of the money,
+// it returns ErrCurrencyCode if the currency code is not an ISO 4217 code.
+func (m Money) numFmt() (string, error) {
+	code := strings.ToUpper(m.Currency)
+	if code == "" {
+		return "#,##0.00", nil
+	}
+	if inStrSlice(currencyCodes, code) == -1 {
+		return "", ErrCurrencyCode
+	}
+	if symbol, ok := currencySymbols[code]; ok {
+		return "[$" + symbol + "]#,##0.00", nil
+	}
+	return "\"" + code + " \"#,##0.00", nil
+}
+
+// numFmtValue directly maps the value and the number format of the struct
+// field with the numFmt tag.
+type numFmtValue struct {
+	value  interface{}
+	numFmt string
+}
+
+// getStructCellValues provides a function to get the values of the exported
+// fields of the struct, the values of the fields with the numFmt tag will be
+// wrapped with the number format.
+func getStructCellValues(v reflect.Value) reflect.Value {
+	values := []interface{}{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if numFmt := field.Tag.Get("numFmt"); numFmt != "" {
+			values = append(values, numFmtValue{value: v.Field(i).Interface(), numFmt: numFmt})
+			continue
+		}
+		values = append(values, v.Field(i).Interface())
+	}
+	return reflect.ValueOf(values)
+}
+
+// setCellNumFmtValue provides a function to set the number value and the
+// number format of the cell by given worksheet name, cell coordinates, value
+// and number format code.
+func (f *File) setCellNumFmtValue(sheet, axis string, value float64, numFmt string) error {
+	if err := f.SetCellFloat(sheet, axis, value, -1, 64); err != nil {
+		return err
+	}
+	return f.setDefaultNumFmtStyle(sheet, axis, numFmt)
+}
+
+// setDefaultNumFmtStyle provides a function to set the number format of the
+// cell by given worksheet name, cell coordinates and number format code if
+// the cell has no style.
+func (f *File) setDefaultNumFmtStyle(sheet, axis, numFmt string) error {
+	if numFmt == "" {
+		return nil
+	}
+	s, err := f.GetCellStyle(sheet, axis)
+	if err != nil || s != 0 {
+		return err
+	}
+	if s, err = f.newNumFmtStyle(numFmt); err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, axis, axis, s)
+}
+
+// newNumFmtStyle provides a function to create the style with the number
+// format by given number format code. The built-in number format will be used
+// if the code matches it.
+func (f *File) newNumFmtStyle(numFmt string) (int, error) {
+	style := &Style{CustomNumFmt: &numFmt}
+	for id, code := range builtInNumFmt {
+		if code == numFmt && (style.CustomNumFmt != nil || id < style.NumFmt) {
+			style = &Style{NumFmt: id}
+		}
+	}
+	return f.NewStyle(style)
+}
@@ -172,6 +172,152 @@ func TestSetCellValues(t *testing.T) {
 	assert.Equal(t, v, "1600-12-31T00:00:00Z")
 }
 
+func TestParseStrings(t *testing.T) {
+	f := NewFile().ParseStrings(true)
+	for _, c := range []struct {
+		cell, value, raw string
+		numFmt           int
+	}{
+		{"A1", "true", "1", 0},
+		{"A2", "1234", "1234", 0},
+		{"A3", "-1,234", "-1234", 3},
+		{"A4", "1,234.5", "1234.5", 4},
+		{"A5", "12%", "0.12", 9},
+		{"A6", "12.5%", "0.125", 10},
+		{"A7", "1.5e3", "1500", 11},
+		{"A8", "2021-12-31", "44561", 164},
+		{"A9", "12/31/2021", "44561", 165},
+		{"A10", "2021-12-31 12:00:00", "44561.5", 166},
+		{"A11", "18:00:00", "0.75", 21},
+		{"A12", "text", "", 0},
+		{"A13", "1,23", "", 0},
+		{"A14", ".", "", 0},
+		{"A15", "007", "", 0},
+		{"A16", "-0012", "", 0},
+		{"A17", "00.5", "", 0},
+		{"A18", "0", "0", 0},
+		{"A19", "0.5", "0.5", 0},
+		{"A20", "-0.5", "-0.5", 0},
+		{"A21", "1e5", "100000", 11},
+	} {
+		cell := c.cell
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, c.value))
+		_, row, err := CellNameToCoordinates(cell)
+		assert.NoError(t, err)
+		ws, err := f.workSheetReader("Sheet1")
+		assert.NoError(t, err)
+		if c.raw == "" {
+			assert.Equal(t, "s", ws.SheetData.Row[row-1].C[0].T, cell)
+		} else {
+			assert.Equal(t, c.raw, ws.SheetData.Row[row-1].C[0].V, cell)
+		}
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.numFmt, *f.Styles.CellXfs.Xf[style].NumFmtID, cell)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", ws.SheetData.Row[0].C[0].T)
+	value, err := f.GetCellValue("Sheet1", "A8")
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-12-31", value)
+	for cell, expected := range map[string]string{"A7": "1.50E+03", "A15": "007", "A21": "1.00E+05"} {
+		value, err = f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B1", &[]interface{}{"007", "1e5"}))
+	for cell, expected := range map[string]string{"B1": "007", "C1": "1.00E+05"} {
+		value, err = f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+
+	// Test parse strings with the custom date layouts and the cell style.
+	f = NewFile().ParseStrings(true, DateLayout{Layout: "02.01.2006", NumFmt: "dd.mm.yyyy"})
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "31.12.2021"))
+	value, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "31.12.2021", value)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "2021-12-31"))
+	value, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-12-31", value)
+	style, err := f.NewStyle(&Style{NumFmt: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "12%"))
+	value, err = f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.12", value)
+
+	// Test parse strings with invalid cell coordinates.
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A", "true"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A", "12%"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	// Test disable parse strings.
+	f.ParseStrings(false)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "1234"))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", ws.SheetData.Row[3].C[0].T)
+}
+
+func TestSetCellNumFmtValue(t *testing.T) {
+	f := NewFile()
+	for _, c := range []struct {
+		cell     string
+		value    interface{}
+		expected string
+	}{
+		{"A1", Percent(0.15), "15.00%"},
+		{"A2", Money{Amount: 1234.5, Currency: "usd"}, "$1,234.50"},
+		{"A3", Money{Amount: 1234.5, Currency: "EUR"}, "€1,234.50"},
+		{"A4", Money{Amount: 1234.5, Currency: "CHF"}, "CHF 1,234.50"},
+		{"A5", Money{Amount: 1234.5}, "1,234.50"},
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", c.cell, c.value))
+		value, err := f.GetCellValue("Sheet1", c.cell, Options{CultureInfo: CultureNameEnUS})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, value, c.cell)
+	}
+	// Test set cell value with the unknown currency code.
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A6", Money{Amount: 1234.5, Currency: "XXX"}), ErrCurrencyCode.Error())
+	// Test set cell value with the number format on the cell with style.
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", Percent(0.15)))
+	value, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.15", value)
+
+	// Test write the struct with the number format tags.
+	type order struct {
+		Product  string
+		Discount float64 `numFmt:"0.00%"`
+		Price    float64 `numFmt:"#,##0.00"`
+		Tax      Percent
+		quantity int
+		Date     time.Time `numFmt:"yyyy-mm-dd"`
+	}
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A7", &order{"Apple", 0.15, 1234.5, 0.08, 1, time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "15.00%", "1234.50", "8.00%", "2021-12-31"}, rows[6])
+	assert.NoError(t, f.SetSheetCol("Sheet1", "A8", &order{Product: "Pear", Discount: 0.5}, 0, style))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Pear", "0.50"}, []string{rows[7][0], rows[8][0]})
+
+	// Test set cell value with the number format with invalid cell coordinates.
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A", Percent(0.15)), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.setDefaultNumFmtStyle("Sheet1", "A", "0%"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetSheetRow("Sheet1", "A", &order{}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetSheetRow("SheetN", "A1", &order{Discount: 0.5}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetSheetRow("Sheet1", "A1", order{}), ErrParameterInvalid.Error())
+}
+
 func TestSetCellBool(t *testing.T) {
 	f := NewFile()
 	assert.EqualError(t, f.SetCellBool("Sheet1", "A", true), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
@@ -401,6 +547,104 @@ func TestSetCellRichText(t *testing.T) {
 	assert.EqualError(t, f.SetCellRichText("SheetN", "A1", richTextRun), "sheet SheetN is not exist")
 	// Test set cell rich text with illegal cell coordinates
 	assert.EqualError(t, f.SetCellRichText("Sheet1", "A", richTextRun), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test set cell rich text with characters exceeds maximum limit
+	assert.EqualError(t, f.SetCellRichText("Sheet1", "A1", []RichTextRun{{Text: strings.Repeat("s", TotalCellChars)}, {Text: "s"}}), ErrCellCharsLength.Error())
+	assert.EqualError(t, f.SetCellRichText("Sheet1", "A1", []RichTextRun{{Text: strings.Repeat("s", TotalCellChars-1)}, {Text: "\U0001F600"}}), ErrCellCharsLength.Error())
+}
+
+func TestUpdateCellRichTextRun(t *testing.T) {
+	f := NewFile()
+	runs := []RichTextRun{
+		{Text: "H"},
+		{Text: "2", Font: &Font{VertAlign: "subscript", Charset: intPtr(134), Condense: true, Extend: true, Outline: true, Shadow: true, Scheme: "minor"}},
+		{Text: "O", Font: &Font{Bold: true}},
+	}
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A1", runs))
+	result, err := f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Font{Underline: "none", VertAlign: "subscript", Charset: intPtr(134), Condense: true, Extend: true, Outline: true, Shadow: true, Scheme: "minor"}, result[1].Font)
+
+	assert.NoError(t, f.UpdateCellRichTextRun("Sheet1", "A1", 2, RichTextRun{Text: "2", Font: &Font{VertAlign: "superscript"}}))
+	result, err = f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "H", result[0].Text)
+	assert.Equal(t, "subscript", result[1].Font.VertAlign)
+	assert.Equal(t, "2", result[2].Text)
+	assert.Equal(t, "superscript", result[2].Font.VertAlign)
+	assert.False(t, result[2].Font.Bold)
+
+	// Test update rich text run on the cell with plain text.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "plain"))
+	assert.NoError(t, f.UpdateCellRichTextRun("Sheet1", "A2", 0, RichTextRun{Text: "rich", Font: &Font{Italic: true}}))
+	result, err = f.GetCellRichText("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "rich", Font: &Font{Italic: true, Underline: "none"}}}, result)
+	// Test update rich text run with invalid run index.
+	assert.EqualError(t, f.UpdateCellRichTextRun("Sheet1", "A1", 3, RichTextRun{}), ErrRichTextRunIdx.Error())
+	assert.EqualError(t, f.UpdateCellRichTextRun("Sheet1", "A1", -1, RichTextRun{}), ErrRichTextRunIdx.Error())
+	// Test update rich text run on the cell without shared string.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", 1))
+	assert.EqualError(t, f.UpdateCellRichTextRun("Sheet1", "A3", 0, RichTextRun{}), ErrRichTextRunIdx.Error())
+	// Test update rich text run on not exists worksheet.
+	assert.EqualError(t, f.UpdateCellRichTextRun("SheetN", "A1", 0, RichTextRun{}), "sheet SheetN is not exist")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUpdateCellRichTextRun.xlsx")))
+}
+
+func TestInlineStrings(t *testing.T) {
+	f := NewFile().InlineStrings(true)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", " inline "))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A2", "text"))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A3", []RichTextRun{{Text: "bold", Font: &Font{Bold: true}}, {Text: " text"}}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", 1))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "inlineStr", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, "", ws.SheetData.Row[0].C[0].V)
+	assert.Equal(t, "preserve", ws.SheetData.Row[0].C[0].IS.T.Space.Value)
+	assert.Equal(t, "inlineStr", ws.SheetData.Row[2].C[0].T)
+	assert.Len(t, ws.SheetData.Row[2].C[0].IS.R, 2)
+	assert.Nil(t, ws.SheetData.Row[3].C[0].IS)
+	assert.Len(t, f.sharedStringsReader().SI, 0)
+	assert.EqualError(t, f.SetCellStr("Sheet1", "A5", strings.Repeat("s", TotalCellChars+1)), ErrCellCharsLength.Error())
+	assert.EqualError(t, f.SetCellStr("Sheet1", "A5", strings.Repeat("s", TotalCellChars-1)+"\U0001F600"), ErrCellCharsLength.Error())
+	file := filepath.Join("test", "TestInlineStrings.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+
+	// Test inline strings will be kept on round-trip.
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{"A1": " inline ", "A2": "text", "A3": "bold text"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	runs, err := f.GetCellRichText("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "bold", Font: &Font{Bold: true, Underline: "none"}}, {Text: " text"}}, runs)
+	runs, err = f.GetCellRichText("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "text"}}, runs)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "new text"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "shared"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", true))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "inlineStr", ws.SheetData.Row[1].C[0].T)
+	assert.Equal(t, "new text", ws.SheetData.Row[1].C[0].IS.T.Val)
+	assert.Equal(t, "s", ws.SheetData.Row[0].C[1].T)
+	assert.Equal(t, "b", ws.SheetData.Row[0].C[0].T)
+	assert.Nil(t, ws.SheetData.Row[0].C[0].IS)
+	assert.NoError(t, f.SaveAs(file))
+
+	// Test get rich text on inline string cell without string item.
+	f = NewFile()
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A1", T: "inlineStr"}}}}
+	runs, err = f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, runs, 0)
 }
 
 func TestFormattedValue2(t *testing.T) {
@@ -443,3 +687,94 @@ func TestFormattedValue2(t *testing.T) {
 	v = f.formattedValue(1, "43528")
 	assert.Equal(t, "43528", v)
 }
+
+func TestSetCellDynamicArrayFormula(t *testing.T) {
+	f := NewFile()
+	for i, value := range []interface{}{"b", "a", "c", "a"} {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", i+1), value))
+	}
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "C1", "=SORT(UNIQUE(A1:A4))"))
+	formula, err := f.GetCellFormula("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "_xlfn._xlws.SORT(_xlfn.UNIQUE(A1:A4))", formula)
+	for cell, expected := range map[string]string{"C1": "a", "C2": "b", "C3": "c", "C4": ""} {
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxF{Content: "_xlfn._xlws.SORT(_xlfn.UNIQUE(A1:A4))", T: STCellFormulaTypeArray, Ref: "C1:C3"}, ws.SheetData.Row[0].C[2].F)
+	assert.Equal(t, 1, ws.SheetData.Row[0].C[2].CM)
+
+	// Test set dynamic array formula with two-dimensional results
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "E1", "SEQUENCE(2,3)"))
+	assert.Equal(t, "E1:G2", ws.SheetData.Row[0].C[4].F.Ref)
+	assert.Equal(t, 1, ws.SheetData.Row[0].C[4].CM)
+	result, err := f.GetCellValue("Sheet1", "G2")
+	assert.NoError(t, err)
+	assert.Equal(t, "6", result)
+
+	// Test the spill range is reduced after recalculation
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "b"))
+	assert.NoError(t, f.CalcAll())
+	assert.Equal(t, "C1:C2", ws.SheetData.Row[0].C[2].F.Ref)
+	result, err = f.GetCellValue("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "", result)
+
+	// Test recalculate the formulas which reference the spill range
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D5", "COUNTA(C1:C4)"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "d"))
+	assert.NoError(t, f.CalcAll())
+	result, err = f.GetCellValue("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result)
+
+	// Test the spill range is blocked
+	assert.NoError(t, f.SetCellValue("Sheet1", "F3", "blocked"))
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "F1", "SEQUENCE(3)"))
+	result, err = f.GetCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#SPILL!", result)
+	assert.Equal(t, "e", ws.SheetData.Row[0].C[5].T)
+	assert.Equal(t, "F1", ws.SheetData.Row[0].C[5].F.Ref)
+	// Test the spill range is out of the worksheet
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "XFD1", "SEQUENCE(1,2)"))
+	result, err = f.GetCellValue("Sheet1", "XFD1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#SPILL!", result)
+
+	// Test remove the dynamic array formula
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "E1", ""))
+	assert.Nil(t, ws.SheetData.Row[0].C[4].F)
+	assert.Equal(t, 0, ws.SheetData.Row[0].C[4].CM)
+	result, err = f.GetCellValue("Sheet1", "G2")
+	assert.NoError(t, err)
+	assert.Equal(t, "", result)
+
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellDynamicArrayFormula.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestSetCellDynamicArrayFormula.xlsx"))
+	assert.NoError(t, err)
+	metadata, err := f.metadataReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, metadata.CellMetadata.Count)
+	assert.Equal(t, "XLDAPR", metadata.MetadataTypes.MetadataType[0].Name)
+	assert.Contains(t, metadata.FutureMetadata[0].Bk[0].ExtLst.Content, `fDynamic="1"`)
+	assert.Contains(t, string(f.readXML("[Content_Types].xml")), ContentTypeSpreadSheetMLSheetMetadata)
+	assert.Contains(t, string(f.readXML("xl/_rels/workbook.xml.rels")), SourceRelationshipSheetMetadata)
+	// Test set dynamic array formula reuse the existing cell metadata
+	assert.NoError(t, f.SetCellDynamicArrayFormula("Sheet1", "H1", "UNIQUE(A1:A4)"))
+	assert.Equal(t, 1, metadata.CellMetadata.Count)
+	assert.Equal(t, 1, metadata.FutureMetadata[0].Count)
+	// Test set dynamic array formula on not exists worksheet
+	assert.EqualError(t, f.SetCellDynamicArrayFormula("SheetN", "A1", "SEQUENCE(2)"), "sheet SheetN is not exist")
+	// Test set dynamic array formula with invalid cell coordinates
+	assert.EqualError(t, f.SetCellDynamicArrayFormula("Sheet1", "A", "SEQUENCE(2)"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test set dynamic array formula with unsupported formula function
+	assert.EqualError(t, f.SetCellDynamicArrayFormula("Sheet1", "A10", "UNSUPPORTEDFUNC(2)"), "not support UNSUPPORTEDFUNC function")
+	// Test set dynamic array formula with unsupported charset metadata
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathMetadata, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCellDynamicArrayFormula("Sheet1", "A1", "SEQUENCE(2)"), "XML syntax error on line 1: invalid UTF-8")
+}
@@ -15,6 +15,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -75,6 +76,17 @@ const (
 	WireframeContour            = "wireframeContour"
 	Bubble                      = "bubble"
 	Bubble3D                    = "bubble3D"
+	StockHighLowClose           = "stockHighLowClose"
+	StockOpenHighLowClose       = "stockOpenHighLowClose"
+	StockVolumeHighLowClose     = "stockVolumeHighLowClose"
+	StockVolumeOpenHighLowClose = "stockVolumeOpenHighLowClose"
+	Waterfall                   = "waterfall"
+	Funnel                      = "funnel"
+	Treemap                     = "treemap"
+	Sunburst                    = "sunburst"
+	BoxWhisker                  = "boxWhisker"
+	Histogram                   = "histogram"
+	Pareto                      = "pareto"
 )
 
 // This section defines the default value of chart properties.
@@ -251,6 +263,10 @@ var (
 		Contour:                     0,
 		Bubble:                      0,
 		Bubble3D:                    0,
+		StockHighLowClose:           0,
+		StockOpenHighLowClose:       0,
+		StockVolumeHighLowClose:     0,
+		StockVolumeOpenHighLowClose: 0,
 	}
 	chartLegendPosition = map[string]string{
 		"bottom":    "b",
@@ -314,6 +330,10 @@ var (
 		WireframeContour:            "General",
 		Bubble:                      "General",
 		Bubble3D:                    "General",
+		StockHighLowClose:           "General",
+		StockOpenHighLowClose:       "General",
+		StockVolumeHighLowClose:     "General",
+		StockVolumeOpenHighLowClose: "General",
 	}
 	chartValAxCrossBetween = map[string]string{
 		Area:                        "midCat",
@@ -370,6 +390,10 @@ var (
 		WireframeContour:            "midCat",
 		Bubble:                      "midCat",
 		Bubble3D:                    "midCat",
+		StockHighLowClose:           "between",
+		StockOpenHighLowClose:       "between",
+		StockVolumeHighLowClose:     "between",
+		StockVolumeOpenHighLowClose: "between",
 	}
 	plotAreaChartGrouping = map[string]string{
 		Area:                        "standard",
@@ -467,6 +491,58 @@ var (
 		Contour:          "none",
 		WireframeContour: "none",
 	}
+	chartStockSeriesCount = map[string]int{
+		StockHighLowClose:           3,
+		StockOpenHighLowClose:       4,
+		StockVolumeHighLowClose:     4,
+		StockVolumeOpenHighLowClose: 5,
+	}
+	chartDisplayUnits = map[string]string{
+		"hundreds":          "hundreds",
+		"thousands":         "thousands",
+		"ten_thousands":     "tenThousands",
+		"hundred_thousands": "hundredThousands",
+		"millions":          "millions",
+		"ten_millions":      "tenMillions",
+		"hundred_millions":  "hundredMillions",
+		"billions":          "billions",
+		"trillions":         "trillions",
+	}
+	chartExLayoutID = map[string]string{
+		Waterfall:  "waterfall",
+		Funnel:     "funnel",
+		Treemap:    "treemap",
+		Sunburst:   "sunburst",
+		BoxWhisker: "boxWhisker",
+		Histogram:  "clusteredColumn",
+		Pareto:     "clusteredColumn",
+	}
+	chartExLegendPosition = map[string]string{
+		"bottom":    "b",
+		"left":      "l",
+		"right":     "r",
+		"top":       "t",
+		"top_right": "r",
+	}
+	chartTrendlineTypes = map[string]string{
+		"exponential":    "exp",
+		"linear":         "linear",
+		"log":            "log",
+		"moving_average": "movingAvg",
+		"polynomial":     "poly",
+		"power":          "power",
+	}
+	chartErrorBarsTypes = map[string]string{
+		"custom":             "cust",
+		"fixed":              "fixedVal",
+		"percentage":         "percentage",
+		"standard_deviation": "stdDev",
+		"standard_error":     "stdErr",
+	}
+	chartErrorBarsDirections = []string{"both", "minus", "plus"}
+	chartTickMarks           = []string{"cross", "in", "none", "out"}
+	chartTimeUnits           = []string{"days", "months", "years"}
+	chartDashTypes           = []string{"solid", "dot", "dash", "lgDash", "dashDot", "lgDashDot", "lgDashDotDot", "sysDash", "sysDot", "sysDashDot", "sysDashDotDot"}
 )
 
 // parseFormatChartSet provides a function to parse the format settings of the
@@ -641,6 +717,21 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //     wireframeContour            | wireframe contour chart
 //     bubble                      | bubble chart
 //     bubble3D                    | 3D bubble chart
+//     stockHighLowClose           | high-low-close stock chart
+//     stockOpenHighLowClose       | open-high-low-close stock chart
+//     stockVolumeHighLowClose     | volume-high-low-close stock chart
+//     stockVolumeOpenHighLowClose | volume-open-high-low-close stock chart
+//     waterfall                   | waterfall chart
+//     funnel                      | funnel chart
+//     treemap                     | treemap chart
+//     sunburst                    | sunburst chart
+//     boxWhisker                  | box and whisker chart
+//     histogram                   | histogram chart
+//     pareto                      | pareto chart
+//
+// The stock chart requires the series in the order of the type name: the high-low-close stock chart requires 3 series (high, low and close prices), the open-high-low-close stock chart requires 4 series (open, high, low and close prices) and will be drawn with up and down bars. The volume stock chart requires the volume series before the price series, the volume series will be drawn as columns on the primary axes, and the price series will be drawn on the secondary axes, the y_axis options only apply to the price axis.
+//
+// The waterfall, funnel, treemap, sunburst, box and whisker, histogram and pareto charts are the extended chart types which are introduced in Excel 2016, these charts will be stored in the xl/charts/chartEx%d.xml parts, and can't be combined with other charts or created in a chartsheet. The name, categories and values options of the series, the title, legend, dimension, format and the show_val, show_cat_name and show_series_name options of the plot area are supported for these charts. The treemap and sunburst charts use the hierarchical categories and the sizes (or values if the sizes isn't supplied) of the series, the histogram and pareto charts group the values of the series into bins automatically, or aggregate the values by the categories if the categories of the series is supplied.
 //
 // In Excel a chart series is a collection of information that defines which data is plotted such as values, axis labels and formatting.
 //
@@ -649,8 +740,16 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //    name
 //    categories
 //    values
+//    sizes
+//    fill
 //    line
 //    marker
+//    smooth
+//    points
+//    y2_axis
+//    trendline
+//    x_error_bars
+//    y_error_bars
 //
 // name: Set the name for the series. The name is displayed in the chart legend and in the formula bar. The name property is optional and if it isn't supplied it will default to Series 1..n. The name can also be a formula such as Sheet1!$A$1
 //
@@ -658,9 +757,13 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // values: This is the most important property of a series and is the only mandatory option for every chart object. This option links the chart with the worksheet data that it displays.
 //
-// line: This sets the line format of the line chart. The line property is optional and if it isn't supplied it will default style. The options that can be set is width. The range of width is 0.25pt - 999pt. If the value of width is outside the range, the default width of the line is 2pt.
+// sizes: This sets the bubble sizes of the series for the bubble chart. The sizes property is optional and if it isn't supplied the values of the series will be used as the bubble sizes.
+//
+// fill: This sets the fill format of the series for the charts except line chart and scatter chart, such as the columns, bars, areas and pie slices. The fill property is optional and if it isn't supplied it will default theme color. The options that can be set are color (in RGB hex format, such as #FF0000) and none.
 //
-// marker: This sets the marker of the line chart and scatter chart. The range of optional field 'size' is 2-72 (default value is 5). The enumeration value of optional field 'symbol' are (default value is 'auto'):
+// line: This sets the line format of the line chart and scatter chart, or the border of the series for the other charts. The line property is optional and if it isn't supplied it will default style. The options that can be set are color, width and none. The range of width is 0.25pt - 999pt. If the value of width is outside the range, the default width of the line is 2pt. The line of the scatter chart will be shown only if the color is specified.
+//
+// marker: This sets the marker of the line chart, scatter chart and stock chart, the marker of the stock chart is hidden by default. The range of optional field 'size' is 2-72 (default value is 5). The marker fill and border can be set by the optional fields 'fill' and 'border' with the options color and none, and the width of the marker border can be set by the optional field 'width'. The enumeration value of optional field 'symbol' are (default value is 'auto'):
 //
 //    circle
 //    dash
@@ -675,14 +778,56 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //    x
 //    auto
 //
+// smooth: Specifies the line of the line chart and scatter chart series shall be smoothed. The smooth property is optional. The default value is false.
+//
+// points: This sets the format of the individual data points in the series by the zero-based index of the data point. The options that can be set are index, fill color, border color and border width. For the line chart and scatter chart, the format will be applied to the marker of the data point. For example:
+//
+//    "points": [{"index": 2, "fill": {"color": "#C00000"}, "border": {"color": "#000000", "width": 1}}]
+//
+// y2_axis: Specifies the series shall be plotted on the secondary axes. The y2_axis property is optional. The default value is false.
+//
+// trendline: This sets the trendline of the series for the area, bar, column, line, scatter and bubble charts which are not stacked. The trendline property is optional. The options that can be set are type, name, order, period, forward, backward, intercept, display_equation, display_r_squared and line with color and width. The order (2-6, default value is 2) is only used for the polynomial trendline, and the period (default value is 2) is only used for the moving average trendline. The enumeration value of field 'type' are:
+//
+//    exponential
+//    linear
+//    log
+//    moving_average
+//    polynomial
+//    power
+//
+// For example, add a polynomial trendline of order 3 with the equation and the R-squared value:
+//
+//    "trendline": {"type": "polynomial", "order": 3, "display_equation": true, "display_r_squared": true}
+//
+// x_error_bars and y_error_bars: These set the horizontal and vertical error bars of the series for the area, bar, column, line, scatter and bubble charts, the horizontal error bars are only available for the scatter and bubble charts. The error bars properties are optional. The options that can be set are type, direction (both, minus or plus, default value is both), value (default value is 1), plus_values, minus_values, no_end_cap and line with color and width. The value is used for the fixed, percentage and standard deviation error bars, and the plus_values and minus_values which are references of the cells are used for the custom error bars. The enumeration value of field 'type' are:
+//
+//    fixed
+//    percentage
+//    standard_deviation
+//    standard_error
+//    custom
+//
+// For example, add the custom vertical error bars:
+//
+//    "y_error_bars": {"type": "custom", "plus_values": "Sheet1!$E$2:$E$4", "minus_values": "Sheet1!$F$2:$F$4"}
+//
 // Set properties of the chart legend. The options that can be set are:
 //
 //    none
 //    position
+//    overlay
+//    delete_series
+//    font
 //    show_legend_key
 //
 // none: Specified if show the legend without overlapping the chart. The default value is 'false'.
 //
+// overlay: Specifies the legend shall be overlapping the plot area. The default value is false.
+//
+// delete_series: Specifies the zero-based indexes of the legend entries which shall be deleted from the legend, for example: [0, 2].
+//
+// font: Specifies the font of the legend, the options that can be set are same as the font of the cell style, such as bold, italic, underline, family, size, strike and color.
+//
 // position: Set the position of the chart legend. The default legend position is right. This parameter only takes effect when 'none' is false. The available positions are:
 //
 //    top
@@ -695,10 +840,28 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // Set properties of the chart title. The properties that can be set are:
 //
-//    title
+//    none
+//    name
+//    rich_text
+//    font
+//    overlay
+//    fill
+//    border
+//
+// none: Specifies the chart title shall be deleted. The default value is false.
 //
 // name: Set the name (title) for the chart. The name is displayed above the chart. The name can also be a formula such as Sheet1!$A$1 or a list with a sheetname. The name property is optional. The default is to have no chart title.
 //
+// rich_text: Set the rich text runs of the chart title, each run has the text and the optional font, for example: [{"text": "Sales ", "font": {"bold": true}}, {"text": "2021", "font": {"color": "#C00000"}}]. The name will be ignored if the rich_text is specified.
+//
+// font: Set the font of the chart title, the options that can be set are same as the font of the cell style, such as bold, italic, underline, family, size, strike and color.
+//
+// overlay: Specifies the title shall be overlapping the plot area. The default value is false.
+//
+// fill: Set the fill color of the chart title by the option color.
+//
+// border: Set the border of the chart title by the options color, width (in points) and none.
+//
 // Specifies how blank cells are plotted on the chart by show_blanks_as. The default value is gap. The options that can be set are:
 //
 //    gap
@@ -736,33 +899,80 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // show_val: Specifies that the value shall be shown in a data label. The show_val property is optional. The default value is false.
 //
+// The fill color and border of the plot area can be set by the fill and border of the plotarea. The options of the fill that can be set is color, and the options of the border that can be set are color, width (in points) and dash_type. The available dash types are: solid, dot, dash, lgDash, dashDot, lgDashDot, lgDashDotDot, sysDash, sysDot, sysDashDot and sysDashDotDot. For example:
+//
+//    "plotarea": {"fill": {"color": "#F2F2F2"}, "border": {"color": "#BFBFBF", "width": 1, "dash_type": "dash"}}
+//
+// Set the fill color and border of the chart area by chartarea, for example: "chartarea": {"fill": {"color": "#FFFFFF"}, "border": {"none": true}}.
+//
 // Set the primary horizontal and vertical axis options by x_axis and y_axis. The properties of x_axis that can be set are:
 //
 //    none
+//    name
 //    major_grid_lines
 //    minor_grid_lines
 //    tick_label_skip
 //    reverse_order
 //    maximum
 //    minimum
+//    major_tick_mark
+//    minor_tick_mark
+//    num_format
+//    num_font
+//    crossing
+//    date_axis
+//    base_unit
+//    major_unit
+//    major_unit_type
+//    minor_unit
+//    minor_unit_type
 //
 // The properties of y_axis that can be set are:
 //
 //    none
+//    name
 //    major_grid_lines
 //    minor_grid_lines
 //    major_unit
+//    minor_unit
 //    reverse_order
 //    maximum
 //    minimum
+//    major_tick_mark
+//    minor_tick_mark
+//    num_format
+//    num_font
+//    crossing
+//    display_units
+//    display_units_visible
 //
 // none: Disable axes.
 //
+// name: Set the title of the axis.
+//
 // major_grid_lines: Specifies major gridlines.
 //
 // minor_grid_lines: Specifies minor gridlines.
 //
-// major_unit: Specifies the distance between major ticks. Shall contain a positive floating-point number. The major_unit property is optional. The default value is auto.
+// major_unit: Specifies the distance between major ticks. Shall contain a positive floating-point number. The major_unit property is optional. The default value is auto. For the x_axis, the major_unit only takes effect on the date axis.
+//
+// minor_unit: Specifies the distance between minor ticks. Shall contain a positive floating-point number. The minor_unit property is optional. The default value is auto. For the x_axis, the minor_unit only takes effect on the date axis.
+//
+// major_tick_mark and minor_tick_mark: Specifies the type of the major and minor tick marks. The available types are: none, in, out and cross. The default value is none.
+//
+// num_format: Specifies the number format code of the axis labels, such as 0.00% or mmm yyyy. The default is linked to the source data.
+//
+// num_font: Specifies the font of the axis labels. The options that can be set are color, bold, italic, underline and rotation. The range of rotation is -90 - 90 degrees.
+//
+// crossing: Specifies the position where the perpendicular axis crosses this axis. For example, set the crossing of the x_axis to specify the category number where the y_axis crosses the x_axis. The available values are: auto_zero, max, min or a number in string such as "3". The default value is auto_zero.
+//
+// date_axis: Specifies the category axis is a date axis, the category data shall be dates.
+//
+// base_unit, major_unit_type and minor_unit_type: Specifies the base time unit, the time unit of the major_unit and the time unit of the minor_unit of the date axis. The available units are: days, months and years.
+//
+// display_units: Specifies the display units of the value axis. The available units are: hundreds, thousands, ten_thousands, hundred_thousands, millions, ten_millions, hundred_millions, billions and trillions.
+//
+// display_units_visible: Specifies the display units label shall be shown on the chart.
 //
 // tick_label_skip: Specifies how many tick labels to skip between label that is drawn. The tick_label_skip property is optional. The default value is auto.
 //
@@ -772,8 +982,20 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // minimum: Specifies that the fixed minimum, 0 is auto. The minimum property is optional. The default value is auto.
 //
+// Set the secondary vertical axis options by y2_axis, the properties of y2_axis are the same as y_axis. The series will be plotted on the secondary axes if the y2_axis option of the series is true, this is typically used in the combo chart, such as plot a line series against the secondary axis of a column chart, and the series of the same chart type can also be plotted on different axes. The secondary axis isn't supported for the 3D, pie, doughnut, contour and stock charts. For example:
+//
+//    {"type":"col","series":[{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$B$2:$B$4"},{"name":"Sheet1!$C$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$C$2:$C$4","y2_axis":true}],"y_axis":{"name":"Sales"},"y2_axis":{"name":"Growth","num_format":"0%","maximum":1}}
+//
 // Set chart size by dimension property. The dimension property is optional. The default width is 480, and height is 290.
 //
+// Set the space between the bars or columns clusters by gap_width, as a percentage of the bar or column width, the range of gap_width is 0 - 500. Set how much the bars or columns in the cluster overlap each other by overlap, the range of overlap is -100 - 100. The gap_width and overlap properties are optional and only take effect on the bar and column charts, and the overlap doesn't take effect on the 3D charts.
+//
+// Set the path of the chart template file (.crtx) saved by Excel by template property to apply the formatting of the template to the chart, such as the chart area, plot area, title, legend, walls, axes and series (by the order of the series) fill, border, line, marker and font. The data references and values of the chart will be kept. The template property is optional.
+//
+// Set the pivot table by pivot_table property in the format of sheet!name to create a pivot chart bound to the pivot table, the series of the chart will be created by the data fields of the pivot table if the series are not specified. The extended chart types can't be used as pivot chart. The pivot_table property is optional. For example, create a pivot chart for the pivot table PivotTable1 on Sheet1:
+//
+//    err := f.AddChart("Sheet1", "N2", `{"type":"col","pivot_table":"Sheet1!PivotTable1","title":{"name":"Sales by Region"}}`)
+//
 // combo: Specifies the create a chart that combines two or more chart types
 // in a single chart. For example, create a clustered column - line chart with
 // data Sheet1!$E$1:$L$15:
@@ -893,16 +1115,39 @@ func (f *File) AddChart(sheet, cell, format string, combo ...string) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	formatSet, comboCharts, err := f.getFormatChart(format, combo)
 	if err != nil {
 		return err
 	}
+	if formatSet.PivotTable != "" {
+		if _, ok := chartExLayoutID[formatSet.Type]; ok {
+			return ErrChartExPivotTable
+		}
+		if err = f.addPivotChartSource(formatSet); err != nil {
+			return err
+		}
+	}
 	// Add first picture for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
 	chartID := f.countCharts() + 1
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
+	if _, ok := chartExLayoutID[formatSet.Type]; ok {
+		chartID = f.countChartExs() + 1
+		drawingRID := f.addRels(drawingRels, SourceRelationshipChartEx, "../charts/chartEx"+strconv.Itoa(chartID)+".xml", "")
+		if err = f.addDrawingChartEx(sheet, drawingXML, cell, drawingRID, formatSet); err != nil {
+			return err
+		}
+		f.addChartEx(chartID, formatSet)
+		f.addContentTypePart(chartID, "chartEx")
+		f.addContentTypePart(drawingID, "drawings")
+		f.addSheetNameSpace(sheet, SourceRelationship)
+		return err
+	}
 	drawingRID := f.addRels(drawingRels, SourceRelationshipChart, "../charts/chart"+strconv.Itoa(chartID)+".xml", "")
 	err = f.addDrawingChart(sheet, drawingXML, cell, formatSet.Dimension.Width, formatSet.Dimension.Height, drawingRID, &formatSet.Format)
 	if err != nil {
@@ -928,6 +1173,9 @@ func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
 	if err != nil {
 		return err
 	}
+	if _, ok := chartExLayoutID[formatSet.Type]; ok {
+		return newUnsupportChartType(formatSet.Type)
+	}
 	cs := xlsxChartsheet{
 		SheetViews: &xlsxChartsheetViews{
 			SheetView: []*xlsxChartsheetView{{ZoomScaleAttr: 100, ZoomToFitAttr: true}},
@@ -974,6 +1222,12 @@ func (f *File) getFormatChart(format string, combo []string) (*formatChart, []*f
 	if err != nil {
 		return formatSet, comboCharts, err
 	}
+	if err = validateFormatChart(formatSet); err != nil {
+		return formatSet, comboCharts, err
+	}
+	if _, ok := chartExLayoutID[formatSet.Type]; ok && len(combo) > 0 {
+		return formatSet, comboCharts, ErrChartExCombo
+	}
 	for _, comboFormat := range combo {
 		comboChart, err := parseFormatChartSet(comboFormat)
 		if err != nil {
@@ -982,14 +1236,240 @@ func (f *File) getFormatChart(format string, combo []string) (*formatChart, []*f
 		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
 			return formatSet, comboCharts, newUnsupportChartType(comboChart.Type)
 		}
+		if err = validateFormatChart(comboChart); err != nil {
+			return formatSet, comboCharts, err
+		}
 		comboCharts = append(comboCharts, comboChart)
 	}
-	if _, ok := chartValAxNumFmtFormatCode[formatSet.Type]; !ok {
+	if _, ok := chartValAxNumFmtFormatCode[formatSet.Type]; !ok && chartExLayoutID[formatSet.Type] == "" {
 		return formatSet, comboCharts, newUnsupportChartType(formatSet.Type)
 	}
+	if formatSet.Template != "" {
+		formatSet.template, err = readChartTemplate(formatSet.Template)
+	}
 	return formatSet, comboCharts, err
 }
 
+// addPivotChartSource provides a function to set the source pivot table of
+// the pivot chart by given format settings, the series will be created by the
+// data fields of the pivot table if the series are not specified, and the
+// chart format counter of the pivot table will be increased.
+func (f *File) addPivotChartSource(formatSet *formatChart) error {
+	idx := strings.LastIndex(formatSet.PivotTable, "!")
+	if idx == -1 {
+		return ErrParameterInvalid
+	}
+	sheet := strings.TrimSuffix(strings.TrimPrefix(formatSet.PivotTable[:idx], "'"), "'")
+	pivotTableParts, err := f.getPivotTableParts(sheet)
+	if err != nil {
+		return err
+	}
+	for _, pivotTableXML := range pivotTableParts {
+		pt, err := f.pivotTableReader(pivotTableXML)
+		if err != nil {
+			return err
+		}
+		if pt.Name != formatSet.PivotTable[idx+1:] {
+			continue
+		}
+		if len(formatSet.Series) == 0 {
+			if formatSet.Series, err = f.getPivotChartSeries(sheet, pt); err != nil {
+				return err
+			}
+		}
+		formatSet.pivotSource = &cPivotSource{
+			Name:  fmt.Sprintf("[]%s!%s", quoteSheetName(sheet), pt.Name),
+			FmtID: &attrValInt{Val: intPtr(pt.ChartFormat)},
+		}
+		pt.ChartFormat++
+		pivotTable, err := xml.Marshal(pt)
+		f.saveFileList(pivotTableXML, pivotTable)
+		return err
+	}
+	return ErrPivotTableNotExist
+}
+
+// getPivotChartSeries provides a function to create the series of the pivot
+// chart by given worksheet name and pivot table definition, each data field
+// of the pivot table will be created as a series which located on the data
+// column of the pivot table.
+func (f *File) getPivotChartSeries(sheet string, pt *xlsxPivotTableDefinition) ([]formatChartSeries, error) {
+	var series []formatChartSeries
+	if pt.Location == nil || pt.DataFields == nil {
+		return series, ErrParameterInvalid
+	}
+	coordinates, err := f.areaRefToCoordinates(pt.Location.Ref)
+	if err != nil {
+		return series, err
+	}
+	_ = sortCoordinates(coordinates)
+	sheet = quoteSheetName(sheet)
+	cellRef := func(col, row int) string {
+		colName, _ := ColumnNumberToName(col)
+		return fmt.Sprintf("$%s$%d", colName, row)
+	}
+	startRow := coordinates[1] + pt.Location.FirstDataRow
+	for i := range pt.DataFields.DataField {
+		col := coordinates[0] + pt.Location.FirstDataCol + i
+		if col > coordinates[2] {
+			break
+		}
+		series = append(series, formatChartSeries{
+			Name:       sheet + "!" + cellRef(col, startRow-1),
+			Categories: sheet + "!" + cellRef(coordinates[0], startRow) + ":" + cellRef(coordinates[0], coordinates[3]),
+			Values:     sheet + "!" + cellRef(col, startRow) + ":" + cellRef(col, coordinates[3]),
+		})
+	}
+	return series, err
+}
+
+// validateFormatChart provides a function to validate the gap width, overlap,
+// border dash type and colors in the chart format settings.
+func validateFormatChart(formatSet *formatChart) error {
+	if formatSet.GapWidth != nil && (*formatSet.GapWidth < 0 || *formatSet.GapWidth > 500) {
+		return ErrChartGapWidth
+	}
+	if formatSet.Overlap != nil && (*formatSet.Overlap < -100 || *formatSet.Overlap > 100) {
+		return ErrChartOverlap
+	}
+	if count, ok := chartStockSeriesCount[formatSet.Type]; ok && len(formatSet.Series) != count {
+		return ErrChartStockSeries
+	}
+	for _, axis := range []formatChartAxis{formatSet.XAxis, formatSet.YAxis, formatSet.Y2Axis} {
+		if err := validateFormatChartAxis(axis); err != nil {
+			return err
+		}
+	}
+	for _, series := range formatSet.Series {
+		if series.Y2Axis && !isChartSecondaryAxisSupported(formatSet.Type) {
+			return ErrChartSecondaryAxis
+		}
+		if err := validateFormatChartTrendline(formatSet.Type, series.Trendline); err != nil {
+			return err
+		}
+		if series.XErrorBars.Type != "" && formatSet.Type != Scatter && formatSet.Type != Bubble {
+			return ErrChartErrorBars
+		}
+		for _, errBars := range []formatChartErrorBars{series.XErrorBars, series.YErrorBars} {
+			if err := validateFormatChartErrorBars(formatSet.Type, errBars); err != nil {
+				return err
+			}
+		}
+	}
+	isChartColor := func(color string) bool {
+		return color == "" || (len(strings.TrimPrefix(color, "#")) == 6 && isRGBColor(color))
+	}
+	if dashType := formatSet.Plotarea.Border.DashType; dashType != "" && inStrSlice(chartDashTypes, dashType) == -1 {
+		return newInvalidStyleAttrError("dash_type", dashType)
+	}
+	colors := []string{formatSet.Title.Fill.Color, formatSet.Title.Border.Color, formatSet.Legend.Font.Color, formatSet.Plotarea.Fill.Color, formatSet.Plotarea.Border.Color, formatSet.Chartarea.Fill.Color}
+	if formatSet.Title.Font != nil {
+		colors = append(colors, formatSet.Title.Font.Color)
+	}
+	for _, run := range formatSet.Title.RichText {
+		if run.Font != nil {
+			colors = append(colors, run.Font.Color)
+		}
+	}
+	for _, series := range formatSet.Series {
+		colors = append(colors, series.Fill.Color, series.Line.Color, series.Marker.Fill.Color, series.Marker.Border.Color,
+			series.Trendline.Line.Color, series.XErrorBars.Line.Color, series.YErrorBars.Line.Color)
+		for _, point := range series.Points {
+			colors = append(colors, point.Fill.Color, point.Border.Color)
+		}
+	}
+	for _, color := range colors {
+		if !isChartColor(color) {
+			return newInvalidStyleAttrError("color", color)
+		}
+	}
+	return nil
+}
+
+// validateFormatChartTrendline provides a function to validate the type,
+// order and period of the trendline in the chart series format settings.
+func validateFormatChartTrendline(chartType string, trendline formatChartTrendline) error {
+	if trendline.Type == "" {
+		return nil
+	}
+	if inStrSlice([]string{Area, Bar, Col, Line, Scatter, Bubble}, chartType) == -1 {
+		return ErrChartTrendline
+	}
+	if _, ok := chartTrendlineTypes[trendline.Type]; !ok {
+		return newInvalidStyleAttrError("trendline type", trendline.Type)
+	}
+	if trendline.Type == "polynomial" && trendline.Order != 0 && (trendline.Order < 2 || trendline.Order > 6) {
+		return ErrChartTrendlineOrder
+	}
+	if trendline.Type == "moving_average" && trendline.Period != 0 && trendline.Period < 2 {
+		return ErrChartTrendlinePeriod
+	}
+	return nil
+}
+
+// validateFormatChartErrorBars provides a function to validate the type,
+// direction and custom values of the error bars in the chart series format
+// settings.
+func validateFormatChartErrorBars(chartType string, errBars formatChartErrorBars) error {
+	if errBars.Type == "" {
+		return nil
+	}
+	if inStrSlice([]string{Area, AreaStacked, AreaPercentStacked, Bar, BarStacked, BarPercentStacked,
+		Col, ColStacked, ColPercentStacked, Line, Scatter, Bubble}, chartType) == -1 {
+		return ErrChartErrorBars
+	}
+	if _, ok := chartErrorBarsTypes[errBars.Type]; !ok {
+		return newInvalidStyleAttrError("error bars type", errBars.Type)
+	}
+	if errBars.Direction != "" && inStrSlice(chartErrorBarsDirections, errBars.Direction) == -1 {
+		return newInvalidStyleAttrError("error bars direction", errBars.Direction)
+	}
+	if errBars.Type == "custom" && errBars.PlusValues == "" && errBars.MinusValues == "" {
+		return ErrParameterRequired
+	}
+	return nil
+}
+
+// isChartSecondaryAxisSupported provides a function to check if the series
+// of the given chart type can be plotted on the secondary axes.
+func isChartSecondaryAxisSupported(chartType string) bool {
+	if _, ok := chartStockSeriesCount[chartType]; ok || strings.Contains(chartType, "3D") {
+		return false
+	}
+	return inStrSlice([]string{Doughnut, Pie, PieOfPieChart, BarOfPieChart, Contour, WireframeContour}, chartType) == -1
+}
+
+// validateFormatChartAxis provides a function to validate the tick marks,
+// time units, display units, crossing point and label rotation in the chart
+// axis format settings.
+func validateFormatChartAxis(axis formatChartAxis) error {
+	for attr, value := range map[string]string{"major_tick_mark": axis.MajorTickMark, "minor_tick_mark": axis.MinorTickMark} {
+		if value != "" && inStrSlice(chartTickMarks, value) == -1 {
+			return newInvalidStyleAttrError(attr, value)
+		}
+	}
+	for attr, value := range map[string]string{"base_unit": axis.BaseUnit, "major_unit_type": axis.MajorUnitType, "minor_unit_type": axis.MinorUnitType} {
+		if value != "" && inStrSlice(chartTimeUnits, value) == -1 {
+			return newInvalidStyleAttrError(attr, value)
+		}
+	}
+	if _, ok := chartDisplayUnits[axis.DisplayUnits]; axis.DisplayUnits != "" && !ok {
+		return newInvalidStyleAttrError("display_units", axis.DisplayUnits)
+	}
+	if axis.Crossing != "" && axis.Crossing != "auto_zero" && axis.Crossing != "max" && axis.Crossing != "min" {
+		if _, err := strconv.ParseFloat(axis.Crossing, 64); err != nil {
+			return newInvalidStyleAttrError("crossing", axis.Crossing)
+		}
+	}
+	if color := axis.NumFont.Color; color != "" && (len(strings.TrimPrefix(color, "#")) != 6 || !isRGBColor(color)) {
+		return newInvalidStyleAttrError("color", color)
+	}
+	if rotation := axis.NumFont.Rotation; rotation != nil && (*rotation < -90 || *rotation > 90) {
+		return newInvalidStyleAttrError("rotation", strconv.Itoa(*rotation))
+	}
+	return nil
+}
+
 // DeleteChart provides a function to delete chart in XLSX by given worksheet
 // and cell name.
 func (f *File) DeleteChart(sheet, cell string) (err error) {
@@ -1010,17 +1490,135 @@ func (f *File) DeleteChart(sheet, cell string) (err error) {
 	return f.deleteDrawing(col, row, drawingXML, "Chart")
 }
 
+// GetCharts provides a function to get the charts in the worksheet by given
+// worksheet name. The chart type, series, title, legend, axes and formatting
+// of each chart will be returned as the same format settings accepted by the
+// AddChart function, so the charts could be modified and added again. For
+// example, copy the first chart in Sheet1 to the cell H2 of Sheet2:
+//
+//    charts, err := f.GetCharts("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if len(charts) > 0 {
+//        if err = f.AddChart("Sheet2", "H2", charts[0].Format, charts[0].Combo...); err != nil {
+//            fmt.Println(err)
+//        }
+//    }
+//
+// The charts with the chart type which doesn't supported by the AddChart
+// function will be skipped.
+func (f *File) GetCharts(sheet string) ([]Chart, error) {
+	var charts []Chart
+	objects, err := f.getDrawingObjects(sheet)
+	if err != nil || len(objects) == 0 {
+		return charts, err
+	}
+	drawingXML, _ := f.getSheetDrawingXML(sheet)
+	drawingRels := getPartRelsPath(drawingXML)
+	for _, object := range objects {
+		if object.Type != "chart" {
+			continue
+		}
+		var deAnchor *decodeTwoCellAnchor
+		if deAnchor, err = f.decodeDrawingAnchor(object.cellAnchor); err != nil {
+			return charts, err
+		}
+		rel := f.getDrawingRelationships(drawingRels, deAnchor.GraphicFrame.Graphic.GraphicData.Chart.RID)
+		if rel == nil {
+			continue
+		}
+		var cs *chartTemplateNode
+		if cs, err = decodeChartTemplateNode(namespaceStrictToTransitional(f.readXML(getRelsTargetPath(drawingRels, rel.Target)))); err != nil {
+			return charts, err
+		}
+		formatSet, comboCharts := f.readChart(cs)
+		if formatSet == nil {
+			continue
+		}
+		f.readChartAnchor(sheet, object.cellAnchor, deAnchor, formatSet)
+		format, _ := json.Marshal(formatSet)
+		chart := Chart{Name: object.Name, Cell: object.Cell, Format: string(format)}
+		for _, comboChart := range comboCharts {
+			combo, _ := json.Marshal(comboChart)
+			chart.Combo = append(chart.Combo, string(combo))
+		}
+		charts = append(charts, chart)
+	}
+	return charts, err
+}
+
+// SetChartSeries provides a function to update the data references of the
+// series in the existing chart by given worksheet name, chart name and the
+// series, without recreating the chart, so the formatting of the chart will
+// be kept. The series will be updated in the order of the series in the
+// chart, the empty name, categories or sizes of the series will keep the
+// existing references. The new series will be added with the formatting of
+// the last series in the chart, and the extra series of the chart will be
+// removed. The name of the chart could be got by the GetCharts function. For
+// example, update the series of the first chart in the worksheet named
+// Sheet1:
+//
+//    charts, err := f.GetCharts("Sheet1")
+//    if err != nil || len(charts) == 0 {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.SetChartSeries("Sheet1", charts[0].Name, []excelize.ChartSeries{
+//        {Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$M$1", Values: "Sheet1!$B$2:$M$2"},
+//        {Name: "Sheet1!$A$3", Categories: "Sheet1!$B$1:$M$1", Values: "Sheet1!$B$3:$M$3"},
+//    })
+//
+func (f *File) SetChartSeries(sheet, name string, series []ChartSeries) error {
+	if len(series) == 0 {
+		return ErrParameterRequired
+	}
+	objects, err := f.getDrawingObjects(sheet)
+	if err != nil {
+		return err
+	}
+	drawingXML, _ := f.getSheetDrawingXML(sheet)
+	drawingRels := getPartRelsPath(drawingXML)
+	for _, object := range objects {
+		if object.Name != name {
+			continue
+		}
+		if object.Type != "chart" {
+			return ErrChartObject
+		}
+		deAnchor, err := f.decodeDrawingAnchor(object.cellAnchor)
+		if err != nil {
+			return err
+		}
+		rel := f.getDrawingRelationships(drawingRels, deAnchor.GraphicFrame.Graphic.GraphicData.Chart.RID)
+		if rel == nil {
+			return ErrObjectNotExist
+		}
+		chartXML := getRelsTargetPath(drawingRels, rel.Target)
+		cs, err := decodeChartTemplateNode(namespaceStrictToTransitional(f.readXML(chartXML)))
+		if err != nil {
+			return err
+		}
+		if err = setChartSeries(cs, series); err != nil {
+			return err
+		}
+		f.saveFileList(chartXML, cs.marshal(cs.prefixes))
+		return err
+	}
+	return ErrObjectNotExist
+}
+
 // countCharts provides a function to get chart files count storage in the
 // folder xl/charts.
 func (f *File) countCharts() int {
-	count := 0
-	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/charts/chart") {
-			count++
-		}
-		return true
-	})
-	return count
+	return f.countParts("xl/charts/chart")
+}
+
+// countChartExs provides a function to get the extended chart files count
+// storage in the folder xl/charts.
+func (f *File) countChartExs() int {
+	return f.countParts("xl/charts/chartEx")
 }
 
 // ptToEMUs provides a function to convert pt to EMUs, 1 pt = 12700 EMUs. The
@@ -1032,3 +1630,469 @@ func (f *File) ptToEMUs(pt float64) int {
 	}
 	return int(12700 * pt)
 }
+
+// getChartNodeText provides a function to get the text content of the chart
+// element, an empty string will be returned if the element doesn't exist.
+func getChartNodeText(n *chartTemplateNode) string {
+	if n == nil {
+		return ""
+	}
+	return n.Text
+}
+
+// getChartNodeBool provides a function to get the boolean value of the chart
+// element, the value will be true if the element exists without the val
+// attribute.
+func getChartNodeBool(n *chartTemplateNode) bool {
+	if n == nil {
+		return false
+	}
+	val := n.attr("val")
+	return val == "" || val == "1" || val == "true"
+}
+
+// getChartNodeFloat provides a function to get the float value of the val
+// attribute of the chart element.
+func getChartNodeFloat(n *chartTemplateNode) (float64, bool) {
+	val, err := strconv.ParseFloat(n.attr("val"), 64)
+	return val, err == nil
+}
+
+// getChartGroupSignature provides a function to get the signature of the
+// chart group in the plot area, which is used to distinguish the chart type.
+func getChartGroupSignature(group *chartTemplateNode) string {
+	barDir, shape := group.child("barDir").attr("val"), group.child("shape").attr("val")
+	if barDir == "" && strings.HasPrefix(group.Name.Local, "bar") {
+		barDir = "col"
+	}
+	if shape == "box" {
+		shape = ""
+	}
+	return strings.Join([]string{
+		group.Name.Local, barDir, group.child("grouping").attr("val"), shape,
+		group.child("ofPieType").attr("val"),
+		strconv.FormatBool(getChartNodeBool(group.child("wireframe"))),
+		strconv.FormatBool(group.child("upDownBars") != nil),
+		strconv.FormatBool(getChartNodeBool(group.path("ser", "bubble3D"))),
+	}, ",")
+}
+
+// getChartTypeSignatures provides a function to get the chart type by the
+// signatures of the chart groups in the plot area, the signatures of multiple
+// chart groups drawn for the same chart type will be joined by the plus sign.
+func (f *File) getChartTypeSignatures() map[string]string {
+	plotAreaFuncs, signatures := f.plotAreaFuncs(), map[string]string{}
+	chartTypes := make([]string, 0, len(plotAreaFuncs))
+	for chartType := range plotAreaFuncs {
+		chartTypes = append(chartTypes, chartType)
+	}
+	sort.Strings(chartTypes)
+	for _, chartType := range chartTypes {
+		formatSet, _ := parseFormatChartSet("{}")
+		formatSet.Type = chartType
+		count := 1
+		if n, ok := chartStockSeriesCount[chartType]; ok {
+			count = n
+		}
+		for i := 0; i < count; i++ {
+			formatSet.Series = append(formatSet.Series, formatChartSeries{Name: "Sheet1!$A$1", Categories: "Sheet1!$A$2:$A$3", Values: "Sheet1!$B$2:$B$3"})
+		}
+		plotArea, _ := xml.Marshal(plotAreaFuncs[chartType](formatSet))
+		cs, _ := decodeChartTemplateNode([]byte("<chartSpace><chart>" + string(plotArea) + "</chart></chartSpace>"))
+		var groups []string
+		for _, group := range cs.path("chart", "cPlotArea").children(isChartTemplateGroup) {
+			groups = append(groups, getChartGroupSignature(group))
+		}
+		if _, ok := signatures[strings.Join(groups, "+")]; !ok {
+			signatures[strings.Join(groups, "+")] = chartType
+		}
+	}
+	return signatures
+}
+
+// readChart provides a function to read the format settings of the chart and
+// the combo charts by given chart part, a nil format settings will be
+// returned if the type of the chart is unsupported.
+func (f *File) readChart(cs *chartTemplateNode) (*formatChart, []*formatChart) {
+	var (
+		formatSet   *formatChart
+		comboCharts []*formatChart
+		signatures  = f.getChartTypeSignatures()
+		plotArea    = cs.path("chart", "plotArea")
+		groups      = plotArea.children(isChartTemplateGroup)
+	)
+	for i := 0; i < len(groups); i++ {
+		chartGroups := groups[i : i+1]
+		chartType, ok := signatures[getChartGroupSignature(groups[i])]
+		if i+1 < len(groups) {
+			if pairType, matched := signatures[getChartGroupSignature(groups[i])+"+"+getChartGroupSignature(groups[i+1])]; matched {
+				chartType, ok, chartGroups = pairType, true, groups[i:i+2]
+				i++
+			}
+		}
+		if !ok {
+			if formatSet == nil {
+				return nil, nil
+			}
+			continue
+		}
+		chart, _ := parseFormatChartSet("{}")
+		chart.Type = chartType
+		for _, group := range chartGroups {
+			readChartGroup(group, chart)
+		}
+		if formatSet == nil {
+			formatSet = chart
+			readChartAxes(plotArea, chartGroups, formatSet)
+			continue
+		}
+		comboCharts = append(comboCharts, chart)
+	}
+	if formatSet == nil {
+		return nil, nil
+	}
+	readChartSpace(cs, formatSet)
+	return formatSet, comboCharts
+}
+
+// readChartGroup provides a function to read the series, data labels, gap
+// width and overlap settings of the chart by given chart group.
+func readChartGroup(group *chartTemplateNode, formatSet *formatChart) {
+	if varyColors := group.child("varyColors"); varyColors != nil {
+		formatSet.VaryColors = getChartNodeBool(varyColors)
+	}
+	if val, ok := getChartNodeFloat(group.child("gapWidth")); ok {
+		formatSet.GapWidth = intPtr(int(val))
+	}
+	if val, ok := getChartNodeFloat(group.child("overlap")); ok {
+		formatSet.Overlap = intPtr(int(val))
+	}
+	if dLbls := group.child("dLbls"); dLbls != nil {
+		formatSet.Legend.ShowLegendKey = getChartNodeBool(dLbls.child("showLegendKey"))
+		formatSet.Plotarea.ShowVal = getChartNodeBool(dLbls.child("showVal"))
+		formatSet.Plotarea.ShowCatName = getChartNodeBool(dLbls.child("showCatName"))
+		formatSet.Plotarea.ShowSerName = getChartNodeBool(dLbls.child("showSerName"))
+		formatSet.Plotarea.ShowBubbleSize = getChartNodeBool(dLbls.child("showBubbleSize"))
+		formatSet.Plotarea.ShowPercent = getChartNodeBool(dLbls.child("showPercent"))
+		formatSet.Plotarea.ShowLeaderLines = getChartNodeBool(dLbls.child("showLeaderLines"))
+	}
+	for _, ser := range group.children(func(node *chartTemplateNode) bool { return node.Name.Local == "ser" }) {
+		formatSet.Series = append(formatSet.Series, readChartSeries(ser))
+	}
+}
+
+// readChartSeries provides a function to read the format settings of the
+// chart series by given c:ser element.
+func readChartSeries(ser *chartTemplateNode) formatChartSeries {
+	var series formatChartSeries
+	ref := func(node *chartTemplateNode) string {
+		if f := getChartNodeText(node.path("strRef", "f")); f != "" {
+			return f
+		}
+		return getChartNodeText(node.path("numRef", "f"))
+	}
+	if series.Name = ref(ser.child("tx")); series.Name == "" {
+		series.Name = getChartNodeText(ser.path("tx", "v"))
+	}
+	if series.Categories = ref(ser.child("cat")); series.Categories == "" {
+		series.Categories = ref(ser.child("xVal"))
+	}
+	if series.Values = ref(ser.child("val")); series.Values == "" {
+		series.Values = ref(ser.child("yVal"))
+	}
+	series.Sizes = ref(ser.child("bubbleSize"))
+	spPr := ser.child("spPr")
+	if ln := spPr.child("ln"); ln != nil {
+		series.Line.None = ln.child("noFill") != nil
+		if series.Line.Color = ln.path("solidFill", "srgbClr").attr("val"); series.Line.Color != "" {
+			if w, err := strconv.Atoi(ln.attr("w")); err == nil {
+				series.Line.Width = float64(w) / 12700
+			}
+		}
+	}
+	series.Fill.None = spPr.child("noFill") != nil
+	series.Fill.Color = spPr.path("solidFill", "srgbClr").attr("val")
+	if marker := ser.child("marker"); marker != nil {
+		series.Marker.Symbol = marker.child("symbol").attr("val")
+		if size, ok := getChartNodeFloat(marker.child("size")); ok {
+			series.Marker.Size = int(size)
+		}
+	}
+	series.Smooth = getChartNodeBool(ser.child("smooth"))
+	return series
+}
+
+// readChartAxes provides a function to read the format settings of the
+// horizontal and vertical axes by given plot area and the chart groups of the
+// primary chart.
+func readChartAxes(plotArea *chartTemplateNode, groups []*chartTemplateNode, formatSet *formatChart) {
+	getAxis := func(group *chartTemplateNode, idx int) *chartTemplateNode {
+		axIDs := group.children(func(node *chartTemplateNode) bool { return node.Name.Local == "axId" })
+		if idx >= len(axIDs) {
+			return nil
+		}
+		for _, axis := range plotArea.children(isChartTemplateAxis) {
+			if axis.child("axId").attr("val") == axIDs[idx].attr("val") {
+				return axis
+			}
+		}
+		return nil
+	}
+	xAxis, yAxis := getAxis(groups[0], 0), getAxis(groups[len(groups)-1], 1)
+	if xAxis != nil {
+		readChartAxis(xAxis, &formatSet.XAxis)
+		formatSet.YAxis.Crossing = readChartAxisCrossing(xAxis)
+	}
+	if yAxis != nil {
+		readChartAxis(yAxis, &formatSet.YAxis)
+		formatSet.XAxis.Crossing = readChartAxisCrossing(yAxis)
+	}
+}
+
+// readChartAxis provides a function to read the format settings of the axis
+// by given c:catAx, c:dateAx or c:valAx element.
+func readChartAxis(axis *chartTemplateNode, opts *formatChartAxis) {
+	opts.None = getChartNodeBool(axis.child("delete"))
+	opts.DateAxis = axis.Name.Local == "dateAx"
+	opts.MajorGridlines = axis.child("majorGridlines") != nil
+	opts.MinorGridlines = axis.child("minorGridlines") != nil
+	scaling := axis.child("scaling")
+	opts.ReverseOrder = scaling.child("orientation").attr("val") == "maxMin"
+	opts.Maximum, _ = getChartNodeFloat(scaling.child("max"))
+	opts.Minimum, _ = getChartNodeFloat(scaling.child("min"))
+	opts.LogBase, _ = getChartNodeFloat(scaling.child("logBase"))
+	if numFmt := axis.child("numFmt"); numFmt != nil && numFmt.attr("sourceLinked") != "1" && numFmt.attr("sourceLinked") != "true" {
+		opts.NumFormat = numFmt.attr("formatCode")
+	}
+	if tickMark := axis.child("majorTickMark").attr("val"); tickMark != "none" {
+		opts.MajorTickMark = tickMark
+	}
+	if tickMark := axis.child("minorTickMark").attr("val"); tickMark != "none" {
+		opts.MinorTickMark = tickMark
+	}
+	opts.MajorUnit, _ = getChartNodeFloat(axis.child("majorUnit"))
+	opts.MinorUnit, _ = getChartNodeFloat(axis.child("minorUnit"))
+	opts.BaseUnit = axis.child("baseTimeUnit").attr("val")
+	opts.MajorUnitType = axis.child("majorTimeUnit").attr("val")
+	opts.MinorUnitType = axis.child("minorTimeUnit").attr("val")
+	if skip, ok := getChartNodeFloat(axis.child("tickLblSkip")); ok {
+		opts.TickLabelSkip = int(skip)
+	}
+	if dispUnits := axis.child("dispUnits"); dispUnits != nil {
+		for name, unit := range chartDisplayUnits {
+			if unit == dispUnits.child("builtInUnit").attr("val") {
+				opts.DisplayUnits = name
+			}
+		}
+		opts.DisplayUnitsVisible = dispUnits.child("dispUnitsLbl") != nil
+	}
+}
+
+// readChartAxisCrossing provides a function to read the crossing point of the
+// perpendicular axis on the axis by given axis element.
+func readChartAxisCrossing(axis *chartTemplateNode) string {
+	if crossesAt := axis.child("crossesAt"); crossesAt != nil {
+		return crossesAt.attr("val")
+	}
+	if crosses := axis.child("crosses").attr("val"); crosses == "max" || crosses == "min" {
+		return crosses
+	}
+	return ""
+}
+
+// readChartSpace provides a function to read the format settings of the
+// title, legend, chart area and plot area of the chart by given chart part.
+func readChartSpace(cs *chartTemplateNode, formatSet *formatChart) {
+	chart := cs.child("chart")
+	if title := chart.child("title"); title != nil {
+		var runs []RichTextRun
+		for _, p := range title.path("tx", "rich").children(func(node *chartTemplateNode) bool { return node.Name.Local == "p" }) {
+			for _, r := range p.children(func(node *chartTemplateNode) bool { return node.Name.Local == "r" }) {
+				runs = append(runs, RichTextRun{Text: getChartNodeText(r.child("t"))})
+			}
+		}
+		formatSet.Title.Name = getChartNodeText(title.path("tx", "strRef", "f"))
+		for _, run := range runs {
+			formatSet.Title.Name += run.Text
+		}
+		if len(runs) > 1 {
+			formatSet.Title.RichText = runs
+		}
+		formatSet.Title.Overlay = getChartNodeBool(title.child("overlay"))
+	} else {
+		formatSet.Title.None = true
+	}
+	if legend := chart.child("legend"); legend != nil {
+		formatSet.Legend.Position = "right"
+		for name, pos := range chartLegendPosition {
+			if pos == legend.child("legendPos").attr("val") {
+				formatSet.Legend.Position = name
+			}
+		}
+		for _, entry := range legend.children(func(node *chartTemplateNode) bool { return node.Name.Local == "legendEntry" }) {
+			if idx, ok := getChartNodeFloat(entry.child("idx")); ok && getChartNodeBool(entry.child("delete")) {
+				formatSet.Legend.DeleteSeries = append(formatSet.Legend.DeleteSeries, int(idx))
+			}
+		}
+		formatSet.Legend.Overlay = getChartNodeBool(legend.child("overlay"))
+	} else {
+		formatSet.Legend.None = true
+	}
+	if dispBlanksAs := chart.child("dispBlanksAs").attr("val"); dispBlanksAs != "" {
+		formatSet.ShowBlanksAs = dispBlanksAs
+	}
+	formatSet.Chartarea.Fill.Color = cs.path("spPr", "solidFill", "srgbClr").attr("val")
+	formatSet.Chartarea.Border.None = cs.path("spPr", "ln", "noFill") != nil
+	spPr := chart.path("plotArea", "spPr")
+	formatSet.Plotarea.Fill.Color = spPr.path("solidFill", "srgbClr").attr("val")
+	if formatSet.Plotarea.Border.Color = spPr.path("ln", "solidFill", "srgbClr").attr("val"); formatSet.Plotarea.Border.Color != "" {
+		if w, err := strconv.Atoi(spPr.child("ln").attr("w")); err == nil {
+			formatSet.Plotarea.Border.Width = w / 12700
+		}
+		formatSet.Plotarea.Border.DashType = spPr.path("ln", "prstDash").attr("val")
+	}
+}
+
+// readChartAnchor provides a function to read the offset, size, positioning
+// and object settings of the chart by given cell anchor.
+func (f *File) readChartAnchor(sheet string, cellAnchor *xdrCellAnchor, deAnchor *decodeTwoCellAnchor, formatSet *formatChart) {
+	formatSet.Format.Positioning = cellAnchor.EditAs
+	formatSet.Format.FLocksWithSheet, formatSet.Format.FPrintsWithSheet = true, true
+	if deAnchor.ClientData != nil {
+		if deAnchor.ClientData.FLocksWithSheet != nil {
+			formatSet.Format.FLocksWithSheet = *deAnchor.ClientData.FLocksWithSheet
+		}
+		if deAnchor.ClientData.FPrintsWithSheet != nil {
+			formatSet.Format.FPrintsWithSheet = *deAnchor.ClientData.FPrintsWithSheet
+		}
+	}
+	if deAnchor.From != nil {
+		formatSet.Format.OffsetX, formatSet.Format.OffsetY = deAnchor.From.ColOff/EMU, deAnchor.From.RowOff/EMU
+	}
+	if deAnchor.From != nil && deAnchor.To != nil {
+		formatSet.Dimension.Width = (f.getColOffsetEMU(sheet, deAnchor.To.Col) + deAnchor.To.ColOff - f.getColOffsetEMU(sheet, deAnchor.From.Col) - deAnchor.From.ColOff) / EMU
+		formatSet.Dimension.Height = (f.getRowOffsetEMU(sheet, deAnchor.To.Row) + deAnchor.To.RowOff - f.getRowOffsetEMU(sheet, deAnchor.From.Row) - deAnchor.From.RowOff) / EMU
+		return
+	}
+	if deAnchor.Ext != nil {
+		formatSet.Dimension.Width, formatSet.Dimension.Height = deAnchor.Ext.Cx/EMU, deAnchor.Ext.Cy/EMU
+	}
+}
+
+// setChartSeries provides a function to update the data references of the
+// series in the chart part by given series, the extra series will be
+// removed, and the chart groups without any series will be removed with the
+// axes only used by the chart groups.
+func setChartSeries(cs *chartTemplateNode, series []ChartSeries) error {
+	plotArea := cs.path("chart", "plotArea")
+	var (
+		sers, serGroups  []*chartTemplateNode
+		maxIdx, maxOrder int
+		isSer            = func(node *chartTemplateNode) bool { return node.Name.Local == "ser" }
+	)
+	for _, group := range plotArea.children(isChartTemplateGroup) {
+		for _, ser := range group.children(isSer) {
+			if idx, ok := getChartNodeFloat(ser.child("idx")); ok && int(idx) > maxIdx {
+				maxIdx = int(idx)
+			}
+			if order, ok := getChartNodeFloat(ser.child("order")); ok && int(order) > maxOrder {
+				maxOrder = int(order)
+			}
+			sers, serGroups = append(sers, ser), append(serGroups, group)
+		}
+	}
+	if len(sers) == 0 {
+		return ErrChartSeries
+	}
+	for i, s := range series {
+		if i >= len(sers) {
+			last, group := sers[len(sers)-1], serGroups[len(sers)-1]
+			ser := last.clone()
+			maxIdx, maxOrder = maxIdx+1, maxOrder+1
+			ser.child("idx").setAttr("val", strconv.Itoa(maxIdx))
+			ser.child("order").setAttr("val", strconv.Itoa(maxOrder))
+			for idx, node := range group.Nodes {
+				if node == last {
+					group.Nodes = append(group.Nodes[:idx+1], append([]*chartTemplateNode{ser}, group.Nodes[idx+1:]...)...)
+					break
+				}
+			}
+			sers, serGroups = append(sers, ser), append(serGroups, group)
+		}
+		setChartSeriesRefs(sers[i], s)
+	}
+	for i := len(series); i < len(sers); i++ {
+		for idx, node := range serGroups[i].Nodes {
+			if node == sers[i] {
+				serGroups[i].Nodes = append(serGroups[i].Nodes[:idx], serGroups[i].Nodes[idx+1:]...)
+				break
+			}
+		}
+	}
+	deleteEmptyChartGroups(plotArea)
+	return nil
+}
+
+// setChartSeriesRefs provides a function to set the data references of the
+// series by given c:ser element and the series, the caches of the updated
+// references will be removed.
+func setChartSeriesRefs(ser *chartTemplateNode, series ChartSeries) {
+	space, order := ser.Name.Space, chartTemplateOrder["ser"]
+	ref := func(name, kind, f string) *chartTemplateNode {
+		return &chartTemplateNode{Name: xml.Name{Space: space, Local: name}, Nodes: []*chartTemplateNode{
+			{Name: xml.Name{Space: space, Local: kind}, Nodes: []*chartTemplateNode{{Name: xml.Name{Space: space, Local: "f"}, Text: f}}},
+		}}
+	}
+	cat, val := "cat", "val"
+	if ser.child("xVal") != nil || ser.child("yVal") != nil {
+		cat, val = "xVal", "yVal"
+	}
+	if series.Name != "" {
+		ser.setChild(ref("tx", "strRef", series.Name), order)
+	}
+	if series.Categories != "" {
+		kind := "strRef"
+		if ser.path(cat, "numRef") != nil {
+			kind = "numRef"
+		}
+		ser.setChild(ref(cat, kind, series.Categories), order)
+	}
+	if series.Values != "" {
+		ser.setChild(ref(val, "numRef", series.Values), order)
+	}
+	if series.Sizes != "" {
+		ser.setChild(ref("bubbleSize", "numRef", series.Sizes), order)
+	}
+}
+
+// deleteEmptyChartGroups provides a function to delete the chart groups
+// without any series in the plot area, and the axes which only used by the
+// deleted chart groups.
+func deleteEmptyChartGroups(plotArea *chartTemplateNode) {
+	axIDs, deleted := map[string]bool{}, map[string]bool{}
+	nodes := plotArea.Nodes[:0]
+	for _, node := range plotArea.Nodes {
+		if !isChartTemplateGroup(node) {
+			nodes = append(nodes, node)
+			continue
+		}
+		empty := node.child("ser") == nil
+		for _, axID := range node.children(func(n *chartTemplateNode) bool { return n.Name.Local == "axId" }) {
+			if empty {
+				deleted[axID.attr("val")] = true
+				continue
+			}
+			axIDs[axID.attr("val")] = true
+		}
+		if !empty {
+			nodes = append(nodes, node)
+		}
+	}
+	plotArea.Nodes = nodes[:0]
+	for _, node := range nodes {
+		if id := node.child("axId").attr("val"); isChartTemplateAxis(node) && deleted[id] && !axIDs[id] {
+			continue
+		}
+		plotArea.Nodes = append(plotArea.Nodes, node)
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// chartTemplateNode directly maps an element in the chart part of the chart
+// template (.crtx) or the generated chart, with the namespace of the element
+// and attributes resolved.
+type chartTemplateNode struct {
+	Name  xml.Name
+	Attr  []xml.Attr
+	Nodes []*chartTemplateNode
+	Text  string
+	// prefixes holds the namespace prefixes declared in the part, only set
+	// on the root element.
+	prefixes map[string]string
+}
+
+// chartTemplateOrder defined the sequence of the child elements which could
+// be inserted into the generated chart by the chart template.
+var chartTemplateOrder = map[string][]string{
+	"chartSpace": {"date1904", "lang", "roundedCorners", "AlternateContent", "style", "clrMapOvr", "pivotSource", "protection", "chart", "spPr", "txPr", "externalData", "printSettings", "userShapes", "extLst"},
+	"chart":      {"title", "autoTitleDeleted", "pivotFmts", "view3D", "floor", "sideWall", "backWall", "plotArea", "legend", "plotVisOnly", "dispBlanksAs", "showDLblsOverMax", "extLst"},
+	"title":      {"tx", "layout", "overlay", "spPr", "txPr", "extLst"},
+	"rich":       {"bodyPr", "lstStyle", "p"},
+	"p":          {"pPr", "r", "br", "fld", "endParaRPr"},
+	"r":          {"rPr", "t"},
+	"legend":     {"legendPos", "legendEntry", "layout", "overlay", "spPr", "txPr", "extLst"},
+	"plotArea":   {"layout", "dTable", "spPr", "extLst"},
+	"wall":       {"thickness", "spPr", "pictureOptions", "extLst"},
+	"group":      {"barDir", "grouping", "varyColors", "ser", "dLbls", "gapWidth", "overlap", "serLines", "axId", "extLst"},
+	"axis":       {"axId", "scaling", "delete", "axPos", "majorGridlines", "minorGridlines", "title", "numFmt", "majorTickMark", "minorTickMark", "tickLblPos", "spPr", "txPr", "crossAx", "crosses", "crossesAt", "extLst"},
+	"ser":        {"idx", "order", "tx", "spPr", "invertIfNegative", "pictureOptions", "marker", "dPt", "dLbls", "trendline", "errBars", "cat", "val", "xVal", "yVal", "smooth", "bubbleSize", "bubble3D", "extLst"},
+}
+
+// chartTemplatePrefixes defined the namespace prefixes which will be used
+// when the merged chart part is serialized.
+var chartTemplatePrefixes = map[string]string{
+	NameSpaceDrawingMLChart.Value:          "c",
+	NameSpaceDrawingML.Value:               "a",
+	SourceRelationship.Value:               "r",
+	"http://www.w3.org/XML/1998/namespace": "xml",
+}
+
+// readChartTemplate provides a function to read the chart part in the chart
+// template (.crtx) by given path.
+func readChartTemplate(name string) (*chartTemplateNode, error) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	files, _, err := ReadZipReader(&r.Reader)
+	if err != nil {
+		return nil, err
+	}
+	chartXML := "chart/chart.xml"
+	if content, ok := files["[Content_Types].xml"]; ok {
+		var types xlsxTypes
+		if err = xml.Unmarshal(namespaceStrictToTransitional(content), &types); err != nil {
+			return nil, err
+		}
+		for _, override := range types.Overrides {
+			if strings.Contains(override.ContentType, "drawingml.chart") {
+				chartXML = strings.TrimPrefix(override.PartName, "/")
+				break
+			}
+		}
+	}
+	content, ok := files[chartXML]
+	if !ok {
+		return nil, ErrChartTemplate
+	}
+	return decodeChartTemplateNode(namespaceStrictToTransitional(content))
+}
+
+// decodeChartTemplateNode provides a function to decode the chart part into
+// the element tree.
+func decodeChartTemplateNode(content []byte) (*chartTemplateNode, error) {
+	var (
+		root     *chartTemplateNode
+		stack    []*chartTemplateNode
+		prefixes = map[string]string{}
+		decoder  = xml.NewDecoder(bytes.NewReader(content))
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &chartTemplateNode{Name: t.Name}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					prefixes[attr.Value] = attr.Name.Local
+					continue
+				}
+				if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.Attr = append(node.Attr, attr)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Nodes = append(parent.Nodes, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 && len(strings.TrimSpace(string(t))) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+	if root == nil || root.Name.Local != "chartSpace" {
+		return nil, ErrChartTemplate
+	}
+	root.prefixes = prefixes
+	return root, nil
+}
+
+// child provides a function to get the first child element by given local
+// name.
+func (n *chartTemplateNode) child(name string) *chartTemplateNode {
+	if n == nil {
+		return nil
+	}
+	for _, node := range n.Nodes {
+		if node.Name.Local == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// path provides a function to get the descendant element by given local
+// names of the first matched element on each level.
+func (n *chartTemplateNode) path(names ...string) *chartTemplateNode {
+	for _, name := range names {
+		n = n.child(name)
+	}
+	return n
+}
+
+// children provides a function to get the child elements which satisfy the
+// given condition.
+func (n *chartTemplateNode) children(fn func(node *chartTemplateNode) bool) []*chartTemplateNode {
+	var nodes []*chartTemplateNode
+	if n == nil {
+		return nodes
+	}
+	for _, node := range n.Nodes {
+		if fn(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// attr provides a function to get the value of the attribute by given local
+// name, an empty string will be returned if the attribute doesn't exist.
+func (n *chartTemplateNode) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, attr := range n.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// setAttr provides a function to set the value of the attribute by given
+// local name, the attribute will be added if it doesn't exist.
+func (n *chartTemplateNode) setAttr(name, value string) {
+	for idx, attr := range n.Attr {
+		if attr.Name.Local == name {
+			n.Attr[idx].Value = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+// clone provides a function to get a deep copy of the element tree.
+func (n *chartTemplateNode) clone() *chartTemplateNode {
+	node := &chartTemplateNode{Name: n.Name, Attr: append([]xml.Attr{}, n.Attr...), Text: n.Text}
+	for _, child := range n.Nodes {
+		node.Nodes = append(node.Nodes, child.clone())
+	}
+	return node
+}
+
+// setChild provides a function to replace the child element with the same
+// local name by given element, the element will be inserted by the given
+// sequence of the child elements if it doesn't exist.
+func (n *chartTemplateNode) setChild(src *chartTemplateNode, order []string) {
+	if n == nil || src == nil {
+		return
+	}
+	for idx, node := range n.Nodes {
+		if node.Name.Local == src.Name.Local {
+			n.Nodes[idx] = src
+			return
+		}
+	}
+	position := func(name string) int {
+		for idx, local := range order {
+			if local == name {
+				return idx
+			}
+		}
+		return -1
+	}
+	target := position(src.Name.Local)
+	for idx, node := range n.Nodes {
+		if p := position(node.Name.Local); p > target {
+			n.Nodes = append(n.Nodes[:idx], append([]*chartTemplateNode{src}, n.Nodes[idx:]...)...)
+			return
+		}
+	}
+	n.Nodes = append(n.Nodes, src)
+}
+
+// copyChildren provides a function to copy the child elements by given local
+// names from the source element.
+func (n *chartTemplateNode) copyChildren(src *chartTemplateNode, order []string, names ...string) {
+	if n == nil || src == nil {
+		return
+	}
+	for _, name := range names {
+		n.setChild(src.child(name), order)
+	}
+}
+
+// isChartTemplateGroup provides a function to check if the element is a
+// chart group in the plot area, such as barChart, lineChart and pieChart.
+func isChartTemplateGroup(node *chartTemplateNode) bool {
+	return strings.HasSuffix(node.Name.Local, "Chart")
+}
+
+// isChartTemplateAxis provides a function to check if the element is an axis
+// in the plot area.
+func isChartTemplateAxis(node *chartTemplateNode) bool {
+	return strings.HasSuffix(node.Name.Local, "Ax")
+}
+
+// applyChartTemplate provides a function to apply the formatting in the
+// chart template to the generated chart part. The chart area, plot area,
+// title, legend, walls, axes, chart groups and series formatting of the
+// template will be applied, and the data references of the generated chart
+// will be kept.
+func applyChartTemplate(chart []byte, tpl *chartTemplateNode) []byte {
+	cs, err := decodeChartTemplateNode(chart)
+	if err != nil {
+		return chart
+	}
+	order := chartTemplateOrder
+	// Chart space style, chart area fill, border and text properties.
+	style := tpl.child("style")
+	if style == nil {
+		style = tpl.path("AlternateContent", "Fallback", "style")
+	}
+	cs.setChild(style, order["chartSpace"])
+	cs.copyChildren(tpl, order["chartSpace"], "roundedCorners", "clrMapOvr", "spPr", "txPr")
+	chartNode, tplChart := cs.child("chart"), tpl.child("chart")
+	// Title.
+	if title, tplTitle := chartNode.child("title"), tplChart.child("title"); title != nil && tplTitle != nil {
+		title.copyChildren(tplTitle, order["title"], "overlay", "spPr", "txPr")
+		if rich, tplRich := title.path("tx", "rich"), tplTitle.path("tx", "rich"); rich != nil && tplRich != nil {
+			rich.copyChildren(tplRich, order["rich"], "bodyPr", "lstStyle")
+			if p, tplP := rich.child("p"), tplRich.child("p"); p != nil && tplP != nil {
+				p.copyChildren(tplP, order["p"], "pPr")
+				if tplRPr := tplP.path("r", "rPr"); tplRPr != nil {
+					for _, r := range p.children(func(node *chartTemplateNode) bool { return node.Name.Local == "r" }) {
+						r.setChild(tplRPr, order["r"])
+					}
+				}
+			}
+		}
+	}
+	// Floor and walls.
+	for _, wall := range []string{"floor", "sideWall", "backWall"} {
+		chartNode.child(wall).copyChildren(tplChart.child(wall), order["wall"], "spPr")
+	}
+	// Legend.
+	chartNode.child("legend").copyChildren(tplChart.child("legend"), order["legend"], "legendPos", "overlay", "spPr", "txPr")
+	// Plot area, chart groups, series and axes.
+	plotArea, tplPlotArea := chartNode.child("plotArea"), tplChart.child("plotArea")
+	if plotArea == nil || tplPlotArea == nil {
+		return cs.marshal(tpl.prefixes)
+	}
+	plotArea.copyChildren(tplPlotArea, order["plotArea"], "spPr")
+	groups, tplGroups := plotArea.children(isChartTemplateGroup), tplPlotArea.children(isChartTemplateGroup)
+	var tplSeries, tplSeriesGroups []*chartTemplateNode
+	for _, tplGroup := range tplGroups {
+		for _, ser := range tplGroup.children(func(node *chartTemplateNode) bool { return node.Name.Local == "ser" }) {
+			tplSeries, tplSeriesGroups = append(tplSeries, ser), append(tplSeriesGroups, tplGroup)
+		}
+	}
+	var idx int
+	for i, group := range groups {
+		if i < len(tplGroups) && tplGroups[i].Name.Local == group.Name.Local {
+			group.copyChildren(tplGroups[i], order["group"], "gapWidth", "overlap")
+		}
+		for _, ser := range group.children(func(node *chartTemplateNode) bool { return node.Name.Local == "ser" }) {
+			if idx < len(tplSeries) {
+				ser.copyChildren(tplSeries[idx], order["ser"], "spPr")
+				if tplSeriesGroups[idx].Name.Local == group.Name.Local {
+					ser.copyChildren(tplSeries[idx], order["ser"], "marker", "dLbls")
+				}
+			}
+			idx++
+		}
+	}
+	axisKind := func(node *chartTemplateNode) string {
+		if node.Name.Local == "dateAx" {
+			return "catAx"
+		}
+		return node.Name.Local
+	}
+	tplAxes := map[string][]*chartTemplateNode{}
+	for _, tplAxis := range tplPlotArea.children(isChartTemplateAxis) {
+		tplAxes[axisKind(tplAxis)] = append(tplAxes[axisKind(tplAxis)], tplAxis)
+	}
+	axes := map[string]int{}
+	for _, axis := range plotArea.children(isChartTemplateAxis) {
+		kind := axisKind(axis)
+		if axes[kind] < len(tplAxes[kind]) {
+			axis.copyChildren(tplAxes[kind][axes[kind]], order["axis"], "majorGridlines", "minorGridlines", "majorTickMark", "minorTickMark", "tickLblPos", "spPr", "txPr")
+		}
+		axes[kind]++
+	}
+	return cs.marshal(tpl.prefixes)
+}
+
+// marshal provides a function to serialize the element tree with the
+// namespace prefixes, the prefixes declared in the chart template will be
+// used for the namespaces which not defined in the chartTemplatePrefixes.
+func (n *chartTemplateNode) marshal(declared map[string]string) []byte {
+	prefixes, used := map[string]string{}, map[string]bool{}
+	for space, prefix := range chartTemplatePrefixes {
+		prefixes[space], used[prefix] = prefix, true
+	}
+	for space, prefix := range declared {
+		if _, ok := prefixes[space]; !ok && prefix != "" && !used[prefix] {
+			prefixes[space], used[prefix] = prefix, true
+		}
+	}
+	prefix := func(space string) string {
+		if p, ok := prefixes[space]; ok {
+			return p
+		}
+		p := fmt.Sprintf("ns%d", len(prefixes))
+		for used[p] {
+			p += "_"
+		}
+		prefixes[space], used[p] = p, true
+		return p
+	}
+	var body bytes.Buffer
+	n.marshalNode(&body, prefix)
+	var buf bytes.Buffer
+	buf.WriteString(`<c:chartSpace`)
+	for _, space := range []string{NameSpaceDrawingMLChart.Value, NameSpaceDrawingML.Value, SourceRelationship.Value} {
+		fmt.Fprintf(&buf, ` xmlns:%s="%s"`, prefixes[space], space)
+	}
+	var spaces []string
+	for space := range prefixes {
+		if _, ok := chartTemplatePrefixes[space]; !ok {
+			spaces = append(spaces, space)
+		}
+	}
+	sort.Strings(spaces)
+	for _, space := range spaces {
+		fmt.Fprintf(&buf, ` xmlns:%s="%s"`, prefixes[space], space)
+	}
+	// Skip the start tag of the root element written by the marshalNode.
+	content := body.Bytes()
+	buf.Write(content[len("<c:chartSpace"):])
+	return buf.Bytes()
+}
+
+// marshalNode provides a function to serialize the element and the child
+// elements.
+func (n *chartTemplateNode) marshalNode(buf *bytes.Buffer, prefix func(space string) string) {
+	name := n.Name.Local
+	if n.Name.Space != "" {
+		name = prefix(n.Name.Space) + ":" + name
+	}
+	buf.WriteString("<" + name)
+	for _, attr := range n.Attr {
+		attrName := attr.Name.Local
+		if attr.Name.Space != "" {
+			attrName = prefix(attr.Name.Space) + ":" + attrName
+		}
+		buf.WriteString(" " + attrName + `="`)
+		_ = xml.EscapeText(buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+	if len(n.Nodes) == 0 && n.Text == "" {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+	_ = xml.EscapeText(buf, []byte(n.Text))
+	for _, node := range n.Nodes {
+		node.marshalNode(buf, prefix)
+	}
+	buf.WriteString("</" + name + ">")
+}
@@ -0,0 +1,107 @@
+package excelize
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeChartTemplate creates a chart template file with the given parts.
+func writeChartTemplate(t *testing.T, name string, parts map[string]string) string {
+	path := filepath.Join("test", name)
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(file)
+	for name, content := range parts {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, file.Close())
+	return path
+}
+
+func TestAddChartTemplate(t *testing.T) {
+	tpl := writeChartTemplate(t, "TestAddChartTemplate.crtx", map[string]string{
+		"[Content_Types].xml": `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Override PartName="/chart/chart1.xml" ContentType="application/vnd.openxmlformats-officedocument.drawingml.chart+xml"/></Types>`,
+		"chart/chart1.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:c16r2="http://schemas.microsoft.com/office/drawing/2015/06/chart">
+	<c:roundedCorners val="1"/>
+	<mc:AlternateContent><mc:Choice Requires="c14"><c14:style xmlns:c14="http://schemas.microsoft.com/office/drawing/2007/8/2/chart" val="102"/></mc:Choice><mc:Fallback><c:style val="7"/></mc:Fallback></mc:AlternateContent>
+	<c:chart>
+		<c:title><c:tx><c:rich><a:bodyPr rot="0"/><a:lstStyle/><a:p><a:pPr><a:defRPr sz="1800" b="1"/></a:pPr><a:r><a:rPr lang="en-US" sz="1800" b="1"><a:solidFill><a:srgbClr val="1F3864"/></a:solidFill></a:rPr><a:t>Template</a:t></a:r></a:p></c:rich></c:tx><c:overlay val="0"/></c:title>
+		<c:plotArea>
+			<c:layout/>
+			<c:barChart><c:barDir val="col"/><c:grouping val="clustered"/><c:varyColors val="0"/>
+				<c:ser><c:idx val="0"/><c:order val="0"/><c:spPr><a:solidFill><a:srgbClr val="C00000"/></a:solidFill></c:spPr><c:invertIfNegative val="0"/><c:val><c:numRef><c:f>Template!$B$2:$D$2</c:f></c:numRef></c:val></c:ser>
+				<c:ser><c:idx val="1"/><c:order val="1"/><c:spPr><a:solidFill><a:srgbClr val="002060"/></a:solidFill></c:spPr><c:invertIfNegative val="0"/><c:val><c:numRef><c:f>Template!$B$3:$D$3</c:f></c:numRef></c:val></c:ser>
+				<c:gapWidth val="50"/><c:overlap val="-10"/><c:axId val="1"/><c:axId val="2"/>
+			</c:barChart>
+			<c:catAx><c:axId val="1"/><c:scaling><c:orientation val="minMax"/></c:scaling><c:delete val="0"/><c:axPos val="b"/><c:majorTickMark val="none"/><c:minorTickMark val="none"/><c:tickLblPos val="nextTo"/><c:spPr><a:ln w="9525"><a:solidFill><a:srgbClr val="BFBFBF"/></a:solidFill></a:ln></c:spPr><c:crossAx val="2"/></c:catAx>
+			<c:valAx><c:axId val="2"/><c:scaling><c:orientation val="minMax"/></c:scaling><c:delete val="0"/><c:axPos val="l"/><c:majorGridlines><c:spPr><a:ln w="9525"><a:solidFill><a:srgbClr val="D9D9D9"/></a:solidFill></a:ln></c:spPr></c:majorGridlines><c:majorTickMark val="none"/><c:minorTickMark val="none"/><c:tickLblPos val="nextTo"/><c:crossAx val="1"/></c:valAx>
+			<c:spPr><a:noFill/></c:spPr>
+		</c:plotArea>
+		<c:legend><c:legendPos val="t"/><c:overlay val="0"/><c:txPr><a:bodyPr/><a:lstStyle/><a:p><a:pPr><a:defRPr sz="900" c16r2:dummy="1"/></a:pPr><a:endParaRPr lang="en-US"/></a:p></c:txPr></c:legend>
+	</c:chart>
+	<c:spPr><a:solidFill><a:srgbClr val="F2F2F2"/></a:solidFill><a:ln><a:noFill/></a:ln></c:spPr>
+	<c:txPr><a:bodyPr/><a:lstStyle/><a:p><a:pPr><a:defRPr sz="1000"><a:latin typeface="Arial"/></a:defRPr></a:pPr><a:endParaRPr lang="en-US"/></a:p></c:txPr>
+	<c:externalData r:id="rId1"><c:autoUpdate val="0"/></c:externalData>
+</c:chartSpace>`,
+	})
+	f := NewFile()
+	for cell, v := range map[string]interface{}{"A2": "Small", "A3": "Normal", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, v))
+	}
+	format := `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"}],"title":{"name":"Fruit"},"template":"` + filepath.ToSlash(tpl) + `"}`
+	assert.NoError(t, f.AddChart("Sheet1", "E1", format))
+	assert.NoError(t, f.AddChartSheet("Chart1", format))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartTemplate.xlsx")))
+
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	chart := string(content.([]byte))
+	for _, expected := range []string{
+		`<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`,
+		`<c:roundedCorners val="1"/>`,
+		`<c:style val="7"/>`,
+		`<a:r><a:rPr lang="en-US" sz="1800" b="1"><a:solidFill><a:srgbClr val="1F3864"/></a:solidFill></a:rPr><a:t>Fruit</a:t></a:r>`,
+		`<c:spPr><a:solidFill><a:srgbClr val="C00000"/></a:solidFill></c:spPr>`,
+		`<c:spPr><a:solidFill><a:srgbClr val="002060"/></a:solidFill></c:spPr>`,
+		`<c:gapWidth val="50"/><c:overlap val="-10"/>`,
+		`<c:majorGridlines><c:spPr><a:ln w="9525"><a:solidFill><a:srgbClr val="D9D9D9"/></a:solidFill></a:ln></c:spPr></c:majorGridlines>`,
+		`<c:legendPos val="t"/>`,
+		`c16r2:dummy="1"`,
+		`<c:spPr><a:noFill/></c:spPr></c:plotArea>`,
+		`<c:spPr><a:solidFill><a:srgbClr val="F2F2F2"/></a:solidFill><a:ln><a:noFill/></a:ln></c:spPr>`,
+		`<a:latin typeface="Arial"/>`,
+		`<c:f>Sheet1!$B$2:$D$2</c:f>`,
+	} {
+		assert.Contains(t, chart, expected)
+	}
+	assert.NotContains(t, chart, "Template")
+	assert.NotContains(t, chart, "externalData")
+	assert.Equal(t, 1, strings.Count(chart, "<c:style "))
+	_, err := decodeChartTemplateNode([]byte(chart))
+	assert.NoError(t, err)
+
+	// Test add chart with not exists chart template.
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"template":"`+filepath.ToSlash(filepath.Join("test", "NotExists.crtx"))+`"}`), "open test/NotExists.crtx: no such file or directory")
+	// Test add chart with the chart template without chart part.
+	tpl = writeChartTemplate(t, "TestAddChartTemplate2.crtx", map[string]string{"chart/colors1.xml": `<cs:colorStyle xmlns:cs="http://schemas.microsoft.com/office/drawing/2012/chartStyle"/>`})
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"template":"`+filepath.ToSlash(tpl)+`"}`), ErrChartTemplate.Error())
+	// Test add chart with the chart template with invalid chart part.
+	tpl = writeChartTemplate(t, "TestAddChartTemplate3.crtx", map[string]string{"chart/chart.xml": `<c:chartSpace`})
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"template":"`+filepath.ToSlash(tpl)+`"}`), "XML syntax error on line 1: unexpected EOF")
+	tpl = writeChartTemplate(t, "TestAddChartTemplate4.crtx", map[string]string{"chart/chart.xml": `<worksheet/>`})
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"template":"`+filepath.ToSlash(tpl)+`"}`), ErrChartTemplate.Error())
+	tpl = writeChartTemplate(t, "TestAddChartTemplate5.crtx", map[string]string{"[Content_Types].xml": `<Types`})
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"template":"`+filepath.ToSlash(tpl)+`"}`), "XML syntax error on line 1: unexpected EOF")
+	// Test apply chart template on invalid chart part.
+	assert.Equal(t, []byte("<c:chartSpace"), applyChartTemplate([]byte("<c:chartSpace"), &chartTemplateNode{}))
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -208,6 +210,475 @@ func TestAddChart(t *testing.T) {
 	assert.EqualError(t, f.AddChart("Sheet2", "BD64", `{"type":"barOfPie","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`, `{"type":"unknown","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`), "unsupported chart type unknown")
 }
 
+func TestAddChartSeriesFormat(t *testing.T) {
+	f := NewFile()
+	for cell, v := range map[string]interface{}{"A2": "Small", "A3": "Normal", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","fill":{"color":"#1f3864"},"line":{"color":"#000000","width":1},"points":[{"index":1,"fill":{"color":"#C00000"},"border":{"color":"#FFFFFF","width":2}}]},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3","fill":{"none":true},"line":{"none":true}}],"gap_width":50,"overlap":-20}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"line","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","line":{"color":"#9BBB59","width":3},"smooth":true,"marker":{"symbol":"square","size":8,"width":1.5,"fill":{"color":"#FFFFFF"},"border":{"color":"#4F81BD"}},"points":[{"index":2,"fill":{"color":"#C00000"}}]},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3","line":{"none":true},"marker":{"fill":{"none":true},"border":{"none":true}}}],"gap_width":50}`))
+	assert.NoError(t, f.AddChart("Sheet1", "M1", `{"type":"pie","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","points":[{"index":0,"fill":{"color":"#C00000"}},{"index":2,"fill":{"color":"#002060"}}]}]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "M16", `{"type":"scatter","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","line":{"color":"#C00000"},"smooth":true}]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "U1", `{"type":"col3DClustered","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"gap_width":0,"overlap":100}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartSeriesFormat.xlsx")))
+
+	for name, expected := range map[string][]string{
+		// Test bar chart series fill, border, data points, gap width and overlap.
+		"xl/charts/chart1.xml": {
+			`<spPr><a:solidFill><a:srgbClr val="1F3864"></a:srgbClr></a:solidFill><a:ln w="12700"><a:solidFill><a:srgbClr val="000000"></a:srgbClr></a:solidFill></a:ln></spPr>`,
+			`<dPt><idx val="1"></idx><spPr><a:solidFill><a:srgbClr val="C00000"></a:srgbClr></a:solidFill><a:ln w="25400"><a:solidFill><a:srgbClr val="FFFFFF"></a:srgbClr></a:solidFill></a:ln></spPr></dPt>`,
+			`<spPr><a:noFill></a:noFill><a:ln><a:noFill> </a:noFill></a:ln></spPr>`,
+			`<gapWidth val="50"></gapWidth><overlap val="-20"></overlap>`,
+		},
+		// Test line chart series line, smooth, marker and data points.
+		"xl/charts/chart2.xml": {
+			`<spPr><a:ln cap="rnd" w="38100"><a:solidFill><a:srgbClr val="9BBB59"></a:srgbClr></a:solidFill></a:ln></spPr>`,
+			`<marker><symbol val="square"></symbol><size val="8"></size><spPr><a:solidFill><a:srgbClr val="FFFFFF"></a:srgbClr></a:solidFill><a:ln w="19050"><a:solidFill><a:srgbClr val="4F81BD"></a:srgbClr></a:solidFill></a:ln></spPr></marker>`,
+			`<dPt><idx val="2"></idx><marker><spPr><a:solidFill><a:srgbClr val="C00000"></a:srgbClr></a:solidFill></spPr></marker></dPt>`,
+			`<smooth val="true"></smooth></ser>`,
+			`<spPr><a:ln cap="rnd" w="25400"><a:noFill> </a:noFill></a:ln></spPr>`,
+			`<spPr><a:noFill></a:noFill><a:ln w="9252"><a:noFill> </a:noFill></a:ln></spPr></marker>`,
+		},
+		// Test pie chart data points.
+		"xl/charts/chart3.xml": {
+			`<dPt><idx val="0"></idx><bubble3D val="false"></bubble3D><spPr><a:solidFill><a:srgbClr val="C00000"></a:srgbClr></a:solidFill></spPr></dPt>`,
+			`<dPt><idx val="2"></idx><bubble3D val="false"></bubble3D><spPr><a:solidFill><a:srgbClr val="002060"></a:srgbClr></a:solidFill></spPr></dPt>`,
+		},
+		// Test scatter chart series line.
+		"xl/charts/chart4.xml": {
+			`<spPr><a:ln cap="rnd" w="25400"><a:solidFill><a:srgbClr val="C00000"></a:srgbClr></a:solidFill></a:ln></spPr>`,
+			`<smooth val="true"></smooth>`,
+		},
+		// Test 3D column chart without overlap.
+		"xl/charts/chart5.xml": {`<gapWidth val="0"></gapWidth>`},
+	} {
+		content, ok := f.Pkg.Load(name)
+		assert.True(t, ok)
+		for _, str := range expected {
+			assert.Contains(t, string(content.([]byte)), str)
+		}
+	}
+	content, ok := f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	assert.NotContains(t, string(content.([]byte)), "gapWidth")
+	assert.Equal(t, 1, bytes.Count(content.([]byte), []byte(`<smooth val="true">`)))
+	content, ok = f.Pkg.Load("xl/charts/chart5.xml")
+	assert.True(t, ok)
+	assert.NotContains(t, string(content.([]byte)), "overlap")
+
+	// Test add chart with invalid gap width, overlap and colors.
+	assert.EqualError(t, f.AddChart("Sheet1", "A32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"gap_width":501}`), ErrChartGapWidth.Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "A32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"overlap":-101}`), ErrChartOverlap.Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "A32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","fill":{"color":"#FF00"}}]}`), `invalid color "#FF00"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "A32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`, `{"type":"line","series":[{"values":"Sheet1!$B$2:$D$2","points":[{"index":0,"border":{"color":"FFFFFFFF"}}]}]}`), `invalid color "FFFFFFFF"`)
+}
+
+func TestAddChartAxisFormat(t *testing.T) {
+	f := NewFile()
+	for cell, v := range map[string]interface{}{"A2": "Sales", "B1": 44197, "C1": 44228, "D1": 44256, "B2": 2000000, "C2": 3500000, "D2": 3000000} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"line","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"x_axis":{"date_axis":true,"base_unit":"days","major_unit":1,"major_unit_type":"months","minor_unit":7,"minor_unit_type":"days","num_format":"mmm yyyy","major_tick_mark":"out","minor_tick_mark":"in","num_font":{"rotation":-45,"color":"#FF0000","bold":true,"italic":true,"underline":true},"crossing":"max"},"y_axis":{"major_unit":1000000,"minor_unit":250000,"display_units":"millions","display_units_visible":true,"major_tick_mark":"cross","num_font":{"rotation":0},"crossing":"1500000"}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"x_axis":{"crossing":"2","major_unit":1},"y_axis":{"display_units":"thousands","crossing":"min"}}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartAxisFormat.xlsx")))
+
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	chart := string(content.([]byte))
+	for _, expected := range []string{
+		`<dateAx><axId val="754001152"></axId>`,
+		`<numFmt formatCode="mmm yyyy" sourceLinked="false"></numFmt><majorTickMark val="out"></majorTickMark><minorTickMark val="in"></minorTickMark>`,
+		`<a:bodyPr anchor="ctr" anchorCtr="true" rot="-2700000"`,
+		`<a:defRPr b="true" baseline="0" i="true" kern="1200" spc="0" strike="noStrike" sz="900" u="sng"><a:solidFill><a:srgbClr val="FF0000"></a:srgbClr></a:solidFill>`,
+		`<crossAx val="753999904"></crossAx><crossesAt val="1.5e+06"></crossesAt><auto val="true"></auto><lblOffset val="100"></lblOffset><baseTimeUnit val="days"></baseTimeUnit><majorUnit val="1"></majorUnit><majorTimeUnit val="months"></majorTimeUnit><minorUnit val="7"></minorUnit><minorTimeUnit val="days"></minorTimeUnit></dateAx>`,
+		`<majorTickMark val="cross"></majorTickMark><minorTickMark val="none"></minorTickMark>`,
+		`<a:bodyPr anchor="ctr" anchorCtr="true" rot="0"`,
+		`<crossAx val="754001152"></crossAx><crosses val="max"></crosses><crossBetween val="between"></crossBetween><majorUnit val="1e+06"></majorUnit><minorUnit val="250000"></minorUnit><dispUnits><builtInUnit val="millions"></builtInUnit><dispUnitsLbl></dispUnitsLbl></dispUnits></valAx>`,
+	} {
+		assert.Contains(t, chart, expected)
+	}
+	assert.NotContains(t, chart, "<catAx>")
+	assert.NotContains(t, chart, "lblAlgn")
+
+	content, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	chart = string(content.([]byte))
+	for _, expected := range []string{
+		`<crossAx val="753999904"></crossAx><crosses val="min"></crosses><auto val="true"></auto><lblAlgn val="ctr"></lblAlgn>`,
+		`<crossAx val="754001152"></crossAx><crossesAt val="2"></crossesAt><crossBetween val="between"></crossBetween><dispUnits><builtInUnit val="thousands"></builtInUnit></dispUnits></valAx>`,
+	} {
+		assert.Contains(t, chart, expected)
+	}
+	assert.NotContains(t, chart, "<dateAx>")
+	assert.NotContains(t, chart, "majorUnit")
+
+	// Test add chart with invalid axis format settings.
+	for _, axis := range []struct{ format, err string }{
+		{`"x_axis":{"major_tick_mark":"top"}`, `invalid major_tick_mark "top"`},
+		{`"y_axis":{"minor_tick_mark":"top"}`, `invalid minor_tick_mark "top"`},
+		{`"x_axis":{"base_unit":"hours"}`, `invalid base_unit "hours"`},
+		{`"x_axis":{"major_unit_type":"hours"}`, `invalid major_unit_type "hours"`},
+		{`"x_axis":{"minor_unit_type":"hours"}`, `invalid minor_unit_type "hours"`},
+		{`"y_axis":{"display_units":"thousand"}`, `invalid display_units "thousand"`},
+		{`"y_axis":{"crossing":"center"}`, `invalid crossing "center"`},
+		{`"y_axis":{"num_font":{"rotation":91}}`, `invalid rotation "91"`},
+		{`"y_axis":{"num_font":{"color":"red"}}`, `invalid color "red"`},
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "E32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],`+axis.format+`}`), axis.err)
+	}
+}
+
+func TestAddChartSecondaryAxis(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Month", "Sales", "Cost", "Growth"},
+		{"Jan", 1200, 800, 0.12},
+		{"Feb", 1500, 900, 0.25},
+		{"Mar", 1300, 1000, -0.13},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(idx+1), &row))
+	}
+	series := func(col string, y2 bool) string {
+		return fmt.Sprintf(`{"name":"Sheet1!$%[1]s$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$%[1]s$2:$%[1]s$4","y2_axis":%[2]t}`, col, y2)
+	}
+	// Test add combo chart with the line series on the secondary axis.
+	assert.NoError(t, f.AddChart("Sheet1", "F1", `{"type":"col","series":[`+series("B", false)+`,`+series("C", false)+`],"x_axis":{"name":"Month"},"y_axis":{"name":"Amount"},"y2_axis":{"name":"Growth","num_format":"0%","maximum":1,"minimum":-0.5,"major_grid_lines":true}}`,
+		`{"type":"line","series":[`+series("D", true)+`]}`))
+	// Test add chart with the series of the same chart type on both axes.
+	assert.NoError(t, f.AddChart("Sheet1", "F16", `{"type":"col","series":[`+series("B", false)+`,`+series("D", true)+`],"y2_axis":{"reverse_order":true,"crossing":"0"}}`))
+	// Test add chart with all series on the secondary axis.
+	assert.NoError(t, f.AddChart("Sheet1", "F31", `{"type":"line","series":[`+series("B", true)+`]}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartSecondaryAxis.xlsx")))
+
+	load := func(name string) string {
+		content, ok := f.Pkg.Load(name)
+		assert.True(t, ok)
+		return string(content.([]byte))
+	}
+	chart := load("xl/charts/chart1.xml")
+	assert.Contains(t, chart, `<axId val="754001152"></axId><axId val="753999904"></axId></barChart><lineChart>`)
+	assert.Contains(t, chart, `<idx val="2"></idx><order val="2"></order><tx><strRef><f>Sheet1!$D$1</f></strRef></tx>`)
+	assert.Contains(t, chart, `<axId val="754001154"></axId><axId val="753999906"></axId></lineChart><catAx>`)
+	assert.Contains(t, chart, `<catAx><axId val="754001154"></axId><scaling><orientation val="minMax"></orientation></scaling><delete val="true"></delete>`)
+	assert.Contains(t, chart, `<valAx><axId val="753999906"></axId><scaling><orientation val="minMax"></orientation><max val="1"></max><min val="-0.5"></min></scaling><delete val="false"></delete><axPos val="r"></axPos><majorGridlines>`)
+	assert.Contains(t, chart, `<a:t>Growth</a:t>`)
+	assert.Contains(t, chart, `<numFmt formatCode="0%" sourceLinked="false"></numFmt>`)
+	assert.Contains(t, chart, `<crossAx val="754001154"></crossAx><crosses val="max"></crosses>`)
+	assert.Equal(t, 1, strings.Count(chart, `<a:t>Month</a:t>`))
+	assert.Equal(t, 1, strings.Count(chart, `<a:t>Amount</a:t>`))
+	assert.Equal(t, 2, strings.Count(chart, "<catAx>"))
+	assert.Equal(t, 2, strings.Count(chart, "<valAx>"))
+
+	chart = load("xl/charts/chart2.xml")
+	assert.Equal(t, 2, strings.Count(chart, "<barChart>"))
+	assert.Contains(t, chart, `<idx val="0"></idx><order val="0"></order><tx><strRef><f>Sheet1!$B$1</f></strRef></tx>`)
+	assert.Contains(t, chart, `<idx val="1"></idx><order val="1"></order><tx><strRef><f>Sheet1!$D$1</f></strRef></tx>`)
+	assert.Contains(t, chart, `<orientation val="maxMin"></orientation></scaling><delete val="false"></delete><axPos val="l"></axPos>`)
+	assert.Contains(t, chart, `<crossAx val="753999906"></crossAx><crossesAt val="0"></crossesAt>`)
+
+	chart = load("xl/charts/chart3.xml")
+	assert.NotContains(t, chart, `754001154`)
+	assert.Equal(t, 1, strings.Count(chart, "<valAx>"))
+
+	// Test add chart with the secondary axis on unsupported chart types.
+	for _, chartType := range []string{Pie, Doughnut, Col3D, StockHighLowClose, Contour} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"`+chartType+`","series":[`+series("B", false)+`,`+series("C", true)+`,`+series("D", false)+`]}`), ErrChartSecondaryAxis.Error())
+	}
+	// Test add chart with invalid secondary axis format settings.
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[`+series("B", true)+`],"y2_axis":{"crossing":"center"}}`), `invalid crossing "center"`)
+}
+
+func TestAddChartTrendlineErrorBars(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"X", "Y", "Plus", "Minus"},
+		{1, 2.1, 0.2, 0.1},
+		{2, 3.9, 0.3, 0.2},
+		{3, 6.2, 0.2, 0.3},
+		{4, 7.8, 0.4, 0.1},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(idx+1), &row))
+	}
+	series := func(opts string) string {
+		return `{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$5","values":"Sheet1!$B$2:$B$5"` + opts + `}`
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "F1", `{"type":"scatter","series":[`+
+		series(`,"trendline":{"type":"polynomial","order":3,"name":"Fit","display_equation":true,"display_r_squared":true,"intercept":0,"forward":0.5,"line":{"color":"#FF0000","width":1.5}},"x_error_bars":{"type":"standard_error"},"y_error_bars":{"type":"custom","direction":"plus","plus_values":"Sheet1!$C$2:$C$5","minus_values":"Sheet1!$D$2:$D$5","no_end_cap":true}`)+`]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "F16", `{"type":"col","series":[`+
+		series(`,"trendline":{"type":"moving_average","period":3,"forward":1},"y_error_bars":{"type":"percentage","value":5}`)+`,`+
+		series(`,"trendline":{"type":"linear"},"y_error_bars":{"type":"fixed"}`)+`]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "F31", `{"type":"colStacked","series":[`+series(`,"y_error_bars":{"type":"standard_deviation","direction":"minus","value":2}`)+`]}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartTrendlineErrorBars.xlsx")))
+
+	load := func(name string) string {
+		content, ok := f.Pkg.Load(name)
+		assert.True(t, ok)
+		return string(content.([]byte))
+	}
+	chart := load("xl/charts/chart1.xml")
+	assert.Contains(t, chart, `</marker><trendline><name>Fit</name><spPr><a:ln cap="rnd" w="19050"><a:solidFill><a:srgbClr val="FF0000"></a:srgbClr></a:solidFill></a:ln></spPr><trendlineType val="poly"></trendlineType><order val="3"></order><forward val="0.5"></forward><intercept val="0"></intercept><dispRSqr val="true"></dispRSqr><dispEq val="true"></dispEq><trendlineLbl><numFmt formatCode="General" sourceLinked="false"></numFmt></trendlineLbl></trendline>`)
+	assert.Contains(t, chart, `<errBars><errDir val="x"></errDir><errBarType val="both"></errBarType><errValType val="stdErr"></errValType><noEndCap val="false"></noEndCap></errBars>`)
+	assert.Contains(t, chart, `<errBars><errDir val="y"></errDir><errBarType val="plus"></errBarType><errValType val="cust"></errValType><noEndCap val="true"></noEndCap><plus><numRef><f>Sheet1!$C$2:$C$5</f></numRef></plus><minus><numRef><f>Sheet1!$D$2:$D$5</f></numRef></minus></errBars><xVal>`)
+
+	chart = load("xl/charts/chart2.xml")
+	assert.Contains(t, chart, `<trendline><trendlineType val="movingAvg"></trendlineType><period val="3"></period><dispRSqr val="false"></dispRSqr><dispEq val="false"></dispEq></trendline>`)
+	assert.Contains(t, chart, `<errBars><errBarType val="both"></errBarType><errValType val="percentage"></errValType><noEndCap val="false"></noEndCap><val val="5"></val></errBars><cat>`)
+	assert.Contains(t, chart, `<trendline><trendlineType val="linear"></trendlineType><dispRSqr val="false"></dispRSqr><dispEq val="false"></dispEq></trendline>`)
+	assert.Contains(t, chart, `<errValType val="fixedVal"></errValType><noEndCap val="false"></noEndCap><val val="1"></val>`)
+
+	chart = load("xl/charts/chart3.xml")
+	assert.NotContains(t, chart, "<trendline>")
+	assert.Contains(t, chart, `<errBarType val="minus"></errBarType><errValType val="stdDev"></errValType><noEndCap val="false"></noEndCap><val val="2"></val>`)
+
+	// Test add chart with invalid trendline and error bars settings.
+	for opts, expected := range map[string]string{
+		`"trendline":{"type":"linear"}`:   ErrChartTrendline.Error(),
+		`"y_error_bars":{"type":"fixed"}`: ErrChartErrorBars.Error(),
+		`"x_error_bars":{"type":"fixed"}`: ErrChartErrorBars.Error(),
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"pie","series":[`+series(","+opts)+`]}`), expected)
+	}
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"colStacked","series":[`+series(`,"trendline":{"type":"linear"}`)+`]}`), ErrChartTrendline.Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[`+series(`,"x_error_bars":{"type":"fixed"}`)+`]}`), ErrChartErrorBars.Error())
+	for opts, expected := range map[string]string{
+		`"trendline":{"type":"cubic"}`:                             `invalid trendline type "cubic"`,
+		`"trendline":{"type":"polynomial","order":7}`:              ErrChartTrendlineOrder.Error(),
+		`"trendline":{"type":"moving_average","period":1}`:         ErrChartTrendlinePeriod.Error(),
+		`"trendline":{"type":"linear","line":{"color":"red"}}`:     `invalid color "red"`,
+		`"y_error_bars":{"type":"range"}`:                          `invalid error bars type "range"`,
+		`"y_error_bars":{"type":"fixed","direction":"up"}`:         `invalid error bars direction "up"`,
+		`"y_error_bars":{"type":"custom"}`:                         ErrParameterRequired.Error(),
+		`"x_error_bars":{"type":"fixed","line":{"color":"#FF00"}}`: `invalid color "#FF00"`,
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"scatter","series":[`+series(","+opts)+`]}`), expected)
+	}
+}
+
+func TestAddChartTitleLegendPlotArea(t *testing.T) {
+	f := NewFile()
+	for cell, v := range map[string]interface{}{"A1": "Fruit Sales", "A2": "Small", "A3": "Normal", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, v))
+	}
+	series := `[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"}]`
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":`+series+`,"title":{"rich_text":[{"text":"Fruit ","font":{"bold":true,"size":16,"family":"Arial","color":"#1F3864"}},{"text":"Sales","font":{"italic":true,"underline":"single","strike":true}}],"font":{"color":"#595959"},"overlay":true,"fill":{"color":"#F2F2F2"},"border":{"color":"#000000","width":1}},"legend":{"position":"top","overlay":true,"delete_series":[1],"font":{"size":8,"color":"#7F7F7F"}},"plotarea":{"fill":{"color":"#FFFFE1"},"border":{"color":"#BFBFBF","width":2,"dash_type":"dash"}},"chartarea":{"fill":{"color":"#EEECE1"},"border":{"none":true}}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"col","series":`+series+`,"title":{"name":"=Sheet1!$A$1","font":{"bold":true,"underline":"double"},"border":{"none":true}}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "M1", `{"type":"col","series":`+series+`,"title":{"none":true},"legend":{"none":true}}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartTitleLegendPlotArea.xlsx")))
+
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	chart := string(content.([]byte))
+	for _, expected := range []string{
+		`<a:defRPr b="false" baseline="0" i="false" kern="1200" spc="0" strike="noStrike" sz="1400" u="none"><a:solidFill><a:srgbClr val="595959"></a:srgbClr></a:solidFill>`,
+		`<a:r><a:rPr altLang="en-US" b="true" baseline="0" i="false" kern="0" lang="en-US" spc="0" sz="1600"><a:solidFill><a:srgbClr val="1F3864"></a:srgbClr></a:solidFill><a:latin typeface="Arial"></a:latin><a:ea typeface="Arial"></a:ea><a:cs typeface="Arial"></a:cs></a:rPr><a:t>Fruit </a:t></a:r>`,
+		`<a:r><a:rPr altLang="en-US" b="false" baseline="0" i="true" kern="0" lang="en-US" spc="0" strike="sngStrike" u="sng"></a:rPr><a:t>Sales</a:t></a:r>`,
+		`<overlay val="true"></overlay><spPr><a:solidFill><a:srgbClr val="F2F2F2"></a:srgbClr></a:solidFill><a:ln w="12700"><a:solidFill><a:srgbClr val="000000"></a:srgbClr></a:solidFill></a:ln></spPr>`,
+		`<legend><legendPos val="t"></legendPos><legendEntry><idx val="1"></idx><delete val="true"></delete></legendEntry><overlay val="true"></overlay>`,
+		`<a:defRPr b="false" baseline="0" i="false" kern="1200" spc="0" strike="noStrike" sz="800" u="none"><a:solidFill><a:srgbClr val="7F7F7F"></a:srgbClr></a:solidFill></a:defRPr>`,
+		`<spPr><a:solidFill><a:srgbClr val="FFFFE1"></a:srgbClr></a:solidFill><a:ln w="25400"><a:solidFill><a:srgbClr val="BFBFBF"></a:srgbClr></a:solidFill><a:prstDash val="dash"></a:prstDash></a:ln></spPr></plotArea>`,
+		`</chart><spPr><a:solidFill><a:srgbClr val="EEECE1"></a:srgbClr></a:solidFill><a:ln><a:noFill> </a:noFill></a:ln></spPr>`,
+	} {
+		assert.Contains(t, chart, expected)
+	}
+
+	content, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	chart = string(content.([]byte))
+	for _, expected := range []string{
+		`<title><tx><strRef><f>Sheet1!$A$1</f></strRef></tx>`,
+		`<spPr><a:ln><a:noFill> </a:noFill></a:ln></spPr><txPr><a:bodyPr anchorCtr="false" rot="0" spcFirstLastPara="false"></a:bodyPr><a:p><a:pPr><a:defRPr b="true" baseline="0" i="false" kern="1200" spc="0" strike="noStrike" sz="1400" u="dbl">`,
+	} {
+		assert.Contains(t, chart, expected)
+	}
+	assert.NotContains(t, chart, "<rich>")
+
+	content, ok = f.Pkg.Load("xl/charts/chart3.xml")
+	assert.True(t, ok)
+	chart = string(content.([]byte))
+	assert.Contains(t, chart, `<autoTitleDeleted val="true"></autoTitleDeleted>`)
+	assert.NotContains(t, chart, "<title>")
+	assert.NotContains(t, chart, "<legend>")
+
+	// Test add chart with invalid title, legend and plot area format settings.
+	for _, format := range []struct{ format, err string }{
+		{`"title":{"fill":{"color":"#FF"}}`, `invalid color "#FF"`},
+		{`"title":{"font":{"color":"#FF"}}`, `invalid color "#FF"`},
+		{`"title":{"rich_text":[{"text":"A","font":{"color":"#FF"}}]}`, `invalid color "#FF"`},
+		{`"legend":{"font":{"color":"#FF"}}`, `invalid color "#FF"`},
+		{`"plotarea":{"border":{"color":"#FF"}}`, `invalid color "#FF"`},
+		{`"plotarea":{"border":{"dash_type":"dotted"}}`, `invalid dash_type "dotted"`},
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "E32", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],`+format.format+`}`), format.err)
+	}
+	assert.False(t, isChartCellRef("!A1"))
+	assert.False(t, isChartCellRef("Hello!World"))
+	assert.True(t, isChartCellRef("'Sheet 1'!$A$1"))
+}
+
+func TestAddChartStockBubble(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Date", "Volume", "Open", "High", "Low", "Close"},
+		{"2021-01-04", 1500, 25.2, 27.1, 24.8, 26.5},
+		{"2021-01-05", 1200, 26.5, 26.9, 25.1, 25.4},
+		{"2021-01-06", 1800, 25.4, 28.3, 25.0, 28.1},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(idx+1), &row))
+	}
+	series := func(cols ...string) string {
+		var s []string
+		for _, col := range cols {
+			s = append(s, fmt.Sprintf(`{"name":"Sheet1!$%[1]s$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$%[1]s$2:$%[1]s$4"}`, col))
+		}
+		return "[" + strings.Join(s, ",") + "]"
+	}
+	for idx, format := range []string{
+		`{"type":"stockHighLowClose","series":` + series("D", "E", "F") + `}`,
+		`{"type":"stockOpenHighLowClose","series":` + series("C", "D", "E", "F") + `}`,
+		`{"type":"stockVolumeHighLowClose","series":` + series("B", "D", "E", "F") + `}`,
+		`{"type":"stockVolumeOpenHighLowClose","series":` + series("B", "C", "D", "E", "F") + `,"y_axis":{"maximum":30}}`,
+		`{"type":"bubble","series":[{"name":"Sheet1!$F$1","categories":"Sheet1!$F$2:$F$4","values":"Sheet1!$C$2:$C$4","sizes":"Sheet1!$B$2:$B$4"},{"name":"Sheet1!$D$1","categories":"Sheet1!$D$2:$D$4","values":"Sheet1!$E$2:$E$4"}]}`,
+	} {
+		assert.NoError(t, f.AddChart("Sheet1", "H"+strconv.Itoa(idx*16+1), format))
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartStockBubble.xlsx")))
+
+	load := func(name string) string {
+		content, ok := f.Pkg.Load(name)
+		assert.True(t, ok)
+		return string(content.([]byte))
+	}
+	chart := load("xl/charts/chart1.xml")
+	assert.Contains(t, chart, `<stockChart><ser><idx val="0"></idx><order val="0"></order><tx><strRef><f>Sheet1!$D$1</f></strRef></tx><spPr><a:ln w="25400"><a:noFill> </a:noFill></a:ln></spPr><invertIfNegative val="false"></invertIfNegative><marker><symbol val="none"></symbol>`)
+	assert.Contains(t, chart, `<hiLowLines><spPr>`)
+	assert.NotContains(t, chart, "<upDownBars>")
+	assert.NotContains(t, chart, "<barChart>")
+	assert.Equal(t, 3, strings.Count(chart, "<ser>"))
+
+	chart = load("xl/charts/chart2.xml")
+	assert.Contains(t, chart, `<upDownBars><gapWidth val="150"></gapWidth><upBars><spPr><a:solidFill><a:schemeClr val="bg1"></a:schemeClr></a:solidFill>`)
+	assert.Contains(t, chart, `<downBars><spPr><a:solidFill><a:schemeClr val="tx1"></a:schemeClr></a:solidFill>`)
+	assert.Equal(t, 4, strings.Count(chart, "<ser>"))
+
+	for _, name := range []string{"xl/charts/chart3.xml", "xl/charts/chart4.xml"} {
+		chart = load(name)
+		assert.Contains(t, chart, `<barChart><barDir val="col"></barDir><grouping val="clustered"></grouping><varyColors val="false"></varyColors><ser><idx val="0"></idx><order val="0"></order><tx><strRef><f>Sheet1!$B$1</f></strRef></tx>`)
+		assert.Contains(t, chart, `<axId val="754001152"></axId><axId val="753999904"></axId></barChart><stockChart><ser><idx val="1"></idx><order val="1"></order>`)
+		assert.Contains(t, chart, `<axId val="754001153"></axId><axId val="753999905"></axId></stockChart>`)
+		assert.Contains(t, chart, `<catAx><axId val="754001153"></axId><scaling><orientation val="minMax"></orientation></scaling><delete val="true"></delete>`)
+		assert.Contains(t, chart, `<valAx><axId val="753999905"></axId>`)
+		assert.Contains(t, chart, `<axPos val="r"></axPos>`)
+		assert.Contains(t, chart, `<crossAx val="754001153"></crossAx><crosses val="max"></crosses>`)
+		assert.Equal(t, 2, strings.Count(chart, "<catAx>"))
+		assert.Equal(t, 2, strings.Count(chart, "<valAx>"))
+	}
+	assert.Equal(t, 1, strings.Count(chart, `<max val="30"></max>`))
+	assert.Contains(t, chart, "<upDownBars>")
+
+	chart = load("xl/charts/chart5.xml")
+	assert.Contains(t, chart, `<xVal><strRef><f>Sheet1!$F$2:$F$4</f></strRef></xVal><yVal><numRef><f>Sheet1!$C$2:$C$4</f></numRef></yVal><bubbleSize><numRef><f>Sheet1!$B$2:$B$4</f></numRef></bubbleSize>`)
+	assert.Contains(t, chart, `<yVal><numRef><f>Sheet1!$E$2:$E$4</f></numRef></yVal><bubbleSize><numRef><f>Sheet1!$E$2:$E$4</f></numRef></bubbleSize>`)
+
+	// Test add stock chart with invalid number of series.
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"stockOpenHighLowClose","series":`+series("D", "E", "F")+`}`), ErrChartStockSeries.Error())
+}
+
+func TestAddChartEx(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Region", "Product", "Sales", "Cost"},
+		{"East", "Apple", 120, 80},
+		{"East", "Orange", 90, 60},
+		{"West", "Apple", -40, 30},
+		{"West", "Pear", 70, 20},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(idx+1), &row))
+	}
+	for idx, format := range []string{
+		`{"type":"waterfall","series":[{"name":"Sheet1!$C$1","categories":"Sheet1!$B$2:$B$5","values":"Sheet1!$C$2:$C$5"}],"title":{"name":"Waterfall Chart"},"plotarea":{"show_val":true}}`,
+		`{"type":"funnel","series":[{"name":"Sheet1!$C$1","categories":"Sheet1!$B$2:$B$5","values":"Sheet1!$C$2:$C$5"}],"legend":{"none":true}}`,
+		`{"type":"treemap","series":[{"name":"Sheet1!$D$1","categories":"Sheet1!$A$2:$B$5","values":"Sheet1!$D$2:$D$5"}],"title":{"none":true}}`,
+		`{"type":"sunburst","series":[{"name":"Sheet1!$D$1","categories":"Sheet1!$A$2:$B$5","values":"Sheet1!$C$2:$C$5","sizes":"Sheet1!$D$2:$D$5"}],"legend":{"position":"top_right"}}`,
+		`{"type":"boxWhisker","series":[{"name":"Sheet1!$C$1","values":"Sheet1!$C$2:$C$5"},{"name":"Sheet1!$D$1","values":"Sheet1!$D$2:$D$5"}]}`,
+		`{"type":"histogram","series":[{"name":"Sheet1!$D$1","values":"Sheet1!$D$2:$D$5"}],"dimension":{"width":640,"height":480}}`,
+		`{"type":"pareto","series":[{"name":"Sheet1!$D$1","categories":"Sheet1!$B$2:$B$5","values":"Sheet1!$D$2:$D$5"}],"format":{"locked":true,"print_obj":false}}`,
+	} {
+		assert.NoError(t, f.AddChart("Sheet1", "F"+strconv.Itoa(idx*16+1), format))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"name":"Sheet1!$C$1","categories":"Sheet1!$B$2:$B$5","values":"Sheet1!$C$2:$C$5"}]}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartEx.xlsx")))
+
+	load := func(name string) string {
+		content, ok := f.Pkg.Load(name)
+		assert.True(t, ok, name)
+		if !ok {
+			return ""
+		}
+		return string(content.([]byte))
+	}
+	chart := load("xl/charts/chartEx1.xml")
+	assert.Contains(t, chart, `<chartSpace xmlns="http://schemas.microsoft.com/office/drawing/2014/chartex" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	assert.Contains(t, chart, `<chartData><data id="0"><strDim type="cat"><f>Sheet1!$B$2:$B$5</f></strDim><numDim type="val"><f>Sheet1!$C$2:$C$5</f></numDim></data></chartData>`)
+	assert.Contains(t, chart, `<title pos="t" align="ctr" overlay="false"><tx><txData><v>Waterfall Chart</v></txData></tx></title>`)
+	assert.Contains(t, chart, `<series layoutId="waterfall"><tx><txData><f>Sheet1!$C$1</f></txData></tx><dataLabels><visibility seriesName="false" categoryName="false" value="true"></visibility></dataLabels><dataId val="0"></dataId></series>`)
+	assert.Contains(t, chart, `<axis id="0"><catScaling gapWidth="0.5"></catScaling><tickLabels></tickLabels></axis><axis id="1"><valScaling></valScaling><majorGridlines></majorGridlines><tickLabels></tickLabels></axis>`)
+	assert.Contains(t, chart, `<legend pos="b" align="ctr" overlay="false"></legend>`)
+
+	chart = load("xl/charts/chartEx2.xml")
+	assert.Contains(t, chart, `<series layoutId="funnel">`)
+	assert.Contains(t, chart, `<axis id="1" hidden="true"><valScaling></valScaling></axis>`)
+	assert.NotContains(t, chart, "<legend")
+
+	chart = load("xl/charts/chartEx3.xml")
+	assert.Contains(t, chart, `<strDim type="cat"><f>Sheet1!$A$2:$B$5</f></strDim><numDim type="size"><f>Sheet1!$D$2:$D$5</f></numDim>`)
+	assert.Contains(t, chart, `<layoutPr><parentLabelLayout val="overlapping"></parentLabelLayout></layoutPr>`)
+	assert.NotContains(t, chart, "<title")
+	assert.NotContains(t, chart, "<axis")
+
+	chart = load("xl/charts/chartEx4.xml")
+	assert.Contains(t, chart, `<numDim type="size"><f>Sheet1!$D$2:$D$5</f></numDim>`)
+	assert.Contains(t, chart, `<legend pos="r" align="min" overlay="false"></legend>`)
+
+	chart = load("xl/charts/chartEx5.xml")
+	assert.Contains(t, chart, `<data id="1"><numDim type="val"><f>Sheet1!$D$2:$D$5</f></numDim></data>`)
+	assert.Contains(t, chart, `<layoutPr><visibility meanLine="false" meanMarker="true" nonoutliers="false" outliers="true"></visibility><statistics quartileMethod="exclusive"></statistics></layoutPr>`)
+
+	chart = load("xl/charts/chartEx6.xml")
+	assert.Contains(t, chart, `<series layoutId="clusteredColumn"><tx><txData><f>Sheet1!$D$1</f></txData></tx><dataId val="0"></dataId><layoutPr><binning intervalClosed="r"></binning></layoutPr></series>`)
+	assert.Contains(t, chart, `<catScaling gapWidth="0"></catScaling>`)
+
+	chart = load("xl/charts/chartEx7.xml")
+	assert.Contains(t, chart, `<layoutPr><aggregation></aggregation></layoutPr><axisId val="0"></axisId><axisId val="1"></axisId></series><series layoutId="paretoLine" ownerIdx="0"><axisId val="2"></axisId></series>`)
+	assert.Contains(t, chart, `<axis id="2"><valScaling max="1" min="0"></valScaling><units unit="percentage"></units><tickLabels></tickLabels></axis>`)
+
+	// Test the drawing part, relationships and content types of the charts.
+	drawing := load("xl/drawings/drawing1.xml")
+	assert.Equal(t, 7, strings.Count(drawing, `<mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006">`))
+	assert.Contains(t, drawing, `<mc:Choice xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" Requires="cx1"><xdr:graphicFrame macro=""><xdr:nvGraphicFramePr><xdr:cNvPr id="2" name="Chart 2" descr=""></xdr:cNvPr><xdr:cNvGraphicFramePr></xdr:cNvGraphicFramePr></xdr:nvGraphicFramePr><xdr:xfrm><a:off x="0" y="0"></a:off><a:ext cx="0" cy="0"></a:ext></xdr:xfrm><a:graphic><a:graphicData uri="http://schemas.microsoft.com/office/drawing/2014/chartex"><cx:chart xmlns:cx="http://schemas.microsoft.com/office/drawing/2014/chartex" r:id="rId1" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"></cx:chart></a:graphicData></a:graphic></xdr:graphicFrame></mc:Choice>`)
+	assert.Contains(t, drawing, `<mc:Choice xmlns:cx2="http://schemas.microsoft.com/office/drawing/2015/10/21/chartex" Requires="cx2">`)
+	assert.Contains(t, drawing, `<a:t>This chart isn&#39;t available in your version of Excel.</a:t>`)
+	assert.Contains(t, drawing, `<xdr:clientData fLocksWithSheet="true" fPrintsWithSheet="false"></xdr:clientData>`)
+	rels := load("xl/drawings/_rels/drawing1.xml.rels")
+	for i := 1; i <= 7; i++ {
+		assert.Contains(t, rels, fmt.Sprintf(`<Relationship Id="rId%d" Target="../charts/chartEx%d.xml" Type="http://schemas.microsoft.com/office/2014/relationships/chartEx"></Relationship>`, i, i))
+	}
+	assert.Contains(t, rels, `<Relationship Id="rId8" Target="../charts/chart8.xml" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"></Relationship>`)
+	assert.Contains(t, load("[Content_Types].xml"), `<Override PartName="/xl/charts/chartEx1.xml" ContentType="application/vnd.ms-office.chartex+xml"></Override>`)
+
+	// Test get charts with the extended charts in the worksheet.
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+
+	// Test add the extended chart with unsupported combo chart and chartsheet.
+	format := `{"type":"waterfall","series":[{"name":"Sheet1!$C$1","categories":"Sheet1!$B$2:$B$5","values":"Sheet1!$C$2:$C$5"}]}`
+	assert.EqualError(t, f.AddChart("Sheet1", "P16", format, format), ErrChartExCombo.Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P16", `{"type":"col"}`, format), "unsupported chart type waterfall")
+	assert.EqualError(t, f.AddChartSheet("Chart1", format), "unsupported chart type waterfall")
+	// Test add the extended chart with invalid cell reference.
+	assert.EqualError(t, f.AddChart("Sheet1", "A", format), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
 func TestAddChartSheet(t *testing.T) {
 	categories := map[string]string{"A2": "Small", "A3": "Normal", "A4": "Large", "B1": "Apple", "C1": "Orange", "D1": "Pear"}
 	values := map[string]int{"B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4, "B4": 6, "C4": 7, "D4": 8}
@@ -256,6 +727,241 @@ func TestDeleteChart(t *testing.T) {
 	assert.NoError(t, NewFile().DeleteChart("Sheet1", "A1"))
 }
 
+func TestGetCharts(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	for idx, row := range [][]interface{}{
+		{nil, "Volume", "High", "Low", "Close"},
+		{"2021-01-01", 10, 14, 8, 12},
+		{"2021-01-02", 20, 16, 10, 11},
+		{"2021-01-03", 30, 18, 12, 16},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := `{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$B$2:$B$4"}`
+	for cell, format := range map[string][]string{
+		"G1": {`{"type":"col","series":[{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$B$2:$B$4","fill":{"color":"#FF0000"}},{"name":"Sheet1!$C$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$C$2:$C$4"}],"format":{"x_offset":15,"y_offset":10},"title":{"name":"Column Chart"},"legend":{"position":"left"},"gap_width":50,"overlap":10,"plotarea":{"show_val":true,"fill":{"color":"#EEEEEE"}},"chartarea":{"border":{"none":true}},"show_blanks_as":"zero"}`,
+			`{"type":"line","series":[{"name":"Sheet1!$E$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$E$2:$E$4","line":{"color":"#00FF00","width":2},"smooth":true}]}`},
+		"G20": {`{"type":"stockVolumeHighLowClose","series":[{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$B$2:$B$4"},{"name":"Sheet1!$C$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$C$2:$C$4"},{"name":"Sheet1!$D$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$D$2:$D$4"},{"name":"Sheet1!$E$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$E$2:$E$4"}],"legend":{"none":true},"title":{"name":"Sheet1!$B$1"}}`},
+		"P1":  {`{"type":"scatter","series":[` + series + `],"title":{"none":true},"x_axis":{"crossing":"max","major_tick_mark":"out"},"y_axis":{"crossing":"2","logbase":10}}`},
+		"P20": {`{"type":"bar3DConeStacked","series":[` + series + `],"dimension":{"width":320,"height":240},"format":{"positioning":"oneCell","locked":true,"print_obj":false},"x_axis":{"reverse_order":true},"y_axis":{"maximum":40,"major_grid_lines":true,"num_format":"0.0"}}`},
+	} {
+		assert.NoError(t, f.AddChart("Sheet1", cell, format[0], format[1:]...))
+	}
+	assert.NoError(t, f.AddPicture("Sheet1", "A10", filepath.Join("test", "images", "excel.png"), ""))
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	if !assert.Len(t, charts, 4) {
+		t.FailNow()
+	}
+	formats, combos := map[string]*formatChart{}, map[string][]string{}
+	for _, chart := range charts {
+		formatSet, err := parseFormatChartSet(chart.Format)
+		assert.NoError(t, err)
+		formats[chart.Cell], combos[chart.Cell] = formatSet, chart.Combo
+		// Test add the chart with the format settings read from the chart.
+		assert.NoError(t, f.AddChart("Sheet2", chart.Cell, chart.Format, chart.Combo...))
+	}
+	col := formats["G1"]
+	assert.Equal(t, Col, col.Type)
+	assert.Len(t, col.Series, 2)
+	assert.Equal(t, "Sheet1!$C$1", col.Series[1].Name)
+	assert.Equal(t, "Sheet1!$A$2:$A$4", col.Series[1].Categories)
+	assert.Equal(t, "Sheet1!$C$2:$C$4", col.Series[1].Values)
+	assert.Equal(t, "FF0000", col.Series[0].Fill.Color)
+	assert.Equal(t, "Column Chart", col.Title.Name)
+	assert.Equal(t, "left", col.Legend.Position)
+	assert.Equal(t, formatChartDimension{Width: 480, Height: 290}, col.Dimension)
+	assert.Equal(t, 15, col.Format.OffsetX)
+	assert.Equal(t, 10, col.Format.OffsetY)
+	assert.Equal(t, 50, *col.GapWidth)
+	assert.Equal(t, 10, *col.Overlap)
+	assert.True(t, col.Plotarea.ShowVal)
+	assert.Equal(t, "EEEEEE", col.Plotarea.Fill.Color)
+	assert.True(t, col.Chartarea.Border.None)
+	assert.Equal(t, "zero", col.ShowBlanksAs)
+	if !assert.Len(t, combos["G1"], 1) {
+		t.FailNow()
+	}
+	combo, err := parseFormatChartSet(combos["G1"][0])
+	assert.NoError(t, err)
+	assert.Equal(t, Line, combo.Type)
+	assert.Equal(t, "00FF00", combo.Series[0].Line.Color)
+	assert.Equal(t, 2.0, combo.Series[0].Line.Width)
+	assert.True(t, combo.Series[0].Smooth)
+
+	stock := formats["G20"]
+	assert.Equal(t, StockVolumeHighLowClose, stock.Type)
+	assert.Len(t, stock.Series, 4)
+	assert.Equal(t, "Sheet1!$B$2:$B$4", stock.Series[0].Values)
+	assert.Equal(t, "Sheet1!$E$2:$E$4", stock.Series[3].Values)
+	assert.Equal(t, "Sheet1!$B$1", stock.Title.Name)
+	assert.True(t, stock.Legend.None)
+
+	scatter := formats["P1"]
+	assert.Equal(t, Scatter, scatter.Type)
+	assert.Equal(t, "Sheet1!$A$2:$A$4", scatter.Series[0].Categories)
+	assert.True(t, scatter.Title.None)
+	assert.Equal(t, "max", scatter.XAxis.Crossing)
+	assert.Equal(t, "2", scatter.YAxis.Crossing)
+	assert.Equal(t, "out", scatter.XAxis.MajorTickMark)
+	assert.Equal(t, 10.0, scatter.YAxis.LogBase)
+
+	bar := formats["P20"]
+	assert.Equal(t, Bar3DConeStacked, bar.Type)
+	assert.Equal(t, formatChartDimension{Width: 320, Height: 240}, bar.Dimension)
+	assert.Equal(t, "oneCell", bar.Format.Positioning)
+	assert.True(t, bar.Format.FLocksWithSheet)
+	assert.False(t, bar.Format.FPrintsWithSheet)
+	assert.True(t, bar.XAxis.ReverseOrder)
+	assert.Equal(t, 40.0, bar.YAxis.Maximum)
+	assert.True(t, bar.YAxis.MajorGridlines)
+	assert.Equal(t, "0.0", bar.YAxis.NumFormat)
+
+	// Test the charts added by the format settings read from the charts are
+	// the same as the original charts.
+	copied, err := f.GetCharts("Sheet2")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, charts, copied)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCharts.xlsx")))
+
+	// Test get charts in the chart part created by Microsoft Excel.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
+	for _, chart := range charts {
+		assert.NoError(t, f.AddChart("Sheet2", chart.Cell, chart.Format, chart.Combo...))
+	}
+	formatSet, err := parseFormatChartSet(charts[1].Format)
+	assert.NoError(t, err)
+	assert.Equal(t, Col, formatSet.Type)
+	assert.Equal(t, "Sheet2!$D$2:$D$11", formatSet.Series[0].Values)
+	assert.True(t, formatSet.Format.FPrintsWithSheet)
+
+	// Test get charts on not exists worksheet.
+	_, err = f.GetCharts("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get charts on the worksheet without drawing.
+	charts, err = NewFile().GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 0)
+
+	// Test get charts with unsupported chart type.
+	f = NewFile()
+	assert.NoError(t, f.AddChart("Sheet1", "A1", `{"type":"col","series":[`+series+`]}`))
+	chart, _ := f.Pkg.Load("xl/charts/chart1.xml")
+	f.Pkg.Store("xl/charts/chart1.xml", bytes.Replace(chart.([]byte), []byte("barChart>"), []byte("unknownChart>"), -1))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 0)
+	// Test get charts with invalid chart part.
+	f.Pkg.Store("xl/charts/chart1.xml", []byte("<chart/>"))
+	_, err = f.GetCharts("Sheet1")
+	assert.EqualError(t, err, ErrChartTemplate.Error())
+	f.Pkg.Store("xl/charts/chart1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get charts without chart relationship.
+	f.Pkg.Delete("xl/drawings/_rels/drawing1.xml.rels")
+	f.Relationships.Delete("xl/drawings/_rels/drawing1.xml.rels")
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 0)
+}
+
+func TestSetChartSeries(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Q1", "Q2", "Q3"}, {"Apple", 2, 3, 4}, {"Orange", 5, 2, 7}, {"Pear", 6, 7, 8}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "F1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$2:$C$2","fill":{"color":"#FF0000"}}],"title":{"name":"Fruits"}}`,
+		`{"type":"line","series":[{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$3:$C$3","line":{"color":"#00FF00"}}]}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "F20", filepath.Join("test", "images", "excel.png"), ""))
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	if !assert.Len(t, charts, 1) {
+		t.FailNow()
+	}
+	name := charts[0].Name
+	assert.Equal(t, "Chart 2", name)
+
+	// Test update the series and add the series with the formatting of the
+	// last series.
+	assert.NoError(t, f.SetChartSeries("Sheet1", name, []ChartSeries{
+		{Name: "Sheet1!$A$3", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$3:$D$3"},
+		{Values: "Sheet1!$B$2:$D$2"},
+		{Name: "Sheet1!$A$4", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$4:$D$4"},
+	}))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	formatSet, err := parseFormatChartSet(charts[0].Format)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fruits", formatSet.Title.Name)
+	assert.Equal(t, "FF0000", formatSet.Series[0].Fill.Color)
+	assert.Equal(t, "Sheet1!$A$3", formatSet.Series[0].Name)
+	assert.Equal(t, "Sheet1!$B$1:$D$1", formatSet.Series[0].Categories)
+	assert.Equal(t, "Sheet1!$B$3:$D$3", formatSet.Series[0].Values)
+	assert.Len(t, charts[0].Combo, 1)
+	combo, err := parseFormatChartSet(charts[0].Combo[0])
+	assert.NoError(t, err)
+	assert.Len(t, combo.Series, 2)
+	assert.Equal(t, "Sheet1!$A$3", combo.Series[0].Name)
+	assert.Equal(t, "Sheet1!$B$1:$C$1", combo.Series[0].Categories)
+	assert.Equal(t, "Sheet1!$B$2:$D$2", combo.Series[0].Values)
+	assert.Equal(t, "Sheet1!$A$4", combo.Series[1].Name)
+	assert.Equal(t, "00FF00", combo.Series[1].Line.Color)
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), `<c:idx val="2"/><c:order val="2"/>`)
+
+	// Test remove the extra series and the chart group without series.
+	assert.NoError(t, f.SetChartSeries("Sheet1", name, []ChartSeries{{Values: "Sheet1!$B$4:$D$4"}}))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts[0].Combo, 0)
+	formatSet, err = parseFormatChartSet(charts[0].Format)
+	assert.NoError(t, err)
+	assert.Len(t, formatSet.Series, 1)
+	assert.Equal(t, "Sheet1!$B$4:$D$4", formatSet.Series[0].Values)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetChartSeries.xlsx")))
+
+	// Test update the series of scatter and bubble chart.
+	assert.NoError(t, f.AddChart("Sheet1", "P1", `{"type":"bubble","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$2:$C$2","sizes":"Sheet1!$B$3:$C$3"}]}`))
+	objects, err := f.getDrawingObjects("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetChartSeries("Sheet1", objects[2].Name, []ChartSeries{{Categories: "Sheet1!$B$2:$D$2", Values: "Sheet1!$B$3:$D$3", Sizes: "Sheet1!$B$4:$D$4"}}))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	formatSet, err = parseFormatChartSet(charts[1].Format)
+	assert.NoError(t, err)
+	assert.Equal(t, Bubble, formatSet.Type)
+	assert.Equal(t, "Sheet1!$B$2:$D$2", formatSet.Series[0].Categories)
+	assert.Equal(t, "Sheet1!$B$3:$D$3", formatSet.Series[0].Values)
+	assert.Equal(t, "Sheet1!$B$4:$D$4", formatSet.Series[0].Sizes)
+
+	// Test update the series with invalid parameters.
+	assert.EqualError(t, f.SetChartSeries("Sheet1", name, nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetChartSeries("SheetN", name, []ChartSeries{{}}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetChartSeries("Sheet1", "Chart", []ChartSeries{{}}), ErrObjectNotExist.Error())
+	assert.EqualError(t, f.SetChartSeries("Sheet1", objects[1].Name, []ChartSeries{{}}), ErrChartObject.Error())
+	// Test update the series of the chart without series.
+	f.Pkg.Store("xl/charts/chart1.xml", []byte(`<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart"><c:chart><c:plotArea><c:barChart/></c:plotArea></c:chart></c:chartSpace>`))
+	assert.EqualError(t, f.SetChartSeries("Sheet1", name, []ChartSeries{{}}), ErrChartSeries.Error())
+	// Test update the series with invalid chart part.
+	f.Pkg.Store("xl/charts/chart1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetChartSeries("Sheet1", name, []ChartSeries{{}}), "XML syntax error on line 1: invalid UTF-8")
+	// Test update the series without chart relationship.
+	f.Pkg.Delete("xl/drawings/_rels/drawing1.xml.rels")
+	f.Relationships.Delete("xl/drawings/_rels/drawing1.xml.rels")
+	assert.EqualError(t, f.SetChartSeries("Sheet1", name, []ChartSeries{{}}), ErrObjectNotExist.Error())
+}
+
 func TestChartWithLogarithmicBase(t *testing.T) {
 	// Create test XLSX file with data
 	f := NewFile()
@@ -372,3 +1078,51 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 		}
 	}
 }
+
+func TestAddPivotChart(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Region", "Sales", "Profit"}))
+	for i, region := range []string{"East", "West", "North", "South"} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{region, (i + 1) * 100, (i + 1) * 10}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$C$5",
+		PivotTableRange: "Sheet1!$E$1:$G$6",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum"}, {Data: "Profit", Subtotal: "Sum"}},
+		Name:            "PivotTable1",
+	}))
+	assert.NoError(t, f.AddChart("Sheet1", "I1", `{"type":"col","pivot_table":"Sheet1!PivotTable1","title":{"name":"Sales by Region"}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "I20", `{"type":"line","pivot_table":"'Sheet1'!PivotTable1","series":[{"name":"Sheet1!$F$1","categories":"Sheet1!$E$2:$E$5","values":"Sheet1!$F$2:$F$5"}]}`))
+
+	var chartSpace xlsxChartSpace
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/charts/chart1.xml"), &chartSpace))
+	assert.Equal(t, "[]Sheet1!PivotTable1", chartSpace.PivotSource.Name)
+	assert.Equal(t, 0, *chartSpace.PivotSource.FmtID.Val)
+	assert.Len(t, *chartSpace.Chart.PlotArea.BarChart.Ser, 2)
+	assert.Equal(t, "Sheet1!$E$2:$E$6", (*chartSpace.Chart.PlotArea.BarChart.Ser)[0].Cat.StrRef.F)
+	assert.Equal(t, "Sheet1!$G$2:$G$6", (*chartSpace.Chart.PlotArea.BarChart.Ser)[1].Val.NumRef.F)
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/charts/chart2.xml"), &chartSpace))
+	assert.Equal(t, 1, *chartSpace.PivotSource.FmtID.Val)
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pt.ChartFormat)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotChart.xlsx")))
+
+	// Test add pivot chart with the extended chart types.
+	assert.EqualError(t, f.AddChart("Sheet1", "I40", `{"type":"funnel","pivot_table":"Sheet1!PivotTable1"}`), ErrChartExPivotTable.Error())
+	// Test add pivot chart with invalid pivot table reference.
+	assert.EqualError(t, f.AddChart("Sheet1", "I40", `{"type":"col","pivot_table":"PivotTable1"}`), ErrParameterInvalid.Error())
+	// Test add pivot chart on not exists worksheet.
+	assert.EqualError(t, f.AddChart("Sheet1", "I40", `{"type":"col","pivot_table":"SheetN!PivotTable1"}`), "sheet SheetN is not exist")
+	// Test add pivot chart with not exists pivot table.
+	assert.EqualError(t, f.AddChart("Sheet1", "I40", `{"type":"col","pivot_table":"Sheet1!PivotTable2"}`), ErrPivotTableNotExist.Error())
+	// Test add pivot chart with unsupported charset pivot table.
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddChart("Sheet1", "I40", `{"type":"col","pivot_table":"Sheet1!PivotTable1"}`), "XML syntax error on line 1: invalid UTF-8")
+	// Test get pivot chart series with invalid location.
+	_, err = f.getPivotChartSeries("Sheet1", &xlsxPivotTableDefinition{})
+	assert.EqualError(t, err, ErrParameterInvalid.Error())
+	_, err = f.getPivotChartSeries("Sheet1", &xlsxPivotTableDefinition{Location: &xlsxLocation{Ref: "A:B"}, DataFields: &xlsxDataFields{}})
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
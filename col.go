@@ -199,6 +199,11 @@ func (f *File) Cols(sheet string) (*Cols, error) {
 	if !ok {
 		return nil, ErrSheetNotExist{sheet}
 	}
+	if !strings.HasPrefix(name, "xl/worksheets/") {
+		if sheetType := f.getSheetType(name); sheetType != "" && sheetType != "worksheet" {
+			return nil, ErrNotWorksheet{SheetName: sheet, SheetType: sheetType}
+		}
+	}
 	if ws, ok := f.Sheet.Load(name); ok && ws != nil {
 		worksheet := ws.(*xlsxWorksheet)
 		worksheet.Lock()
@@ -277,6 +282,22 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 	if err != nil {
 		return err
 	}
+	return f.SetColsVisible(sheet, start, end, visible)
+}
+
+// SetColsVisible provides a function to set visible of the columns by given
+// worksheet name, the first and the last column number of the columns and
+// the visibility. For example, hide the columns from D to F in Sheet1:
+//
+//    err := f.SetColsVisible("Sheet1", 4, 6, false)
+//
+func (f *File) SetColsVisible(sheet string, start, end int, visible bool) error {
+	if end < start {
+		start, end = end, start
+	}
+	if start < 1 || end > TotalColumns {
+		return ErrColumnNumber
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -307,6 +328,42 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 	return nil
 }
 
+// GetHiddenCols provides a function to get the hidden columns by given
+// worksheet name, the contiguous hidden columns are returned as one columns
+// range in the order of the columns. For example, get the hidden columns in
+// Sheet1, the result will be [D:F H:H] if the columns D, E, F and H are
+// hidden:
+//
+//    cols, err := f.GetHiddenCols("Sheet1")
+//
+func (f *File) GetHiddenCols(sheet string) ([]string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.Cols == nil {
+		return nil, err
+	}
+	hidden := make([]bool, TotalColumns+2)
+	for _, col := range ws.Cols.Col {
+		for c := col.Min; c <= col.Max && c <= TotalColumns; c++ {
+			if c > 0 {
+				hidden[c] = col.Hidden
+			}
+		}
+	}
+	var hiddenCols []string
+	for c, start := 1, 0; c <= TotalColumns+1; c++ {
+		if hidden[c] && start == 0 {
+			start = c
+		}
+		if !hidden[c] && start != 0 {
+			startCol, _ := ColumnNumberToName(start)
+			endCol, _ := ColumnNumberToName(c - 1)
+			hiddenCols = append(hiddenCols, startCol+":"+endCol)
+			start = 0
+		}
+	}
+	return hiddenCols, err
+}
+
 // GetColOutlineLevel provides a function to get outline level of a single
 // column by given worksheet name and column name. For example, get outline
 // level of column D in Sheet1:
@@ -335,6 +392,124 @@ func (f *File) GetColOutlineLevel(sheet, col string) (uint8, error) {
 	return level, err
 }
 
+// ColOption is an option of a column of a worksheet. See SetColOptions().
+type ColOption interface {
+	setColOption(col *xlsxCol)
+}
+
+// ColOptionPtr is a writable ColOption. See GetColOptions().
+type ColOptionPtr interface {
+	ColOption
+	getColOption(col *xlsxCol)
+}
+
+type (
+	// ColBestFit is a ColOption
+	ColBestFit bool
+	// ColCollapsed is a ColOption
+	ColCollapsed bool
+	// ColPhonetic is a ColOption
+	ColPhonetic bool
+)
+
+// setColOption implements the ColOption interface and specifies if the
+// column width has been set to the best fit width.
+func (o ColBestFit) setColOption(col *xlsxCol) {
+	col.BestFit = bool(o)
+}
+
+// getColOption implements the ColOptionPtr interface and get the settings of
+// whether the column width is the best fit width.
+func (o *ColBestFit) getColOption(col *xlsxCol) {
+	*o = ColBestFit(col.BestFit)
+}
+
+// setColOption implements the ColOption interface and specifies if the
+// outlining of the affected columns is in the collapsed state.
+func (o ColCollapsed) setColOption(col *xlsxCol) {
+	col.Collapsed = bool(o)
+}
+
+// getColOption implements the ColOptionPtr interface and get the settings of
+// whether the outline of the column is collapsed.
+func (o *ColCollapsed) getColOption(col *xlsxCol) {
+	*o = ColCollapsed(col.Collapsed)
+}
+
+// setColOption implements the ColOption interface and specifies if the
+// phonetic information should be displayed by default for the column.
+func (o ColPhonetic) setColOption(col *xlsxCol) {
+	col.Phonetic = bool(o)
+}
+
+// getColOption implements the ColOptionPtr interface and get the settings of
+// whether the phonetic information should be displayed for the column.
+func (o *ColPhonetic) getColOption(col *xlsxCol) {
+	*o = ColPhonetic(col.Phonetic)
+}
+
+// SetColOptions provides a function to set the column attributes by given
+// worksheet name, columns range and column options. For example, collapse
+// the outline of the columns from D to F in Sheet1:
+//
+//    err := f.SetColOptions("Sheet1", "D:F", excelize.ColCollapsed(true))
+//
+func (f *File) SetColOptions(sheet, columns string, opts ...ColOption) error {
+	start, end, err := f.parseColRange(columns)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.Cols == nil {
+		ws.Cols = &xlsxCols{}
+	}
+	colData := xlsxCol{Min: start, Max: end, Width: defaultColWidth}
+	for _, opt := range opts {
+		opt.setColOption(&colData)
+	}
+	ws.Cols.Col = flatCols(colData, ws.Cols.Col, func(fc, c xlsxCol) xlsxCol {
+		for _, opt := range opts {
+			opt.setColOption(&c)
+		}
+		c.Min, c.Max = fc.Min, fc.Max
+		return c
+	})
+	return err
+}
+
+// GetColOptions provides a function to get the column attributes by given
+// worksheet name, column name and column options. For example, get the
+// collapsed state of column D in Sheet1:
+//
+//    var collapsed excelize.ColCollapsed
+//    err := f.GetColOptions("Sheet1", "D", &collapsed)
+//
+func (f *File) GetColOptions(sheet, col string, opts ...ColOptionPtr) error {
+	colNum, err := ColumnNameToNumber(col)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	colData := xlsxCol{}
+	if ws.Cols != nil {
+		for _, c := range ws.Cols.Col {
+			if c.Min <= colNum && colNum <= c.Max {
+				colData = c
+			}
+		}
+	}
+	for _, opt := range opts {
+		opt.getColOption(&colData)
+	}
+	return err
+}
+
 // parseColRange parse and convert column range with column name to the column number.
 func (f *File) parseColRange(columns string) (start, end int, err error) {
 	colsTab := strings.Split(columns, ":")
@@ -613,6 +788,54 @@ func (f *File) positionObjectPixels(sheet string, col, row, x1, y1, width, heigh
 	return col, row, colEnd, rowEnd, x2, y2
 }
 
+// positionObjectEMUs provides a function to calculate the vertices that
+// define the position of a graphical object within the worksheet in EMUs by
+// the zero-based start column and row index, offsets and size of the object,
+// the start offsets greater than the width or height of the cell will be
+// moved to the next cells. It returns the start column, start row, start
+// offsets, end column, end row and end offsets.
+func (f *File) positionObjectEMUs(sheet string, col, row, x1, y1, width, height int) (int, int, int, int, int, int, int, int) {
+	for x1 >= f.getColWidth(sheet, col+1)*EMU {
+		x1 -= f.getColWidth(sheet, col+1) * EMU
+		col++
+	}
+	for y1 >= f.getRowHeight(sheet, row+1)*EMU {
+		y1 -= f.getRowHeight(sheet, row+1) * EMU
+		row++
+	}
+	colEnd, rowEnd := col, row
+	width += x1
+	height += y1
+	for width >= f.getColWidth(sheet, colEnd+1)*EMU {
+		colEnd++
+		width -= f.getColWidth(sheet, colEnd) * EMU
+	}
+	for height >= f.getRowHeight(sheet, rowEnd+1)*EMU {
+		rowEnd++
+		height -= f.getRowHeight(sheet, rowEnd) * EMU
+	}
+	return col, row, x1, y1, colEnd, rowEnd, width, height
+}
+
+// anchorSizeEMUs provides a function to calculate the absolute position of
+// the start vertex and the size of the object in EMUs by given zero-based
+// start and end vertices of the two cell anchor.
+func (f *File) anchorSizeEMUs(sheet string, colStart, rowStart, x1, y1, colEnd, rowEnd, x2, y2 int) (x, y, width, height int) {
+	for col := 0; col < colStart; col++ {
+		x += f.getColWidth(sheet, col+1) * EMU
+	}
+	for row := 0; row < rowStart; row++ {
+		y += f.getRowHeight(sheet, row+1) * EMU
+	}
+	for col := colStart; col < colEnd; col++ {
+		width += f.getColWidth(sheet, col+1) * EMU
+	}
+	for row := rowStart; row < rowEnd; row++ {
+		height += f.getRowHeight(sheet, row+1) * EMU
+	}
+	return x + x1, y + y1, width - x1 + x2, height - y1 + y2
+}
+
 // getColWidth provides a function to get column width in pixels by given
 // sheet name and column number.
 func (f *File) getColWidth(sheet string, col int) int {
@@ -658,6 +881,115 @@ func (f *File) GetColWidth(sheet, col string) (float64, error) {
 	return defaultColWidth, err
 }
 
+// AutoFitColWidth provides a function to adjust the width of a single column
+// or multiple columns to fit the formatted cell values by given worksheet
+// name and columns range. The text widths are measured by the registered
+// font metrics of the cell fonts, and the columns without any cell value
+// will be skipped. For example, auto fit the width of columns A to D in
+// Sheet1:
+//
+//    err := f.AutoFitColWidth("Sheet1", "A:D")
+//
+func (f *File) AutoFitColWidth(sheet, columns string) error {
+	start, end, err := f.parseColRange(columns)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	var (
+		sst    = f.sharedStringsReader()
+		fonts  = map[int]*Font{}
+		widths = map[int]float64{}
+	)
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			col, _, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if col < start || col > end {
+				continue
+			}
+			val, err := c.getValueFrom(f, sst)
+			if err != nil {
+				return err
+			}
+			if val == "" {
+				continue
+			}
+			styleID := f.prepareCellStyle(ws, col, c.S)
+			if _, ok := fonts[styleID]; !ok {
+				fonts[styleID] = f.getStyleFont(styleID)
+			}
+			font := fonts[styleID]
+			widths[col] = math.Max(widths[col], GetFontMetrics(font.Family).TextWidth(val, font))
+		}
+	}
+	digitWidth := f.getMaxDigitWidth()
+	for col, width := range widths {
+		name, _ := ColumnNumberToName(col)
+		width = math.Min(math.Trunc((width+5)/digitWidth*256)/256, MaxColumnWidth)
+		if err = f.SetColWidth(sheet, name, name, width); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// getMaxDigitWidth provides a function to get the maximum width of the digit
+// characters in pixels of the default font of the spreadsheet.
+func (f *File) getMaxDigitWidth() float64 {
+	font := f.getStyleFont(0)
+	if width := math.Round(GetFontMetrics(font.Family).TextWidth("0", font)); width > 0 {
+		return width
+	}
+	return 7
+}
+
+// SetColWidthPixels provides a function to set the width in pixels of a
+// single column or multiple columns, the width will be converted to the
+// character units by the maximum digit width of the default font of the
+// spreadsheet. For example, set the width of columns A to H in Sheet1 to 100
+// pixels:
+//
+//    err := f.SetColWidthPixels("Sheet1", "A", "H", 100)
+//
+func (f *File) SetColWidthPixels(sheet, startcol, endcol string, pixels float64) error {
+	return f.SetColWidth(sheet, startcol, endcol, PixelsToColWidth(pixels, f.getMaxDigitWidth()))
+}
+
+// GetColWidthPixels provides a function to get the width in pixels of the
+// column by given worksheet name and column name.
+func (f *File) GetColWidthPixels(sheet, col string) (float64, error) {
+	width, err := f.GetColWidth(sheet, col)
+	return ColWidthToPixels(width, f.getMaxDigitWidth()), err
+}
+
+// ColWidthToPixels provides a function to convert the column width in the
+// character units to pixels by given maximum digit width in pixels of the
+// default font, for example, the maximum digit width of the 11 point Calibri
+// font is 7 pixels.
+func ColWidthToPixels(width, maxDigitWidth float64) float64 {
+	if width <= 0 || maxDigitWidth <= 0 {
+		return 0
+	}
+	return math.Trunc((256*width + math.Trunc(128/maxDigitWidth)) / 256 * maxDigitWidth)
+}
+
+// PixelsToColWidth provides a function to convert the column width in pixels
+// to the character units by given maximum digit width in pixels of the
+// default font, the width will be truncated to the precision of 1/256
+// character.
+func PixelsToColWidth(pixels, maxDigitWidth float64) float64 {
+	if pixels <= 0 || maxDigitWidth <= 0 {
+		return 0
+	}
+	return math.Trunc(pixels/maxDigitWidth*256) / 256
+}
+
 // InsertCol provides a function to insert a new column before given column
 // index. For example, create a new column before column C in Sheet1:
 //
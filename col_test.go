@@ -168,6 +168,35 @@ func TestColsRows(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSetColsVisible(t *testing.T) {
+	f := NewFile()
+	hiddenCols, err := f.GetHiddenCols("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, hiddenCols)
+
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "E", 20))
+	assert.NoError(t, f.SetColsVisible("Sheet1", 4, 6, false))
+	assert.NoError(t, f.SetColsVisible("Sheet1", 10, 8, false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "XFD", false))
+	for col, expected := range map[string]bool{"C": true, "D": false, "F": false, "G": true, "H": false, "J": false, "K": true} {
+		visible, err := f.GetColVisible("Sheet1", col)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, visible, col)
+	}
+	width, err := f.GetColWidth("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	hiddenCols, err = f.GetHiddenCols("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"D:F", "H:J", "XFD:XFD"}, hiddenCols)
+
+	assert.EqualError(t, f.SetColsVisible("Sheet1", 0, 2, false), ErrColumnNumber.Error())
+	assert.EqualError(t, f.SetColsVisible("Sheet1", 1, TotalColumns+1, false), ErrColumnNumber.Error())
+	assert.EqualError(t, f.SetColsVisible("SheetN", 1, 2, false), "sheet SheetN is not exist")
+	_, err = f.GetHiddenCols("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
 func TestColumnVisibility(t *testing.T) {
 	t.Run("TestBook1", func(t *testing.T) {
 		f, err := prepareTestBook1()
@@ -228,6 +257,45 @@ func TestColumnVisibility(t *testing.T) {
 	})
 }
 
+func TestColOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetColWidth("Sheet1", "D", "E", 20))
+	assert.NoError(t, f.SetColOptions("Sheet1", "E:F", ColBestFit(true), ColCollapsed(true), ColPhonetic(true)))
+	path := filepath.Join("test", "TestColOptions.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+
+	f, err := OpenFile(path)
+	assert.NoError(t, err)
+	var (
+		bestFit   ColBestFit
+		collapsed ColCollapsed
+		phonetic  ColPhonetic
+	)
+	assert.NoError(t, f.GetColOptions("Sheet1", "D", &bestFit, &collapsed, &phonetic))
+	assert.False(t, bool(bestFit))
+	assert.False(t, bool(collapsed))
+	assert.False(t, bool(phonetic))
+	for _, col := range []string{"E", "F"} {
+		assert.NoError(t, f.GetColOptions("Sheet1", col, &bestFit, &collapsed, &phonetic))
+		assert.True(t, bool(bestFit))
+		assert.True(t, bool(collapsed))
+		assert.True(t, bool(phonetic))
+	}
+	width, err := f.GetColWidth("Sheet1", "E")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	// Test get column options without columns definition
+	f = NewFile()
+	assert.NoError(t, f.GetColOptions("Sheet1", "A", &bestFit))
+	assert.False(t, bool(bestFit))
+	// Test set and get column options with invalid column name
+	assert.EqualError(t, f.SetColOptions("Sheet1", "*", ColBestFit(true)), newInvalidColumnNameError("*").Error())
+	assert.EqualError(t, f.GetColOptions("Sheet1", "*", &bestFit), newInvalidColumnNameError("*").Error())
+	// Test set and get column options on not exists worksheet
+	assert.EqualError(t, f.SetColOptions("SheetN", "A", ColBestFit(true)), "sheet SheetN is not exist")
+	assert.EqualError(t, f.GetColOptions("SheetN", "A", &bestFit), "sheet SheetN is not exist")
+}
+
 func TestOutlineLevel(t *testing.T) {
 	f := NewFile()
 	level, err := f.GetColOutlineLevel("Sheet1", "D")
@@ -332,6 +400,79 @@ func TestColWidth(t *testing.T) {
 	convertRowHeightToPixels(0)
 }
 
+func TestColWidthPixels(t *testing.T) {
+	f := NewFile()
+	pixels, err := f.GetColWidthPixels("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultColWidthPixels, pixels)
+	assert.NoError(t, f.SetColWidthPixels("Sheet1", "A", "B", 100))
+	width, err := f.GetColWidth("Sheet1", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 14.28515625, width)
+	pixels, err = f.GetColWidthPixels("Sheet1", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, pixels)
+	// Test set column width in pixels with the default font of the spreadsheet.
+	f.Styles.Fonts.Font[0].Name.Val, f.Styles.Fonts.Font[0].Sz.Val = stringPtr("Arial"), float64Ptr(20)
+	assert.NoError(t, f.SetColWidthPixels("Sheet1", "C", "C", 100))
+	pixels, err = f.GetColWidthPixels("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, pixels)
+	assert.Equal(t, 15.0, f.getMaxDigitWidth())
+	f.Styles.Fonts.Font[0].Sz.Val = float64Ptr(0.1)
+	assert.Equal(t, 7.0, f.getMaxDigitWidth())
+	assert.EqualError(t, f.SetColWidthPixels("Sheet1", "A", "A", 10000), ErrColumnWidth.Error())
+	_, err = f.GetColWidthPixels("SheetN", "A")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test the column width conversion helpers.
+	for pixels := 1.0; pixels < 500; pixels++ {
+		assert.Equal(t, pixels, ColWidthToPixels(PixelsToColWidth(pixels, 7), 7))
+	}
+	assert.Equal(t, defaultColWidth, PixelsToColWidth(defaultColWidthPixels, 7))
+	assert.Equal(t, 0.0, PixelsToColWidth(-1, 7))
+	assert.Equal(t, 0.0, ColWidthToPixels(0, 7))
+}
+
+func TestAutoFitColWidth(t *testing.T) {
+	f := NewFile()
+	for cell, val := range map[string]interface{}{"A1": "Apple", "A2": "Pea", "B1": "Hello, world!", "C2": "中文字符", "D1": 3.1415926, "E3": ""} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, val))
+	}
+	style, err := f.NewStyle(&Style{Font: &Font{Family: "Arial", Size: 20, Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.SetColWidth("Sheet1", "E", "E", 20))
+	assert.NoError(t, f.AutoFitColWidth("Sheet1", "E:A"))
+	for col, expected := range map[string]float64{"A": 5.65234375, "B": 23.57421875, "C": 9.09375, "D": 9.73828125, "E": 20} {
+		width, err := f.GetColWidth("Sheet1", col)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, width, col)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFitColWidth.xlsx")))
+
+	// Test auto fit column width with the registered font metrics.
+	RegisterFontMetrics("Corporate Sans", testFontMetrics{})
+	defer fontMetrics.Delete("corporate sans")
+	style, err = f.NewStyle(&Style{Font: &Font{Family: "Corporate Sans", Size: 7}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", style))
+	assert.NoError(t, f.AutoFitColWidth("Sheet1", "A"))
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 5.7109375, width)
+
+	// Test auto fit column width with invalid columns range.
+	assert.EqualError(t, f.AutoFitColWidth("Sheet1", "*"), newInvalidColumnNameError("*").Error())
+	// Test auto fit column width on not exists worksheet.
+	assert.EqualError(t, f.AutoFitColWidth("SheetN", "A"), "sheet SheetN is not exist")
+	// Test auto fit column width with invalid cell reference.
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].R = "A"
+	assert.EqualError(t, f.AutoFitColWidth("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
 func TestInsertCol(t *testing.T) {
 	f := NewFile()
 	sheet1 := f.GetSheetName(0)
@@ -23,6 +23,13 @@ import (
 	"strings"
 )
 
+// Define the default size in pixels and fill color of the note shape.
+const (
+	defaultCommentWidth  = 128
+	defaultCommentHeight = 74
+	defaultCommentFill   = "#ffffe1"
+)
+
 // parseFormatCommentsSet provides a function to parse the format settings of
 // the comment with default value.
 func parseFormatCommentsSet(formatSet string) (*formatComment, error) {
@@ -39,6 +46,9 @@ func parseFormatCommentsSet(formatSet string) (*formatComment, error) {
 func (f *File) GetComments() (comments map[string][]Comment) {
 	comments = map[string][]Comment{}
 	for n, path := range f.sheetMap {
+		if !strings.HasPrefix(path, "xl/worksheets/") && f.getSheetType(path) != "worksheet" {
+			continue
+		}
 		target := f.getSheetComments(filepath.Base(path))
 		if target == "" {
 			continue
@@ -94,16 +104,52 @@ func (f *File) getSheetComments(sheetFile string) string {
 //
 //    err := f.AddComment("Sheet1", "A30", `{"author":"Excelize: ","text":"This is a comment."}`)
 //
+// The note shape can be customized by the optional format settings:
+//
+//    width        - The width of the note shape in pixels, default 128.
+//    height       - The height of the note shape in pixels, default 74 and
+//                   grows with the text lines.
+//    fill         - The fill color of the note shape in hex, default #FFFFE1.
+//    border_color - The border color of the note shape in hex, default black.
+//    border_width - The border width of the note shape in points, default
+//                   0.75.
+//    font         - The font of the comment text, the author is always bold.
+//
+// For example, add a comment with light blue fill, dark blue border and
+// 11pt Arial font in a 200 x 100 pixels note in Sheet1!$B$2:
+//
+//    err := f.AddComment("Sheet1", "B2", `{
+//        "author": "Excelize: ",
+//        "text": "This is a comment.",
+//        "width": 200,
+//        "height": 100,
+//        "fill": "#DDEBF7",
+//        "border_color": "#1F4E78",
+//        "border_width": 1.5,
+//        "font":
+//        {
+//            "family": "Arial",
+//            "size": 11,
+//            "color": "#1F4E78"
+//        }
+//    }`)
+//
 func (f *File) AddComment(sheet, cell, format string) error {
 	formatSet, err := parseFormatCommentsSet(format)
 	if err != nil {
 		return err
 	}
+	if err = validateCommentFormat(formatSet); err != nil {
+		return err
+	}
 	// Read sheet data.
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	commentID := f.countComments() + 1
 	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(commentID) + ".vml"
 	sheetRelationshipsComments := "../comments" + strconv.Itoa(commentID) + ".xml"
@@ -122,16 +168,16 @@ func (f *File) AddComment(sheet, cell, format string) error {
 		f.addSheetLegacyDrawing(sheet, rID)
 	}
 	commentsXML := "xl/comments" + strconv.Itoa(commentID) + ".xml"
-	var colCount int
-	for i, l := range strings.Split(formatSet.Text, "\n") {
-		if ll := len(l); ll > colCount {
-			if i == 0 {
-				ll += len(formatSet.Author)
-			}
-			colCount = ll
+	if formatSet.Width == 0 {
+		formatSet.Width = defaultCommentWidth
+	}
+	if formatSet.Height == 0 {
+		formatSet.Height = defaultCommentHeight
+		if lines := strings.Count(formatSet.Text, "\n") + 1; lines > 4 {
+			formatSet.Height += (lines - 4) * 16
 		}
 	}
-	err = f.addDrawingVML(commentID, drawingVML, cell, strings.Count(formatSet.Text, "\n")+1, colCount)
+	err = f.addDrawingVML(commentID, drawingVML, sheet, cell, formatSet)
 	if err != nil {
 		return err
 	}
@@ -140,9 +186,43 @@ func (f *File) AddComment(sheet, cell, format string) error {
 	return err
 }
 
+// validateCommentFormat provides a function to validate the note shape
+// settings of the comment.
+func validateCommentFormat(formatSet *formatComment) error {
+	if formatSet.Width < 0 || formatSet.Height < 0 {
+		return ErrParameterInvalid
+	}
+	if formatSet.BorderWidth < 0 {
+		return newInvalidStyleAttrError("comment border width", strconv.FormatFloat(formatSet.BorderWidth, 'f', -1, 64))
+	}
+	if formatSet.Fill != "" && !isRGBColor(formatSet.Fill) {
+		return newInvalidStyleAttrError("comment fill color", formatSet.Fill)
+	}
+	if formatSet.BorderColor != "" && !isRGBColor(formatSet.BorderColor) {
+		return newInvalidStyleAttrError("comment border color", formatSet.BorderColor)
+	}
+	if formatSet.Font != nil && formatSet.Font.Color != "" && !isRGBColor(formatSet.Font.Color) {
+		return ErrFontColor
+	}
+	return nil
+}
+
+// vmlColor provides a function to convert the hex color to the VML color
+// format, the alpha channel of the ARGB color will be ignored.
+func vmlColor(color string) string {
+	color = strings.ToLower(strings.TrimPrefix(color, "#"))
+	if len(color) == 8 {
+		color = color[2:]
+	}
+	return "#" + color
+}
+
 // addDrawingVML provides a function to create comment as
-// xl/drawings/vmlDrawing%d.vml by given commit ID and cell.
-func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount, colCount int) error {
+// xl/drawings/vmlDrawing%d.vml by given commit ID and cell. The note shape
+// is anchored to the right of the cell, and the margins and size of the
+// shape style are kept consistent with the anchor, since Excel for Mac
+// renders the note by the shape style instead of the anchor.
+func (f *File) addDrawingVML(commentID int, drawingVML, sheet, cell string, formatSet *formatComment) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -160,7 +240,7 @@ func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount,
 				Ext: "edit",
 				IDmap: &xlsxIDmap{
 					Ext:  "edit",
-					Data: commentID,
+					Data: f.getVMLIDMap(drawingVML),
 				},
 			},
 			Shapetype: &xlsxShapetype{
@@ -177,16 +257,51 @@ func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount,
 				},
 			},
 		}
+		// Keep the existing shapes of the drawing which loaded from the file.
+		if d := f.decodeVMLDrawingReader(drawingVML); d != nil {
+			for _, v := range d.Shape {
+				vml.Shape = append(vml.Shape, xlsxShape{
+					ID:           v.ID,
+					Type:         v.Type,
+					Style:        v.Style,
+					Fillcolor:    v.Fillcolor,
+					Insetmode:    v.Insetmode,
+					Strokecolor:  v.Strokecolor,
+					Strokeweight: v.Strokeweight,
+					Val:          v.Val,
+				})
+			}
+		}
+	}
+	fillColor := defaultCommentFill
+	if formatSet.Fill != "" {
+		fillColor = vmlColor(formatSet.Fill)
+	}
+	// The note shape starts from the next column of the cell, and the row
+	// above the cell except the first row.
+	fromCol, fromRow, offsetX, offsetY := col+1, row-1, 15, 10
+	if row == 1 {
+		fromRow, offsetY = 1, 2
+	}
+	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, fromCol, fromRow, offsetX, offsetY, formatSet.Width, formatSet.Height)
+	for offsetX >= f.getColWidth(sheet, fromCol) {
+		offsetX -= f.getColWidth(sheet, fromCol)
+		fromCol++
+	}
+	for offsetY >= f.getRowHeight(sheet, fromRow) {
+		offsetY -= f.getRowHeight(sheet, fromRow)
+		fromRow++
+	}
+	marginLeft, marginTop := offsetX, offsetY
+	for c := 1; c < colStart; c++ {
+		marginLeft += f.getColWidth(sheet, c)
+	}
+	for r := 1; r < rowStart; r++ {
+		marginTop += f.getRowHeight(sheet, r)
 	}
 	sp := encodeShape{
 		Fill: &vFill{
-			Color2: "#fbfe82",
-			Angle:  -180,
-			Type:   "gradient",
-			Fill: &oFill{
-				Ext:  "view",
-				Type: "gradientUnscaled",
-			},
+			Color2: fillColor,
 		},
 		Shadow: &vShadow{
 			On:       "t",
@@ -205,41 +320,40 @@ func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount,
 		ClientData: &xClientData{
 			ObjectType: "Note",
 			Anchor: fmt.Sprintf(
-				"%d, 23, %d, 0, %d, %d, %d, 5",
-				1+yAxis, 1+xAxis, 2+yAxis+lineCount, colCount+yAxis, 2+xAxis+lineCount),
-			AutoFill: "True",
+				"%d, %d, %d, %d, %d, %d, %d, %d",
+				colStart-1, offsetX, rowStart-1, offsetY, colEnd-1, x2, rowEnd-1, y2),
+			AutoFill: "False",
 			Row:      xAxis,
 			Column:   yAxis,
 		},
 	}
 	s, _ := xml.Marshal(sp)
 	shape := xlsxShape{
-		ID:          "_x0000_s1025",
-		Type:        "#_x0000_t202",
-		Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
-		Fillcolor:   "#fbf6d6",
-		Strokecolor: "#edeaa1",
-		Val:         string(s[13 : len(s)-14]),
-	}
-	d := f.decodeVMLDrawingReader(drawingVML)
-	if d != nil {
-		for _, v := range d.Shape {
-			s := xlsxShape{
-				ID:          "_x0000_s1025",
-				Type:        "#_x0000_t202",
-				Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
-				Fillcolor:   "#fbf6d6",
-				Strokecolor: "#edeaa1",
-				Val:         v.Val,
-			}
-			vml.Shape = append(vml.Shape, s)
-		}
+		ID:   fmt.Sprintf("_x0000_s%d", vml.Shapelayout.IDmap.Data*1024+len(vml.Shape)+1),
+		Type: "#_x0000_t202",
+		Style: fmt.Sprintf("position:absolute;margin-left:%spt;margin-top:%spt;width:%spt;height:%spt;z-index:%d;visibility:hidden",
+			pixelsToPoints(marginLeft), pixelsToPoints(marginTop), pixelsToPoints(formatSet.Width), pixelsToPoints(formatSet.Height), len(vml.Shape)+1),
+		Fillcolor: fillColor,
+		Insetmode: "auto",
+		Val:       string(s[13 : len(s)-14]),
+	}
+	if formatSet.BorderColor != "" {
+		shape.Strokecolor = vmlColor(formatSet.BorderColor)
+	}
+	if formatSet.BorderWidth > 0 {
+		shape.Strokeweight = strconv.FormatFloat(formatSet.BorderWidth, 'f', -1, 64) + "pt"
 	}
 	vml.Shape = append(vml.Shape, shape)
 	f.VMLDrawing[drawingVML] = vml
 	return err
 }
 
+// pixelsToPoints provides a function to convert the pixels to the points in
+// the VML style.
+func pixelsToPoints(pixels int) string {
+	return strconv.FormatFloat(float64(pixels)*0.75, 'f', -1, 64)
+}
+
 // addComment provides a function to create chart as xl/comments%d.xml by
 // given cell and format sets.
 func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
@@ -260,36 +374,16 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 		comments.Authors.Author = append(comments.Authors.Author, formatSet.Author)
 		authorID = len(comments.Authors.Author) - 1
 	}
-	defaultFont := f.GetDefaultFont()
 	bold := ""
+	authorRPr, textRPr := f.newCommentRPr(formatSet.Font), f.newCommentRPr(formatSet.Font)
+	authorRPr.B = &bold
 	cmt := xlsxComment{
 		Ref:      cell,
 		AuthorID: authorID,
 		Text: xlsxText{
 			R: []xlsxR{
-				{
-					RPr: &xlsxRPr{
-						B:  &bold,
-						Sz: &attrValFloat{Val: float64Ptr(9)},
-						Color: &xlsxColor{
-							Indexed: 81,
-						},
-						RFont:  &attrValString{Val: stringPtr(defaultFont)},
-						Family: &attrValInt{Val: intPtr(2)},
-					},
-					T: &xlsxT{Val: a},
-				},
-				{
-					RPr: &xlsxRPr{
-						Sz: &attrValFloat{Val: float64Ptr(9)},
-						Color: &xlsxColor{
-							Indexed: 81,
-						},
-						RFont:  &attrValString{Val: stringPtr(defaultFont)},
-						Family: &attrValInt{Val: intPtr(2)},
-					},
-					T: &xlsxT{Val: t},
-				},
+				{RPr: authorRPr, T: &xlsxT{Val: a}},
+				{RPr: textRPr, T: &xlsxT{Val: t}},
 			},
 		},
 	}
@@ -297,6 +391,43 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 	f.Comments[commentsXML] = comments
 }
 
+// newCommentRPr provides a function to create the run properties of the
+// comment text by given font settings.
+func (f *File) newCommentRPr(fnt *Font) *xlsxRPr {
+	rpr := &xlsxRPr{
+		Sz:     &attrValFloat{Val: float64Ptr(9)},
+		Color:  &xlsxColor{Indexed: 81},
+		RFont:  &attrValString{Val: stringPtr(f.GetDefaultFont())},
+		Family: &attrValInt{Val: intPtr(2)},
+	}
+	if fnt == nil {
+		return rpr
+	}
+	trueVal := ""
+	if fnt.Bold {
+		rpr.B = &trueVal
+	}
+	if fnt.Italic {
+		rpr.I = &trueVal
+	}
+	if fnt.Strike {
+		rpr.Strike = &trueVal
+	}
+	if fnt.Underline != "" {
+		rpr.U = &attrValString{Val: stringPtr(fnt.Underline)}
+	}
+	if fnt.Family != "" {
+		rpr.RFont = &attrValString{Val: stringPtr(fnt.Family)}
+	}
+	if fnt.Size > 0 {
+		rpr.Sz = &attrValFloat{Val: float64Ptr(fnt.Size)}
+	}
+	if fnt.Color != "" {
+		rpr.Color = &xlsxColor{RGB: getPaletteColor(fnt.Color)}
+	}
+	return rpr
+}
+
 // countComments provides a function to get comments files count storage in
 // the folder xl.
 func (f *File) countComments() int {
@@ -336,6 +467,54 @@ func (f *File) decodeVMLDrawingReader(path string) *decodeVmlDrawing {
 	return f.DecodeVMLDrawing[path]
 }
 
+// getVMLIDMap provides a function to get the drawing block ID of the VML
+// drawing part by given part path. The block ID of the existing part will be
+// kept, otherwise a new block ID will be allocated after the block IDs of all
+// the VML drawing parts in the workbook, which are shared by the comments
+// and the header and footer pictures, so the shape IDs _x0000_s<ID*1024+N>
+// in the different parts will not be duplicated.
+func (f *File) getVMLIDMap(drawingVML string) int {
+	if vml := f.VMLDrawing[drawingVML]; vml != nil && vml.Shapelayout != nil && vml.Shapelayout.IDmap != nil {
+		return vml.Shapelayout.IDmap.Data
+	}
+	var maxID int
+	for _, vml := range f.VMLDrawing {
+		if vml != nil && vml.Shapelayout != nil && vml.Shapelayout.IDmap != nil && vml.Shapelayout.IDmap.Data > maxID {
+			maxID = vml.Shapelayout.IDmap.Data
+		}
+	}
+	existing := -1
+	f.Pkg.Range(func(k, v interface{}) bool {
+		name := k.(string)
+		content, ok := v.([]byte)
+		if !ok || !strings.HasPrefix(name, "xl/drawings/") || !strings.HasSuffix(name, ".vml") {
+			return true
+		}
+		decode := new(decodeVmlShapelayout)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			Decode(decode); err != nil && err != io.EOF {
+			return true
+		}
+		for _, data := range strings.Split(decode.Shapelayout.IDmap.Data, ",") {
+			ID, err := strconv.Atoi(strings.TrimSpace(data))
+			if err != nil {
+				continue
+			}
+			if name == drawingVML && existing == -1 {
+				existing = ID
+			}
+			if ID > maxID {
+				maxID = ID
+			}
+		}
+		return true
+	})
+	if existing != -1 {
+		return existing
+	}
+	return maxID + 1
+}
+
 // vmlDrawingWriter provides a function to save xl/drawings/vmlDrawing%d.xml
 // after serialize structure.
 func (f *File) vmlDrawingWriter() {
@@ -43,6 +43,62 @@ func TestAddComments(t *testing.T) {
 	assert.EqualValues(t, len(NewFile().GetComments()), 0)
 }
 
+func TestAddCommentFormat(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddComment("Sheet1", "C5", `{"author":"Excelize: ","text":"This is a styled comment.","width":200,"height":100,"fill":"#DDEBF7","border_color":"#FF1F4E78","border_width":1.5,"font":{"family":"Arial","size":11,"color":"#1F4E78","italic":true,"underline":"single","strike":true,"bold":true}}`))
+	vml := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"]
+	if assert.Len(t, vml.Shape, 2) {
+		assert.Equal(t, "_x0000_s1025", vml.Shape[0].ID)
+		assert.Equal(t, "position:absolute;margin-left:59.25pt;margin-top:1.5pt;width:96pt;height:55.5pt;z-index:1;visibility:hidden", vml.Shape[0].Style)
+		assert.Equal(t, "#ffffe1", vml.Shape[0].Fillcolor)
+		assert.Contains(t, vml.Shape[0].Val, "<x:Anchor>1, 15, 0, 2, 3, 15, 3, 16</x:Anchor>")
+		assert.Equal(t, "_x0000_s1026", vml.Shape[1].ID)
+		assert.Equal(t, "position:absolute;margin-left:155.25pt;margin-top:52.5pt;width:150pt;height:75pt;z-index:2;visibility:hidden", vml.Shape[1].Style)
+		assert.Equal(t, "#ddebf7", vml.Shape[1].Fillcolor)
+		assert.Equal(t, "#1f4e78", vml.Shape[1].Strokecolor)
+		assert.Equal(t, "1.5pt", vml.Shape[1].Strokeweight)
+		assert.Contains(t, vml.Shape[1].Val, `<v:fill color2="#ddebf7"></v:fill>`)
+	}
+	rpr := f.Comments["xl/comments1.xml"].CommentList.Comment[1].Text.R[1].RPr
+	assert.Equal(t, "Arial", *rpr.RFont.Val)
+	assert.Equal(t, 11.0, *rpr.Sz.Val)
+	assert.Equal(t, "FF1F4E78", rpr.Color.RGB)
+	assert.Equal(t, "single", *rpr.U.Val)
+	assert.NotNil(t, rpr.B)
+	assert.NotNil(t, rpr.I)
+	assert.NotNil(t, rpr.Strike)
+	file := filepath.Join("test", "TestAddCommentFormat.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+
+	// Test add comments on the worksheet which already has comments.
+	f, err := OpenFile(file)
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddComment("Sheet1", "E10", `{"author":"Excelize: ","text":"1\n2\n3\n4\n5"}`))
+	assert.NoError(t, f.AddComment("Sheet1", "E20", `{"author":"Excelize: ","text":"This is a comment."}`))
+	vml = f.VMLDrawing["xl/drawings/vmlDrawing1.vml"]
+	if assert.Len(t, vml.Shape, 4) {
+		assert.Equal(t, "#1f4e78", vml.Shape[1].Strokecolor)
+		assert.Equal(t, "_x0000_s1027", vml.Shape[2].ID)
+		assert.Contains(t, vml.Shape[2].Style, "height:67.5pt")
+		assert.Equal(t, "_x0000_s1028", vml.Shape[3].ID)
+	}
+	assert.NoError(t, f.SaveAs(file))
+	assert.Len(t, f.GetComments()["Sheet1"], 4)
+
+	// Test add comment with invalid format settings.
+	for _, format := range []string{
+		`{"width":-1}`,
+		`{"fill":"#FFFFG1"}`,
+		`{"border_color":"red"}`,
+		`{"border_width":-1}`,
+		`{"font":{"color":"#FFFFG1"}}`,
+	} {
+		assert.Error(t, f.AddComment("Sheet1", "A1", format))
+	}
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", `{"fill":"#FFFFG1"}`), `invalid comment fill color "#FFFFG1"`)
+}
+
 func TestDecodeVMLDrawingReader(t *testing.T) {
 	f := NewFile()
 	path := "xl/drawings/vmlDrawing1.xml"
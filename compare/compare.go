@@ -0,0 +1,299 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+// Package compare providing a set of functions that allow you to compare two
+// versions of a spreadsheet document, and report the added or removed
+// worksheets and defined names, changed formulas and changed cell values
+// between them. The report can be rendered as human-readable text or used as
+// a structure in programs, for example, to check the workbook changes in the
+// continuous integration.
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// ChangeType defined the type of a difference between two workbooks.
+type ChangeType string
+
+// This section defines the currently supported change types.
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+)
+
+// symbol returns the prefix symbol of the change type in the report text.
+func (c ChangeType) symbol() string {
+	switch c {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	}
+	return "~"
+}
+
+// SheetChange directly maps an added or removed worksheet.
+type SheetChange struct {
+	Sheet string
+	Type  ChangeType
+}
+
+// NameChange directly maps an added, removed or modified defined name. The
+// Old and New are the references of the defined name.
+type NameChange struct {
+	Name  string
+	Scope string
+	Type  ChangeType
+	Old   string
+	New   string
+}
+
+// CellChange directly maps a changed formula or value of a cell.
+type CellChange struct {
+	Sheet string
+	Cell  string
+	Type  ChangeType
+	Old   string
+	New   string
+}
+
+// Report directly maps the differences between two workbooks.
+type Report struct {
+	Sheets   []SheetChange
+	Names    []NameChange
+	Formulas []CellChange
+	Values   []CellChange
+}
+
+// Empty returns true if there are no differences between the workbooks.
+func (r *Report) Empty() bool {
+	return len(r.Sheets) == 0 && len(r.Names) == 0 && len(r.Formulas) == 0 && len(r.Values) == 0
+}
+
+// String returns the human-readable text of the report.
+func (r *Report) String() string {
+	if r.Empty() {
+		return "no differences\n"
+	}
+	var b strings.Builder
+	if len(r.Sheets) > 0 {
+		b.WriteString("Sheets:\n")
+		for _, c := range r.Sheets {
+			fmt.Fprintf(&b, "  %s %s\n", c.Type.symbol(), c.Sheet)
+		}
+	}
+	if len(r.Names) > 0 {
+		b.WriteString("Defined names:\n")
+		for _, c := range r.Names {
+			fmt.Fprintf(&b, "  %s %s (%s): %s\n", c.Type.symbol(), c.Name, c.Scope, change(c.Old, c.New))
+		}
+	}
+	for _, section := range []struct {
+		title   string
+		changes []CellChange
+	}{
+		{"Formulas", r.Formulas},
+		{"Values", r.Values},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n", section.title)
+		for _, c := range section.changes {
+			fmt.Fprintf(&b, "  %s %s!%s: %s\n", c.Type.symbol(), c.Sheet, c.Cell, change(c.Old, c.New))
+		}
+	}
+	return b.String()
+}
+
+// change returns the text of the change from old to new value.
+func change(old, new string) string {
+	if old == "" {
+		return fmt.Sprintf("%q", new)
+	}
+	if new == "" {
+		return fmt.Sprintf("%q", old)
+	}
+	return fmt.Sprintf("%q -> %q", old, new)
+}
+
+// changeType returns the change type by given old and new value.
+func changeType(old, new string) ChangeType {
+	if old == "" {
+		return Added
+	}
+	if new == "" {
+		return Removed
+	}
+	return Modified
+}
+
+// OpenFiles provides a function to open the workbooks by given paths, and
+// compare the differences from the first workbook to the second one. For
+// example:
+//
+//    report, err := compare.OpenFiles("Book1.xlsx", "Book2.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    fmt.Print(report)
+//
+func OpenFiles(from, to string, opt ...excelize.Options) (*Report, error) {
+	a, err := excelize.OpenFile(from, opt...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := excelize.OpenFile(to, opt...)
+	if err != nil {
+		return nil, err
+	}
+	return Files(a, b)
+}
+
+// Files provides a function to compare the differences from the workbook
+// 'from' to the workbook 'to'. The added and removed worksheets, defined
+// names, and the changed formulas and cell values of the worksheets in both
+// workbooks will be reported. The cached values of the formula cells will not
+// be compared, and the chart sheets only be compared by name.
+func Files(from, to *excelize.File) (*Report, error) {
+	report := &Report{}
+	fromSheets, toSheets := from.GetSheetList(), to.GetSheetList()
+	for _, sheet := range fromSheets {
+		if indexOf(toSheets, sheet) == -1 {
+			report.Sheets = append(report.Sheets, SheetChange{Sheet: sheet, Type: Removed})
+		}
+	}
+	for _, sheet := range toSheets {
+		if indexOf(fromSheets, sheet) == -1 {
+			report.Sheets = append(report.Sheets, SheetChange{Sheet: sheet, Type: Added})
+		}
+	}
+	compareNames(report, from.GetDefinedName(), to.GetDefinedName())
+	for _, sheet := range toSheets {
+		if indexOf(fromSheets, sheet) == -1 {
+			continue
+		}
+		if err := compareSheet(report, from, to, sheet); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// indexOf returns the index of the string in the slice, or -1 if not found.
+func indexOf(a []string, x string) int {
+	for idx, n := range a {
+		if x == n {
+			return idx
+		}
+	}
+	return -1
+}
+
+// compareNames provides a function to compare the defined names.
+func compareNames(report *Report, from, to []excelize.DefinedName) {
+	key := func(dn excelize.DefinedName) string { return dn.Scope + "!" + dn.Name }
+	fromNames := make(map[string]excelize.DefinedName, len(from))
+	for _, dn := range from {
+		fromNames[key(dn)] = dn
+	}
+	for _, dn := range from {
+		if _, ok := findName(to, key(dn), key); !ok {
+			report.Names = append(report.Names, NameChange{Name: dn.Name, Scope: dn.Scope, Type: Removed, Old: dn.RefersTo})
+		}
+	}
+	for _, dn := range to {
+		old, ok := fromNames[key(dn)]
+		if !ok {
+			report.Names = append(report.Names, NameChange{Name: dn.Name, Scope: dn.Scope, Type: Added, New: dn.RefersTo})
+			continue
+		}
+		if old.RefersTo != dn.RefersTo {
+			report.Names = append(report.Names, NameChange{Name: dn.Name, Scope: dn.Scope, Type: Modified, Old: old.RefersTo, New: dn.RefersTo})
+		}
+	}
+}
+
+// findName returns the defined name by given key.
+func findName(names []excelize.DefinedName, k string, key func(excelize.DefinedName) string) (excelize.DefinedName, bool) {
+	for _, dn := range names {
+		if key(dn) == k {
+			return dn, true
+		}
+	}
+	return excelize.DefinedName{}, false
+}
+
+// compareSheet provides a function to compare the formulas and cell values
+// of the worksheet in both workbooks.
+func compareSheet(report *Report, from, to *excelize.File, sheet string) error {
+	fromRows, err := from.GetRows(sheet)
+	if err != nil {
+		return err
+	}
+	toRows, err := to.GetRows(sheet)
+	if err != nil {
+		return err
+	}
+	rows := len(fromRows)
+	if len(toRows) > rows {
+		rows = len(toRows)
+	}
+	for r := 0; r < rows; r++ {
+		fromRow, toRow := rowAt(fromRows, r), rowAt(toRows, r)
+		cols := len(fromRow)
+		if len(toRow) > cols {
+			cols = len(toRow)
+		}
+		for c := 0; c < cols; c++ {
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			fromFormula, err := from.GetCellFormula(sheet, cell)
+			if err != nil {
+				return err
+			}
+			toFormula, err := to.GetCellFormula(sheet, cell)
+			if err != nil {
+				return err
+			}
+			if fromFormula != toFormula {
+				report.Formulas = append(report.Formulas, CellChange{
+					Sheet: sheet, Cell: cell, Type: changeType(fromFormula, toFormula), Old: fromFormula, New: toFormula,
+				})
+				continue
+			}
+			if fromValue, toValue := valueAt(fromRow, c), valueAt(toRow, c); fromValue != toValue && fromFormula == "" {
+				report.Values = append(report.Values, CellChange{
+					Sheet: sheet, Cell: cell, Type: changeType(fromValue, toValue), Old: fromValue, New: toValue,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// rowAt returns the row by given index, or nil if the index out of range.
+func rowAt(rows [][]string, idx int) []string {
+	if idx < len(rows) {
+		return rows[idx]
+	}
+	return nil
+}
+
+// valueAt returns the cell value by given index, or empty string if the
+// index out of range.
+func valueAt(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package compare
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	from := excelize.NewFile()
+	assert.NoError(t, from.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, from.SetCellValue("Sheet1", "A2", 2))
+	assert.NoError(t, from.SetCellValue("Sheet1", "A4", "removed"))
+	assert.NoError(t, from.SetCellFormula("Sheet1", "B1", "SUM(A1:A2)"))
+	from.NewSheet("Sheet2")
+	assert.NoError(t, from.SetDefinedName(&excelize.DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, from.SetDefinedName(&excelize.DefinedName{Name: "Obsolete", RefersTo: "Sheet1!$A$2"}))
+
+	to := excelize.NewFile()
+	assert.NoError(t, to.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, to.SetCellValue("Sheet1", "A2", 3))
+	assert.NoError(t, to.SetCellValue("Sheet1", "A3", "added"))
+	assert.NoError(t, to.SetCellFormula("Sheet1", "B1", "SUM(A1:A3)"))
+	assert.NoError(t, to.SetCellFormula("Sheet1", "B2", "A1*2"))
+	to.NewSheet("Sheet3")
+	assert.NoError(t, to.SetDefinedName(&excelize.DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$2"}))
+	assert.NoError(t, to.SetDefinedName(&excelize.DefinedName{Name: "Total", RefersTo: "Sheet1!$B$1", Scope: "Sheet1"}))
+
+	report, err := Files(from, to)
+	assert.NoError(t, err)
+	assert.False(t, report.Empty())
+	assert.Equal(t, []SheetChange{{Sheet: "Sheet2", Type: Removed}, {Sheet: "Sheet3", Type: Added}}, report.Sheets)
+	assert.Equal(t, []NameChange{
+		{Name: "Obsolete", Scope: "Workbook", Type: Removed, Old: "Sheet1!$A$2"},
+		{Name: "Amount", Scope: "Workbook", Type: Modified, Old: "Sheet1!$A$1", New: "Sheet1!$A$2"},
+		{Name: "Total", Scope: "Sheet1", Type: Added, New: "Sheet1!$B$1"},
+	}, report.Names)
+	assert.Equal(t, []CellChange{
+		{Sheet: "Sheet1", Cell: "B1", Type: Modified, Old: "SUM(A1:A2)", New: "SUM(A1:A3)"},
+		{Sheet: "Sheet1", Cell: "B2", Type: Added, New: "A1*2"},
+	}, report.Formulas)
+	assert.Equal(t, []CellChange{
+		{Sheet: "Sheet1", Cell: "A2", Type: Modified, Old: "2", New: "3"},
+		{Sheet: "Sheet1", Cell: "A3", Type: Added, New: "added"},
+		{Sheet: "Sheet1", Cell: "A4", Type: Removed, Old: "removed"},
+	}, report.Values)
+	assert.Equal(t, `Sheets:
+  - Sheet2
+  + Sheet3
+Defined names:
+  - Obsolete (Workbook): "Sheet1!$A$2"
+  ~ Amount (Workbook): "Sheet1!$A$1" -> "Sheet1!$A$2"
+  + Total (Sheet1): "Sheet1!$B$1"
+Formulas:
+  ~ Sheet1!B1: "SUM(A1:A2)" -> "SUM(A1:A3)"
+  + Sheet1!B2: "A1*2"
+Values:
+  ~ Sheet1!A2: "2" -> "3"
+  + Sheet1!A3: "added"
+  - Sheet1!A4: "removed"
+`, report.String())
+
+	report, err = Files(to, to)
+	assert.NoError(t, err)
+	assert.True(t, report.Empty())
+	assert.Equal(t, "no differences\n", report.String())
+}
+
+func TestOpenFiles(t *testing.T) {
+	path := filepath.Join("..", "test", "Book1.xlsx")
+	report, err := OpenFiles(path, path)
+	assert.NoError(t, err)
+	assert.True(t, report.Empty())
+	_, err = OpenFiles(filepath.Join("..", "test", "NotExist.xlsx"), path)
+	assert.Error(t, err)
+	_, err = OpenFiles(path, filepath.Join("..", "test", "NotExist.xlsx"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,583 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
+	"github.com/xuri/efp"
+)
+
+// condFmtCellRefRegexp defined the regular expression to match the cell
+// reference, the column reference or the row reference in the range
+// reference of the formula, which will be shifted when evaluate the formula
+// for the cell in the conditional formatting range.
+var condFmtCellRefRegexp = regexp.MustCompile(`^(\$?)([A-Za-z]{0,3})(\$?)([0-9]*)$`)
+
+// condFmtContext directly maps the values used in the evaluation of the
+// conditional formatting rules of the cell.
+type condFmtContext struct {
+	f          *File
+	ws         *xlsxWorksheet
+	sheet      string
+	cell       string
+	col, row   int
+	value      string
+	isNum      bool
+	isErr      bool
+	num        float64
+	sqref      string
+	rangeCache map[string][]condFmtValue
+}
+
+// condFmtValue directly maps the value of the cell in the conditional
+// formatting range.
+type condFmtValue struct {
+	value string
+	isNum bool
+	num   float64
+}
+
+// GetCellConditionalStyle provides a function to evaluate the conditional
+// formatting rules of the worksheet against the current cell values, and get
+// the effective style of the conditional formats which applied to the cell
+// by given worksheet name and cell reference. The styles of the applied
+// rules are merged in the priority order, the font, fill, border, alignment,
+// protection and number format settings of the rule with the smaller
+// priority number take precedence, and the evaluation will be stopped after
+// the applied rule with the stop if true setting. The color scale rules will
+// be resolved into the solid fill color of the cell value. The data bar and
+// icon set rules will be ignored, and it returns nil if no rule applied to
+// the cell. For example, get the effective conditional style of Sheet1!A1:
+//
+//    style, err := f.GetCellConditionalStyle("Sheet1", "A1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if style != nil && style.Font != nil {
+//        fmt.Println(style.Font.Color)
+//    }
+//
+// Note that the formulas in the cell value and expression rules are
+// evaluated by the CalcCellValue engine, so the formula functions which are
+// not supported by the CalcCellValue function could not be evaluated.
+func (f *File) GetCellConditionalStyle(sheet, cell string) (*Style, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &condFmtContext{f: f, ws: ws, sheet: sheet, cell: cell, col: col, row: row, rangeCache: make(map[string][]condFmtValue)}
+	if c := ctx.getCell(col, row); c != nil {
+		v := ctx.getValue(c)
+		ctx.value, ctx.isNum, ctx.num, ctx.isErr = v.value, v.isNum, v.num, c.T == "e"
+	}
+	type condFmtRuleRef struct {
+		sqref string
+		rule  *xlsxCfRule
+	}
+	var rules []condFmtRuleRef
+	for _, cf := range ws.ConditionalFormatting {
+		if cellInSqref(cf.SQRef, col, row) {
+			for _, cr := range cf.CfRule {
+				rules = append(rules, condFmtRuleRef{sqref: cf.SQRef, rule: cr})
+			}
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].rule.Priority < rules[j].rule.Priority })
+	var style *Style
+	theme := f.themeReader()
+	for _, r := range rules {
+		ctx.sqref = r.sqref
+		var ruleStyle *Style
+		if r.rule.Type == "colorScale" {
+			if color := ctx.evalColorScale(r.rule.ColorScale, theme); color != "" {
+				ruleStyle = &Style{Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{color}}}
+			}
+		} else if ctx.evalRule(r.rule) && r.rule.DxfID != nil {
+			ruleStyle = f.getConditionalStyle(*r.rule.DxfID, theme)
+		} else {
+			continue
+		}
+		if ruleStyle == nil {
+			continue
+		}
+		style = mergeConditionalStyle(style, ruleStyle)
+		if r.rule.StopIfTrue {
+			break
+		}
+	}
+	return style, err
+}
+
+// mergeConditionalStyle provides a function to merge the style settings of
+// the conditional formatting rule into the style settings of the rules with
+// the higher priorities.
+func mergeConditionalStyle(style, ruleStyle *Style) *Style {
+	if style == nil {
+		return ruleStyle
+	}
+	if style.Font == nil {
+		style.Font = ruleStyle.Font
+	}
+	if style.Fill.Type == "" {
+		style.Fill = ruleStyle.Fill
+	}
+	if len(style.Border) == 0 {
+		style.Border = ruleStyle.Border
+	}
+	if style.Alignment == nil {
+		style.Alignment = ruleStyle.Alignment
+	}
+	if style.Protection == nil {
+		style.Protection = ruleStyle.Protection
+	}
+	if style.NumFmt == 0 && style.CustomNumFmt == nil {
+		style.NumFmt, style.CustomNumFmt = ruleStyle.NumFmt, ruleStyle.CustomNumFmt
+	}
+	return style
+}
+
+// cellInSqref provides a function to check if the cell is in the range
+// references which are separated by spaces.
+func cellInSqref(sqref string, col, row int) bool {
+	for _, ref := range strings.Fields(sqref) {
+		if coordinates, ok := condFmtRefCoordinates(ref); ok &&
+			col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+			return true
+		}
+	}
+	return false
+}
+
+// condFmtRefCoordinates provides a function to get the coordinates of the
+// cell reference or the range reference, the whole columns and rows range
+// reference are also supported.
+func condFmtRefCoordinates(ref string) ([]int, bool) {
+	cells := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	coordinates := make([]int, 0, 4)
+	for i, cell := range cells[:2] {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			if col, err = ColumnNameToNumber(cell); err == nil {
+				row = []int{1, TotalRows}[i]
+			} else if row, err = strconv.Atoi(cell); err == nil {
+				col = []int{1, TotalColumns}[i]
+			} else {
+				return nil, false
+			}
+		}
+		coordinates = append(coordinates, col, row)
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, true
+}
+
+// getCell provides a function to get the cell in the worksheet by given
+// coordinates, it returns nil if the cell doesn't exist.
+func (ctx *condFmtContext) getCell(col, row int) *xlsxC {
+	for i := range ctx.ws.SheetData.Row {
+		r := &ctx.ws.SheetData.Row[i]
+		if r.R != row {
+			continue
+		}
+		for j := range r.C {
+			if c, _, err := CellNameToCoordinates(r.C[j].R); err == nil && c == col {
+				return &r.C[j]
+			}
+		}
+	}
+	return nil
+}
+
+// getValue provides a function to get the unformatted value of the cell.
+func (ctx *condFmtContext) getValue(c *xlsxC) condFmtValue {
+	v := condFmtValue{value: c.V}
+	switch c.T {
+	case "s":
+		sst := ctx.f.sharedStringsReader()
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(sst.SI) {
+			v.value = sst.SI[idx].String()
+		}
+	case "inlineStr":
+		if c.IS != nil {
+			v.value = c.IS.String()
+		}
+	case "", "n":
+		num, err := strconv.ParseFloat(c.V, 64)
+		v.isNum, v.num = err == nil, num
+	}
+	return v
+}
+
+// getRangeValues provides a function to get the values of the existing cells
+// in the conditional formatting range.
+func (ctx *condFmtContext) getRangeValues() []condFmtValue {
+	if values, ok := ctx.rangeCache[ctx.sqref]; ok {
+		return values
+	}
+	var values []condFmtValue
+	for i := range ctx.ws.SheetData.Row {
+		r := &ctx.ws.SheetData.Row[i]
+		for j := range r.C {
+			if col, row, err := CellNameToCoordinates(r.C[j].R); err == nil && cellInSqref(ctx.sqref, col, row) {
+				if v := ctx.getValue(&r.C[j]); v.value != "" {
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	ctx.rangeCache[ctx.sqref] = values
+	return values
+}
+
+// getRangeNumbers provides a function to get the sorted numeric values in
+// the conditional formatting range.
+func (ctx *condFmtContext) getRangeNumbers() []float64 {
+	var nums []float64
+	for _, v := range ctx.getRangeValues() {
+		if v.isNum {
+			nums = append(nums, v.num)
+		}
+	}
+	sort.Float64s(nums)
+	return nums
+}
+
+// evalFormula provides a function to evaluate the formula of the conditional
+// formatting rule for the cell, the relative references in the formula are
+// relative to the top left cell of the conditional formatting range.
+func (ctx *condFmtContext) evalFormula(formula string) (string, error) {
+	if num, err := strconv.ParseFloat(formula, 64); err == nil {
+		return strconv.FormatFloat(num, 'f', -1, 64), nil
+	}
+	if len(formula) > 1 && strings.HasPrefix(formula, `"`) && strings.HasSuffix(formula, `"`) && !strings.Contains(strings.Replace(formula[1:len(formula)-1], `""`, "", -1), `"`) {
+		return strings.Replace(formula[1:len(formula)-1], `""`, `"`, -1), nil
+	}
+	col, row, err := CellNameToCoordinates(condFmtTopLeftCell(ctx.sqref))
+	if err != nil {
+		return "", err
+	}
+	ast, err := parse.Formula(formula)
+	if err != nil {
+		return "", err
+	}
+	ast.RewriteReferences(func(ref parse.Reference) parse.Reference {
+		if ref.Type == parse.ReferenceCell || ref.Type == parse.ReferenceRange {
+			parts := strings.Split(ref.Ref, ":")
+			for i, part := range parts {
+				parts[i] = shiftCondFmtCellRef(part, ctx.col-col, ctx.row-row)
+			}
+			ref.Ref = strings.Join(parts, ":")
+		}
+		return ref
+	})
+	ps := efp.ExcelParser()
+	token, err := ctx.f.evalInfixExp(ctx.sheet, ctx.cell, ps.Parse(ast.String()))
+	return token.TValue, err
+}
+
+// shiftCondFmtCellRef provides a function to shift the relative column and
+// row of the cell reference by given offsets.
+func shiftCondFmtCellRef(ref string, cols, rows int) string {
+	match := condFmtCellRefRegexp.FindStringSubmatch(ref)
+	if match == nil {
+		return ref
+	}
+	if match[2] != "" && match[1] == "" {
+		if col, err := ColumnNameToNumber(match[2]); err == nil {
+			match[2], _ = ColumnNumberToName(col + cols)
+		}
+	}
+	if match[4] != "" && match[3] == "" {
+		if row, err := strconv.Atoi(match[4]); err == nil {
+			match[4] = strconv.Itoa(row + rows)
+		}
+	}
+	return match[1] + match[2] + match[3] + match[4]
+}
+
+// isTruthy checks if the result of the formula is true.
+func isTruthy(result string) bool {
+	if num, err := strconv.ParseFloat(result, 64); err == nil {
+		return num != 0
+	}
+	return strings.EqualFold(result, "TRUE")
+}
+
+// compareValue provides a function to compare the cell value with the
+// value, the numbers are compared numerically, the texts are compared case
+// insensitively, and the numbers are less than the texts.
+func (ctx *condFmtContext) compareValue(value string) int {
+	num, err := strconv.ParseFloat(value, 64)
+	if ctx.isNum && err == nil {
+		switch {
+		case ctx.num < num:
+			return -1
+		case ctx.num > num:
+			return 1
+		}
+		return 0
+	}
+	if ctx.isNum != (err == nil) {
+		if ctx.isNum {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(strings.ToLower(ctx.value), strings.ToLower(value))
+}
+
+// evalRule provides a function to check if the conditional formatting rule
+// applies to the cell.
+func (ctx *condFmtContext) evalRule(rule *xlsxCfRule) bool {
+	switch rule.Type {
+	case "cellIs":
+		return ctx.evalCellIs(rule)
+	case "expression":
+		if len(rule.Formula) == 0 {
+			return false
+		}
+		result, err := ctx.evalFormula(rule.Formula[0])
+		return err == nil && isTruthy(result)
+	case "containsText", "notContainsText", "beginsWith", "endsWith":
+		value, text := strings.ToLower(ctx.value), strings.ToLower(rule.Text)
+		return map[string]bool{
+			"containsText":    strings.Contains(value, text),
+			"notContainsText": !strings.Contains(value, text),
+			"beginsWith":      strings.HasPrefix(value, text),
+			"endsWith":        strings.HasSuffix(value, text),
+		}[rule.Type]
+	case "containsBlanks":
+		return strings.TrimSpace(ctx.value) == ""
+	case "notContainsBlanks":
+		return strings.TrimSpace(ctx.value) != ""
+	case "containsErrors":
+		return ctx.isErr
+	case "notContainsErrors":
+		return !ctx.isErr
+	case "timePeriod":
+		return ctx.isNum && ctx.evalTimePeriod(rule.TimePeriod)
+	case "top10":
+		return ctx.isNum && ctx.evalTop10(rule)
+	case "aboveAverage":
+		return ctx.isNum && ctx.evalAboveAverage(rule)
+	case "duplicateValues", "uniqueValues":
+		if strings.TrimSpace(ctx.value) == "" {
+			return false
+		}
+		var count int
+		for _, v := range ctx.getRangeValues() {
+			if strings.EqualFold(v.value, ctx.value) {
+				count++
+			}
+		}
+		return (count > 1) == (rule.Type == "duplicateValues")
+	}
+	return false
+}
+
+// evalCellIs provides a function to check if the cell value rule applies to
+// the cell.
+func (ctx *condFmtContext) evalCellIs(rule *xlsxCfRule) bool {
+	var results []int
+	for _, formula := range rule.Formula {
+		value, err := ctx.evalFormula(formula)
+		if err != nil {
+			return false
+		}
+		results = append(results, ctx.compareValue(value))
+	}
+	if len(results) == 0 {
+		return false
+	}
+	switch rule.Operator {
+	case "between", "notBetween":
+		if len(results) < 2 {
+			return false
+		}
+		between := results[0] >= 0 && results[1] <= 0
+		return between == (rule.Operator == "between")
+	case "equal":
+		return results[0] == 0
+	case "notEqual":
+		return results[0] != 0
+	case "greaterThan":
+		return results[0] > 0
+	case "lessThan":
+		return results[0] < 0
+	case "greaterThanOrEqual":
+		return results[0] >= 0
+	case "lessThanOrEqual":
+		return results[0] <= 0
+	}
+	return false
+}
+
+// evalTimePeriod provides a function to check if the date of the cell value
+// occurring in the time period by given time period type.
+func (ctx *condFmtContext) evalTimePeriod(period string) bool {
+	var date1904 bool
+	if wb := ctx.f.workbookReader(); wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	t := timeFromExcelTime(math.Floor(ctx.num), date1904)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(math.Round(date.Sub(today).Hours() / 24))
+	weekStart := -int(today.Weekday())
+	months := (date.Year()-today.Year())*12 + int(date.Month()) - int(today.Month())
+	return map[string]bool{
+		"yesterday": days == -1,
+		"today":     days == 0,
+		"tomorrow":  days == 1,
+		"last7Days": days >= -6 && days <= 0,
+		"lastWeek":  days >= weekStart-7 && days < weekStart,
+		"thisWeek":  days >= weekStart && days < weekStart+7,
+		"nextWeek":  days >= weekStart+7 && days < weekStart+14,
+		"lastMonth": months == -1,
+		"thisMonth": months == 0,
+		"nextMonth": months == 1,
+	}[period]
+}
+
+// evalTop10 provides a function to check if the cell value is in the top or
+// bottom N or N percent values of the conditional formatting range.
+func (ctx *condFmtContext) evalTop10(rule *xlsxCfRule) bool {
+	nums := ctx.getRangeNumbers()
+	rank := rule.Rank
+	if rule.Percent {
+		rank = int(float64(len(nums)) * float64(rule.Rank) / 100)
+	}
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(nums) {
+		rank = len(nums)
+	}
+	if rule.Bottom {
+		return ctx.num <= nums[rank-1]
+	}
+	return ctx.num >= nums[len(nums)-rank]
+}
+
+// evalAboveAverage provides a function to check if the cell value is above
+// or below the average of the values in the conditional formatting range.
+func (ctx *condFmtContext) evalAboveAverage(rule *xlsxCfRule) bool {
+	nums := ctx.getRangeNumbers()
+	var sum, variance float64
+	for _, num := range nums {
+		sum += num
+	}
+	avg := sum / float64(len(nums))
+	for _, num := range nums {
+		variance += (num - avg) * (num - avg)
+	}
+	threshold := avg
+	above := rule.AboveAverage == nil || *rule.AboveAverage
+	if rule.StdDev > 0 {
+		stdDev := math.Sqrt(variance/float64(len(nums))) * float64(rule.StdDev)
+		if threshold += stdDev; !above {
+			threshold = avg - stdDev
+		}
+	}
+	if above {
+		return ctx.num > threshold || rule.EqualAverage && ctx.num == threshold
+	}
+	return ctx.num < threshold || rule.EqualAverage && ctx.num == threshold
+}
+
+// evalColorScale provides a function to get the color of the cell value in
+// the color scale rule, it returns empty string if the cell value isn't a
+// number.
+func (ctx *condFmtContext) evalColorScale(colorScale *xlsxColorScale, theme *xlsxTheme) string {
+	if colorScale == nil || !ctx.isNum || len(colorScale.Cfvo) < 2 || len(colorScale.Cfvo) != len(colorScale.Color) {
+		return ""
+	}
+	nums := ctx.getRangeNumbers()
+	minNum, maxNum := nums[0], nums[len(nums)-1]
+	var thresholds []float64
+	for _, cfvo := range colorScale.Cfvo {
+		var threshold float64
+		switch cfvo.Type {
+		case "min":
+			threshold = minNum
+		case "max":
+			threshold = maxNum
+		case "percent":
+			p, _ := strconv.ParseFloat(cfvo.Val, 64)
+			threshold = minNum + (maxNum-minNum)*p/100
+		case "percentile":
+			p, _ := strconv.ParseFloat(cfvo.Val, 64)
+			threshold = condFmtPercentile(nums, p/100)
+		default:
+			value, err := ctx.evalFormula(cfvo.Val)
+			if err != nil {
+				return ""
+			}
+			threshold, _ = strconv.ParseFloat(value, 64)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	if ctx.num <= thresholds[0] {
+		return getColorRGB(colorScale.Color[0], theme)
+	}
+	for i := 1; i < len(thresholds); i++ {
+		if ctx.num > thresholds[i] {
+			continue
+		}
+		from, to := getColorRGB(colorScale.Color[i-1], theme), getColorRGB(colorScale.Color[i], theme)
+		if from == "" || to == "" || thresholds[i] == thresholds[i-1] {
+			return to
+		}
+		return interpolateColor(from, to, (ctx.num-thresholds[i-1])/(thresholds[i]-thresholds[i-1]))
+	}
+	return getColorRGB(colorScale.Color[len(colorScale.Color)-1], theme)
+}
+
+// condFmtPercentile provides a function to get the inclusive percentile of
+// the sorted numbers.
+func condFmtPercentile(nums []float64, k float64) float64 {
+	idx := k * float64(len(nums)-1)
+	lower := int(math.Floor(idx))
+	if lower+1 >= len(nums) {
+		return nums[len(nums)-1]
+	}
+	return nums[lower] + (idx-float64(lower))*(nums[lower+1]-nums[lower])
+}
+
+// interpolateColor provides a function to get the linear interpolated color
+// between the two colors in the format of #RRGGBB by given ratio.
+func interpolateColor(from, to string, ratio float64) string {
+	var sb strings.Builder
+	sb.WriteString("#")
+	for i := 1; i < 7; i += 2 {
+		a, _ := strconv.ParseUint(from[i:i+2], 16, 8)
+		b, _ := strconv.ParseUint(to[i:i+2], 16, 8)
+		sb.WriteString(strings.ToUpper(strconv.FormatInt(int64(math.Round(float64(a)+(float64(b)-float64(a))*ratio))|0x100, 16)[1:]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+package excelize
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCellConditionalStyle(t *testing.T) {
+	f := NewFile()
+	red, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	bold, err := f.NewConditionalStyle(`{"font":{"bold":true},"border":[{"type":"left","color":"#0000FF","style":1}]}`)
+	assert.NoError(t, err)
+	for r, v := range []interface{}{1, 5, 9, 12, "apple", "error", "", 3, 3, 20} {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", r+1), v))
+	}
+	getFontColor := func(cell string) string {
+		style, err := f.GetCellConditionalStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		if style == nil || style.Font == nil {
+			return ""
+		}
+		return style.Font.Color
+	}
+	// Test cell value rule
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A4", fmt.Sprintf(`[{"type":"cell","criteria":"between","format":%d,"minimum":"4","maximum":"10"}]`, red)))
+	assert.Equal(t, "", getFontColor("A1"))
+	assert.Equal(t, "#9A0511", getFontColor("A2"))
+	assert.Equal(t, "#9A0511", getFontColor("A3"))
+	assert.Equal(t, "", getFontColor("A4"))
+	// Test cell out of the range
+	assert.Equal(t, "", getFontColor("A8"))
+	style, err := f.GetCellConditionalStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Nil(t, style)
+
+	// Test merge the styles by priority and stop if true
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A4", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"8"}]`, bold)))
+	style, err = f.GetCellConditionalStyle("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "#9A0511", style.Font.Color)
+	assert.False(t, style.Font.Bold)
+	assert.Equal(t, []string{"#FEC7CE"}, style.Fill.Color)
+	assert.Len(t, style.Border, 1)
+	style, err = f.GetCellConditionalStyle("Sheet1", "A4")
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Bold)
+	assert.NoError(t, f.SetConditionalFormatStopIfTrue("Sheet1", "A1:A4", 0, true))
+	style, err = f.GetCellConditionalStyle("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Len(t, style.Border, 0)
+
+	// Test text, blanks, duplicate and expression rules
+	f.NewSheet("Sheet2")
+	for r, v := range []interface{}{"apple", "Banana", "", "apple", 10, 20} {
+		assert.NoError(t, f.SetCellValue("Sheet2", fmt.Sprintf("A%d", r+1), v))
+		assert.NoError(t, f.SetCellValue("Sheet2", fmt.Sprintf("B%d", r+1), r))
+	}
+	assert.NoError(t, f.SetConditionalFormat("Sheet2", "A1:A6", fmt.Sprintf(`[{"type":"text","criteria":"begins with","format":%d,"value":"BAN"}]`, red)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet2", "A1:A6", fmt.Sprintf(`[{"type":"duplicate","criteria":"=","format":%d}]`, bold)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet2", "C1:C6", fmt.Sprintf(`[{"type":"blanks","format":%d}]`, red)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet2", "B1:B6", fmt.Sprintf(`[{"type":"formula","criteria":"AND($B1>2,A1>15)","format":%d}]`, red)))
+	for cell, expected := range map[string]interface{}{"A1": true, "A2": "#9A0511", "A3": nil, "A4": true, "C2": "#9A0511", "B5": "", "B6": "#9A0511"} {
+		style, err := f.GetCellConditionalStyle("Sheet2", cell)
+		assert.NoError(t, err)
+		switch expected := expected.(type) {
+		case bool:
+			assert.True(t, style.Font.Bold, cell)
+		case string:
+			if expected == "" {
+				assert.Nil(t, style, cell)
+				continue
+			}
+			assert.Equal(t, expected, style.Font.Color, cell)
+		default:
+			assert.Nil(t, style, cell)
+		}
+	}
+
+	// Test top, average and time period rules
+	f.NewSheet("Sheet3")
+	for r, v := range []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+		assert.NoError(t, f.SetCellValue("Sheet3", fmt.Sprintf("A%d", r+1), v))
+		assert.NoError(t, f.SetCellValue("Sheet3", fmt.Sprintf("B%d", r+1), v))
+		assert.NoError(t, f.SetCellValue("Sheet3", fmt.Sprintf("C%d", r+1), v))
+	}
+	assert.NoError(t, f.SetConditionalFormat("Sheet3", "A1:A10", fmt.Sprintf(`[{"type":"top","criteria":"=","format":%d,"value":"3"}]`, red)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet3", "B1:B10", fmt.Sprintf(`[{"type":"bottom","criteria":"=","format":%d,"value":"20","percent":true}]`, red)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet3", "C1:C10", fmt.Sprintf(`[{"type":"average","criteria":"=","format":%d,"above_average":false}]`, red)))
+	for cell, expected := range map[string]bool{"A7": false, "A8": true, "A10": true, "B2": true, "B3": false, "C5": true, "C6": false} {
+		style, err := f.GetCellConditionalStyle("Sheet3", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, style != nil, cell)
+	}
+	assert.NoError(t, f.SetCellValue("Sheet3", "D1", time.Now()))
+	assert.NoError(t, f.SetCellValue("Sheet3", "D2", time.Now().AddDate(0, 0, -1)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet3", "D1:D2", fmt.Sprintf(`[{"type":"time_period","criteria":"today","format":%d}]`, red)))
+	style, err = f.GetCellConditionalStyle("Sheet3", "D1")
+	assert.NoError(t, err)
+	assert.NotNil(t, style)
+	style, err = f.GetCellConditionalStyle("Sheet3", "D2")
+	assert.NoError(t, err)
+	assert.Nil(t, style)
+
+	// Test color scale rule
+	assert.NoError(t, f.SetConditionalFormat("Sheet3", "E1:E3", `[{"type":"2_color_scale","criteria":"=","min_type":"min","max_type":"max","min_color":"#000000","max_color":"#FFFFFF"}]`))
+	for r, v := range []int{0, 5, 10} {
+		assert.NoError(t, f.SetCellValue("Sheet3", fmt.Sprintf("E%d", r+1), v))
+	}
+	for cell, expected := range map[string]string{"E1": "#000000", "E2": "#808080", "E3": "#FFFFFF"} {
+		style, err := f.GetCellConditionalStyle("Sheet3", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{expected}}, style.Fill, cell)
+	}
+
+	// Test get conditional style with not exist worksheet
+	_, err = f.GetCellConditionalStyle("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get conditional style with invalid cell reference
+	_, err = f.GetCellConditionalStyle("Sheet1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestShiftCondFmtCellRef(t *testing.T) {
+	assert.Equal(t, "B3", shiftCondFmtCellRef("A1", 1, 2))
+	assert.Equal(t, "$A3", shiftCondFmtCellRef("$A1", 1, 2))
+	assert.Equal(t, "B$1", shiftCondFmtCellRef("A$1", 1, 2))
+	assert.Equal(t, "C", shiftCondFmtCellRef("B", 1, 2))
+	assert.Equal(t, "Rate", shiftCondFmtCellRef("Rate", 1, 2))
+	assert.Equal(t, "#808080", interpolateColor("#000000", "#FFFFFF", 0.5))
+	assert.False(t, cellInSqref("A1:-", 1, 1))
+	assert.True(t, cellInSqref("B2 C:C", 3, 100))
+}
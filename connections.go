@@ -0,0 +1,198 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// defaultXMLPathConnections defined the path of the workbook connections
+// part.
+const defaultXMLPathConnections = "xl/connections.xml"
+
+// connectionsReader provides a function to get the pointer to the structure
+// after deserialization of xl/connections.xml.
+func (f *File) connectionsReader() (*xlsxConnections, error) {
+	if f.connections == nil {
+		content, ok := f.Pkg.Load(defaultXMLPathConnections)
+		if !ok {
+			return nil, nil
+		}
+		connections := new(xlsxConnections)
+		if _, ok := f.xmlAttr[defaultXMLPathConnections]; !ok {
+			d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte))))
+			f.xmlAttr[defaultXMLPathConnections] = append(f.xmlAttr[defaultXMLPathConnections], getRootElement(d)...)
+		}
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(connections); err != nil && err != io.EOF {
+			return nil, err
+		}
+		ns := f.xmlAttr[defaultXMLPathConnections]
+		for idx := range connections.Connection {
+			conn := &connections.Connection[idx]
+			localNameSpaceAttrs(conn.Attr, ns)
+			if conn.DbPr != nil {
+				localNameSpaceAttrs(conn.DbPr.Attr, ns)
+			}
+			for _, pr := range []*xlsxConnectionPr{conn.OlapPr, conn.WebPr, conn.TextPr, conn.Parameters} {
+				if pr != nil {
+					localNameSpaceAttrs(pr.Attr, ns)
+				}
+			}
+		}
+		f.connections = connections
+	}
+	return f.connections, nil
+}
+
+// localNameSpaceAttrs provides a function to replace the namespace of the
+// prefixed attributes with the prefix declared in the element or the given
+// root element attributes, so that the attributes will be serialized with
+// the original prefix instead of the generated prefix.
+func localNameSpaceAttrs(attrs, ns []xml.Attr) {
+	decls := append(append([]xml.Attr{}, attrs...), ns...)
+	for idx, attr := range attrs {
+		if attr.Name.Space == "" || attr.Name.Space == "xmlns" || attr.Name.Space == NameSpaceXML {
+			continue
+		}
+		if prefix := getXMLNamespace(attr.Name.Space, decls); prefix != attr.Name.Space {
+			attrs[idx].Name = xml.Name{Local: prefix + ":" + attr.Name.Local}
+		}
+	}
+}
+
+// connectionsWriter provides a function to save xl/connections.xml after
+// serialize structure.
+func (f *File) connectionsWriter() {
+	if f.connections != nil {
+		output, _ := xml.Marshal(f.connections)
+		f.saveFileList(defaultXMLPathConnections, f.replaceNameSpaceBytes(defaultXMLPathConnections, output))
+	}
+}
+
+// getMashupQuery returns the name of the Power Query query by given
+// connection string, the connection string of the query likes:
+// Provider=Microsoft.Mashup.OleDb.1;Data Source=$Workbook$;Location=Query1
+func getMashupQuery(connection string) string {
+	if !strings.Contains(strings.ToLower(connection), "provider=microsoft.mashup.oledb") {
+		return ""
+	}
+	for _, item := range strings.Split(connection, ";") {
+		if kv := strings.SplitN(item, "=", 2); len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "Location") {
+			return strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+	}
+	return ""
+}
+
+// GetConnections provides a function to get the external data connections
+// of the workbook. The connections part and the Power Query (Data Mashup)
+// parts will be preserved on saving the workbook. For example:
+//
+//    connections, err := f.GetConnections()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, conn := range connections {
+//        fmt.Println(conn.Name, conn.ConnectionString, conn.RefreshOnLoad)
+//    }
+//
+func (f *File) GetConnections() ([]Connection, error) {
+	var connections []Connection
+	conns, err := f.connectionsReader()
+	if err != nil || conns == nil {
+		return connections, err
+	}
+	for _, c := range conns.Connection {
+		conn := Connection{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			Type:          c.Type,
+			RefreshOnLoad: c.RefreshOnLoad,
+		}
+		if c.DbPr != nil {
+			conn.ConnectionString = c.DbPr.Connection
+			conn.Command = c.DbPr.Command
+			conn.Query = getMashupQuery(c.DbPr.Connection)
+		}
+		connections = append(connections, conn)
+	}
+	return connections, err
+}
+
+// GetQueries provides a function to get the names of the Power Query (Data
+// Mashup) queries loaded by the connections of the workbook.
+func (f *File) GetQueries() ([]string, error) {
+	var queries []string
+	connections, err := f.GetConnections()
+	for _, conn := range connections {
+		if conn.Query != "" {
+			queries = append(queries, conn.Query)
+		}
+	}
+	return queries, err
+}
+
+// getConnection provides a function to get the connection by given name.
+func (f *File) getConnection(name string) (*xlsxConnection, error) {
+	conns, err := f.connectionsReader()
+	if err != nil {
+		return nil, err
+	}
+	if conns != nil {
+		for idx := range conns.Connection {
+			if conns.Connection[idx].Name == name {
+				return &conns.Connection[idx], err
+			}
+		}
+	}
+	return nil, newNoExistConnectionError(name)
+}
+
+// SetConnectionString provides a function to change the connection string of
+// the database connection by given connection name. For example, change the
+// data source of the connection named "Sales":
+//
+//    err := f.SetConnectionString("Sales", "Provider=SQLOLEDB.1;Data Source=db;Initial Catalog=sales")
+//
+func (f *File) SetConnectionString(name, connection string) error {
+	conn, err := f.getConnection(name)
+	if err != nil {
+		return err
+	}
+	if conn.DbPr == nil {
+		conn.DbPr = &xlsxDbPr{}
+	}
+	conn.DbPr.Connection = connection
+	return err
+}
+
+// SetConnectionRefreshOnLoad provides a function to set whether the data of
+// the connection should be refreshed when opening the workbook by given
+// connection name. For example, refresh the connection named "Sales" on
+// opening:
+//
+//    err := f.SetConnectionRefreshOnLoad("Sales", true)
+//
+func (f *File) SetConnectionRefreshOnLoad(name string, refreshOnLoad bool) error {
+	conn, err := f.getConnection(name)
+	if err != nil {
+		return err
+	}
+	conn.RefreshOnLoad = refreshOnLoad
+	return err
+}
@@ -0,0 +1,72 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnections(t *testing.T) {
+	f := NewFile()
+	connections, err := f.GetConnections()
+	assert.NoError(t, err)
+	assert.Nil(t, connections)
+	assert.EqualError(t, f.SetConnectionString("Sales", ""), "connection Sales is not exist")
+
+	f.Pkg.Store(defaultXMLPathConnections, []byte(`<connections xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="xr16" xmlns:xr16="http://schemas.microsoft.com/office/spreadsheetml/2017/revision16"><connection id="1" keepAlive="1" name="Query - Sales" description="Connection to the 'Sales' query in the workbook." type="5" refreshedVersion="6" background="1" saveData="1"><dbPr connection="Provider=Microsoft.Mashup.OleDb.1;Data Source=$Workbook$;Location=Sales;Extended Properties=&quot;&quot;" command="SELECT * FROM [Sales]"/></connection><connection id="2" xr16:uid="{00000000-0000-0000-0000-000000000001}" name="Orders" type="1" refreshedVersion="6" unknown="1"><dbPr connection="DSN=Orders;" command="SELECT * FROM Orders" commandType="2" xr16:uid="{00000000-0000-0000-0000-000000000002}"/><extLst><ext uri="{DE250136-89BD-433C-8126-D09CA5730AF9}" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:connection id="" model="1"/></ext></extLst></connection><connection id="3" name="Web" type="4" refreshedVersion="6"><webPr sourceData="1" parsePre="1" url="https://example.com/data"><tables count="1"><x v="1"/></tables></webPr></connection><connection id="4" name="Text" type="6" refreshedVersion="6"><textPr codePage="65001" sourceFile="C:\data.csv" comma="1"><textFields count="2"><textField/><textField type="text"/></textFields></textPr></connection></connections>`))
+	f.Pkg.Store("customXml/item1.xml", []byte(`<DataMashup xmlns="http://schemas.microsoft.com/DataMashup">AAAAAA==</DataMashup>`))
+	connections, err = f.GetConnections()
+	assert.NoError(t, err)
+	assert.Equal(t, []Connection{
+		{ID: 1, Name: "Query - Sales", Description: "Connection to the 'Sales' query in the workbook.", Type: 5, ConnectionString: `Provider=Microsoft.Mashup.OleDb.1;Data Source=$Workbook$;Location=Sales;Extended Properties=""`, Command: "SELECT * FROM [Sales]", Query: "Sales"},
+		{ID: 2, Name: "Orders", Type: 1, ConnectionString: "DSN=Orders;", Command: "SELECT * FROM Orders"},
+		{ID: 3, Name: "Web", Type: 4},
+		{ID: 4, Name: "Text", Type: 6},
+	}, connections)
+	queries, err := f.GetQueries()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sales"}, queries)
+
+	assert.NoError(t, f.SetConnectionString("Orders", "DSN=OrdersArchive;"))
+	assert.NoError(t, f.SetConnectionString("Web", "DSN=Web;"))
+	assert.NoError(t, f.SetConnectionRefreshOnLoad("Query - Sales", true))
+	assert.EqualError(t, f.SetConnectionString("Products", ""), "connection Products is not exist")
+	assert.EqualError(t, f.SetConnectionRefreshOnLoad("Products", true), "connection Products is not exist")
+	path := filepath.Join("test", "TestConnections.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	connections, err = f.GetConnections()
+	assert.NoError(t, err)
+	assert.True(t, connections[0].RefreshOnLoad)
+	assert.Equal(t, "DSN=OrdersArchive;", connections[1].ConnectionString)
+	assert.Equal(t, "DSN=Web;", connections[2].ConnectionString)
+	assert.Contains(t, string(f.readXML(defaultXMLPathConnections)), `<webPr sourceData="1" parsePre="1" url="https://example.com/data"><tables count="1"><x v="1"/></tables></webPr>`)
+	assert.Contains(t, string(f.readXML(defaultXMLPathConnections)), `mc:Ignorable="xr16"`)
+	for _, content := range []string{
+		`<connection id="2" name="Orders" type="1" refreshedVersion="6" xr16:uid="{00000000-0000-0000-0000-000000000001}" unknown="1">`,
+		`<dbPr connection="DSN=OrdersArchive;" command="SELECT * FROM Orders" commandType="2" xr16:uid="{00000000-0000-0000-0000-000000000002}"></dbPr>`,
+		`<extLst><ext uri="{DE250136-89BD-433C-8126-D09CA5730AF9}" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:connection id="" model="1"/></ext></extLst>`,
+		`<textPr codePage="65001" sourceFile="C:\data.csv" comma="1"><textFields count="2"><textField/><textField type="text"/></textFields></textPr>`,
+	} {
+		assert.Contains(t, string(f.readXML(defaultXMLPathConnections)), content)
+	}
+	assert.Equal(t, `<DataMashup xmlns="http://schemas.microsoft.com/DataMashup">AAAAAA==</DataMashup>`, string(f.readXML("customXml/item1.xml")))
+
+	// Test get connections with invalid connections part
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathConnections, MacintoshCyrillicCharset)
+	_, err = f.GetConnections()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetQueries()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetConnectionString("Sales", ""), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetMashupQuery(t *testing.T) {
+	assert.Equal(t, "Query1", getMashupQuery(`Provider=Microsoft.Mashup.OleDb.1;Data Source=$Workbook$;Location="Query1"`))
+	assert.Equal(t, "", getMashupQuery(`Provider=Microsoft.Mashup.OleDb.1;Data Source=$Workbook$`))
+	assert.Equal(t, "", getMashupQuery(`DSN=Orders;Location=Query1`))
+}
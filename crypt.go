@@ -15,6 +15,7 @@ import (
 	"crypto/cipher"
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -22,9 +23,11 @@ import (
 	"encoding/binary"
 	"encoding/xml"
 	"hash"
-	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/richardlehane/mscfb"
 	"golang.org/x/crypto/md4"
@@ -44,6 +47,28 @@ var (
 	oleIdentifier              = []byte{
 		0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1,
 	}
+	// encryptHashSize defined the hash size of the supported hash algorithm
+	// for the agile encryption on write.
+	encryptHashSize = map[string]int{"SHA1": 20, "SHA256": 32, "SHA384": 48, "SHA512": 64}
+	// hashFuncs defined the hash functions by the lower case name of the
+	// hash algorithm.
+	hashFuncs = map[string]func() hash.Hash{
+		"md4":        md4.New,
+		"md5":        md5.New,
+		"ripemd-160": ripemd160.New,
+		"sha1":       sha1.New,
+		"sha256":     sha256.New,
+		"sha384":     sha512.New384,
+		"sha512":     sha512.New,
+	}
+)
+
+const (
+	encryptionNS                = "http://schemas.microsoft.com/office/2006/encryption"
+	keyEncryptorPasswordNS      = "http://schemas.microsoft.com/office/2006/keyEncryptor/password"
+	defaultEncryptHashAlgorithm = "SHA512"
+	defaultEncryptKeyBits       = 256
+	defaultEncryptSpinCount     = 100000
 )
 
 // Encryption specifies the encryption structure, streams, and storages are
@@ -149,33 +174,27 @@ func Decrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	return
 }
 
-// Encrypt API encrypt data with the password.
+// Encrypt API encrypt data with the password by the ECMA-376 agile
+// encryption. The hash algorithm, the key bits of the AES cipher and the
+// spin count of the password hashing could be specified by the
+// EncryptHashAlgorithm, EncryptKeyBits and EncryptSpinCount options, the
+// SHA512 hash algorithm, AES-256 cipher and 100000 spin count will be used
+// by default. The data spaces storage will be written into the compound
+// file as Microsoft Excel does, and the encrypted data will be decrypted and
+// verified before returning.
 func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
-	// Generate a random key to use to encrypt the document. Excel uses 32 bytes. We'll use the password to encrypt this key.
-	packageKey, _ := randomBytes(32)
-	keyDataSaltValue, _ := randomBytes(16)
-	keyEncryptors, _ := randomBytes(16)
-	encryptionInfo := Encryption{
-		KeyData: KeyData{
-			BlockSize:       16,
-			KeyBits:         len(packageKey) * 8,
-			HashSize:        64,
-			CipherAlgorithm: "AES",
-			CipherChaining:  "ChainingModeCBC",
-			HashAlgorithm:   "SHA512",
-			SaltValue:       base64.StdEncoding.EncodeToString(keyDataSaltValue),
-		},
-		KeyEncryptors: KeyEncryptors{KeyEncryptor: []KeyEncryptor{{
-			EncryptedKey: EncryptedKey{SpinCount: 100000, KeyData: KeyData{
-				CipherAlgorithm: "AES",
-				CipherChaining:  "ChainingModeCBC",
-				HashAlgorithm:   "SHA512",
-				HashSize:        64,
-				BlockSize:       16,
-				KeyBits:         256,
-				SaltValue:       base64.StdEncoding.EncodeToString(keyEncryptors)},
-			}}},
-		},
+	encryptionInfo, err := agileEncryptionInfo(opt)
+	if err != nil {
+		return
+	}
+	keyData := encryptionInfo.KeyData
+	encryptedKey := &encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey
+	keyEncryptorSalt, _ := base64.StdEncoding.DecodeString(encryptedKey.SaltValue)
+	// Generate a random key to use to encrypt the document. We'll use the
+	// password to encrypt this key.
+	packageKey, err := randomBytes(keyData.KeyBits / 8)
+	if err != nil {
+		return
 	}
 
 	// Package Encryption
@@ -189,85 +208,261 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	// Data Integrity
 
 	// Create the data integrity fields used by clients for integrity checks.
-	// Generate a random array of bytes to use in HMAC. The docs say to use the same length as the key salt, but Excel seems to use 64.
-	hmacKey, _ := randomBytes(64)
+	// Generate a random array of bytes with the hash size to use in HMAC.
+	hmacKey, err := randomBytes(keyData.HashSize)
 	if err != nil {
 		return
 	}
-	// Create an initialization vector using the package encryption info and the appropriate block key.
-	hmacKeyIV, err := createIV(blockKeyHmacKey, encryptionInfo)
+	// Create the HMAC of the whole encrypted package stream.
+	h := hmac.New(hashFuncs[strings.ToLower(keyData.HashAlgorithm)], hmacKey)
+	_, _ = h.Write(encryptedPackage)
+	hmacValue := h.Sum(nil)
+	// Encrypt the HMAC key and value with the package key and the
+	// initialization vectors generated by the appropriate block keys.
+	for _, integrity := range []struct {
+		blockKey, value []byte
+		field           *string
+	}{
+		{blockKeyHmacKey, hmacKey, &encryptionInfo.DataIntegrity.EncryptedHmacKey},
+		{blockKeyHmacValue, hmacValue, &encryptionInfo.DataIntegrity.EncryptedHmacValue},
+	} {
+		iv, err := createIV(integrity.blockKey, encryptionInfo)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := crypt(true, keyData.CipherAlgorithm, keyData.CipherChaining, packageKey, iv, padBlock(integrity.value, keyData.BlockSize))
+		if err != nil {
+			return nil, err
+		}
+		*integrity.field = base64.StdEncoding.EncodeToString(encrypted)
+	}
+
+	// Key Encryption and Verifier hash
+
+	// Create a random byte array with the salt size for hashing.
+	verifierHashInput, err := randomBytes(encryptedKey.SaltSize)
 	if err != nil {
 		return
 	}
-	// Use the package key and the IV to encrypt the HMAC key.
-	encryptedHmacKey, _ := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacKeyIV, hmacKey)
-	// Create the HMAC.
-	h := hmac.New(sha512.New, append(hmacKey, encryptedPackage...))
-	for _, buf := range [][]byte{hmacKey, encryptedPackage} {
-		_, _ = h.Write(buf)
+	// Encrypt the verifier input, the hash of the verifier input and the
+	// package key with the encryption keys converted from the password.
+	for _, verifier := range []struct {
+		blockKey, value []byte
+		field           *string
+	}{
+		{blockKeyVerifierHashInput, verifierHashInput, &encryptedKey.EncryptedVerifierHashInput},
+		{blockKeyVerifierHashValue, hashing(encryptedKey.HashAlgorithm, verifierHashInput), &encryptedKey.EncryptedVerifierHashValue},
+		{blockKey, packageKey, &encryptedKey.EncryptedKeyValue},
+	} {
+		key, err := convertPasswdToKey(opt.Password, verifier.blockKey, encryptionInfo)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := crypt(true, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, keyEncryptorSalt, padBlock(verifier.value, encryptedKey.BlockSize))
+		if err != nil {
+			return nil, err
+		}
+		*verifier.field = base64.StdEncoding.EncodeToString(encrypted)
 	}
-	hmacValue := h.Sum(nil)
-	// Generate an initialization vector for encrypting the resulting HMAC value.
-	hmacValueIV, err := createIV(blockKeyHmacValue, encryptionInfo)
+	// Marshal the encryption info buffer.
+	encryptionInfoBuffer, err := marshalEncryptionInfo(encryptionInfo)
 	if err != nil {
 		return
 	}
-	// Encrypt the value.
-	encryptedHmacValue, _ := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacValueIV, hmacValue)
-	// Put the encrypted key and value on the encryption info.
-	encryptionInfo.DataIntegrity.EncryptedHmacKey = base64.StdEncoding.EncodeToString(encryptedHmacKey)
-	encryptionInfo.DataIntegrity.EncryptedHmacValue = base64.StdEncoding.EncodeToString(encryptedHmacValue)
-
-	// Key Encryption
+	packageBuf = writeCFB(append(dataSpacesStreams(),
+		cfbStream{name: "EncryptionInfo", data: encryptionInfoBuffer},
+		cfbStream{name: "EncryptedPackage", data: encryptedPackage},
+	))
+	err = verifyEncryption(packageBuf, raw, opt)
+	return
+}
 
-	// Convert the password to an encryption key.
-	key, err := convertPasswdToKey(opt.Password, blockKey, encryptionInfo)
-	if err != nil {
-		return
+// dataSpacesStreams provides a function to create the streams in the
+// \x06DataSpaces storage of the encrypted package, which declares the
+// EncryptedPackage stream is transformed by the strong encryption transform
+// as Microsoft Excel does.
+func dataSpacesStreams() []cfbStream {
+	var version, dataSpaceMap, dataSpaceInfo, primary bytes.Buffer
+	write := func(buf *bytes.Buffer, data ...interface{}) {
+		for _, v := range data {
+			if s, ok := v.(string); ok {
+				writeUnicodeLPP4(buf, s)
+				continue
+			}
+			_ = binary.Write(buf, binary.LittleEndian, v)
+		}
+	}
+	versionNumbers := []uint16{1, 0, 1, 0, 1, 0}
+	write(&version, "Microsoft.Container.DataSpaces", versionNumbers)
+	var entry bytes.Buffer
+	write(&entry, uint32(1), uint32(0), "EncryptedPackage", "StrongEncryptionDataSpace")
+	write(&dataSpaceMap, uint32(8), uint32(1), uint32(entry.Len()+4), entry.Bytes())
+	write(&dataSpaceInfo, uint32(8), uint32(1), "StrongEncryptionTransform")
+	var transform bytes.Buffer
+	write(&transform, uint32(1), "{FF9A3F03-56EF-4613-BDD5-5A41C1D07246}")
+	write(&primary, uint32(transform.Len()+4), transform.Bytes(), "Microsoft.Container.EncryptionTransform",
+		versionNumbers, uint32(0), uint32(0), uint32(0), uint32(4))
+	return []cfbStream{
+		{name: "\x06DataSpaces/Version", data: version.Bytes()},
+		{name: "\x06DataSpaces/DataSpaceMap", data: dataSpaceMap.Bytes()},
+		{name: "\x06DataSpaces/DataSpaceInfo/StrongEncryptionDataSpace", data: dataSpaceInfo.Bytes()},
+		{name: "\x06DataSpaces/TransformInfo/StrongEncryptionTransform/\x06Primary", data: primary.Bytes()},
 	}
-	// Encrypt the package key with the encryption key.
-	encryptedKeyValue, _ := crypt(true, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherAlgorithm, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherChaining, key, keyEncryptors, packageKey)
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedKeyValue = base64.StdEncoding.EncodeToString(encryptedKeyValue)
+}
 
-	// Verifier hash
+// writeUnicodeLPP4 provides a function to write the length prefixed UTF-16
+// string padded to the multiple of 4 bytes into given buffer.
+func writeUnicodeLPP4(buf *bytes.Buffer, s string) {
+	encoded := utf16.Encode([]rune(s))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(encoded)*2))
+	_ = binary.Write(buf, binary.LittleEndian, encoded)
+	if len(encoded)%2 != 0 {
+		buf.Write(make([]byte, 2))
+	}
+}
 
-	// Create a random byte array for hashing.
-	verifierHashInput, _ := randomBytes(16)
-	// Create an encryption key from the password for the input.
-	verifierHashInputKey, err := convertPasswdToKey(opt.Password, blockKeyVerifierHashInput, encryptionInfo)
-	if err != nil {
+// agileEncryptionInfo provides a function to create the agile encryption
+// info with random salts by given options, and validate the password and
+// the encryption options.
+func agileEncryptionInfo(opt *Options) (encryption Encryption, err error) {
+	if opt == nil || opt.Password == "" || utf8.RuneCountInString(opt.Password) > MaxPasswordLength {
+		err = ErrPasswordLength
 		return
 	}
-	// Use the key to encrypt the verifier input.
-	encryptedVerifierHashInput, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, verifierHashInputKey, keyEncryptors, verifierHashInput)
-	if err != nil {
+	hashAlgorithm, keyBits, spinCount := defaultEncryptHashAlgorithm, defaultEncryptKeyBits, defaultEncryptSpinCount
+	if opt.EncryptHashAlgorithm != "" {
+		hashAlgorithm = strings.ToUpper(opt.EncryptHashAlgorithm)
+	}
+	hashSize, ok := encryptHashSize[hashAlgorithm]
+	if !ok {
+		err = ErrEncryptHashAlgorithm
 		return
 	}
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashInput = base64.StdEncoding.EncodeToString(encryptedVerifierHashInput)
-	// Create a hash of the input.
-	verifierHashValue := hashing(encryptionInfo.KeyData.HashAlgorithm, verifierHashInput)
-	// Create an encryption key from the password for the hash.
-	verifierHashValueKey, err := convertPasswdToKey(opt.Password, blockKeyVerifierHashValue, encryptionInfo)
-	if err != nil {
+	if opt.EncryptKeyBits != 0 {
+		keyBits = opt.EncryptKeyBits
+	}
+	if keyBits != 128 && keyBits != 192 && keyBits != 256 {
+		err = ErrEncryptKeyBits
 		return
 	}
-	// Use the key to encrypt the hash value.
-	encryptedVerifierHashValue, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, verifierHashValueKey, keyEncryptors, verifierHashValue)
+	if opt.EncryptSpinCount != 0 {
+		spinCount = opt.EncryptSpinCount
+	}
+	if spinCount < 1 || spinCount > MaxEncryptSpinCount {
+		err = ErrEncryptSpinCount
+		return
+	}
+	keyData := KeyData{
+		SaltSize:        16,
+		BlockSize:       16,
+		KeyBits:         keyBits,
+		HashSize:        hashSize,
+		CipherAlgorithm: "AES",
+		CipherChaining:  "ChainingModeCBC",
+		HashAlgorithm:   hashAlgorithm,
+	}
+	keyDataSalt, err := randomBytes(keyData.SaltSize)
 	if err != nil {
 		return
 	}
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashValue = base64.StdEncoding.EncodeToString(encryptedVerifierHashValue)
-	// Marshal the encryption info buffer.
-	encryptionInfoBuffer, err := xml.Marshal(encryptionInfo)
+	keyEncryptorSalt, err := randomBytes(keyData.SaltSize)
 	if err != nil {
 		return
 	}
-	// TODO: Create a new CFB.
-	_, _ = encryptedPackage, encryptionInfoBuffer
-	err = ErrEncrypt
+	encryption.KeyData = keyData
+	encryption.KeyData.SaltValue = base64.StdEncoding.EncodeToString(keyDataSalt)
+	keyData.SaltValue = base64.StdEncoding.EncodeToString(keyEncryptorSalt)
+	encryption.KeyEncryptors.KeyEncryptor = []KeyEncryptor{{
+		URI:          keyEncryptorPasswordNS,
+		EncryptedKey: EncryptedKey{SpinCount: spinCount, KeyData: keyData},
+	}}
 	return
 }
 
+// marshalEncryptionInfo provides a function to serialize the agile
+// encryption info to the EncryptionInfo stream, which begins with the
+// version and the reserved flags.
+func marshalEncryptionInfo(encryption Encryption) ([]byte, error) {
+	output, err := xml.Marshal(encryption)
+	if err != nil {
+		return nil, err
+	}
+	output = bytes.Replace(output, []byte(`<encryption>`), []byte(`<encryption xmlns="`+encryptionNS+`" xmlns:p="`+keyEncryptorPasswordNS+`">`), 1)
+	output = bytes.Replace(output, []byte(`<encryptedKey xmlns="`+keyEncryptorPasswordNS+`"`), []byte(`<p:encryptedKey`), -1)
+	output = bytes.Replace(output, []byte(`</encryptedKey>`), []byte(`</p:encryptedKey>`), -1)
+	return append([]byte{0x04, 0x00, 0x04, 0x00, 0x40, 0x00, 0x00, 0x00}, append([]byte(XMLHeader), output...)...), nil
+}
+
+// verifyEncryption provides a function to decrypt the encrypted data and
+// verify the data integrity, the decrypted data should be the same with the
+// original data.
+func verifyEncryption(packageBuf, raw []byte, opt *Options) error {
+	doc, err := mscfb.New(bytes.NewReader(packageBuf))
+	if err != nil {
+		return ErrEncryptSelfCheck
+	}
+	encryptionInfoBuf, encryptedPackageBuf := extractPart(doc)
+	if mechanism, err := encryptionMechanism(encryptionInfoBuf); err != nil || mechanism != "agile" {
+		return ErrEncryptSelfCheck
+	}
+	encryptionInfo, err := parseEncryptionInfo(encryptionInfoBuf[8:])
+	if err != nil {
+		return ErrEncryptSelfCheck
+	}
+	packageKey, err := agileDecryptPackageKey(encryptionInfo, opt)
+	if err != nil || !verifyDataIntegrity(encryptionInfo, packageKey, encryptedPackageBuf) {
+		return ErrEncryptSelfCheck
+	}
+	decrypted, err := cryptPackage(false, packageKey, encryptedPackageBuf, encryptionInfo)
+	if err != nil || !bytes.Equal(decrypted, raw) {
+		return ErrEncryptSelfCheck
+	}
+	return nil
+}
+
+// verifyDataIntegrity provides a function to verify the HMAC of the
+// encrypted package stream by given agile encryption info and package key.
+func verifyDataIntegrity(encryption Encryption, packageKey, encryptedPackage []byte) bool {
+	keyData, values := encryption.KeyData, make([][]byte, 2)
+	hashFunc, ok := hashFuncs[strings.ToLower(keyData.HashAlgorithm)]
+	if !ok {
+		return false
+	}
+	for idx, integrity := range []struct {
+		blockKey []byte
+		value    string
+	}{
+		{blockKeyHmacKey, encryption.DataIntegrity.EncryptedHmacKey},
+		{blockKeyHmacValue, encryption.DataIntegrity.EncryptedHmacValue},
+	} {
+		encrypted, err := base64.StdEncoding.DecodeString(integrity.value)
+		if err != nil || len(encrypted) < keyData.HashSize || len(encrypted)%keyData.BlockSize != 0 {
+			return false
+		}
+		iv, err := createIV(integrity.blockKey, encryption)
+		if err != nil {
+			return false
+		}
+		if values[idx], err = crypt(false, keyData.CipherAlgorithm, keyData.CipherChaining, packageKey, iv, encrypted); err != nil {
+			return false
+		}
+	}
+	h := hmac.New(hashFunc, values[0][:keyData.HashSize])
+	_, _ = h.Write(encryptedPackage)
+	return hmac.Equal(h.Sum(nil), values[1][:keyData.HashSize])
+}
+
+// padBlock provides a function to pad the data with zero bytes to an
+// integer multiple of the block size.
+func padBlock(data []byte, blockSize int) []byte {
+	padded := make([]byte, len(data), len(data)+blockSize)
+	copy(padded, data)
+	if remainder := len(data) % blockSize; remainder != 0 {
+		padded = append(padded, make([]byte, blockSize-remainder)...)
+	}
+	return padded
+}
+
 // extractPart extract data from storage by specified part name.
 func extractPart(doc *mscfb.Reader) (encryptionInfoBuf, encryptedPackageBuf []byte) {
 	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
@@ -342,20 +537,49 @@ func standardDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options
 	if err != nil {
 		return nil, err
 	}
-	// decrypted data
-	x := encryptedPackageBuf[8:]
 	blob, err := aes.NewCipher(secretKey)
 	if err != nil {
 		return nil, err
 	}
+	if !standardVerifyPassword(blob, verifier) {
+		return nil, ErrWorkbookPassword
+	}
+	// decrypted data
+	if len(encryptedPackageBuf) < packageOffset || (len(encryptedPackageBuf)-packageOffset)%aes.BlockSize != 0 {
+		return nil, ErrUnknownEncryptMechanism
+	}
+	x := encryptedPackageBuf[packageOffset:]
 	decrypted := make([]byte, len(x))
 	size := 16
 	for bs, be := 0, size; bs < len(x); bs, be = bs+size, be+size {
 		blob.Decrypt(decrypted[bs:be], x[bs:be])
 	}
+	// Truncate the padding by the size of the package.
+	if size := binary.LittleEndian.Uint64(encryptedPackageBuf[:packageOffset]); size < uint64(len(decrypted)) {
+		decrypted = decrypted[:size]
+	}
 	return decrypted, err
 }
 
+// standardVerifyPassword provides a function to verify the password by given
+// AES cipher with the key converted from the password and the standard
+// encryption verifier, the SHA1 hash of the decrypted verifier should match
+// the decrypted verifier hash.
+func standardVerifyPassword(blob cipher.Block, verifier StandardEncryptionVerifier) bool {
+	if len(verifier.EncryptedVerifier) != aes.BlockSize || len(verifier.EncryptedVerifierHash)%aes.BlockSize != 0 ||
+		len(verifier.EncryptedVerifierHash) < sha1.Size {
+		return false
+	}
+	decryptECB := func(input []byte) []byte {
+		output := make([]byte, len(input))
+		for bs := 0; bs < len(input); bs += aes.BlockSize {
+			blob.Decrypt(output[bs:bs+aes.BlockSize], input[bs:bs+aes.BlockSize])
+		}
+		return output
+	}
+	return bytes.Equal(hashing("sha1", decryptECB(verifier.EncryptedVerifier)), decryptECB(verifier.EncryptedVerifierHash)[:sha1.Size])
+}
+
 // standardEncryptionVerifier extract ECMA-376 standard encryption verifier.
 func standardEncryptionVerifier(algorithm string, blob []byte) StandardEncryptionVerifier {
 	verifier := StandardEncryptionVerifier{
@@ -421,6 +645,24 @@ func agileDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options) (
 	if encryptionInfo, err = parseEncryptionInfo(encryptionInfoBuf[8:]); err != nil {
 		return
 	}
+	packageKey, err := agileDecryptPackageKey(encryptionInfo, opt)
+	if err != nil {
+		return
+	}
+	// Use the package key to decrypt the package.
+	return cryptPackage(false, packageKey, encryptedPackageBuf, encryptionInfo)
+}
+
+// agileDecryptPackageKey provides a function to decrypt the package key by
+// given agile encryption info and the password.
+func agileDecryptPackageKey(encryptionInfo Encryption, opt *Options) (packageKey []byte, err error) {
+	if len(encryptionInfo.KeyEncryptors.KeyEncryptor) == 0 {
+		err = ErrUnknownEncryptMechanism
+		return
+	}
+	if err = agileVerifyPassword(encryptionInfo, opt); err != nil {
+		return
+	}
 	// Convert the password into an encryption key.
 	key, err := convertPasswdToKey(opt.Password, blockKey, encryptionInfo)
 	if err != nil {
@@ -436,9 +678,56 @@ func agileDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options) (
 	if err != nil {
 		return
 	}
-	packageKey, _ := crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, encryptedKeyValue)
-	// Use the package key to decrypt the package.
-	return cryptPackage(false, packageKey, encryptedPackageBuf, encryptionInfo)
+	packageKey, err = crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, encryptedKeyValue)
+	if keyBytes := encryptedKey.KeyBits / 8; err == nil && len(packageKey) > keyBytes {
+		packageKey = packageKey[:keyBytes]
+	}
+	return
+}
+
+// agileVerifyPassword provides a function to verify the password by given
+// agile encryption info, the hash of the verifier hash input decrypted with
+// the key converted from the password should match the decrypted verifier
+// hash value.
+func agileVerifyPassword(encryptionInfo Encryption, opt *Options) error {
+	encryptedKey, values := encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey, make([][]byte, 2)
+	saltValue, err := base64.StdEncoding.DecodeString(encryptedKey.SaltValue)
+	if err != nil {
+		return err
+	}
+	if len(saltValue) != aes.BlockSize {
+		return ErrUnknownEncryptMechanism
+	}
+	for idx, verifier := range []struct {
+		blockKey []byte
+		value    string
+	}{
+		{blockKeyVerifierHashInput, encryptedKey.EncryptedVerifierHashInput},
+		{blockKeyVerifierHashValue, encryptedKey.EncryptedVerifierHashValue},
+	} {
+		encrypted, err := base64.StdEncoding.DecodeString(verifier.value)
+		if err != nil {
+			return err
+		}
+		if len(encrypted)%aes.BlockSize != 0 {
+			return ErrUnknownEncryptMechanism
+		}
+		key, err := convertPasswdToKey(opt.Password, verifier.blockKey, encryptionInfo)
+		if err != nil {
+			return err
+		}
+		if values[idx], err = crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, encrypted); err != nil {
+			return err
+		}
+	}
+	if len(values[0]) < encryptedKey.SaltSize || len(values[1]) < encryptedKey.HashSize {
+		return ErrWorkbookPassword
+	}
+	hashValue := hashing(encryptedKey.HashAlgorithm, values[0][:encryptedKey.SaltSize])
+	if len(hashValue) < encryptedKey.HashSize || !bytes.Equal(hashValue[:encryptedKey.HashSize], values[1][:encryptedKey.HashSize]) {
+		return ErrWorkbookPassword
+	}
+	return nil
 }
 
 // convertPasswdToKey convert the password into an encryption key.
@@ -467,8 +756,7 @@ func convertPasswdToKey(passwd string, blockKey []byte, encryption Encryption) (
 	// Truncate or pad as needed to get to length of keyBits.
 	keyBytes := encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.KeyBits / 8
 	if len(key) < keyBytes {
-		tmp := make([]byte, 0x36)
-		key = append(key, tmp...)
+		key = append(key, bytes.Repeat([]byte{0x36}, keyBytes-len(key))...)
 	} else if len(key) > keyBytes {
 		key = key[:keyBytes]
 	}
@@ -477,19 +765,11 @@ func convertPasswdToKey(passwd string, blockKey []byte, encryption Encryption) (
 
 // hashing data by specified hash algorithm.
 func hashing(hashAlgorithm string, buffer ...[]byte) (key []byte) {
-	var hashMap = map[string]hash.Hash{
-		"md4":        md4.New(),
-		"md5":        md5.New(),
-		"ripemd-160": ripemd160.New(),
-		"sha1":       sha1.New(),
-		"sha256":     sha256.New(),
-		"sha384":     sha512.New384(),
-		"sha512":     sha512.New(),
-	}
-	handler, ok := hashMap[strings.ToLower(hashAlgorithm)]
+	hashFunc, ok := hashFuncs[strings.ToLower(hashAlgorithm)]
 	if !ok {
 		return key
 	}
+	handler := hashFunc()
 	for _, buf := range buffer {
 		_, _ = handler.Write(buf)
 	}
@@ -497,6 +777,28 @@ func hashing(hashAlgorithm string, buffer ...[]byte) (key []byte) {
 	return key
 }
 
+// genISOPasswdHash provides a function to generate the hash value of the
+// password by given hash algorithm name (such as SHA-512), base64 encoded
+// salt value and spin count, which is used in the sheet protection and the
+// workbook protection.
+func genISOPasswdHash(passwd, hashAlgorithm, salt string, spinCount int) (string, error) {
+	saltValue, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", err
+	}
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	passwordBuffer, err := encoder.Bytes([]byte(passwd))
+	if err != nil {
+		return "", err
+	}
+	hashAlgorithm = strings.Replace(strings.ToLower(hashAlgorithm), "sha-", "sha", 1)
+	key := hashing(hashAlgorithm, saltValue, passwordBuffer)
+	for i := 0; i < spinCount; i++ {
+		key = hashing(hashAlgorithm, key, createUInt32LEBuffer(i, 4))
+	}
+	return base64.StdEncoding.EncodeToString(key), err
+}
+
 // createUInt32LEBuffer create buffer with little endian 32-bit unsigned
 // integer.
 func createUInt32LEBuffer(value int, bufferSize int) []byte {
@@ -545,12 +847,13 @@ func cryptPackage(encrypt bool, packageKey, input []byte, encryption Encryption)
 		if end > len(input) {
 			end = len(input)
 		}
-		// Grab the next chunk
+		// Grab a copy of the next chunk, the chunk will be encrypted or
+		// decrypted in place
 		var inputChunk []byte
 		if (end + offset) < len(input) {
-			inputChunk = input[start+offset : end+offset]
+			inputChunk = append(inputChunk, input[start+offset:end+offset]...)
 		} else {
-			inputChunk = input[start+offset : end]
+			inputChunk = append(inputChunk, input[start+offset:end]...)
 		}
 
 		// Pad the chunk if it is not an integer multiple of the block size
@@ -573,6 +876,13 @@ func cryptPackage(encrypt bool, packageKey, input []byte, encryption Encryption)
 	}
 	if encrypt {
 		outputChunks = append(createUInt32LEBuffer(len(input), 8), outputChunks...)
+		return
+	}
+	// Truncate the padding of the last chunk by the size of the package.
+	if len(input) >= packageOffset {
+		if size := binary.LittleEndian.Uint64(input[:packageOffset]); size < uint64(len(outputChunks)) {
+			outputChunks = outputChunks[:size]
+		}
 	}
 	return
 }
@@ -595,8 +905,7 @@ func createIV(blockKey interface{}, encryption Encryption) ([]byte, error) {
 	// Truncate or pad as needed to meet the block size.
 	iv := hashing(encryptedKey.HashAlgorithm, append(saltValue, blockKeyBuf...))
 	if len(iv) < encryptedKey.BlockSize {
-		tmp := make([]byte, 0x36)
-		iv = append(iv, tmp...)
+		iv = append(iv, bytes.Repeat([]byte{0x36}, encryptedKey.BlockSize-len(iv))...)
 	} else if len(iv) > encryptedKey.BlockSize {
 		iv = iv[0:encryptedKey.BlockSize]
 	}
@@ -611,3 +920,260 @@ func randomBytes(n int) ([]byte, error) {
 	_, err := rand.Read(b)
 	return b, err
 }
+
+// Compound File Binary
+
+const (
+	cfbSectorSize        = 512
+	cfbMiniSectorSize    = 64
+	cfbMiniStreamCutoff  = 4096
+	cfbDirEntrySize      = 128
+	cfbHeaderDIFATLength = 109
+	cfbDIFATSect         = 0xFFFFFFFC
+	cfbFATSect           = 0xFFFFFFFD
+	cfbEndOfChain        = 0xFFFFFFFE
+	cfbFreeSect          = 0xFFFFFFFF
+	cfbNoStream          = 0xFFFFFFFF
+
+	cfbStorageObject     = 1
+	cfbStreamObject      = 2
+	cfbRootStorageObject = 5
+)
+
+// cfbStream directly maps the stream in the root storage of the compound
+// file.
+type cfbStream struct {
+	name  string
+	data  []byte
+	start uint32
+}
+
+// cfbSectors provides a function to get the number of sectors by given size
+// in bytes and the sector size.
+func cfbSectors(size, sectorSize int) int {
+	return (size + sectorSize - 1) / sectorSize
+}
+
+// cfbChain provides a function to set the consecutive sectors chain in the
+// allocation table by given start sector and the number of sectors.
+func cfbChain(table []uint32, start, count int) {
+	for idx := start; idx < start+count; idx++ {
+		table[idx] = uint32(idx + 1)
+	}
+	if count > 0 {
+		table[start+count-1] = cfbEndOfChain
+	}
+}
+
+// cfbCompareName provides a function to compare the names of the directory
+// entries in the compound file, the shorter name is less than the longer
+// name, and the names with the same length are compared in upper case.
+func cfbCompareName(a, b string) bool {
+	if la, lb := len(utf16.Encode([]rune(a))), len(utf16.Encode([]rune(b))); la != lb {
+		return la < lb
+	}
+	return strings.ToUpper(a) < strings.ToUpper(b)
+}
+
+// cfbDirEntry directly maps the directory entry of the storage or stream in
+// the compound file, the stream is the index of the stream data for the
+// stream object, or -1 for the storage object.
+type cfbDirEntry struct {
+	name               string
+	objectType         byte
+	stream             int
+	children           []int
+	left, right, child uint32
+}
+
+// cfbDirEntries provides a function to create the directory entries by given
+// streams, the storages will be created by the slash separated path of the
+// stream names, and the root storage will be the first entry. The siblings
+// of each storage are organized in the balanced binary search tree.
+func cfbDirEntries(streams []cfbStream) []cfbDirEntry {
+	entries := []cfbDirEntry{{name: "Root Entry", objectType: cfbRootStorageObject, stream: -1}}
+	storages := map[string]int{"": 0}
+	for idx, stream := range streams {
+		parts := strings.Split(stream.name, "/")
+		parent, storagePath := 0, ""
+		for _, part := range parts[:len(parts)-1] {
+			storagePath += "/" + part
+			if _, ok := storages[storagePath]; !ok {
+				entries = append(entries, cfbDirEntry{name: part, objectType: cfbStorageObject, stream: -1})
+				storages[storagePath] = len(entries) - 1
+				entries[parent].children = append(entries[parent].children, len(entries)-1)
+			}
+			parent = storages[storagePath]
+		}
+		entries = append(entries, cfbDirEntry{name: parts[len(parts)-1], objectType: cfbStreamObject, stream: idx})
+		entries[parent].children = append(entries[parent].children, len(entries)-1)
+	}
+	for idx := range entries {
+		entries[idx].left, entries[idx].right = cfbNoStream, cfbNoStream
+	}
+	for idx := range entries {
+		children := entries[idx].children
+		sort.Slice(children, func(i, j int) bool { return cfbCompareName(entries[children[i]].name, entries[children[j]].name) })
+		var buildTree func(lo, hi int) uint32
+		buildTree = func(lo, hi int) uint32 {
+			if lo > hi {
+				return cfbNoStream
+			}
+			mid := (lo + hi) / 2
+			entries[children[mid]].left, entries[children[mid]].right = buildTree(lo, mid-1), buildTree(mid+1, hi)
+			return uint32(children[mid])
+		}
+		entries[idx].child = buildTree(0, len(children)-1)
+	}
+	return entries
+}
+
+// writeCFB provides a function to create the compound file binary in
+// version 3 by given streams, the slash separated name of the stream
+// specifies the path of the stream in the storages. The streams smaller
+// than the mini stream cutoff size will be stored in the mini stream.
+func writeCFB(streams []cfbStream) []byte {
+	var (
+		sector, miniSector int
+		miniStream         []byte
+		miniChains         [][2]int
+	)
+	// Allocate the sectors for the regular streams and the mini stream.
+	for idx := range streams {
+		stream := &streams[idx]
+		stream.start = cfbEndOfChain
+		if len(stream.data) >= cfbMiniStreamCutoff {
+			stream.start = uint32(sector)
+			sector += cfbSectors(len(stream.data), cfbSectorSize)
+			continue
+		}
+		if count := cfbSectors(len(stream.data), cfbMiniSectorSize); count > 0 {
+			stream.start = uint32(miniSector)
+			miniChains = append(miniChains, [2]int{miniSector, count})
+			miniStream = append(miniStream, padBlock(stream.data, cfbMiniSectorSize)...)
+			miniSector += count
+		}
+	}
+	rootStart, miniStreamSectors := uint32(cfbEndOfChain), cfbSectors(len(miniStream), cfbSectorSize)
+	if miniStreamSectors > 0 {
+		rootStart = uint32(sector)
+	}
+	sector += miniStreamSectors
+	miniFATStart, miniFATSectors := uint32(cfbEndOfChain), cfbSectors(miniSector*4, cfbSectorSize)
+	if miniFATSectors > 0 {
+		miniFATStart = uint32(sector)
+	}
+	sector += miniFATSectors
+	entries := cfbDirEntries(streams)
+	dirStart, dirSectors := sector, cfbSectors(len(entries)*cfbDirEntrySize, cfbSectorSize)
+	sector += dirSectors
+	// Calculate the number of the FAT sectors and the DIFAT sectors which
+	// also occupy the sectors.
+	var fatSectors, difatSectors int
+	for {
+		fat := cfbSectors(sector+fatSectors+difatSectors, cfbSectorSize/4)
+		difat := 0
+		if fat > cfbHeaderDIFATLength {
+			difat = cfbSectors(fat-cfbHeaderDIFATLength, cfbSectorSize/4-1)
+		}
+		if fat == fatSectors && difat == difatSectors {
+			break
+		}
+		fatSectors, difatSectors = fat, difat
+	}
+	fatStart, difatStart := sector, sector+fatSectors
+	// Build the FAT and the mini FAT.
+	fat := make([]uint32, fatSectors*cfbSectorSize/4)
+	for idx := range fat {
+		fat[idx] = cfbFreeSect
+	}
+	for _, stream := range streams {
+		if len(stream.data) >= cfbMiniStreamCutoff {
+			cfbChain(fat, int(stream.start), cfbSectors(len(stream.data), cfbSectorSize))
+		}
+	}
+	cfbChain(fat, int(rootStart), miniStreamSectors)
+	cfbChain(fat, int(miniFATStart), miniFATSectors)
+	cfbChain(fat, dirStart, dirSectors)
+	for idx := 0; idx < fatSectors; idx++ {
+		fat[fatStart+idx] = cfbFATSect
+	}
+	for idx := 0; idx < difatSectors; idx++ {
+		fat[difatStart+idx] = cfbDIFATSect
+	}
+	miniFAT := make([]uint32, miniFATSectors*cfbSectorSize/4)
+	for idx := range miniFAT {
+		miniFAT[idx] = cfbFreeSect
+	}
+	for _, chain := range miniChains {
+		cfbChain(miniFAT, chain[0], chain[1])
+	}
+	// Build the DIFAT.
+	difat := make([]uint32, cfbHeaderDIFATLength+difatSectors*(cfbSectorSize/4-1))
+	for idx := range difat {
+		difat[idx] = cfbFreeSect
+		if idx < fatSectors {
+			difat[idx] = uint32(fatStart + idx)
+		}
+	}
+
+	var buf bytes.Buffer
+	write := func(data ...interface{}) {
+		for _, v := range data {
+			_ = binary.Write(&buf, binary.LittleEndian, v)
+		}
+	}
+	firstDIFAT := uint32(cfbEndOfChain)
+	if difatSectors > 0 {
+		firstDIFAT = uint32(difatStart)
+	}
+	// Header
+	buf.Write(oleIdentifier)
+	buf.Write(make([]byte, 16))
+	write(uint16(0x003E), uint16(0x0003), uint16(0xFFFE), uint16(9), uint16(6))
+	buf.Write(make([]byte, 6))
+	write(uint32(0), uint32(fatSectors), uint32(dirStart), uint32(0), uint32(cfbMiniStreamCutoff),
+		miniFATStart, uint32(miniFATSectors), firstDIFAT, uint32(difatSectors), difat[:cfbHeaderDIFATLength])
+	// Regular stream sectors
+	for _, stream := range streams {
+		if len(stream.data) >= cfbMiniStreamCutoff {
+			buf.Write(padBlock(stream.data, cfbSectorSize))
+		}
+	}
+	// Mini stream and mini FAT sectors
+	buf.Write(padBlock(miniStream, cfbSectorSize))
+	write(miniFAT)
+	// Directory sectors
+	for _, entry := range entries {
+		start, size := uint32(0), 0
+		switch {
+		case entry.objectType == cfbRootStorageObject:
+			start, size = rootStart, len(miniStream)
+		case entry.stream != -1:
+			start, size = streams[entry.stream].start, len(streams[entry.stream].data)
+		}
+		nameBuf := make([]uint16, 32)
+		copy(nameBuf[:31], utf16.Encode([]rune(entry.name)))
+		nameLen := uint16((len(utf16.Encode([]rune(entry.name))) + 1) * 2)
+		if nameLen > 64 {
+			nameLen = 64
+		}
+		write(nameBuf, nameLen, entry.objectType, byte(1), entry.left, entry.right, entry.child)
+		buf.Write(make([]byte, 36))
+		write(start, uint64(size))
+	}
+	for idx := len(entries); idx < dirSectors*cfbSectorSize/cfbDirEntrySize; idx++ {
+		write(make([]byte, 68), uint32(cfbNoStream), uint32(cfbNoStream), uint32(cfbNoStream), make([]byte, 48))
+	}
+	// FAT and DIFAT sectors
+	write(fat)
+	for idx := 0; idx < difatSectors; idx++ {
+		offset := cfbHeaderDIFATLength + idx*(cfbSectorSize/4-1)
+		next := uint32(cfbEndOfChain)
+		if idx < difatSectors-1 {
+			next = uint32(difatStart + idx + 1)
+		}
+		write(difat[offset:offset+cfbSectorSize/4-1], next)
+	}
+	return buf.Bytes()
+}
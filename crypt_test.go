@@ -10,16 +10,175 @@
 package excelize
 
 import (
+	"bytes"
+	"crypto/aes"
+	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/richardlehane/mscfb"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEncrypt(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "encryptSHA1.xlsx"), Options{Password: "password"})
 	assert.NoError(t, err)
-	assert.EqualError(t, f.SaveAs(filepath.Join("test", "BadEncrypt.xlsx"), Options{Password: "password"}), "not support encryption currently")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"}))
+	f, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"})
+	assert.NoError(t, err)
+	cell, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SECRET", cell)
+
+	// Test encrypt with the specified encryption options.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "data"))
+	for _, opts := range []Options{
+		{Password: "密码", EncryptHashAlgorithm: "sha1", EncryptKeyBits: 128, EncryptSpinCount: 1},
+		{Password: "password", EncryptHashAlgorithm: "SHA256", EncryptKeyBits: 192, EncryptSpinCount: 1000},
+		{Password: strings.Repeat("p", MaxPasswordLength), EncryptHashAlgorithm: "SHA384", EncryptSpinCount: 10},
+	} {
+		buf := new(bytes.Buffer)
+		f.options = &opts
+		_, err = f.WriteTo(buf)
+		assert.NoError(t, err)
+		f2, err := OpenReader(buf, opts)
+		assert.NoError(t, err)
+		cell, err = f2.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "data", cell)
+	}
+
+	// Test encrypt with invalid encryption options.
+	for _, c := range []struct {
+		opts *Options
+		err  error
+	}{
+		{nil, ErrPasswordLength},
+		{&Options{}, ErrPasswordLength},
+		{&Options{Password: strings.Repeat("p", MaxPasswordLength+1)}, ErrPasswordLength},
+		{&Options{Password: "password", EncryptHashAlgorithm: "MD5"}, ErrEncryptHashAlgorithm},
+		{&Options{Password: "password", EncryptKeyBits: 64}, ErrEncryptKeyBits},
+		{&Options{Password: "password", EncryptSpinCount: -1}, ErrEncryptSpinCount},
+		{&Options{Password: "password", EncryptSpinCount: MaxEncryptSpinCount + 1}, ErrEncryptSpinCount},
+	} {
+		_, err := Encrypt([]byte("data"), c.opts)
+		assert.EqualError(t, err, c.err.Error())
+	}
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "password", EncryptKeyBits: 64}), ErrEncryptKeyBits.Error())
+}
+
+func TestVerifyEncryption(t *testing.T) {
+	opts := &Options{Password: "password", EncryptSpinCount: 1}
+	raw := []byte("data")
+	packageBuf, err := Encrypt(raw, opts)
+	assert.NoError(t, err)
+	// Test verify encryption with mismatched data.
+	assert.EqualError(t, verifyEncryption(packageBuf, []byte("other"), opts), ErrEncryptSelfCheck.Error())
+	// Test verify encryption with invalid compound file.
+	assert.EqualError(t, verifyEncryption([]byte("data"), raw, opts), ErrEncryptSelfCheck.Error())
+	// Test verify encryption with the standard encryption.
+	content, err := ioutil.ReadFile(filepath.Join("test", "encryptAES.xlsx"))
+	assert.NoError(t, err)
+	assert.EqualError(t, verifyEncryption(content, raw, opts), ErrEncryptSelfCheck.Error())
+	// Test verify encryption with invalid encryption info.
+	packageBuf = writeCFB([]cfbStream{
+		{name: "EncryptionInfo", data: []byte{0x04, 0x00, 0x04, 0x00, 0x40, 0x00, 0x00, 0x00, '<'}},
+		{name: "EncryptedPackage", data: raw},
+	})
+	assert.EqualError(t, verifyEncryption(packageBuf, raw, opts), ErrEncryptSelfCheck.Error())
+
+	// Test verify data integrity with invalid encryption info.
+	encryption, err := agileEncryptionInfo(opts)
+	assert.NoError(t, err)
+	encryption.DataIntegrity.EncryptedHmacKey = "*"
+	assert.False(t, verifyDataIntegrity(encryption, make([]byte, 32), raw))
+	encryption.KeyData.HashAlgorithm = "unknown"
+	assert.False(t, verifyDataIntegrity(encryption, make([]byte, 32), raw))
+}
+
+func TestDecrypt(t *testing.T) {
+	opts := &Options{Password: "password", EncryptSpinCount: 1}
+	raw := []byte("data")
+	packageBuf, err := Encrypt(raw, opts)
+	assert.NoError(t, err)
+	decrypted, err := Decrypt(packageBuf, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, decrypted)
+	_, err = Decrypt(packageBuf, &Options{Password: "passwd"})
+	assert.EqualError(t, err, ErrWorkbookPassword.Error())
+
+	// Test verify password with invalid agile encryption info.
+	encryption, err := agileEncryptionInfo(opts)
+	assert.NoError(t, err)
+	for _, c := range []struct {
+		field *string
+		value string
+		err   string
+	}{
+		{&encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.SaltValue, "*", "illegal base64 data at input byte 0"},
+		{&encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.SaltValue, "AA==", ErrUnknownEncryptMechanism.Error()},
+		{&encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashInput, "*", "illegal base64 data at input byte 0"},
+		{&encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashInput, "AA==", ErrUnknownEncryptMechanism.Error()},
+		{&encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashInput, "", ErrWorkbookPassword.Error()},
+	} {
+		value := *c.field
+		*c.field = c.value
+		assert.EqualError(t, agileVerifyPassword(encryption, opts), c.err)
+		*c.field = value
+	}
+	// Test verify password with the standard encryption verifier.
+	block, err := aes.NewCipher(make([]byte, 16))
+	assert.NoError(t, err)
+	assert.False(t, standardVerifyPassword(block, StandardEncryptionVerifier{EncryptedVerifier: make([]byte, 15)}))
+	assert.False(t, standardVerifyPassword(block, StandardEncryptionVerifier{EncryptedVerifier: make([]byte, 16), EncryptedVerifierHash: make([]byte, 32)}))
+}
+
+func TestWriteCFB(t *testing.T) {
+	// Test write the compound file with the regular streams, mini streams
+	// and the DIFAT sectors.
+	streams := []cfbStream{
+		{name: "EncryptedPackage", data: bytes.Repeat([]byte{1}, 8<<20)},
+		{name: "Empty", data: []byte{}},
+		{name: "EncryptionInfo", data: bytes.Repeat([]byte{2}, 100)},
+		{name: "Small", data: bytes.Repeat([]byte{3}, 4095)},
+	}
+	doc, err := mscfb.New(bytes.NewReader(writeCFB(streams)))
+	assert.NoError(t, err)
+	sizes := map[string]int64{}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		buf := make([]byte, entry.Size)
+		n, _ := doc.Read(buf)
+		assert.Equal(t, int(entry.Size), n)
+		for _, stream := range streams {
+			if stream.name == entry.Name {
+				assert.Equal(t, stream.data, buf[:len(stream.data)], entry.Name)
+			}
+		}
+		sizes[entry.Name] = entry.Size
+	}
+	assert.Equal(t, map[string]int64{"EncryptedPackage": 8 << 20, "Empty": 0, "EncryptionInfo": 100, "Small": 4095}, sizes)
+
+	// Test write the compound file with the streams in the nested storages,
+	// the control characters in the names will be trimmed by the reader.
+	streams = append(dataSpacesStreams(), cfbStream{name: "EncryptionInfo", data: []byte{1}})
+	doc, err = mscfb.New(bytes.NewReader(writeCFB(streams)))
+	assert.NoError(t, err)
+	paths := map[string][]byte{}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		buf := make([]byte, entry.Size)
+		_, _ = doc.Read(buf)
+		paths[strings.Join(append(entry.Path, entry.Name), "/")] = buf
+	}
+	for _, stream := range streams {
+		assert.Equal(t, stream.data, paths[strings.ReplaceAll(stream.name, "\x06", "")], stream.name)
+	}
+	for _, storage := range []string{"DataSpaces", "DataSpaces/DataSpaceInfo", "DataSpaces/TransformInfo", "DataSpaces/TransformInfo/StrongEncryptionTransform"} {
+		assert.Contains(t, paths, storage)
+	}
+	assert.Len(t, paths["DataSpaces/TransformInfo/StrongEncryptionTransform/Primary"], 200)
+	assert.Equal(t, []byte{0x58, 0, 0, 0, 1, 0, 0, 0}, paths["DataSpaces/TransformInfo/StrongEncryptionTransform/Primary"][:8])
 }
 
 func TestEncryptionMechanism(t *testing.T) {
@@ -0,0 +1,474 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CultureName is the name of the language and region used to format the
+// cell values, the custom culture name could be defined by the user and
+// registered by RegisterCultureInfo.
+type CultureName byte
+
+// This section defines the currently supported culture names.
+const (
+	CultureNameUnknown CultureName = iota
+	CultureNameEnUS
+	CultureNameDeDE
+	CultureNameEsES
+	CultureNameFrFR
+	CultureNameJaJP
+	CultureNameZhCN
+)
+
+// CultureInfo defined the culture specific settings used to format the cell
+// values, the number format codes of the culture are written in the
+// invariant culture, that is, using the comma as the group separator and
+// the period as the decimal separator.
+//
+// DecimalSeparator and GroupSeparator specifies the separators of the
+// formatted numbers.
+//
+// CurrencyFormat specifies the number format code with 2 decimal places for
+// the currency built-in number formats 5 to 8, the currency built-in number
+// formats 5 and 6 will be formatted without decimal places.
+//
+// ShortDatePattern specifies the number format code for the date built-in
+// number formats 14 and 22, which determines the order of the year, month
+// and day.
+//
+// MonthNames, AbbrMonthNames, DayNames and AbbrDayNames specifies the full
+// and abbreviated names of the month from January and the day of the week
+// from Sunday.
+//
+// AMDesignator and PMDesignator specifies the designators for the 12-hour
+// time formats.
+type CultureInfo struct {
+	DecimalSeparator string
+	GroupSeparator   string
+	CurrencyFormat   string
+	ShortDatePattern string
+	MonthNames       [12]string
+	AbbrMonthNames   [12]string
+	DayNames         [7]string
+	AbbrDayNames     [7]string
+	AMDesignator     string
+	PMDesignator     string
+}
+
+var (
+	// cultureInfos defined the registered culture info by the culture name.
+	cultureInfos sync.Map
+	// numFmtQuotedTextRegexp defined the regular expression to match the
+	// quoted literal text in the number format code.
+	numFmtQuotedTextRegexp = regexp.MustCompile(`"[^"]*"`)
+	// numFmtBracketRegexp defined the regular expression to match the color,
+	// condition and locale settings in the number format code.
+	numFmtBracketRegexp = regexp.MustCompile(`\[[^\]]*\]`)
+	// timeNameMarkers defined the replacer to convert the month names, day
+	// names and AM/PM designators in the Go time layout to the markers,
+	// which will be replaced by the culture specific names after the time
+	// formatted.
+	timeNameMarkers = strings.NewReplacer("January", "\x01", "Jan", "\x02", "Monday", "\x03", "Mon", "\x04", "PM", "\x05", "pm", "\x06")
+)
+
+func init() {
+	cjkMonths := [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"}
+	for name, culture := range map[CultureName]CultureInfo{
+		CultureNameEnUS: {
+			DecimalSeparator: ".", GroupSeparator: ",",
+			CurrencyFormat:   `"$"#,##0.00_);("$"#,##0.00)`,
+			ShortDatePattern: "m/d/yyyy",
+			MonthNames:       [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			AbbrMonthNames:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			DayNames:         [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			AbbrDayNames:     [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			AMDesignator:     "AM", PMDesignator: "PM",
+		},
+		CultureNameDeDE: {
+			DecimalSeparator: ",", GroupSeparator: ".",
+			CurrencyFormat:   `#,##0.00 "€";-#,##0.00 "€"`,
+			ShortDatePattern: "dd.mm.yyyy",
+			MonthNames:       [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			AbbrMonthNames:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			DayNames:         [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			AbbrDayNames:     [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+			AMDesignator:     "AM", PMDesignator: "PM",
+		},
+		CultureNameEsES: {
+			DecimalSeparator: ",", GroupSeparator: ".",
+			CurrencyFormat:   `#,##0.00 "€";-#,##0.00 "€"`,
+			ShortDatePattern: "dd/mm/yyyy",
+			MonthNames:       [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			AbbrMonthNames:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+			DayNames:         [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			AbbrDayNames:     [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			AMDesignator:     "a. m.", PMDesignator: "p. m.",
+		},
+		CultureNameFrFR: {
+			DecimalSeparator: ",", GroupSeparator: "\u00a0",
+			CurrencyFormat:   `#,##0.00 "€";-#,##0.00 "€"`,
+			ShortDatePattern: "dd/mm/yyyy",
+			MonthNames:       [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			AbbrMonthNames:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			DayNames:         [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			AbbrDayNames:     [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			AMDesignator:     "AM", PMDesignator: "PM",
+		},
+		CultureNameJaJP: {
+			DecimalSeparator: ".", GroupSeparator: ",",
+			CurrencyFormat:   `"￥"#,##0.00;"￥"-#,##0.00`,
+			ShortDatePattern: "yyyy/m/d",
+			MonthNames:       cjkMonths,
+			AbbrMonthNames:   cjkMonths,
+			DayNames:         [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+			AbbrDayNames:     [7]string{"日", "月", "火", "水", "木", "金", "土"},
+			AMDesignator:     "午前", PMDesignator: "午後",
+		},
+		CultureNameZhCN: {
+			DecimalSeparator: ".", GroupSeparator: ",",
+			CurrencyFormat:   `"¥"#,##0.00;"¥"-#,##0.00`,
+			ShortDatePattern: "yyyy/m/d",
+			MonthNames:       [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			AbbrMonthNames:   cjkMonths,
+			DayNames:         [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			AbbrDayNames:     [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+			AMDesignator:     "上午", PMDesignator: "下午",
+		},
+	} {
+		RegisterCultureInfo(name, culture)
+	}
+}
+
+// RegisterCultureInfo provides a function to register the culture info by
+// given culture name, the registered culture info will override the built-in
+// culture info with the same culture name. The built-in culture info are
+// available for the en-US, de-DE, es-ES, fr-FR, ja-JP and zh-CN cultures.
+// For example, register the culture info for the pt-BR culture:
+//
+//	const CultureNamePtBR excelize.CultureName = 100
+//
+//	excelize.RegisterCultureInfo(CultureNamePtBR, excelize.CultureInfo{
+//	    DecimalSeparator: ",",
+//	    GroupSeparator:   ".",
+//	    CurrencyFormat:   `"R$" #,##0.00;-"R$" #,##0.00`,
+//	    ShortDatePattern: "dd/mm/yyyy",
+//	    MonthNames:       [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+//	    AbbrMonthNames:   [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+//	    DayNames:         [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+//	    AbbrDayNames:     [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+//	    AMDesignator:     "AM",
+//	    PMDesignator:     "PM",
+//	})
+//
+// The culture name CultureNameUnknown is reserved for formatting the cell
+// values without culture specific settings, and can't be registered.
+func RegisterCultureInfo(name CultureName, culture CultureInfo) {
+	if name == CultureNameUnknown {
+		return
+	}
+	cultureInfos.Store(name, &culture)
+}
+
+// GetCultureInfo provides a function to get the registered culture info by
+// given culture name, it will return nil if the culture info of the culture
+// name has not been registered.
+func GetCultureInfo(name CultureName) *CultureInfo {
+	if culture, ok := cultureInfos.Load(name); ok {
+		c := *culture.(*CultureInfo)
+		return &c
+	}
+	return nil
+}
+
+// getCultureInfo provides a function to get the culture info used to format
+// the cell values by given options, the culture name of the options will
+// override the culture name of the workbook.
+func (f *File) getCultureInfo(opts ...Options) *CultureInfo {
+	name := f.culture
+	for _, opt := range opts {
+		if opt.CultureInfo != CultureNameUnknown {
+			name = opt.CultureInfo
+		}
+	}
+	if culture, ok := cultureInfos.Load(name); ok {
+		return culture.(*CultureInfo)
+	}
+	return nil
+}
+
+// cultureNumFmtFunc defined the functions to format the cell values with
+// the culture info by given built-in number format ID.
+var cultureNumFmtFunc = map[int]func(v string, format string, culture *CultureInfo) string{
+	2:  formatNumberWithCulture,
+	3:  formatNumberWithCulture,
+	4:  formatNumberWithCulture,
+	5:  formatIntCurrencyWithCulture,
+	6:  formatIntCurrencyWithCulture,
+	7:  formatCurrencyWithCulture,
+	8:  formatCurrencyWithCulture,
+	10: formatNumberWithCulture,
+	14: formatShortDateWithCulture,
+	15: formatTime,
+	16: formatTime,
+	17: formatTime,
+	18: formatTime,
+	19: formatTime,
+	20: formatTime,
+	21: formatTime,
+	22: formatShortDateWithCulture,
+	37: formatNumberWithCulture,
+	38: formatNumberWithCulture,
+	39: formatNumberWithCulture,
+	40: formatNumberWithCulture,
+	45: formatTime,
+	46: formatTime,
+	47: formatTime,
+}
+
+// formatCurrencyWithCulture provides a function to format the cell value by
+// the currency format of the culture.
+func formatCurrencyWithCulture(v string, format string, culture *CultureInfo) string {
+	if format = culture.CurrencyFormat; format == "" {
+		format = "#,##0.00"
+	}
+	return formatNumberWithCulture(v, format, culture)
+}
+
+// formatIntCurrencyWithCulture provides a function to format the cell value
+// by the currency format of the culture without decimal places.
+func formatIntCurrencyWithCulture(v string, format string, culture *CultureInfo) string {
+	if format = culture.CurrencyFormat; format == "" {
+		format = "#,##0.00"
+	}
+	return formatNumberWithCulture(v, strings.Replace(format, ".00", "", -1), culture)
+}
+
+// formatShortDateWithCulture provides a function to format the cell value
+// by the short date pattern of the culture, the time part will be appended
+// for the built-in number format 22.
+func formatShortDateWithCulture(v string, format string, culture *CultureInfo) string {
+	pattern := culture.ShortDatePattern
+	if pattern == "" {
+		pattern = format
+	} else if format == builtInNumFmt[22] {
+		pattern += " h:mm"
+	}
+	return formatTime(v, pattern, culture)
+}
+
+// replaceTimeNameMarkers provides a function to replace the markers in the
+// formatted time with the culture specific month names, day names and AM/PM
+// designators by given time.
+func (culture *CultureInfo) replaceTimeNameMarkers(s string, t time.Time) string {
+	designator := culture.AMDesignator
+	if t.Hour() >= 12 {
+		designator = culture.PMDesignator
+	}
+	return strings.NewReplacer(
+		"\x01", culture.MonthNames[t.Month()-1],
+		"\x02", culture.AbbrMonthNames[t.Month()-1],
+		"\x03", culture.DayNames[t.Weekday()],
+		"\x04", culture.AbbrDayNames[t.Weekday()],
+		"\x05", designator,
+		"\x06", designator,
+	).Replace(s)
+}
+
+// numFmtSection directly maps the parsed section of the number format code,
+// which consists of the literal text before and after the digit
+// placeholders.
+type numFmtSection struct {
+	prefix, suffix               strings.Builder
+	intDigits, fracDigits        int
+	optionalFracDigits, scaling  int
+	grouping, percent, hasDigits bool
+}
+
+// parseNumFmtSection provides a function to parse the section of the number
+// format code by given section. The color, condition and locale settings
+// will be ignored, the currency symbol in the locale settings will be used
+// as the literal text.
+func parseNumFmtSection(section string) *numFmtSection {
+	var (
+		s                   numFmtSection
+		runes               = []rune(section)
+		inDecimal, inSuffix bool
+		pendingCommas       int
+	)
+	literal := func(text string) {
+		if s.hasDigits {
+			inSuffix = true
+		}
+		if inSuffix {
+			s.suffix.WriteString(text)
+			return
+		}
+		s.prefix.WriteString(text)
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			literal(string(runes[i+1 : j]))
+			i = j
+		case r == '\\' && i+1 < len(runes):
+			i++
+			literal(string(runes[i]))
+		case (r == '_' || r == '*') && i+1 < len(runes):
+			i++
+		case r == '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if token := string(runes[i+1 : j]); strings.HasPrefix(token, "$") {
+				literal(strings.SplitN(token[1:], "-", 2)[0])
+			}
+			i = j
+		case !inSuffix && strings.ContainsRune("0#?", r):
+			s.hasDigits = true
+			if pendingCommas > 0 {
+				s.grouping, pendingCommas = true, 0
+			}
+			if inDecimal {
+				s.fracDigits++
+				if r == '#' {
+					s.optionalFracDigits++
+				}
+				continue
+			}
+			if r != '#' {
+				s.intDigits++
+			}
+		case !inSuffix && r == ',':
+			pendingCommas++
+		case !inSuffix && r == '.' && (s.hasDigits || i+1 < len(runes) && strings.ContainsRune("0#?", runes[i+1])):
+			s.hasDigits, inDecimal = true, true
+		case r == '%':
+			s.percent = true
+			literal("%")
+		default:
+			literal(string(r))
+		}
+	}
+	s.scaling = pendingCommas
+	return &s
+}
+
+// splitNumFmtSections provides a function to split the number format code
+// into sections by the semicolons outside the quoted text.
+func splitNumFmtSections(format string) []string {
+	var (
+		sections []string
+		quoted   bool
+		start    int
+	)
+	for i := 0; i < len(format); i++ {
+		switch format[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case ';':
+			if !quoted {
+				sections = append(sections, format[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(sections, format[start:])
+}
+
+// formatNumberWithCulture provides a function to format the numeric cell
+// value by given number format code and culture info, the digit grouping
+// and the decimal separator of the culture will be used. The original value
+// will be returned if the cell value is not numeric or the number format
+// code doesn't contain any digit placeholder, the scientific notation and
+// fraction number formats are not supported currently.
+func formatNumberWithCulture(v string, format string, culture *CultureInfo) string {
+	num, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	sections := splitNumFmtSections(format)
+	section, negative := sections[0], false
+	if num < 0 {
+		num = -num
+		if len(sections) > 1 && sections[1] != "" {
+			section = sections[1]
+		} else {
+			negative = true
+		}
+	} else if num == 0 && len(sections) > 2 && sections[2] != "" {
+		section = sections[2]
+	}
+	if upper := strings.ToUpper(section); strings.Contains(upper, "E+") || strings.Contains(upper, "E-") || strings.Contains(section, "/") {
+		return v
+	}
+	s := parseNumFmtSection(section)
+	if !s.hasDigits {
+		if lower := strings.ToLower(section); len(sections) > 1 && !strings.Contains(lower, "general") && !strings.Contains(lower, "@") {
+			return s.prefix.String()
+		}
+		return v
+	}
+	if s.percent {
+		num *= 100
+	}
+	num /= math.Pow(1000, float64(s.scaling))
+	// Round half away from zero as the spreadsheet application.
+	precision := math.Pow(10, float64(s.fracDigits))
+	num = math.Round(num*precision) / precision
+	parts := strings.SplitN(strconv.FormatFloat(num, 'f', s.fracDigits, 64), ".", 2)
+	intPart := strings.TrimLeft(parts[0], "0")
+	for len(intPart) < s.intDigits {
+		intPart = "0" + intPart
+	}
+	if s.grouping && len(intPart) > 3 {
+		var sb strings.Builder
+		for i, digit := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				sb.WriteString(culture.GroupSeparator)
+			}
+			sb.WriteRune(digit)
+		}
+		intPart = sb.String()
+	}
+	var result strings.Builder
+	if negative && (len(parts) > 1 && strings.Trim(parts[1], "0") != "" || strings.Trim(parts[0], "0") != "") {
+		result.WriteString("-")
+	}
+	result.WriteString(s.prefix.String())
+	result.WriteString(intPart)
+	if len(parts) > 1 {
+		frac := parts[1]
+		for i := 0; i < s.optionalFracDigits && strings.HasSuffix(frac, "0"); i++ {
+			frac = frac[:len(frac)-1]
+		}
+		if frac != "" {
+			result.WriteString(culture.DecimalSeparator + frac)
+		}
+	}
+	result.WriteString(s.suffix.String())
+	return result.String()
+}
@@ -0,0 +1,116 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCultureInfo(t *testing.T) {
+	f := NewFile()
+	for cell, numFmt := range map[string]int{"A1": 4, "A2": 7, "A3": 14, "A4": 15, "A5": 22, "A6": 18, "A7": 3, "A8": 2, "A9": 10, "A10": 5, "A11": 39} {
+		style, err := f.NewStyle(&Style{NumFmt: numFmt})
+		assert.NoError(t, err)
+		// The currency built-in number formats depend on the language.
+		numFmtID := numFmt
+		f.Styles.CellXfs.Xf[style].NumFmtID = &numFmtID
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, style))
+	}
+	for cell, value := range map[string]interface{}{"A1": 1234567.891, "A2": -1234.5, "A3": 43528, "A4": 43528, "A5": 43528.75, "A6": 43528.75, "A7": 1234567, "A8": 0.5, "A9": 0.1234, "A10": 1234.5, "A11": -1234.5} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	for _, c := range []struct {
+		culture  CultureName
+		expected []string
+	}{
+		{CultureNameUnknown, []string{"1234567.89", "-1234.5", "03-04-19", "4-Mar-19", "3/4/19 18:00", "6:00 pm", "1234567", "0.50", "12.34%", "1234.5", "(-1234.50)"}},
+		{CultureNameEnUS, []string{"1,234,567.89", "($1,234.50)", "3/4/2019", "4-Mar-19", "3/4/2019 18:00", "6:00 PM", "1,234,567", "0.50", "12.34%", "$1,235", "(1,234.50)"}},
+		{CultureNameDeDE, []string{"1.234.567,89", "-1.234,50 €", "04.03.2019", "4-Mär-19", "04.03.2019 18:00", "6:00 PM", "1.234.567", "0,50", "12,34%", "1.235 €", "(1.234,50)"}},
+		{CultureNameFrFR, []string{"1\u00a0234\u00a0567,89", "-1\u00a0234,50 €", "04/03/2019", "4-mars-19", "04/03/2019 18:00", "6:00 PM", "1\u00a0234\u00a0567", "0,50", "12,34%", "1\u00a0235 €", "(1\u00a0234,50)"}},
+		{CultureNameJaJP, []string{"1,234,567.89", "￥-1,234.50", "2019/3/4", "4-3月-19", "2019/3/4 18:00", "6:00 午後", "1,234,567", "0.50", "12.34%", "￥1,235", "(1,234.50)"}},
+	} {
+		for i, expected := range c.expected {
+			cell, err := CoordinatesToCellName(1, i+1)
+			assert.NoError(t, err)
+			value, err := f.GetCellValue("Sheet1", cell, Options{CultureInfo: c.culture})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value, cell)
+		}
+	}
+
+	// Test get cell value with the culture of the workbook.
+	f.culture = CultureNameEsES
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.234.567,89", value)
+	value, err = f.GetCellValue("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.Equal(t, "6:00 p. m.", value)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "04/03/2019", rows[2][0])
+	// Test the culture of the options overrides the culture of the workbook.
+	value, err = f.GetCellValue("Sheet1", "A1", Options{CultureInfo: CultureNameEnUS})
+	assert.NoError(t, err)
+	assert.Equal(t, "1,234,567.89", value)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCultureInfo.xlsx")))
+
+	// Test open workbook with the culture.
+	f, err = OpenFile(filepath.Join("test", "TestCultureInfo.xlsx"), Options{CultureInfo: CultureNameZhCN})
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.Equal(t, "6:00 下午", value)
+
+	// Test get cell value with custom number formats.
+	f = NewFile(Options{CultureInfo: CultureNameDeDE})
+	for cell, numFmt := range map[string]string{
+		"B1":  "[$$-409]#,##0.00",
+		"B2":  `#,##0.00\ [$€-407];[Red]\-#,##0.00\ [$€-407]`,
+		"B3":  "dddd, d. mmmm yyyy",
+		"B4":  "ddd mmm d",
+		"B5":  `0.0,,"M"`,
+		"B6":  "0.00E+00",
+		"B7":  "#.##",
+		"B8":  "@",
+		"B9":  "0.000%",
+		"B10": `#,##0;-#,##0;"zero"`,
+	} {
+		numFmt := numFmt
+		style, err := f.NewStyle(&Style{CustomNumFmt: &numFmt})
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, style))
+	}
+	for cell, value := range map[string]interface{}{"B1": 1234.5, "B2": -1234.5, "B3": 43528, "B4": 43528, "B5": 12345678, "B6": 12345, "B7": 12.5, "B8": 12.5, "B9": 0.12345, "B10": 0} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	for cell, expected := range map[string]string{"B1": "$1.234,50", "B2": "-1.234,50 €", "B3": "Montag, 4. März 2019", "B4": "Mo Mär 4", "B5": "12,3M", "B6": "12345", "B7": "12,5", "B8": "12.5", "B9": "12,345%", "B10": "zero"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+
+	// Test register custom culture info.
+	const cultureNameCustom CultureName = 100
+	assert.Nil(t, GetCultureInfo(cultureNameCustom))
+	culture := GetCultureInfo(CultureNameEnUS)
+	culture.DecimalSeparator, culture.GroupSeparator, culture.CurrencyFormat, culture.ShortDatePattern = ",", "'", "", ""
+	RegisterCultureInfo(cultureNameCustom, *culture)
+	RegisterCultureInfo(CultureNameUnknown, *culture)
+	assert.Nil(t, GetCultureInfo(CultureNameUnknown))
+	assert.Equal(t, ".", GetCultureInfo(CultureNameEnUS).DecimalSeparator)
+	for _, c := range []struct {
+		numFmt          int
+		value, expected string
+	}{{4, "1234.5", "1'234,50"}, {5, "1234.5", "1'235"}, {7, "-1234.5", "-1'234,50"}, {14, "43528", "03-04-19"}} {
+		assert.Equal(t, c.expected, cultureNumFmtFunc[c.numFmt](c.value, builtInNumFmt[c.numFmt], GetCultureInfo(cultureNameCustom)))
+	}
+
+	// Test format invalid number with culture.
+	assert.Equal(t, "text", formatNumberWithCulture("text", "#,##0", culture))
+	assert.Equal(t, "0", formatNumberWithCulture("-0.1", "0", culture))
+	assert.Equal(t, "(0)", formatNumberWithCulture("-0.1", "#,##0;(#,##0)", culture))
+	assert.Equal(t, `1`, formatNumberWithCulture("1", `0"`, culture))
+	assert.Equal(t, `1`, formatNumberWithCulture("1", `[$`, culture))
+}
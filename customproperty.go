@@ -0,0 +1,165 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// SetSheetCustomProperty provides a function to set the custom property of
+// the worksheet by given worksheet name, property name and value. The custom
+// properties are named metadata stored along with the worksheet, which could
+// be read by the automation tools, such as the CustomProperties collection
+// in the VBA and the Office Scripts. The value of the property with the same
+// name will be replaced. For example, leave a machine-readable hint on
+// Sheet1 for the downstream automation:
+//
+//    err := f.SetSheetCustomProperty("Sheet1", "ReportSchema", `{"version":2,"keyColumn":"A"}`)
+//
+func (f *File) SetSheetCustomProperty(sheet, name, value string) error {
+	if name == "" {
+		return ErrParameterRequired
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	content := encodeCustomPropertyValue(value)
+	if ws.CustomProperties != nil {
+		for _, customPr := range ws.CustomProperties.CustomPr {
+			if customPr.Name != name {
+				continue
+			}
+			if target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID); target != "" {
+				f.Pkg.Store(strings.Replace(target, "..", "xl", -1), content)
+				return err
+			}
+		}
+	} else {
+		ws.CustomProperties = &xlsxCustomProperties{}
+	}
+	propertyID := f.countParts("xl/customProperty") + 1
+	sheetRelationshipsCustomPropertyXML := "../customProperty" + strconv.Itoa(propertyID) + ".bin"
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	rID := f.addRels(sheetRels, SourceRelationshipCustomProperty, sheetRelationshipsCustomPropertyXML, "")
+	f.addSheetNameSpace(sheet, SourceRelationship)
+	ws.CustomProperties.CustomPr = append(ws.CustomProperties.CustomPr, &xlsxCustomPr{
+		Name: name,
+		RID:  "rId" + strconv.Itoa(rID),
+	})
+	f.Pkg.Store("xl/customProperty"+strconv.Itoa(propertyID)+".bin", content)
+	f.addContentTypeOverride("/xl/customProperty"+strconv.Itoa(propertyID)+".bin", ContentTypeSpreadSheetMLCustomProperty)
+	return err
+}
+
+// GetSheetCustomProperties provides a function to get the custom properties
+// of the worksheet by given worksheet name. For example, get the custom
+// properties of Sheet1:
+//
+//    props, err := f.GetSheetCustomProperties("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for name, value := range props {
+//        fmt.Println(name, value)
+//    }
+//
+func (f *File) GetSheetCustomProperties(sheet string) (map[string]string, error) {
+	props := map[string]string{}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.CustomProperties == nil {
+		return props, err
+	}
+	for _, customPr := range ws.CustomProperties.CustomPr {
+		target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID)
+		if target == "" {
+			continue
+		}
+		props[customPr.Name] = decodeCustomPropertyValue(f.readXML(strings.Replace(target, "..", "xl", -1)))
+	}
+	return props, err
+}
+
+// DeleteSheetCustomProperty provides a function to delete the custom
+// property of the worksheet by given worksheet name and property name.
+func (f *File) DeleteSheetCustomProperty(sheet, name string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.CustomProperties == nil {
+		return err
+	}
+	for idx := 0; idx < len(ws.CustomProperties.CustomPr); idx++ {
+		customPr := ws.CustomProperties.CustomPr[idx]
+		if customPr.Name != name {
+			continue
+		}
+		if target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID); target != "" {
+			f.deleteSheetRelationships(sheet, customPr.RID)
+			f.deleteUnusedPart(strings.Replace(target, "..", "xl", -1))
+		}
+		ws.CustomProperties.CustomPr = append(ws.CustomProperties.CustomPr[:idx], ws.CustomProperties.CustomPr[idx+1:]...)
+		idx--
+	}
+	if len(ws.CustomProperties.CustomPr) == 0 {
+		ws.CustomProperties = nil
+	}
+	return err
+}
+
+// addContentTypeOverride provides a function to add the override content
+// type by given part name and content type if it doesn't exist.
+func (f *File) addContentTypeOverride(partName, contentType string) {
+	content := f.contentTypesReader()
+	content.Lock()
+	defer content.Unlock()
+	for _, v := range content.Overrides {
+		if v.PartName == partName {
+			return
+		}
+	}
+	content.Overrides = append(content.Overrides, xlsxOverride{
+		PartName:    partName,
+		ContentType: contentType,
+	})
+}
+
+// encodeCustomPropertyValue provides a function to encode the value of the
+// custom property as the null-terminated UTF-16LE string, as the same as
+// the custom property part generated by the spreadsheet application.
+func encodeCustomPropertyValue(value string) []byte {
+	units := append(utf16.Encode([]rune(value)), 0)
+	content := make([]byte, len(units)*2)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(content[i*2:], unit)
+	}
+	return content
+}
+
+// decodeCustomPropertyValue provides a function to decode the value of the
+// custom property from the UTF-16LE string, the content will be returned as
+// is if it isn't an UTF-16LE string.
+func decodeCustomPropertyValue(content []byte) string {
+	if len(content)%2 != 0 {
+		return string(content)
+	}
+	units := make([]uint16, 0, len(content)/2)
+	for i := 0; i < len(content); i += 2 {
+		units = append(units, binary.LittleEndian.Uint16(content[i:]))
+	}
+	for len(units) > 0 && units[len(units)-1] == 0 {
+		units = units[:len(units)-1]
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,63 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSheetCustomProperty(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetCustomProperty("Sheet1", "ReportSchema", `{"version":1}`))
+	assert.NoError(t, f.SetSheetCustomProperty("Sheet1", "Owner", "财务部"))
+	assert.NoError(t, f.SetSheetCustomProperty("Sheet2", "Owner", "Ops"))
+	assert.NoError(t, f.SetSheetCustomProperty("Sheet1", "ReportSchema", `{"version":2}`))
+	content, ok := f.Pkg.Load("xl/customProperty2.bin")
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x22, 0x8d, 0xa1, 0x52, 0xe8, 0x90, 0, 0}, content)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSheetCustomProperty.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestSheetCustomProperty.xlsx"))
+	assert.NoError(t, err)
+	props, err := f.GetSheetCustomProperties("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ReportSchema": `{"version":2}`, "Owner": "财务部"}, props)
+	props, err = f.GetSheetCustomProperties("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "Ops"}, props)
+	// Test delete custom properties.
+	assert.NoError(t, f.DeleteSheetCustomProperty("Sheet1", "ReportSchema"))
+	_, ok = f.Pkg.Load("xl/customProperty1.bin")
+	assert.False(t, ok)
+	for _, override := range f.contentTypesReader().Overrides {
+		assert.NotEqual(t, "/xl/customProperty1.bin", override.PartName)
+	}
+	assert.NoError(t, f.DeleteSheetCustomProperty("Sheet1", "Owner"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.CustomProperties)
+	assert.NoError(t, f.DeleteSheetCustomProperty("Sheet1", "Owner"))
+	// Test add custom property after some properties have been deleted.
+	assert.NoError(t, f.SetSheetCustomProperty("Sheet1", "Owner", "BI"))
+	_, ok = f.Pkg.Load("xl/customProperty4.bin")
+	assert.True(t, ok)
+	props, err = f.GetSheetCustomProperties("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "BI"}, props)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSheetCustomProperty2.xlsx")))
+
+	// Test get custom property with the value which isn't UTF-16 string.
+	f.Pkg.Store("xl/customProperty4.bin", []byte("BI "))
+	props, err = f.GetSheetCustomProperties("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "BI "}, props)
+	// Test set custom property with empty name.
+	assert.EqualError(t, f.SetSheetCustomProperty("Sheet1", "", ""), ErrParameterRequired.Error())
+	// Test set, get and delete custom properties on not exists worksheet.
+	assert.EqualError(t, f.SetSheetCustomProperty("SheetN", "Owner", ""), "sheet SheetN is not exist")
+	_, err = f.GetSheetCustomProperties("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.EqualError(t, f.DeleteSheetCustomProperty("SheetN", "Owner"), "sheet SheetN is not exist")
+}
@@ -0,0 +1,295 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"strings"
+)
+
+// GetCustomViews provides a function to get the custom views of the
+// workbook, include the settings of each worksheet saved with the view. The
+// custom views will be preserved on saving the workbook. For example:
+//
+//	views, err := f.GetCustomViews()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, view := range views {
+//	    fmt.Println(view.Name, view.ActiveSheet)
+//	    for _, sheetView := range view.Sheets {
+//	        fmt.Println(sheetView.Sheet, sheetView.HiddenRows, sheetView.FilterRange)
+//	    }
+//	}
+func (f *File) GetCustomViews() ([]CustomView, error) {
+	var views []CustomView
+	wb := f.workbookReader()
+	if wb.CustomWorkbookViews == nil {
+		return views, nil
+	}
+	for _, v := range wb.CustomWorkbookViews.CustomWorkbookView {
+		view := CustomView{
+			Name:                 stringValue(v.Name),
+			GUID:                 stringValue(v.GUID),
+			PersonalView:         v.PersonalView != nil && *v.PersonalView,
+			IncludeHiddenRowCol:  defaultTrue(v.IncludeHiddenRowCol),
+			IncludePrintSettings: defaultTrue(v.IncludePrintSettings),
+		}
+		for _, sheet := range wb.Sheets.Sheet {
+			if v.ActiveSheetID != nil && sheet.SheetID == *v.ActiveSheetID {
+				view.ActiveSheet = sheet.Name
+			}
+		}
+		for _, sheet := range f.GetSheets() {
+			if sheet.Type != "worksheet" {
+				continue
+			}
+			ws, err := f.workSheetReader(sheet.Name)
+			if err != nil {
+				return views, err
+			}
+			if ws.CustomSheetViews == nil {
+				continue
+			}
+			for _, sv := range ws.CustomSheetViews.CustomSheetView {
+				if !strings.EqualFold(sv.GUID, view.GUID) {
+					continue
+				}
+				sheetView := CustomSheetView{
+					Sheet:         sheet.Name,
+					TopLeftCell:   sv.TopLeftCell,
+					Scale:         sv.Scale,
+					View:          sv.View,
+					State:         sv.State,
+					ShowGridLines: defaultTrue(sv.ShowGridLines),
+					HiddenRows:    sv.HiddenRows,
+					HiddenColumns: sv.HiddenColumns,
+				}
+				if sheetView.Scale == 0 {
+					sheetView.Scale = 100
+				}
+				if sheetView.View == "" {
+					sheetView.View = "normal"
+				}
+				if sheetView.State == "" {
+					sheetView.State = "visible"
+				}
+				if sv.AutoFilter != nil {
+					sheetView.FilterRange = sv.AutoFilter.Ref
+				}
+				view.Sheets = append(view.Sheets, sheetView)
+			}
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// AddCustomView provides a function to add a custom view into the workbook.
+// The display and filter settings of each worksheet will be captured from the
+// current state of the worksheet, include the top left cell, zoom scale,
+// visibility, hidden rows and columns and the auto filter with its criteria,
+// unless the settings of the worksheet are specified by the Sheets of the
+// view. The print settings of the worksheets will be saved with the view when
+// IncludePrintSettings is true. The custom view with the same name will be
+// replaced. For example, add a custom view named "Summary" based on the
+// current state of the workbook:
+//
+//	err := f.AddCustomView(&excelize.CustomView{
+//	    Name:                 "Summary",
+//	    IncludeHiddenRowCol:  true,
+//	    IncludePrintSettings: true,
+//	})
+func (f *File) AddCustomView(view *CustomView) error {
+	if view == nil || view.Name == "" {
+		return ErrParameterRequired
+	}
+	opt := *view
+	var err error
+	if opt.GUID == "" {
+		if opt.GUID, err = newGUID(); err != nil {
+			return err
+		}
+	}
+	for _, sheetView := range opt.Sheets {
+		if _, err = f.workSheetReader(sheetView.Sheet); err != nil {
+			return err
+		}
+	}
+	if err = f.DeleteCustomView(opt.Name); err != nil && err != ErrCustomViewNotExist {
+		return err
+	}
+	wb := f.workbookReader()
+	activeSheetID := f.getActiveSheetID()
+	if opt.ActiveSheet != "" {
+		idx := f.GetSheetIndex(opt.ActiveSheet)
+		if idx == -1 {
+			return ErrSheetNotExist{opt.ActiveSheet}
+		}
+		activeSheetID = wb.Sheets.Sheet[idx].SheetID
+	}
+	for _, sheet := range f.GetSheets() {
+		if sheet.Type != "worksheet" {
+			continue
+		}
+		ws, err := f.workSheetReader(sheet.Name)
+		if err != nil {
+			return err
+		}
+		sv := f.captureCustomSheetView(ws, sheet.Name, &opt)
+		if ws.CustomSheetViews == nil {
+			ws.CustomSheetViews = &xlsxCustomSheetViews{}
+		}
+		ws.CustomSheetViews.CustomSheetView = append(ws.CustomSheetViews.CustomSheetView, sv)
+	}
+	windowWidth, windowHeight := 28800, 12300
+	if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
+		if wb.BookViews.WorkBookView[0].WindowWidth > 0 {
+			windowWidth = wb.BookViews.WorkBookView[0].WindowWidth
+		}
+		if wb.BookViews.WorkBookView[0].WindowHeight > 0 {
+			windowHeight = wb.BookViews.WorkBookView[0].WindowHeight
+		}
+	}
+	if wb.CustomWorkbookViews == nil {
+		wb.CustomWorkbookViews = &xlsxCustomWorkbookViews{}
+	}
+	wb.CustomWorkbookViews.CustomWorkbookView = append(wb.CustomWorkbookViews.CustomWorkbookView, xlsxCustomWorkbookView{
+		Name:                 stringPtr(opt.Name),
+		GUID:                 stringPtr(opt.GUID),
+		PersonalView:         boolPtr(opt.PersonalView),
+		IncludeHiddenRowCol:  boolPtr(opt.IncludeHiddenRowCol),
+		IncludePrintSettings: boolPtr(opt.IncludePrintSettings),
+		ActiveSheetID:        intPtr(activeSheetID),
+		WindowWidth:          intPtr(windowWidth),
+		WindowHeight:         intPtr(windowHeight),
+	})
+	return nil
+}
+
+// captureCustomSheetView provides a function to create the custom sheet view
+// of the worksheet by given custom view settings, the settings of the
+// worksheet which not specified by the view will be captured from the
+// current state of the worksheet.
+func (f *File) captureCustomSheetView(ws *xlsxWorksheet, sheet string, opt *CustomView) *xlsxCustomSheetView {
+	sv := &xlsxCustomSheetView{GUID: opt.GUID}
+	sheetView := CustomSheetView{ShowGridLines: true}
+	var specified bool
+	for _, v := range opt.Sheets {
+		if strings.EqualFold(v.Sheet, sheet) {
+			sheetView, specified = v, true
+		}
+	}
+	if !specified {
+		if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 {
+			v := ws.SheetViews.SheetView[len(ws.SheetViews.SheetView)-1]
+			sheetView.TopLeftCell, sheetView.Scale, sheetView.View = v.TopLeftCell, int(v.ZoomScale), v.View
+			sheetView.ShowGridLines = defaultTrue(v.ShowGridLines)
+		}
+		for _, s := range f.workbookReader().Sheets.Sheet {
+			if strings.EqualFold(s.Name, sheet) {
+				sheetView.State = s.State
+			}
+		}
+		for _, row := range ws.SheetData.Row {
+			sheetView.HiddenRows = sheetView.HiddenRows || row.Hidden
+		}
+		if ws.Cols != nil {
+			for _, col := range ws.Cols.Col {
+				sheetView.HiddenColumns = sheetView.HiddenColumns || col.Hidden
+			}
+		}
+		if ws.AutoFilter != nil {
+			sheetView.FilterRange = ws.AutoFilter.Ref
+		}
+	}
+	sv.TopLeftCell, sv.Scale, sv.HiddenRows, sv.HiddenColumns = sheetView.TopLeftCell, sheetView.Scale, sheetView.HiddenRows, sheetView.HiddenColumns
+	if sheetView.View != "normal" {
+		sv.View = sheetView.View
+	}
+	if sheetView.State != "visible" {
+		sv.State = sheetView.State
+	}
+	if sv.Scale == 100 {
+		sv.Scale = 0
+	}
+	if !sheetView.ShowGridLines {
+		sv.ShowGridLines = boolPtr(false)
+	}
+	if sheetView.FilterRange != "" {
+		sv.Filter, sv.ShowAutoFilter = true, true
+		sv.AutoFilter = &xlsxAutoFilter{Ref: sheetView.FilterRange}
+		if ws.AutoFilter != nil && ws.AutoFilter.Ref == sheetView.FilterRange {
+			sv.AutoFilter.FilterColumn = ws.AutoFilter.FilterColumn
+		}
+	}
+	if opt.IncludePrintSettings {
+		sv.PageMargins, sv.PrintOptions, sv.PageSetup, sv.HeaderFooter = ws.PageMargins, ws.PrintOptions, ws.PageSetUp, ws.HeaderFooter
+		sv.RowBreaks, sv.ColBreaks = ws.RowBreaks, ws.ColBreaks
+	}
+	return sv
+}
+
+// DeleteCustomView provides a function to delete the custom view by given
+// view name, the settings of the worksheets saved with the view will be
+// removed together. For example, delete the custom view named "Summary":
+//
+//	err := f.DeleteCustomView("Summary")
+func (f *File) DeleteCustomView(name string) error {
+	wb := f.workbookReader()
+	if wb.CustomWorkbookViews == nil {
+		return ErrCustomViewNotExist
+	}
+	var worksheets []*xlsxWorksheet
+	for _, sheet := range f.GetSheets() {
+		if sheet.Type != "worksheet" {
+			continue
+		}
+		ws, err := f.workSheetReader(sheet.Name)
+		if err != nil {
+			return err
+		}
+		worksheets = append(worksheets, ws)
+	}
+	var guid string
+	views := wb.CustomWorkbookViews.CustomWorkbookView[:0]
+	for _, v := range wb.CustomWorkbookViews.CustomWorkbookView {
+		if guid == "" && strings.EqualFold(stringValue(v.Name), name) {
+			guid = stringValue(v.GUID)
+			continue
+		}
+		views = append(views, v)
+	}
+	if guid == "" {
+		return ErrCustomViewNotExist
+	}
+	wb.CustomWorkbookViews.CustomWorkbookView = views
+	if len(views) == 0 {
+		wb.CustomWorkbookViews = nil
+	}
+	for _, ws := range worksheets {
+		if ws.CustomSheetViews == nil {
+			continue
+		}
+		sheetViews := ws.CustomSheetViews.CustomSheetView[:0]
+		for _, sv := range ws.CustomSheetViews.CustomSheetView {
+			if !strings.EqualFold(sv.GUID, guid) {
+				sheetViews = append(sheetViews, sv)
+			}
+		}
+		ws.CustomSheetViews.CustomSheetView = sheetViews
+		if len(sheetViews) == 0 {
+			ws.CustomSheetViews = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomView(t *testing.T) {
+	f := NewFile()
+	views, err := f.GetCustomViews()
+	assert.NoError(t, err)
+	assert.Empty(t, views)
+	assert.EqualError(t, f.DeleteCustomView("View"), ErrCustomViewNotExist.Error())
+	assert.EqualError(t, f.AddCustomView(nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddCustomView(&CustomView{}), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddCustomView(&CustomView{Name: "View", Sheets: []CustomSheetView{{Sheet: "SheetN"}}}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.AddCustomView(&CustomView{Name: "View", ActiveSheet: "SheetN"}), "sheet SheetN is not exist")
+
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetRowVisible("Sheet1", 2, false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "B", false))
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1", "C10", `{"column":"A","expression":"x != blanks"}`))
+	assert.NoError(t, f.SetPageLayout("Sheet1", PageLayoutOrientation(OrientationLandscape)))
+	assert.NoError(t, f.AddCustomView(&CustomView{
+		Name:                 "Filtered",
+		IncludeHiddenRowCol:  true,
+		IncludePrintSettings: true,
+	}))
+	assert.NoError(t, f.AddCustomView(&CustomView{
+		Name:        "Overview",
+		GUID:        "{0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9}",
+		ActiveSheet: "Sheet2",
+		Sheets: []CustomSheetView{
+			{Sheet: "Sheet1", TopLeftCell: "C3", Scale: 80, View: "pageLayout", State: "visible"},
+			{Sheet: "Sheet2", Scale: 100, View: "normal", State: "hidden", ShowGridLines: true, FilterRange: "A1:B5"},
+		},
+	}))
+	file := filepath.Join("test", "TestCustomView.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	views, err = f.GetCustomViews()
+	assert.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.Equal(t, "Filtered", views[0].Name)
+	assert.Equal(t, "Sheet1", views[0].ActiveSheet)
+	assert.True(t, views[0].IncludeHiddenRowCol)
+	assert.True(t, views[0].IncludePrintSettings)
+	assert.Equal(t, []CustomSheetView{
+		{Sheet: "Sheet1", Scale: 100, View: "normal", State: "visible", ShowGridLines: true, HiddenRows: true, HiddenColumns: true, FilterRange: "$A$1:$C$10"},
+		{Sheet: "Sheet2", Scale: 100, View: "normal", State: "visible", ShowGridLines: true},
+	}, views[0].Sheets)
+	assert.Equal(t, CustomView{
+		Name:        "Overview",
+		GUID:        "{0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9}",
+		ActiveSheet: "Sheet2",
+		Sheets: []CustomSheetView{
+			{Sheet: "Sheet1", TopLeftCell: "C3", Scale: 80, View: "pageLayout", State: "visible"},
+			{Sheet: "Sheet2", Scale: 100, View: "normal", State: "hidden", ShowGridLines: true, FilterRange: "A1:B5"},
+		},
+	}, views[1])
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	// Test the filter criteria and the print settings are saved with the view.
+	sv := ws.CustomSheetViews.CustomSheetView[0]
+	assert.Len(t, sv.AutoFilter.FilterColumn, 1)
+	assert.Equal(t, "landscape", sv.PageSetup.Orientation)
+	assert.Nil(t, ws.CustomSheetViews.CustomSheetView[1].PageSetup)
+	assert.False(t, *ws.CustomSheetViews.CustomSheetView[1].ShowGridLines)
+
+	// Test replace the custom view with the same name.
+	assert.NoError(t, f.AddCustomView(&CustomView{Name: "overview"}))
+	views, err = f.GetCustomViews()
+	assert.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.Equal(t, "overview", views[1].Name)
+	assert.NotEqual(t, "{0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9}", views[1].GUID)
+
+	// Test delete the custom views.
+	assert.NoError(t, f.DeleteCustomView("Filtered"))
+	assert.EqualError(t, f.DeleteCustomView("Filtered"), ErrCustomViewNotExist.Error())
+	assert.NoError(t, f.DeleteCustomView("Overview"))
+	assert.Nil(t, f.WorkBook.CustomWorkbookViews)
+	assert.Nil(t, ws.CustomSheetViews)
+
+	// Test get and delete the custom views with invalid worksheet.
+	assert.NoError(t, f.AddCustomView(&CustomView{Name: "View"}))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = nil
+	_, err = f.GetCustomViews()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddCustomView(&CustomView{Name: "View"}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.DeleteCustomView("View"), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
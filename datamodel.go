@@ -0,0 +1,144 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GetDataModel provides a function to inspect the workbook data model (Power
+// Pivot) in read-only mode. The data model parts in the xl/model folder and
+// the connection parts will be preserved on saving the workbook, this
+// function returns the model tables and relationships declared in the
+// workbook, the measures referenced by the pivot caches, and the paths of the
+// data model related parts. Note that the DAX expression of the measure only
+// available when the measure is defined as a calculated member in the pivot
+// cache, the model itself is stored in binary format. For example:
+//
+//    model, err := f.GetDataModel()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, table := range model.Tables {
+//        fmt.Println(table.Name)
+//    }
+//    for _, measure := range model.Measures {
+//        fmt.Println(measure.Name, measure.Formula)
+//    }
+//
+func (f *File) GetDataModel() (*DataModel, error) {
+	model := &DataModel{}
+	wb := f.workbookReader()
+	if wb.ExtLst != nil {
+		decodeExtLst := new(decodeWorkbookExt)
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + wb.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return model, err
+		}
+		for _, ext := range decodeExtLst.Ext {
+			if ext.URI != ExtURIDataModel {
+				continue
+			}
+			dataModel := new(decodeX15DataModel)
+			if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(dataModel); err != nil && err != io.EOF {
+				return model, err
+			}
+			if dataModel.ModelTables != nil {
+				for _, t := range dataModel.ModelTables.ModelTable {
+					model.Tables = append(model.Tables, DataModelTable{ID: t.ID, Name: t.Name, Connection: t.Connection})
+				}
+			}
+			if dataModel.ModelRelationships != nil {
+				for _, r := range dataModel.ModelRelationships.ModelRelationship {
+					model.Relationships = append(model.Relationships, DataModelRelationship{
+						FromTable: r.FromTable, FromColumn: r.FromColumn, ToTable: r.ToTable, ToColumn: r.ToColumn,
+					})
+				}
+			}
+		}
+	}
+	var pivotCaches []string
+	f.Pkg.Range(func(k, v interface{}) bool {
+		path := k.(string)
+		if strings.HasPrefix(path, "xl/model/") || path == "xl/connections.xml" {
+			model.Parts = append(model.Parts, path)
+		}
+		if strings.HasPrefix(path, "xl/pivotCache/pivotCacheDefinition") {
+			pivotCaches = append(pivotCaches, path)
+		}
+		return true
+	})
+	sort.Strings(model.Parts)
+	sort.Strings(pivotCaches)
+	for _, path := range pivotCaches {
+		if err := f.getDataModelMeasures(model, path); err != nil {
+			return model, err
+		}
+	}
+	return model, nil
+}
+
+// getDataModelMeasures provides a function to get the data model measures
+// referenced by the pivot cache definition by given part path.
+func (f *File) getDataModelMeasures(model *DataModel, path string) error {
+	pc := new(decodePivotCacheMeasures)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(pc); err != nil && err != io.EOF {
+		return err
+	}
+	exists := func(name string) int {
+		for idx, m := range model.Measures {
+			if m.Name == name {
+				return idx
+			}
+		}
+		return -1
+	}
+	if pc.CacheHierarchies != nil {
+		for _, h := range pc.CacheHierarchies.CacheHierarchy {
+			if h.Measure && exists(h.UniqueName) == -1 {
+				model.Measures = append(model.Measures, DataModelMeasure{
+					Name: h.UniqueName, Caption: h.Caption, MeasureGroup: h.MeasureGroup,
+				})
+			}
+		}
+	}
+	if pc.CalculatedMembers != nil {
+		for _, c := range pc.CalculatedMembers.CalculatedMember {
+			if !strings.HasPrefix(c.Name, "[Measures].") {
+				continue
+			}
+			measure := DataModelMeasure{Name: c.Name, Formula: c.Mdx}
+			if c.ExtLst != nil {
+				for _, ext := range c.ExtLst.Ext {
+					if ext.URI == ExtURICalculatedMember && ext.CalculatedMember != nil {
+						measure.MeasureGroup = ext.CalculatedMember.MeasureGroup
+					}
+				}
+			}
+			if idx := exists(c.Name); idx != -1 {
+				model.Measures[idx].Formula = measure.Formula
+				if measure.MeasureGroup != "" {
+					model.Measures[idx].MeasureGroup = measure.MeasureGroup
+				}
+				continue
+			}
+			model.Measures = append(model.Measures, measure)
+		}
+	}
+	return nil
+}
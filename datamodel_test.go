@@ -0,0 +1,51 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDataModel(t *testing.T) {
+	f := NewFile()
+	model, err := f.GetDataModel()
+	assert.NoError(t, err)
+	assert.Equal(t, &DataModel{}, model)
+
+	wb := f.workbookReader()
+	wb.ExtLst = &xlsxExtLst{Ext: `<ext uri="` + ExtURIDataModel + `" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:dataModel><x15:modelTables><x15:modelTable id="Sales" name="Sales" connection="WorksheetConnection_Sales"/><x15:modelTable id="Products" name="Products" connection="WorksheetConnection_Products"/></x15:modelTables><x15:modelRelationships><x15:modelRelationship fromTable="Sales" fromColumn="ProductID" toTable="Products" toColumn="ID"/></x15:modelRelationships></x15:dataModel></ext>`}
+	f.Pkg.Store("xl/model/item.data", []byte{0x00, 0x01})
+	f.Pkg.Store("xl/connections.xml", []byte(`<connections xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><connection id="1" name="ThisWorkbookDataModel" type="5"/></connections>`))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cacheHierarchies count="2"><cacheHierarchy uniqueName="[Sales].[Amount]" caption="Amount"/><cacheHierarchy uniqueName="[Measures].[Total Sales]" caption="Total Sales" measure="1" measureGroup="Sales"/></cacheHierarchies><calculatedMembers count="2"><calculatedMember name="[Measures].[Total Sales]" mdx="SUM([Amount])"/><calculatedMember name="[Measures].[Average Price]" mdx="AVERAGE([Price])"><extLst><ext uri="`+ExtURICalculatedMember+`" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:calculatedMember measureGroup="Products" measure="1"/></ext></extLst></calculatedMember><calculatedMember name="[Sales].[Set]" mdx="{}"/></calculatedMembers></pivotCacheDefinition>`))
+	path := filepath.Join("test", "TestGetDataModel.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	model, err = f.GetDataModel()
+	assert.NoError(t, err)
+	assert.Equal(t, []DataModelTable{
+		{ID: "Sales", Name: "Sales", Connection: "WorksheetConnection_Sales"},
+		{ID: "Products", Name: "Products", Connection: "WorksheetConnection_Products"},
+	}, model.Tables)
+	assert.Equal(t, []DataModelRelationship{{FromTable: "Sales", FromColumn: "ProductID", ToTable: "Products", ToColumn: "ID"}}, model.Relationships)
+	assert.Equal(t, []DataModelMeasure{
+		{Name: "[Measures].[Total Sales]", Caption: "Total Sales", MeasureGroup: "Sales", Formula: "SUM([Amount])"},
+		{Name: "[Measures].[Average Price]", MeasureGroup: "Products", Formula: "AVERAGE([Price])"},
+	}, model.Measures)
+	assert.Equal(t, []string{"xl/connections.xml", "xl/model/item.data"}, model.Parts)
+
+	// Test get data model with invalid workbook extension
+	f.WorkBook.ExtLst.Ext = `<ext uri="` + ExtURIDataModel + `"><dataModel><modelTables><modelTable id="Sales" name="Sales"></ext>`
+	_, err = f.GetDataModel()
+	assert.Error(t, err)
+	f.WorkBook.ExtLst.Ext = `<ext uri="`
+	_, err = f.GetDataModel()
+	assert.Error(t, err)
+	// Test get data model with invalid pivot cache definition
+	f.WorkBook.ExtLst = nil
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetDataModel()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
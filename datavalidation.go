@@ -12,8 +12,14 @@
 package excelize
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf16"
 )
 
 // DataValidationType defined the type of data validation.
@@ -31,6 +37,9 @@ const (
 	DataValidationTypeTime
 	// DataValidationTypeWhole Integer
 	DataValidationTypeWhole
+	// DataValidationTypeTextLength defined the type of data validation on
+	// the length of the text.
+	DataValidationTypeTextLength = DataValidationTypeTextLeng
 )
 
 const (
@@ -38,6 +47,15 @@ const (
 	dataValidationFormulaStrLen = 257
 	// dataValidationFormulaStrLenErr
 	dataValidationFormulaStrLenErr = "data validation must be 0-255 characters"
+	// dataValidationTitleLen defined the maximum length of the title of the
+	// input and error messages.
+	dataValidationTitleLen = 32
+	// dataValidationMessageLen defined the maximum length of the input and
+	// error messages.
+	dataValidationMessageLen = 255
+	// dataValidationListSheet defined the name of the hidden worksheet which
+	// is used to store the list source of the data validation.
+	dataValidationListSheet = "DataValidationLists"
 )
 
 // DataValidationErrorStyle defined the style of data validation error alert.
@@ -74,6 +92,26 @@ const (
 	DataValidationOperatorNotEqual
 )
 
+// DataValidationImeMode defined the IME (input method editor) mode of the
+// cells with data validation, which is used for the East Asian languages.
+type DataValidationImeMode int
+
+// Data validation IME modes.
+const (
+	_ DataValidationImeMode = iota
+	DataValidationImeModeNoControl
+	DataValidationImeModeOff
+	DataValidationImeModeOn
+	DataValidationImeModeDisabled
+	DataValidationImeModeHiragana
+	DataValidationImeModeFullKatakana
+	DataValidationImeModeHalfKatakana
+	DataValidationImeModeFullAlpha
+	DataValidationImeModeHalfAlpha
+	DataValidationImeModeFullHangul
+	DataValidationImeModeHalfHangul
+)
+
 // NewDataValidation return data validation struct.
 func NewDataValidation(allowBlank bool) *DataValidation {
 	return &DataValidation{
@@ -108,30 +146,101 @@ func (dd *DataValidation) SetInput(title, msg string) {
 	dd.Prompt = &msg
 }
 
-// SetDropList data validation list.
+// SetDropList data validation list. The length of the list source including
+// the separators must be 0-255 characters, use the SetDataValidationDropList
+// function to set the list source which exceeds the length limitation.
 func (dd *DataValidation) SetDropList(keys []string) error {
-	formula := "\"" + strings.Join(keys, ",") + "\""
-	if dataValidationFormulaStrLen < len(formula) {
-		return fmt.Errorf(dataValidationFormulaStrLenErr)
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = strings.Replace(key, "\"", "\"\"", -1)
 	}
-	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", formula)
+	formula := "\"" + strings.Join(items, ",") + "\""
+	if dataValidationFormulaStrLen < len(utf16.Encode([]rune(formula))) {
+		return ErrDataValidationFormulaLength
+	}
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(formula))
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", buf.String())
 	dd.Type = convDataValidationType(typeList)
 	return nil
 }
 
-// SetRange provides function to set data validation range in drop list.
+// SetRange provides function to set data validation range in drop list. The
+// second value will be ignored for the operators except between and
+// notBetween. For example, limit the length of the text in the cells
+// shorter than 10 characters:
+//
+//     dvRange.SetRange(10, 0, excelize.DataValidationTypeTextLength, excelize.DataValidationOperatorLessThan)
+//
 func (dd *DataValidation) SetRange(f1, f2 float64, t DataValidationType, o DataValidationOperator) error {
-	formula1 := fmt.Sprintf("%f", f1)
-	formula2 := fmt.Sprintf("%f", f2)
 	if dataValidationFormulaStrLen+21 < len(dd.Formula1) || dataValidationFormulaStrLen+21 < len(dd.Formula2) {
-		return fmt.Errorf(dataValidationFormulaStrLenErr)
+		return ErrDataValidationFormulaLength
 	}
+	dd.setFormulas(strconv.FormatFloat(f1, 'f', -1, 64), strconv.FormatFloat(f2, 'f', -1, 64), t, o)
+	return nil
+}
+
+// SetTimeRange provides function to set data validation on the time of day
+// in the cells by given start and end time, the date part of the time will
+// be ignored, and the end time will be ignored for the operators except
+// between and notBetween. For example, allow the time between 9:00 and
+// 17:30:
+//
+//     dvRange.SetTimeRange(
+//         time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+//         time.Date(0, 1, 1, 17, 30, 0, 0, time.UTC),
+//         excelize.DataValidationOperatorBetween)
+//
+func (dd *DataValidation) SetTimeRange(t1, t2 time.Time, o DataValidationOperator) {
+	timeOfDay := func(t time.Time) string {
+		seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+		return strconv.FormatFloat(float64(seconds)/86400, 'f', -1, 64)
+	}
+	dd.setFormulas(timeOfDay(t1), timeOfDay(t2), DataValidationTypeTime, o)
+}
 
+// setFormulas provides function to set the formulas, type and operator of
+// the data validation, the second formula will only be set for the between
+// and notBetween operators.
+func (dd *DataValidation) setFormulas(formula1, formula2 string, t DataValidationType, o DataValidationOperator) {
 	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", formula1)
-	dd.Formula2 = fmt.Sprintf("<formula2>%s</formula2>", formula2)
+	dd.Formula2 = ""
+	if o == DataValidationOperatorBetween || o == DataValidationOperatorNotBetween {
+		dd.Formula2 = fmt.Sprintf("<formula2>%s</formula2>", formula2)
+	}
 	dd.Type = convDataValidationType(t)
 	dd.Operator = convDataValidationOperatior(o)
-	return nil
+}
+
+// SetImeMode provides function to set the IME (input method editor) mode of
+// the cells with data validation. For example, turn on the IME to input the
+// full-width Katakana characters:
+//
+//     dvRange.SetImeMode(excelize.DataValidationImeModeFullKatakana)
+//
+func (dd *DataValidation) SetImeMode(mode DataValidationImeMode) {
+	dd.ImeMode = map[DataValidationImeMode]string{
+		DataValidationImeModeNoControl:    "noControl",
+		DataValidationImeModeOff:          "off",
+		DataValidationImeModeOn:           "on",
+		DataValidationImeModeDisabled:     "disabled",
+		DataValidationImeModeHiragana:     "hiragana",
+		DataValidationImeModeFullKatakana: "fullKatakana",
+		DataValidationImeModeHalfKatakana: "halfKatakana",
+		DataValidationImeModeFullAlpha:    "fullAlpha",
+		DataValidationImeModeHalfAlpha:    "halfAlpha",
+		DataValidationImeModeFullHangul:   "fullHangul",
+		DataValidationImeModeHalfHangul:   "halfHangul",
+	}[mode]
+}
+
+// SetShowDropDown provides function to set if the in-cell dropdown of the
+// list data validation will be displayed. The in-cell dropdown is displayed
+// by default. Note that the showDropDown attribute in the spreadsheet
+// specifies to suppress the in-cell dropdown on true, this function sets the
+// ShowDropDown field with the inverted value.
+func (dd *DataValidation) SetShowDropDown(show bool) {
+	dd.ShowDropDown = !show
 }
 
 // SetSqrefDropList provides set data validation on a range with source
@@ -229,11 +338,27 @@ func convDataValidationOperatior(o DataValidationOperator) string {
 //     dvRange.SetDropList([]string{"1", "2", "3"})
 //     err = f.AddDataValidation("Sheet1", dvRange)
 //
+// Example 4, set data validation on the data rows of the Region column in
+// the Sales table by the structured reference, the range will be expanded or
+// shrunk with the table when resizing the table by the SetTableRange
+// function:
+//
+//     dvRange = excelize.NewDataValidation(true)
+//     dvRange.Sqref = "Sales[Region]"
+//     dvRange.SetDropList([]string{"East", "West"})
+//     err = f.AddDataValidation("Sheet1", dvRange)
+//
 func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	if err = checkDataValidationMessages(dv); err != nil {
+		return err
+	}
+	if dv.Sqref, err = f.resolveStructRefs(sheet, dv.Sqref); err != nil {
+		return err
+	}
 	if nil == ws.DataValidations {
 		ws.DataValidations = new(xlsxDataValidations)
 	}
@@ -242,6 +367,269 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	return err
 }
 
+// checkDataValidationMessages provides a function to check the length of the
+// titles and messages of the input and error alert of the data validation.
+// The lengths are counted by the characters, so the localized messages could
+// be used in the range of the length limitation.
+func checkDataValidationMessages(dv *DataValidation) error {
+	for _, title := range []*string{dv.ErrorTitle, dv.PromptTitle} {
+		if title != nil && len(utf16.Encode([]rune(*title))) > dataValidationTitleLen {
+			return ErrDataValidationTitleLength
+		}
+	}
+	for _, msg := range []*string{dv.Error, dv.Prompt} {
+		if msg != nil && len(utf16.Encode([]rune(*msg))) > dataValidationMessageLen {
+			return ErrDataValidationMessageLength
+		}
+	}
+	return nil
+}
+
+// SetDataValidationDropList provides a function to set the list source of
+// the data validation by given data validation object and list items. The
+// list items will be stored in the hidden worksheet named
+// DataValidationLists when the length of the list source exceeds 255
+// characters, and the data validation will reference to the cells in this
+// worksheet. Note that the worksheet which stores the list source should not
+// be deleted. For example, set the in-cell dropdown with long list source on
+// Sheet1!A1:A10:
+//
+//     dvRange := excelize.NewDataValidation(true)
+//     dvRange.Sqref = "A1:A10"
+//     if err := f.SetDataValidationDropList(dvRange, countries); err != nil {
+//         fmt.Println(err)
+//     }
+//     err := f.AddDataValidation("Sheet1", dvRange)
+//
+func (f *File) SetDataValidationDropList(dv *DataValidation, keys []string) error {
+	err := dv.SetDropList(keys)
+	if err != ErrDataValidationFormulaLength {
+		return err
+	}
+	if f.GetSheetIndex(dataValidationListSheet) == -1 {
+		f.NewSheet(dataValidationListSheet)
+		if err = f.SetSheetVisible(dataValidationListSheet, false); err != nil {
+			return err
+		}
+	}
+	cols, err := f.GetCols(dataValidationListSheet)
+	if err != nil {
+		return err
+	}
+	col := len(cols) + 1
+	for idx, key := range keys {
+		cell, _ := CoordinatesToCellName(col, idx+1)
+		if err = f.SetCellStr(dataValidationListSheet, cell, key); err != nil {
+			return err
+		}
+	}
+	colName, _ := ColumnNumberToName(col)
+	dv.Formula1 = fmt.Sprintf("<formula1>'%s'!$%s$1:$%s$%d</formula1>", dataValidationListSheet, colName, colName, len(keys))
+	dv.Type = convDataValidationType(typeList)
+	return err
+}
+
+// AddChoiceColumn provides a function to set a range of cells as the choice
+// column by given worksheet name, range reference and the settings of the
+// choices. The cells in the range will get an in-cell dropdown of the choice
+// values, and will be highlighted by the fill and font colors of the
+// selected choice with the conditional formatting. The empty cells will be
+// filled with the default choice if it's specified. The Yes and No choices
+// with green and red colors will be used if the choices are not specified.
+// For example, set the cells Sheet1!E2:E100 as the Yes/No column, and fill
+// the empty cells with No:
+//
+//    err := f.AddChoiceColumn("Sheet1", "E2:E100", &excelize.ChoiceColumnOptions{
+//        Default:      "No",
+//        ErrorTitle:   "Invalid choice",
+//        ErrorMessage: "Please choose Yes or No",
+//    })
+//
+// Set the cells Sheet1!F2:F100 as the status column with custom choices:
+//
+//    err := f.AddChoiceColumn("Sheet1", "F2:F100", &excelize.ChoiceColumnOptions{
+//        Choices: []excelize.Choice{
+//            {Value: "Done", FillColor: "#C6EFCE", FontColor: "#006100"},
+//            {Value: "In Progress", FillColor: "#FFEB9C", FontColor: "#9C5700"},
+//            {Value: "Blocked", FillColor: "#FFC7CE", FontColor: "#9C0006"},
+//        },
+//        AllowBlank: true,
+//    })
+//
+func (f *File) AddChoiceColumn(sheet, area string, opts *ChoiceColumnOptions) error {
+	if opts == nil {
+		opts = &ChoiceColumnOptions{}
+	}
+	choices := opts.Choices
+	if len(choices) == 0 {
+		choices = []Choice{
+			{Value: "Yes", FillColor: "#C6EFCE", FontColor: "#006100"},
+			{Value: "No", FillColor: "#FFC7CE", FontColor: "#9C0006"},
+		}
+	}
+	coordinates, err := f.areaRefToCoordinates(area)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ref, _ := f.coordinatesToAreaRef(coordinates)
+	values := make([]string, len(choices))
+	for i, choice := range choices {
+		values[i] = choice.Value
+		for _, color := range []string{choice.FillColor, choice.FontColor} {
+			if color == "" {
+				continue
+			}
+			if _, err = ParseHexColor(color); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Default != "" && inStrSlice(values, opts.Default) == -1 {
+		return ErrParameterInvalid
+	}
+	dv := NewDataValidation(opts.AllowBlank)
+	dv.Sqref = ref
+	if err = f.SetDataValidationDropList(dv, values); err != nil {
+		return err
+	}
+	if opts.ErrorTitle != "" || opts.ErrorMessage != "" {
+		dv.SetError(DataValidationErrorStyleStop, opts.ErrorTitle, opts.ErrorMessage)
+	}
+	if err = f.AddDataValidation(sheet, dv); err != nil {
+		return err
+	}
+	var formats []ConditionalFormatOptions
+	for _, choice := range choices {
+		if choice.FillColor == "" && choice.FontColor == "" {
+			continue
+		}
+		style := Style{}
+		if choice.FillColor != "" {
+			style.Fill = Fill{Type: "pattern", Color: []string{choice.FillColor}, Pattern: 1}
+		}
+		if choice.FontColor != "" {
+			style.Font = &Font{Color: choice.FontColor}
+		}
+		styleSet, _ := json.Marshal(style)
+		format, err := f.NewConditionalStyle(string(styleSet))
+		if err != nil {
+			return err
+		}
+		formats = append(formats, ConditionalFormatOptions{
+			Type:     "cell",
+			Criteria: "==",
+			Format:   format,
+			Value:    "\"" + strings.Replace(choice.Value, "\"", "\"\"", -1) + "\"",
+		})
+	}
+	if len(formats) > 0 {
+		if err = f.SetConditionalFormatOptions(sheet, ref, formats); err != nil {
+			return err
+		}
+	}
+	if opts.Default == "" {
+		return err
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			val, err := f.GetCellValue(sheet, cell)
+			if err != nil {
+				return err
+			}
+			if val != "" {
+				continue
+			}
+			if err = f.SetCellStr(sheet, cell, opts.Default); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// GetDataValidations provides a function to get the data validations of the
+// worksheet by given worksheet name. The returned data validations are copies
+// of the existing ones, each of them contains the range, type, operator,
+// formulas, input and error messages of the validation. For example, get the
+// data validations of Sheet1:
+//
+//    dvs, err := f.GetDataValidations("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, dv := range dvs {
+//        fmt.Println(dv.Sqref, dv.Type, dv.Operator, dv.Formula1, dv.Formula2)
+//    }
+//
+func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
+	var dvs []*DataValidation
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.DataValidations == nil {
+		return dvs, err
+	}
+	for _, dv := range ws.DataValidations.DataValidation {
+		if dv == nil {
+			continue
+		}
+		item := *dv
+		if item.Formula2 == "" {
+			if idx := strings.Index(item.Formula1, "<formula2"); idx != -1 {
+				item.Formula1, item.Formula2 = item.Formula1[:idx], item.Formula1[idx:]
+			}
+		}
+		dvs = append(dvs, &item)
+	}
+	return dvs, err
+}
+
+// SetDataValidation provides a function to modify the data validation in
+// place by given worksheet name, the reference sequence of the existing data
+// validation and the new settings of the data validation. The order of the
+// data validations in the worksheet will be kept, and the
+// ErrDataValidationNotExist will be returned if the data validation doesn't
+// exist. For example, change the input message of the data validation on
+// Sheet1!A1:B2:
+//
+//    dvs, err := f.GetDataValidations("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, dv := range dvs {
+//        if dv.Sqref == "A1:B2" {
+//            dv.SetInput("Input", "Enter a number between 10 and 20")
+//            err = f.SetDataValidation("Sheet1", "A1:B2", dv)
+//        }
+//    }
+//
+func (f *File) SetDataValidation(sheet, sqref string, dv *DataValidation) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if err = checkDataValidationMessages(dv); err != nil {
+		return err
+	}
+	if dv.Sqref == "" {
+		dv.Sqref = sqref
+	}
+	if dv.Sqref, err = f.resolveStructRefs(sheet, dv.Sqref); err != nil {
+		return err
+	}
+	if ws.DataValidations != nil {
+		for i, item := range ws.DataValidations.DataValidation {
+			if item != nil && item.Sqref == sqref {
+				ws.DataValidations.DataValidation[i] = dv
+				return err
+			}
+		}
+	}
+	return ErrDataValidationNotExist
+}
+
 // DeleteDataValidation delete data validation by given worksheet name and
 // reference sequence.
 func (f *File) DeleteDataValidation(sheet, sqref string) error {
@@ -10,9 +10,11 @@
 package excelize
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -86,6 +88,78 @@ func TestDataValidationError(t *testing.T) {
 	assert.EqualError(t, f.AddDataValidation("SheetN", nil), "sheet SheetN is not exist")
 }
 
+func TestDataValidationSettings(t *testing.T) {
+	f := NewFile()
+	// Test set data validation with the single operand operators.
+	for _, o := range []DataValidationOperator{DataValidationOperatorEqual, DataValidationOperatorNotEqual, DataValidationOperatorGreaterThan, DataValidationOperatorGreaterThanOrEqual, DataValidationOperatorLessThan, DataValidationOperatorLessThanOrEqual} {
+		dvRange := NewDataValidation(true)
+		assert.NoError(t, dvRange.SetRange(10.5, 20, DataValidationTypeDecimal, o))
+		assert.Equal(t, "<formula1>10.5</formula1>", dvRange.Formula1)
+		assert.Empty(t, dvRange.Formula2)
+	}
+	for _, o := range []DataValidationOperator{DataValidationOperatorBetween, DataValidationOperatorNotBetween} {
+		dvRange := NewDataValidation(true)
+		assert.NoError(t, dvRange.SetRange(1, 20, DataValidationTypeTextLength, o))
+		assert.Equal(t, "textLength", dvRange.Type)
+		assert.Equal(t, "<formula1>1</formula1>", dvRange.Formula1)
+		assert.Equal(t, "<formula2>20</formula2>", dvRange.Formula2)
+	}
+	// Test set data validation on time.
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:A10"
+	dvRange.SetTimeRange(time.Date(2021, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC), DataValidationOperatorBetween)
+	assert.Equal(t, []string{"time", "between", "<formula1>0.375</formula1>", "<formula2>0.75</formula2>"}, []string{dvRange.Type, dvRange.Operator, dvRange.Formula1, dvRange.Formula2})
+	dvRange.SetImeMode(DataValidationImeModeFullKatakana)
+	assert.Equal(t, "fullKatakana", dvRange.ImeMode)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	// Test set the visibility of the in-cell dropdown.
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "B1:B10"
+	assert.NoError(t, dvRange.SetDropList([]string{`"Yes" & Oui`, "<No>", "否"}))
+	assert.Equal(t, `<formula1>&#34;&#34;&#34;Yes&#34;&#34; &amp; Oui,&lt;No&gt;,否&#34;</formula1>`, dvRange.Formula1)
+	dvRange.SetShowDropDown(false)
+	assert.True(t, dvRange.ShowDropDown)
+	dvRange.SetShowDropDown(true)
+	assert.False(t, dvRange.ShowDropDown)
+	// Test set the localized input and error messages.
+	dvRange.SetInput("入力", strings.Repeat("選", 255))
+	dvRange.SetError(DataValidationErrorStyleStop, strings.Repeat("エ", 32), "エラー")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	// Test set the list source with localized items.
+	dvRange = NewDataValidation(true)
+	assert.NoError(t, dvRange.SetDropList(strings.Split(strings.Repeat("选项,", 84)+"选项", ",")))
+	assert.EqualError(t, dvRange.SetDropList(strings.Split(strings.Repeat("选项,", 86), ",")), ErrDataValidationFormulaLength.Error())
+	// Test add data validation with too long titles and messages.
+	dvRange.SetInput(strings.Repeat("入", 33), "")
+	assert.EqualError(t, f.AddDataValidation("Sheet1", dvRange), ErrDataValidationTitleLength.Error())
+	dvRange.SetInput("", strings.Repeat("入", 256))
+	assert.EqualError(t, f.AddDataValidation("Sheet1", dvRange), ErrDataValidationMessageLength.Error())
+
+	// Test set the list source which exceeds the length limitation.
+	var keys []string
+	for i := 1; i <= 100; i++ {
+		keys = append(keys, fmt.Sprintf("Item %d", i))
+	}
+	for idx, sqref := range []string{"C1:C10", "D1:D10"} {
+		dvRange = NewDataValidation(true)
+		dvRange.Sqref = sqref
+		assert.NoError(t, f.SetDataValidationDropList(dvRange, keys))
+		colName, _ := ColumnNumberToName(idx + 1)
+		assert.Equal(t, fmt.Sprintf("<formula1>'DataValidationLists'!$%s$1:$%s$100</formula1>", colName, colName), dvRange.Formula1)
+		assert.Equal(t, "list", dvRange.Type)
+		assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	}
+	assert.False(t, f.GetSheetVisible("DataValidationLists"))
+	val, err := f.GetCellValue("DataValidationLists", "B100")
+	assert.NoError(t, err)
+	assert.Equal(t, "Item 100", val)
+	// Test set the short list source.
+	dvRange = NewDataValidation(true)
+	assert.NoError(t, f.SetDataValidationDropList(dvRange, []string{"1", "2"}))
+	assert.Equal(t, `<formula1>&#34;1,2&#34;</formula1>`, dvRange.Formula1)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDataValidationSettings.xlsx")))
+}
+
 func TestDeleteDataValidation(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
@@ -102,3 +176,116 @@ func TestDeleteDataValidation(t *testing.T) {
 	// Test delete data validation on no exists worksheet.
 	assert.EqualError(t, f.DeleteDataValidation("SheetN", "A1:B2"), "sheet SheetN is not exist")
 }
+
+func TestGetDataValidations(t *testing.T) {
+	f := NewFile()
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, dvs)
+
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:B2"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	dvRange.SetInput("input title", "input body")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	dvList := NewDataValidation(true)
+	dvList.Sqref = "C1:C10"
+	assert.NoError(t, dvList.SetDropList([]string{"Yes", "No"}))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvList))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetDataValidations.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestGetDataValidations.xlsx"))
+	assert.NoError(t, err)
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "A1:B2", dvs[0].Sqref)
+	assert.Equal(t, "whole", dvs[0].Type)
+	assert.Equal(t, "between", dvs[0].Operator)
+	assert.Equal(t, "<formula1>10</formula1>", dvs[0].Formula1)
+	assert.Equal(t, "<formula2>20</formula2>", dvs[0].Formula2)
+	assert.Equal(t, "input title", *dvs[0].PromptTitle)
+	assert.Equal(t, "input body", *dvs[0].Prompt)
+	assert.Equal(t, "C1:C10", dvs[1].Sqref)
+	assert.Equal(t, "list", dvs[1].Type)
+	assert.Equal(t, `<formula1>&#34;Yes,No&#34;</formula1>`, dvs[1].Formula1)
+	assert.Empty(t, dvs[1].Formula2)
+
+	// Test modify data validation in place.
+	dvs[0].SetError(DataValidationErrorStyleStop, "error title", "error body")
+	assert.NoError(t, dvs[0].SetRange(1, 5, DataValidationTypeDecimal, DataValidationOperatorBetween))
+	assert.NoError(t, f.SetDataValidation("Sheet1", "A1:B2", dvs[0]))
+	dvs[1].Sqref = ""
+	assert.NoError(t, dvs[1].SetDropList([]string{"A", "B", "C"}))
+	assert.NoError(t, f.SetDataValidation("Sheet1", "C1:C10", dvs[1]))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "decimal", dvs[0].Type)
+	assert.Equal(t, "<formula1>1</formula1>", dvs[0].Formula1)
+	assert.Equal(t, "<formula2>5</formula2>", dvs[0].Formula2)
+	assert.Equal(t, "error body", *dvs[0].Error)
+	assert.Equal(t, "C1:C10", dvs[1].Sqref)
+	assert.Equal(t, `<formula1>&#34;A,B,C&#34;</formula1>`, dvs[1].Formula1)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetDataValidation.xlsx")))
+
+	// Test modify the data validation which does not exist.
+	assert.EqualError(t, f.SetDataValidation("Sheet1", "D1:D2", dvs[0]), ErrDataValidationNotExist.Error())
+	assert.EqualError(t, NewFile().SetDataValidation("Sheet1", "D1:D2", dvs[0]), ErrDataValidationNotExist.Error())
+	// Test modify data validation with invalid messages.
+	dvs[0].SetInput(strings.Repeat("c", 33), "")
+	assert.EqualError(t, f.SetDataValidation("Sheet1", "A1:B2", dvs[0]), ErrDataValidationTitleLength.Error())
+	// Test modify data validation with invalid structured reference.
+	dvs[1].Sqref = "Table1[Column]"
+	assert.EqualError(t, f.SetDataValidation("Sheet1", "C1:C10", dvs[1]), ErrTableNotExist.Error())
+	// Test get and modify data validation on not exists worksheet.
+	_, err = f.GetDataValidations("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetDataValidation("SheetN", "A1:B2", dvs[0]), "sheet SheetN is not exist")
+}
+
+func TestAddChoiceColumn(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A3", "Yes"))
+	assert.NoError(t, f.AddChoiceColumn("Sheet1", "A5:A2", &ChoiceColumnOptions{
+		Default:      "No",
+		ErrorTitle:   "Invalid choice",
+		ErrorMessage: "Please choose Yes or No",
+	}))
+	for cell, expected := range map[string]string{"A2": "No", "A3": "Yes", "A4": "No", "A5": "No", "A6": ""} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	assert.NoError(t, f.AddChoiceColumn("Sheet1", "B2:B5", &ChoiceColumnOptions{
+		Choices: []Choice{
+			{Value: `Done "OK"`, FillColor: "#C6EFCE", FontColor: "#006100"},
+			{Value: "In Progress", FillColor: "FFEB9C"},
+			{Value: "Blocked", FontColor: "#9C0006"},
+			{Value: "Unknown"},
+		},
+		AllowBlank: true,
+	}))
+	assert.NoError(t, f.AddChoiceColumn("Sheet1", "C2:C5", nil))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.DataValidations.DataValidation, 3)
+	dv := ws.DataValidations.DataValidation[0]
+	assert.Equal(t, []string{"A2:A5", "list", `<formula1>&#34;Yes,No&#34;</formula1>`, "Invalid choice"}, []string{dv.Sqref, dv.Type, dv.Formula1, *dv.ErrorTitle})
+	assert.True(t, ws.DataValidations.DataValidation[1].AllowBlank)
+	assert.Len(t, ws.ConditionalFormatting, 3)
+	assert.Len(t, ws.ConditionalFormatting[1].CfRule, 3)
+	assert.Equal(t, []string{`"Done ""OK"""`}, ws.ConditionalFormatting[1].CfRule[0].Formula)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChoiceColumn.xlsx")))
+
+	// Test add choice column with invalid range reference.
+	assert.EqualError(t, f.AddChoiceColumn("Sheet1", "A", nil), ErrParameterInvalid.Error())
+	// Test add choice column with invalid color.
+	assert.EqualError(t, f.AddChoiceColumn("Sheet1", "D1:D2", &ChoiceColumnOptions{Choices: []Choice{{Value: "Yes", FillColor: "#GGGGGG"}}}), ErrHexColor.Error())
+	// Test add choice column with the default value which is not a choice.
+	assert.EqualError(t, f.AddChoiceColumn("Sheet1", "D1:D2", &ChoiceColumnOptions{Default: "Maybe"}), ErrParameterInvalid.Error())
+	// Test add choice column with too long error title.
+	assert.EqualError(t, f.AddChoiceColumn("Sheet1", "D1:D2", &ChoiceColumnOptions{ErrorTitle: strings.Repeat("e", 33)}), ErrDataValidationTitleLength.Error())
+	// Test add choice column on not exists worksheet.
+	assert.EqualError(t, f.AddChoiceColumn("SheetN", "D1:D2", nil), "sheet SheetN is not exist")
+}
@@ -17,9 +17,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // prepareDrawing provides a function to prepare drawing ID and XML by given
@@ -63,65 +67,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 		Lang:           &attrValString{Val: stringPtr("en-US")},
 		RoundedCorners: &attrValBool{Val: boolPtr(false)},
 		Chart: cChart{
-			Title: &cTitle{
-				Tx: cTx{
-					Rich: &cRich{
-						P: aP{
-							PPr: &aPPr{
-								DefRPr: aRPr{
-									Kern:   1200,
-									Strike: "noStrike",
-									U:      "none",
-									Sz:     1400,
-									SolidFill: &aSolidFill{
-										SchemeClr: &aSchemeClr{
-											Val: "tx1",
-											LumMod: &attrValInt{
-												Val: intPtr(65000),
-											},
-											LumOff: &attrValInt{
-												Val: intPtr(35000),
-											},
-										},
-									},
-									Ea: &aEa{
-										Typeface: "+mn-ea",
-									},
-									Cs: &aCs{
-										Typeface: "+mn-cs",
-									},
-									Latin: &aLatin{
-										Typeface: "+mn-lt",
-									},
-								},
-							},
-							R: &aR{
-								RPr: aRPr{
-									Lang:    "en-US",
-									AltLang: "en-US",
-								},
-								T: formatSet.Title.Name,
-							},
-						},
-					},
-				},
-				TxPr: cTxPr{
-					P: aP{
-						PPr: &aPPr{
-							DefRPr: aRPr{
-								Kern:   1200,
-								U:      "none",
-								Sz:     14000,
-								Strike: "noStrike",
-							},
-						},
-						EndParaRPr: &aEndParaRPr{
-							Lang: "en-US",
-						},
-					},
-				},
-				Overlay: &attrValBool{Val: boolPtr(false)},
-			},
+			Title: f.drawChartTitle(formatSet),
 			View3D: &cView3D{
 				RotX:        &attrValInt{Val: intPtr(chartView3DRotX[formatSet.Type])},
 				RotY:        &attrValInt{Val: intPtr(chartView3DRotY[formatSet.Type])},
@@ -179,7 +125,260 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 			},
 		},
 	}
-	plotAreaFunc := map[string]func(*formatChart) *cPlotArea{
+	xlsxChartSpace.PivotSource = formatSet.pivotSource
+	plotAreaFunc := f.plotAreaFuncs()
+	if formatSet.Title.None {
+		xlsxChartSpace.Chart.AutoTitleDeleted = &cAutoTitleDeleted{Val: true}
+	}
+	if formatSet.Chartarea.Fill.Color != "" {
+		xlsxChartSpace.SpPr.SolidFill = drawChartSolidFill(formatSet.Chartarea.Fill.Color, "")
+	}
+	if formatSet.Chartarea.Border.None {
+		xlsxChartSpace.SpPr.Ln = &aLn{NoFill: " "}
+	}
+	f.drawChartLegend(xlsxChartSpace.Chart.Legend, formatSet)
+	if formatSet.Legend.None {
+		xlsxChartSpace.Chart.Legend = nil
+	}
+	addChart := func(c, p *cPlotArea) {
+		immutable, mutable := reflect.ValueOf(c).Elem(), reflect.ValueOf(p).Elem()
+		for i := 0; i < mutable.NumField(); i++ {
+			field := mutable.Field(i)
+			if field.IsNil() {
+				continue
+			}
+			immutable.FieldByName(mutable.Type().Field(i).Name).Set(field)
+		}
+	}
+	charts, secondaryCharts := splitChartSecondarySeries(append([]*formatChart{formatSet}, comboCharts...))
+	var order int
+	for _, chart := range append(charts, secondaryCharts...) {
+		chart.order = order
+		order += len(chart.Series)
+	}
+	for _, chart := range charts {
+		addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[chart.Type](chart))
+	}
+	f.drawChartSecondaryGroups(xlsxChartSpace.Chart.PlotArea, formatSet, secondaryCharts)
+	xlsxChartSpace.Chart.PlotArea.SpPr = f.drawChartPlotAreaSpPr(formatSet)
+	if formatSet.XAxis.DateAxis && xlsxChartSpace.Chart.PlotArea.CatAx != nil {
+		xlsxChartSpace.Chart.PlotArea.DateAx, xlsxChartSpace.Chart.PlotArea.CatAx = xlsxChartSpace.Chart.PlotArea.CatAx, nil
+	}
+	chart, _ := xml.Marshal(xlsxChartSpace)
+	if formatSet.template != nil {
+		chart = applyChartTemplate(chart, formatSet.template)
+	}
+	media := "xl/charts/chart" + strconv.Itoa(count+1) + ".xml"
+	f.saveFileList(media, chart)
+}
+
+// addChartEx provides a function to create the extended chart as
+// xl/charts/chartEx%d.xml by given chart index and format sets.
+func (f *File) addChartEx(chartID int, formatSet *formatChart) {
+	chartSpace := xlsxChartExSpace{
+		XMLNSa: NameSpaceDrawingML.Value,
+		XMLNSr: SourceRelationship.Value,
+	}
+	if !formatSet.Title.None {
+		chartSpace.Chart.Title = &cxTitle{
+			Pos: "t", Align: "ctr", Overlay: formatSet.Title.Overlay,
+			Tx: &cxText{TxData: &cxTextData{V: formatSet.Title.Name}},
+		}
+	}
+	if !formatSet.Legend.None {
+		chartSpace.Chart.Legend = &cxLegend{
+			Pos: chartExLegendPosition[formatSet.Legend.Position], Align: "ctr", Overlay: formatSet.Legend.Overlay,
+		}
+		if formatSet.Legend.Position == "top_right" {
+			chartSpace.Chart.Legend.Align = "min"
+		}
+	}
+	for idx, ser := range formatSet.Series {
+		chartSpace.ChartData.Data = append(chartSpace.ChartData.Data, drawChartExData(idx, formatSet.Type, ser))
+		chartSpace.Chart.PlotArea.PlotAreaRegion.Series = append(chartSpace.Chart.PlotArea.PlotAreaRegion.Series,
+			drawChartExSeries(idx, formatSet, ser)...)
+	}
+	chartSpace.Chart.PlotArea.Axis = drawChartExAxes(formatSet.Type)
+	chart, _ := xml.Marshal(chartSpace)
+	f.saveFileList("xl/charts/chartEx"+strconv.Itoa(chartID)+".xml", chart)
+}
+
+// drawChartExData provides a function to draw the cx:data element of the
+// extended chart by given series index, chart type and series format sets.
+func drawChartExData(idx int, chartType string, ser formatChartSeries) *cxData {
+	data := &cxData{ID: idx}
+	if ser.Categories != "" {
+		data.StrDim = []*cxDim{{Type: "cat", F: ser.Categories}}
+	}
+	switch chartType {
+	case Treemap, Sunburst:
+		sizes := ser.Sizes
+		if sizes == "" {
+			sizes = ser.Values
+		}
+		data.NumDim = []*cxDim{{Type: "size", F: sizes}}
+	default:
+		data.NumDim = []*cxDim{{Type: "val", F: ser.Values}}
+	}
+	return data
+}
+
+// drawChartExSeries provides a function to draw the cx:series elements of
+// the extended chart by given series index, chart and series format sets.
+// The pareto chart contains an additional pareto line series for each
+// series.
+func drawChartExSeries(idx int, formatSet *formatChart, ser formatChartSeries) []*cxSeries {
+	series := &cxSeries{
+		LayoutID: chartExLayoutID[formatSet.Type],
+		DataID:   &attrValInt{Val: intPtr(idx)},
+	}
+	if ser.Name != "" {
+		series.Tx = &cxText{TxData: &cxTextData{F: ser.Name}}
+	}
+	if formatSet.Plotarea.ShowVal || formatSet.Plotarea.ShowCatName || formatSet.Plotarea.ShowSerName {
+		series.DataLabels = &cxDataLabels{Visibility: &cxDataLabelVisibility{
+			SeriesName:   formatSet.Plotarea.ShowSerName,
+			CategoryName: formatSet.Plotarea.ShowCatName,
+			Value:        formatSet.Plotarea.ShowVal,
+		}}
+	}
+	switch formatSet.Type {
+	case Treemap:
+		series.LayoutPr = &cxLayoutPr{ParentLabelLayout: &attrValString{Val: stringPtr("overlapping")}}
+	case BoxWhisker:
+		series.LayoutPr = &cxLayoutPr{
+			Visibility: &cxSeriesVisibility{MeanMarker: true, Outliers: true},
+			Statistics: &cxStatistics{QuartileMethod: "exclusive"},
+		}
+	case Histogram, Pareto:
+		series.LayoutPr = &cxLayoutPr{Binning: &cxBinning{IntervalClosed: "r"}}
+		if ser.Categories != "" {
+			series.LayoutPr = &cxLayoutPr{Aggregation: &cxEmptyElement{}}
+		}
+	}
+	if formatSet.Type != Pareto {
+		return []*cxSeries{series}
+	}
+	series.AxisID = []*attrValInt{{Val: intPtr(0)}, {Val: intPtr(1)}}
+	return []*cxSeries{series, {
+		LayoutID: "paretoLine",
+		OwnerIdx: intPtr(idx),
+		AxisID:   []*attrValInt{{Val: intPtr(2)}},
+	}}
+}
+
+// drawChartExAxes provides a function to draw the cx:axis elements of the
+// extended chart by given chart type. The treemap and sunburst charts don't
+// have any axes.
+func drawChartExAxes(chartType string) []*cxAxis {
+	catAx := &cxAxis{ID: 0, CatScaling: &cxCatScaling{GapWidth: "0.5"}, TickLabels: &cxEmptyElement{}}
+	valAx := &cxAxis{ID: 1, ValScaling: &cxValScaling{}, MajorGridlines: &cxEmptyElement{}, TickLabels: &cxEmptyElement{}}
+	switch chartType {
+	case Treemap, Sunburst:
+		return nil
+	case Funnel:
+		catAx.CatScaling.GapWidth = "0.06"
+		return []*cxAxis{catAx, {ID: 1, Hidden: true, ValScaling: &cxValScaling{}}}
+	case BoxWhisker:
+		catAx.CatScaling.GapWidth = "1"
+	case Histogram:
+		catAx.CatScaling.GapWidth = "0"
+	case Pareto:
+		catAx.CatScaling.GapWidth = "0"
+		return []*cxAxis{catAx, valAx, {
+			ID: 2, ValScaling: &cxValScaling{Max: "1", Min: "0"},
+			Units: &cxAxisUnits{Unit: "percentage"}, TickLabels: &cxEmptyElement{},
+		}}
+	}
+	return []*cxAxis{catAx, valAx}
+}
+
+// splitChartSecondarySeries provides a function to split the series of the
+// charts by the axes which they are plotted on, the chart which contains the
+// series on both primary and secondary axes will be split into two charts.
+// All series will be plotted on the primary axes if none of the series are
+// on the primary axes.
+func splitChartSecondarySeries(charts []*formatChart) ([]*formatChart, []*formatChart) {
+	var hasPrimary bool
+	for _, chart := range charts {
+		for _, ser := range chart.Series {
+			hasPrimary = hasPrimary || !ser.Y2Axis
+		}
+	}
+	if !hasPrimary {
+		return charts, nil
+	}
+	var primary, secondary []*formatChart
+	for _, chart := range charts {
+		var primarySeries, secondarySeries []formatChartSeries
+		for _, ser := range chart.Series {
+			if ser.Y2Axis {
+				secondarySeries = append(secondarySeries, ser)
+				continue
+			}
+			primarySeries = append(primarySeries, ser)
+		}
+		if len(secondarySeries) == 0 {
+			primary = append(primary, chart)
+			continue
+		}
+		if len(primarySeries) > 0 {
+			primaryChart := *chart
+			primaryChart.Series = primarySeries
+			primary = append(primary, &primaryChart)
+		}
+		secondaryChart := *chart
+		secondaryChart.Series = secondarySeries
+		secondary = append(secondary, &secondaryChart)
+	}
+	return primary, secondary
+}
+
+// drawChartSecondaryGroups provides a function to draw the chart groups of
+// the given charts on the secondary axes, the secondary value axis will be
+// drawn by the y2_axis format settings, and the secondary category axis will
+// be hidden.
+func (f *File) drawChartSecondaryGroups(plotArea *cPlotArea, formatSet *formatChart, charts []*formatChart) {
+	catAxID, valAxID := 754001154, 753999906
+	plotAreaFunc := f.plotAreaFuncs()
+	var catAx, valAx []*cAxs
+	for _, chart := range charts {
+		secondary := *chart
+		secondary.YAxis = formatSet.Y2Axis
+		p := plotAreaFunc[chart.Type](&secondary)
+		v := reflect.ValueOf(p).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			group, ok := v.Field(i).Interface().(*cCharts)
+			if !ok || group == nil {
+				continue
+			}
+			group.AxID = []*attrValInt{{Val: intPtr(catAxID)}, {Val: intPtr(valAxID)}}
+			plotArea.SecondaryCharts = append(plotArea.SecondaryCharts, &cChartGroup{
+				XMLName: xml.Name{Local: strings.Split(v.Type().Field(i).Tag.Get("xml"), ",")[0]},
+				cCharts: *group,
+			})
+		}
+		if len(catAx) == 0 || len(valAx) == 0 {
+			catAx, valAx = p.CatAx, p.ValAx
+		}
+	}
+	if len(catAx) == 0 || len(valAx) == 0 {
+		return
+	}
+	catAx[0].AxID, catAx[0].CrossAx = &attrValInt{Val: intPtr(catAxID)}, &attrValInt{Val: intPtr(valAxID)}
+	catAx[0].Delete, catAx[0].Title = &attrValBool{Val: boolPtr(true)}, nil
+	valAx[0].AxID, valAx[0].CrossAx = &attrValInt{Val: intPtr(valAxID)}, &attrValInt{Val: intPtr(catAxID)}
+	valAx[0].AxPos = &attrValString{Val: stringPtr(valAxPos[!formatSet.Y2Axis.ReverseOrder])}
+	if valAx[0].CrossesAt == nil {
+		valAx[0].Crosses = &attrValString{Val: stringPtr("max")}
+	}
+	plotArea.CatAx, plotArea.ValAx = append(plotArea.CatAx, catAx[0]), append(plotArea.ValAx, valAx[0])
+}
+
+// plotAreaFuncs provides a function to get the functions to draw the
+// c:plotArea element for each chart type.
+func (f *File) plotAreaFuncs() map[string]func(*formatChart) *cPlotArea {
+	return map[string]func(*formatChart) *cPlotArea{
 		Area:                        f.drawBaseChart,
 		AreaStacked:                 f.drawBaseChart,
 		AreaPercentStacked:          f.drawBaseChart,
@@ -234,30 +433,177 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 		WireframeContour:            f.drawSurfaceChart,
 		Bubble:                      f.drawBaseChart,
 		Bubble3D:                    f.drawBaseChart,
+		StockHighLowClose:           f.drawStockChart,
+		StockOpenHighLowClose:       f.drawStockChart,
+		StockVolumeHighLowClose:     f.drawStockChart,
+		StockVolumeOpenHighLowClose: f.drawStockChart,
 	}
-	if formatSet.Legend.None {
-		xlsxChartSpace.Chart.Legend = nil
+}
+
+// drawChartTitle provides a function to draw the c:title element by given
+// format sets. The title will be a reference of the cell if the name of the
+// title is a cell reference such as Sheet1!$A$1.
+func (f *File) drawChartTitle(formatSet *formatChart) *cTitle {
+	if formatSet.Title.None {
+		return nil
 	}
-	addChart := func(c, p *cPlotArea) {
-		immutable, mutable := reflect.ValueOf(c).Elem(), reflect.ValueOf(p).Elem()
-		for i := 0; i < mutable.NumField(); i++ {
-			field := mutable.Field(i)
-			if field.IsNil() {
-				continue
+	title := &cTitle{
+		Tx: cTx{
+			Rich: &cRich{
+				P: aP{
+					PPr: &aPPr{
+						DefRPr: aRPr{
+							Kern:   1200,
+							Strike: "noStrike",
+							U:      "none",
+							Sz:     1400,
+							SolidFill: &aSolidFill{
+								SchemeClr: &aSchemeClr{
+									Val: "tx1",
+									LumMod: &attrValInt{
+										Val: intPtr(65000),
+									},
+									LumOff: &attrValInt{
+										Val: intPtr(35000),
+									},
+								},
+							},
+							Ea: &aEa{
+								Typeface: "+mn-ea",
+							},
+							Cs: &aCs{
+								Typeface: "+mn-cs",
+							},
+							Latin: &aLatin{
+								Typeface: "+mn-lt",
+							},
+						},
+					},
+					R: []*aR{{
+						RPr: aRPr{
+							Lang:    "en-US",
+							AltLang: "en-US",
+						},
+						T: formatSet.Title.Name,
+					}},
+				},
+			},
+		},
+		TxPr: cTxPr{
+			P: aP{
+				PPr: &aPPr{
+					DefRPr: aRPr{
+						Kern:   1200,
+						U:      "none",
+						Sz:     14000,
+						Strike: "noStrike",
+					},
+				},
+				EndParaRPr: &aEndParaRPr{
+					Lang: "en-US",
+				},
+			},
+		},
+		Overlay: &attrValBool{Val: boolPtr(formatSet.Title.Overlay)},
+	}
+	if ref := strings.TrimPrefix(formatSet.Title.Name, "="); isChartCellRef(ref) && len(formatSet.Title.RichText) == 0 {
+		title.TxPr.P.PPr.DefRPr = title.Tx.Rich.P.PPr.DefRPr
+		title.Tx = cTx{StrRef: &cStrRef{F: ref}}
+	}
+	if formatSet.Title.Font != nil {
+		if title.Tx.Rich != nil {
+			drawChartFont(formatSet.Title.Font, &title.Tx.Rich.P.PPr.DefRPr)
+		} else {
+			drawChartFont(formatSet.Title.Font, &title.TxPr.P.PPr.DefRPr)
+		}
+	}
+	if len(formatSet.Title.RichText) > 0 {
+		title.Tx.Rich.P.R = nil
+		for _, run := range formatSet.Title.RichText {
+			r := &aR{RPr: aRPr{Lang: "en-US", AltLang: "en-US"}, T: run.Text}
+			if run.Font != nil {
+				drawChartFont(run.Font, &r.RPr)
 			}
-			immutable.FieldByName(mutable.Type().Field(i).Name).Set(field)
+			title.Tx.Rich.P.R = append(title.Tx.Rich.P.R, r)
 		}
 	}
-	addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[formatSet.Type](formatSet))
-	order := len(formatSet.Series)
-	for idx := range comboCharts {
-		comboCharts[idx].order = order
-		addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[comboCharts[idx].Type](comboCharts[idx]))
-		order += len(comboCharts[idx].Series)
+	title.SpPr.SolidFill = drawChartSolidFill(formatSet.Title.Fill.Color, "")
+	if formatSet.Title.Border.None {
+		title.SpPr.Ln = &aLn{NoFill: " "}
+	} else if formatSet.Title.Border.Color != "" {
+		title.SpPr.Ln = &aLn{W: f.ptToEMUs(formatSet.Title.Border.Width), SolidFill: drawChartSolidFill(formatSet.Title.Border.Color, "")}
 	}
-	chart, _ := xml.Marshal(xlsxChartSpace)
-	media := "xl/charts/chart" + strconv.Itoa(count+1) + ".xml"
-	f.saveFileList(media, chart)
+	return title
+}
+
+// isChartCellRef provides a function to check if the given string is a
+// reference of the single cell with the worksheet name, such as Sheet1!$A$1.
+func isChartCellRef(ref string) bool {
+	idx := strings.LastIndex(ref, "!")
+	if idx < 1 {
+		return false
+	}
+	_, _, err := CellNameToCoordinates(strings.Replace(ref[idx+1:], "$", "", -1))
+	return err == nil
+}
+
+// drawChartFont provides a function to set the run properties by given font
+// settings.
+func drawChartFont(fnt *Font, rPr *aRPr) {
+	rPr.B, rPr.I = fnt.Bold, fnt.Italic
+	if u, ok := map[string]string{"single": "sng", "double": "dbl"}[fnt.Underline]; ok {
+		rPr.U = u
+	}
+	if fnt.Strike {
+		rPr.Strike = "sngStrike"
+	}
+	if fnt.Size > 0 {
+		rPr.Sz = fnt.Size * 100
+	}
+	if fnt.Color != "" {
+		rPr.SolidFill = drawChartSolidFill(fnt.Color, "")
+	}
+	if fnt.Family != "" {
+		rPr.Latin, rPr.Ea, rPr.Cs = &aLatin{Typeface: fnt.Family}, &aEa{Typeface: fnt.Family}, &aCs{Typeface: fnt.Family}
+	}
+}
+
+// drawChartLegend provides a function to draw the overlay, deleted legend
+// entries and font of the c:legend element by given format sets.
+func (f *File) drawChartLegend(legend *cLegend, formatSet *formatChart) {
+	legend.Overlay = &attrValBool{Val: boolPtr(formatSet.Legend.Overlay)}
+	for _, idx := range formatSet.Legend.DeleteSeries {
+		legend.LegendEntry = append(legend.LegendEntry, &cLegendEntry{
+			IDx:    &attrValInt{Val: intPtr(idx)},
+			Delete: &attrValBool{Val: boolPtr(true)},
+		})
+	}
+	if formatSet.Legend.Font != (Font{}) {
+		legend.TxPr = &cTxPr{
+			P: aP{
+				PPr:        &aPPr{DefRPr: aRPr{Kern: 1200, Sz: 900, U: "none", Strike: "noStrike"}},
+				EndParaRPr: &aEndParaRPr{Lang: "en-US"},
+			},
+		}
+		drawChartFont(&formatSet.Legend.Font, &legend.TxPr.P.PPr.DefRPr)
+	}
+}
+
+// drawChartPlotAreaSpPr provides a function to draw the fill and border of
+// the c:plotArea element by given format sets.
+func (f *File) drawChartPlotAreaSpPr(formatSet *formatChart) *cSpPr {
+	border := formatSet.Plotarea.Border
+	if formatSet.Plotarea.Fill.Color == "" && border.Color == "" {
+		return nil
+	}
+	spPr := &cSpPr{SolidFill: drawChartSolidFill(formatSet.Plotarea.Fill.Color, "")}
+	if border.Color != "" {
+		spPr.Ln = &aLn{W: f.ptToEMUs(float64(border.Width)), SolidFill: drawChartSolidFill(border.Color, "")}
+		if border.DashType != "" {
+			spPr.Ln.PrstDash = &attrValString{Val: stringPtr(border.DashType)}
+		}
+	}
+	return spPr
 }
 
 // drawBaseChart provides a function to draw the c:plotArea element for bar,
@@ -292,6 +638,14 @@ func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
 	if *c.Overlap.Val, ok = plotAreaChartOverlap[formatSet.Type]; !ok {
 		c.Overlap = nil
 	}
+	if c.BarDir != nil {
+		if formatSet.GapWidth != nil {
+			c.GapWidth = &attrValInt{Val: intPtr(*formatSet.GapWidth)}
+		}
+		if formatSet.Overlap != nil && !strings.Contains(formatSet.Type, "3D") {
+			c.Overlap = &attrValInt{Val: intPtr(*formatSet.Overlap)}
+		}
+	}
 	catAx := f.drawPlotAreaCatAx(formatSet)
 	valAx := f.drawPlotAreaValAx(formatSet)
 	charts := map[string]*cPlotArea{
@@ -655,6 +1009,58 @@ func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
 	}
 }
 
+// drawStockChart provides a function to draw the c:plotArea element for stock
+// chart by given format sets. The stock chart with open prices will be drawn
+// with up and down bars, and the first series of the volume stock chart will
+// be drawn as a column chart on the primary axes, the other series will be
+// drawn on the secondary axes.
+func (f *File) drawStockChart(formatSet *formatChart) *cPlotArea {
+	stock, plotArea := *formatSet, &cPlotArea{}
+	catAxID, valAxID := 754001152, 753999904
+	if formatSet.Type == StockVolumeHighLowClose || formatSet.Type == StockVolumeOpenHighLowClose {
+		volume := *formatSet
+		volume.Type, volume.Series, volume.VaryColors = Col, formatSet.Series[:1], false
+		volume.YAxis = formatChartAxis{}
+		stock.Series, stock.order = formatSet.Series[1:], formatSet.order+1
+		plotArea = f.drawBaseChart(&volume)
+		catAxID, valAxID = 754001153, 753999905
+	}
+	plotArea.StockChart = &cCharts{
+		Ser:        f.drawChartSeries(&stock),
+		DLbls:      f.drawChartDLbls(&stock),
+		HiLowLines: &cChartLines{SpPr: f.drawPlotAreaSpPr()},
+		AxID: []*attrValInt{
+			{Val: intPtr(catAxID)},
+			{Val: intPtr(valAxID)},
+		},
+	}
+	if formatSet.Type == StockOpenHighLowClose || formatSet.Type == StockVolumeOpenHighLowClose {
+		plotArea.StockChart.UpDownBars = &cUpDownBars{
+			GapWidth: &attrValInt{Val: intPtr(150)},
+			UpBars: &cChartLines{SpPr: &cSpPr{
+				SolidFill: drawChartSolidFill("", "bg1"),
+				Ln:        &aLn{W: 9525, SolidFill: drawChartSolidFill("", "tx1")},
+			}},
+			DownBars: &cChartLines{SpPr: &cSpPr{
+				SolidFill: drawChartSolidFill("", "tx1"),
+				Ln:        &aLn{W: 9525, SolidFill: drawChartSolidFill("", "tx1")},
+			}},
+		}
+	}
+	catAx, valAx := f.drawPlotAreaCatAx(&stock), f.drawPlotAreaValAx(&stock)
+	if plotArea.BarChart != nil {
+		catAx[0].AxID, catAx[0].CrossAx = &attrValInt{Val: intPtr(catAxID)}, &attrValInt{Val: intPtr(valAxID)}
+		catAx[0].Delete = &attrValBool{Val: boolPtr(true)}
+		valAx[0].AxID, valAx[0].CrossAx = &attrValInt{Val: intPtr(valAxID)}, &attrValInt{Val: intPtr(catAxID)}
+		valAx[0].AxPos = &attrValString{Val: stringPtr(valAxPos[!formatSet.YAxis.ReverseOrder])}
+		if valAx[0].CrossesAt == nil {
+			valAx[0].Crosses = &attrValString{Val: stringPtr("max")}
+		}
+	}
+	plotArea.CatAx, plotArea.ValAx = append(plotArea.CatAx, catAx...), append(plotArea.ValAx, valAx...)
+	return plotArea
+}
+
 // drawSurface3DChart provides a function to draw the c:surface3DChart element by
 // given format sets.
 func (f *File) drawSurface3DChart(formatSet *formatChart) *cPlotArea {
@@ -748,11 +1154,14 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 			Marker:           f.drawChartSeriesMarker(k, formatSet),
 			DPt:              f.drawChartSeriesDPt(k, formatSet),
 			DLbls:            f.drawChartSeriesDLbls(formatSet),
+			Trendline:        f.drawChartSeriesTrendline(formatSet.Series[k]),
+			ErrBars:          f.drawChartSeriesErrBars(formatSet.Series[k], formatSet),
 			InvertIfNegative: &attrValBool{Val: boolPtr(false)},
 			Cat:              f.drawChartSeriesCat(formatSet.Series[k], formatSet),
 			Val:              f.drawChartSeriesVal(formatSet.Series[k], formatSet),
 			XVal:             f.drawChartSeriesXVal(formatSet.Series[k], formatSet),
 			YVal:             f.drawChartSeriesYVal(formatSet.Series[k], formatSet),
+			Smooth:           f.drawChartSeriesSmooth(k, formatSet),
 			BubbleSize:       f.drawCharSeriesBubbleSize(formatSet.Series[k], formatSet),
 			Bubble3D:         f.drawCharSeriesBubble3D(formatSet),
 		})
@@ -760,26 +1169,155 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 	return &ser
 }
 
+// drawChartLineSpPr provides a function to draw the c:spPr element of the
+// trendline and the error bars by given line format settings.
+func (f *File) drawChartLineSpPr(line formatChartLine) *cSpPr {
+	if line.Color == "" && line.Width == 0 {
+		return nil
+	}
+	return &cSpPr{Ln: &aLn{W: f.ptToEMUs(line.Width), Cap: "rnd", SolidFill: drawChartSolidFill(line.Color, "tx1")}}
+}
+
+// drawChartSeriesTrendline provides a function to draw the c:trendline
+// element by given series format sets.
+func (f *File) drawChartSeriesTrendline(series formatChartSeries) []*cTrendline {
+	opts := series.Trendline
+	if opts.Type == "" {
+		return nil
+	}
+	trendline := &cTrendline{
+		Name:          opts.Name,
+		SpPr:          f.drawChartLineSpPr(opts.Line),
+		TrendlineType: &attrValString{Val: stringPtr(chartTrendlineTypes[opts.Type])},
+		DispRSqr:      &attrValBool{Val: boolPtr(opts.DisplayRSquared)},
+		DispEq:        &attrValBool{Val: boolPtr(opts.DisplayEquation)},
+	}
+	if opts.Type == "polynomial" {
+		trendline.Order = &attrValInt{Val: intPtr(2)}
+		if opts.Order != 0 {
+			trendline.Order.Val = intPtr(opts.Order)
+		}
+	}
+	if opts.Type == "moving_average" {
+		trendline.Period = &attrValInt{Val: intPtr(2)}
+		if opts.Period != 0 {
+			trendline.Period.Val = intPtr(opts.Period)
+		}
+	} else {
+		if opts.Forward != 0 {
+			trendline.Forward = &attrValFloat{Val: float64Ptr(opts.Forward)}
+		}
+		if opts.Backward != 0 {
+			trendline.Backward = &attrValFloat{Val: float64Ptr(opts.Backward)}
+		}
+	}
+	if opts.Intercept != nil && inStrSlice([]string{"exponential", "linear", "polynomial"}, opts.Type) != -1 {
+		trendline.Intercept = &attrValFloat{Val: float64Ptr(*opts.Intercept)}
+	}
+	if opts.DisplayEquation || opts.DisplayRSquared {
+		trendline.TrendlineLbl = &cTrendlineLbl{NumFmt: &cNumFmt{FormatCode: "General"}}
+	}
+	return []*cTrendline{trendline}
+}
+
+// drawChartSeriesErrBars provides a function to draw the c:errBars elements
+// by given series and format sets.
+func (f *File) drawChartSeriesErrBars(series formatChartSeries, formatSet *formatChart) []*cErrBars {
+	var errBars []*cErrBars
+	for i, opts := range []formatChartErrorBars{series.XErrorBars, series.YErrorBars} {
+		if opts.Type == "" {
+			continue
+		}
+		bars := &cErrBars{
+			ErrBarType: &attrValString{Val: stringPtr("both")},
+			ErrValType: &attrValString{Val: stringPtr(chartErrorBarsTypes[opts.Type])},
+			NoEndCap:   &attrValBool{Val: boolPtr(opts.NoEndCap)},
+			SpPr:       f.drawChartLineSpPr(opts.Line),
+		}
+		if formatSet.Type == Scatter || formatSet.Type == Bubble {
+			bars.ErrDir = &attrValString{Val: stringPtr([]string{"x", "y"}[i])}
+		}
+		if opts.Direction != "" {
+			bars.ErrBarType.Val = stringPtr(opts.Direction)
+		}
+		switch opts.Type {
+		case "custom":
+			if opts.PlusValues != "" {
+				bars.Plus = &cVal{NumRef: &cNumRef{F: opts.PlusValues}}
+			}
+			if opts.MinusValues != "" {
+				bars.Minus = &cVal{NumRef: &cNumRef{F: opts.MinusValues}}
+			}
+		case "fixed", "percentage", "standard_deviation":
+			bars.Val = &attrValFloat{Val: float64Ptr(1)}
+			if opts.Value != 0 {
+				bars.Val.Val = float64Ptr(opts.Value)
+			}
+		}
+		errBars = append(errBars, bars)
+	}
+	return errBars
+}
+
+// drawChartSolidFill provides a function to draw the a:solidFill element by
+// given RGB color, the scheme color will be used if the RGB color is empty.
+func drawChartSolidFill(color, schemeClr string) *aSolidFill {
+	if color != "" {
+		return &aSolidFill{SrgbClr: &attrValString{Val: stringPtr(strings.ToUpper(strings.TrimPrefix(color, "#")))}}
+	}
+	if schemeClr != "" {
+		return &aSolidFill{SchemeClr: &aSchemeClr{Val: schemeClr}}
+	}
+	return nil
+}
+
 // drawChartSeriesSpPr provides a function to draw the c:spPr element by given
 // format sets.
 func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
+	series := formatSet.Series[i]
 	spPrScatter := &cSpPr{
 		Ln: &aLn{
 			W:      25400,
 			NoFill: " ",
 		},
 	}
+	if series.Line.Color != "" && !series.Line.None {
+		spPrScatter.Ln = &aLn{
+			W:         f.ptToEMUs(series.Line.Width),
+			Cap:       "rnd",
+			SolidFill: drawChartSolidFill(series.Line.Color, ""),
+		}
+	}
 	spPrLine := &cSpPr{
 		Ln: &aLn{
-			W:   f.ptToEMUs(formatSet.Series[i].Line.Width),
-			Cap: "rnd", // rnd, sq, flat
-			SolidFill: &aSolidFill{
-				SchemeClr: &aSchemeClr{Val: "accent" + strconv.Itoa((formatSet.order+i)%6+1)},
-			},
+			W:         f.ptToEMUs(series.Line.Width),
+			Cap:       "rnd", // rnd, sq, flat
+			SolidFill: drawChartSolidFill(series.Line.Color, "accent"+strconv.Itoa((formatSet.order+i)%6+1)),
 		},
 	}
-	chartSeriesSpPr := map[string]*cSpPr{Line: spPrLine, Scatter: spPrScatter}
-	return chartSeriesSpPr[formatSet.Type]
+	if series.Line.None {
+		spPrLine.Ln.NoFill, spPrLine.Ln.SolidFill = " ", nil
+	}
+	chartSeriesSpPr := map[string]*cSpPr{
+		Line: spPrLine, Scatter: spPrScatter, StockHighLowClose: spPrScatter, StockOpenHighLowClose: spPrScatter,
+		StockVolumeHighLowClose: spPrScatter, StockVolumeOpenHighLowClose: spPrScatter,
+	}
+	if spPr, ok := chartSeriesSpPr[formatSet.Type]; ok {
+		return spPr
+	}
+	spPr := &cSpPr{SolidFill: drawChartSolidFill(series.Fill.Color, "")}
+	if series.Fill.None {
+		spPr.NoFill, spPr.SolidFill = stringPtr(""), nil
+	}
+	if series.Line.None {
+		spPr.Ln = &aLn{NoFill: " "}
+	} else if series.Line.Color != "" {
+		spPr.Ln = &aLn{W: f.ptToEMUs(series.Line.Width), SolidFill: drawChartSolidFill(series.Line.Color, "")}
+	}
+	if spPr.NoFill == nil && spPr.SolidFill == nil && spPr.Ln == nil {
+		return nil
+	}
+	return spPr
 }
 
 // drawChartSeriesDPt provides a function to draw the c:dPt element by given
@@ -808,7 +1346,31 @@ func (f *File) drawChartSeriesDPt(i int, formatSet *formatChart) []*cDPt {
 		},
 	}}
 	chartSeriesDPt := map[string][]*cDPt{Pie: dpt, Pie3D: dpt}
-	return chartSeriesDPt[formatSet.Type]
+	dPts := chartSeriesDPt[formatSet.Type]
+	for _, point := range formatSet.Series[i].Points {
+		spPr := &cSpPr{SolidFill: drawChartSolidFill(point.Fill.Color, "")}
+		if point.Border.Color != "" {
+			spPr.Ln = &aLn{W: f.ptToEMUs(point.Border.Width), SolidFill: drawChartSolidFill(point.Border.Color, "")}
+		}
+		pt := &cDPt{IDx: &attrValInt{Val: intPtr(point.Index)}, SpPr: spPr}
+		switch formatSet.Type {
+		case Line, Scatter:
+			pt.Marker, pt.SpPr = &cMarker{SpPr: spPr}, nil
+		case Pie, Pie3D:
+			pt.Bubble3D = &attrValBool{Val: boolPtr(false)}
+		}
+		var exists bool
+		for idx := range dPts {
+			if *dPts[idx].IDx.Val == point.Index {
+				dPts[idx], exists = pt, true
+			}
+		}
+		if !exists {
+			dPts = append(dPts, pt)
+		}
+	}
+	sort.Slice(dPts, func(i, j int) bool { return *dPts[i].IDx.Val < *dPts[j].IDx.Val })
+	return dPts
 }
 
 // drawChartSeriesCat provides a function to draw the c:cat element by given
@@ -844,7 +1406,10 @@ func (f *File) drawChartSeriesVal(v formatChartSeries, formatSet *formatChart) *
 // drawChartSeriesMarker provides a function to draw the c:marker element by
 // given data index and format sets.
 func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
-	defaultSymbol := map[string]*attrValString{Scatter: {Val: stringPtr("circle")}}
+	defaultSymbol := map[string]*attrValString{
+		Scatter: {Val: stringPtr("circle")}, StockHighLowClose: {Val: stringPtr("none")}, StockOpenHighLowClose: {Val: stringPtr("none")},
+		StockVolumeHighLowClose: {Val: stringPtr("none")}, StockVolumeOpenHighLowClose: {Val: stringPtr("none")},
+	}
 	marker := &cMarker{
 		Symbol: defaultSymbol[formatSet.Type],
 		Size:   &attrValInt{Val: intPtr(5)},
@@ -855,27 +1420,54 @@ func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
 	if size := intPtr(formatSet.Series[i].Marker.Size); *size != 0 {
 		marker.Size = &attrValInt{Val: size}
 	}
-	if i < 6 {
+	series := formatSet.Series[i]
+	if i < 6 || series.Line.Color != "" {
 		marker.SpPr = &cSpPr{
-			SolidFill: &aSolidFill{
-				SchemeClr: &aSchemeClr{
-					Val: "accent" + strconv.Itoa(i+1),
-				},
-			},
+			SolidFill: drawChartSolidFill(series.Line.Color, "accent"+strconv.Itoa(i+1)),
 			Ln: &aLn{
-				W: 9252,
-				SolidFill: &aSolidFill{
-					SchemeClr: &aSchemeClr{
-						Val: "accent" + strconv.Itoa(i+1),
-					},
-				},
+				W:         9252,
+				SolidFill: drawChartSolidFill(series.Line.Color, "accent"+strconv.Itoa(i+1)),
 			},
 		}
 	}
-	chartSeriesMarker := map[string]*cMarker{Scatter: marker, Line: marker}
+	if m := series.Marker; m.Fill.None || m.Fill.Color != "" || m.Border.None || m.Border.Color != "" || m.Width > 0 {
+		if marker.SpPr == nil {
+			marker.SpPr = &cSpPr{}
+		}
+		if marker.SpPr.Ln == nil {
+			marker.SpPr.Ln = &aLn{W: 9252}
+		}
+		if m.Fill.None {
+			marker.SpPr.NoFill, marker.SpPr.SolidFill = stringPtr(""), nil
+		} else if m.Fill.Color != "" {
+			marker.SpPr.SolidFill = drawChartSolidFill(m.Fill.Color, "")
+		}
+		if m.Border.None {
+			marker.SpPr.Ln.NoFill, marker.SpPr.Ln.SolidFill = " ", nil
+		} else if m.Border.Color != "" {
+			marker.SpPr.Ln.SolidFill = drawChartSolidFill(m.Border.Color, "")
+		}
+		if m.Width > 0 {
+			marker.SpPr.Ln.W = f.ptToEMUs(m.Width)
+		}
+	}
+	chartSeriesMarker := map[string]*cMarker{
+		Scatter: marker, Line: marker, StockHighLowClose: marker, StockOpenHighLowClose: marker,
+		StockVolumeHighLowClose: marker, StockVolumeOpenHighLowClose: marker,
+	}
 	return chartSeriesMarker[formatSet.Type]
 }
 
+// drawChartSeriesSmooth provides a function to draw the c:smooth element by
+// given data index and format sets.
+func (f *File) drawChartSeriesSmooth(i int, formatSet *formatChart) *attrValBool {
+	if !formatSet.Series[i].Smooth {
+		return nil
+	}
+	chartSeriesSmooth := map[string]*attrValBool{Line: {Val: boolPtr(true)}, Scatter: {Val: boolPtr(true)}}
+	return chartSeriesSmooth[formatSet.Type]
+}
+
 // drawChartSeriesXVal provides a function to draw the c:xVal element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart) *cCat {
@@ -884,7 +1476,7 @@ func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart)
 			F: v.Categories,
 		},
 	}
-	chartSeriesXVal := map[string]*cCat{Scatter: cat}
+	chartSeriesXVal := map[string]*cCat{Scatter: cat, Bubble: cat, Bubble3D: cat}
 	return chartSeriesXVal[formatSet.Type]
 }
 
@@ -901,14 +1493,19 @@ func (f *File) drawChartSeriesYVal(v formatChartSeries, formatSet *formatChart)
 }
 
 // drawCharSeriesBubbleSize provides a function to draw the c:bubbleSize
-// element by given chart series and format sets.
+// element by given chart series and format sets. The values of the series
+// will be used as the bubble sizes if the sizes of the series is empty.
 func (f *File) drawCharSeriesBubbleSize(v formatChartSeries, formatSet *formatChart) *cVal {
 	if _, ok := map[string]bool{Bubble: true, Bubble3D: true}[formatSet.Type]; !ok {
 		return nil
 	}
+	sizes := v.Sizes
+	if sizes == "" {
+		sizes = v.Values
+	}
 	return &cVal{
 		NumRef: &cNumRef{
-			F: v.Values,
+			F: sizes,
 		},
 	}
 }
@@ -976,7 +1573,7 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 			MinorTickMark: &attrValString{Val: stringPtr("none")},
 			TickLblPos:    &attrValString{Val: stringPtr("nextTo")},
 			SpPr:          f.drawPlotAreaSpPr(),
-			TxPr:          f.drawPlotAreaTxPr(),
+			TxPr:          f.drawPlotAreaTxPr(nil),
 			CrossAx:       &attrValInt{Val: intPtr(753999904)},
 			Crosses:       &attrValString{Val: stringPtr("autoZero")},
 			Auto:          &attrValBool{Val: boolPtr(true)},
@@ -994,6 +1591,25 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 	if formatSet.XAxis.TickLabelSkip != 0 {
 		axs[0].TickLblSkip = &attrValInt{Val: intPtr(formatSet.XAxis.TickLabelSkip)}
 	}
+	f.drawPlotAreaAxisOptions(axs[0], &formatSet.XAxis, formatSet.YAxis.Crossing)
+	if formatSet.XAxis.DateAxis {
+		axs[0].LblAlgn, axs[0].TickLblSkip, axs[0].NoMultiLvlLbl = nil, nil, nil
+		if formatSet.XAxis.BaseUnit != "" {
+			axs[0].BaseTimeUnit = &attrValString{Val: stringPtr(formatSet.XAxis.BaseUnit)}
+		}
+		if formatSet.XAxis.MajorUnit != 0 {
+			axs[0].MajorUnit = &attrValFloat{Val: float64Ptr(formatSet.XAxis.MajorUnit)}
+		}
+		if formatSet.XAxis.MajorUnitType != "" {
+			axs[0].MajorTimeUnit = &attrValString{Val: stringPtr(formatSet.XAxis.MajorUnitType)}
+		}
+		if formatSet.XAxis.MinorUnit != 0 {
+			axs[0].MinorUnit = &attrValFloat{Val: float64Ptr(formatSet.XAxis.MinorUnit)}
+		}
+		if formatSet.XAxis.MinorUnitType != "" {
+			axs[0].MinorTimeUnit = &attrValString{Val: stringPtr(formatSet.XAxis.MinorUnitType)}
+		}
+	}
 	return axs
 }
 
@@ -1030,7 +1646,7 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 			MinorTickMark: &attrValString{Val: stringPtr("none")},
 			TickLblPos:    &attrValString{Val: stringPtr("nextTo")},
 			SpPr:          f.drawPlotAreaSpPr(),
-			TxPr:          f.drawPlotAreaTxPr(),
+			TxPr:          f.drawPlotAreaTxPr(nil),
 			CrossAx:       &attrValInt{Val: intPtr(754001152)},
 			Crosses:       &attrValString{Val: stringPtr("autoZero")},
 			CrossBetween:  &attrValString{Val: stringPtr(chartValAxCrossBetween[formatSet.Type])},
@@ -1048,9 +1664,53 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 	if formatSet.YAxis.MajorUnit != 0 {
 		axs[0].MajorUnit = &attrValFloat{Val: float64Ptr(formatSet.YAxis.MajorUnit)}
 	}
+	if formatSet.YAxis.MinorUnit != 0 {
+		axs[0].MinorUnit = &attrValFloat{Val: float64Ptr(formatSet.YAxis.MinorUnit)}
+	}
+	if unit, ok := chartDisplayUnits[formatSet.YAxis.DisplayUnits]; ok {
+		axs[0].DispUnits = &cDispUnits{BuiltInUnit: &attrValString{Val: stringPtr(unit)}}
+		if formatSet.YAxis.DisplayUnitsVisible {
+			axs[0].DispUnits.DispUnitsLbl = &cDispUnitsLbl{}
+		}
+	}
+	f.drawPlotAreaAxisOptions(axs[0], &formatSet.YAxis, formatSet.XAxis.Crossing)
 	return axs
 }
 
+// drawPlotAreaAxisOptions provides a function to draw the number format, tick
+// marks, label font and crossing point of the c:catAx, c:dateAx and c:valAx
+// element by given axis format settings, and the crossing point of this axis
+// on the perpendicular axis.
+func (f *File) drawPlotAreaAxisOptions(axs *cAxs, opts *formatChartAxis, crossing string) {
+	if opts.NumFormat != "" {
+		axs.NumFmt = &cNumFmt{FormatCode: opts.NumFormat}
+	}
+	if opts.MajorTickMark != "" {
+		axs.MajorTickMark = &attrValString{Val: stringPtr(opts.MajorTickMark)}
+	}
+	if opts.MinorTickMark != "" {
+		axs.MinorTickMark = &attrValString{Val: stringPtr(opts.MinorTickMark)}
+	}
+	axs.TxPr = f.drawPlotAreaTxPr(opts)
+	if opts.Name != "" {
+		axs.Title = f.drawChartTitle(&formatChart{Title: formatChartTitle{Name: opts.Name}})
+		if axs.Title.Tx.Rich != nil {
+			axs.Title.Tx.Rich.P.PPr.DefRPr.Sz = 1000
+		} else {
+			axs.Title.TxPr.P.PPr.DefRPr.Sz = 1000
+		}
+	}
+	switch crossing {
+	case "", "auto_zero":
+	case "max", "min":
+		axs.Crosses = &attrValString{Val: stringPtr(crossing)}
+	default:
+		if val, err := strconv.ParseFloat(crossing, 64); err == nil {
+			axs.Crosses, axs.CrossesAt = nil, &attrValFloat{Val: float64Ptr(val)}
+		}
+	}
+}
+
 // drawPlotAreaSerAx provides a function to draw the c:serAx element.
 func (f *File) drawPlotAreaSerAx(formatSet *formatChart) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
@@ -1073,7 +1733,7 @@ func (f *File) drawPlotAreaSerAx(formatSet *formatChart) []*cAxs {
 			AxPos:      &attrValString{Val: stringPtr(catAxPos[formatSet.XAxis.ReverseOrder])},
 			TickLblPos: &attrValString{Val: stringPtr("nextTo")},
 			SpPr:       f.drawPlotAreaSpPr(),
-			TxPr:       f.drawPlotAreaTxPr(),
+			TxPr:       f.drawPlotAreaTxPr(nil),
 			CrossAx:    &attrValInt{Val: intPtr(753999904)},
 		},
 	}
@@ -1098,9 +1758,10 @@ func (f *File) drawPlotAreaSpPr() *cSpPr {
 	}
 }
 
-// drawPlotAreaTxPr provides a function to draw the c:txPr element.
-func (f *File) drawPlotAreaTxPr() *cTxPr {
-	return &cTxPr{
+// drawPlotAreaTxPr provides a function to draw the c:txPr element by given
+// axis format settings.
+func (f *File) drawPlotAreaTxPr(opts *formatChartAxis) *cTxPr {
+	txPr := &cTxPr{
 		BodyPr: aBodyPr{
 			Rot:              -60000000,
 			SpcFirstLastPara: true,
@@ -1135,6 +1796,20 @@ func (f *File) drawPlotAreaTxPr() *cTxPr {
 			EndParaRPr: &aEndParaRPr{Lang: "en-US"},
 		},
 	}
+	if opts == nil {
+		return txPr
+	}
+	if opts.NumFont.Rotation != nil {
+		txPr.BodyPr.Rot = *opts.NumFont.Rotation * 60000
+	}
+	if opts.NumFont.Color != "" {
+		txPr.P.PPr.DefRPr.SolidFill = drawChartSolidFill(opts.NumFont.Color, "")
+	}
+	txPr.P.PPr.DefRPr.B, txPr.P.PPr.DefRPr.I = opts.NumFont.Bold, opts.NumFont.Italic
+	if opts.NumFont.Underline {
+		txPr.P.PPr.DefRPr.U = "sng"
+	}
+	return txPr
 }
 
 // drawingParser provides a function to parse drawingXML. In order to solve
@@ -1158,6 +1833,11 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
 				log.Printf("xml decode error: %s", err)
 			}
 			content.R = decodeWsDr.R
+			for _, v := range decodeWsDr.AbsoluteAnchor {
+				content.AbsoluteAnchor = append(content.AbsoluteAnchor, &xdrCellAnchor{
+					GraphicFrame: v.Content,
+				})
+			}
 			for _, v := range decodeWsDr.OneCellAnchor {
 				content.OneCellAnchor = append(content.OneCellAnchor, &xdrCellAnchor{
 					EditAs:       v.EditAs,
@@ -1179,7 +1859,7 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
 	}
 	wsDr.Lock()
 	defer wsDr.Unlock()
-	return wsDr, len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2
+	return wsDr, len(wsDr.AbsoluteAnchor) + len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2
 }
 
 // addDrawingChart provides a function to add chart graphic frame by given
@@ -1241,14 +1921,91 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	return err
 }
 
-// addSheetDrawingChart provides a function to add chart graphic frame for
-// chartsheet by given sheet, drawingXML, width, height, relationship index
-// and format sets.
-func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *formatPicture) {
-	content, cNvPrID := f.drawingParser(drawingXML)
-	absoluteAnchor := xdrCellAnchor{
-		EditAs: formatSet.Positioning,
-		Pos:    &xlsxPoint2D{},
+// addDrawingChartEx provides a function to add the extended chart graphic
+// frame by given sheet, drawingXML, cell, relationship index and format sets.
+// The graphic frame is wrapped in the alternate content with a fallback
+// shape for the applications which doesn't support the extended chart types.
+func (f *File) addDrawingChartEx(sheet, drawingXML, cell string, rID int, formatSet *formatChart) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	width := int(float64(formatSet.Dimension.Width) * formatSet.Format.XScale)
+	height := int(float64(formatSet.Dimension.Height) * formatSet.Format.YScale)
+	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
+		f.positionObjectPixels(sheet, col-1, row-1, formatSet.Format.OffsetX, formatSet.Format.OffsetY, width, height)
+	content, cNvPrID := f.drawingParser(drawingXML)
+	twoCellAnchor := xdrCellAnchor{
+		EditAs: formatSet.Format.Positioning,
+		From: &xlsxFrom{
+			Col: colStart, ColOff: formatSet.Format.OffsetX * EMU,
+			Row: rowStart, RowOff: formatSet.Format.OffsetY * EMU,
+		},
+		To: &xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+	}
+	cNvPr := &xlsxCNvPr{ID: cNvPrID, Name: "Chart " + strconv.Itoa(cNvPrID)}
+	alternateContent := xlsxChartExAlternateContent{
+		XMLNSMC: SourceRelationshipCompatibility.Value,
+		Choice: xlsxChartExChoice{
+			XMLNSCX1: NameSpaceDrawingMLChartEx1,
+			Requires: "cx1",
+			GraphicFrame: xlsxGraphicFrame{
+				NvGraphicFramePr: xlsxNvGraphicFramePr{CNvPr: cNvPr},
+				Graphic: &xlsxGraphic{
+					GraphicData: &xlsxGraphicData{
+						URI: NameSpaceDrawingMLChartEx,
+						ChartEx: &xlsxChartEx{
+							CX:  NameSpaceDrawingMLChartEx,
+							R:   SourceRelationship.Value,
+							RID: "rId" + strconv.Itoa(rID),
+						},
+					},
+				},
+			},
+		},
+		Fallback: xlsxChartExFallback{
+			Sp: &xdrSp{
+				NvSpPr: &xdrNvSpPr{CNvPr: cNvPr, CNvSpPr: &xdrCNvSpPr{TxBox: true}},
+				SpPr: &xlsxSpPr{
+					Xfrm: xlsxXfrm{
+						Off: xlsxOff{X: f.getColOffsetEMU(sheet, colStart) + twoCellAnchor.From.ColOff, Y: f.getRowOffsetEMU(sheet, rowStart) + twoCellAnchor.From.RowOff},
+						Ext: xlsxExt{Cx: width * EMU, Cy: height * EMU},
+					},
+					PrstGeom: xlsxPrstGeom{Prst: "rect"},
+				},
+				TxBody: &xdrTxBody{
+					BodyPr: &aBodyPr{},
+					P: []*aP{{R: []*aR{{
+						RPr: aRPr{Lang: "en-US", Sz: 1100},
+						T:   "This chart isn't available in your version of Excel.",
+					}}}},
+				},
+			},
+		},
+	}
+	if formatSet.Type == Funnel {
+		alternateContent.Choice.XMLNSCX1, alternateContent.Choice.XMLNSCX2 = "", NameSpaceDrawingMLChartEx2
+		alternateContent.Choice.Requires = "cx2"
+	}
+	graphic, _ := xml.Marshal(alternateContent)
+	twoCellAnchor.GraphicFrame = string(graphic)
+	twoCellAnchor.ClientData = &xdrClientData{
+		FLocksWithSheet:  formatSet.Format.FLocksWithSheet,
+		FPrintsWithSheet: formatSet.Format.FPrintsWithSheet,
+	}
+	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
+	f.Drawings.Store(drawingXML, content)
+	return err
+}
+
+// addSheetDrawingChart provides a function to add chart graphic frame for
+// chartsheet by given sheet, drawingXML, width, height, relationship index
+// and format sets.
+func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *formatPicture) {
+	content, cNvPrID := f.drawingParser(drawingXML)
+	absoluteAnchor := xdrCellAnchor{
+		EditAs: formatSet.Positioning,
+		Pos:    &xlsxPoint2D{},
 		Ext:    &xlsxExt{},
 	}
 
@@ -1280,44 +2037,897 @@ func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *forma
 	f.Drawings.Store(drawingXML, content)
 }
 
-// deleteDrawing provides a function to delete chart graphic frame by given by
-// given coordinates and graphic type.
-func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err error) {
+// DeleteDrawing provides a function to delete the drawing objects, including
+// charts, pictures and shapes in a worksheet by given worksheet name and
+// drawing object name. All drawing objects with the given name will be
+// deleted, and the charts and images which are no longer used by any drawing
+// objects will be deleted from the document. For example, delete the
+// picture added with the name "Picture 2" in the worksheet named Sheet1:
+//
+//    err := f.DeleteDrawing("Sheet1", "Picture 2")
+//
+func (f *File) DeleteDrawing(sheet, name string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.Drawing == nil {
+		return err
+	}
+	drawingXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl", -1)
+	return f.deleteDrawingObjects(drawingXML, func(deAnchor *decodeTwoCellAnchor) bool {
+		return getDrawingObjectName(deAnchor) == name
+	})
+}
+
+// GetObjectByName provides a function to get the drawing object, including
+// charts, pictures and shapes in a worksheet by given worksheet name and
+// drawing object name. The names of the drawing objects are assigned as
+// "Chart 2", "Picture 3" or "Shape 4" when they are added, and could be
+// changed by the SetObjectName function. For example, get the cell of the
+// picture named "Logo" in the worksheet named Sheet1:
+//
+//    obj, err := f.GetObjectByName("Sheet1", "Logo")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    fmt.Println(obj.Type, obj.Cell)
+//
+func (f *File) GetObjectByName(sheet, name string) (DrawingObject, error) {
+	var obj DrawingObject
+	objects, err := f.getDrawingObjects(sheet)
+	if err != nil {
+		return obj, err
+	}
+	for _, object := range objects {
+		if object.Name == name {
+			return object.DrawingObject, err
+		}
+	}
+	return obj, ErrObjectNotExist
+}
+
+// SetObjectName provides a function to rename the drawing object, including
+// charts, pictures and shapes in a worksheet by given worksheet name, the
+// name of the drawing object and the new name. The name of the drawing
+// object must be unique in the worksheet. For example, rename the picture
+// named "Picture 2" in the worksheet named Sheet1 to "Logo":
+//
+//    err := f.SetObjectName("Sheet1", "Picture 2", "Logo")
+//
+func (f *File) SetObjectName(sheet, name, newName string) error {
+	if newName == "" {
+		return ErrParameterRequired
+	}
+	objects, err := f.getDrawingObjects(sheet)
+	if err != nil {
+		return err
+	}
+	var target *drawingObject
+	for idx, object := range objects {
+		if object.Name == newName && newName != name {
+			return ErrObjectNameDuplicate
+		}
+		if object.Name == name && target == nil {
+			target = &objects[idx]
+		}
+	}
+	if target == nil {
+		return ErrObjectNotExist
+	}
+	cellAnchor := target.cellAnchor
+	switch {
+	case cellAnchor.Pic != nil:
+		cellAnchor.Pic.NvPicPr.CNvPr.Name = newName
+	case cellAnchor.Sp != nil && cellAnchor.Sp.NvSpPr != nil && cellAnchor.Sp.NvSpPr.CNvPr != nil:
+		cellAnchor.Sp.NvSpPr.CNvPr.Name = newName
+	default:
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(newName))
+		if loc := cNvPrNameRegexp.FindStringSubmatchIndex(cellAnchor.GraphicFrame); loc != nil {
+			cellAnchor.GraphicFrame = cellAnchor.GraphicFrame[:loc[4]] + buf.String() + cellAnchor.GraphicFrame[loc[5]:]
+		}
+	}
+	return err
+}
+
+// drawingObject defined the drawing object with the cell anchor in the
+// drawing part.
+type drawingObject struct {
+	DrawingObject
+	cellAnchor *xdrCellAnchor
+}
+
+// drawingObjectTypes defined the types of the drawing objects.
+var drawingObjectTypes = map[string]string{"Chart": "chart", "Group": "group", "Pic": "picture", "Shape": "shape"}
+
+// cNvPrNameRegexp defined the regular expression to match the name attribute
+// of the non-visual drawing properties in the drawing part.
+var cNvPrNameRegexp = regexp.MustCompile(`(<(?:[\w-]+:)?cNvPr\s[^>]*?\bname=")([^"]*)(")`)
+
+// getDrawingObjects provides a function to get the drawing objects and the
+// cell anchors of them in a worksheet by given worksheet name.
+func (f *File) getDrawingObjects(sheet string) ([]drawingObject, error) {
+	var objects []drawingObject
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.Drawing == nil {
+		return objects, err
+	}
+	drawingXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl", -1)
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	for _, cellAnchors := range [][]*xdrCellAnchor{wsDr.AbsoluteAnchor, wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, cellAnchor := range cellAnchors {
+			deAnchor, err := f.decodeDrawingAnchor(cellAnchor)
+			if err != nil {
+				return objects, err
+			}
+			cNvPr := getDrawingObjectCNvPr(deAnchor)
+			if cNvPr == nil {
+				continue
+			}
+			object := drawingObject{
+				DrawingObject: DrawingObject{
+					ID:   cNvPr.ID,
+					Name: cNvPr.Name,
+					Type: drawingObjectTypes[getDrawingObjectType(deAnchor)],
+				},
+				cellAnchor: cellAnchor,
+			}
+			if deAnchor.From != nil {
+				object.Cell, _ = CoordinatesToCellName(deAnchor.From.Col+1, deAnchor.From.Row+1)
+			}
+			objects = append(objects, object)
+		}
+	}
+	return objects, err
+}
+
+// deleteDrawing provides a function to delete the drawing objects by given
+// coordinates and drawing object type, the type of the drawing object should
+// be "Chart", "Pic" or "Shape".
+func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) error {
+	return f.deleteDrawingObjects(drawingXML, func(deAnchor *decodeTwoCellAnchor) bool {
+		return getDrawingObjectType(deAnchor) == drawingType && deAnchor.From != nil &&
+			deAnchor.From.Col == col && deAnchor.From.Row == row
+	})
+}
+
+// deleteDrawingObjects provides a function to delete the drawing objects
+// which match the given function in the drawing part, and clean up the
+// relationships and parts which are no longer used by any drawing objects.
+func (f *File) deleteDrawingObjects(drawingXML string, match func(deAnchor *decodeTwoCellAnchor) bool) error {
+	var rIDs []string
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	for _, cellAnchors := range []*[]*xdrCellAnchor{&wsDr.AbsoluteAnchor, &wsDr.OneCellAnchor, &wsDr.TwoCellAnchor} {
+		for idx := 0; idx < len(*cellAnchors); idx++ {
+			deAnchor, err := f.decodeDrawingAnchor((*cellAnchors)[idx])
+			if err != nil {
+				wsDr.Unlock()
+				return err
+			}
+			if match(deAnchor) {
+				rIDs = append(rIDs, getDrawingObjectRIDs(deAnchor)...)
+				*cellAnchors = append((*cellAnchors)[:idx], (*cellAnchors)[idx+1:]...)
+				idx--
+			}
+		}
+	}
+	content, _ := xml.Marshal(wsDr)
+	wsDr.Unlock()
+	f.Drawings.Store(drawingXML, wsDr)
+	f.deleteUnusedRels(drawingXML, rIDs, string(content))
+	return nil
+}
+
+// decodeDrawingAnchor provides a function to decode the cell anchor in the
+// drawing part, the properties of the anchor which have been created in
+// memory will be merged into the decoded cell anchor.
+func (f *File) decodeDrawingAnchor(cellAnchor *xdrCellAnchor) (*decodeTwoCellAnchor, error) {
+	if cellAnchor.Pic != nil {
+		return convertCellAnchor(cellAnchor), nil
+	}
+	deAnchor := new(decodeTwoCellAnchor)
+	if err := f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + cellAnchor.GraphicFrame + "</decodeTwoCellAnchor>")).
+		Decode(deAnchor); err != nil && err != io.EOF {
+		return deAnchor, fmt.Errorf("xml decode error: %s", err)
+	}
+	if cellAnchor.From != nil {
+		deAnchor.From = &decodeFrom{Col: cellAnchor.From.Col, ColOff: cellAnchor.From.ColOff, Row: cellAnchor.From.Row, RowOff: cellAnchor.From.RowOff}
+	}
+	if cellAnchor.To != nil {
+		deAnchor.To = &decodeTo{Col: cellAnchor.To.Col, ColOff: cellAnchor.To.ColOff, Row: cellAnchor.To.Row, RowOff: cellAnchor.To.RowOff}
+	}
+	if cellAnchor.Ext != nil {
+		deAnchor.Ext = &decodeExt{Cx: cellAnchor.Ext.Cx, Cy: cellAnchor.Ext.Cy}
+	}
+	if cellAnchor.ClientData != nil {
+		deAnchor.ClientData = &decodeClientData{FLocksWithSheet: boolPtr(cellAnchor.ClientData.FLocksWithSheet), FPrintsWithSheet: boolPtr(cellAnchor.ClientData.FPrintsWithSheet)}
+	}
+	if cellAnchor.Sp != nil {
+		deAnchor.Sp = &decodeSp{NvSpPr: &decodeNvSpPr{CNvPr: &decodeCNvPr{}}}
+		if cellAnchor.Sp.NvSpPr != nil && cellAnchor.Sp.NvSpPr.CNvPr != nil {
+			deAnchor.Sp.NvSpPr.CNvPr.ID = cellAnchor.Sp.NvSpPr.CNvPr.ID
+			deAnchor.Sp.NvSpPr.CNvPr.Name = cellAnchor.Sp.NvSpPr.CNvPr.Name
+			if cellAnchor.Sp.NvSpPr.CNvPr.HlinkClick != nil {
+				deAnchor.Sp.NvSpPr.CNvPr.HlinkClick = &decodeHlinkClick{RID: cellAnchor.Sp.NvSpPr.CNvPr.HlinkClick.RID}
+			}
+		}
+	}
+	return deAnchor, nil
+}
+
+// getDrawingObjectType provides a function to get the type of the drawing
+// object by given decoded cell anchor, the "Pic", "Chart", "Shape" or "Group"
+// will be returned, and an empty string will be returned for the other
+// objects.
+func getDrawingObjectType(deAnchor *decodeTwoCellAnchor) string {
+	switch {
+	case deAnchor.Pic != nil:
+		return "Pic"
+	case deAnchor.GraphicFrame != nil && deAnchor.GraphicFrame.Graphic.GraphicData.Chart != nil:
+		return "Chart"
+	case deAnchor.Sp != nil:
+		return "Shape"
+	case deAnchor.GrpSp != nil:
+		return "Group"
+	}
+	return ""
+}
+
+// getDrawingObjectCNvPr provides a function to get the non-visual drawing
+// properties of the drawing object by given decoded cell anchor.
+func getDrawingObjectCNvPr(deAnchor *decodeTwoCellAnchor) *decodeCNvPr {
+	switch {
+	case deAnchor.Pic != nil:
+		return &deAnchor.Pic.NvPicPr.CNvPr
+	case deAnchor.GraphicFrame != nil:
+		return &deAnchor.GraphicFrame.NvGraphicFramePr.CNvPr
+	case deAnchor.Sp != nil && deAnchor.Sp.NvSpPr != nil && deAnchor.Sp.NvSpPr.CNvPr != nil:
+		return deAnchor.Sp.NvSpPr.CNvPr
+	case deAnchor.GrpSp != nil:
+		return &deAnchor.GrpSp.NvGrpSpPr.CNvPr
+	}
+	return nil
+}
+
+// getDrawingObjectName provides a function to get the name of the drawing
+// object by given decoded cell anchor.
+func getDrawingObjectName(deAnchor *decodeTwoCellAnchor) string {
+	if cNvPr := getDrawingObjectCNvPr(deAnchor); cNvPr != nil {
+		return cNvPr.Name
+	}
+	return ""
+}
+
+// getDrawingObjectRIDs provides a function to get the relationship IDs used
+// by the drawing object by given decoded cell anchor.
+func getDrawingObjectRIDs(deAnchor *decodeTwoCellAnchor) []string {
+	var rIDs []string
+	addHlink := func(cNvPr *decodeCNvPr) {
+		if cNvPr != nil && cNvPr.HlinkClick != nil {
+			rIDs = append(rIDs, cNvPr.HlinkClick.RID)
+		}
+	}
+	if deAnchor.Pic != nil {
+		rIDs = append(rIDs, deAnchor.Pic.BlipFill.Blip.Embed)
+		addHlink(&deAnchor.Pic.NvPicPr.CNvPr)
+	}
+	if deAnchor.GraphicFrame != nil {
+		if chart := deAnchor.GraphicFrame.Graphic.GraphicData.Chart; chart != nil {
+			rIDs = append(rIDs, chart.RID)
+		}
+		addHlink(&deAnchor.GraphicFrame.NvGraphicFramePr.CNvPr)
+	}
+	if deAnchor.Sp != nil && deAnchor.Sp.NvSpPr != nil {
+		addHlink(deAnchor.Sp.NvSpPr.CNvPr)
+	}
+	if deAnchor.GrpSp != nil {
+		addHlink(&deAnchor.GrpSp.NvGrpSpPr.CNvPr)
+		for _, sp := range deAnchor.GrpSp.Sp {
+			rIDs = append(rIDs, getDrawingObjectRIDs(&decodeTwoCellAnchor{Sp: sp})...)
+		}
+		for _, pic := range deAnchor.GrpSp.Pic {
+			rIDs = append(rIDs, getDrawingObjectRIDs(&decodeTwoCellAnchor{Pic: pic})...)
+		}
+		for _, graphicFrame := range deAnchor.GrpSp.GraphicFrame {
+			rIDs = append(rIDs, getDrawingObjectRIDs(&decodeTwoCellAnchor{GraphicFrame: graphicFrame})...)
+		}
+		for _, grpSp := range deAnchor.GrpSp.GrpSp {
+			rIDs = append(rIDs, getDrawingObjectRIDs(&decodeTwoCellAnchor{GrpSp: grpSp})...)
+		}
+	}
+	return rIDs
+}
+
+// getPartRelsPath provides a function to get the path of the relationships
+// part by given part path, for example, the relationships part of the
+// xl/drawings/drawing1.xml is xl/drawings/_rels/drawing1.xml.rels.
+func getPartRelsPath(part string) string {
+	return path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+}
+
+// getRelsTargetPath provides a function to get the path of the part in the
+// package by given relationships part path and the target of the
+// relationship.
+func getRelsTargetPath(relsPath, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(path.Dir(relsPath)), target)
+}
+
+// deleteUnusedRels provides a function to delete the relationships by given
+// part path and relationship IDs when they are not used in the content of
+// the part any more, and delete the target parts which are no longer
+// referenced by any relationships in the package.
+func (f *File) deleteUnusedRels(part string, rIDs []string, content string) {
+	relsPath := getPartRelsPath(part)
+	rels := f.relsReader(relsPath)
+	if rels == nil {
+		return
+	}
+	var targets []string
+	rels.Lock()
+	for idx := 0; idx < len(rels.Relationships); idx++ {
+		rel := rels.Relationships[idx]
+		if inStrSlice(rIDs, rel.ID) == -1 || strings.Contains(content, "\""+rel.ID+"\"") {
+			continue
+		}
+		if rel.TargetMode != "External" {
+			targets = append(targets, getRelsTargetPath(relsPath, rel.Target))
+		}
+		rels.Relationships = append(rels.Relationships[:idx], rels.Relationships[idx+1:]...)
+		idx--
+	}
+	rels.Unlock()
+	for _, target := range targets {
+		f.deleteUnusedPart(target)
+	}
+}
+
+// deleteUnusedPart provides a function to delete the part and the parts
+// which were only used by it, when the part is no longer referenced by any
+// relationships in the package.
+func (f *File) deleteUnusedPart(part string) {
+	if _, ok := f.Pkg.Load(part); !ok {
+		return
+	}
+	if f.isPartReferenced(part) {
+		return
+	}
+	f.Pkg.Delete(part)
+	f.Drawings.Delete(part)
+	f.deleteSheetFromContentTypes("/" + part)
+	relsPath := getPartRelsPath(part)
+	var targets []string
+	if rels := f.relsReader(relsPath); rels != nil {
+		rels.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				targets = append(targets, getRelsTargetPath(relsPath, rel.Target))
+			}
+		}
+		rels.Unlock()
+	}
+	f.Pkg.Delete(relsPath)
+	f.Relationships.Delete(relsPath)
+	for _, target := range targets {
+		f.deleteUnusedPart(target)
+	}
+}
+
+// isPartReferenced provides a function to check if the part is referenced
+// by any relationships in the package.
+func (f *File) isPartReferenced(part string) bool {
+	relsPaths := map[string]bool{}
+	for _, m := range []*sync.Map{&f.Pkg, &f.Relationships} {
+		m.Range(func(k, v interface{}) bool {
+			if strings.HasSuffix(k.(string), ".rels") {
+				relsPaths[k.(string)] = true
+			}
+			return true
+		})
+	}
+	for relsPath := range relsPaths {
+		rels := f.relsReader(relsPath)
+		if rels == nil {
+			continue
+		}
+		rels.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" && getRelsTargetPath(relsPath, rel.Target) == part {
+				rels.Unlock()
+				return true
+			}
+		}
+		rels.Unlock()
+	}
+	return false
+}
+
+// SetDrawingZOrder provides a function to change the z-order of the drawing
+// object, including charts, pictures, shapes and groups in a worksheet by
+// given worksheet name, drawing object name and z-order action. The z-order
+// of the drawing object will be changed among the drawing objects with the
+// same kind of anchor. For example, bring the picture named "Logo" to the
+// front of other drawing objects in Sheet1:
+//
+//    err := f.SetDrawingZOrder("Sheet1", "Logo", excelize.ZOrderBringToFront)
+//
+func (f *File) SetDrawingZOrder(sheet, name string, order ZOrderAction) error {
+	drawingXML, err := f.getSheetDrawingXML(sheet)
+	if err != nil {
+		return err
+	}
+	if drawingXML == "" {
+		return ErrObjectNotExist
+	}
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	cellAnchors, idx, err := f.getDrawingCellAnchor(wsDr, name)
+	if err != nil {
+		return err
+	}
+	anchors, cellAnchor := *cellAnchors, (*cellAnchors)[idx]
+	switch order {
+	case ZOrderBringToFront:
+		anchors = append(append(anchors[:idx:idx], anchors[idx+1:]...), cellAnchor)
+	case ZOrderBringForward:
+		if idx < len(anchors)-1 {
+			anchors[idx], anchors[idx+1] = anchors[idx+1], anchors[idx]
+		}
+	case ZOrderSendBackward:
+		if idx > 0 {
+			anchors[idx], anchors[idx-1] = anchors[idx-1], anchors[idx]
+		}
+	case ZOrderSendToBack:
+		anchors = append([]*xdrCellAnchor{cellAnchor}, append(anchors[:idx:idx], anchors[idx+1:]...)...)
+	default:
+		return ErrParameterInvalid
+	}
+	*cellAnchors = anchors
+	return err
+}
+
+// GroupDrawingObjects provides a function to group the drawing objects,
+// including charts, pictures, shapes and groups in a worksheet by given
+// worksheet name, the name of the group and the names of the drawing
+// objects. The group will be placed at the z-order of the front most
+// drawing object in the group, and the grouped drawing objects could be
+// moved, deleted and changed the z-order together by the name of the group.
+// For example, group the picture named "Logo" and the shape named "Title"
+// into the group named "Header" in Sheet1:
+//
+//    err := f.GroupDrawingObjects("Sheet1", "Header", []string{"Logo", "Title"})
+//
+func (f *File) GroupDrawingObjects(sheet, name string, objects []string) error {
+	if name == "" {
+		return ErrParameterRequired
+	}
+	if len(objects) < 2 {
+		return ErrGroupDrawingObjects
+	}
+	drawingXML, err := f.getSheetDrawingXML(sheet)
+	if err != nil {
+		return err
+	}
+	if drawingXML == "" {
+		return ErrObjectNotExist
+	}
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	members := map[*xdrCellAnchor]bool{}
+	for _, object := range objects {
+		cellAnchors, idx, err := f.getDrawingCellAnchor(wsDr, object)
+		if err != nil {
+			return err
+		}
+		members[(*cellAnchors)[idx]] = true
+	}
+	if len(members) < 2 {
+		return ErrGroupDrawingObjects
+	}
+	if _, _, err = f.getDrawingCellAnchor(wsDr, name); err != ErrObjectNotExist {
+		if err == nil {
+			return ErrObjectNameDuplicate
+		}
+		return err
+	}
 	var (
-		wsDr            *xlsxWsDr
-		deTwoCellAnchor *decodeTwoCellAnchor
+		children       strings.Builder
+		editAs         string
+		id, insert     = getDrawingMaxCNvPrID(wsDr) + 1, -1
+		grouped        int
+		x1, y1, x2, y2 int
 	)
-	xdrCellAnchorFuncs := map[string]func(anchor *xdrCellAnchor) bool{
-		"Chart": func(anchor *xdrCellAnchor) bool { return anchor.Pic == nil },
-		"Pic":   func(anchor *xdrCellAnchor) bool { return anchor.Pic != nil },
-	}
-	decodeTwoCellAnchorFuncs := map[string]func(anchor *decodeTwoCellAnchor) bool{
-		"Chart": func(anchor *decodeTwoCellAnchor) bool { return anchor.Pic == nil },
-		"Pic":   func(anchor *decodeTwoCellAnchor) bool { return anchor.Pic != nil },
-	}
-	wsDr, _ = f.drawingParser(drawingXML)
-	for idx := 0; idx < len(wsDr.TwoCellAnchor); idx++ {
-		if err = nil; wsDr.TwoCellAnchor[idx].From != nil && xdrCellAnchorFuncs[drawingType](wsDr.TwoCellAnchor[idx]) {
-			if wsDr.TwoCellAnchor[idx].From.Col == col && wsDr.TwoCellAnchor[idx].From.Row == row {
-				wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor[:idx], wsDr.TwoCellAnchor[idx+1:]...)
-				idx--
+	for _, cellAnchors := range []*[]*xdrCellAnchor{&wsDr.AbsoluteAnchor, &wsDr.OneCellAnchor, &wsDr.TwoCellAnchor} {
+		for idx := 0; idx < len(*cellAnchors); idx++ {
+			cellAnchor := (*cellAnchors)[idx]
+			if !members[cellAnchor] {
+				continue
+			}
+			elements, err := splitDrawingElements(getCellAnchorContent(cellAnchor))
+			if err != nil {
+				return err
+			}
+			left, top, right, bottom := f.getDrawingElementsBounds(sheet, elements)
+			var child string
+			for _, element := range elements {
+				if _, ok := drawingAnchorElements[element.name]; !ok {
+					child += setDrawingObjectXfrm(element.content, left, top, right-left, bottom-top)
+				}
+			}
+			if grouped++; grouped == 1 {
+				x1, y1, x2, y2 = left, top, right, bottom
+			}
+			if left < x1 {
+				x1 = left
+			}
+			if top < y1 {
+				y1 = top
 			}
+			if right > x2 {
+				x2 = right
+			}
+			if bottom > y2 {
+				y2 = bottom
+			}
+			if cellAnchors == &wsDr.TwoCellAnchor {
+				editAs, insert = cellAnchor.EditAs, idx
+			}
+			children.WriteString(child)
+			*cellAnchors = append((*cellAnchors)[:idx], (*cellAnchors)[idx+1:]...)
+			idx--
 		}
 	}
-	for idx := 0; idx < len(wsDr.TwoCellAnchor); idx++ {
-		deTwoCellAnchor = new(decodeTwoCellAnchor)
-		if err = f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + wsDr.TwoCellAnchor[idx].GraphicFrame + "</decodeTwoCellAnchor>")).
-			Decode(deTwoCellAnchor); err != nil && err != io.EOF {
-			err = fmt.Errorf("xml decode error: %s", err)
-			return
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(name))
+	group := &xdrCellAnchor{
+		EditAs: editAs,
+		GraphicFrame: f.getDrawingAnchorFromTo(sheet, x1, y1, x2, y2) +
+			fmt.Sprintf(`<xdr:grpSp><xdr:nvGrpSpPr><xdr:cNvPr id="%d" name="%s"/><xdr:cNvGrpSpPr/></xdr:nvGrpSpPr>`+
+				`<xdr:grpSpPr><a:xfrm><a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/><a:chOff x="%d" y="%d"/><a:chExt cx="%d" cy="%d"/></a:xfrm></xdr:grpSpPr>`,
+				id, buf.String(), x1, y1, x2-x1, y2-y1, x1, y1, x2-x1, y2-y1) +
+			children.String() + `</xdr:grpSp><xdr:clientData/>`,
+	}
+	if insert == -1 || insert > len(wsDr.TwoCellAnchor) {
+		insert = len(wsDr.TwoCellAnchor)
+	}
+	wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor[:insert], append([]*xdrCellAnchor{group}, wsDr.TwoCellAnchor[insert:]...)...)
+	return nil
+}
+
+// UngroupDrawingObjects provides a function to ungroup the group of drawing
+// objects in a worksheet by given worksheet name and the name of the group.
+// The drawing objects in the group will be placed at the z-order of the
+// group. For example, ungroup the group named "Header" in Sheet1:
+//
+//    err := f.UngroupDrawingObjects("Sheet1", "Header")
+//
+func (f *File) UngroupDrawingObjects(sheet, name string) error {
+	drawingXML, err := f.getSheetDrawingXML(sheet)
+	if err != nil {
+		return err
+	}
+	if drawingXML == "" {
+		return ErrObjectNotExist
+	}
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	cellAnchors, idx, err := f.getDrawingCellAnchor(wsDr, name)
+	if err != nil {
+		return err
+	}
+	cellAnchor := (*cellAnchors)[idx]
+	elements, err := splitDrawingElements(getCellAnchorContent(cellAnchor))
+	if err != nil {
+		return err
+	}
+	var group *drawingElement
+	for i := range elements {
+		if elements[i].name == "grpSp" {
+			group = &elements[i]
 		}
-		if err = nil; deTwoCellAnchor.From != nil && decodeTwoCellAnchorFuncs[drawingType](deTwoCellAnchor) {
-			if deTwoCellAnchor.From.Col == col && deTwoCellAnchor.From.Row == row {
-				wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor[:idx], wsDr.TwoCellAnchor[idx+1:]...)
-				idx--
+	}
+	if group == nil {
+		return ErrNotGroupObject
+	}
+	var members []drawingElement
+	if start, end := strings.Index(group.content, ">")+1, strings.LastIndex(group.content, "<"); start < end {
+		if members, err = splitDrawingElements(group.content[start:end]); err != nil {
+			return err
+		}
+	}
+	var (
+		groupXfrm, anchors = drawingXfrm{}, []*xdrCellAnchor{}
+		scale              = func(v, off, chOff, ext, chExt int) int {
+			if chExt == 0 {
+				return off + v - chOff
 			}
+			return off + int(float64(v-chOff)*float64(ext)/float64(chExt))
+		}
+	)
+	for _, member := range members {
+		if member.name == "grpSpPr" {
+			groupXfrm = getDrawingObjectXfrm(member.content)
 		}
 	}
-	f.Drawings.Store(drawingXML, wsDr)
+	for _, member := range members {
+		if member.name == "nvGrpSpPr" || member.name == "grpSpPr" {
+			continue
+		}
+		xfrm := getDrawingObjectXfrm(member.content)
+		x1 := scale(xfrm.x, groupXfrm.x, groupXfrm.chX, groupXfrm.cx, groupXfrm.chCx)
+		y1 := scale(xfrm.y, groupXfrm.y, groupXfrm.chY, groupXfrm.cy, groupXfrm.chCy)
+		x2 := scale(xfrm.x+xfrm.cx, groupXfrm.x, groupXfrm.chX, groupXfrm.cx, groupXfrm.chCx)
+		y2 := scale(xfrm.y+xfrm.cy, groupXfrm.y, groupXfrm.chY, groupXfrm.cy, groupXfrm.chCy)
+		anchors = append(anchors, &xdrCellAnchor{
+			EditAs: cellAnchor.EditAs,
+			GraphicFrame: f.getDrawingAnchorFromTo(sheet, x1, y1, x2, y2) +
+				setDrawingObjectXfrm(member.content, x1, y1, x2-x1, y2-y1) + `<xdr:clientData/>`,
+		})
+	}
+	if cellAnchors != &wsDr.TwoCellAnchor {
+		*cellAnchors = append((*cellAnchors)[:idx], (*cellAnchors)[idx+1:]...)
+		wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor, anchors...)
+		return err
+	}
+	*cellAnchors = append((*cellAnchors)[:idx], append(anchors, (*cellAnchors)[idx+1:]...)...)
 	return err
 }
+
+// getSheetDrawingXML provides a function to get the path of the drawing part
+// of the worksheet by given worksheet name, an empty path will be returned
+// if the worksheet doesn't have any drawing objects.
+func (f *File) getSheetDrawingXML(sheet string) (string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.Drawing == nil {
+		return "", err
+	}
+	return strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl", -1), err
+}
+
+// getDrawingCellAnchor provides a function to get the cell anchors slice and
+// the index of the cell anchor of the drawing object by given drawing object
+// name in the drawing part.
+func (f *File) getDrawingCellAnchor(wsDr *xlsxWsDr, name string) (*[]*xdrCellAnchor, int, error) {
+	for _, cellAnchors := range []*[]*xdrCellAnchor{&wsDr.AbsoluteAnchor, &wsDr.OneCellAnchor, &wsDr.TwoCellAnchor} {
+		for idx, cellAnchor := range *cellAnchors {
+			deAnchor, err := f.decodeDrawingAnchor(cellAnchor)
+			if err != nil {
+				return cellAnchors, idx, err
+			}
+			if cNvPr := getDrawingObjectCNvPr(deAnchor); cNvPr != nil && cNvPr.Name == name {
+				return cellAnchors, idx, err
+			}
+		}
+	}
+	return nil, -1, ErrObjectNotExist
+}
+
+// drawingElement defined the raw XML content of an element in the drawing
+// part with the local name of the element.
+type drawingElement struct {
+	name, content string
+}
+
+// drawingAnchorElements defined the local names of the elements which
+// specifies the position of the drawing object in the cell anchor.
+var drawingAnchorElements = map[string]struct{}{"pos": {}, "from": {}, "to": {}, "ext": {}, "clientData": {}}
+
+// getCellAnchorContent provides a function to get the raw XML content of the
+// cell anchor inside the cell anchor element, the properties of the anchor
+// which have been created in memory will be serialized into the content.
+func getCellAnchorContent(cellAnchor *xdrCellAnchor) string {
+	output, _ := xml.Marshal(&xdrCellAnchor{
+		Pos: cellAnchor.Pos, From: cellAnchor.From, To: cellAnchor.To, Ext: cellAnchor.Ext, Sp: cellAnchor.Sp,
+		Pic: cellAnchor.Pic, GraphicFrame: cellAnchor.GraphicFrame, ClientData: cellAnchor.ClientData,
+	})
+	content := string(output)
+	return content[strings.Index(content, ">")+1 : strings.LastIndex(content, "<")]
+}
+
+// splitDrawingElements provides a function to split the raw XML content into
+// the top level elements.
+func splitDrawingElements(content string) ([]drawingElement, error) {
+	var (
+		elements []drawingElement
+		depth    int
+		start    int64
+		name     string
+		decoder  = xml.NewDecoder(strings.NewReader(content))
+	)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return elements, fmt.Errorf("xml decode error: %s", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				start, name = offset, element.Name.Local
+			}
+			depth++
+		case xml.EndElement:
+			if depth--; depth == 0 {
+				elements = append(elements, drawingElement{name: name, content: content[start:decoder.InputOffset()]})
+			}
+		}
+	}
+	return elements, nil
+}
+
+// getDrawingElementsBounds provides a function to get the bounds of the
+// drawing object in EMUs by given worksheet name and the elements of the
+// cell anchor.
+func (f *File) getDrawingElementsBounds(sheet string, elements []drawingElement) (x1, y1, x2, y2 int) {
+	var deAnchor decodeTwoCellAnchor
+	for _, element := range elements {
+		if _, ok := drawingAnchorElements[element.name]; ok {
+			_ = xml.Unmarshal([]byte("<decodeTwoCellAnchor>"+element.content+"</decodeTwoCellAnchor>"), &deAnchor)
+		}
+	}
+	if deAnchor.Pos != nil {
+		x1, y1 = deAnchor.Pos.X, deAnchor.Pos.Y
+	}
+	if deAnchor.From != nil {
+		x1 = f.getColOffsetEMU(sheet, deAnchor.From.Col) + deAnchor.From.ColOff
+		y1 = f.getRowOffsetEMU(sheet, deAnchor.From.Row) + deAnchor.From.RowOff
+	}
+	x2, y2 = x1, y1
+	if deAnchor.Ext != nil {
+		x2, y2 = x1+deAnchor.Ext.Cx, y1+deAnchor.Ext.Cy
+	}
+	if deAnchor.To != nil {
+		x2 = f.getColOffsetEMU(sheet, deAnchor.To.Col) + deAnchor.To.ColOff
+		y2 = f.getRowOffsetEMU(sheet, deAnchor.To.Row) + deAnchor.To.RowOff
+	}
+	return
+}
+
+// getColOffsetEMU provides a function to get the horizontal offset in EMUs
+// of the left edge of the column by given worksheet name and zero-based
+// column index.
+func (f *File) getColOffsetEMU(sheet string, col int) int {
+	var offset int
+	for c := 1; c <= col; c++ {
+		offset += f.getColWidth(sheet, c) * EMU
+	}
+	return offset
+}
+
+// getRowOffsetEMU provides a function to get the vertical offset in EMUs of
+// the top edge of the row by given worksheet name and zero-based row index.
+func (f *File) getRowOffsetEMU(sheet string, row int) int {
+	var offset int
+	for r := 1; r <= row; r++ {
+		offset += f.getRowHeight(sheet, r) * EMU
+	}
+	return offset
+}
+
+// getDrawingAnchorFromTo provides a function to get the start and end
+// anchors XML of the two cell anchor by given worksheet name and the bounds
+// of the drawing object in EMUs.
+func (f *File) getDrawingAnchorFromTo(sheet string, x1, y1, x2, y2 int) string {
+	position := func(offset int, size func(int) int, max int) (idx, off int) {
+		for idx = 0; idx < max-1 && offset >= size(idx+1)*EMU; idx++ {
+			offset -= size(idx+1) * EMU
+		}
+		return idx, offset
+	}
+	colWidth := func(col int) int { return f.getColWidth(sheet, col) }
+	rowHeight := func(row int) int { return f.getRowHeight(sheet, row) }
+	fromCol, fromColOff := position(x1, colWidth, TotalColumns)
+	fromRow, fromRowOff := position(y1, rowHeight, TotalRows)
+	toCol, toColOff := position(x2, colWidth, TotalColumns)
+	toRow, toRowOff := position(y2, rowHeight, TotalRows)
+	from, _ := xml.Marshal(xlsxFrom{Col: fromCol, ColOff: fromColOff, Row: fromRow, RowOff: fromRowOff})
+	to, _ := xml.Marshal(xlsxTo{Col: toCol, ColOff: toColOff, Row: toRow, RowOff: toRowOff})
+	return strings.Replace(string(from), "xlsxFrom>", "xdr:from>", 2) + strings.Replace(string(to), "xlsxTo>", "xdr:to>", 2)
+}
+
+// drawingXfrm defined the 2D transform of the drawing object in EMUs, the
+// child offset and extents are only used for the group.
+type drawingXfrm struct {
+	x, y, cx, cy, chX, chY, chCx, chCy int
+}
+
+var (
+	// xfrmRegexp defined the regular expression to match the first 2D
+	// transform element of the drawing object.
+	xfrmRegexp = regexp.MustCompile(`(?s)<((?:[\w-]+:)?)xfrm(\s[^>]*?)?(?:/>|>(.*?)</(?:[\w-]+:)?xfrm>)`)
+	// spPrRegexp defined the regular expression to match the shape properties
+	// element of the drawing object.
+	spPrRegexp = regexp.MustCompile(`<((?:[\w-]+:)?)spPr(\s[^>]*?)?(/?)>`)
+	// xfrmChildRegexp defined the regular expression to match the child
+	// elements of the 2D transform element.
+	xfrmChildRegexp = regexp.MustCompile(`<(?:[\w-]+:)?(off|ext|chOff|chExt)\s([^>]*?)(?:/>|>\s*</(?:[\w-]+:)?(?:off|ext|chOff|chExt)>)`)
+	// xfrmAttrRegexp defined the regular expression to match the attributes
+	// of the child elements of the 2D transform element.
+	xfrmAttrRegexp = regexp.MustCompile(`\b(x|y|cx|cy)="(-?\d+)"`)
+)
+
+// getDrawingObjectXfrm provides a function to get the 2D transform of the
+// drawing object by given raw XML content of the drawing object.
+func getDrawingObjectXfrm(content string) drawingXfrm {
+	var xfrm drawingXfrm
+	match := xfrmRegexp.FindStringSubmatch(content)
+	if match == nil {
+		return xfrm
+	}
+	for _, child := range xfrmChildRegexp.FindAllStringSubmatch(match[3], -1) {
+		for _, attr := range xfrmAttrRegexp.FindAllStringSubmatch(child[2], -1) {
+			val, _ := strconv.Atoi(attr[2])
+			if field, ok := map[string]*int{
+				"off.x": &xfrm.x, "off.y": &xfrm.y, "ext.cx": &xfrm.cx, "ext.cy": &xfrm.cy,
+				"chOff.x": &xfrm.chX, "chOff.y": &xfrm.chY, "chExt.cx": &xfrm.chCx, "chExt.cy": &xfrm.chCy,
+			}[child[1]+"."+attr[1]]; ok {
+				*field = val
+			}
+		}
+	}
+	return xfrm
+}
+
+// setDrawingObjectXfrm provides a function to set the offset and extents of
+// the 2D transform of the drawing object by given raw XML content of the
+// drawing object, the other properties of the transform will be kept.
+func setDrawingObjectXfrm(content string, x, y, cx, cy int) string {
+	offExt := fmt.Sprintf(`<a:off x="%d" y="%d"/><a:ext cx="%d" cy="%d"/>`, x, y, cx, cy)
+	if loc := xfrmRegexp.FindStringSubmatchIndex(content); loc != nil {
+		prefix, attrs, inner := content[loc[2]:loc[3]], "", ""
+		if loc[4] != -1 {
+			attrs = content[loc[4]:loc[5]]
+		}
+		if loc[6] != -1 {
+			inner = xfrmChildRegexp.ReplaceAllStringFunc(content[loc[6]:loc[7]], func(child string) string {
+				if name := xfrmChildRegexp.FindStringSubmatch(child)[1]; name == "off" || name == "ext" {
+					return ""
+				}
+				return child
+			})
+		}
+		return content[:loc[0]] + "<" + prefix + "xfrm" + attrs + ">" + offExt + inner + "</" + prefix + "xfrm>" + content[loc[1]:]
+	}
+	if loc := spPrRegexp.FindStringSubmatchIndex(content); loc != nil {
+		prefix, attrs := content[loc[2]:loc[3]], ""
+		if loc[4] != -1 {
+			attrs = content[loc[4]:loc[5]]
+		}
+		xfrm := "<a:xfrm>" + offExt + "</a:xfrm>"
+		if loc[6] != loc[7] {
+			return content[:loc[0]] + "<" + prefix + "spPr" + attrs + ">" + xfrm + "</" + prefix + "spPr>" + content[loc[1]:]
+		}
+		return content[:loc[1]] + xfrm + content[loc[1]:]
+	}
+	return content
+}
+
+// getDrawingMaxCNvPrID provides a function to get the maximum identifier of
+// the non-visual drawing properties in the drawing part.
+func getDrawingMaxCNvPrID(wsDr *xlsxWsDr) int {
+	var maxID int
+	content, _ := xml.Marshal(wsDr)
+	for _, match := range cNvPrIDRegexp.FindAllStringSubmatch(string(content), -1) {
+		if id, _ := strconv.Atoi(match[1]); id > maxID {
+			maxID = id
+		}
+	}
+	return maxID
+}
+
+// cNvPrIDRegexp defined the regular expression to match the id attribute of
+// the non-visual drawing properties in the drawing part.
+var cNvPrIDRegexp = regexp.MustCompile(`<(?:[\w-]+:)?cNvPr\s[^>]*?\bid="(\d+)"`)
@@ -12,8 +12,12 @@
 package excelize
 
 import (
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDrawingParser(t *testing.T) {
@@ -28,3 +32,284 @@ func TestDrawingParser(t *testing.T) {
 	// Test with unsupported charset
 	f.drawingParser("charset")
 }
+
+func TestDeleteDrawing(t *testing.T) {
+	f := NewFile()
+	chart := `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`
+	assert.NoError(t, f.AddChart("Sheet1", "E1", chart))
+	assert.NoError(t, f.AddChart("Sheet1", "E20", chart))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), `{"hyperlink":"https://github.com/xuri/excelize","hyperlink_type":"External"}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "A20", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddShape("Sheet1", "M1", `{"type":"rect","paragraph":[{"text":"Rectangle"}]}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteDrawing.xlsx")))
+
+	for _, inMemory := range []bool{true, false} {
+		if !inMemory {
+			var err error
+			f, err = OpenFile(filepath.Join("test", "TestDeleteDrawing.xlsx"))
+			assert.NoError(t, err)
+		}
+		rels := f.relsReader("xl/drawings/_rels/drawing1.xml.rels")
+		assert.Len(t, rels.Relationships, 5)
+		// Test delete chart, the chart part should be deleted.
+		assert.NoError(t, f.DeleteChart("Sheet1", "E1"))
+		_, ok := f.Pkg.Load("xl/charts/chart1.xml")
+		assert.False(t, ok)
+		_, ok = f.Pkg.Load("xl/charts/chart2.xml")
+		assert.True(t, ok)
+		for _, override := range f.contentTypesReader().Overrides {
+			assert.NotEqual(t, "/xl/charts/chart1.xml", override.PartName)
+		}
+		assert.Len(t, rels.Relationships, 4)
+		// Test delete picture, the image which is still used should be kept.
+		assert.NoError(t, f.DeletePicture("Sheet1", "A1"))
+		_, ok = f.Pkg.Load("xl/media/image1.png")
+		assert.True(t, ok)
+		assert.Len(t, rels.Relationships, 2)
+		anchors, err := f.GetPictureAnchors("Sheet1")
+		assert.NoError(t, err)
+		assert.Len(t, anchors, 1)
+		// Test delete picture by name, the unused image should be deleted.
+		assert.NoError(t, f.DeleteDrawing("Sheet1", anchors[0].Name))
+		_, ok = f.Pkg.Load("xl/media/image1.png")
+		assert.False(t, ok)
+		assert.Len(t, rels.Relationships, 1)
+		// Test delete chart on the cell of the shape.
+		assert.NoError(t, f.DeleteChart("Sheet1", "M1"))
+		wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+		assert.Len(t, wsDr.TwoCellAnchor, 2)
+		assert.NoError(t, f.DeleteShape("Sheet1", "M1"))
+		assert.Len(t, wsDr.TwoCellAnchor, 1)
+		// Test add chart after the chart part has been deleted.
+		assert.NoError(t, f.AddChart("Sheet1", "E1", chart))
+		_, ok = f.Pkg.Load("xl/charts/chart3.xml")
+		assert.True(t, ok)
+		assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteDrawing2.xlsx")))
+	}
+	// Test delete drawing on not exists worksheet.
+	assert.EqualError(t, f.DeleteDrawing("SheetN", "Chart 1"), "sheet SheetN is not exist")
+	// Test delete drawing on no drawing worksheet.
+	assert.NoError(t, NewFile().DeleteDrawing("Sheet1", "Chart 1"))
+	// Test delete drawing with invalid drawing part.
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	wsDr.TwoCellAnchor[0].GraphicFrame = strings.Repeat("<", 2)
+	assert.EqualError(t, f.DeleteDrawing("Sheet1", "Chart 1"), "xml decode error: XML syntax error on line 1: expected element name after <")
+}
+
+func TestDeleteShape(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddShape("Sheet1", "A1", `{"type":"rect"}`))
+	assert.NoError(t, f.DeleteShape("Sheet1", "A1"))
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.Len(t, wsDr.TwoCellAnchor, 0)
+	// Test delete shape on not exists worksheet.
+	assert.EqualError(t, f.DeleteShape("SheetN", "A1"), "sheet SheetN is not exist")
+	// Test delete shape with invalid coordinates.
+	assert.EqualError(t, f.DeleteShape("Sheet1", ""), `cannot convert cell "" to coordinates: invalid cell name ""`)
+	// Test delete shape on no drawing worksheet.
+	assert.NoError(t, NewFile().DeleteShape("Sheet1", "A1"))
+}
+
+func TestObjectName(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddShape("Sheet1", "M1", `{"type":"rect"}`))
+	for name, expected := range map[string]DrawingObject{
+		"Chart 2":   {ID: 2, Name: "Chart 2", Type: "chart", Cell: "E1"},
+		"Picture 3": {ID: 3, Name: "Picture 3", Type: "picture", Cell: "A1"},
+		"Shape 4":   {ID: 4, Name: "Shape 4", Type: "shape", Cell: "M1"},
+	} {
+		obj, err := f.GetObjectByName("Sheet1", name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, obj)
+	}
+	assert.NoError(t, f.SetObjectName("Sheet1", "Chart 2", `Sales "Q1" & Q2`))
+	assert.NoError(t, f.SetObjectName("Sheet1", "Picture 3", "Logo"))
+	assert.NoError(t, f.SetObjectName("Sheet1", "Shape 4", "Banner"))
+	assert.NoError(t, f.SetObjectName("Sheet1", "Banner", "Banner"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestObjectName.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestObjectName.xlsx"))
+	assert.NoError(t, err)
+	for name, expected := range map[string]DrawingObject{
+		`Sales "Q1" & Q2`: {ID: 2, Name: `Sales "Q1" & Q2`, Type: "chart", Cell: "E1"},
+		"Logo":            {ID: 3, Name: "Logo", Type: "picture", Cell: "A1"},
+		"Banner":          {ID: 4, Name: "Banner", Type: "shape", Cell: "M1"},
+	} {
+		obj, err := f.GetObjectByName("Sheet1", name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, obj)
+	}
+	// Test rename the drawing objects which are loaded from the file.
+	assert.NoError(t, f.SetObjectName("Sheet1", "Logo", "Picture"))
+	assert.NoError(t, f.SetObjectName("Sheet1", "Banner", "Shape"))
+	for _, name := range []string{"Picture", "Shape"} {
+		_, err = f.GetObjectByName("Sheet1", name)
+		assert.NoError(t, err)
+	}
+	// Test rename drawing object with the name which already exists.
+	assert.EqualError(t, f.SetObjectName("Sheet1", "Picture", "Shape"), ErrObjectNameDuplicate.Error())
+	// Test rename drawing object with empty name.
+	assert.EqualError(t, f.SetObjectName("Sheet1", "Picture", ""), ErrParameterRequired.Error())
+	// Test get and rename not exists drawing object.
+	_, err = f.GetObjectByName("Sheet1", "Chart 3")
+	assert.EqualError(t, err, ErrObjectNotExist.Error())
+	assert.EqualError(t, f.SetObjectName("Sheet1", "Chart 3", "Chart"), ErrObjectNotExist.Error())
+	assert.EqualError(t, NewFile().SetObjectName("Sheet1", "Chart 3", "Chart"), ErrObjectNotExist.Error())
+	// Test get and rename drawing object on not exists worksheet.
+	_, err = f.GetObjectByName("SheetN", "Chart 2")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetObjectName("SheetN", "Chart 2", "Chart"), "sheet SheetN is not exist")
+	// Test get drawing object with invalid drawing part.
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	wsDr.TwoCellAnchor[0].GraphicFrame = "<"
+	_, err = f.GetObjectByName("Sheet1", "Chart 2")
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: expected element name after <")
+}
+
+func TestSetDrawingZOrder(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddShape("Sheet1", "B2", `{"type":"rect"}`))
+	getNames := func() []string {
+		var names []string
+		wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+		for _, cellAnchor := range wsDr.TwoCellAnchor {
+			deAnchor, err := f.decodeDrawingAnchor(cellAnchor)
+			assert.NoError(t, err)
+			names = append(names, getDrawingObjectName(deAnchor))
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Chart 2", "Picture 3", "Shape 4"}, getNames())
+	for _, c := range []struct {
+		name     string
+		order    ZOrderAction
+		expected []string
+	}{
+		{"Chart 2", ZOrderBringToFront, []string{"Picture 3", "Shape 4", "Chart 2"}},
+		{"Chart 2", ZOrderBringToFront, []string{"Picture 3", "Shape 4", "Chart 2"}},
+		{"Picture 3", ZOrderBringForward, []string{"Shape 4", "Picture 3", "Chart 2"}},
+		{"Shape 4", ZOrderSendBackward, []string{"Shape 4", "Picture 3", "Chart 2"}},
+		{"Picture 3", ZOrderSendBackward, []string{"Picture 3", "Shape 4", "Chart 2"}},
+		{"Chart 2", ZOrderSendToBack, []string{"Chart 2", "Picture 3", "Shape 4"}},
+		{"Shape 4", ZOrderBringForward, []string{"Chart 2", "Picture 3", "Shape 4"}},
+	} {
+		assert.NoError(t, f.SetDrawingZOrder("Sheet1", c.name, c.order))
+		assert.Equal(t, c.expected, getNames())
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetDrawingZOrder.xlsx")))
+
+	// Test set z-order of the drawing object in the workbook opened from file.
+	f, err := OpenFile(filepath.Join("test", "TestSetDrawingZOrder.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetDrawingZOrder("Sheet1", "Shape 4", ZOrderSendToBack))
+	assert.Equal(t, []string{"Shape 4", "Chart 2", "Picture 3"}, getNames())
+	// Test set z-order with invalid action.
+	assert.EqualError(t, f.SetDrawingZOrder("Sheet1", "Shape 4", 4), ErrParameterInvalid.Error())
+	// Test set z-order of not exists drawing object.
+	assert.EqualError(t, f.SetDrawingZOrder("Sheet1", "Shape 5", ZOrderBringToFront), ErrObjectNotExist.Error())
+	assert.EqualError(t, NewFile().SetDrawingZOrder("Sheet1", "Shape 4", ZOrderBringToFront), ErrObjectNotExist.Error())
+	// Test set z-order on not exists worksheet.
+	assert.EqualError(t, f.SetDrawingZOrder("SheetN", "Shape 4", ZOrderBringToFront), "sheet SheetN is not exist")
+	// Test set z-order with invalid drawing part.
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	wsDr.TwoCellAnchor[0].GraphicFrame = strings.Repeat("<", 2)
+	assert.EqualError(t, f.SetDrawingZOrder("Sheet1", "Shape 4", ZOrderBringToFront), "xml decode error: XML syntax error on line 1: expected element name after <")
+}
+
+func TestGroupDrawingObjects(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "B", 20))
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), `{"hyperlink":"https://github.com/xuri/excelize","hyperlink_type":"External"}`))
+	assert.NoError(t, f.AddShape("Sheet1", "B2", `{"type":"rect"}`))
+	assert.NoError(t, f.AddShape("Sheet1", "M20", `{"type":"rect"}`))
+	assert.NoError(t, f.GroupDrawingObjects("Sheet1", "Group 1", []string{"Chart 2", "Shape 4"}))
+	obj, err := f.GetObjectByName("Sheet1", "Group 1")
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{ID: 6, Name: "Group 1", Type: "group", Cell: "B1"}, obj)
+	_, err = f.GetObjectByName("Sheet1", "Chart 2")
+	assert.EqualError(t, err, ErrObjectNotExist.Error())
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.Len(t, wsDr.TwoCellAnchor, 3)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `<xdr:grpSp><xdr:nvGrpSpPr><xdr:cNvPr id="6" name="Group 1"/>`)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `<xdr:graphicFrame macro="">`)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `<xdr:sp macro="" textlink="">`)
+	// Test group the drawing objects with group.
+	assert.NoError(t, f.GroupDrawingObjects("Sheet1", "Group 2", []string{"Group 1", "Picture 3", "Picture 3"}))
+	obj, err = f.GetObjectByName("Sheet1", "Group 2")
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{ID: 7, Name: "Group 2", Type: "group", Cell: "A1"}, obj)
+	assert.Len(t, wsDr.TwoCellAnchor, 2)
+	assert.NoError(t, f.SetDrawingZOrder("Sheet1", "Group 2", ZOrderBringToFront))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGroupDrawingObjects.xlsx")))
+
+	// Test ungroup the drawing objects in the workbook opened from file.
+	f, err = OpenFile(filepath.Join("test", "TestGroupDrawingObjects.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.UngroupDrawingObjects("Sheet1", "Group 2"))
+	wsDr, _ = f.drawingParser("xl/drawings/drawing1.xml")
+	assert.Len(t, wsDr.TwoCellAnchor, 3)
+	obj, err = f.GetObjectByName("Sheet1", "Picture 3")
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{ID: 3, Name: "Picture 3", Type: "picture", Cell: "A1"}, obj)
+	assert.NoError(t, f.UngroupDrawingObjects("Sheet1", "Group 1"))
+	for name, expected := range map[string]DrawingObject{
+		"Chart 2": {ID: 2, Name: "Chart 2", Type: "chart", Cell: "E1"},
+		"Shape 4": {ID: 4, Name: "Shape 4", Type: "shape", Cell: "B2"},
+		"Shape 5": {ID: 5, Name: "Shape 5", Type: "shape", Cell: "M20"},
+	} {
+		obj, err := f.GetObjectByName("Sheet1", name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, obj)
+	}
+	assert.Len(t, wsDr.TwoCellAnchor, 4)
+	// Test ungroup the drawing object which is not a group.
+	assert.EqualError(t, f.UngroupDrawingObjects("Sheet1", "Shape 4"), ErrNotGroupObject.Error())
+	// Test delete the group, the parts used by the drawing objects in the
+	// group should be deleted.
+	assert.NoError(t, f.GroupDrawingObjects("Sheet1", "Group 3", []string{"Chart 2", "Picture 3"}))
+	assert.NoError(t, f.DeleteDrawing("Sheet1", "Group 3"))
+	_, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.False(t, ok)
+	_, ok = f.Pkg.Load("xl/media/image1.png")
+	assert.False(t, ok)
+	assert.Len(t, f.relsReader("xl/drawings/_rels/drawing1.xml.rels").Relationships, 0)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGroupDrawingObjects2.xlsx")))
+
+	// Test group drawing objects with invalid parameters.
+	assert.EqualError(t, f.GroupDrawingObjects("Sheet1", "", []string{"Shape 4", "Shape 5"}), ErrParameterRequired.Error())
+	assert.EqualError(t, f.GroupDrawingObjects("Sheet1", "Group 4", []string{"Shape 4"}), ErrGroupDrawingObjects.Error())
+	assert.EqualError(t, f.GroupDrawingObjects("Sheet1", "Group 4", []string{"Shape 4", "Shape 4"}), ErrGroupDrawingObjects.Error())
+	assert.EqualError(t, f.GroupDrawingObjects("Sheet1", "Shape 4", []string{"Shape 4", "Shape 5"}), ErrObjectNameDuplicate.Error())
+	assert.EqualError(t, f.GroupDrawingObjects("Sheet1", "Group 4", []string{"Shape 4", "Shape 6"}), ErrObjectNotExist.Error())
+	assert.EqualError(t, NewFile().GroupDrawingObjects("Sheet1", "Group 4", []string{"Shape 4", "Shape 5"}), ErrObjectNotExist.Error())
+	assert.EqualError(t, f.GroupDrawingObjects("SheetN", "Group 4", []string{"Shape 4", "Shape 5"}), "sheet SheetN is not exist")
+	// Test ungroup drawing objects with invalid parameters.
+	assert.EqualError(t, f.UngroupDrawingObjects("Sheet1", "Group 4"), ErrObjectNotExist.Error())
+	assert.EqualError(t, NewFile().UngroupDrawingObjects("Sheet1", "Group 4"), ErrObjectNotExist.Error())
+	assert.EqualError(t, f.UngroupDrawingObjects("SheetN", "Group 4"), "sheet SheetN is not exist")
+}
+
+func TestDrawingObjectXfrm(t *testing.T) {
+	for _, c := range []struct {
+		content, expected string
+	}{
+		{`<xdr:sp><xdr:spPr><a:xfrm rot="60000"><a:off x="1" y="2"/><a:ext cx="3" cy="4"/></a:xfrm></xdr:spPr></xdr:sp>`, `<xdr:sp><xdr:spPr><a:xfrm rot="60000"><a:off x="10" y="20"/><a:ext cx="30" cy="40"/></a:xfrm></xdr:spPr></xdr:sp>`},
+		{`<xdr:grpSp><xdr:grpSpPr><a:xfrm><a:off x="1" y="2"/><a:ext cx="3" cy="4"/><a:chOff x="1" y="2"/><a:chExt cx="3" cy="4"/></a:xfrm></xdr:grpSpPr></xdr:grpSp>`, `<xdr:grpSp><xdr:grpSpPr><a:xfrm><a:off x="10" y="20"/><a:ext cx="30" cy="40"/><a:chOff x="1" y="2"/><a:chExt cx="3" cy="4"/></a:xfrm></xdr:grpSpPr></xdr:grpSp>`},
+		{`<xdr:graphicFrame><xdr:xfrm/></xdr:graphicFrame>`, `<xdr:graphicFrame><xdr:xfrm><a:off x="10" y="20"/><a:ext cx="30" cy="40"/></xdr:xfrm></xdr:graphicFrame>`},
+		{`<xdr:pic><xdr:spPr/></xdr:pic>`, `<xdr:pic><xdr:spPr><a:xfrm><a:off x="10" y="20"/><a:ext cx="30" cy="40"/></a:xfrm></xdr:spPr></xdr:pic>`},
+		{`<xdr:pic><xdr:spPr bwMode="auto"><a:prstGeom prst="rect"/></xdr:spPr></xdr:pic>`, `<xdr:pic><xdr:spPr bwMode="auto"><a:xfrm><a:off x="10" y="20"/><a:ext cx="30" cy="40"/></a:xfrm><a:prstGeom prst="rect"/></xdr:spPr></xdr:pic>`},
+		{`<xdr:cxnSp/>`, `<xdr:cxnSp/>`},
+	} {
+		assert.Equal(t, c.expected, setDrawingObjectXfrm(c.content, 10, 20, 30, 40))
+	}
+	assert.Equal(t, drawingXfrm{x: 1, y: 2, cx: 3, cy: 4, chX: 5, chY: 6, chCx: 7, chCy: 8},
+		getDrawingObjectXfrm(`<a:xfrm><a:off x="1" y="2"/><a:ext cx="3" cy="4"/><a:chOff x="5" y="6"/><a:chExt cx="7" cy="8"/></a:xfrm>`))
+	assert.Equal(t, drawingXfrm{}, getDrawingObjectXfrm(`<xdr:sp/>`))
+	_, err := splitDrawingElements("<a")
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: unexpected EOF")
+}
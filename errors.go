@@ -14,6 +14,7 @@ package excelize
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 func newInvalidColumnNameError(col string) error {
@@ -36,6 +37,40 @@ func newUnsupportChartType(chartType string) error {
 	return fmt.Errorf("unsupported chart type %s", chartType)
 }
 
+func newNoExistConnectionError(name string) error {
+	return fmt.Errorf("connection %s is not exist", name)
+}
+
+func newUnexpectedHTTPStatusError(status string) error {
+	return fmt.Errorf("unexpected HTTP status %s", status)
+}
+
+func newUnsupportedContentTypeError(contentType string) error {
+	return fmt.Errorf("unsupported content type %q of the spreadsheet", contentType)
+}
+
+func newInvalidStyleAttrError(attr, value string) error {
+	return fmt.Errorf("invalid %s %q", attr, value)
+}
+
+func newInvalidOptionalValue(name, value string, values []string) error {
+	return fmt.Errorf("invalid %s value %q, acceptable value should be one of %s", name, value, strings.Join(values, ", "))
+}
+
+// UnsafeNameError defined the error on receive the file name or the worksheet
+// name which would break on SharePoint, OneDrive or the other platforms. The
+// Suggestion is the safe alternative of the name.
+type UnsafeNameError struct {
+	Name       string
+	Reason     string
+	Suggestion string
+}
+
+// Error returns the error message of the unsafe name.
+func (err *UnsafeNameError) Error() string {
+	return fmt.Sprintf("the name %q %s, consider using %q instead", err.Name, err.Reason, err.Suggestion)
+}
+
 var (
 	// ErrStreamSetColWidth defined the error message on set column width in
 	// stream writing mode.
@@ -43,6 +78,18 @@ var (
 	// ErrColumnNumber defined the error message on receive an invalid column
 	// number.
 	ErrColumnNumber = errors.New("column number exceeds maximum limit")
+	// ErrOptionsOutOfBoundsCells defined the error message on receive an
+	// invalid out-of-bounds cells option.
+	ErrOptionsOutOfBoundsCells = errors.New("out-of-bounds cells option must be clamp or skip")
+	// ErrOptionsUnsafeNames defined the error message on receive an invalid
+	// unsafe names option.
+	ErrOptionsUnsafeNames = errors.New("unsafe names option must be error or sanitize")
+	// ErrMaxRows defined the error message on receive a row number exceeds
+	// maximum limit.
+	ErrMaxRows = errors.New("row number exceeds maximum limit")
+	// ErrCellCharsLength defined the error message for receiving a cell
+	// characters length that exceeds the limit.
+	ErrCellCharsLength = fmt.Errorf("cell value must be 0-%d characters", TotalCellChars)
 	// ErrColumnWidth defined the error message on receive an invalid column
 	// width.
 	ErrColumnWidth = errors.New("the width of the column must be smaller than or equal to 255 characters")
@@ -77,12 +124,30 @@ var (
 	ErrMaxFileNameLength = errors.New("file name length exceeds maximum limit")
 	// ErrEncrypt defined the error message on encryption spreadsheet.
 	ErrEncrypt = errors.New("not support encryption currently")
+	// ErrPasswordLength defined the error message on receive the empty
+	// password or the password length exceeds the limit on encryption.
+	ErrPasswordLength = fmt.Errorf("the password must be 1-%d characters", MaxPasswordLength)
+	// ErrEncryptHashAlgorithm defined the error message on receive an
+	// unsupported hash algorithm on encryption.
+	ErrEncryptHashAlgorithm = errors.New("the encryption hash algorithm must be SHA1, SHA256, SHA384 or SHA512")
+	// ErrEncryptKeyBits defined the error message on receive an invalid key
+	// bits of the cipher on encryption.
+	ErrEncryptKeyBits = errors.New("the encryption key bits must be 128, 192 or 256")
+	// ErrEncryptSpinCount defined the error message on receive an invalid
+	// spin count on encryption.
+	ErrEncryptSpinCount = fmt.Errorf("the encryption spin count must be between 1 and %d", MaxEncryptSpinCount)
+	// ErrEncryptSelfCheck defined the error message on the encrypted
+	// spreadsheet can't be decrypted and verified.
+	ErrEncryptSelfCheck = errors.New("the encrypted spreadsheet failed the self-check")
 	// ErrUnknownEncryptMechanism defined the error message on unsupport
 	// encryption mechanism.
 	ErrUnknownEncryptMechanism = errors.New("unknown encryption mechanism")
 	// ErrUnsupportEncryptMechanism defined the error message on unsupport
 	// encryption mechanism.
 	ErrUnsupportEncryptMechanism = errors.New("unsupport encryption mechanism")
+	// ErrWorkbookPassword defined the error message on receiving the
+	// incorrect workbook password.
+	ErrWorkbookPassword = errors.New("the supplied open workbook password is not correct")
 	// ErrParameterRequired defined the error message on receive the empty
 	// parameter.
 	ErrParameterRequired = errors.New("parameter is required")
@@ -95,14 +160,191 @@ var (
 	// ErrDefinedNameduplicate defined the error message on the same name
 	// already exists on the scope.
 	ErrDefinedNameduplicate = errors.New("the same name already exists on the scope")
+	// ErrDefinedNameBuiltInScope defined the error message on the built-in
+	// defined name not scoped to a worksheet.
+	ErrDefinedNameBuiltInScope = errors.New("the built-in defined name must be scoped to a worksheet")
 	// ErrFontLength defined the error message on the length of the font
 	// family name overflow.
 	ErrFontLength = errors.New("the length of the font family name must be smaller than or equal to 31")
+	// ErrFontFile defined the error message on receive the unsupported font
+	// file format.
+	ErrFontFile = errors.New("unsupported font file format")
 	// ErrFontSize defined the error message on the size of the font is invalid.
 	ErrFontSize = errors.New("font size must be between 1 and 409 points")
+	// ErrFontColor defined the error message on receive an invalid font color.
+	ErrFontColor = errors.New("font color must be a RGB color code")
+	// ErrFillType defined the error message on receive an invalid fill type.
+	ErrFillType = errors.New("fill type must be gradient or pattern")
+	// ErrFillPattern defined the error message on receive an invalid fill
+	// pattern index.
+	ErrFillPattern = errors.New("fill pattern must be between 0 and 18")
+	// ErrFillShading defined the error message on receive an invalid gradient
+	// fill shading index.
+	ErrFillShading = errors.New("fill shading must be between 0 and 5")
+	// ErrFillColor defined the error message on receive an invalid fill color.
+	ErrFillColor = errors.New("gradient fill requires 2 colors and pattern fill requires at least 1 color")
+	// ErrBorderStyle defined the error message on receive an invalid border
+	// style index.
+	ErrBorderStyle = errors.New("border style must be between 0 and 13")
 	// ErrSheetIdx defined the error message on receive the invalid worksheet
 	// index.
 	ErrSheetIdx = errors.New("invalid worksheet index")
+	// ErrRichTextRunIdx defined the error message on receive the invalid rich
+	// text run index.
+	ErrRichTextRunIdx = errors.New("invalid rich text run index")
+	// ErrChartTemplate defined the error message on receive an invalid chart
+	// template file.
+	ErrChartTemplate = errors.New("the chart template must contain a chart part")
+	// ErrChartGapWidth defined the error message on receive an invalid gap
+	// width of the bar chart.
+	ErrChartGapWidth = errors.New("gap width must be between 0 and 500")
+	// ErrChartOverlap defined the error message on receive an invalid overlap
+	// of the bar chart.
+	ErrChartOverlap = errors.New("overlap must be between -100 and 100")
+	// ErrChartStockSeries defined the error message on receive an invalid
+	// number of series for the stock chart.
+	ErrChartStockSeries = errors.New("the number of series does not match the stock chart type")
+	// ErrHexColor defined the error message on receive an invalid hex color
+	// code.
+	ErrHexColor = errors.New("hex color must be in RRGGBB or AARRGGBB format with an optional leading '#'")
+	// ErrPictureAnchor defined the error message on receive an invalid anchor
+	// type of the picture.
+	ErrPictureAnchor = errors.New("the picture anchor must be twoCell, oneCell or absolute")
+	// ErrPictureJPEGQuality defined the error message on receive an invalid
+	// JPEG quality of the picture.
+	ErrPictureJPEGQuality = errors.New("the JPEG quality of the picture must be 0-100")
+	// ErrPictureAnchorRange defined the error message on the end anchor of
+	// the picture is not after the start anchor.
+	ErrPictureAnchorRange = errors.New("the end anchor of the picture must be after the start anchor")
+	// ErrObjectNotExist defined the error message on the drawing object does
+	// not exist in the worksheet.
+	ErrObjectNotExist = errors.New("the drawing object does not exist")
+	// ErrObjectNameDuplicate defined the error message on the same name
+	// drawing object already exists in the worksheet.
+	ErrObjectNameDuplicate = errors.New("the same name drawing object already exists")
+	// ErrChartObject defined the error message on the drawing object is not a
+	// chart.
+	ErrChartObject = errors.New("the drawing object is not a chart")
+	// ErrChartSeries defined the error message on the chart doesn't contain
+	// any series.
+	ErrChartSeries = errors.New("the chart doesn't contain any series")
+	// ErrChartSecondaryAxis defined the error message on plot the series on
+	// the secondary axes for the unsupported chart types.
+	ErrChartSecondaryAxis = errors.New("the series of the chart type can't be plotted on the secondary axis")
+	// ErrChartTrendline defined the error message on add the trendline to the
+	// series of the chart type which doesn't support trendlines.
+	ErrChartTrendline = errors.New("the series of the chart type doesn't support trendlines")
+	// ErrChartTrendlineOrder defined the error message on receive the invalid
+	// order of the polynomial trendline.
+	ErrChartTrendlineOrder = errors.New("the order of the polynomial trendline must be between 2 and 6")
+	// ErrChartTrendlinePeriod defined the error message on receive the
+	// invalid period of the moving average trendline.
+	ErrChartTrendlinePeriod = errors.New("the period of the moving average trendline must be at least 2")
+	// ErrChartErrorBars defined the error message on add the error bars to
+	// the series of the chart type which doesn't support the error bars.
+	ErrChartErrorBars = errors.New("the series of the chart type doesn't support the error bars")
+	// ErrChartExCombo defined the error message on create combo chart with
+	// the extended chart types.
+	ErrChartExCombo = errors.New("the extended chart types can't be combined with other charts")
+	// ErrChartExPivotTable defined the error message on create pivot chart
+	// with the extended chart types.
+	ErrChartExPivotTable = errors.New("the extended chart types can't be used as pivot chart")
+	// ErrGroupDrawingObjects defined the error message on group less than two
+	// drawing objects.
+	ErrGroupDrawingObjects = errors.New("group must contain at least two drawing objects")
+	// ErrNotGroupObject defined the error message on ungroup the drawing
+	// object which is not a group.
+	ErrNotGroupObject = errors.New("the drawing object is not a group")
+	// ErrTableName defined the error message on receive an invalid table
+	// name.
+	ErrTableName = errors.New("the table name must begin with a letter, an underscore or a backslash, contain only letters, numbers, periods and underscores, and can't be a cell reference")
+	// ErrTableNameDuplicate defined the error message on the same name table
+	// already exists in the workbook.
+	ErrTableNameDuplicate = errors.New("the same name table already exists")
+	// ErrTableOverlap defined the error message on the range of the table
+	// overlaps with another table.
+	ErrTableOverlap = errors.New("the table range overlaps with an existing table")
+	// ErrTableMergeCell defined the error message on the table or merged
+	// cells overlaps with each other.
+	ErrTableMergeCell = errors.New("the table range can't contain merged cells")
+	// ErrAutoFilterTable defined the error message on the auto filter of the
+	// worksheet overlaps with a table.
+	ErrAutoFilterTable = errors.New("the auto filter range overlaps with a table")
+	// ErrTableNotExist defined the error message on the table does not exist
+	// in the worksheet.
+	ErrTableNotExist = errors.New("the table does not exist")
+	// ErrTableColumnNotExist defined the error message on the column of the
+	// structured reference does not exist in the table.
+	ErrTableColumnNotExist = errors.New("the table column does not exist")
+	// ErrTableColumnName defined the error message on receive an empty name
+	// or the same name of another column in the table.
+	ErrTableColumnName = errors.New("the table column name must be non-empty and unique in the table")
+	// ErrTableRange defined the error message on resize the table with the
+	// moved header row or the range not overlaps with the original range.
+	ErrTableRange = errors.New("the header row of the table can't be moved and the new range must overlap the original range")
+	// ErrDataValidationFormulaLength defined the error message on the length
+	// of the data validation formula exceeds the limit.
+	ErrDataValidationFormulaLength = errors.New(dataValidationFormulaStrLenErr)
+	// ErrDataValidationTitleLength defined the error message on the length
+	// of the title of the data validation input or error message exceeds the
+	// limit.
+	ErrDataValidationTitleLength = errors.New("the title of the data validation message must be 0-32 characters")
+	// ErrDataValidationMessageLength defined the error message on the length
+	// of the data validation input or error message exceeds the limit.
+	ErrDataValidationMessageLength = errors.New("the data validation message must be 0-255 characters")
+	// ErrThemePreset defined the error message on receive an unsupported
+	// built-in document theme name.
+	ErrThemePreset = errors.New("unsupported theme preset")
 	// ErrGroupSheets defined the error message on group sheets.
 	ErrGroupSheets = errors.New("group worksheet must contain an active worksheet")
+	// ErrCustomViewNotExist defined the error message on the custom view does
+	// not exist in the workbook.
+	ErrCustomViewNotExist = errors.New("the custom view does not exist")
+	// ErrProtectedObjects defined the error message on adding drawing objects
+	// or comments to the worksheet which objects locked by sheet protection,
+	// include the worksheet protected by Excel with the default settings or
+	// by the ProtectSheet function with nil settings. Use the
+	// IgnoreObjectsProtection option to add objects to such worksheet.
+	ErrProtectedObjects = errors.New("the objects of the protected worksheet are locked")
+	// ErrCopySameSheet defined the error message on copy the parts of the
+	// worksheet to itself.
+	ErrCopySameSheet = errors.New("the source and target worksheet can't be the same")
+	// ErrDataValidationNotExist defined the error message on the data
+	// validation does not exist in the worksheet.
+	ErrDataValidationNotExist = errors.New("the data validation does not exist")
+	// ErrConditionalFormatNotExist defined the error message on the
+	// conditional formatting rule does not exist in the worksheet.
+	ErrConditionalFormatNotExist = errors.New("the conditional formatting rule does not exist")
+	// ErrPivotTableNotExist defined the error message on the pivot table does
+	// not exist in the worksheet.
+	ErrPivotTableNotExist = errors.New("the pivot table does not exist")
+	// ErrPivotTableCalculatedFieldName defined the error message on the name
+	// of the calculated field is the same as the other fields in the pivot
+	// table.
+	ErrPivotTableCalculatedFieldName = errors.New("the name of the calculated field must be unique in the pivot table fields")
+	// ErrPivotTableCalculatedField defined the error message on the calculated
+	// field is used in the row, column or filter fields of the pivot table.
+	ErrPivotTableCalculatedField = errors.New("the calculated field can only be used in the data fields")
+	// ErrPivotTableCalculatedItem defined the error message on the field of
+	// the calculated item is not a row, column or filter field of the pivot
+	// table.
+	ErrPivotTableCalculatedItem = errors.New("the calculated item must belong to a row, column or filter field")
+	// ErrTimelineField defined the error message on the field of the timeline
+	// is not a field of the pivot table data source.
+	ErrTimelineField = errors.New("the timeline field must be a field of the pivot table data source")
+	// ErrCalcFuncName defined the error message on receiving the invalid
+	// name of the custom formula function.
+	ErrCalcFuncName = errors.New("the function name must start with a letter or underscore and contain only letters, numbers, underscores and periods")
+	// ErrUnprotectSheetPassword defined the error message on the password
+	// doesn't match the password of the protected worksheet.
+	ErrUnprotectSheetPassword = errors.New("worksheet protect password not match")
+	// ErrCircularReference defined the error message on the formulas have
+	// circular references in the workbook recalculation.
+	ErrCircularReference = errors.New("the formulas have circular references")
+	// ErrDownloadSize defined the error message on the size of the
+	// downloaded spreadsheet exceeds the limit.
+	ErrDownloadSize = errors.New("the size of the downloaded spreadsheet exceeds the limit")
+	// ErrCurrencyCode defined the error message on receive the unknown
+	// currency code of the money.
+	ErrCurrencyCode = errors.New("the currency code must be an ISO 4217 currency code")
 )
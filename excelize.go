@@ -15,15 +15,22 @@ package excelize
 import (
 	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/net/html/charset"
 )
@@ -32,10 +39,21 @@ import (
 type File struct {
 	sync.Mutex
 	options          *Options
+	culture          CultureName
+	inlineStr        bool
+	parseStr         bool
+	dateLayouts      []DateLayout
+	outOfBoundsCells string
+	ignoreObjsProt   bool
 	xmlAttr          map[string][]xml.Attr
 	checked          map[string]bool
+	mediaHashes      map[[sha256.Size]byte]string
 	sheetMap         map[string]string
 	streams          map[string]*StreamWriter
+	stringPool       stringPool
+	calcFuncs        sync.Map
+	connections      *xlsxConnections
+	metadata         *xlsxMetadata
 	CalcChain        *xlsxCalcChain
 	Comments         map[string]*xlsxComments
 	ContentTypes     *xlsxTypes
@@ -58,8 +76,74 @@ type File struct {
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
 // Options define the options for open spreadsheet.
+//
+// Password specifies the password of the spreadsheet in plain text. The
+// spreadsheet encrypted by the ECMA-376 standard encryption or agile
+// encryption will be decrypted with the password on open, and the
+// ErrWorkbookPassword will be returned if the password is missing or not
+// correct.
+//
+// OutOfBoundsCells specifies how to read the cells and rows beyond the
+// maximum rows and columns limit of the worksheet, which are produced by some
+// generators. By default, reading such worksheet returns an error. The
+// optional values are "clamp" (move the cells to the last row or column) and
+// "skip" (ignore the cells), a diagnostic message will be logged for each
+// out-of-bounds cell or row.
+//
+// CultureInfo specifies the culture used to format the cell values, which
+// determines the number grouping, decimal separator, currency format, date
+// order and the names of the month and day of the week. The default value
+// CultureNameUnknown formats the cell values without culture specific
+// settings. The culture could be specified for each call of the
+// GetCellValue function, which overrides the culture of the workbook.
+//
+// EncryptHashAlgorithm, EncryptKeyBits and EncryptSpinCount specify the
+// hash algorithm ("SHA1", "SHA256", "SHA384" or "SHA512"), the key bits of
+// the AES cipher (128, 192 or 256) and the spin count of the password
+// hashing (between 1 and 10000000) for the agile encryption, which used on
+// saving the spreadsheet with the password. The default values are SHA512,
+// 256 and 100000. The password must be 1-255 characters.
+//
+// Fsync specifies if flush the saved spreadsheet and the directory entry to
+// the stable storage before the SaveAs function returns, which makes the
+// saved spreadsheet durable against the power loss or the system crash at
+// the cost of the slower saving.
+//
+// MaxDownloadSize and DownloadTimeout specify the maximum size in bytes and
+// the timeout of downloading the spreadsheet by the OpenURL function. The
+// default values are 100 MB and no timeout except the deadline of the
+// context.
+//
+// UnsafeNames specifies how the SaveAs function handles the file name and the
+// worksheet names which would break on SharePoint, OneDrive or the other
+// platforms, such as the illegal characters, the leading or trailing spaces
+// and dots, the reserved device names and the names exceed the length limit.
+// By default, the names will not be checked. The optional values are "error"
+// (return an UnsafeNameError with the suggested safe name) and "sanitize"
+// (replace the names with the suggested safe names). Note that sanitizing
+// renames the worksheets of the workbook in memory as a side effect of the
+// SaveAs function, the worksheet references in the formulas of the cells,
+// the defined names and the cell annotations will be updated, but the other
+// references, such as the formulas of the conditional formats and the data
+// validations, will not be updated.
+//
+// IgnoreObjectsProtection specifies if the drawing objects and comments are
+// allowed to add to the worksheet which objects are locked by the sheet
+// protection, such as the worksheet protected by Excel with the default
+// settings or by the ProtectSheet function without allowing to edit objects.
+// By default, adding objects to such worksheet returns ErrProtectedObjects.
 type Options struct {
-	Password string
+	Password                string
+	OutOfBoundsCells        string
+	CultureInfo             CultureName
+	EncryptHashAlgorithm    string
+	EncryptKeyBits          int
+	EncryptSpinCount        int
+	Fsync                   bool
+	MaxDownloadSize         int64
+	DownloadTimeout         time.Duration
+	UnsafeNames             string
+	IgnoreObjectsProtection bool
 }
 
 // OpenFile take the name of an spreadsheet file and returns a populated spreadsheet file struct
@@ -70,8 +154,8 @@ type Options struct {
 //        return
 //    }
 //
-// Note that the excelize just support decrypt and not support encrypt currently, the spreadsheet
-// saved by Save and SaveAs will be without password unprotected.
+// Note that the spreadsheet saved by Save and SaveAs will be without password
+// unprotected, unless the password is specified in the options of SaveAs.
 func OpenFile(filename string, opt ...Options) (*File, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -111,11 +195,23 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 		return nil, err
 	}
 	f := newFile()
-	if bytes.Contains(b, oleIdentifier) && len(opt) > 0 {
+	for _, o := range opt {
+		if o.OutOfBoundsCells != "" && o.OutOfBoundsCells != "clamp" && o.OutOfBoundsCells != "skip" {
+			return nil, ErrOptionsOutOfBoundsCells
+		}
+		f.outOfBoundsCells = o.OutOfBoundsCells
+		f.culture = o.CultureInfo
+		f.ignoreObjsProt = o.IgnoreObjectsProtection
+	}
+	if bytes.HasPrefix(b, oleIdentifier) {
+		f.options = &Options{}
 		for _, o := range opt {
 			f.options = &o
 		}
 		b, err = Decrypt(b, f.options)
+		if err == ErrWorkbookPassword {
+			return nil, err
+		}
 		if err != nil {
 			return nil, fmt.Errorf("decrypted file failed")
 		}
@@ -131,8 +227,9 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	}
 	f.SheetCount = sheetCount
 	for k, v := range file {
-		f.Pkg.Store(k, v)
+		f.Pkg.Store(f.intern(k), v)
 	}
+	f.addDefaultParts()
 	f.CalcChain = f.calcChainReader()
 	f.sheetMap = f.getSheetMap()
 	f.Styles = f.stylesReader()
@@ -140,10 +237,163 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	return f, nil
 }
 
+// defaultMaxDownloadSize defined the default maximum size in bytes of
+// downloading the spreadsheet by the OpenURL function.
+const defaultMaxDownloadSize = 100 << 20
+
+// downloadContentTypes defined the acceptable content types of the
+// spreadsheet downloaded by the OpenURL function.
+var downloadContentTypes = []string{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.template",
+	"application/vnd.ms-excel.sheet.macroenabled.12",
+	"application/vnd.ms-excel.template.macroenabled.12",
+	"application/vnd.ms-excel",
+	"application/octet-stream",
+	"binary/octet-stream",
+	"application/zip",
+	"application/x-zip-compressed",
+}
+
+// downloadReader directly maps the reader of the downloaded spreadsheet,
+// which returns the ErrDownloadSize error if the size of the downloaded
+// spreadsheet exceeds the limit.
+type downloadReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read provides a function to read the downloaded spreadsheet.
+func (r *downloadReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if r.n -= int64(n); r.n < 0 {
+		return n, ErrDownloadSize
+	}
+	return n, err
+}
+
+// OpenURL provides a function to download the spreadsheet by given URL and
+// returns a populated spreadsheet file. The download will be canceled when
+// the context is done or the timeout specified by the DownloadTimeout option
+// is exceeded. The ErrDownloadSize will be returned if the size of the
+// spreadsheet exceeds the MaxDownloadSize option, and an error will be
+// returned if the response status isn't 200 OK or the content type of the
+// response isn't a spreadsheet or binary content type. For example:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//    defer cancel()
+//    f, err := excelize.OpenURL(ctx, "https://example.com/Book1.xlsx", excelize.Options{MaxDownloadSize: 10 << 20})
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//
+func OpenURL(ctx context.Context, url string, opt ...Options) (*File, error) {
+	maxSize, timeout := int64(defaultMaxDownloadSize), time.Duration(0)
+	for _, o := range opt {
+		if o.MaxDownloadSize > 0 {
+			maxSize = o.MaxDownloadSize
+		}
+		timeout = o.DownloadTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newUnexpectedHTTPStatusError(resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || inStrSlice(downloadContentTypes, strings.ToLower(mediaType)) == -1 {
+			return nil, newUnsupportedContentTypeError(contentType)
+		}
+	}
+	if resp.ContentLength > maxSize {
+		return nil, ErrDownloadSize
+	}
+	return OpenReader(&downloadReader{r: resp.Body, n: maxSize}, opt...)
+}
+
+// addDefaultParts provides a function to synthesize the default styles, theme
+// and document properties parts for the spreadsheet produced by minimal
+// generators which missing these optional parts, to make sure the cell
+// values and styles can be read and written normally.
+func (f *File) addDefaultParts() {
+	wbRelsPath := f.getWorkbookRelsPath()
+	for _, part := range []struct {
+		path, relPath, relType, contentType, content string
+	}{
+		{"xl/styles.xml", wbRelsPath, SourceRelationshipStyles, "styles", templateStyles},
+		{"xl/theme/theme1.xml", wbRelsPath, SourceRelationshipTheme, "theme", templateTheme},
+		{"docProps/app.xml", "_rels/.rels", SourceRelationshipExtendProperties, "docPropsApp", templateDocpropsApp},
+		{"docProps/core.xml", "_rels/.rels", SourceRelationshipCoreProperties, "docPropsCore", templateDocpropsCore},
+	} {
+		if _, ok := f.Pkg.Load(part.path); ok {
+			continue
+		}
+		f.Pkg.Store(part.path, []byte(XMLHeader+part.content))
+		f.addRels(part.relPath, part.relType, "/"+part.path, "")
+		f.addContentTypePart(0, part.contentType)
+	}
+}
+
 // CharsetTranscoder Set user defined codepage transcoder function for open
 // XLSX from non UTF-8 encoding.
 func (f *File) CharsetTranscoder(fn charsetTranscoderFn) *File { f.CharsetReader = fn; return f }
 
+// InlineStrings provides a function to specify whether the string values set
+// by SetCellStr, SetCellValue and SetCellRichText should be written as the
+// inline strings of the cells instead of the shared strings table. The cells
+// with inline strings in the existing spreadsheet will always be kept as
+// inline strings on round-trip, even if they were overwritten by the new
+// string values. For example, create a spreadsheet with inline strings:
+//
+//    f := excelize.NewFile().InlineStrings(true)
+//
+func (f *File) InlineStrings(enable bool) *File { f.inlineStr = enable; return f }
+
+// ParseStrings provides a function to specify whether the string values set
+// by SetCellValue should be parsed and stored as the typed cells like the
+// paste behavior of Excel, which is useful when the data arrives as strings.
+// When it was enabled, the "TRUE" and "FALSE" strings will be stored as
+// boolean values, the numbers, the numbers with thousands separators, the
+// percentages and the date and time strings matching the given date layouts
+// will be stored as numbers with the corresponding number formats if the
+// cells have no style, and the numbers in the scientific notation will be
+// stored with the 0.00E+00 number format. The numbers with leading zeros,
+// such as "007", will be kept as strings except "0" and the decimals like
+// "0.5". The default date layouts will be used if the layouts are not
+// specified, which are:
+//
+//    Layout                    | NumFmt
+//   ---------------------------+---------------------
+//    2006-01-02                | yyyy-mm-dd
+//    2006-01-02 15:04:05       | yyyy-mm-dd hh:mm:ss
+//    2006-01-02T15:04:05Z07:00 | yyyy-mm-dd hh:mm:ss
+//    1/2/2006                  | m/d/yyyy
+//    15:04:05                  | hh:mm:ss
+//
+// For example, parse the string values with the date layout dd.mm.yyyy:
+//
+//    f := excelize.NewFile().ParseStrings(true, excelize.DateLayout{Layout: "02.01.2006", NumFmt: "dd.mm.yyyy"})
+//    err := f.SetCellValue("Sheet1", "A1", "31.12.2021")
+//
+func (f *File) ParseStrings(enable bool, layouts ...DateLayout) *File {
+	f.parseStr, f.dateLayouts = enable, layouts
+	return f
+}
+
 // Creates new XML decoder with charset reader.
 func (f *File) xmlNewDecoder(rdr io.Reader) (ret *xml.Decoder) {
 	ret = xml.NewDecoder(rdr)
@@ -183,9 +433,11 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 		ws = worksheet.(*xlsxWorksheet)
 		return
 	}
-	if strings.HasPrefix(name, "xl/chartsheets") {
-		err = fmt.Errorf("sheet %s is chart sheet", sheet)
-		return
+	if !strings.HasPrefix(name, "xl/worksheets/") {
+		if sheetType := f.getSheetType(name); sheetType != "" && sheetType != "worksheet" {
+			err = ErrNotWorksheet{SheetName: sheet, SheetType: sheetType}
+			return
+		}
 	}
 	ws = new(xlsxWorksheet)
 	if _, ok := f.xmlAttr[name]; !ok {
@@ -202,16 +454,126 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 		f.checked = make(map[string]bool)
 	}
 	if ok = f.checked[name]; !ok {
+		f.checkSheetBounds(ws, name)
 		checkSheet(ws)
 		if err = checkRow(ws); err != nil {
 			return
 		}
 		f.checked[name] = true
 	}
+	f.internWorksheet(ws)
 	f.Sheet.Store(name, ws)
 	return
 }
 
+// checkSheetBounds provides a function to clamp or skip the rows and cells
+// beyond the maximum rows and columns limit of the worksheet by the
+// out-of-bounds cells option. The clamped rows will be merged into the last
+// row of the worksheet, and the clamped cells which collide with the
+// existing cells will be dropped, the existing cells will be kept.
+func (f *File) checkSheetBounds(ws *xlsxWorksheet, name string) {
+	if f.outOfBoundsCells == "" {
+		return
+	}
+	sheetRows, lastRow := ws.SheetData.Row[:0], -1
+	collided := make(map[string]bool)
+	for _, r := range ws.SheetData.Row {
+		if r.R > TotalRows {
+			log.Printf("out of bounds row %d in %s has been %s", r.R, name, outOfBoundsAction[f.outOfBoundsCells])
+			if f.outOfBoundsCells == "skip" {
+				continue
+			}
+			r.R = TotalRows
+		}
+		cells := r.C[:0]
+		for _, c := range r.C {
+			ref, skip := f.checkCellBounds(c.R)
+			if ref != c.R || skip {
+				log.Printf("out of bounds cell %s in %s has been %s", c.R, name, outOfBoundsAction[f.outOfBoundsCells])
+			}
+			if !skip {
+				c.R = ref
+				cells = append(cells, c)
+			}
+		}
+		r.C = cells
+		if r.R == TotalRows {
+			if lastRow != -1 {
+				cells := append(sheetRows[lastRow].C, r.C...)
+				sort.SliceStable(cells, func(i, j int) bool {
+					col1, _, _ := CellNameToCoordinates(cells[i].R)
+					col2, _, _ := CellNameToCoordinates(cells[j].R)
+					return col1 < col2
+				})
+				sheetRows[lastRow].C = cells
+				continue
+			}
+			lastRow = len(sheetRows)
+		}
+		sheetRows = append(sheetRows, r)
+	}
+	for idx := range sheetRows {
+		sheetRows[idx].C = dropCollidedCells(sheetRows[idx].C, name, collided)
+	}
+	ws.SheetData.Row = sheetRows
+}
+
+// dropCollidedCells provides a function to drop the cells with the same
+// reference of the previous cells in the row, which are produced by clamping
+// the out-of-bounds cells, each collided reference will be logged once.
+func dropCollidedCells(cells []xlsxC, name string, collided map[string]bool) []xlsxC {
+	seen := make(map[string]bool, len(cells))
+	result := cells[:0]
+	for _, c := range cells {
+		if c.R != "" && seen[c.R] {
+			if !collided[c.R] {
+				collided[c.R] = true
+				log.Printf("clamped cell %s in %s collides with the existing cell and has been dropped", c.R, name)
+			}
+			continue
+		}
+		seen[c.R] = true
+		result = append(result, c)
+	}
+	return result
+}
+
+// outOfBoundsAction defined the diagnostic message of the out-of-bounds cells
+// option.
+var outOfBoundsAction = map[string]string{"clamp": "clamped", "skip": "skipped"}
+
+// checkCellBounds provides a function to check the cell reference by the
+// out-of-bounds cells option, returns the clamped cell reference, and whether
+// the cell should be skipped. The reference which is not a valid cell name
+// will be returned as it is.
+func (f *File) checkCellBounds(ref string) (string, bool) {
+	if f.outOfBoundsCells == "" || ref == "" {
+		return ref, false
+	}
+	colName, row, err := SplitCellName(ref)
+	if err != nil {
+		return ref, false
+	}
+	col, err := ColumnNameToNumber(colName)
+	if err != nil && err != ErrColumnNumber {
+		return ref, false
+	}
+	if err == nil && row <= TotalRows {
+		return ref, false
+	}
+	if f.outOfBoundsCells == "skip" {
+		return ref, true
+	}
+	if err != nil {
+		col = TotalColumns
+	}
+	if row > TotalRows {
+		row = TotalRows
+	}
+	ref, _ = CoordinatesToCellName(col, row)
+	return ref, false
+}
+
 // checkSheet provides a function to fill each row element and make that is
 // continuous in a worksheet of XML.
 func checkSheet(ws *xlsxWorksheet) {
@@ -252,6 +614,7 @@ func checkSheet(ws *xlsxWorksheet) {
 func (f *File) addRels(relPath, relType, target, targetMode string) int {
 	var uniqPart = map[string]string{
 		SourceRelationshipSharedStrings: "/xl/sharedStrings.xml",
+		SourceRelationshipSheetMetadata: "/xl/metadata.xml",
 	}
 	rels := f.relsReader(relPath)
 	if rels == nil {
@@ -318,7 +681,7 @@ func (f *File) UpdateLinkedValue() error {
 	for _, name := range f.GetSheetList() {
 		xlsx, err := f.workSheetReader(name)
 		if err != nil {
-			if err.Error() == fmt.Sprintf("sheet %s is chart sheet", trimSheetName(name)) {
+			if _, ok := err.(ErrNotWorksheet); ok {
 				continue
 			}
 			return err
@@ -1,8 +1,10 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"image/color"
@@ -11,6 +13,8 @@ import (
 	_ "image/png"
 	"io/ioutil"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -54,7 +58,12 @@ func TestOpenFile(t *testing.T) {
 
 	assert.NoError(t, f.SetCellStr("Sheet2", "C11", "Knowns"))
 	// Test max characters in a cell.
-	assert.NoError(t, f.SetCellStr("Sheet2", "D11", strings.Repeat("c", TotalCellChars+2)))
+	assert.EqualError(t, f.SetCellStr("Sheet2", "D11", strings.Repeat("c", TotalCellChars+2)), ErrCellCharsLength.Error())
+	assert.NoError(t, f.SetCellStr("Sheet2", "D11", strings.Repeat("中", TotalCellChars)))
+	// Test set cell value with the characters out of the basic multilingual
+	// plane, which are counted as the surrogate pairs.
+	assert.EqualError(t, f.SetCellStr("Sheet2", "D12", strings.Repeat("\U0001F600", TotalCellChars/2+1)), ErrCellCharsLength.Error())
+	assert.NoError(t, f.SetCellStr("Sheet2", "D12", strings.Repeat("\U0001F600", TotalCellChars/2)))
 	f.NewSheet(":\\/?*[]Maximum 31 characters allowed in sheet title.")
 	// Test set worksheet name with illegal name.
 	f.SetSheetName("Maximum 31 characters allowed i", "[Rename]:\\/?* Maximum 31 characters allowed in sheet title.")
@@ -204,6 +213,15 @@ func TestOpenReader(t *testing.T) {
 	_, err = OpenReader(bytes.NewReader(oleIdentifier), Options{Password: "password"})
 	assert.EqualError(t, err, "decrypted file failed")
 
+	// Test open password protected spreadsheet with incorrect password or
+	// without password.
+	for _, name := range []string{"encryptSHA1.xlsx", "encryptAES.xlsx"} {
+		for _, opts := range [][]Options{{{Password: "passwd"}}, {{}}, nil} {
+			_, err = OpenFile(filepath.Join("test", name), opts...)
+			assert.EqualError(t, err, ErrWorkbookPassword.Error(), name)
+		}
+	}
+
 	// Test open password protected spreadsheet created by Microsoft Office Excel 2010.
 	f, err := OpenFile(filepath.Join("test", "encryptSHA1.xlsx"), Options{Password: "password"})
 	assert.NoError(t, err)
@@ -247,6 +265,140 @@ func TestOpenReader(t *testing.T) {
 	assert.EqualError(t, err, "zip: unsupported compression algorithm")
 }
 
+func TestOpenReaderOutOfBoundsCells(t *testing.T) {
+	f := NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="str"><v>A1</v></c><c r="XFE1" t="str"><v>XFE1</v></c></row><row r="2"><c r="B2" t="str"><v>B2</v></c></row><row r="1048577"><c r="C1048577" t="str"><v>C1048577</v></c></row></sheetData></worksheet>`))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test open spreadsheet with out-of-bounds cells by default.
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	_, err = f.GetCellValue("Sheet1", "A1")
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+
+	// Test open spreadsheet with skip out-of-bounds cells.
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{OutOfBoundsCells: "skip"})
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, {"", "B2"}}, rows)
+	val, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", val)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.SheetData.Row, 2)
+	assert.Len(t, ws.SheetData.Row[0].C, 1)
+
+	// Test open spreadsheet with clamp out-of-bounds cells.
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{OutOfBoundsCells: "clamp"})
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Len(t, rows[0], TotalColumns)
+	assert.Equal(t, "XFE1", rows[0][TotalColumns-1])
+	for cell, expected := range map[string]string{"A1": "A1", "XFD1": "XFE1", "B2": "B2", "C1048576": "C1048577"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	// Test open spreadsheet with invalid out-of-bounds cells option.
+	_, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{OutOfBoundsCells: "drop"})
+	assert.EqualError(t, err, ErrOptionsOutOfBoundsCells.Error())
+	// Test check cell bounds with invalid cell reference.
+	f.outOfBoundsCells = "skip"
+	ref, skip := f.checkCellBounds("A")
+	assert.Equal(t, "A", ref)
+	assert.False(t, skip)
+	ref, skip = f.checkCellBounds("A-B1")
+	assert.Equal(t, "A-B1", ref)
+	assert.False(t, skip)
+
+	// Test open spreadsheet with clamp the colliding out-of-bounds cells.
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="5"><c r="XFD5" t="str"><v>XFD5</v></c><c r="XFE5" t="str"><v>XFE5</v></c><c r="XFF5" t="str"><v>XFF5</v></c></row><row r="1048576"><c r="B1048576" t="str"><v>B1048576</v></c></row><row r="1048577"><c r="A1048577" t="str"><v>A1048577</v></c><c r="B1048577" t="str"><v>B1048577</v></c></row><row r="1048578"><c r="A1048578" t="str"><v>A1048578</v></c></row></sheetData></worksheet>`))
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{OutOfBoundsCells: "clamp"})
+	assert.NoError(t, err)
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.SheetData.Row, TotalRows)
+	for idx, refs := range map[int][]string{4: {"XFD5"}, TotalRows - 1: {"A1048576", "B1048576"}} {
+		var cells []string
+		for _, c := range ws.SheetData.Row[idx].C {
+			if c.V != "" {
+				cells = append(cells, c.R)
+			}
+		}
+		assert.Equal(t, refs, cells)
+	}
+	for cell, expected := range map[string]string{"XFD5": "XFD5", "A1048576": "A1048577", "B1048576": "B1048576"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+}
+
+func TestOpenReaderMissingParts(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"[Content_Types].xml":        `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`,
+		"_rels/.rels":                `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`,
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" s="1"><v>1.5</v></c><c r="B1" t="inlineStr"><is><t>B1</t></is></c></row></sheetData></worksheet>`,
+	} {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+
+	f, err := OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{"A1": "1.5", "B1": "B1"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	for _, part := range []string{"xl/styles.xml", "xl/theme/theme1.xml", "docProps/app.xml", "docProps/core.xml"} {
+		_, ok := f.Pkg.Load(part)
+		assert.True(t, ok, part)
+	}
+	props, err := f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "xuri", props.Creator)
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.50", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenReaderMissingParts.xlsx")))
+
+	// Test reopen the saved spreadsheet with the synthesized parts.
+	f, err = OpenFile(filepath.Join("test", "TestOpenReaderMissingParts.xlsx"))
+	assert.NoError(t, err)
+	rels := f.relsReader("xl/_rels/workbook.xml.rels")
+	assert.Len(t, rels.Relationships, 4)
+	content := f.contentTypesReader()
+	assert.Len(t, content.Overrides, 7)
+	props, err = f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "xuri", props.Creator)
+}
+
 func TestBrokenFile(t *testing.T) {
 	// Test write file with broken file struct.
 	f := File{}
@@ -313,7 +465,7 @@ func TestNewFile(t *testing.T) {
 func TestAddDrawingVML(t *testing.T) {
 	// Test addDrawingVML with illegal cell coordinates.
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingVML(0, "", "*", 0, 0), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
+	assert.EqualError(t, f.addDrawingVML(0, "", "Sheet1", "*", &formatComment{}), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
 }
 
 func TestSetCellHyperLink(t *testing.T) {
@@ -425,6 +577,18 @@ func TestSetCellFormula(t *testing.T) {
 	// Test remove all cell formula.
 	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", ""))
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellFormula3.xlsx")))
+
+	// Test set cell formula in the R1C1 reference style.
+	f = NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B3", "SUM(R[-2]C:R[-1]C)*R1C1", FormulaOpts{RefMode: stringPtr("R1C1")}))
+	formula, err := f.GetCellFormula("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B1:B2)*$A$1", formula)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B4", "R1C1", FormulaOpts{RefMode: stringPtr("A1")}))
+	formula, err = f.GetCellFormula("Sheet1", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, "R1C1", formula)
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "B5", "RC", FormulaOpts{RefMode: stringPtr("unknown")}), `invalid RefMode value "unknown", acceptable value should be one of A1, R1C1`)
 }
 
 func TestSetSheetBackground(t *testing.T) {
@@ -1126,6 +1290,56 @@ func TestSetSheetRow(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSheetRow.xlsx")))
 }
 
+func TestSetSheetRowWithStyles(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	cellStyle, err := f.NewStyle(&Style{Font: &Font{Italic: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B2", &[]interface{}{
+		"Total", 1, &Cell{Formula: "SUM(C2)", StyleID: cellStyle}, Cell{Value: 2}, (*Cell)(nil),
+	}, style, style, style))
+	for cell, expected := range map[string]int{"B2": style, "C2": style, "D2": cellStyle, "E2": 0, "F2": 0} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(C2)", formula)
+	value, err := f.GetCellValue("Sheet1", "E2")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", value)
+	// Test set sheet row with formula cell on not exists worksheet.
+	assert.EqualError(t, f.SetSheetRow("SheetN", "A1", &[]interface{}{Cell{Formula: "1"}}), "sheet SheetN is not exist")
+}
+
+func TestSetSheetCol(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet1", "B2", &[]interface{}{"A", 1, Cell{Formula: "B3*2"}}, style))
+	for cell, expected := range map[string]string{"B2": "A", "B3": "1", "C2": ""} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, "B3*2", formula)
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	styleID, err = f.GetCellStyle("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+
+	assert.EqualError(t, f.SetSheetCol("Sheet1", "", &[]interface{}{"cell"}), `cannot convert cell "" to coordinates: invalid cell name ""`)
+	assert.EqualError(t, f.SetSheetCol("Sheet1", "B1", []interface{}{}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.SetSheetCol("Sheet1", fmt.Sprintf("A%d", TotalRows), &[]interface{}{1, 2}), ErrMaxRows.Error())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSheetCol.xlsx")))
+}
+
 func TestHSL(t *testing.T) {
 	var hsl HSL
 	r, g, b, a := hsl.RGBA()
@@ -1169,6 +1383,73 @@ func TestProtectSheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestProtectSheet.xlsx")))
 	// Test protect not exists worksheet.
 	assert.EqualError(t, f.ProtectSheet("SheetN", nil), "sheet SheetN is not exist")
+
+	// Test add objects on the protected worksheet with locked objects.
+	f = NewFile()
+	assert.NoError(t, f.ProtectSheet("Sheet1", nil))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, ws.SheetProtection.Objects)
+	assert.True(t, ws.SheetProtection.Scenarios)
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""), ErrProtectedObjects.Error())
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`), ErrProtectedObjects.Error())
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", `{"type":"rect"}`), ErrProtectedObjects.Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "A1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`), ErrProtectedObjects.Error())
+	// Test add objects on the protected worksheet which allowed edit objects.
+	assert.NoError(t, f.ProtectSheet("Sheet1", &FormatSheetProtection{EditObjects: true}))
+	assert.False(t, ws.SheetProtection.Objects)
+	assert.True(t, ws.SheetProtection.Scenarios)
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddShape("Sheet1", "A1", `{"type":"rect"}`))
+	// Test add objects on the unprotected worksheet.
+	assert.NoError(t, f.ProtectSheet("Sheet1", nil))
+	assert.NoError(t, f.UnprotectSheet("Sheet1"))
+	assert.NoError(t, f.AddShape("Sheet1", "B1", `{"type":"rect"}`))
+	// Test add objects on the protected worksheet with ignore the objects
+	// protection.
+	f = NewFile(Options{IgnoreObjectsProtection: true})
+	assert.NoError(t, f.ProtectSheet("Sheet1", nil))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", `{"type":"rect"}`), ErrProtectedObjects.Error())
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{IgnoreObjectsProtection: true})
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddShape("Sheet1", "A1", `{"type":"rect"}`))
+}
+
+func TestGetSheetProtection(t *testing.T) {
+	f := NewFile()
+	settings, protected, err := f.GetSheetProtection("Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, protected)
+	assert.Equal(t, FormatSheetProtection{}, settings)
+
+	assert.NoError(t, f.ProtectSheet("Sheet1", &FormatSheetProtection{
+		Password:          "password",
+		EditObjects:       true,
+		FormatCells:       true,
+		SelectLockedCells: true,
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSheetProtection.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestGetSheetProtection.xlsx"))
+	assert.NoError(t, err)
+	settings, protected, err = f.GetSheetProtection("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, protected)
+	assert.Equal(t, FormatSheetProtection{
+		EditObjects:       true,
+		FormatCells:       true,
+		SelectLockedCells: true,
+	}, settings)
+
+	// Test get protection settings on not exists worksheet.
+	_, _, err = f.GetSheetProtection("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
 }
 
 func TestUnprotectSheet(t *testing.T) {
@@ -1183,6 +1464,60 @@ func TestUnprotectSheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnprotectSheet.xlsx")))
 }
 
+func TestWithSheetUnprotected(t *testing.T) {
+	f := NewFile()
+	// Test run the function on the unprotected worksheet.
+	var called bool
+	assert.NoError(t, f.WithSheetUnprotected("Sheet1", "", func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+
+	assert.NoError(t, f.ProtectSheet("Sheet1", &FormatSheetProtection{
+		Password:    "password",
+		FormatCells: true,
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	expected := *ws.SheetProtection
+	assert.NoError(t, f.WithSheetUnprotected("Sheet1", "password", func() error {
+		assert.Nil(t, ws.SheetProtection)
+		return f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), "")
+	}))
+	assert.Equal(t, expected, *ws.SheetProtection)
+	// Test restore the protection settings when the function returns an error.
+	assert.EqualError(t, f.WithSheetUnprotected("Sheet1", "password", func() error {
+		return f.AddPicture("Sheet1", "A1", "", "")
+	}), "stat : no such file or directory")
+	assert.Equal(t, expected, *ws.SheetProtection)
+	// Test unprotect worksheet with the wrong password.
+	called = false
+	assert.EqualError(t, f.WithSheetUnprotected("Sheet1", "passwd", func() error {
+		called = true
+		return nil
+	}), ErrUnprotectSheetPassword.Error())
+	assert.False(t, called)
+
+	// Test unprotect worksheet protected by the hash algorithm.
+	hashValue, err := genISOPasswdHash("password", "SHA-512", "MTIzNDU2Nzg5MDEyMzQ1Ng==", 100000)
+	assert.NoError(t, err)
+	ws.SheetProtection = &xlsxSheetProtection{AlgorithmName: "SHA-512", HashValue: hashValue, SaltValue: "MTIzNDU2Nzg5MDEyMzQ1Ng==", SpinCount: 100000, Sheet: true, Objects: true}
+	assert.EqualError(t, f.WithSheetUnprotected("Sheet1", "Password", func() error { return nil }), ErrUnprotectSheetPassword.Error())
+	assert.NoError(t, f.WithSheetUnprotected("Sheet1", "password", func() error {
+		return f.AddShape("Sheet1", "B1", `{"type":"rect"}`)
+	}))
+	assert.Equal(t, hashValue, ws.SheetProtection.HashValue)
+	// Test unprotect worksheet with the invalid salt value.
+	ws.SheetProtection.SaltValue = "*"
+	assert.EqualError(t, f.WithSheetUnprotected("Sheet1", "password", func() error { return nil }), "illegal base64 data at input byte 0")
+	// Test unprotect worksheet protected without the password.
+	assert.NoError(t, f.ProtectSheet("Sheet1", nil))
+	assert.NoError(t, f.WithSheetUnprotected("Sheet1", "", func() error { return nil }))
+	// Test unprotect not exists worksheet.
+	assert.EqualError(t, f.WithSheetUnprotected("SheetN", "", func() error { return nil }), "sheet SheetN is not exist")
+}
+
 func TestSetDefaultTimeStyle(t *testing.T) {
 	f := NewFile()
 	// Test set default time style on not exists worksheet.
@@ -1353,3 +1688,62 @@ func BenchmarkOpenFile(b *testing.B) {
 		}
 	}
 }
+
+func TestOpenURL(t *testing.T) {
+	book, err := ioutil.ReadFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Book1.xlsx", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		_, _ = w.Write(book)
+	})
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.(http.Flusher).Flush()
+		_, _ = w.Write(book)
+	})
+	mux.HandleFunc("/index.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f, err := OpenURL(context.Background(), server.URL+"/Book1.xlsx")
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "A19")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total:", value)
+	f, err = OpenURL(context.Background(), server.URL+"/stream")
+	assert.NoError(t, err)
+	assert.NotNil(t, f)
+
+	// Test open spreadsheet exceeds the size limit
+	_, err = OpenURL(context.Background(), server.URL+"/Book1.xlsx", Options{MaxDownloadSize: 1024})
+	assert.EqualError(t, err, ErrDownloadSize.Error())
+	_, err = OpenURL(context.Background(), server.URL+"/stream", Options{MaxDownloadSize: 1024})
+	assert.EqualError(t, err, ErrDownloadSize.Error())
+	// Test open spreadsheet with unsupported content type
+	_, err = OpenURL(context.Background(), server.URL+"/index.html")
+	assert.EqualError(t, err, `unsupported content type "text/html; charset=utf-8" of the spreadsheet`)
+	// Test open spreadsheet with unexpected HTTP status
+	_, err = OpenURL(context.Background(), server.URL+"/not-found.xlsx")
+	assert.EqualError(t, err, "unexpected HTTP status 404 Not Found")
+	// Test open spreadsheet with timeout
+	_, err = OpenURL(context.Background(), server.URL+"/slow", Options{DownloadTimeout: 10 * time.Millisecond})
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	// Test open spreadsheet with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = OpenURL(ctx, server.URL+"/Book1.xlsx")
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+	// Test open spreadsheet with invalid URL
+	_, err = OpenURL(context.Background(), ":")
+	assert.EqualError(t, err, `parse ":": missing protocol scheme`)
+}
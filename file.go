@@ -17,16 +17,27 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
 )
 
-// NewFile provides a function to create new file by default template. For
-// example:
+// NewFile provides a function to create new file by default template. The
+// options could be used to specify the culture to format the cell values.
+// For example:
 //
 //    f := NewFile()
 //
-func NewFile() *File {
+func NewFile(opts ...Options) *File {
 	f := newFile()
+	for _, o := range opts {
+		f.culture = o.CultureInfo
+		f.ignoreObjsProt = o.IgnoreObjectsProtection
+	}
 	f.Pkg.Store("_rels/.rels", []byte(XMLHeader+templateRels))
 	f.Pkg.Store("docProps/app.xml", []byte(XMLHeader+templateDocpropsApp))
 	f.Pkg.Store("docProps/core.xml", []byte(XMLHeader+templateDocpropsCore))
@@ -63,22 +74,263 @@ func (f *File) Save() error {
 }
 
 // SaveAs provides a function to create or update to an spreadsheet at the
-// provided path.
+// provided path. The spreadsheet will be written to a temporary file in the
+// destination directory first, and then renamed to the provided path when
+// the writing succeeds, so the existing spreadsheet at the provided path
+// will never be truncated or corrupted by a failed or interrupted saving.
+// Enable the Fsync option to flush the spreadsheet to the stable storage
+// before the renaming. For example:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{Fsync: true})
+//
+// Specify the UnsafeNames option to check the file name and the worksheet
+// names which would break on SharePoint or OneDrive before saving, for
+// example, save the spreadsheet as "Report_2021.xlsx" with the safe
+// worksheet names:
+//
+//    err := f.SaveAs("Report:2021.xlsx", excelize.Options{UnsafeNames: "sanitize"})
+//
 func (f *File) SaveAs(name string, opt ...Options) error {
 	if len(name) > MaxFileNameLength {
 		return ErrMaxFileNameLength
 	}
+	f.options = nil
+	for _, o := range opt {
+		f.options = &o
+	}
+	if f.options != nil && f.options.UnsafeNames != "" {
+		var err error
+		if name, err = f.checkUnsafeNames(name, f.options.UnsafeNames); err != nil {
+			return err
+		}
+	}
 	f.Path = name
-	file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
+	fsync := f.options != nil && f.options.Fsync
+	file, err := createTempFile(name)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	f.options = nil
-	for _, o := range opt {
-		f.options = &o
+	tmp := file.Name()
+	if err = f.Write(file); err == nil && fsync {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		if fi, statErr := os.Stat(name); statErr == nil {
+			err = os.Chmod(tmp, fi.Mode())
+		}
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if fsync {
+		syncDir(filepath.Dir(name))
+	}
+	return nil
+}
+
+// reservedFileNames defined the device names which couldn't be used as the
+// file name on Windows, SharePoint and OneDrive.
+var reservedFileNames = []string{"CON", "PRN", "AUX", "NUL", "COM0", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9", "LPT0", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9"}
+
+// checkUnsafeNames provides a function to check the worksheet names in the
+// workbook and the file name by given file path and the handling of the
+// unsafe names, the safe file path will be returned if the names be
+// sanitized.
+func (f *File) checkUnsafeNames(name, handling string) (string, error) {
+	if handling != "error" && handling != "sanitize" {
+		return name, ErrOptionsUnsafeNames
+	}
+	wb := f.workbookReader()
+	for idx, sheet := range wb.Sheets.Sheet {
+		safe, reason := getSafeSheetName(sheet.Name)
+		if reason == "" {
+			continue
+		}
+		for i, r := 2, []rune(safe); f.GetSheetIndex(safe) != -1; i++ {
+			suffix := " (" + strconv.Itoa(i) + ")"
+			if len(r) > 31-len(suffix) {
+				r = r[:31-len(suffix)]
+			}
+			safe = string(r) + suffix
+		}
+		if handling == "error" {
+			return name, &UnsafeNameError{Name: sheet.Name, Reason: reason, Suggestion: safe}
+		}
+		wb.Sheets.Sheet[idx].Name = safe
+		f.sheetMap[safe] = f.sheetMap[sheet.Name]
+		delete(f.sheetMap, sheet.Name)
+		f.renameSheetRefs(sheet.Name, safe)
+		f.updateAnnotationsSheet(sheet.Name, safe)
+	}
+	dir, base := filepath.Split(name)
+	safe, reason := getSafeFileName(base)
+	if reason == "" {
+		return name, nil
+	}
+	if handling == "error" {
+		return name, &UnsafeNameError{Name: base, Reason: reason, Suggestion: safe}
+	}
+	return dir + safe, nil
+}
+
+// renameSheetRefs provides a function to update the worksheet name in the
+// references of the formulas of the cells and the defined names in the
+// workbook on renaming the worksheet.
+func (f *File) renameSheetRefs(oldName, newName string) {
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			continue
+		}
+		for i := range ws.SheetData.Row {
+			for j := range ws.SheetData.Row[i].C {
+				if c := &ws.SheetData.Row[i].C[j]; c.F != nil && c.F.Content != "" {
+					c.F.Content = renameFormulaSheet(c.F.Content, oldName, newName)
+				}
+			}
+		}
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			dn := &wb.DefinedNames.DefinedName[idx]
+			dn.Data = renameFormulaSheet(dn.Data, oldName, newName)
+		}
+	}
+}
+
+// renameFormulaSheet provides a function to replace the worksheet name of
+// the references in the formula by given old and new worksheet name. The
+// formula will be returned without changes if it doesn't refer to the
+// worksheet or it can't be parsed.
+func renameFormulaSheet(formula, oldName, newName string) string {
+	ast, err := parse.Formula(strings.TrimPrefix(formula, "="))
+	if err != nil {
+		return formula
+	}
+	var changed bool
+	ast.RewriteReferences(func(ref parse.Reference) parse.Reference {
+		if ref.Sheet != "" && strings.EqualFold(ref.Sheet, oldName) {
+			changed, ref.Sheet = true, newName
+		}
+		return ref
+	})
+	if !changed {
+		return formula
+	}
+	if strings.HasPrefix(formula, "=") {
+		return "=" + ast.String()
+	}
+	return ast.String()
+}
+
+// getSafeFileName provides a function to get the safe file name which could
+// be stored on SharePoint and OneDrive by given file name without the
+// directory, and the reason of the file name is unsafe, an empty reason will
+// be returned if the file name is safe.
+func getSafeFileName(name string) (string, string) {
+	var reasons []string
+	if strings.IndexFunc(name, func(r rune) bool { return r < 32 || strings.ContainsRune(`"*:<>?/\|`, r) }) != -1 {
+		reasons = append(reasons, "contains illegal characters")
+		name = strings.Map(func(r rune) rune {
+			if r < 32 || strings.ContainsRune(`"*:<>?/\|`, r) {
+				return '_'
+			}
+			return r
+		}, name)
+	}
+	if trimmed := strings.TrimRight(strings.TrimSpace(name), ". "); trimmed != name {
+		reasons = append(reasons, "has leading or trailing spaces or dots")
+		name = trimmed
+	}
+	if strings.HasPrefix(name, "~$") {
+		reasons = append(reasons, "starts with ~$")
+		name = strings.TrimPrefix(name, "~$")
+	}
+	if strings.Contains(strings.ToLower(name), "_vti_") {
+		reasons = append(reasons, "contains _vti_")
+		for idx := strings.Index(strings.ToLower(name), "_vti_"); idx != -1; idx = strings.Index(strings.ToLower(name), "_vti_") {
+			name = name[:idx] + name[idx+1:]
+		}
+	}
+	stem, ext := name, ""
+	if idx := strings.Index(name, "."); idx != -1 {
+		stem, ext = name[:idx], name[idx:]
+	}
+	if inStrSlice(reservedFileNames, strings.ToUpper(stem)) != -1 {
+		reasons = append(reasons, "is a reserved name")
+		stem += "_"
+	}
+	if stem == "" {
+		reasons = append(reasons, "is blank")
+		stem = "Book1"
+	}
+	return stem + ext, strings.Join(reasons, ", ")
+}
+
+// getSafeSheetName provides a function to get the safe worksheet name by
+// given worksheet name, and the reason of the worksheet name is unsafe, an
+// empty reason will be returned if the worksheet name is safe.
+func getSafeSheetName(name string) (string, string) {
+	var reasons []string
+	if strings.ContainsAny(name, ":\\/?*[]") {
+		reasons = append(reasons, "contains illegal characters")
+		name = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(":\\/?*[]", r) {
+				return '_'
+			}
+			return r
+		}, name)
+	}
+	if trimmed := strings.Trim(strings.TrimSpace(name), "'"); trimmed != name {
+		reasons = append(reasons, "has leading or trailing spaces or apostrophes")
+		name = trimmed
+	}
+	if r := []rune(name); len(r) > 31 {
+		reasons = append(reasons, "exceeds 31 characters")
+		name = strings.TrimSpace(string(r[:31]))
+	}
+	if strings.EqualFold(name, "History") {
+		reasons = append(reasons, "is a reserved name")
+		name += "1"
+	}
+	if name == "" {
+		reasons = append(reasons, "is blank")
+		name = "Sheet1"
+	}
+	return name, strings.Join(reasons, ", ")
+}
+
+// createTempFile provides a function to create a new temporary file in the
+// directory of the given file path for the atomic saving. The permission of
+// the temporary file is the same with the file created by the os.Create
+// function.
+func createTempFile(name string) (*os.File, error) {
+	dir, base := filepath.Split(name)
+	for i := 0; ; i++ {
+		file, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf(".~%s.%d.%d.tmp", base, os.Getpid(), time.Now().UnixNano()+int64(i))),
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		return file, err
+	}
+}
+
+// syncDir provides a function to flush the directory entries of the given
+// directory to the stable storage, the errors will be ignored on the
+// platforms which not support synchronizing the directory.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
 	}
-	return f.Write(file)
 }
 
 // Write provides a function to write to an io.Writer.
@@ -140,8 +392,10 @@ func (f *File) writeDirectToWriter(w io.Writer) error {
 func (f *File) writeToZip(zw *zip.Writer) error {
 	f.calcChainWriter()
 	f.commentsWriter()
+	f.connectionsWriter()
 	f.contentTypesWriter()
 	f.drawingsWriter()
+	f.metadataWriter()
 	f.vmlDrawingWriter()
 	f.workBookWriter()
 	f.workSheetWriter()
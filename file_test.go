@@ -3,7 +3,9 @@ package excelize
 import (
 	"bufio"
 	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -68,3 +70,104 @@ func TestWriteTo(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestSaveAsAtomic(t *testing.T) {
+	path := filepath.Join("test", "TestSaveAsAtomic.xlsx")
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "original"))
+	assert.NoError(t, f.SaveAs(path, Options{Fsync: true}))
+	assert.NoError(t, os.Chmod(path, 0600))
+	original, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+
+	// Test the existing spreadsheet will be kept when the saving failed.
+	f.Pkg.Store("/d/", []byte("s"))
+	assert.EqualError(t, f.SaveAs(path), "zip: write to directory")
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original, content)
+	f.Pkg.Delete("/d/")
+
+	// Test overwrite the existing spreadsheet with the same permission.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "updated"))
+	assert.NoError(t, f.Save())
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", value)
+
+	// Test no temporary files left in the destination directory.
+	files, err := ioutil.ReadDir("test")
+	assert.NoError(t, err)
+	for _, file := range files {
+		assert.False(t, strings.HasPrefix(file.Name(), ".~"), file.Name())
+	}
+}
+
+func TestSaveAsUnsafeNames(t *testing.T) {
+	f := NewFile()
+	// Test save the spreadsheet with the unsafe file name.
+	err := f.SaveAs(filepath.Join("test", "Report:2021 .xlsx."), Options{UnsafeNames: "error"})
+	assert.EqualError(t, err, `the name "Report:2021 .xlsx." contains illegal characters, has leading or trailing spaces or dots, consider using "Report_2021 .xlsx" instead`)
+	unsafeErr, ok := err.(*UnsafeNameError)
+	assert.True(t, ok)
+	assert.Equal(t, "Report_2021 .xlsx", unsafeErr.Suggestion)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "Report:2021 .xlsx."), Options{UnsafeNames: "sanitize"}))
+	assert.Equal(t, filepath.Join("test", "Report_2021 .xlsx"), f.Path)
+	_, err = os.Stat(f.Path)
+	assert.NoError(t, err)
+	// Test save the spreadsheet with the safe names.
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsUnsafeNames.xlsx"), Options{UnsafeNames: "error"}))
+	// Test save the spreadsheet with invalid unsafe names option.
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveAsUnsafeNames.xlsx"), Options{UnsafeNames: "unknown"}), ErrOptionsUnsafeNames.Error())
+
+	// Test save the spreadsheet with the unsafe worksheet names.
+	f.NewSheet("History1")
+	f.NewSheet("Sheet3")
+	wb := f.workbookReader()
+	wb.Sheets.Sheet[0].Name = "history"
+	f.sheetMap["history"] = f.sheetMap["Sheet1"]
+	wb.Sheets.Sheet[2].Name = "'Q1/Q2'"
+	f.sheetMap["'Q1/Q2'"] = f.sheetMap["Sheet3"]
+	assert.NoError(t, f.SetCellFormula("History1", "A1", "=history!A1+'''Q1/Q2'''!B2:B3"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "HISTORY!$A$1:$A$2"}))
+	assert.NoError(t, f.SetCellAnnotation("history", &Annotation{Range: "A1", Source: "file"}))
+	err = f.SaveAs(filepath.Join("test", "TestSaveAsUnsafeNames.xlsx"), Options{UnsafeNames: "error"})
+	assert.EqualError(t, err, `the name "history" is a reserved name, consider using "history1 (2)" instead`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsUnsafeNames.xlsx"), Options{UnsafeNames: "sanitize"}))
+	assert.Equal(t, []string{"history1 (2)", "History1", "Q1_Q2"}, f.GetSheetList())
+	formula, err := f.GetCellFormula("History1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "='history1 (2)'!A1+Q1_Q2!B2:B3", formula)
+	assert.Equal(t, "'history1 (2)'!$A$1:$A$2", f.GetDefinedName()[0].RefersTo)
+	annotations, err := f.GetAnnotations("history1 (2)")
+	assert.NoError(t, err)
+	assert.Equal(t, []Annotation{{Range: "A1", Source: "file"}}, annotations)
+	assert.NoError(t, f.SetCellValue("Q1_Q2", "A1", 1))
+
+	for _, c := range []struct{ name, safe, reason string }{
+		{"~$Book1.xlsx", "Book1.xlsx", "starts with ~$"},
+		{"a_vti_b.xlsx", "avti_b.xlsx", "contains _vti_"},
+		{"con.xlsx", "con_.xlsx", "is a reserved name"},
+		{" .xlsx", "Book1.xlsx", "has leading or trailing spaces or dots, is blank"},
+		{"Book1.xlsx", "Book1.xlsx", ""},
+	} {
+		safe, reason := getSafeFileName(c.name)
+		assert.Equal(t, c.safe, safe, c.name)
+		assert.Equal(t, c.reason, reason, c.name)
+	}
+	for _, c := range []struct{ name, safe, reason string }{
+		{strings.Repeat("s", 32), strings.Repeat("s", 31), "exceeds 31 characters"},
+		{"[]", "__", "contains illegal characters"},
+		{"''", "Sheet1", "has leading or trailing spaces or apostrophes, is blank"},
+		{"Sheet1", "Sheet1", ""},
+	} {
+		safe, reason := getSafeSheetName(c.name)
+		assert.Equal(t, c.safe, safe, c.name)
+		assert.Equal(t, c.reason, reason, c.name)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// fontContentTypes defined the content types of the embedded font parts.
+var fontContentTypes = map[string]bool{
+	ContentTypeObfuscatedFont:     true,
+	ContentTypeFontTTF:            true,
+	ContentTypeFontOTF:            true,
+	"application/x-fontdata":      true,
+	"application/x-font-truetype": true,
+	"application/vnd.ms-opentype": true,
+	"font/ttf":                    true,
+	"font/otf":                    true,
+}
+
+// EmbeddedFont directly maps the settings of the font part embedded in the
+// workbook. The Family is the font family name read from the naming table
+// of the font, the Data is the de-obfuscated font data for the obfuscated
+// font.
+type EmbeddedFont struct {
+	Family      string
+	Path        string
+	ContentType string
+	Obfuscated  bool
+	Data        []byte
+}
+
+// GetEmbeddedFonts provides a function to get the fonts embedded in the
+// workbook, the font parts will be preserved on saving the workbook. The
+// family name of the obfuscated font will be empty if the key of the font
+// can't be derived from the part name. For example:
+//
+//    for _, font := range f.GetEmbeddedFonts() {
+//        fmt.Println(font.Family, font.Path, font.Obfuscated)
+//    }
+//
+func (f *File) GetEmbeddedFonts() []EmbeddedFont {
+	var fonts []EmbeddedFont
+	overrides, defaults := map[string]string{}, map[string]string{}
+	content := f.contentTypesReader()
+	content.Lock()
+	for _, override := range content.Overrides {
+		overrides[strings.TrimPrefix(override.PartName, "/")] = override.ContentType
+	}
+	for _, def := range content.Defaults {
+		defaults[strings.ToLower(def.Extension)] = def.ContentType
+	}
+	content.Unlock()
+	var paths []string
+	f.Pkg.Range(func(k, v interface{}) bool {
+		name := k.(string)
+		contentType, ok := overrides[name]
+		if !ok {
+			contentType = defaults[strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))]
+		}
+		if fontContentTypes[contentType] {
+			paths = append(paths, name)
+		}
+		return true
+	})
+	sort.Strings(paths)
+	for _, fontPath := range paths {
+		contentType, ok := overrides[fontPath]
+		if !ok {
+			contentType = defaults[strings.ToLower(strings.TrimPrefix(path.Ext(fontPath), "."))]
+		}
+		font := EmbeddedFont{
+			Path:        fontPath,
+			ContentType: contentType,
+			Obfuscated:  contentType == ContentTypeObfuscatedFont,
+			Data:        append([]byte{}, f.readXML(fontPath)...),
+		}
+		if font.Obfuscated {
+			key, ok := getFontKey(fontPath)
+			if !ok {
+				fonts = append(fonts, font)
+				continue
+			}
+			obfuscateFont(font.Data, key)
+		}
+		font.Family, _ = getFontFamilyName(font.Data)
+		fonts = append(fonts, font)
+	}
+	return fonts
+}
+
+// AddEmbeddedFont provides a function to embed the TrueType or OpenType font
+// into the workbook by given font data, the font will be used by the
+// applications which support the embedded fonts on rendering the cells,
+// shapes and charts with the same font family if the font isn't installed.
+// The font will be obfuscated with a random key as the Office applications
+// do if the obfuscate is true. For example, embed the font file
+// CustomFont.ttf:
+//
+//    file, err := ioutil.ReadFile("CustomFont.ttf")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.AddEmbeddedFont(file, true); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) AddEmbeddedFont(data []byte, obfuscate bool) error {
+	if _, err := getFontFamilyName(data); err != nil {
+		return err
+	}
+	ext, contentType := ".ttf", ContentTypeFontTTF
+	if bytes.HasPrefix(data, []byte("OTTO")) {
+		ext, contentType = ".otf", ContentTypeFontOTF
+	}
+	var fontPath string
+	font := append([]byte{}, data...)
+	if obfuscate {
+		guid, err := newGUID()
+		if err != nil {
+			return err
+		}
+		fontPath, contentType = "xl/fonts/"+guid+".odttf", ContentTypeObfuscatedFont
+		key, _ := getFontKey(fontPath)
+		obfuscateFont(font, key)
+	}
+	for idx := 1; fontPath == ""; idx++ {
+		if _, ok := f.Pkg.Load("xl/fonts/font" + strconv.Itoa(idx) + ext); !ok {
+			fontPath = "xl/fonts/font" + strconv.Itoa(idx) + ext
+		}
+	}
+	f.Pkg.Store(fontPath, font)
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipFont, strings.TrimPrefix(fontPath, "xl/"), "")
+	f.addContentTypeOverride("/"+fontPath, contentType)
+	return nil
+}
+
+// getFontKey provides a function to get the key of the obfuscated font from
+// the GUID in the part name, the key is the bytes of the GUID in reverse
+// order.
+func getFontKey(fontPath string) ([]byte, bool) {
+	name := strings.TrimSuffix(path.Base(fontPath), path.Ext(fontPath))
+	guid, err := hex.DecodeString(strings.Replace(strings.Trim(name, "{}"), "-", "", -1))
+	if err != nil || len(guid) != 16 {
+		return nil, false
+	}
+	key := make([]byte, 16)
+	for i := range guid {
+		key[i] = guid[15-i]
+	}
+	return key, true
+}
+
+// obfuscateFont provides a function to obfuscate or de-obfuscate the font
+// data by given key, the first 32 bytes of the font data will be XORed with
+// the key.
+func obfuscateFont(data, key []byte) {
+	for i := 0; i < 32 && i < len(data); i++ {
+		data[i] ^= key[i%len(key)]
+	}
+}
+
+// getFontFamilyName provides a function to read the font family name from
+// the naming table of the TrueType or OpenType font data. The names for the
+// Windows platform will be preferred.
+func getFontFamilyName(data []byte) (string, error) {
+	if len(data) < 12 {
+		return "", ErrFontFile
+	}
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+	default:
+		return "", ErrFontFile
+	}
+	var table []byte
+	for i, numTables := 0, int(binary.BigEndian.Uint16(data[4:6])); i < numTables; i++ {
+		rec := 12 + i*16
+		if rec+16 > len(data) {
+			break
+		}
+		if string(data[rec:rec+4]) == "name" {
+			offset, length := int(binary.BigEndian.Uint32(data[rec+8:])), int(binary.BigEndian.Uint32(data[rec+12:]))
+			if offset+length <= len(data) {
+				table = data[offset : offset+length]
+			}
+			break
+		}
+	}
+	if len(table) < 6 {
+		return "", ErrFontFile
+	}
+	names := map[uint16]string{}
+	count, storage := int(binary.BigEndian.Uint16(table[2:4])), int(binary.BigEndian.Uint16(table[4:6]))
+	for i := 0; i < count && 6+i*12+12 <= len(table); i++ {
+		rec := table[6+i*12 : 6+i*12+12]
+		platformID, nameID := binary.BigEndian.Uint16(rec[0:2]), binary.BigEndian.Uint16(rec[6:8])
+		length, offset := int(binary.BigEndian.Uint16(rec[8:10])), storage+int(binary.BigEndian.Uint16(rec[10:12]))
+		if _, ok := names[platformID]; ok || nameID != 1 || offset+length > len(table) {
+			continue
+		}
+		value := table[offset : offset+length]
+		if platformID == 1 {
+			names[platformID] = string(value)
+			continue
+		}
+		u := make([]uint16, len(value)/2)
+		for j := range u {
+			u[j] = binary.BigEndian.Uint16(value[j*2:])
+		}
+		names[platformID] = string(utf16.Decode(u))
+	}
+	for _, platformID := range []uint16{3, 0, 1} {
+		if name, ok := names[platformID]; ok && name != "" {
+			return name, nil
+		}
+	}
+	return "", ErrFontFile
+}
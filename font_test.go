@@ -0,0 +1,110 @@
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestFont provides a function to create the minimal font data with the
+// naming table which contains the font family name for the Macintosh and
+// Windows platforms.
+func newTestFont(version, family string) []byte {
+	var names, storage bytes.Buffer
+	record := func(platformID, encodingID, languageID, nameID uint16, value []byte) {
+		_ = binary.Write(&names, binary.BigEndian, []uint16{platformID, encodingID, languageID, nameID, uint16(len(value)), uint16(storage.Len())})
+		storage.Write(value)
+	}
+	record(1, 0, 0, 1, []byte("Mac "+family))
+	record(3, 1, 0x409, 2, []byte{0, 'R'})
+	encoded := utf16.Encode([]rune(family))
+	value := make([]byte, len(encoded)*2)
+	for i, c := range encoded {
+		binary.BigEndian.PutUint16(value[i*2:], c)
+	}
+	record(3, 1, 0x409, 1, value)
+	var table bytes.Buffer
+	_ = binary.Write(&table, binary.BigEndian, []uint16{0, 3, uint16(6 + names.Len())})
+	table.Write(names.Bytes())
+	table.Write(storage.Bytes())
+	var buf bytes.Buffer
+	buf.WriteString(version)
+	_ = binary.Write(&buf, binary.BigEndian, []uint16{1, 16, 0, 0})
+	buf.WriteString("name")
+	_ = binary.Write(&buf, binary.BigEndian, []uint32{0, 28, uint32(table.Len())})
+	buf.Write(table.Bytes())
+	return buf.Bytes()
+}
+
+func TestEmbeddedFonts(t *testing.T) {
+	f := NewFile()
+	assert.Empty(t, f.GetEmbeddedFonts())
+	font, otf := newTestFont("\x00\x01\x00\x00", "Custom Sans"), newTestFont("OTTO", "Custom Serif")
+	assert.NoError(t, f.AddEmbeddedFont(font, false))
+	assert.NoError(t, f.AddEmbeddedFont(font, true))
+	assert.NoError(t, f.AddEmbeddedFont(otf, false))
+	// Test the obfuscated font data in the package.
+	fonts := f.GetEmbeddedFonts()
+	assert.Len(t, fonts, 3)
+	content, ok := f.Pkg.Load(fonts[2].Path)
+	assert.True(t, ok)
+	assert.NotEqual(t, font[:32], content.([]byte)[:32])
+	assert.Equal(t, font[32:], content.([]byte)[32:])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestEmbeddedFonts.xlsx")))
+
+	// Test get embedded fonts from the saved workbook.
+	f, err := OpenFile(filepath.Join("test", "TestEmbeddedFonts.xlsx"))
+	assert.NoError(t, err)
+	fonts = f.GetEmbeddedFonts()
+	assert.Len(t, fonts, 3)
+	for i, expected := range []struct {
+		family, path, contentType string
+		obfuscated                bool
+		data                      []byte
+	}{
+		{"Custom Serif", "xl/fonts/font1.otf", ContentTypeFontOTF, false, otf},
+		{"Custom Sans", "xl/fonts/font1.ttf", ContentTypeFontTTF, false, font},
+		{"Custom Sans", fonts[2].Path, ContentTypeObfuscatedFont, true, font},
+	} {
+		assert.Equal(t, expected.family, fonts[i].Family)
+		assert.Equal(t, expected.path, fonts[i].Path)
+		assert.Equal(t, expected.contentType, fonts[i].ContentType)
+		assert.Equal(t, expected.obfuscated, fonts[i].Obfuscated)
+		assert.Equal(t, expected.data, fonts[i].Data)
+	}
+	assert.Regexp(t, `^xl/fonts/\{[0-9A-F-]{36}\}\.odttf$`, fonts[2].Path)
+	var count int
+	for _, rel := range f.relsReader(f.getWorkbookRelsPath()).Relationships {
+		if rel.Type == SourceRelationshipFont {
+			count++
+		}
+	}
+	assert.Equal(t, 3, count)
+	// Test embed the same font again.
+	assert.NoError(t, f.AddEmbeddedFont(font, false))
+	assert.Equal(t, "xl/fonts/font2.ttf", f.GetEmbeddedFonts()[2].Path)
+
+	// Test get embedded fonts by the default content types, and the
+	// obfuscated font without the key in the part name.
+	f = NewFile()
+	f.ContentTypes.Defaults = append(f.ContentTypes.Defaults, xlsxDefault{Extension: "odttf", ContentType: ContentTypeObfuscatedFont}, xlsxDefault{Extension: "TTF", ContentType: ContentTypeFontTTF})
+	f.Pkg.Store("xl/fonts/font1.odttf", font)
+	f.Pkg.Store("xl/fonts/font2.ttf", []byte("invalid"))
+	fonts = f.GetEmbeddedFonts()
+	assert.Len(t, fonts, 2)
+	assert.Equal(t, EmbeddedFont{Path: "xl/fonts/font1.odttf", ContentType: ContentTypeObfuscatedFont, Obfuscated: true, Data: font}, fonts[0])
+	assert.Equal(t, EmbeddedFont{Path: "xl/fonts/font2.ttf", ContentType: ContentTypeFontTTF, Data: []byte("invalid")}, fonts[1])
+
+	// Test embed the unsupported font files.
+	for _, data := range [][]byte{nil, []byte("wOFF00000000"), font[:24], append(append([]byte{}, font[:12]...), []byte("head0000")...), font[:40]} {
+		assert.EqualError(t, f.AddEmbeddedFont(data, false), ErrFontFile.Error())
+	}
+	// Test get the font family name for the Macintosh platform.
+	family, err := getFontFamilyName(newTestFont("true", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, "Mac ", family)
+}
@@ -0,0 +1,175 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FontMetrics defined the interface to measure the width of the text in
+// pixels at 96 DPI by given font settings, the font family, size, bold and
+// italic settings of the font could be used for measurement. Implement this
+// interface and register it by RegisterFontMetrics to get accurate widths
+// for the custom fonts.
+type FontMetrics interface {
+	TextWidth(text string, font *Font) float64
+}
+
+// glyphMetrics defined the built-in font metrics by the advance widths of the
+// printable ASCII characters in 1/1000 em units, the widths of the bold
+// characters will be used when it is available.
+type glyphMetrics struct {
+	widths, boldWidths [95]float64
+}
+
+// Define the default font settings for the text measurement.
+const (
+	defaultFontFamily = "Calibri"
+	defaultFontSize   = 11
+)
+
+var (
+	// calibriMetrics defined the advance widths of the Calibri font.
+	calibriMetrics = &glyphMetrics{
+		widths: [95]float64{
+			226, 326, 401, 498, 507, 715, 682, 221, 303, 303, 498, 498, 250, 306, 252, 386,
+			507, 507, 507, 507, 507, 507, 507, 507, 507, 507, 268, 268, 498, 498, 498, 463,
+			894, 579, 544, 533, 615, 488, 459, 631, 623, 252, 319, 520, 420, 855, 646, 662,
+			517, 673, 543, 459, 487, 642, 567, 890, 519, 487, 468, 307, 386, 307, 498, 498,
+			291, 479, 525, 423, 525, 498, 305, 471, 525, 230, 239, 455, 230, 799, 525, 527,
+			525, 525, 349, 391, 335, 525, 452, 715, 433, 453, 395, 314, 460, 314, 498,
+		},
+		boldWidths: [95]float64{
+			226, 326, 438, 498, 507, 729, 705, 233, 312, 312, 498, 498, 258, 306, 267, 430,
+			507, 507, 507, 507, 507, 507, 507, 507, 507, 507, 276, 276, 498, 498, 498, 463,
+			898, 606, 561, 529, 630, 488, 459, 637, 631, 267, 331, 547, 423, 874, 659, 676,
+			532, 686, 563, 473, 495, 653, 591, 906, 551, 520, 478, 325, 430, 325, 498, 498,
+			300, 494, 537, 418, 537, 503, 316, 474, 537, 246, 255, 480, 246, 813, 537, 538,
+			537, 537, 355, 399, 347, 537, 473, 745, 459, 474, 397, 344, 475, 344, 498,
+		},
+	}
+	// arialMetrics defined the advance widths of the Arial font.
+	arialMetrics = &glyphMetrics{
+		widths: [95]float64{
+			278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+			556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+			1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+			667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+			333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+			556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+		},
+		boldWidths: [95]float64{
+			278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+			556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+			975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+			667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+			333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+			611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+		},
+	}
+	// fontMetrics defined the registered font metrics by the lower case font
+	// family name.
+	fontMetrics sync.Map
+)
+
+func init() {
+	for _, family := range []string{"Calibri", "Calibri Light"} {
+		RegisterFontMetrics(family, calibriMetrics)
+	}
+	for _, family := range []string{"Arial", "Helvetica", "Liberation Sans"} {
+		RegisterFontMetrics(family, arialMetrics)
+	}
+}
+
+// RegisterFontMetrics provides a function to register the font metrics by
+// given font family name, the font family name is case insensitive, and the
+// registered font metrics will override the built-in metrics with the same
+// font family name. The built-in metrics are available for the Calibri and
+// Arial font families, the other fonts will be measured by the Calibri
+// metrics, and the characters of the CJK and full-width forms will be
+// measured as the full-width glyphs. For example, register the metrics for a
+// custom font:
+//
+//    type corporateFont struct{}
+//
+//    func (corporateFont) TextWidth(text string, font *excelize.Font) float64 {
+//        return float64(len([]rune(text))) * font.Size * 0.6 * 96 / 72
+//    }
+//
+//    excelize.RegisterFontMetrics("Corporate Sans", corporateFont{})
+//
+func RegisterFontMetrics(family string, metrics FontMetrics) {
+	fontMetrics.Store(strings.ToLower(family), metrics)
+}
+
+// GetFontMetrics provides a function to get the font metrics by given font
+// family name, the default Calibri metrics will be returned if the metrics of
+// the font family has not been registered.
+func GetFontMetrics(family string) FontMetrics {
+	if metrics, ok := fontMetrics.Load(strings.ToLower(family)); ok {
+		return metrics.(FontMetrics)
+	}
+	return calibriMetrics
+}
+
+// TextWidth provides a function to measure the width of the longest line of
+// the text in pixels at 96 DPI by given font settings.
+func (m *glyphMetrics) TextWidth(text string, font *Font) float64 {
+	size, bold := float64(defaultFontSize), false
+	if font != nil {
+		if font.Size > 0 {
+			size = font.Size
+		}
+		bold = font.Bold
+	}
+	widths := m.widths
+	if bold && m.boldWidths[0] != 0 {
+		widths = m.boldWidths
+	}
+	var max float64
+	for _, line := range strings.Split(text, "\n") {
+		var units float64
+		for _, r := range line {
+			units += glyphWidth(r, &widths)
+		}
+		max = math.Max(max, units)
+	}
+	return max * size / 1000 * 96 / 72
+}
+
+// glyphWidth provides a function to get the advance width of the character
+// in 1/1000 em units by given character and the widths of the printable
+// ASCII characters, the widths of the characters in the CJK scripts and
+// full-width forms falls back to the full-width, and the other characters
+// falls back to the width of the digit.
+func glyphWidth(r rune, widths *[95]float64) float64 {
+	if r >= ' ' && r <= '~' {
+		return widths[r-' ']
+	}
+	if isFullWidthRune(r) {
+		return 1000
+	}
+	if unicode.IsControl(r) || unicode.Is(unicode.Mn, r) {
+		return 0
+	}
+	return widths['0'-' ']
+}
+
+// isFullWidthRune provides a function to check if the character should be
+// displayed as the full-width glyph.
+func isFullWidthRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF01 && r <= 0xFF60) || (r >= 0xFFE0 && r <= 0xFFE6)
+}
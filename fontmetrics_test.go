@@ -0,0 +1,36 @@
+package excelize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFontMetrics struct{}
+
+func (testFontMetrics) TextWidth(text string, font *Font) float64 {
+	return float64(len([]rune(text))) * font.Size
+}
+
+func TestFontMetrics(t *testing.T) {
+	assert.Equal(t, calibriMetrics, GetFontMetrics("calibri"))
+	assert.Equal(t, arialMetrics, GetFontMetrics("ARIAL"))
+	assert.Equal(t, calibriMetrics, GetFontMetrics("Corporate Sans"))
+
+	// Test measure the text width with the built-in metrics.
+	assert.Equal(t, 7, int(GetFontMetrics("Calibri").TextWidth("0", nil)))
+	assert.Equal(t, 7, int(GetFontMetrics("Arial").TextWidth("0", &Font{Size: 10})))
+	assert.Equal(t, 11.0/1000*96/72*(579+525+525+230+498), calibriMetrics.TextWidth("Apple", nil))
+	assert.Greater(t, arialMetrics.TextWidth("Apple", &Font{Size: 10, Bold: true}), arialMetrics.TextWidth("Apple", &Font{Size: 10}))
+	// Test measure the multi-line text by the longest line.
+	assert.Equal(t, calibriMetrics.TextWidth("Apple", nil), calibriMetrics.TextWidth("Pea\nApple\n", nil))
+	// Test measure the CJK, combining marks and the other characters.
+	assert.Equal(t, 11.0/1000*96/72*2000, calibriMetrics.TextWidth("中文", nil))
+	assert.Equal(t, 11.0/1000*96/72*2000, calibriMetrics.TextWidth("ＡＢ", nil))
+	assert.Equal(t, calibriMetrics.TextWidth("e0", nil), calibriMetrics.TextWidth("éé", nil))
+
+	// Test register the custom font metrics.
+	RegisterFontMetrics("Corporate Sans", testFontMetrics{})
+	defer fontMetrics.Delete("corporate sans")
+	assert.Equal(t, 24.0, GetFontMetrics("corporate sans").TextWidth("abc", &Font{Size: 8}))
+}
@@ -0,0 +1,273 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+// Package fx providing a set of functions that allow you to build the
+// formulas of the spreadsheet programmatically, the worksheet names, the
+// texts and the table column names will be quoted and escaped when it's
+// required, so it's safer than concatenating the formula strings, especially
+// with user-provided worksheet names. For example, set the formula of the
+// total amount:
+//
+//    formula := fx.Sum(fx.Sheet("Sales 2021").Range("B2", "B10")).Mul(fx.Name("Rate"))
+//    err := f.SetCellFormula("Sheet1", "A1", formula.String())
+//
+package fx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
+)
+
+// Expr directly maps the expression of the formula. The zero value of the
+// expression is the omitted argument of the functions.
+type Expr struct {
+	node *parse.Node
+}
+
+// Sheet directly maps the worksheet name of the references.
+type Sheet string
+
+// String returns the formula text of the expression without the leading
+// equal sign, which could be used in the SetCellFormula function.
+func (e Expr) String() string {
+	return parse.AST{Root: e.tree()}.String()
+}
+
+// Format returns the formula text of the expression by given format
+// settings, such as the culture-specific list separator and decimal
+// separator which are used in the formula bar.
+func (e Expr) Format(opts parse.FormatOptions) string {
+	return parse.AST{Root: e.tree()}.Format(opts)
+}
+
+// AST returns the abstract syntax tree of the expression.
+func (e Expr) AST() parse.AST {
+	return parse.AST{Root: e.tree()}
+}
+
+// tree returns the node of the expression, the empty node will be returned
+// for the zero value of the expression.
+func (e Expr) tree() *parse.Node {
+	if e.node == nil {
+		return &parse.Node{Type: parse.NodeEmpty}
+	}
+	return e.node
+}
+
+// Num returns the number expression. The NaN and infinity numbers will be
+// converted to the #NUM! error.
+func Num(v float64) Expr {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Err("#NUM!")
+	}
+	return Expr{node: &parse.Node{Type: parse.NodeNumber, Value: strconv.FormatFloat(v, 'f', -1, 64)}}
+}
+
+// Int returns the integer number expression.
+func Int(v int) Expr {
+	return Expr{node: &parse.Node{Type: parse.NodeNumber, Value: strconv.Itoa(v)}}
+}
+
+// Str returns the text expression, the double quotes in the text will be
+// escaped.
+func Str(s string) Expr {
+	return Expr{node: &parse.Node{Type: parse.NodeText, Value: s}}
+}
+
+// Bool returns the TRUE or FALSE expression.
+func Bool(b bool) Expr {
+	return Expr{node: &parse.Node{Type: parse.NodeLogical, Value: strings.ToUpper(strconv.FormatBool(b))}}
+}
+
+// Err returns the error expression, such as #N/A and #DIV/0!.
+func Err(code string) Expr {
+	return Expr{node: &parse.Node{Type: parse.NodeError, Value: code}}
+}
+
+// reference returns the reference expression by given reference.
+func reference(ref parse.Reference) Expr {
+	value := ref.Ref
+	if ref.Sheet != "" {
+		value = ref.Sheet + "!" + ref.Ref
+	}
+	return Expr{node: &parse.Node{Type: parse.NodeReference, Value: value}}
+}
+
+// Ref returns the cell reference expression in the current worksheet, such
+// as A1 or $A$1.
+func Ref(cell string) Expr {
+	return reference(parse.Reference{Ref: cell})
+}
+
+// Range returns the range reference expression in the current worksheet by
+// given the top left cell and the bottom right cell of the range.
+func Range(from, to string) Expr {
+	return reference(parse.Reference{Ref: from + ":" + to})
+}
+
+// Name returns the defined name expression.
+func Name(name string) Expr {
+	return reference(parse.Reference{Ref: name})
+}
+
+// Column returns the structured reference expression of the table column,
+// the special characters in the column name will be escaped. For example,
+// fx.Column("Sales", "Amount") returns Sales[Amount].
+func Column(table, column string) Expr {
+	escaped := strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(column)
+	return reference(parse.Reference{Ref: table + "[" + escaped + "]"})
+}
+
+// Ref returns the cell reference expression in the worksheet.
+func (s Sheet) Ref(cell string) Expr {
+	return reference(parse.Reference{Sheet: string(s), Ref: cell})
+}
+
+// Range returns the range reference expression in the worksheet by given the
+// top left cell and the bottom right cell of the range.
+func (s Sheet) Range(from, to string) Expr {
+	return reference(parse.Reference{Sheet: string(s), Ref: from + ":" + to})
+}
+
+// Name returns the defined name expression in the scope of the worksheet.
+func (s Sheet) Name(name string) Expr {
+	return reference(parse.Reference{Sheet: string(s), Ref: name})
+}
+
+// Call returns the function call expression by given function name and
+// arguments, use the zero value of the expression for the omitted
+// arguments.
+func Call(name string, args ...Expr) Expr {
+	node := &parse.Node{Type: parse.NodeFunction, Value: strings.ToUpper(name)}
+	for _, arg := range args {
+		node.Children = append(node.Children, arg.tree())
+	}
+	return Expr{node: node}
+}
+
+// Array returns the array constant expression by given rows.
+func Array(rows ...[]Expr) Expr {
+	node := &parse.Node{Type: parse.NodeArray}
+	for _, row := range rows {
+		rowNode := &parse.Node{Type: parse.NodeArrayRow}
+		for _, element := range row {
+			rowNode.Children = append(rowNode.Children, element.tree())
+		}
+		node.Children = append(node.Children, rowNode)
+	}
+	return Expr{node: node}
+}
+
+// Paren returns the expression in the parentheses.
+func Paren(e Expr) Expr {
+	return Expr{node: &parse.Node{Type: parse.NodeParen, Children: []*parse.Node{e.tree()}}}
+}
+
+// operand returns the node of the operand, the operand will be put into the
+// parentheses if the priority of the operator is larger than the operand.
+func operand(e Expr, priority int) *parse.Node {
+	node := e.tree()
+	if parse.OperatorPriority(node) < priority {
+		return Paren(e).node
+	}
+	return node
+}
+
+// infix returns the infix operator expression, the operators in the same
+// priority are evaluated from left to right.
+func infix(operator string, left, right Expr) Expr {
+	node := &parse.Node{Type: parse.NodeInfix, Value: operator}
+	priority := parse.OperatorPriority(node)
+	node.Children = []*parse.Node{operand(left, priority), operand(right, priority+1)}
+	return Expr{node: node}
+}
+
+// Add returns the expression of e + o.
+func (e Expr) Add(o Expr) Expr { return infix("+", e, o) }
+
+// Sub returns the expression of e - o.
+func (e Expr) Sub(o Expr) Expr { return infix("-", e, o) }
+
+// Mul returns the expression of e * o.
+func (e Expr) Mul(o Expr) Expr { return infix("*", e, o) }
+
+// Div returns the expression of e / o.
+func (e Expr) Div(o Expr) Expr { return infix("/", e, o) }
+
+// Pow returns the expression of e ^ o.
+func (e Expr) Pow(o Expr) Expr { return infix("^", e, o) }
+
+// Concat returns the expression of e & o.
+func (e Expr) Concat(o Expr) Expr { return infix("&", e, o) }
+
+// Eq returns the expression of e = o.
+func (e Expr) Eq(o Expr) Expr { return infix("=", e, o) }
+
+// Ne returns the expression of e <> o.
+func (e Expr) Ne(o Expr) Expr { return infix("<>", e, o) }
+
+// Lt returns the expression of e < o.
+func (e Expr) Lt(o Expr) Expr { return infix("<", e, o) }
+
+// Le returns the expression of e <= o.
+func (e Expr) Le(o Expr) Expr { return infix("<=", e, o) }
+
+// Gt returns the expression of e > o.
+func (e Expr) Gt(o Expr) Expr { return infix(">", e, o) }
+
+// Ge returns the expression of e >= o.
+func (e Expr) Ge(o Expr) Expr { return infix(">=", e, o) }
+
+// Neg returns the expression of -e.
+func (e Expr) Neg() Expr {
+	node := &parse.Node{Type: parse.NodePrefix, Value: "-"}
+	node.Children = []*parse.Node{operand(e, parse.OperatorPriority(node))}
+	return Expr{node: node}
+}
+
+// Percent returns the expression of e%.
+func (e Expr) Percent() Expr {
+	node := &parse.Node{Type: parse.NodePostfix, Value: "%"}
+	node.Children = []*parse.Node{operand(e, parse.OperatorPriority(node))}
+	return Expr{node: node}
+}
+
+// Sum returns the expression of the SUM function.
+func Sum(args ...Expr) Expr { return Call("SUM", args...) }
+
+// Average returns the expression of the AVERAGE function.
+func Average(args ...Expr) Expr { return Call("AVERAGE", args...) }
+
+// Count returns the expression of the COUNT function.
+func Count(args ...Expr) Expr { return Call("COUNT", args...) }
+
+// CountA returns the expression of the COUNTA function.
+func CountA(args ...Expr) Expr { return Call("COUNTA", args...) }
+
+// Min returns the expression of the MIN function.
+func Min(args ...Expr) Expr { return Call("MIN", args...) }
+
+// Max returns the expression of the MAX function.
+func Max(args ...Expr) Expr { return Call("MAX", args...) }
+
+// Round returns the expression of the ROUND function.
+func Round(number, digits Expr) Expr { return Call("ROUND", number, digits) }
+
+// If returns the expression of the IF function.
+func If(condition, then, otherwise Expr) Expr { return Call("IF", condition, then, otherwise) }
+
+// IfError returns the expression of the IFERROR function.
+func IfError(value, valueIfError Expr) Expr { return Call("IFERROR", value, valueIfError) }
+
+// And returns the expression of the AND function.
+func And(args ...Expr) Expr { return Call("AND", args...) }
+
+// Or returns the expression of the OR function.
+func Or(args ...Expr) Expr { return Call("OR", args...) }
+
+// Not returns the expression of the NOT function.
+func Not(e Expr) Expr { return Call("NOT", e) }
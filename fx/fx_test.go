@@ -0,0 +1,52 @@
+package fx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpr(t *testing.T) {
+	for expected, expr := range map[string]Expr{
+		"SUM(A1:A10)":                       Sum(Range("A1", "A10")),
+		"SUM('Sales 2021'!B2:B10)*Rate":     Sum(Sheet("Sales 2021").Range("B2", "B10")).Mul(Name("Rate")),
+		"'It''s'!A1+Sheet1!$B$1":            Sheet("It's").Ref("A1").Add(Sheet("Sheet1").Ref("$B$1")),
+		`IF(A1>=10,"a ""big"" one",)`:       If(Ref("A1").Ge(Int(10)), Str(`a "big" one`), Expr{}),
+		"(1+2)*3":                           Int(1).Add(Int(2)).Mul(Int(3)),
+		"1+2*3":                             Int(1).Add(Int(2).Mul(Int(3))),
+		"1-(2-3)":                           Int(1).Sub(Int(2).Sub(Int(3))),
+		"1-2-3":                             Int(1).Sub(Int(2)).Sub(Int(3)),
+		"-(2^2)":                            Int(2).Pow(Int(2)).Neg(),
+		"(A1+1)%":                           Ref("A1").Add(Int(1)).Percent(),
+		`A1&" "&B1`:                         Ref("A1").Concat(Str(" ")).Concat(Ref("B1")),
+		"AND(A1<>B1,OR(A1<1,A1>2),NOT(C1))": And(Ref("A1").Ne(Ref("B1")), Or(Ref("A1").Lt(Int(1)), Ref("A1").Gt(Int(2))), Not(Ref("C1"))),
+		"IFERROR(A1/B1,#N/A)":               IfError(Ref("A1").Div(Ref("B1")), Err("#N/A")),
+		"ROUND(AVERAGE(A:A),2)=MAX(1.5,MIN(Sheet1!Rate))": Round(Average(Range("A", "A")), Int(2)).Eq(Max(Num(1.5), Min(Sheet("Sheet1").Name("Rate")))),
+		"COUNT(A1)+COUNTA(A1)<=TRUE":                      Count(Ref("A1")).Add(CountA(Ref("A1"))).Le(Bool(true)),
+		"SUMIF(Sales[Region],\"East\",Sales['[Amount])":   Call("sumif", Column("Sales", "Region"), Str("East"), Column("Sales", "[Amount")),
+		"SUM({1,2;3,4})": Sum(Array([]Expr{Int(1), Int(2)}, []Expr{Int(3), Int(4)})),
+		"(A1)=#NUM!":     Paren(Ref("A1")).Eq(Num(math.NaN())),
+	} {
+		assert.Equal(t, expected, expr.String())
+		// Test the formula text could be parsed into the same formula.
+		ast, err := parse.Formula(expr.String())
+		assert.NoError(t, err, expected)
+		assert.Equal(t, expected, ast.String())
+	}
+	assert.Equal(t, "", Expr{}.String())
+	assert.Equal(t, parse.NodeFunction, Sum().AST().Root.Type)
+
+	// Test format the formula with the culture-specific separators.
+	assert.Equal(t, "SUM(1,5;{2.5};(A1:A2;B1))", Sum(Num(1.5), Array([]Expr{Num(2.5)}), Paren(infix(",", Range("A1", "A2"), Ref("B1")))).Format(parse.FormatOptions{ListSeparator: ";", DecimalSeparator: ","}))
+
+	// Test calculate the formula in the workbook.
+	f := excelize.NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]int{1, 2, 3}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", Sum(Range("A1", "C1")).Mul(Int(2)).String()))
+	result, err := f.CalcCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "12", result)
+}
@@ -0,0 +1,470 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// getSheetInfo provides a function to get the settings of the worksheet,
+// chart sheet or dialog sheet by given sheet name.
+func (f *File) getSheetInfo(sheet string) (SheetInfo, error) {
+	name := trimSheetName(sheet)
+	for _, info := range f.GetSheets() {
+		if info.Name == name {
+			return info, nil
+		}
+	}
+	return SheetInfo{}, fmt.Errorf("sheet %s is not exist", sheet)
+}
+
+// sheetPartReader provides a function to read the chart sheet or dialog
+// sheet part by given part path and decode it into the given structure. The
+// attributes of the root element will be kept for saving the part.
+func (f *File) sheetPartReader(partPath string, v interface{}) error {
+	if _, ok := f.xmlAttr[partPath]; !ok {
+		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(partPath))))
+		f.xmlAttr[partPath] = append(f.xmlAttr[partPath], getRootElement(d)...)
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(partPath)))).
+		Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("xml decode error: %s", err)
+	}
+	return nil
+}
+
+// sheetPartWriter provides a function to serialize the chart sheet or dialog
+// sheet structure and save it into the given part path.
+func (f *File) sheetPartWriter(partPath string, v interface{}) error {
+	var exist bool
+	for _, attr := range f.xmlAttr[partPath] {
+		if attr.Name == SourceRelationship.Name {
+			exist = true
+		}
+	}
+	if !exist {
+		f.xmlAttr[partPath] = append(f.xmlAttr[partPath], SourceRelationship)
+	}
+	output, err := xml.Marshal(v)
+	f.saveFileList(partPath, replaceRelationshipsBytes(f.replaceNameSpaceBytes(partPath, output)))
+	return err
+}
+
+// setSheetHeaderFooter provides a function to update the header and footer
+// settings of the worksheet, chart sheet or dialog sheet by given sheet name
+// and update function.
+func (f *File) setSheetHeaderFooter(sheet string, fn func(headerFooter **xlsxHeaderFooter, legacyDrawingHF **xlsxLegacyDrawingHF) error) error {
+	info, err := f.getSheetInfo(sheet)
+	if err != nil {
+		return err
+	}
+	switch info.Type {
+	case "chartsheet":
+		cs := new(xlsxChartsheet)
+		if err = f.sheetPartReader(info.Path, cs); err != nil {
+			return err
+		}
+		if err = fn(&cs.HeaderFooter, &cs.LegacyDrawingHF); err != nil {
+			return err
+		}
+		return f.sheetPartWriter(info.Path, cs)
+	case "dialogsheet":
+		ds := new(xlsxDialogsheet)
+		if err = f.sheetPartReader(info.Path, ds); err != nil {
+			return err
+		}
+		if err = fn(&ds.HeaderFooter, &ds.LegacyDrawingHF); err != nil {
+			return err
+		}
+		return f.sheetPartWriter(info.Path, ds)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	return fn(&ws.HeaderFooter, &ws.LegacyDrawingHF)
+}
+
+// AddHeaderFooterImage provides a function to add a picture into the header
+// or footer of the worksheet, chart sheet or dialog sheet by given sheet
+// name and picture settings. The picture is placed in the left, center or
+// right section of the header or footer of the odd, even or first page. Note
+// that the text of the header or footer must contain the &G code in the same
+// section to display the picture. For example, add a logo in the center
+// section of the header:
+//
+//	file, err := ioutil.ReadFile("logo.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.SetHeaderFooter("Sheet1", &excelize.FormatHeaderFooter{
+//	    OddHeader: "&C&G",
+//	}); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.AddHeaderFooterImage("Sheet1", &excelize.FormatHeaderFooterImage{
+//	    Position:  "center",
+//	    Title:     "Logo",
+//	    Extension: ".png",
+//	    File:      file,
+//	})
+func (f *File) AddHeaderFooterImage(sheet string, opts *FormatHeaderFooterImage) error {
+	if opts == nil || len(opts.File) == 0 {
+		return ErrParameterRequired
+	}
+	shapeID, err := headerFooterShapeID(opts)
+	if err != nil {
+		return err
+	}
+	ext, ok := supportImageTypes[opts.Extension]
+	if !ok {
+		return ErrImgExt
+	}
+	img, _, err := image.DecodeConfig(bytes.NewReader(opts.File))
+	if err != nil {
+		return err
+	}
+	width, height := img.Width, img.Height
+	if opts.Width > 0 {
+		width = opts.Width
+	}
+	if opts.Height > 0 {
+		height = opts.Height
+	}
+	info, err := f.getSheetInfo(sheet)
+	if err != nil {
+		return err
+	}
+	sheetRels := path.Join(path.Dir(info.Path), "_rels", path.Base(info.Path)+".rels")
+	return f.setSheetHeaderFooter(sheet, func(_ **xlsxHeaderFooter, legacyDrawingHF **xlsxLegacyDrawingHF) error {
+		var drawingVML string
+		if *legacyDrawingHF != nil {
+			if target := f.getRelsTargetByID(sheetRels, (*legacyDrawingHF).RID); target != "" {
+				drawingVML = path.Join(path.Dir(info.Path), target)
+				if strings.HasPrefix(target, "/") {
+					drawingVML = strings.TrimPrefix(target, "/")
+				}
+			}
+		}
+		if drawingVML == "" {
+			drawingVML = fmt.Sprintf("xl/drawings/vmlDrawingHF%d.vml", f.countParts("xl/drawings/vmlDrawingHF")+1)
+			rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/"+path.Base(drawingVML), "")
+			*legacyDrawingHF = &xlsxLegacyDrawingHF{RID: "rId" + strconv.Itoa(rID)}
+		}
+		if info.Type == "worksheet" {
+			f.addSheetNameSpace(sheet, SourceRelationship)
+		}
+		drawingRels := path.Join(path.Dir(drawingVML), "_rels", path.Base(drawingVML)+".rels")
+		mediaStr := ".." + strings.TrimPrefix(f.addMedia(opts.File, ext), "xl")
+		rID := f.addRels(drawingRels, SourceRelationshipImage, mediaStr, "")
+		f.setContentTypePartVMLExtensions()
+		f.setContentTypePartImageExtensions()
+		return f.addHeaderFooterShape(drawingVML, &vmlHeaderFooterShape{
+			ID:    shapeID,
+			Type:  "#_x0000_t75",
+			Style: fmt.Sprintf("position:absolute;margin-left:0;margin-top:0;width:%spt;height:%spt;z-index:1", pixelsToPoints(width), pixelsToPoints(height)),
+			ImageData: &vImageData{
+				RelID: "rId" + strconv.Itoa(rID),
+				Title: opts.Title,
+			},
+			Lock: &oLock{Ext: "edit", Rotation: "t"},
+		})
+	})
+}
+
+// headerFooterShapeID provides a function to get the VML shape ID of the
+// picture in the header or footer by given picture settings, such as "LH",
+// "CF" or "RHFIRST".
+func headerFooterShapeID(opts *FormatHeaderFooterImage) (string, error) {
+	position, ok := map[string]string{"left": "L", "center": "C", "right": "R"}[opts.Position]
+	if !ok {
+		return "", ErrParameterInvalid
+	}
+	page, ok := map[string]string{"": "", "odd": "", "even": "EVEN", "first": "FIRST"}[opts.Page]
+	if !ok {
+		return "", ErrParameterInvalid
+	}
+	section := "H"
+	if opts.IsFooter {
+		section = "F"
+	}
+	return position + section + page, nil
+}
+
+// getRelsTargetByID provides a function to get the target of the
+// relationship by given relationships part path and relationship ID.
+func (f *File) getRelsTargetByID(relsPath, rID string) string {
+	rels := f.relsReader(relsPath)
+	if rels == nil {
+		return ""
+	}
+	rels.Lock()
+	defer rels.Unlock()
+	for _, rel := range rels.Relationships {
+		if rel.ID == rID {
+			return rel.Target
+		}
+	}
+	return ""
+}
+
+// addHeaderFooterShape provides a function to add or replace the picture
+// shape in the header and footer VML drawing part by given part path and
+// shape settings.
+func (f *File) addHeaderFooterShape(drawingVML string, shape *vmlHeaderFooterShape) error {
+	decode := new(decodeVmlHeaderFooter)
+	if err := f.xmlNewDecoder(bytes.NewReader(f.readXML(drawingVML))).
+		Decode(decode); err != nil && err != io.EOF {
+		return err
+	}
+	idmap := f.getVMLIDMap(drawingVML)
+	vml := vmlHeaderFooter{
+		XMLNSv:      "urn:schemas-microsoft-com:vml",
+		XMLNSo:      "urn:schemas-microsoft-com:office:office",
+		XMLNSx:      "urn:schemas-microsoft-com:office:excel",
+		Shapelayout: &xlsxShapelayout{Ext: "edit", IDmap: &xlsxIDmap{Ext: "edit", Data: idmap}},
+		Shapetype: &vmlPictureShapetype{
+			ID:             "_x0000_t75",
+			Coordsize:      "21600,21600",
+			Spt:            75,
+			Preferrelative: "t",
+			Path:           "m@4@5l@4@11@9@11@9@5xe",
+			Filled:         "f",
+			Stroked:        "f",
+			Val:            templateVMLPictureShapetype,
+		},
+	}
+	for _, s := range decode.Shape {
+		if s.ImageData == nil {
+			continue
+		}
+		if s.ID == shape.ID {
+			f.deleteRels(path.Join(path.Dir(drawingVML), "_rels", path.Base(drawingVML)+".rels"), s.ImageData.RelID)
+			continue
+		}
+		vml.Shape = append(vml.Shape, &vmlHeaderFooterShape{
+			ID:        s.ID,
+			Type:      "#_x0000_t75",
+			Style:     s.Style,
+			ImageData: &vImageData{RelID: s.ImageData.RelID, Title: s.ImageData.Title},
+			Lock:      &oLock{Ext: "edit", Rotation: "t"},
+		})
+	}
+	vml.Shape = append(vml.Shape, shape)
+	for i, s := range vml.Shape {
+		s.Spid = fmt.Sprintf("_x0000_s%d", idmap*1024+i+1)
+	}
+	output, err := xml.Marshal(vml)
+	f.Pkg.Store(drawingVML, output)
+	return err
+}
+
+// deleteRels provides a function to delete the relationship by given
+// relationships part path and relationship ID.
+func (f *File) deleteRels(relsPath, rID string) {
+	rels := f.relsReader(relsPath)
+	if rels == nil {
+		return
+	}
+	rels.Lock()
+	defer rels.Unlock()
+	for k, v := range rels.Relationships {
+		if v.ID == rID {
+			rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
+			return
+		}
+	}
+}
+
+// getSheetPictureRIDs provides a function to get the relationship ID of the
+// header and footer VML drawing part and the relationship ID of the
+// background picture of the worksheet, chart sheet or dialog sheet by given
+// sheet information.
+func (f *File) getSheetPictureRIDs(info SheetInfo) (legacyDrawingHF, picture string, err error) {
+	var (
+		hf  *xlsxLegacyDrawingHF
+		pic *xlsxPicture
+	)
+	switch info.Type {
+	case "chartsheet":
+		cs := new(xlsxChartsheet)
+		if err = f.sheetPartReader(info.Path, cs); err != nil {
+			return
+		}
+		hf, pic = cs.LegacyDrawingHF, cs.Picture
+	case "dialogsheet":
+		ds := new(xlsxDialogsheet)
+		if err = f.sheetPartReader(info.Path, ds); err != nil {
+			return
+		}
+		hf = ds.LegacyDrawingHF
+	default:
+		var ws *xlsxWorksheet
+		if ws, err = f.workSheetReader(info.Name); err != nil {
+			return
+		}
+		hf, pic = ws.LegacyDrawingHF, ws.Picture
+	}
+	if hf != nil {
+		legacyDrawingHF = hf.RID
+	}
+	if pic != nil {
+		picture = pic.RID
+	}
+	return
+}
+
+// GetHeaderFooterImages provides a function to get all pictures in the
+// headers and footers of the worksheet, chart sheet or dialog sheet by given
+// sheet name. The position, page and section of each picture will be
+// returned with the title, extension name, raw content and the size in
+// pixels of the picture. For example, get the pictures in the headers and
+// footers of Sheet1:
+//
+//	images, err := f.GetHeaderFooterImages("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, img := range images {
+//	    fmt.Println(img.Position, img.Page, img.IsFooter, img.Title, len(img.File))
+//	}
+func (f *File) GetHeaderFooterImages(sheet string) ([]FormatHeaderFooterImage, error) {
+	var images []FormatHeaderFooterImage
+	info, err := f.getSheetInfo(sheet)
+	if err != nil {
+		return images, err
+	}
+	rID, _, err := f.getSheetPictureRIDs(info)
+	if err != nil || rID == "" {
+		return images, err
+	}
+	sheetRels := getPartRelsPath(info.Path)
+	target := f.getRelsTargetByID(sheetRels, rID)
+	if target == "" {
+		return images, err
+	}
+	drawingVML := getRelsTargetPath(sheetRels, target)
+	decode := new(decodeVmlHeaderFooter)
+	if err = f.xmlNewDecoder(bytes.NewReader(f.readXML(drawingVML))).
+		Decode(decode); err != nil && err != io.EOF {
+		return images, err
+	}
+	drawingRels := getPartRelsPath(drawingVML)
+	for _, shape := range decode.Shape {
+		if shape.ImageData == nil {
+			continue
+		}
+		img, ok := parseHeaderFooterShapeID(shape.ID)
+		if !ok {
+			continue
+		}
+		target := f.getRelsTargetByID(drawingRels, shape.ImageData.RelID)
+		if target == "" {
+			continue
+		}
+		img.Title, img.Extension = shape.ImageData.Title, path.Ext(target)
+		if file, ok := f.Pkg.Load(getRelsTargetPath(drawingRels, target)); ok {
+			img.File = file.([]byte)
+		}
+		img.Width, img.Height = parseHeaderFooterShapeSize(shape.Style)
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// parseHeaderFooterShapeID provides a function to parse the VML shape ID of
+// the picture in the header or footer, such as "LH", "CF" or "RHFIRST", into
+// the position, page and section of the picture.
+func parseHeaderFooterShapeID(ID string) (FormatHeaderFooterImage, bool) {
+	var img FormatHeaderFooterImage
+	if len(ID) < 2 {
+		return img, false
+	}
+	position, ok := map[byte]string{'L': "left", 'C': "center", 'R': "right"}[ID[0]]
+	if !ok || (ID[1] != 'H' && ID[1] != 'F') {
+		return img, false
+	}
+	page, ok := map[string]string{"": "odd", "EVEN": "even", "FIRST": "first"}[ID[2:]]
+	if !ok {
+		return img, false
+	}
+	img.Position, img.Page, img.IsFooter = position, page, ID[1] == 'F'
+	return img, true
+}
+
+// parseHeaderFooterShapeSize provides a function to parse the width and
+// height in pixels of the picture in the header or footer by given style of
+// the VML shape, the size in the style is in points.
+func parseHeaderFooterShapeSize(style string) (width, height int) {
+	for _, attr := range strings.Split(style, ";") {
+		kv := strings.SplitN(strings.TrimSpace(attr), ":", 2)
+		if len(kv) != 2 || !strings.HasSuffix(kv[1], "pt") {
+			continue
+		}
+		val, err := strconv.ParseFloat(strings.TrimSuffix(kv[1], "pt"), 64)
+		if err != nil {
+			continue
+		}
+		switch kv[0] {
+		case "width":
+			width = int(math.Round(val / 0.75))
+		case "height":
+			height = int(math.Round(val / 0.75))
+		}
+	}
+	return
+}
+
+// GetSheetBackground provides a function to get the background picture of
+// the worksheet or chart sheet by given sheet name, the file name and raw
+// content of the picture will be returned, the empty value will be returned
+// if the sheet has no background picture. For example:
+//
+//	name, file, err := f.GetSheetBackground("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := ioutil.WriteFile(name, file, 0644); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) GetSheetBackground(sheet string) (string, []byte, error) {
+	info, err := f.getSheetInfo(sheet)
+	if err != nil {
+		return "", nil, err
+	}
+	_, rID, err := f.getSheetPictureRIDs(info)
+	if err != nil || rID == "" {
+		return "", nil, err
+	}
+	sheetRels := getPartRelsPath(info.Path)
+	target := f.getRelsTargetByID(sheetRels, rID)
+	if target == "" {
+		return "", nil, err
+	}
+	file, ok := f.Pkg.Load(getRelsTargetPath(sheetRels, target))
+	if !ok {
+		return "", nil, err
+	}
+	return path.Base(target), file.([]byte), err
+}
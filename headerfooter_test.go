@@ -0,0 +1,224 @@
+package excelize
+
+import (
+	_ "image/png"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddHeaderFooterImage(t *testing.T) {
+	file, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	f := NewFile()
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$B$1:$D$1"}]}`))
+	// Prepare a dialog sheet in the workbook.
+	f.Pkg.Store("xl/dialogsheets/sheet1.xml", []byte(`<dialogsheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"/></sheetViews><pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/><oleObjects><oleObject progId="Dialog" shapeId="1025"/></oleObjects></dialogsheet>`))
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipDialogsheet, "dialogsheets/sheet1.xml", "")
+	wb := f.workbookReader()
+	wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: "Dialog1", SheetID: 10, ID: "rId" + strconv.Itoa(rID)})
+	f.sheetMap["Dialog1"] = "xl/dialogsheets/sheet1.xml"
+
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "top", File: file}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "left", Page: "last", File: file}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "left", Extension: ".svg", File: file}), ErrImgExt.Error())
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "left", Extension: ".png", File: []byte("png")}), "image: unknown format")
+	assert.EqualError(t, f.AddHeaderFooterImage("SheetN", &FormatHeaderFooterImage{Position: "left", Extension: ".png", File: file}), "sheet SheetN is not exist")
+
+	for _, sheet := range []string{"Sheet1", "Chart1", "Dialog1"} {
+		assert.NoError(t, f.SetHeaderFooter(sheet, &FormatHeaderFooter{DifferentFirst: true, OddHeader: "&L&G&C&A", OddFooter: "&R&G", FirstHeader: "&C&G"}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "left", Title: "Logo", Extension: ".png", File: file}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "right", IsFooter: true, Extension: ".png", File: file, Width: 40, Height: 20}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "center", Page: "first", Extension: ".png", File: file}))
+		// Test replace the picture in the same section.
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "left", Title: "Replaced", Extension: ".png", File: file}))
+	}
+	assert.Equal(t, 1, f.countMedia())
+	assert.Equal(t, 3, f.countParts("xl/drawings/vmlDrawingHF"))
+	content, ok := f.Pkg.Load("xl/drawings/vmlDrawingHF1.vml")
+	assert.True(t, ok)
+	vml := string(content.([]byte))
+	assert.Equal(t, 3, strings.Count(vml, "<v:shape "))
+	assert.Contains(t, vml, `<v:shape id="RF" o:spid="_x0000_s1025" type="#_x0000_t75" style="position:absolute;margin-left:0;margin-top:0;width:30pt;height:15pt;z-index:1"><v:imagedata o:relid="rId2" o:title=""></v:imagedata>`)
+	assert.Contains(t, vml, `<v:shape id="LH" o:spid="_x0000_s1027" type="#_x0000_t75" style="position:absolute;margin-left:0;margin-top:0;width:150pt;height:96pt;z-index:1"><v:imagedata o:relid="rId4" o:title="Replaced"></v:imagedata>`)
+	assert.Contains(t, vml, `<v:shape id="CHFIRST"`)
+	// Test the relationship of the replaced picture has been removed.
+	assert.Len(t, f.relsReader("xl/drawings/_rels/vmlDrawingHF1.vml.rels").Relationships, 3)
+	filename := filepath.Join("test", "TestAddHeaderFooterImage.xlsx")
+	assert.NoError(t, f.SaveAs(filename))
+
+	f, err = OpenFile(filename)
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "rId1", ws.LegacyDrawingHF.RID)
+	assert.Equal(t, "&L&G&C&A", ws.HeaderFooter.OddHeader)
+	for partPath, expected := range map[string][]string{
+		"xl/chartsheets/sheet2.xml":  {`<headerFooter differentFirst="true"><oddHeader>&amp;L&amp;G&amp;C&amp;A</oddHeader>`, `<legacyDrawingHF r:id="rId2"></legacyDrawingHF>`},
+		"xl/dialogsheets/sheet1.xml": {`<dialogsheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`, `<legacyDrawingHF r:id="rId1"></legacyDrawingHF>`, `<oleObjects><oleObject progId="Dialog" shapeId="1025"/></oleObjects>`},
+	} {
+		content, ok := f.Pkg.Load(partPath)
+		assert.True(t, ok, partPath)
+		for _, str := range expected {
+			assert.Contains(t, string(content.([]byte)), str, partPath)
+		}
+	}
+	// Test add picture into the existing header and footer drawing part.
+	assert.NoError(t, f.AddHeaderFooterImage("Chart1", &FormatHeaderFooterImage{Position: "center", Page: "even", Extension: ".png", File: file}))
+	content, _ = f.Pkg.Load("xl/drawings/vmlDrawingHF2.vml")
+	assert.Equal(t, 4, strings.Count(string(content.([]byte)), "<v:shape "))
+
+	assert.EqualError(t, f.SetHeaderFooter("Chart1", &FormatHeaderFooter{OddHeader: strings.Repeat("c", 256)}), "field OddHeader must be less than 255 characters")
+	// Test remove the header and footer of the chart sheet.
+	assert.NoError(t, f.SetHeaderFooter("Chart1", nil))
+	content, _ = f.Pkg.Load("xl/chartsheets/sheet2.xml")
+	assert.NotContains(t, string(content.([]byte)), "<headerFooter")
+
+	// Test add picture with unsupported charset drawing and sheet parts.
+	f.Pkg.Store("xl/drawings/vmlDrawingHF2.vml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddHeaderFooterImage("Chart1", &FormatHeaderFooterImage{Position: "center", Extension: ".png", File: file}), "XML syntax error on line 1: invalid UTF-8")
+	for _, sheet := range []string{"Chart1", "Dialog1"} {
+		info, err := f.getSheetInfo(sheet)
+		assert.NoError(t, err)
+		f.Pkg.Store(info.Path, MacintoshCyrillicCharset)
+		assert.EqualError(t, f.SetHeaderFooter(sheet, nil), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	}
+}
+
+func TestAddHeaderFooterImageWithComments(t *testing.T) {
+	file, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &FormatHeaderFooter{OddHeader: "&L&G"}))
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "left", Extension: ".png", File: file}))
+	assert.NoError(t, f.AddComment("Sheet2", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	filename := filepath.Join("test", "TestAddHeaderFooterImageWithComments.xlsx")
+	assert.NoError(t, f.SaveAs(filename))
+
+	f, err = OpenFile(filename)
+	assert.NoError(t, err)
+	for partPath, expected := range map[string][]string{
+		"xl/drawings/vmlDrawing1.vml":   {`<o:idmap v:ext="edit" data="1"></o:idmap>`, `id="_x0000_s1025"`},
+		"xl/drawings/vmlDrawingHF1.vml": {`<o:idmap v:ext="edit" data="2"></o:idmap>`, `o:spid="_x0000_s2049"`},
+		"xl/drawings/vmlDrawing2.vml":   {`<o:idmap v:ext="edit" data="3"></o:idmap>`, `id="_x0000_s3073"`},
+	} {
+		content, ok := f.Pkg.Load(partPath)
+		assert.True(t, ok, partPath)
+		for _, str := range expected {
+			assert.Contains(t, string(content.([]byte)), str, partPath)
+		}
+	}
+	// Test add the comment and picture into the existing drawing parts.
+	assert.NoError(t, f.AddComment("Sheet1", "B1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &FormatHeaderFooterImage{Position: "right", Extension: ".png", File: file}))
+	assert.Equal(t, 1, f.getVMLIDMap("xl/drawings/vmlDrawing1.vml"))
+	assert.Equal(t, 2, f.getVMLIDMap("xl/drawings/vmlDrawingHF1.vml"))
+	assert.Equal(t, 4, f.getVMLIDMap("xl/drawings/vmlDrawing3.vml"))
+	content, ok := f.Pkg.Load("xl/drawings/vmlDrawingHF1.vml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `o:spid="_x0000_s2050"`)
+}
+
+func TestGetHeaderFooterImages(t *testing.T) {
+	file, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	f := NewFile()
+	images, err := f.GetHeaderFooterImages("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$B$1:$D$1"}]}`))
+	for _, sheet := range []string{"Sheet1", "Chart1"} {
+		assert.NoError(t, f.SetHeaderFooter(sheet, &FormatHeaderFooter{DifferentFirst: true, OddHeader: "&L&G", OddFooter: "&R&G", FirstHeader: "&C&G"}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "left", Title: "Logo", Extension: ".png", File: file}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "right", IsFooter: true, Extension: ".png", File: file, Width: 40, Height: 20}))
+		assert.NoError(t, f.AddHeaderFooterImage(sheet, &FormatHeaderFooterImage{Position: "center", Page: "first", Extension: ".png", File: file}))
+	}
+	filename := filepath.Join("test", "TestGetHeaderFooterImages.xlsx")
+	assert.NoError(t, f.SaveAs(filename))
+
+	f, err = OpenFile(filename)
+	assert.NoError(t, err)
+	for _, sheet := range []string{"Sheet1", "Chart1"} {
+		images, err = f.GetHeaderFooterImages(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, images, 3)
+		assert.Equal(t, FormatHeaderFooterImage{Position: "left", Page: "odd", Title: "Logo", Extension: ".png", File: file, Width: 200, Height: 128}, images[0])
+		assert.Equal(t, FormatHeaderFooterImage{Position: "right", Page: "odd", IsFooter: true, Extension: ".png", File: file, Width: 40, Height: 20}, images[1])
+		assert.Equal(t, FormatHeaderFooterImage{Position: "center", Page: "first", Extension: ".png", File: file, Width: 200, Height: 128}, images[2])
+	}
+	// Test get pictures with invalid shapes and relationships.
+	f.Pkg.Store("xl/drawings/vmlDrawingHF1.vml", []byte(`<xml xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><v:shape id="X"><v:imagedata o:relid="rId1"/></v:shape><v:shape id="LHLAST"><v:imagedata o:relid="rId1"/></v:shape><v:shape id="LX"><v:imagedata o:relid="rId1"/></v:shape><v:shape id="LH"><v:imagedata o:relid="rId100"/></v:shape><v:shape id="CH"/><v:shape id="RH" style="width:xpt;height:15pt"><v:imagedata o:relid="rId1"/></v:shape></xml>`))
+	images, err = f.GetHeaderFooterImages("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, []int{0, 20}, []int{images[0].Width, images[0].Height})
+	// Test get pictures with unsupported charset drawing part.
+	f.Pkg.Store("xl/drawings/vmlDrawingHF1.vml", MacintoshCyrillicCharset)
+	_, err = f.GetHeaderFooterImages("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get pictures with not exists relationship.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.LegacyDrawingHF.RID = "rId100"
+	images, err = f.GetHeaderFooterImages("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+	// Test get pictures on not exists sheet.
+	_, err = f.GetHeaderFooterImages("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get pictures with unsupported charset sheet part.
+	f.Pkg.Store("xl/chartsheets/sheet2.xml", MacintoshCyrillicCharset)
+	_, err = f.GetHeaderFooterImages("Chart1")
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetSheetBackground(t *testing.T) {
+	f := NewFile()
+	name, file, err := f.GetSheetBackground("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+	assert.Empty(t, file)
+	assert.NoError(t, f.SetSheetBackground("Sheet1", filepath.Join("test", "images", "background.jpg")))
+	filename := filepath.Join("test", "TestGetSheetBackground.xlsx")
+	assert.NoError(t, f.SaveAs(filename))
+
+	f, err = OpenFile(filename)
+	assert.NoError(t, err)
+	expected, err := ioutil.ReadFile(filepath.Join("test", "images", "background.jpg"))
+	assert.NoError(t, err)
+	name, file, err = f.GetSheetBackground("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.jpeg", name)
+	assert.Equal(t, expected, file)
+	// Test get background picture with not exists media part.
+	f.Pkg.Delete("xl/media/image1.jpeg")
+	name, file, err = f.GetSheetBackground("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+	assert.Empty(t, file)
+	// Test get background picture with not exists relationship.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.Picture.RID = "rId100"
+	name, _, err = f.GetSheetBackground("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+	// Test get background picture on not exists sheet.
+	_, _, err = f.GetSheetBackground("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get background picture with unsupported charset dialog sheet part.
+	f.Pkg.Store("xl/dialogsheets/sheet1.xml", MacintoshCyrillicCharset)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipDialogsheet, "dialogsheets/sheet1.xml", "")
+	wb := f.workbookReader()
+	wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: "Dialog1", SheetID: 10, ID: "rId" + strconv.Itoa(rID)})
+	f.sheetMap["Dialog1"] = "xl/dialogsheets/sheet1.xml"
+	_, _, err = f.GetSheetBackground("Dialog1")
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
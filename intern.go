@@ -0,0 +1,135 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "sync"
+
+// maxInternedStrings defined the maximum number of distinct strings in the
+// string pool of the workbook, the strings will not be pooled after the
+// limit is reached to avoid the pool growing with the unique cell references
+// of the huge worksheets.
+const maxInternedStrings = 1 << 16
+
+// stringPool directly maps the pool of the repeated strings in the workbook,
+// such as the part paths, relationship types, content types and the cell
+// references, which shared by the parts and worksheets in the workbook.
+type stringPool struct {
+	sync.Mutex
+	strings    map[string]string
+	lookups    int
+	hits       int
+	savedBytes int64
+}
+
+// Stats directly maps the statistics of the workbook in memory. The strings
+// interned by the workbook and the saved bytes of the duplicate strings
+// could be used to measure the memory overhead of the workbook.
+//
+// InternedStrings specifies the number of distinct strings in the string
+// pool of the workbook.
+//
+// InternLookups specifies the number of strings looked up in the string
+// pool.
+//
+// InternHits specifies the number of strings which share the memory with
+// the strings in the string pool.
+//
+// InternSavedBytes specifies the total bytes of the duplicate strings which
+// share the memory with the strings in the string pool.
+//
+// LoadedWorksheets specifies the number of the worksheets which have been
+// deserialized in memory.
+type Stats struct {
+	InternedStrings  int
+	InternLookups    int
+	InternHits       int
+	InternSavedBytes int64
+	LoadedWorksheets int
+}
+
+// intern provides a function to get the string in the string pool of the
+// workbook which equals to the given string, the given string will be added
+// to the pool if it doesn't exist.
+func (f *File) intern(s string) string {
+	if s == "" {
+		return s
+	}
+	f.stringPool.Lock()
+	defer f.stringPool.Unlock()
+	f.stringPool.lookups++
+	if v, ok := f.stringPool.strings[s]; ok {
+		f.stringPool.hits++
+		f.stringPool.savedBytes += int64(len(s))
+		return v
+	}
+	if f.stringPool.strings == nil {
+		f.stringPool.strings = make(map[string]string)
+	}
+	if len(f.stringPool.strings) < maxInternedStrings {
+		f.stringPool.strings[s] = s
+	}
+	return s
+}
+
+// internWorksheet provides a function to intern the cell references, cell
+// types and the row spans of the worksheet, which are repeated in the
+// worksheets with the same layout.
+func (f *File) internWorksheet(ws *xlsxWorksheet) {
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		row.Spans = f.intern(row.Spans)
+		for colIdx := range row.C {
+			c := &row.C[colIdx]
+			c.R, c.T = f.intern(c.R), f.intern(c.T)
+		}
+	}
+}
+
+// internRels provides a function to intern the relationship types and
+// target modes of the relationships.
+func (f *File) internRels(rels *xlsxRelationships) {
+	for idx := range rels.Relationships {
+		rel := &rels.Relationships[idx]
+		rel.Type, rel.TargetMode = f.intern(rel.Type), f.intern(rel.TargetMode)
+	}
+}
+
+// GetStats provides a function to get the statistics of the workbook in
+// memory, which includes the statistics of the string interning. For
+// example, get the saved bytes by the string interning after reading all
+// worksheets in the workbook:
+//
+//    for _, name := range f.GetSheetList() {
+//        if _, err := f.GetRows(name); err != nil {
+//            fmt.Println(err)
+//        }
+//    }
+//    stats := f.GetStats()
+//    fmt.Println(stats.InternHits, stats.InternSavedBytes)
+//
+func (f *File) GetStats() Stats {
+	f.stringPool.Lock()
+	stats := Stats{
+		InternedStrings:  len(f.stringPool.strings),
+		InternLookups:    f.stringPool.lookups,
+		InternHits:       f.stringPool.hits,
+		InternSavedBytes: f.stringPool.savedBytes,
+	}
+	f.stringPool.Unlock()
+	f.Sheet.Range(func(_, ws interface{}) bool {
+		if ws != nil {
+			stats.LoadedWorksheets++
+		}
+		return true
+	})
+	return stats
+}
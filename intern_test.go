@@ -0,0 +1,65 @@
+package excelize
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntern(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, "", f.intern(""))
+	s1, s2 := fmt.Sprintf("A%d", 1), fmt.Sprintf("A%d", 1)
+	assert.Equal(t, s1, f.intern(s1))
+	assert.Equal(t, s1, f.intern(s2))
+	stats := f.GetStats()
+	assert.Equal(t, 1, stats.InternHits)
+	assert.Equal(t, int64(2), stats.InternSavedBytes)
+
+	// Test the strings will not be pooled after the limit is reached.
+	f = NewFile()
+	for i := 0; i <= maxInternedStrings; i++ {
+		f.intern(fmt.Sprintf("%d", i))
+	}
+	assert.Equal(t, maxInternedStrings, f.GetStats().InternedStrings)
+	f.intern(fmt.Sprintf("%d", maxInternedStrings))
+	assert.Equal(t, 0, f.GetStats().InternHits)
+}
+
+func TestGetStats(t *testing.T) {
+	f := NewFile()
+	for i := 2; i <= 10; i++ {
+		f.NewSheet(fmt.Sprintf("Sheet%d", i))
+	}
+	for _, name := range f.GetSheetList() {
+		for col := 1; col <= 10; col++ {
+			cell, err := CoordinatesToCellName(col, 1)
+			assert.NoError(t, err)
+			assert.NoError(t, f.SetCellValue(name, cell, col))
+		}
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetStats.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestGetStats.xlsx"))
+	assert.NoError(t, err)
+	before := f.GetStats()
+	assert.Equal(t, 0, before.LoadedWorksheets)
+	for _, name := range f.GetSheetList() {
+		_, err := f.workSheetReader(name)
+		assert.NoError(t, err)
+	}
+	after := f.GetStats()
+	assert.Equal(t, 10, after.LoadedWorksheets)
+	// The cell references and row spans of the worksheets with the same
+	// layout share the memory.
+	assert.Equal(t, before.InternHits+9*11, after.InternHits)
+	assert.Equal(t, before.InternedStrings+11, after.InternedStrings)
+	assert.True(t, after.InternSavedBytes > before.InternSavedBytes)
+	ws, err := f.workSheetReader("Sheet10")
+	assert.NoError(t, err)
+	// Test the interned strings keep the values.
+	assert.Equal(t, "xl/worksheets/sheet10.xml", f.sheetMap["Sheet10"])
+	assert.Equal(t, "J1", ws.SheetData.Row[0].C[9].R)
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -194,7 +195,7 @@ func CellNameToCoordinates(cell string) (int, int, error) {
 		return -1, -1, fmt.Errorf(msg, cell, err)
 	}
 	if row > TotalRows {
-		return -1, -1, fmt.Errorf("row number exceeds maximum limit")
+		return -1, -1, ErrMaxRows
 	}
 	col, err := ColumnNameToNumber(colname)
 	return col, row, err
@@ -222,6 +223,239 @@ func CoordinatesToCellName(col, row int, abs ...bool) (string, error) {
 	return sign + colname + sign + strconv.Itoa(row), err
 }
 
+var (
+	// a1CellRefRegexp defined the regular expression to match the cell
+	// reference in the A1 reference style at the beginning of the text.
+	a1CellRefRegexp = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)([0-9]+)`)
+	// a1ColsRefRegexp defined the regular expression to match the whole
+	// columns reference in the A1 reference style, such as A:C.
+	a1ColsRefRegexp = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3}):(\$?)([A-Za-z]{1,3})`)
+	// a1RowsRefRegexp defined the regular expression to match the whole rows
+	// reference in the A1 reference style, such as 1:3.
+	a1RowsRefRegexp = regexp.MustCompile(`^(\$?)([0-9]+):(\$?)([0-9]+)`)
+	// r1c1RefRegexp defined the regular expression to match the cell, the
+	// whole row or the whole column reference in the R1C1 reference style,
+	// such as R1C1, R[-1]C, R2 and C[1].
+	r1c1RefRegexp = regexp.MustCompile(`^(?:([Rr])(\[-?[0-9]+\]|[0-9]+)?)?(?:([Cc])(\[-?[0-9]+\]|[0-9]+)?)?`)
+)
+
+// isFormulaRefEnd returns if the reference in the formula text ends at the
+// given position, the reference shouldn't be followed by the characters of
+// the names, the function call or the structured reference.
+func isFormulaRefEnd(text string, pos int) bool {
+	return pos >= len(text) || !isStructRefNameChar(text[pos]) && text[pos] != '$' && text[pos] != '(' && text[pos] != '['
+}
+
+// convertFormulaRefs provides a function to convert the references in the
+// formula by given function, which returns the length of the reference at
+// the beginning of the given text and the replacement of the reference, or
+// zero length if the text isn't started with a reference. The string
+// literals, the quoted worksheet names and the texts in the brackets of the
+// structured references and the external references will be kept.
+func convertFormulaRefs(formula string, fn func(text string) (int, string)) string {
+	var b strings.Builder
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		if c == '"' || c == '\'' {
+			end := i + 1
+			for ; end < len(formula); end++ {
+				if formula[end] == c {
+					if end+1 < len(formula) && formula[end+1] == c {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			if end >= len(formula) {
+				end = len(formula) - 1
+			}
+			b.WriteString(formula[i : end+1])
+			i = end + 1
+			continue
+		}
+		if c == '[' {
+			depth, end := 0, i
+			for ; end < len(formula); end++ {
+				if formula[end] == '\'' {
+					end++
+					continue
+				}
+				if formula[end] == '[' {
+					depth++
+				}
+				if formula[end] == ']' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			if end >= len(formula) {
+				end = len(formula) - 1
+			}
+			b.WriteString(formula[i : end+1])
+			i = end + 1
+			continue
+		}
+		if !isStructRefNameChar(c) && c != '$' {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		if n, replacement := fn(formula[i:]); n > 0 {
+			b.WriteString(replacement)
+			i += n
+			continue
+		}
+		end := i + 1
+		for end < len(formula) && (isStructRefNameChar(formula[end]) || formula[end] == '$') {
+			end++
+		}
+		b.WriteString(formula[i:end])
+		i = end
+	}
+	return b.String()
+}
+
+// r1c1Part returns the row or column part of the reference in the R1C1
+// reference style by given prefix, the absolute flag, the number and the
+// number of the cell which the formula belongs to.
+func r1c1Part(prefix string, abs bool, num, base int) string {
+	if abs {
+		return prefix + strconv.Itoa(num)
+	}
+	if num == base {
+		return prefix
+	}
+	return prefix + "[" + strconv.Itoa(num-base) + "]"
+}
+
+// A1ToR1C1 provides a function to convert the references in the formula
+// from the A1 reference style to the R1C1 reference style by given formula
+// and the cell which the formula belongs to. The absolute references will
+// be converted to the absolute row and column numbers, and the relative
+// references will be converted to the offsets from the cell. For example,
+// convert the formula of the cell C3:
+//
+//    formula, err := excelize.A1ToR1C1("SUM(A1:B2)*$A$1+Sheet2!C:C", "C3")
+//    // returns "SUM(R[-2]C[-2]:R[-1]C[-1])*R1C1+Sheet2!C", nil
+//
+func A1ToR1C1(formula, cell string) (string, error) {
+	baseCol, baseRow, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return formula, err
+	}
+	return convertFormulaRefs(formula, func(text string) (int, string) {
+		if m := a1CellRefRegexp.FindStringSubmatch(text); m != nil && isFormulaRefEnd(text, len(m[0])) {
+			col, _ := ColumnNameToNumber(m[2])
+			if row, err := strconv.Atoi(m[4]); err == nil && col > 0 && col <= TotalColumns && row > 0 && row <= TotalRows {
+				return len(m[0]), r1c1Part("R", m[3] == "$", row, baseRow) + r1c1Part("C", m[1] == "$", col, baseCol)
+			}
+		}
+		if m := a1ColsRefRegexp.FindStringSubmatch(text); m != nil && isFormulaRefEnd(text, len(m[0])) {
+			from, _ := ColumnNameToNumber(m[2])
+			to, _ := ColumnNameToNumber(m[4])
+			if from > 0 && from <= TotalColumns && to > 0 && to <= TotalColumns {
+				first, last := r1c1Part("C", m[1] == "$", from, baseCol), r1c1Part("C", m[3] == "$", to, baseCol)
+				if first == last {
+					return len(m[0]), first
+				}
+				return len(m[0]), first + ":" + last
+			}
+		}
+		if m := a1RowsRefRegexp.FindStringSubmatch(text); m != nil && isFormulaRefEnd(text, len(m[0])) {
+			from, _ := strconv.Atoi(m[2])
+			to, _ := strconv.Atoi(m[4])
+			if from > 0 && from <= TotalRows && to > 0 && to <= TotalRows {
+				first, last := r1c1Part("R", m[1] == "$", from, baseRow), r1c1Part("R", m[3] == "$", to, baseRow)
+				if first == last {
+					return len(m[0]), first
+				}
+				return len(m[0]), first + ":" + last
+			}
+		}
+		return 0, ""
+	}), err
+}
+
+// parseR1C1Part provides a function to parse the row or column part of the
+// reference in the R1C1 reference style, the relative part will be wrapped
+// around within the given maximum number as the spreadsheet applications do.
+// It returns the number, the absolute flag and if the part is valid.
+func parseR1C1Part(part string, base, max int) (int, bool, bool) {
+	if part == "" {
+		return base, false, true
+	}
+	if strings.HasPrefix(part, "[") {
+		offset, err := strconv.Atoi(strings.Trim(part, "[]"))
+		if err != nil {
+			return 0, false, false
+		}
+		return ((base-1+offset)%max+max)%max + 1, false, true
+	}
+	num, err := strconv.Atoi(part)
+	return num, true, err == nil && num > 0 && num <= max
+}
+
+// R1C1ToA1 provides a function to convert the references in the formula
+// from the R1C1 reference style to the A1 reference style by given formula
+// and the cell which the formula belongs to. The relative references beyond
+// the edges of the worksheet will be wrapped around as the spreadsheet
+// applications do. For example, convert the formula of the cell C3:
+//
+//    formula, err := excelize.R1C1ToA1("SUM(R[-2]C[-2]:R[-1]C[-1])*R1C1+Sheet2!C", "C3")
+//    // returns "SUM(A1:B2)*$A$1+Sheet2!C:C", nil
+//
+func R1C1ToA1(formula, cell string) (string, error) {
+	baseCol, baseRow, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return formula, err
+	}
+	// parse returns the length, the A1 style reference and the kind of the
+	// reference at the beginning of the text, the kind is "R" for the whole
+	// row, "C" for the whole column, or "RC" for the cell.
+	parse := func(text string) (int, string, string) {
+		m := r1c1RefRegexp.FindStringSubmatch(text)
+		if m == nil || m[0] == "" || !isFormulaRefEnd(text, len(m[0])) {
+			return 0, "", ""
+		}
+		row, absRow, ok := parseR1C1Part(m[2], baseRow, TotalRows)
+		if !ok {
+			return 0, "", ""
+		}
+		col, absCol, ok := parseR1C1Part(m[4], baseCol, TotalColumns)
+		if !ok {
+			return 0, "", ""
+		}
+		rowRef, colRef := strconv.Itoa(row), ""
+		if absRow {
+			rowRef = "$" + rowRef
+		}
+		if colRef, _ = ColumnNumberToName(col); absCol {
+			colRef = "$" + colRef
+		}
+		switch {
+		case m[1] == "":
+			return len(m[0]), colRef, "C"
+		case m[3] == "":
+			return len(m[0]), rowRef, "R"
+		}
+		return len(m[0]), colRef + rowRef, "RC"
+	}
+	return convertFormulaRefs(formula, func(text string) (int, string) {
+		n, ref, kind := parse(text)
+		if n == 0 || kind == "RC" {
+			return n, ref
+		}
+		if strings.HasPrefix(text[n:], ":") {
+			if m, last, lastKind := parse(text[n+1:]); m > 0 && lastKind == kind {
+				return n + 1 + m, ref + ":" + last
+			}
+		}
+		return n, ref + ":" + ref
+	}), err
+}
+
 // boolPtr returns a pointer to a bool with the given value.
 func boolPtr(b bool) *bool { return &b }
 
@@ -231,9 +465,21 @@ func intPtr(i int) *int { return &i }
 // float64Ptr returns a pofloat64er to a float64 with the given value.
 func float64Ptr(f float64) *float64 { return &f }
 
+// uintPtr returns a pointer to a uint with the given value.
+func uintPtr(i uint) *uint { return &i }
+
 // stringPtr returns a pointer to a string with the given value.
 func stringPtr(s string) *string { return &s }
 
+// stringValue returns the pointed string value, or the empty string if s is
+// nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // defaultTrue returns true if b is nil, or the pointed value.
 func defaultTrue(b *bool) bool {
 	if b == nil {
@@ -594,3 +840,42 @@ func (stack *Stack) Len() int {
 func (stack *Stack) Empty() bool {
 	return stack.list.Len() == 0
 }
+
+// countParts provides a function to get the count of the parts with the
+// given path prefix in the package, the largest sequence number in the part
+// names will be returned if it's greater than the count, to avoid overwrite
+// the remaining parts when some of the parts have been deleted.
+func (f *File) countParts(prefix string) int {
+	count, max := 0, 0
+	f.Pkg.Range(func(k, v interface{}) bool {
+		name := k.(string)
+		if idx := strings.Index(name, prefix); idx != -1 {
+			count++
+			name = name[idx+len(prefix):]
+			if seq, err := strconv.Atoi(strings.TrimSuffix(name, path.Ext(name))); err == nil && seq > max {
+				max = seq
+			}
+		}
+		return true
+	})
+	if max > count {
+		return max
+	}
+	return count
+}
+
+// quoteSheetName provides a function to quote the sheet name with the single
+// quotes for the references in the formulas, if the sheet name contains
+// characters other than letters, digits, underscores and periods, starts
+// with a digit or looks like a cell reference.
+func quoteSheetName(name string) string {
+	if _, _, err := CellNameToCoordinates(name); err == nil {
+		return "'" + name + "'"
+	}
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r) && r != '.') {
+			return "'" + strings.Replace(name, "'", "''", -1) + "'"
+		}
+	}
+	return name
+}
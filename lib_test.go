@@ -211,6 +211,50 @@ func TestCoordinatesToCellName_Error(t *testing.T) {
 	}
 }
 
+func TestR1C1ReferenceStyle(t *testing.T) {
+	for cell, formulas := range map[string][][2]string{
+		"C3": {
+			{"SUM(A1:B2)*$A$1+Sheet2!C:C", "SUM(R[-2]C[-2]:R[-1]C[-1])*R1C1+Sheet2!C"},
+			{"=C3+$C4+C$5", "=RC+R[1]C3+R5C"},
+			{"SUM(A:B,$2:$4,3:3)", "SUM(C[-2]:C[-1],R2:R4,R)"},
+			{"'Sheet 1'!B2&\"A1\"", "'Sheet 1'!R[-1]C[-1]&\"A1\""},
+			{"LOG10(A1)+Rate+Table1[[#This Row],[A1]]+[1]Sheet1!A1", "LOG10(R[-2]C[-2])+Rate+Table1[[#This Row],[A1]]+[1]Sheet1!R[-2]C[-2]"},
+			{"ROUND(1.5E+3,0)+#REF!", "ROUND(1.5E+3,0)+#REF!"},
+		},
+		"A1": {
+			{"XFD1048576", "R[1048575]C[16383]"},
+			{"$XFD$1048576", "R1048576C16384"},
+			{"XFE1+A0", "XFE1+A0"},
+		},
+	} {
+		for _, formula := range formulas {
+			result, err := A1ToR1C1(formula[0], cell)
+			assert.NoError(t, err)
+			assert.Equal(t, formula[1], result, formula[0])
+			result, err = R1C1ToA1(formula[1], cell)
+			assert.NoError(t, err)
+			assert.Equal(t, formula[0], result, formula[1])
+		}
+	}
+	for formula, expected := range map[string]string{
+		"R[-1]C[-1]":     "XFD1048576",
+		"rc[1]+R2C":      "B1+A$2",
+		"R:R[2]":         "1:3",
+		"C[1]+R1":        "B:B+$1:$1",
+		"R1:C1":          "$1:$1:$A:$A",
+		"R0C1+R1C16385":  "R0C1+R1C16385",
+		"RC(1)+RC[1]D+R": "RC(1)+RC[1]D+1:1",
+	} {
+		result, err := R1C1ToA1(formula, "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, formula)
+	}
+	_, err := A1ToR1C1("A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = R1C1ToA1("RC", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
 func TestBytesReplace(t *testing.T) {
 	s := []byte{0x01}
 	assert.EqualValues(t, s, bytesReplace(s, []byte{}, []byte{}, 0))
@@ -271,3 +315,17 @@ func TestBstrMarshal(t *testing.T) {
 		assert.Equal(t, expected, bstrMarshal(bstr))
 	}
 }
+
+func TestQuoteSheetName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Sheet1":    "Sheet1",
+		"Sheet_1.a": "Sheet_1.a",
+		"Sheet 1":   "'Sheet 1'",
+		"1Sheet":    "'1Sheet'",
+		"A1":        "'A1'",
+		"It's":      "'It''s'",
+		"数据":        "数据",
+	} {
+		assert.Equal(t, expected, quoteSheetName(name))
+	}
+}
@@ -22,7 +22,9 @@ import (
 //    err := f.MergeCell("Sheet1", "D3", "E9")
 //
 // If you create a merged cell that overlaps with another existing merged cell,
-// those merged cells that already exist will be removed.
+// those merged cells that already exist will be removed. The merged cell
+// can't overlap with the tables in the worksheet, the ErrTableMergeCell will
+// be returned in this case.
 //
 //                 B1(x1,y1)      D1(x2,y1)
 //               +------------------------+
@@ -51,6 +53,9 @@ func (f *File) MergeCell(sheet, hcell, vcell string) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkSheetTablesOverlap(sheet, rect1, ErrTableMergeCell, ""); err != nil {
+		return err
+	}
 	ref := hcell + ":" + vcell
 	if ws.MergeCells != nil {
 		for i := 0; i < len(ws.MergeCells.Cells); i++ {
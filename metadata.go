@@ -0,0 +1,139 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultXMLPathMetadata defined the path of the cell metadata part.
+const defaultXMLPathMetadata = "xl/metadata.xml"
+
+// metadataTypeDynamicArray defined the name of the metadata type and the
+// future metadata of the dynamic array properties.
+const metadataTypeDynamicArray = "XLDAPR"
+
+// metadataReader provides a function to get the pointer to the structure
+// after deserialization of xl/metadata.xml.
+func (f *File) metadataReader() (*xlsxMetadata, error) {
+	if f.metadata == nil {
+		content, ok := f.Pkg.Load(defaultXMLPathMetadata)
+		if !ok {
+			return nil, nil
+		}
+		metadata := new(xlsxMetadata)
+		if _, ok := f.xmlAttr[defaultXMLPathMetadata]; !ok {
+			d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte))))
+			f.xmlAttr[defaultXMLPathMetadata] = append(f.xmlAttr[defaultXMLPathMetadata], getRootElement(d)...)
+		}
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(metadata); err != nil && err != io.EOF {
+			return nil, err
+		}
+		f.metadata = metadata
+	}
+	return f.metadata, nil
+}
+
+// metadataWriter provides a function to save xl/metadata.xml after
+// serialize structure.
+func (f *File) metadataWriter() {
+	if f.metadata != nil {
+		output, _ := xml.Marshal(f.metadata)
+		f.saveFileList(defaultXMLPathMetadata, f.replaceNameSpaceBytes(defaultXMLPathMetadata, output))
+	}
+}
+
+// getDynamicArrayCellMetadata provides a function to get the 1-based index
+// of the cell metadata block of the dynamic array formulas, the metadata
+// type, the future metadata of the dynamic array properties and the cell
+// metadata block will be created if not exist.
+func (f *File) getDynamicArrayCellMetadata() (int, error) {
+	metadata, err := f.metadataReader()
+	if err != nil {
+		return 0, err
+	}
+	if metadata == nil {
+		metadata = &xlsxMetadata{XMLNSXDA: NameSpaceDynamicArray}
+		f.metadata = metadata
+	}
+	if metadata.MetadataTypes == nil {
+		metadata.MetadataTypes = new(xlsxMetadataTypes)
+	}
+	typeIdx := -1
+	for idx, metadataType := range metadata.MetadataTypes.MetadataType {
+		if metadataType.Name == metadataTypeDynamicArray {
+			typeIdx = idx
+			break
+		}
+	}
+	if typeIdx == -1 {
+		metadata.MetadataTypes.MetadataType = append(metadata.MetadataTypes.MetadataType, xlsxMetadataType{
+			Name: metadataTypeDynamicArray, MinSupportedVersion: 120000, Copy: true, PasteAll: true,
+			PasteValues: true, Merge: true, SplitFirst: true, RowColShift: true, ClearFormats: true,
+			ClearComments: true, Assign: true, Coerce: true, CellMeta: true,
+		})
+		typeIdx = len(metadata.MetadataTypes.MetadataType) - 1
+	}
+	metadata.MetadataTypes.Count = len(metadata.MetadataTypes.MetadataType)
+	bkIdx := f.getDynamicArrayFutureMetadata(metadata)
+	if metadata.CellMetadata == nil {
+		metadata.CellMetadata = new(xlsxMetadataBlocks)
+	}
+	for idx, bk := range metadata.CellMetadata.Bk {
+		if len(bk.Rc) == 1 && bk.Rc[0].T == typeIdx+1 && bk.Rc[0].V == bkIdx {
+			return idx + 1, err
+		}
+	}
+	metadata.CellMetadata.Bk = append(metadata.CellMetadata.Bk, xlsxMetadataBlock{
+		Rc: []xlsxMetadataRecord{{T: typeIdx + 1, V: bkIdx}},
+	})
+	metadata.CellMetadata.Count = len(metadata.CellMetadata.Bk)
+	f.addContentTypePart(0, "metadata")
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipSheetMetadata, "metadata.xml", "")
+	return metadata.CellMetadata.Count, err
+}
+
+// getDynamicArrayFutureMetadata provides a function to get the 0-based index
+// of the future metadata block of the dynamic array properties, which
+// indicates the formula is a dynamic array formula and the array isn't
+// collapsed into a single cell.
+func (f *File) getDynamicArrayFutureMetadata(metadata *xlsxMetadata) int {
+	idx := -1
+	for i, futureMetadata := range metadata.FutureMetadata {
+		if futureMetadata.Name == metadataTypeDynamicArray {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		metadata.FutureMetadata = append(metadata.FutureMetadata, xlsxFutureMetadata{Name: metadataTypeDynamicArray})
+		idx = len(metadata.FutureMetadata) - 1
+	}
+	futureMetadata := &metadata.FutureMetadata[idx]
+	for i, bk := range futureMetadata.Bk {
+		if bk.ExtLst != nil && strings.Contains(bk.ExtLst.Content, `fDynamic="1"`) &&
+			!strings.Contains(bk.ExtLst.Content, `fCollapsed="1"`) {
+			return i
+		}
+	}
+	futureMetadata.Bk = append(futureMetadata.Bk, xlsxFutureMetadataBlock{
+		ExtLst: &xlsxInnerXML{Content: fmt.Sprintf(`<ext uri="%s" xmlns:xda="%s"><xda:dynamicArrayProperties fDynamic="1" fCollapsed="0"/></ext>`,
+			ExtURIDynamicArrayProperties, NameSpaceDynamicArray)},
+	})
+	futureMetadata.Count = len(futureMetadata.Bk)
+	return futureMetadata.Count - 1
+}
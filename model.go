@@ -0,0 +1,385 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// cellModelTypes defined the value types of the cells in the workbook model.
+var cellModelTypes = []string{"bool", "date", "error", "number", "string"}
+
+// WorkbookModel directly maps the logical model of the workbook, which could
+// be encoded to JSON or the other formats for the language-agnostic
+// pipelines and the snapshot testing of the generated workbooks. Only the
+// worksheets, the cell values, formulas and simplified styles, the merged
+// cells, the custom column widths and row heights, and the defined names are
+// included in the model. The first style of the Styles is the default style
+// of the workbook, which is referenced by the cells without explicit style.
+// For example, the JSON structure of the model:
+//
+//    {
+//        "sheets": [
+//            {
+//                "name": "Sheet1",
+//                "cells": [
+//                    {"ref": "A1", "type": "string", "value": "Price", "style": 1},
+//                    {"ref": "A2", "type": "number", "value": "25.5"},
+//                    {"ref": "A3", "type": "number", "value": "51", "formula": "A2*2"}
+//                ],
+//                "merge_cells": ["A1:B1"],
+//                "col_widths": {"A": 20},
+//                "row_heights": {"1": 30}
+//            }
+//        ],
+//        "styles": [{}, {"font": {"bold": true}}],
+//        "defined_names": [{"Name": "Rate", "RefersTo": "Sheet1!$A$2"}]
+//    }
+//
+type WorkbookModel struct {
+	Sheets       []SheetModel  `json:"sheets"`
+	Styles       []Style       `json:"styles,omitempty"`
+	DefinedNames []DefinedName `json:"defined_names,omitempty"`
+}
+
+// SheetModel directly maps the logical model of the worksheet. The keys of
+// the ColWidths are the column names and the keys of the RowHeights are the
+// row numbers.
+type SheetModel struct {
+	Name       string             `json:"name"`
+	Hidden     bool               `json:"hidden,omitempty"`
+	Cells      []CellModel        `json:"cells"`
+	MergeCells []string           `json:"merge_cells,omitempty"`
+	ColWidths  map[string]float64 `json:"col_widths,omitempty"`
+	RowHeights map[string]float64 `json:"row_heights,omitempty"`
+}
+
+// CellModel directly maps the logical model of the cell. The Type is the
+// value type of the cell, which is one of bool, date, error, number and
+// string, or empty for the cell without value. The Value is the raw value of
+// the cell without the number format applied, the bool value is TRUE or
+// FALSE, and the rich text is flattened into the plain text. The Formula is
+// the formula of the cell without the leading equal sign, and the Value is
+// the cached result of the formula. The Style is the index of the Styles in
+// the workbook model.
+type CellModel struct {
+	Ref     string `json:"ref"`
+	Type    string `json:"type,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Formula string `json:"formula,omitempty"`
+	Style   int    `json:"style,omitempty"`
+}
+
+// ExportModel provides a function to export the logical model of the
+// workbook. The chartsheets and dialogsheets will be skipped. For example,
+// export the workbook model to the JSON:
+//
+//    model, err := f.ExportModel()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    data, err := json.Marshal(model)
+//
+func (f *File) ExportModel() (*WorkbookModel, error) {
+	theme := f.themeReader()
+	model := &WorkbookModel{Styles: []Style{*f.getCellXfStyle(0, theme)}, DefinedNames: f.GetDefinedName()}
+	styles, sst := map[int]int{0: 0}, f.sharedStringsReader()
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			if _, ok := err.(ErrNotWorksheet); ok {
+				continue
+			}
+			return model, err
+		}
+		sheetModel := SheetModel{Name: sheet, Hidden: !f.GetSheetVisible(sheet), Cells: []CellModel{}}
+		for _, row := range ws.SheetData.Row {
+			if row.CustomHeight {
+				if sheetModel.RowHeights == nil {
+					sheetModel.RowHeights = make(map[string]float64)
+				}
+				sheetModel.RowHeights[strconv.Itoa(row.R)] = row.Ht
+			}
+			for _, c := range row.C {
+				cell := c.getModel(sst)
+				if c.F != nil {
+					if cell.Formula, err = f.GetCellFormula(sheet, c.R); err != nil {
+						return model, err
+					}
+					cell.Formula = strings.TrimPrefix(cell.Formula, "=")
+				}
+				if _, ok := styles[c.S]; !ok {
+					styles[c.S] = len(model.Styles)
+					model.Styles = append(model.Styles, *f.getCellXfStyle(c.S, theme))
+				}
+				if cell.Style = styles[c.S]; cell.Type != "" || cell.Formula != "" || cell.Style != 0 {
+					sheetModel.Cells = append(sheetModel.Cells, cell)
+				}
+			}
+		}
+		if ws.MergeCells != nil {
+			for _, mergeCell := range ws.MergeCells.Cells {
+				if mergeCell != nil {
+					sheetModel.MergeCells = append(sheetModel.MergeCells, mergeCell.Ref)
+				}
+			}
+		}
+		if ws.Cols != nil {
+			for _, col := range ws.Cols.Col {
+				if !col.CustomWidth {
+					continue
+				}
+				if sheetModel.ColWidths == nil {
+					sheetModel.ColWidths = make(map[string]float64)
+				}
+				for c := col.Min; c <= col.Max && c <= TotalColumns; c++ {
+					name, _ := ColumnNumberToName(c)
+					sheetModel.ColWidths[name] = col.Width
+				}
+			}
+		}
+		model.Sheets = append(model.Sheets, sheetModel)
+	}
+	return model, nil
+}
+
+// getModel provides a function to get the logical model of the cell by
+// given shared string table, the formula and style of the cell are not
+// included.
+func (c *xlsxC) getModel(sst *xlsxSST) CellModel {
+	cell := CellModel{Ref: c.R, Value: c.V}
+	switch c.T {
+	case "b":
+		cell.Type, cell.Value = "bool", strings.ToUpper(strconv.FormatBool(c.V == "1"))
+	case "d":
+		cell.Type = "date"
+	case "e":
+		cell.Type = "error"
+	case "s":
+		cell.Type = "string"
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(sst.SI) {
+			cell.Value = sst.SI[idx].String()
+		}
+	case "str":
+		cell.Type = "string"
+	case "inlineStr":
+		cell.Type = "string"
+		if c.IS != nil {
+			cell.Value = c.IS.String()
+		}
+	default:
+		if c.V != "" {
+			cell.Type = "number"
+		}
+	}
+	return cell
+}
+
+// getCellXfStyle provides a function to get the simplified style settings of
+// the cell by given style index and theme, the theme colors will be resolved
+// into RGB color codes. The default font, fill and border of the workbook
+// are not included in the style settings.
+func (f *File) getCellXfStyle(styleID int, theme *xlsxTheme) *Style {
+	style, s := &Style{}, f.stylesReader()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return style
+	}
+	xf := s.CellXfs.Xf[styleID]
+	if xf.FontID != nil && *xf.FontID > 0 && s.Fonts != nil && *xf.FontID < len(s.Fonts.Font) {
+		style.Font = extractFont(s.Fonts.Font[*xf.FontID], theme)
+	}
+	if xf.FillID != nil && *xf.FillID > 1 && s.Fills != nil && *xf.FillID < len(s.Fills.Fill) {
+		style.Fill = extractFill(s.Fills.Fill[*xf.FillID], theme)
+	}
+	if xf.BorderID != nil && *xf.BorderID > 0 && s.Borders != nil && *xf.BorderID < len(s.Borders.Border) {
+		style.Border = extractBorders(s.Borders.Border[*xf.BorderID], theme)
+	}
+	if xf.Alignment != nil {
+		style.Alignment = &Alignment{
+			Horizontal:      xf.Alignment.Horizontal,
+			Indent:          xf.Alignment.Indent,
+			JustifyLastLine: xf.Alignment.JustifyLastLine,
+			ReadingOrder:    xf.Alignment.ReadingOrder,
+			RelativeIndent:  xf.Alignment.RelativeIndent,
+			ShrinkToFit:     xf.Alignment.ShrinkToFit,
+			TextRotation:    xf.Alignment.TextRotation,
+			Vertical:        xf.Alignment.Vertical,
+			WrapText:        xf.Alignment.WrapText,
+		}
+	}
+	if xf.Protection != nil {
+		style.Protection = &Protection{
+			Hidden: xf.Protection.Hidden != nil && *xf.Protection.Hidden,
+			Locked: xf.Protection.Locked == nil || *xf.Protection.Locked,
+		}
+	}
+	if xf.NumFmtID != nil {
+		if style.NumFmt = *xf.NumFmtID; s.NumFmts != nil && style.NumFmt >= 164 {
+			for _, numFmt := range s.NumFmts.NumFmt {
+				if numFmt != nil && numFmt.NumFmtID == style.NumFmt {
+					style.NumFmt, style.CustomNumFmt = 0, stringPtr(numFmt.FormatCode)
+				}
+			}
+		}
+	}
+	style.QuotePrefix = xf.QuotePrefix != nil && *xf.QuotePrefix
+	return style
+}
+
+// ImportModel provides a function to create a workbook by given logical
+// model of the workbook, which is exported by the ExportModel function or
+// built by the other languages. For example, create the workbook by the
+// model which is decoded from the JSON and save it:
+//
+//    var model excelize.WorkbookModel
+//    if err := json.Unmarshal(data, &model); err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    f, err := excelize.ImportModel(&model)
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.SaveAs("Book1.xlsx")
+//
+func ImportModel(model *WorkbookModel) (*File, error) {
+	f := NewFile()
+	styles := make([]int, len(model.Styles))
+	for idx := 1; idx < len(model.Styles); idx++ {
+		styleID, err := f.NewStyle(&model.Styles[idx])
+		if err != nil {
+			return f, err
+		}
+		styles[idx] = styleID
+	}
+	for idx, sheet := range model.Sheets {
+		if idx == 0 {
+			f.SetSheetName(f.GetSheetName(0), sheet.Name)
+		} else {
+			f.NewSheet(sheet.Name)
+		}
+		for _, cell := range sheet.Cells {
+			if cell.Style < 0 || cell.Style > 0 && cell.Style >= len(styles) {
+				return f, newInvalidStyleAttrError("style index", strconv.Itoa(cell.Style))
+			}
+			var styleID int
+			if cell.Style > 0 {
+				styleID = styles[cell.Style]
+			}
+			if err := f.setCellModel(sheet.Name, cell, styleID); err != nil {
+				return f, err
+			}
+		}
+		for _, mergeCell := range sheet.MergeCells {
+			cells := strings.Split(mergeCell, ":")
+			if err := f.MergeCell(sheet.Name, cells[0], cells[len(cells)-1]); err != nil {
+				return f, err
+			}
+		}
+		for col, width := range sheet.ColWidths {
+			if err := f.SetColWidth(sheet.Name, col, col, width); err != nil {
+				return f, err
+			}
+		}
+		for row, height := range sheet.RowHeights {
+			r, err := strconv.Atoi(row)
+			if err != nil {
+				return f, err
+			}
+			if err = f.SetRowHeight(sheet.Name, r, height); err != nil {
+				return f, err
+			}
+		}
+	}
+	for _, sheet := range model.Sheets {
+		if sheet.Hidden {
+			if err := f.SetSheetVisible(sheet.Name, false); err != nil {
+				return f, err
+			}
+		}
+	}
+	for idx := range model.DefinedNames {
+		if err := f.SetDefinedName(&model.DefinedNames[idx]); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// setCellModel provides a function to set the value, formula and style of
+// the cell by given worksheet name, logical model of the cell and style
+// index.
+func (f *File) setCellModel(sheet string, cell CellModel, styleID int) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	c, _, _, err := f.prepareCell(ws, sheet, cell.Ref)
+	if err != nil {
+		return err
+	}
+	c.S, c.IS, c.F = styleID, nil, nil
+	switch cell.Type {
+	case "":
+		c.T, c.V = "", ""
+	case "bool":
+		c.T, c.V = setCellBool(strings.EqualFold(cell.Value, "TRUE") || cell.Value == "1")
+	case "date":
+		c.T, c.V = "d", cell.Value
+	case "error":
+		c.T, c.V = "e", cell.Value
+	case "number":
+		if _, err = strconv.ParseFloat(cell.Value, 64); err != nil {
+			return err
+		}
+		c.T, c.V = setCellDefault(cell.Value)
+	case "string":
+		if cell.Formula != "" {
+			c.T, c.V, c.XMLSpace, err = setCellStr(cell.Value)
+			break
+		}
+		c.T, c.V, err = f.setCellString(cell.Value)
+	default:
+		return newInvalidOptionalValue("cell type", cell.Type, cellModelTypes)
+	}
+	if err == nil && cell.Formula != "" {
+		c.F = &xlsxF{Content: strings.TrimPrefix(cell.Formula, "=")}
+	}
+	return err
+}
+
+// WriteModel provides a function to write the logical model of the workbook
+// to the writer in the indented JSON format.
+func (f *File) WriteModel(w io.Writer) error {
+	model, err := f.ExportModel()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(model)
+}
+
+// ReadModel provides a function to create a workbook by given reader of the
+// logical model of the workbook in the JSON format.
+func ReadModel(r io.Reader) (*File, error) {
+	var model WorkbookModel
+	if err := json.NewDecoder(r).Decode(&model); err != nil {
+		return nil, err
+	}
+	return ImportModel(&model)
+}
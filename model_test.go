@@ -0,0 +1,122 @@
+package excelize
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkbookModel(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "Price"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 25.5))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "=A2*2"))
+	assert.NoError(t, f.SetCellBool("Sheet1", "B1", true))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "B2", []RichTextRun{{Text: "Rich "}, {Text: "text", Font: &Font{Bold: true}}}))
+	assert.NoError(t, f.MergeCell("Sheet1", "C1", "D2"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	style, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Color: "#FF0000", Family: "Arial", Size: 12},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"#FFFF00"}},
+		Border:    []Border{{Type: "left", Color: "#0000FF", Style: 1}},
+		Alignment: &Alignment{Horizontal: "center", WrapText: true},
+		NumFmt:    2,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", style))
+	customNumFmt := "0.00%"
+	style, err = f.NewStyle(&Style{CustomNumFmt: &customNumFmt})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E1", "E1", style))
+	f.NewSheet("Sheet 2")
+	assert.NoError(t, f.SetCellValue("Sheet 2", "A1", -1))
+	assert.NoError(t, f.SetCellFormula("Sheet 2", "A2", `"a"&"b"`))
+	ws, err := f.workSheetReader("Sheet 2")
+	assert.NoError(t, err)
+	ws.SheetData.Row[1].C[0].T, ws.SheetData.Row[1].C[0].V = "str", "ab"
+	ws.SheetData.Row[0].C = append(ws.SheetData.Row[0].C, xlsxC{R: "B1", T: "e", V: "#N/A"}, xlsxC{R: "C1", T: "d", V: "2021-08-01T00:00:00Z"})
+	assert.NoError(t, f.SetSheetVisible("Sheet 2", false))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "Sheet1!$A$2"}))
+
+	model, err := f.ExportModel()
+	assert.NoError(t, err)
+	assert.Len(t, model.Sheets, 2)
+	assert.Len(t, model.Styles, 3)
+	assert.Equal(t, Style{}, model.Styles[0])
+	assert.Equal(t, &Font{Bold: true, Color: "#FF0000", Family: "Arial", Size: 12}, model.Styles[1].Font)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"#FFFF00"}}, model.Styles[1].Fill)
+	assert.Equal(t, []Border{{Type: "left", Color: "#0000FF", Style: 1}}, model.Styles[1].Border)
+	assert.Equal(t, 2, model.Styles[1].NumFmt)
+	assert.Equal(t, "0.00%", *model.Styles[2].CustomNumFmt)
+	assert.Equal(t, []CellModel{
+		{Ref: "A1", Type: "string", Value: "Price", Style: 1},
+		{Ref: "B1", Type: "bool", Value: "TRUE"},
+		{Ref: "E1", Style: 2},
+		{Ref: "A2", Type: "number", Value: "25.5", Style: 1},
+		{Ref: "B2", Type: "string", Value: "Rich text"},
+		{Ref: "A3", Formula: "A2*2"},
+	}, model.Sheets[0].Cells)
+	assert.Equal(t, []string{"C1:D2"}, model.Sheets[0].MergeCells)
+	assert.Equal(t, map[string]float64{"A": 20, "B": 20}, model.Sheets[0].ColWidths)
+	assert.Equal(t, map[string]float64{"1": 30}, model.Sheets[0].RowHeights)
+	assert.Equal(t, SheetModel{Name: "Sheet 2", Hidden: true, Cells: []CellModel{
+		{Ref: "A1", Type: "number", Value: "-1"},
+		{Ref: "B1", Type: "error", Value: "#N/A"},
+		{Ref: "C1", Type: "date", Value: "2021-08-01T00:00:00Z"},
+		{Ref: "A2", Type: "string", Value: "ab", Formula: `"a"&"b"`},
+	}}, model.Sheets[1])
+	assert.Equal(t, []DefinedName{{Name: "Rate", RefersTo: "Sheet1!$A$2", Scope: "Workbook"}}, model.DefinedNames)
+
+	// Test import the workbook model from the JSON and export it again.
+	var buf bytes.Buffer
+	assert.NoError(t, f.WriteModel(&buf))
+	g, err := ReadModel(&buf)
+	assert.NoError(t, err)
+	assert.NoError(t, g.SaveAs(filepath.Join("test", "TestWorkbookModel.xlsx")))
+	imported, err := g.ExportModel()
+	assert.NoError(t, err)
+	assert.Equal(t, model.Sheets, imported.Sheets)
+	assert.Equal(t, model.DefinedNames, imported.DefinedNames)
+	assert.Equal(t, model.Styles[1].Font, imported.Styles[1].Font)
+	assert.Equal(t, model.Styles[2].CustomNumFmt, imported.Styles[2].CustomNumFmt)
+	cellStyle, err := g.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.NotZero(t, cellStyle)
+
+	// Test import the workbook model with invalid cell type, value, style
+	// and row number.
+	for _, c := range []struct {
+		model    WorkbookModel
+		expected string
+	}{
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", Cells: []CellModel{{Ref: "A1", Type: "text"}}}}}, `invalid cell type value "text", acceptable value should be one of bool, date, error, number, string`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", Cells: []CellModel{{Ref: "A1", Type: "number", Value: "x"}}}}}, `strconv.ParseFloat: parsing "x": invalid syntax`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", Cells: []CellModel{{Ref: "A1", Style: 1}}}}}, `invalid style index "1"`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", Cells: []CellModel{{Ref: "A", Type: "string"}}}}}, `cannot convert cell "A" to coordinates: invalid cell name "A"`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", Cells: []CellModel{{Ref: "A1", Type: "string", Value: strings.Repeat("c", TotalCellChars+1)}}}}}, ErrCellCharsLength.Error()},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", MergeCells: []string{"A:B"}}}}, `cannot convert cell "A" to coordinates: invalid cell name "A"`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", ColWidths: map[string]float64{"A": 300}}}}, ErrColumnWidth.Error()},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", RowHeights: map[string]float64{"x": 30}}}}, `strconv.Atoi: parsing "x": invalid syntax`},
+		{WorkbookModel{Sheets: []SheetModel{{Name: "Sheet1", RowHeights: map[string]float64{"1": 500}}}}, ErrMaxRowHeight.Error()},
+		{WorkbookModel{Styles: []Style{{}, {Font: &Font{Family: strings.Repeat("f", MaxFontFamilyLength+1)}}}}, ErrFontLength.Error()},
+		{WorkbookModel{DefinedNames: []DefinedName{{Name: "Rate", RefersTo: "Sheet1!A1"}, {Name: "Rate", RefersTo: "Sheet1!A2"}}}, ErrDefinedNameduplicate.Error()},
+	} {
+		_, err = ImportModel(&c.model)
+		assert.EqualError(t, err, c.expected)
+	}
+	_, err = ReadModel(strings.NewReader("{"))
+	assert.EqualError(t, err, "unexpected EOF")
+	_, err = f.ExportModel()
+	assert.NoError(t, err)
+	// Test export and write the workbook model with unsupported charset
+	// worksheet.
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	_, err = f.ExportModel()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.WriteModel(&buf), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
@@ -0,0 +1,528 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+// Package parse providing a set of functions that allow you to tokenize and
+// parse the formulas of the spreadsheet into the abstract syntax tree, find
+// the cell references and defined names which are used in the formulas, and
+// rewrite the references, for example, to build the linters or the
+// dependency analyzers of the workbooks.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/efp"
+)
+
+var (
+	// ErrEmptyFormula defined the error message on parse an empty formula.
+	ErrEmptyFormula = errors.New("the formula is empty")
+	// ErrUnexpectedEnd defined the error message on the formula ends before
+	// the expression, the function or the parentheses are closed.
+	ErrUnexpectedEnd = errors.New("unexpected end of the formula")
+)
+
+// newUnexpectedTokenError defined the error message on receive an unexpected
+// token in the formula.
+func newUnexpectedTokenError(token efp.Token) error {
+	value := token.TValue
+	if value == "" {
+		value = map[string]string{efp.TokenSubTypeStart: "(", efp.TokenSubTypeStop: ")"}[token.TSubType]
+	}
+	return fmt.Errorf("unexpected token %q in the formula", value)
+}
+
+// NodeType defined the type of the node in the abstract syntax tree of the
+// formula.
+type NodeType int
+
+// This section defines the currently supported node types.
+const (
+	// NodeNumber is the number operand, the Value is the number text.
+	NodeNumber NodeType = iota
+	// NodeText is the text operand, the Value is the unquoted text.
+	NodeText
+	// NodeLogical is the TRUE or FALSE operand.
+	NodeLogical
+	// NodeError is the error operand, such as #N/A and #REF!.
+	NodeError
+	// NodeReference is the cell reference, the range reference, the defined
+	// name or the structured reference, the Value is the reference with the
+	// unquoted worksheet name.
+	NodeReference
+	// NodeFunction is the function call, the Value is the function name and
+	// the Children are the arguments.
+	NodeFunction
+	// NodeArray is the array constant, the Children are the rows.
+	NodeArray
+	// NodeArrayRow is the row of the array constant, the Children are the
+	// elements.
+	NodeArrayRow
+	// NodeParen is the expression in the parentheses.
+	NodeParen
+	// NodePrefix is the prefix operator, such as "-" and "+".
+	NodePrefix
+	// NodeInfix is the infix operator, the Value is the operator, which is a
+	// space for the intersection and a comma for the union of the ranges.
+	NodeInfix
+	// NodePostfix is the percent operator.
+	NodePostfix
+	// NodeEmpty is the omitted argument of the function.
+	NodeEmpty
+	// NodeCall is the call on the result of the function or the expression
+	// in the parentheses, such as LAMBDA(x,x+1)(1), the first child is the
+	// callee and the other Children are the arguments.
+	NodeCall
+)
+
+// Node directly maps the node in the abstract syntax tree of the formula.
+type Node struct {
+	Type     NodeType
+	Value    string
+	Children []*Node
+}
+
+// AST directly maps the abstract syntax tree of the formula.
+type AST struct {
+	Root *Node
+}
+
+// Token directly maps the token of the formula, the Type and SubType are the
+// same as the token type and subtype constants in the
+// github.com/xuri/efp package.
+type Token struct {
+	Value   string
+	Type    string
+	SubType string
+}
+
+// infixPriority defined the priorities of the infix operators, the operators
+// with the larger priorities will be evaluated first.
+var infixPriority = map[string]int{
+	",": 1, "=": 2, "<>": 2, "<": 2, ">": 2, "<=": 2, ">=": 2, "&": 3,
+	"+": 4, "-": 4, "*": 5, "/": 5, "^": 6, " ": 9,
+}
+
+const (
+	// prefixPriority defined the priority of the prefix operators.
+	prefixPriority = 7
+	// postfixPriority defined the priority of the percent operator.
+	postfixPriority = 8
+)
+
+// Tokenize provides a function to split the formula into tokens, the formula
+// could be started with or without the equal sign. For example:
+//
+//    for _, token := range parse.Tokenize("=SUM(A1:A10)*2") {
+//        fmt.Println(token.Type, token.SubType, token.Value)
+//    }
+//
+func Tokenize(formula string) []Token {
+	var tokens []Token
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(formula) {
+		tokens = append(tokens, Token{Value: token.TValue, Type: token.TType, SubType: token.TSubType})
+	}
+	return tokens
+}
+
+// Formula provides a function to parse the formula into the abstract syntax
+// tree, the formula could be started with or without the equal sign. For
+// example, print the references in the formula:
+//
+//    ast, err := parse.Formula("=SUM(Sheet1!A1:A10)+Rate")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, ref := range ast.References() {
+//        fmt.Println(ref.Sheet, ref.Ref, ref.Type)
+//    }
+//
+func Formula(formula string) (AST, error) {
+	ps := efp.ExcelParser()
+	var tokens []efp.Token
+	for _, token := range ps.Parse(formula) {
+		if token.TType != efp.TokenTypeWhitespace && token.TType != efp.TokenTypeNoop {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return AST{}, ErrEmptyFormula
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return AST{}, err
+	}
+	if token := p.peek(); token != nil {
+		return AST{}, newUnexpectedTokenError(*token)
+	}
+	return AST{Root: root}, err
+}
+
+// parser directly maps the state of the formula parser.
+type parser struct {
+	tokens []efp.Token
+	pos    int
+}
+
+// peek returns the next token without consuming it, or nil at the end of the
+// formula.
+func (p *parser) peek() *efp.Token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// next returns and consumes the next token, or nil at the end of the
+// formula.
+func (p *parser) next() *efp.Token {
+	token := p.peek()
+	if token != nil {
+		p.pos++
+	}
+	return token
+}
+
+// isStop checks if the token is the end of the function or the
+// subexpression by given token type.
+func isStop(token *efp.Token, tokenType string) bool {
+	return token != nil && token.TType == tokenType && token.TSubType == efp.TokenSubTypeStop
+}
+
+// parseExpr provides a function to parse the expression which only contains
+// the operators with the priority not less than the given priority.
+func (p *parser) parseExpr(priority int) (*Node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for token := p.peek(); token != nil; token = p.peek() {
+		switch token.TType {
+		case efp.TokenTypeOperatorPostfix:
+			if postfixPriority < priority {
+				return left, err
+			}
+			p.next()
+			left = &Node{Type: NodePostfix, Value: token.TValue, Children: []*Node{left}}
+		case efp.TokenTypeOperatorInfix:
+			operator := token.TValue
+			if token.TSubType == efp.TokenSubTypeIntersection {
+				operator = " "
+			}
+			pri, ok := infixPriority[operator]
+			if !ok {
+				return nil, newUnexpectedTokenError(*token)
+			}
+			if pri < priority {
+				return left, err
+			}
+			p.next()
+			right, err := p.parseExpr(pri + 1)
+			if err != nil {
+				return nil, err
+			}
+			left = &Node{Type: NodeInfix, Value: operator, Children: []*Node{left, right}}
+		default:
+			return left, err
+		}
+	}
+	return left, err
+}
+
+// parseOperand provides a function to parse the operand, the prefix
+// operator, the subexpression, the function call or the array constant.
+func (p *parser) parseOperand() (*Node, error) {
+	token := p.next()
+	if token == nil {
+		return nil, ErrUnexpectedEnd
+	}
+	switch token.TType {
+	case efp.TokenTypeOperand:
+		nodeType, ok := map[string]NodeType{
+			efp.TokenSubTypeNumber:  NodeNumber,
+			efp.TokenSubTypeText:    NodeText,
+			efp.TokenSubTypeLogical: NodeLogical,
+			efp.TokenSubTypeError:   NodeError,
+			efp.TokenSubTypeRange:   NodeReference,
+		}[token.TSubType]
+		if !ok {
+			return nil, newUnexpectedTokenError(*token)
+		}
+		return &Node{Type: nodeType, Value: token.TValue}, nil
+	case efp.TokenTypeUnknown:
+		// The reference error with the worksheet name, such as Sheet1!#REF!,
+		// is tokenized as the worksheet name and the error operand.
+		if next := p.peek(); strings.HasSuffix(token.TValue, "!") && next != nil &&
+			next.TType == efp.TokenTypeOperand && next.TSubType == efp.TokenSubTypeError {
+			p.next()
+			ref := Reference{Sheet: strings.TrimSuffix(token.TValue, "!"), Ref: next.TValue}
+			return &Node{Type: NodeError, Value: ref.String()}, nil
+		}
+	case efp.TokenTypeOperatorPrefix:
+		child, err := p.parseExpr(prefixPriority)
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Type: NodePrefix, Value: token.TValue, Children: []*Node{child}}, nil
+	case efp.TokenTypeSubexpression:
+		if token.TSubType != efp.TokenSubTypeStart {
+			return nil, newUnexpectedTokenError(*token)
+		}
+		child, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.parseSubexpressionStop(); err != nil {
+			return nil, err
+		}
+		return p.parseCall(&Node{Type: NodeParen, Children: []*Node{child}})
+	case efp.TokenTypeFunction:
+		if token.TSubType != efp.TokenSubTypeStart {
+			return nil, newUnexpectedTokenError(*token)
+		}
+		if token.TValue == "ARRAY" {
+			return p.parseArray()
+		}
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseCall(&Node{Type: NodeFunction, Value: token.TValue, Children: args})
+	}
+	return nil, newUnexpectedTokenError(*token)
+}
+
+// parseSubexpressionStop provides a function to consume the closing
+// parenthesis of the subexpression or the call.
+func (p *parser) parseSubexpressionStop() error {
+	if stop := p.next(); !isStop(stop, efp.TokenTypeSubexpression) {
+		if stop == nil {
+			return ErrUnexpectedEnd
+		}
+		return newUnexpectedTokenError(*stop)
+	}
+	return nil
+}
+
+// parseCall provides a function to parse the calls on the result of the
+// function or the subexpression, such as LAMBDA(a,b,a+b)(2,3), the arguments
+// of the call are tokenized as the union of the expressions.
+func (p *parser) parseCall(callee *Node) (*Node, error) {
+	for token := p.peek(); token != nil && token.TType == efp.TokenTypeSubexpression &&
+		token.TSubType == efp.TokenSubTypeStart; token = p.peek() {
+		p.next()
+		call := &Node{Type: NodeCall, Children: []*Node{callee}}
+		for !isStop(p.peek(), efp.TokenTypeSubexpression) {
+			arg, err := p.parseExpr(infixPriority[","] + 1)
+			if err != nil {
+				return nil, err
+			}
+			call.Children = append(call.Children, arg)
+			if next := p.peek(); next == nil || next.TType != efp.TokenTypeOperatorInfix || next.TValue != "," {
+				break
+			}
+			p.next()
+			if next := p.peek(); isStop(next, efp.TokenTypeSubexpression) {
+				return nil, newUnexpectedTokenError(*next)
+			}
+		}
+		if err := p.parseSubexpressionStop(); err != nil {
+			return nil, err
+		}
+		callee = call
+	}
+	return callee, nil
+}
+
+// parseArgs provides a function to parse the arguments of the function until
+// the end of the function, the omitted arguments will be parsed as the empty
+// nodes.
+func (p *parser) parseArgs() ([]*Node, error) {
+	var args []*Node
+	if isStop(p.peek(), efp.TokenTypeFunction) {
+		p.next()
+		return args, nil
+	}
+	for {
+		arg := &Node{Type: NodeEmpty}
+		if token := p.peek(); token != nil && token.TType != efp.TokenTypeArgument && !isStop(token, efp.TokenTypeFunction) {
+			var err error
+			if arg, err = p.parseExpr(0); err != nil {
+				return nil, err
+			}
+		}
+		args = append(args, arg)
+		token := p.next()
+		if token == nil {
+			return nil, ErrUnexpectedEnd
+		}
+		if isStop(token, efp.TokenTypeFunction) {
+			return args, nil
+		}
+		if token.TType != efp.TokenTypeArgument {
+			return nil, newUnexpectedTokenError(*token)
+		}
+	}
+}
+
+// parseArray provides a function to parse the rows of the array constant.
+func (p *parser) parseArray() (*Node, error) {
+	array := &Node{Type: NodeArray}
+	for {
+		token := p.next()
+		if token == nil {
+			return nil, ErrUnexpectedEnd
+		}
+		if token.TType != efp.TokenTypeFunction || token.TSubType != efp.TokenSubTypeStart || token.TValue != "ARRAYROW" {
+			return nil, newUnexpectedTokenError(*token)
+		}
+		elements, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		array.Children = append(array.Children, &Node{Type: NodeArrayRow, Children: elements})
+		if token = p.next(); token == nil {
+			return nil, ErrUnexpectedEnd
+		}
+		if isStop(token, efp.TokenTypeFunction) {
+			return array, nil
+		}
+		if token.TType != efp.TokenTypeArgument {
+			return nil, newUnexpectedTokenError(*token)
+		}
+	}
+}
+
+// Walk provides a function to traverse the nodes of the abstract syntax tree
+// in depth-first order, the children of the node will be skipped if the
+// function returns false.
+func (a AST) Walk(fn func(node *Node) bool) {
+	walk(a.Root, fn)
+}
+
+// walk traverses the node and its children in depth-first order.
+func walk(node *Node, fn func(node *Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	for _, child := range node.Children {
+		walk(child, fn)
+	}
+}
+
+// OperatorPriority returns the priority of the operator node, the operators
+// with the larger priorities will be evaluated first, and the priority of
+// the other nodes are larger than all operators.
+func OperatorPriority(node *Node) int {
+	switch node.Type {
+	case NodeInfix:
+		return infixPriority[node.Value]
+	case NodePrefix:
+		return prefixPriority
+	case NodePostfix:
+		return postfixPriority
+	}
+	return postfixPriority + 2
+}
+
+// FormatOptions directly maps the settings of the formula text. The
+// ListSeparator is used to separate the arguments of the functions and the
+// ranges in the union, the DecimalSeparator is used in the numbers, the comma
+// and the dot will be used if they are empty. The array constants will
+// always be in the invariant format.
+type FormatOptions struct {
+	ListSeparator    string
+	DecimalSeparator string
+}
+
+// String returns the formula text of the abstract syntax tree without the
+// leading equal sign, which could be used in the spreadsheet file.
+func (a AST) String() string {
+	return a.Format(FormatOptions{})
+}
+
+// Format returns the formula text of the abstract syntax tree without the
+// leading equal sign by given format settings, for example, get the formula
+// text with the semicolon list separator and the comma decimal separator,
+// which is used in the formula bar of the German locale:
+//
+//    ast.Format(parse.FormatOptions{ListSeparator: ";", DecimalSeparator: ","})
+//
+func (a AST) Format(opts FormatOptions) string {
+	if opts.ListSeparator == "" {
+		opts.ListSeparator = ","
+	}
+	if opts.DecimalSeparator == "" {
+		opts.DecimalSeparator = "."
+	}
+	var sb strings.Builder
+	writeNode(&sb, a.Root, &opts, false)
+	return sb.String()
+}
+
+// writeNode provides a function to write the formula text of the node by
+// given format settings.
+func writeNode(sb *strings.Builder, node *Node, opts *FormatOptions, inArray bool) {
+	if node == nil {
+		return
+	}
+	writeList := func(nodes []*Node, sep string) {
+		for i, child := range nodes {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			writeNode(sb, child, opts, inArray || node.Type == NodeArray)
+		}
+	}
+	switch node.Type {
+	case NodeNumber:
+		if inArray {
+			sb.WriteString(node.Value)
+			return
+		}
+		sb.WriteString(strings.Replace(node.Value, ".", opts.DecimalSeparator, 1))
+	case NodeText:
+		sb.WriteString(`"` + strings.Replace(node.Value, `"`, `""`, -1) + `"`)
+	case NodeReference:
+		sb.WriteString(ParseReference(node.Value).String())
+	case NodeFunction:
+		sb.WriteString(node.Value + "(")
+		writeList(node.Children, opts.ListSeparator)
+		sb.WriteString(")")
+	case NodeArray:
+		sb.WriteString("{")
+		writeList(node.Children, ";")
+		sb.WriteString("}")
+	case NodeArrayRow:
+		writeList(node.Children, ",")
+	case NodeParen:
+		sb.WriteString("(")
+		writeList(node.Children, "")
+		sb.WriteString(")")
+	case NodePrefix:
+		sb.WriteString(node.Value)
+		writeList(node.Children, "")
+	case NodeInfix:
+		if node.Value == "," {
+			writeList(node.Children, opts.ListSeparator)
+			return
+		}
+		writeList(node.Children, node.Value)
+	case NodePostfix:
+		writeList(node.Children, "")
+		sb.WriteString(node.Value)
+	case NodeCall:
+		writeList(node.Children[:1], "")
+		sb.WriteString("(")
+		writeList(node.Children[1:], opts.ListSeparator)
+		sb.WriteString(")")
+	default:
+		sb.WriteString(node.Value)
+	}
+}
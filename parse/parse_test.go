@@ -0,0 +1,126 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormula(t *testing.T) {
+	for formula, expected := range map[string]string{
+		`=SUM(A1:B2,'Sheet 1'!C3)*-2%+"a""b"&{1,2;3,4}`: `SUM(A1:B2,'Sheet 1'!C3)*-2%+"a""b"&{1,2;3,4}`,
+		`=(A1,B1) C1:C2`:                `(A1,B1) C1:C2`,
+		`IF(TRUE,#N/A,Table1[Col])`:     `IF(TRUE,#N/A,Table1[Col])`,
+		`=Sheet1!$A$1:$B$2 + name1 ^ 2`: `Sheet1!$A$1:$B$2+name1^2`,
+		`=IF(A1>=B1,,1)`:                `IF(A1>=B1,,1)`,
+		`=-(1+2)`:                       `-(1+2)`,
+		`=NOW()`:                        `NOW()`,
+		`='It''s'!A1`:                   `'It''s'!A1`,
+		`=Sheet1!#REF!+'Sheet 2'!#REF!`: `Sheet1!#REF!+'Sheet 2'!#REF!`,
+		`=LAMBDA(a,b,a+b)(2, 3)`:        `LAMBDA(a,b,a+b)(2,3)`,
+		`=(LAMBDA(x,x))((A1,B1))()`:     `(LAMBDA(x,x))((A1,B1))()`,
+	} {
+		ast, err := Formula(formula)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, ast.String(), formula)
+	}
+
+	// Test the priorities of the operators.
+	ast, err := Formula("=1+2*3^-2%&A1=B1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Node{Type: NodeInfix, Value: "=", Children: []*Node{
+		{Type: NodeInfix, Value: "&", Children: []*Node{
+			{Type: NodeInfix, Value: "+", Children: []*Node{
+				{Type: NodeNumber, Value: "1"},
+				{Type: NodeInfix, Value: "*", Children: []*Node{
+					{Type: NodeNumber, Value: "2"},
+					{Type: NodeInfix, Value: "^", Children: []*Node{
+						{Type: NodeNumber, Value: "3"},
+						{Type: NodePrefix, Value: "-", Children: []*Node{
+							{Type: NodePostfix, Value: "%", Children: []*Node{{Type: NodeNumber, Value: "2"}}},
+						}},
+					}},
+				}},
+			}},
+			{Type: NodeReference, Value: "A1"},
+		}},
+		{Type: NodeReference, Value: "B1"},
+	}}, ast.Root)
+	ast, err = Formula("=1-2-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "-", ast.Root.Value)
+	assert.Equal(t, NodeInfix, ast.Root.Children[0].Type)
+	assert.Equal(t, NodeNumber, ast.Root.Children[1].Type)
+
+	// Test parse the call on the result of the function.
+	ast, err = Formula("=LAMBDA(x,x+1)(1)*2")
+	assert.NoError(t, err)
+	assert.Equal(t, &Node{Type: NodeInfix, Value: "*", Children: []*Node{
+		{Type: NodeCall, Children: []*Node{
+			{Type: NodeFunction, Value: "LAMBDA", Children: []*Node{
+				{Type: NodeReference, Value: "x"},
+				{Type: NodeInfix, Value: "+", Children: []*Node{
+					{Type: NodeReference, Value: "x"},
+					{Type: NodeNumber, Value: "1"},
+				}},
+			}},
+			{Type: NodeNumber, Value: "1"},
+		}},
+		{Type: NodeNumber, Value: "2"},
+	}}, ast.Root)
+
+	// Test walk the abstract syntax tree and skip the children.
+	ast, err = Formula("=SUM(A1,MAX(B1,C1))")
+	assert.NoError(t, err)
+	var functions []string
+	ast.Walk(func(node *Node) bool {
+		if node.Type == NodeFunction {
+			functions = append(functions, node.Value)
+		}
+		return node.Value != "MAX"
+	})
+	assert.Equal(t, []string{"SUM", "MAX"}, functions)
+
+	// Test parse invalid formulas.
+	for formula, expected := range map[string]string{
+		"":             ErrEmptyFormula.Error(),
+		"=1+":          ErrUnexpectedEnd.Error(),
+		"=SUM(1":       ErrUnexpectedEnd.Error(),
+		"=SUM(1,":      ErrUnexpectedEnd.Error(),
+		"=(1":          ErrUnexpectedEnd.Error(),
+		"={1,2":        ErrUnexpectedEnd.Error(),
+		"={1,2}+":      ErrUnexpectedEnd.Error(),
+		"=1)":          `unexpected token ")" in the formula`,
+		"=(1))":        `unexpected token ")" in the formula`,
+		"=SUM(1))":     `unexpected token ")" in the formula`,
+		"=SUM(1)(2":    ErrUnexpectedEnd.Error(),
+		"=SUM(1)(2,)":  `unexpected token ")" in the formula`,
+		"=SUM(1)(2 3":  ErrUnexpectedEnd.Error(),
+		"=Sheet1!#REF": `unexpected token "Sheet1!" in the formula`,
+	} {
+		_, err := Formula(formula)
+		assert.EqualError(t, err, expected, formula)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ast, err := Formula("=SUM(1.5,{2.5,3},(A1,B1))")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(1,5;{2.5,3};(A1;B1))", ast.Format(FormatOptions{ListSeparator: ";", DecimalSeparator: ","}))
+	assert.Equal(t, "SUM(1.5,{2.5,3},(A1,B1))", ast.Format(FormatOptions{}))
+	assert.Equal(t, 10, OperatorPriority(ast.Root))
+	assert.Equal(t, 1, OperatorPriority(ast.Root.Children[2].Children[0]))
+	ast, err = Formula("=LAMBDA(a,b,a+b)(1.5,2)")
+	assert.NoError(t, err)
+	assert.Equal(t, "LAMBDA(a;b;a+b)(1,5;2)", ast.Format(FormatOptions{ListSeparator: ";", DecimalSeparator: ","}))
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []Token{
+		{Value: "SUM", Type: "Function", SubType: "Start"},
+		{Value: "A1:A10", Type: "Operand", SubType: "Range"},
+		{Value: "", Type: "Function", SubType: "Stop"},
+		{Value: "*", Type: "OperatorInfix", SubType: "Math"},
+		{Value: "2", Type: "Operand", SubType: "Number"},
+	}, Tokenize("=SUM(A1:A10)*2"))
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ReferenceType defined the type of the reference in the formula.
+type ReferenceType string
+
+// This section defines the currently supported reference types.
+const (
+	ReferenceCell       ReferenceType = "cell"
+	ReferenceRange      ReferenceType = "range"
+	ReferenceName       ReferenceType = "name"
+	ReferenceStructured ReferenceType = "structured"
+)
+
+var (
+	// cellRefRegexp defined the regular expression to match the cell
+	// reference in the A1 reference style.
+	cellRefRegexp = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+	// rangeRefRegexp defined the regular expression to match the range
+	// reference, the whole columns or the whole rows reference in the A1
+	// reference style.
+	rangeRefRegexp = regexp.MustCompile(`^(\$?[A-Za-z]{1,3}\$?[0-9]+:\$?[A-Za-z]{1,3}\$?[0-9]+|\$?[A-Za-z]{1,3}:\$?[A-Za-z]{1,3}|\$?[0-9]+:\$?[0-9]+)$`)
+	// unquotedSheetRegexp defined the regular expression to match the
+	// worksheet name which could be used without the quotes.
+	unquotedSheetRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+)
+
+// Reference directly maps the reference in the formula. The Sheet is the
+// unquoted worksheet name or empty if the reference doesn't specify the
+// worksheet, the Ref is the reference without the worksheet name, such as
+// A1, $A$1:$B$2, A:A, Rate or Sales[Amount].
+type Reference struct {
+	Sheet string
+	Ref   string
+	Type  ReferenceType
+}
+
+// ParseReference provides a function to parse the reference text with the
+// unquoted worksheet name into the reference.
+func ParseReference(text string) Reference {
+	var ref Reference
+	depth, idx := 0, -1
+	for i, r := range text {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '!':
+			if depth == 0 {
+				idx = i
+			}
+		}
+	}
+	ref.Ref = text
+	if idx != -1 {
+		ref.Sheet, ref.Ref = text[:idx], text[idx+1:]
+	}
+	switch {
+	case cellRefRegexp.MatchString(ref.Ref):
+		ref.Type = ReferenceCell
+	case rangeRefRegexp.MatchString(ref.Ref):
+		ref.Type = ReferenceRange
+	case strings.Contains(ref.Ref, "["):
+		ref.Type = ReferenceStructured
+	default:
+		ref.Type = ReferenceName
+	}
+	return ref
+}
+
+// String returns the reference text in the formula, the worksheet name will
+// be quoted if it's required.
+func (r Reference) String() string {
+	if r.Sheet == "" {
+		return r.Ref
+	}
+	sheet := r.Sheet
+	if !unquotedSheetRegexp.MatchString(sheet) || cellRefRegexp.MatchString(sheet) {
+		sheet = "'" + strings.Replace(sheet, "'", "''", -1) + "'"
+	}
+	return sheet + "!" + r.Ref
+}
+
+// References provides a function to get the references in the formula in
+// the order of the appearance, include the cell references, the range
+// references, the defined names and the structured references.
+func (a AST) References() []Reference {
+	var refs []Reference
+	a.Walk(func(node *Node) bool {
+		if node.Type == NodeReference {
+			refs = append(refs, ParseReference(node.Value))
+		}
+		return true
+	})
+	return refs
+}
+
+// RewriteReferences provides a function to rewrite the references in the
+// formula by given function, which returns the new reference of each
+// reference, the reference will be replaced by the error operand if the new
+// reference is the #REF! error. For example, rename the worksheet in the
+// references:
+//
+//    ast, err := parse.Formula("SUM(Sheet1!A1:A10)*Sheet1!B1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    ast.RewriteReferences(func(ref parse.Reference) parse.Reference {
+//        if ref.Sheet == "Sheet1" {
+//            ref.Sheet = "Sales 2021"
+//        }
+//        return ref
+//    })
+//    fmt.Println(ast) // SUM('Sales 2021'!A1:A10)*'Sales 2021'!B1
+//
+func (a AST) RewriteReferences(fn func(ref Reference) Reference) {
+	a.Walk(func(node *Node) bool {
+		if node.Type == NodeReference {
+			ref := fn(ParseReference(node.Value))
+			if ref.Ref == "#REF!" {
+				node.Type, node.Value = NodeError, ref.String()
+				return true
+			}
+			node.Value = ref.Ref
+			if ref.Sheet != "" {
+				node.Value = ref.Sheet + "!" + ref.Ref
+			}
+		}
+		return true
+	})
+}
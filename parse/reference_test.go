@@ -0,0 +1,65 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	for text, expected := range map[string]Reference{
+		"A1":                          {Ref: "A1", Type: ReferenceCell},
+		"Sheet1!$A$1:$B$2":            {Sheet: "Sheet1", Ref: "$A$1:$B$2", Type: ReferenceRange},
+		"Sheet 1!A:A":                 {Sheet: "Sheet 1", Ref: "A:A", Type: ReferenceRange},
+		"1:1":                         {Ref: "1:1", Type: ReferenceRange},
+		"Rate":                        {Ref: "Rate", Type: ReferenceName},
+		"Sales[[#This Row],[Amount]]": {Ref: "Sales[[#This Row],[Amount]]", Type: ReferenceStructured},
+		"[1]Sheet1!A1":                {Sheet: "[1]Sheet1", Ref: "A1", Type: ReferenceCell},
+	} {
+		assert.Equal(t, expected, ParseReference(text), text)
+	}
+	for expected, ref := range map[string]Reference{
+		"A1":           {Ref: "A1"},
+		"Sheet1!A1":    {Sheet: "Sheet1", Ref: "A1"},
+		"'Sheet 1'!A1": {Sheet: "Sheet 1", Ref: "A1"},
+		"'A1'!A1":      {Sheet: "A1", Ref: "A1"},
+		"'1Q'!A1":      {Sheet: "1Q", Ref: "A1"},
+		"'It''s'!A1":   {Sheet: "It's", Ref: "A1"},
+	} {
+		assert.Equal(t, expected, ref.String())
+	}
+}
+
+func TestReferences(t *testing.T) {
+	ast, err := Formula("=SUM(Sheet1!A1:A10)*Sheet1!B1+Rate-'Sheet 2'!C1+Sales[Amount]")
+	assert.NoError(t, err)
+	assert.Equal(t, []Reference{
+		{Sheet: "Sheet1", Ref: "A1:A10", Type: ReferenceRange},
+		{Sheet: "Sheet1", Ref: "B1", Type: ReferenceCell},
+		{Ref: "Rate", Type: ReferenceName},
+		{Sheet: "Sheet 2", Ref: "C1", Type: ReferenceCell},
+		{Ref: "Sales[Amount]", Type: ReferenceStructured},
+	}, ast.References())
+
+	ast.RewriteReferences(func(ref Reference) Reference {
+		switch ref.Sheet {
+		case "Sheet1":
+			ref.Sheet = "Sales 2021"
+		case "Sheet 2":
+			ref.Sheet = ""
+		}
+		return ref
+	})
+	assert.Equal(t, "SUM('Sales 2021'!A1:A10)*'Sales 2021'!B1+Rate-C1+Sales[Amount]", ast.String())
+
+	// Test rewrite the references with the reference error.
+	ast, err = Formula("='Sheet 2'!A1+B1")
+	assert.NoError(t, err)
+	ast.RewriteReferences(func(ref Reference) Reference {
+		ref.Ref = "#REF!"
+		return ref
+	})
+	assert.Equal(t, "'Sheet 2'!#REF!+#REF!", ast.String())
+	assert.Equal(t, NodeError, ast.Root.Children[0].Type)
+	assert.Len(t, ast.References(), 0)
+}
@@ -0,0 +1,460 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mohae/deepcopy"
+)
+
+// PasteMode is the bit mask of the options of the PasteSpecial function.
+type PasteMode uint16
+
+// This section defines the options of the PasteSpecial function, combine them
+// with the bitwise OR operator. One of the PasteValues, PasteFormulas and
+// PasteFormats shall be specified, or the formulas and the formats will be
+// pasted, and at most one of the operations could be specified.
+const (
+	PasteValues PasteMode = 1 << iota
+	PasteFormulas
+	PasteFormats
+	PasteTranspose
+	PasteSkipBlanks
+	PasteAdd
+	PasteSubtract
+	PasteMultiply
+	PasteDivide
+	PasteAll = PasteFormulas | PasteFormats
+)
+
+// formulaCellRefRegexp defined the regular expression to match the cell
+// references in the formula.
+var formulaCellRefRegexp = regexp.MustCompile(`\$?[A-Za-z]{1,3}\$?[0-9]+`)
+
+// pasteCell directly maps the cell in the source range of the PasteSpecial
+// function with the coordinates relative to the top-left cell of the range.
+type pasteCell struct {
+	col, row int
+	cell     xlsxC
+	formula  string
+}
+
+// PasteSpecial provides a function to copy the cells in the range to the
+// location starting from the given cell like the paste special in Excel by
+// given the source range and the target cell with the worksheet names, and
+// the paste mode. The paste modes are:
+//
+//    PasteValues     | Paste the values of the cells, the cached results will be used for the formulas
+//    PasteFormulas   | Paste the values and formulas of the cells, the relative references will be adjusted
+//    PasteFormats    | Paste the styles of the cells
+//    PasteAll        | Paste the formulas and the styles of the cells
+//    PasteTranspose  | Switch the rows and columns of the range
+//    PasteSkipBlanks | Don't replace the values of the target cells with the blank cells
+//    PasteAdd        | Add the numeric values to the target cells
+//    PasteSubtract   | Subtract the numeric values from the target cells
+//    PasteMultiply   | Multiply the target cells by the numeric values
+//    PasteDivide     | Divide the target cells by the numeric values
+//
+// The operations only apply to the numeric values, and the formulas of the
+// target cells will be replaced by the calculated values. The references in
+// the formulas which are out of the worksheet after adjusted will be replaced
+// with #REF!. For example, paste the transposed values of the range A1:C3 in
+// Sheet1 to the cell E1 in Sheet2, and add the values of the range A1:C3 in
+// Sheet1 to the values of the cells in Sheet1!A5:C7:
+//
+//    err := f.PasteSpecial("Sheet1!A1:C3", "Sheet2!E1", excelize.PasteValues|excelize.PasteTranspose)
+//    err = f.PasteSpecial("Sheet1!A1:C3", "Sheet1!A5", excelize.PasteValues|excelize.PasteAdd)
+//
+func (f *File) PasteSpecial(srcRange, dstCell string, mode PasteMode) error {
+	var ops int
+	for _, op := range []PasteMode{PasteAdd, PasteSubtract, PasteMultiply, PasteDivide} {
+		if mode&op != 0 {
+			ops++
+		}
+	}
+	if ops > 1 {
+		return ErrParameterInvalid
+	}
+	if mode&(PasteValues|PasteFormulas|PasteFormats) == 0 {
+		mode |= PasteAll
+	}
+	srcSheet, ref, err := splitSheetRef(srcRange)
+	if err != nil {
+		return err
+	}
+	dstSheet, dst, err := splitSheetRef(dstCell)
+	if err != nil {
+		return err
+	}
+	rng := strings.Split(ref, ":")
+	coordinates, err := areaRangeToCoordinates(rng[0], rng[len(rng)-1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	dstCol, dstRow, err := CellNameToCoordinates(dst)
+	if err != nil {
+		return err
+	}
+	cols, rows := coordinates[2]-coordinates[0], coordinates[3]-coordinates[1]
+	if mode&PasteTranspose != 0 {
+		cols, rows = rows, cols
+	}
+	if dstCol+cols > TotalColumns {
+		return ErrColumnNumber
+	}
+	if dstRow+rows > TotalRows {
+		return ErrMaxRows
+	}
+	return f.pasteCells(srcSheet, dstSheet, coordinates, dstCol, dstRow, mode, func(formula string, srcCol, srcRow, col, row int) string {
+		return shiftFormulaCellRefs(formula, col-srcCol, row-srcRow)
+	})
+}
+
+// pasteCells provides a function to paste the cells in the range of the
+// source worksheet to the target worksheet by given worksheet names, the
+// coordinates of the source range, the coordinates of the target cell, the
+// paste mode and the function to adjust the formulas by given coordinates of
+// the source cell and the target cell.
+func (f *File) pasteCells(srcSheet, dstSheet string, coordinates []int, dstCol, dstRow int, mode PasteMode, adjust func(formula string, srcCol, srcRow, col, row int) string) error {
+	cells, err := f.getPasteCells(srcSheet, coordinates)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(dstSheet)
+	if err != nil {
+		return err
+	}
+	sheetID := f.getSheetID(dstSheet)
+	for _, src := range cells {
+		col, row := dstCol+src.col, dstRow+src.row
+		if mode&PasteTranspose != 0 {
+			col, row = dstCol+src.row, dstRow+src.col
+		}
+		prepareSheetXML(ws, col, row)
+		cell := &ws.SheetData.Row[row-1].C[col-1]
+		if mode&PasteFormats != 0 {
+			cell.S = src.cell.S
+		}
+		isBlank := src.cell.V == "" && src.cell.F == nil && src.cell.IS == nil
+		if mode&(PasteValues|PasteFormulas) == 0 || (isBlank && mode&PasteSkipBlanks != 0) {
+			continue
+		}
+		hasFormula := cell.F != nil
+		if mode&(PasteAdd|PasteSubtract|PasteMultiply|PasteDivide) == 0 || !pasteOperation(cell, src.cell, mode) {
+			cell.T, cell.V, cell.XMLSpace, cell.F = src.cell.T, src.cell.V, src.cell.XMLSpace, nil
+			cell.IS = deepcopy.Copy(src.cell.IS).(*xlsxSI)
+			if mode&PasteFormulas != 0 && src.formula != "" {
+				cell.F = &xlsxF{Content: adjust(src.formula, coordinates[0]+src.col, coordinates[1]+src.row, col, row)}
+			}
+		}
+		if hasFormula && cell.F == nil {
+			f.deleteCalcChain(sheetID, cell.R)
+		}
+	}
+	return err
+}
+
+// TransposeRange provides a function to write the transposed values of the
+// cells in the range to the location starting from the given cell by given
+// worksheet name, the source range, the target cell and whether to copy the
+// styles of the cells. The relative references in the formulas to the cells
+// in the source range will point to the transposed cells, and the other
+// relative references will be shifted with the cells. For example, transpose
+// the range A1:C3 in Sheet1 to the cell E1 with the styles:
+//
+//    err := f.TransposeRange("Sheet1", "A1:C3", "E1", true)
+//
+func (f *File) TransposeRange(sheet, srcRange, dstCell string, withStyles bool) error {
+	rng := strings.Split(strings.Replace(srcRange, "$", "", -1), ":")
+	coordinates, err := areaRangeToCoordinates(rng[0], rng[len(rng)-1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	dstCol, dstRow, err := CellNameToCoordinates(strings.Replace(dstCell, "$", "", -1))
+	if err != nil {
+		return err
+	}
+	if dstCol+coordinates[3]-coordinates[1] > TotalColumns {
+		return ErrColumnNumber
+	}
+	if dstRow+coordinates[2]-coordinates[0] > TotalRows {
+		return ErrMaxRows
+	}
+	mode := PasteFormulas | PasteTranspose
+	if withStyles {
+		mode |= PasteFormats
+	}
+	return f.pasteCells(sheet, sheet, coordinates, dstCol, dstRow, mode, func(formula string, srcCol, srcRow, col, row int) string {
+		return transposeFormulaCellRefs(formula, coordinates, dstCol, dstRow, col-srcCol, row-srcRow)
+	})
+}
+
+// transposeFormulaCellRefs provides a function to adjust the cell references
+// in the formula of the transposed cell by given coordinates of the source
+// range, the coordinates of the target cell and the columns and rows offset
+// of the cell. The relative references to the cells in the source range will
+// be replaced with the references to the transposed cells, and the other
+// relative references will be shifted by the offset.
+func transposeFormulaCellRefs(formula string, coordinates []int, dstCol, dstRow, cols, rows int) string {
+	return adjustFormulaCellRefs(formula, func(col, row int, absCol, absRow bool) string {
+		if !absCol && !absRow && col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+			return formatFormulaCellRef(dstCol+row-coordinates[1], dstRow+col-coordinates[0], absCol, absRow)
+		}
+		if !absCol {
+			col += cols
+		}
+		if !absRow {
+			row += rows
+		}
+		return formatFormulaCellRef(col, row, absCol, absRow)
+	})
+}
+
+// splitSheetRef provides a function to split the reference with the
+// worksheet name, such as 'Sheet 1'!A1:B2, into the unquoted worksheet name
+// and the reference.
+func splitSheetRef(ref string) (string, string, error) {
+	idx := strings.LastIndex(ref, "!")
+	if idx < 1 {
+		return "", "", ErrParameterInvalid
+	}
+	sheet := ref[:idx]
+	if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") && len(sheet) > 1 {
+		sheet = strings.Replace(sheet[1:len(sheet)-1], "''", "'", -1)
+	}
+	return sheet, strings.Replace(ref[idx+1:], "$", "", -1), nil
+}
+
+// getPasteCells provides a function to get the copies of the cells in the
+// range of the worksheet by given worksheet name and the coordinates of the
+// range. The formulas of the cells in the shared formulas will be expanded.
+func (f *File) getPasteCells(sheet string, coordinates []int) ([]pasteCell, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	var cells []pasteCell
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cells = append(cells, pasteCell{col: col - coordinates[0], row: row - coordinates[1]})
+		}
+	}
+	width := coordinates[2] - coordinates[0] + 1
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil || col < coordinates[0] || col > coordinates[2] || row < coordinates[1] || row > coordinates[3] {
+				continue
+			}
+			cell := &cells[(row-coordinates[1])*width+col-coordinates[0]]
+			cell.cell = *deepcopy.Copy(&c).(*xlsxC)
+			if c.F != nil {
+				cell.formula = c.F.Content
+				if c.F.T == STCellFormulaTypeShared {
+					cell.formula = getSharedFormulaContent(ws, c.F.Si, col, row)
+				}
+			}
+		}
+	}
+	return cells, err
+}
+
+// getSharedFormulaContent provides a function to get the formula of the cell
+// in the shared formula by given worksheet, the index of the shared formula
+// and the coordinates of the cell.
+func getSharedFormulaContent(ws *xlsxWorksheet, si string, col, row int) string {
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si == si {
+				masterCol, masterRow, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return ""
+				}
+				return shiftFormulaCellRefs(c.F.Content, col-masterCol, row-masterRow)
+			}
+		}
+	}
+	return ""
+}
+
+// pasteOperation provides a function to apply the operation of the paste
+// mode on the numeric values of the target cell and the source cell, and
+// returns false if the value of the source cell isn't a number. The blank
+// cells will be treated as zero.
+func pasteOperation(cell *xlsxC, src xlsxC, mode PasteMode) bool {
+	getNumber := func(c *xlsxC) (float64, bool) {
+		if c.T != "" && c.T != "n" {
+			return 0, false
+		}
+		if c.V == "" {
+			return 0, c.IS == nil
+		}
+		n, err := strconv.ParseFloat(c.V, 64)
+		return n, err == nil
+	}
+	value, ok := getNumber(&src)
+	if !ok {
+		return false
+	}
+	result, ok := getNumber(cell)
+	if !ok {
+		return true
+	}
+	switch {
+	case mode&PasteAdd != 0:
+		result += value
+	case mode&PasteSubtract != 0:
+		result -= value
+	case mode&PasteMultiply != 0:
+		result *= value
+	case value == 0:
+		cell.T, cell.V, cell.F = "e", formulaErrorDIV, nil
+		return true
+	default:
+		result /= value
+	}
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		cell.T, cell.V, cell.F = "e", formulaErrorNUM, nil
+		return true
+	}
+	cell.T, cell.V, cell.F, cell.IS = "", strconv.FormatFloat(result, 'f', -1, 64), nil, nil
+	return true
+}
+
+// shiftFormulaCellRefs provides a function to shift the relative cell
+// references in the formula by given columns and rows offset, the references
+// which are out of the worksheet will be replaced with #REF!.
+func shiftFormulaCellRefs(formula string, cols, rows int) string {
+	if cols == 0 && rows == 0 {
+		return formula
+	}
+	return adjustFormulaCellRefs(formula, func(col, row int, absCol, absRow bool) string {
+		if !absCol {
+			col += cols
+		}
+		if !absRow {
+			row += rows
+		}
+		return formatFormulaCellRef(col, row, absCol, absRow)
+	})
+}
+
+// formatFormulaCellRef provides a function to get the cell reference in the
+// formula by given coordinates and whether the column and row are absolute,
+// #REF! will be returned if the reference is out of the worksheet.
+func formatFormulaCellRef(col, row int, absCol, absRow bool) string {
+	if col < 1 || row < 1 || col > TotalColumns || row > TotalRows {
+		return formulaErrorREF
+	}
+	colName, _ := ColumnNumberToName(col)
+	var ref strings.Builder
+	if absCol {
+		ref.WriteString("$")
+	}
+	ref.WriteString(colName)
+	if absRow {
+		ref.WriteString("$")
+	}
+	ref.WriteString(strconv.Itoa(row))
+	return ref.String()
+}
+
+// adjustFormulaCellRefs provides a function to rewrite the cell references in
+// the formula by given function, the string literals, quoted worksheet names
+// and structured references in the formula will be kept.
+func adjustFormulaCellRefs(formula string, fn func(col, row int, absCol, absRow bool) string) string {
+	var (
+		result strings.Builder
+		start  int
+	)
+	adjust := func(segment string) {
+		last := 0
+		for _, loc := range formulaCellRefRegexp.FindAllStringIndex(segment, -1) {
+			if isFormulaNameChar(segment, loc[0]-1) || isFormulaNameChar(segment, loc[1]) ||
+				(loc[1] < len(segment) && strings.ContainsRune("(![", rune(segment[loc[1]]))) {
+				continue
+			}
+			ref := segment[loc[0]:loc[1]]
+			absCol := strings.HasPrefix(ref, "$")
+			ref = strings.TrimPrefix(ref, "$")
+			idx := strings.IndexAny(ref, "$0123456789")
+			absRow := ref[idx] == '$'
+			col, err := ColumnNameToNumber(ref[:idx])
+			if err != nil {
+				continue
+			}
+			row, err := strconv.Atoi(strings.TrimPrefix(ref[idx:], "$"))
+			if err != nil || row < 1 || row > TotalRows {
+				continue
+			}
+			result.WriteString(segment[last:loc[0]])
+			result.WriteString(fn(col, row, absCol, absRow))
+			last = loc[1]
+		}
+		result.WriteString(segment[last:])
+	}
+	for i := 0; i < len(formula); i++ {
+		end := i
+		switch formula[i] {
+		case '"', '\'':
+			for end++; end < len(formula); end++ {
+				if formula[end] == formula[i] {
+					if end+1 < len(formula) && formula[end+1] == formula[i] {
+						end++
+						continue
+					}
+					break
+				}
+			}
+		case '[':
+			for depth := 0; end < len(formula); end++ {
+				if formula[end] == '[' {
+					depth++
+				}
+				if formula[end] == ']' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+		default:
+			continue
+		}
+		if end >= len(formula) {
+			end = len(formula) - 1
+		}
+		adjust(formula[start:i])
+		result.WriteString(formula[i : end+1])
+		start, i = end+1, end
+	}
+	adjust(formula[start:])
+	return result.String()
+}
+
+// isFormulaNameChar provides a function to check if the character at the
+// given index of the formula is a part of the name, such as a function name,
+// a defined name or a worksheet name.
+func isFormulaNameChar(formula string, idx int) bool {
+	if idx < 0 || idx >= len(formula) {
+		return false
+	}
+	c := formula[idx]
+	return c == '_' || c == '.' || c == '$' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= 0x80
+}
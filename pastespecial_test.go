@@ -0,0 +1,199 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasteSpecial(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet 2")
+	for idx, row := range [][]interface{}{{"Item", 10, 20}, {"Cost", 3, 4}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "SUM(B1:C1)*$B$2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D1", 90))
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", style))
+
+	// Test paste the formulas and formats.
+	assert.NoError(t, f.PasteSpecial("Sheet1!A1:D2", "'Sheet 2'!B3", PasteAll))
+	formula, err := f.GetCellFormula("Sheet 2", "E3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(C3:D3)*$B$2", formula)
+	value, err := f.GetCellValue("Sheet 2", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", value)
+	styleID, err := f.GetCellStyle("Sheet 2", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+
+	// Test paste the values only.
+	assert.NoError(t, f.PasteSpecial("Sheet1!$A$1:$D$2", "Sheet1!A5", PasteValues))
+	formula, err = f.GetCellFormula("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, "", formula)
+	value, err = f.GetCellValue("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, "90", value)
+	styleID, err = f.GetCellStyle("Sheet1", "A5")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+
+	// Test paste the transposed formats and formulas.
+	assert.NoError(t, f.PasteSpecial("Sheet1!A1:D2", "Sheet1!F1", PasteFormulas|PasteFormats|PasteTranspose))
+	for cell, expected := range map[string]string{"F1": "Item", "G1": "Cost", "F2": "10", "G3": "4"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err = f.GetCellFormula("Sheet1", "F4")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(D4:E4)*$B$2", formula)
+	styleID, err = f.GetCellStyle("Sheet1", "G1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+
+	// Test paste with the operations.
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A10", &[]interface{}{"Text", 1, 2, 3}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D10", "A1"))
+	assert.NoError(t, f.PasteSpecial("Sheet1!A1:D2", "Sheet1!A10", PasteValues|PasteAdd))
+	for cell, expected := range map[string]string{"A10": "Item", "B10": "11", "C10": "22", "D10": "93", "B11": "3"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err = f.GetCellFormula("Sheet1", "D10")
+	assert.NoError(t, err)
+	assert.Equal(t, "", formula)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B12", &[]interface{}{8, 9}))
+	assert.NoError(t, f.PasteSpecial("Sheet1!B2:C2", "Sheet1!B12", PasteValues|PasteMultiply))
+	value, err = f.GetCellValue("Sheet1", "C12")
+	assert.NoError(t, err)
+	assert.Equal(t, "36", value)
+	assert.NoError(t, f.PasteSpecial("Sheet1!B2:C2", "Sheet1!B12", PasteValues|PasteSubtract))
+	value, err = f.GetCellValue("Sheet1", "C12")
+	assert.NoError(t, err)
+	assert.Equal(t, "32", value)
+	assert.NoError(t, f.PasteSpecial("Sheet1!B2:C2", "Sheet1!B12", PasteValues|PasteDivide))
+	value, err = f.GetCellValue("Sheet1", "C12")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", value)
+	// Test divide by the blank cells.
+	assert.NoError(t, f.PasteSpecial("Sheet1!Z1:AA1", "Sheet1!B12", PasteValues|PasteDivide))
+	value, err = f.GetCellValue("Sheet1", "C12")
+	assert.NoError(t, err)
+	assert.Equal(t, "#DIV/0!", value)
+
+	// Test paste with skip blanks.
+	assert.NoError(t, f.SetCellValue("Sheet1", "B20", "Keep"))
+	assert.NoError(t, f.PasteSpecial("Sheet1!Z1", "Sheet1!B20", PasteValues|PasteSkipBlanks))
+	value, err = f.GetCellValue("Sheet1", "B20")
+	assert.NoError(t, err)
+	assert.Equal(t, "Keep", value)
+	assert.NoError(t, f.PasteSpecial("Sheet1!Z1", "Sheet1!B20", PasteValues))
+	value, err = f.GetCellValue("Sheet1", "B20")
+	assert.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	// Test paste the shared formulas and the overlapped ranges.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for i, cell := range []string{"B30", "B31"} {
+		c, _, _, err := f.prepareCell(ws, "Sheet1", cell)
+		assert.NoError(t, err)
+		c.F = &xlsxF{T: STCellFormulaTypeShared, Si: "0"}
+		if i == 0 {
+			c.F.Content, c.F.Ref = "A30*2", "B30:B31"
+		}
+	}
+	assert.NoError(t, f.PasteSpecial("Sheet1!B30:B31", "Sheet1!B31", 0))
+	for cell, expected := range map[string]string{"B31": "A31*2", "B32": "A32*2"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPasteSpecial.xlsx")))
+
+	// Test paste special with invalid parameters.
+	assert.EqualError(t, f.PasteSpecial("A1:B2", "Sheet1!A1", PasteAll), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "A1", PasteAll), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "Sheet1!A1", PasteAdd|PasteDivide), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A:B2", "Sheet1!A1", PasteAll), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "Sheet1!A", PasteAll), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "Sheet1!XFD1", PasteAll), ErrColumnNumber.Error())
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "Sheet1!A1048576", PasteAll), ErrMaxRows.Error())
+	assert.EqualError(t, f.PasteSpecial("SheetN!A1:B2", "Sheet1!A1", PasteAll), "sheet SheetN is not exist")
+	assert.EqualError(t, f.PasteSpecial("Sheet1!A1:B2", "SheetN!A1", PasteAll), "sheet SheetN is not exist")
+}
+
+func TestShiftFormulaCellRefs(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"A1+$B1+C$1+$D$1":                  "C2+$B2+E$1+$D$1",
+		`"A1"&'A1 Sheet'!A1&Sheet1!B2`:     `"A1"&'A1 Sheet'!C2&Sheet1!D3`,
+		`"say ""A1"""&A1`:                  `"say ""A1"""&C2`,
+		"LOG10(A1)+ATAN2(A1,B1)":           "LOG10(C2)+ATAN2(C2,D2)",
+		"SUM(Table1[[#This Row],[A1]])+A1": "SUM(Table1[[#This Row],[A1]])+C2",
+		"XFE1+AB1C+A1048577+_A1+A1.B":      "XFE1+AB1C+A1048577+_A1+A1.B",
+		"SUM(A1:B2)":                       "SUM(C2:D3)",
+		`"unterminated A1`:                 `"unterminated A1`,
+	} {
+		assert.Equal(t, expected, shiftFormulaCellRefs(formula, 2, 1), formula)
+	}
+	assert.Equal(t, "#REF!+$A$1", shiftFormulaCellRefs("A1+$A$1", -1, 0))
+	assert.Equal(t, "#REF!", shiftFormulaCellRefs("A1", 0, -1))
+	assert.Equal(t, "A1", shiftFormulaCellRefs("A1", 0, 0))
+}
+
+func TestTransposeRange(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"Item", 10, 20}, {"Cost", 3}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "B1*B2+$A$1+E5"))
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", style))
+
+	assert.NoError(t, f.TransposeRange("Sheet1", "$A$1:C2", "E1", true))
+	for cell, expected := range map[string]string{"E1": "Item", "E2": "10", "E3": "20", "F1": "Cost", "F2": "3"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "E2*F2+$A$1+H6", formula)
+	styleID, err := f.GetCellStyle("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+
+	// Test transpose range without styles.
+	assert.NoError(t, f.TransposeRange("Sheet1", "A1:C2", "A5", false))
+	styleID, err = f.GetCellStyle("Sheet1", "B5")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+	formula, err = f.GetCellFormula("Sheet1", "B7")
+	assert.NoError(t, err)
+	assert.Equal(t, "A6*B6+$A$1+D10", formula)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestTransposeRange.xlsx")))
+
+	// Test transpose range with invalid parameters.
+	assert.EqualError(t, f.TransposeRange("Sheet1", "A:C2", "E1", true), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.TransposeRange("Sheet1", "A1:C2", "E0", true), `cannot convert cell "E0" to coordinates: invalid cell name "E0"`)
+	assert.EqualError(t, f.TransposeRange("Sheet1", "A1:A3", "XFD1", true), ErrColumnNumber.Error())
+	assert.EqualError(t, f.TransposeRange("Sheet1", "A1:C1", "A1048576", true), ErrMaxRows.Error())
+	assert.EqualError(t, f.TransposeRange("SheetN", "A1:C2", "E1", true), "sheet SheetN is not exist")
+}
+
+func TestTransposeFormulaCellRefs(t *testing.T) {
+	coordinates := []int{1, 1, 3, 2}
+	assert.Equal(t, "E1+E3+$C$1+G$1+#REF!", transposeFormulaCellRefs("A1+C1+$C$1+C$1+A1048576", coordinates, 5, 1, 4, 1))
+}
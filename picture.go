@@ -13,10 +13,14 @@ package excelize
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"os"
@@ -24,6 +28,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"golang.org/x/image/draw"
 )
 
 // parseFormatPictureSet provides a function to parse the format settings of
@@ -40,6 +46,12 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 		YScale:           1.0,
 	}
 	err := json.Unmarshal(parseFormatSet(formatSet), &format)
+	if err == nil && inStrSlice([]string{"", "twoCell", "oneCell", "absolute"}, format.Anchor) == -1 {
+		err = ErrPictureAnchor
+	}
+	if err == nil && (format.JPEGQuality < 0 || format.JPEGQuality > 100) {
+		err = ErrPictureJPEGQuality
+	}
 	return &format, err
 }
 
@@ -84,6 +96,35 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 // spreadsheet, "oneCell" (Move but don't size with cells) or "absolute"
 // (Don't move or size with cells). If you don't set this parameter, default
 // positioning is move and size with cells.
+//
+// The offsets and size of the picture could be specified in EMUs (English
+// Metric Units, 9525 EMUs per pixel) for pixel-perfect positioning, the
+// "x_offset_emu" and "y_offset_emu" will be added to the "x_offset" and
+// "y_offset" in pixels, and the "width_emu" and "height_emu" will override the
+// scaled size of the picture. Set "to_cell" with the optional
+// "to_x_offset_emu" and "to_y_offset_emu" to specify the end cell and offsets
+// of the two cell anchor, for example:
+//
+//    err := f.AddPicture("Sheet1", "B2", "image.png", `{"x_offset_emu": 4762, "to_cell": "D10", "to_x_offset_emu": 95250, "to_y_offset_emu": 47625}`)
+//
+// The PNG and JPEG pictures could be downscaled and compressed before
+// embedding to reduce the size of the workbook. Set "max_width" and
+// "max_height" in pixels to downscale the picture which exceeds the maximum
+// dimensions with the aspect ratio preserved, and the picture will be
+// displayed in the downscaled size. Set "jpeg_quality" (1-100) to re-encode
+// the JPEG picture with the given quality, and set "convert_to_jpeg" to
+// convert the PNG picture to JPEG, the transparent pixels will be filled with
+// white. The original picture will be used if it's not downscaled and the
+// processed picture is not smaller. For example, downscale a photo to
+// 1024x1024 pixels at most with quality 80:
+//
+//    err := f.AddPicture("Sheet1", "A2", "photo.jpg", `{"max_width": 1024, "max_height": 1024, "jpeg_quality": 80}`)
+//
+// Anchor defines three types of the anchor of a picture, "twoCell" (anchored
+// by the start and end cells), "oneCell" (anchored by the start cell with
+// absolute size) or "absolute" (anchored by the absolute position and size).
+// If you don't set this parameter, default anchor is "twoCell". Use
+// GetPictureAnchors to read back the exact anchors of the pictures.
 func (f *File) AddPicture(sheet, cell, picture, format string) error {
 	var err error
 	// Check picture exists first.
@@ -139,6 +180,9 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	if err != nil {
 		return err
 	}
+	if file, ext, err = compressPicture(file, ext, formatSet); err != nil {
+		return err
+	}
 	img, _, err := image.DecodeConfig(bytes.NewReader(file))
 	if err != nil {
 		return err
@@ -148,6 +192,9 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	// Add first picture for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
@@ -171,6 +218,72 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	return err
 }
 
+// compressPicture provides a function to downscale and compress the PNG and
+// JPEG picture by given picture file bytes, extension name and format set,
+// and returns the processed picture and extension name. The original picture
+// will be returned if no processing is required, or it's not downscaled and
+// the processed picture is not smaller.
+func compressPicture(file []byte, ext string, formatSet *formatPicture) ([]byte, string, error) {
+	if (ext != ".png" && ext != ".jpeg") || (formatSet.MaxWidth <= 0 && formatSet.MaxHeight <= 0 &&
+		(formatSet.JPEGQuality == 0 || ext != ".jpeg") && (!formatSet.ConvertToJPEG || ext != ".png")) {
+		return file, ext, nil
+	}
+	var (
+		img image.Image
+		err error
+	)
+	if ext == ".png" {
+		img, err = png.Decode(bytes.NewReader(file))
+	} else {
+		img, err = jpeg.Decode(bytes.NewReader(file))
+	}
+	if err != nil {
+		return file, ext, err
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if formatSet.MaxWidth > 0 && width > formatSet.MaxWidth {
+		width, height = formatSet.MaxWidth, int(float64(height)*float64(formatSet.MaxWidth)/float64(width)+0.5)
+	}
+	if formatSet.MaxHeight > 0 && height > formatSet.MaxHeight {
+		width, height = int(float64(width)*float64(formatSet.MaxHeight)/float64(height)+0.5), formatSet.MaxHeight
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	newExt := ext
+	if formatSet.ConvertToJPEG {
+		newExt = ".jpeg"
+	}
+	var dst draw.Image = image.NewRGBA(image.Rect(0, 0, width, height))
+	if newExt == ".jpeg" {
+		// Fill the transparent pixels with white for the JPEG picture.
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+	if width != bounds.Dx() || height != bounds.Dy() {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	} else {
+		draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+	}
+	var buf bytes.Buffer
+	if newExt == ".jpeg" {
+		quality := formatSet.JPEGQuality
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, dst)
+	}
+	if err != nil || (buf.Len() >= len(file) && width == bounds.Dx() && height == bounds.Dy()) {
+		return file, ext, err
+	}
+	return buf.Bytes(), newExt, err
+}
+
 // deleteSheetRelationships provides a function to delete relationships in
 // xl/worksheets/_rels/sheet%d.xml.rels by given worksheet name and
 // relationship index.
@@ -262,23 +375,39 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	}
 	col--
 	row--
-	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
-		f.positionObjectPixels(sheet, col, row, formatSet.OffsetX, formatSet.OffsetY, width, height)
+	cx, cy := width*EMU, height*EMU
+	if formatSet.WidthEMU > 0 {
+		cx = formatSet.WidthEMU
+	}
+	if formatSet.HeightEMU > 0 {
+		cy = formatSet.HeightEMU
+	}
+	colStart, rowStart, x1, y1, colEnd, rowEnd, x2, y2 := f.positionObjectEMUs(sheet, col, row,
+		formatSet.OffsetX*EMU+formatSet.OffsetXEMU, formatSet.OffsetY*EMU+formatSet.OffsetYEMU, cx, cy)
+	if formatSet.ToCell != "" {
+		if colEnd, rowEnd, err = CellNameToCoordinates(formatSet.ToCell); err != nil {
+			return err
+		}
+		colEnd, rowEnd, x2, y2 = colEnd-1, rowEnd-1, formatSet.ToOffsetXEMU, formatSet.ToOffsetYEMU
+	}
+	x, y, cx, cy := f.anchorSizeEMUs(sheet, colStart, rowStart, x1, y1, colEnd, rowEnd, x2, y2)
+	if cx <= 0 || cy <= 0 {
+		return ErrPictureAnchorRange
+	}
 	content, cNvPrID := f.drawingParser(drawingXML)
 	twoCellAnchor := xdrCellAnchor{}
-	twoCellAnchor.EditAs = formatSet.Positioning
-	from := xlsxFrom{}
-	from.Col = colStart
-	from.ColOff = formatSet.OffsetX * EMU
-	from.Row = rowStart
-	from.RowOff = formatSet.OffsetY * EMU
-	to := xlsxTo{}
-	to.Col = colEnd
-	to.ColOff = x2 * EMU
-	to.Row = rowEnd
-	to.RowOff = y2 * EMU
-	twoCellAnchor.From = &from
-	twoCellAnchor.To = &to
+	switch formatSet.Anchor {
+	case "oneCell":
+		twoCellAnchor.From = &xlsxFrom{Col: colStart, ColOff: x1, Row: rowStart, RowOff: y1}
+		twoCellAnchor.Ext = &xlsxExt{Cx: cx, Cy: cy}
+	case "absolute":
+		twoCellAnchor.Pos = &xlsxPoint2D{X: x, Y: y}
+		twoCellAnchor.Ext = &xlsxExt{Cx: cx, Cy: cy}
+	default:
+		twoCellAnchor.EditAs = formatSet.Positioning
+		twoCellAnchor.From = &xlsxFrom{Col: colStart, ColOff: x1, Row: rowStart, RowOff: y1}
+		twoCellAnchor.To = &xlsxTo{Col: colEnd, ColOff: x2, Row: rowEnd, RowOff: y2}
+	}
 	pic := xlsxPic{}
 	pic.NvPicPr.CNvPicPr.PicLocks.NoChangeAspect = formatSet.NoChangeAspect
 	pic.NvPicPr.CNvPr.ID = cNvPrID
@@ -292,6 +421,7 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	}
 	pic.BlipFill.Blip.R = SourceRelationship.Value
 	pic.BlipFill.Blip.Embed = "rId" + strconv.Itoa(rID)
+	pic.SpPr.Xfrm.Ext = xlsxExt{Cx: cx, Cy: cy}
 	pic.SpPr.PrstGeom.Prst = "rect"
 
 	twoCellAnchor.Pic = &pic
@@ -301,7 +431,14 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	}
 	content.Lock()
 	defer content.Unlock()
-	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
+	switch formatSet.Anchor {
+	case "oneCell":
+		content.OneCellAnchor = append(content.OneCellAnchor, &twoCellAnchor)
+	case "absolute":
+		content.AbsoluteAnchor = append(content.AbsoluteAnchor, &twoCellAnchor)
+	default:
+		content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
+	}
 	f.Drawings.Store(drawingXML, content)
 	return err
 }
@@ -309,37 +446,37 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 // countMedia provides a function to get media files count storage in the
 // folder xl/media/image.
 func (f *File) countMedia() int {
-	count := 0
-	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/media/image") {
-			count++
-		}
-		return true
-	})
-	return count
+	return f.countParts("xl/media/image")
 }
 
 // addMedia provides a function to add a picture into folder xl/media/image by
 // given file and extension name. Duplicate images are only actually stored once
-// and drawings that use it will reference the same image.
+// and drawings that use it will reference the same image, the images are
+// indexed by the SHA-256 hash of the content.
 func (f *File) addMedia(file []byte, ext string) string {
-	count := f.countMedia()
-	var name string
-	f.Pkg.Range(func(k, existing interface{}) bool {
-		if !strings.HasPrefix(k.(string), "xl/media/image") {
+	hash := sha256.Sum256(file)
+	if f.mediaHashes == nil {
+		f.mediaHashes = make(map[[sha256.Size]byte]string)
+		f.Pkg.Range(func(k, existing interface{}) bool {
+			if strings.HasPrefix(k.(string), "xl/media/image") {
+				f.mediaHashes[sha256.Sum256(existing.([]byte))] = k.(string)
+			}
 			return true
+		})
+	}
+	if name, ok := f.mediaHashes[hash]; ok {
+		if existing, ok := f.Pkg.Load(name); ok && bytes.Equal(file, existing.([]byte)) {
+			return name
 		}
-		if bytes.Equal(file, existing.([]byte)) {
-			name = k.(string)
-			return false
-		}
-		return true
-	})
-	if name != "" {
-		return name
 	}
+	count := f.countMedia()
 	media := "xl/media/image" + strconv.Itoa(count+1) + ext
+	for _, ok := f.Pkg.Load(media); ok; _, ok = f.Pkg.Load(media) {
+		count++
+		media = "xl/media/image" + strconv.Itoa(count+1) + ext
+	}
 	f.Pkg.Store(media, file)
+	f.mediaHashes[hash] = media
 	return media
 }
 
@@ -394,24 +531,42 @@ func (f *File) addContentTypePart(index int, contentType string) {
 		"drawings": f.setContentTypePartImageExtensions,
 	}
 	partNames := map[string]string{
-		"chart":         "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
-		"chartsheet":    "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
-		"comments":      "/xl/comments" + strconv.Itoa(index) + ".xml",
-		"drawings":      "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
-		"table":         "/xl/tables/table" + strconv.Itoa(index) + ".xml",
-		"pivotTable":    "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
-		"pivotCache":    "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
-		"sharedStrings": "/xl/sharedStrings.xml",
+		"chart":             "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
+		"chartEx":           "/xl/charts/chartEx" + strconv.Itoa(index) + ".xml",
+		"chartsheet":        "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
+		"comments":          "/xl/comments" + strconv.Itoa(index) + ".xml",
+		"drawings":          "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
+		"table":             "/xl/tables/table" + strconv.Itoa(index) + ".xml",
+		"pivotTable":        "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
+		"pivotCache":        "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"pivotCacheRecords": "/xl/pivotCache/pivotCacheRecords" + strconv.Itoa(index) + ".xml",
+		"timeline":          "/xl/timelines/timeline" + strconv.Itoa(index) + ".xml",
+		"timelineCache":     "/xl/timelineCaches/timelineCache" + strconv.Itoa(index) + ".xml",
+		"sharedStrings":     "/xl/sharedStrings.xml",
+		"metadata":          "/xl/metadata.xml",
+		"styles":            "/xl/styles.xml",
+		"theme":             "/xl/theme/theme1.xml",
+		"docPropsApp":       "/docProps/app.xml",
+		"docPropsCore":      "/docProps/core.xml",
 	}
 	contentTypes := map[string]string{
-		"chart":         ContentTypeDrawingML,
-		"chartsheet":    ContentTypeSpreadSheetMLChartsheet,
-		"comments":      ContentTypeSpreadSheetMLComments,
-		"drawings":      ContentTypeDrawing,
-		"table":         ContentTypeSpreadSheetMLTable,
-		"pivotTable":    ContentTypeSpreadSheetMLPivotTable,
-		"pivotCache":    ContentTypeSpreadSheetMLPivotCacheDefinition,
-		"sharedStrings": ContentTypeSpreadSheetMLSharedStrings,
+		"chart":             ContentTypeDrawingML,
+		"chartEx":           ContentTypeChartEx,
+		"chartsheet":        ContentTypeSpreadSheetMLChartsheet,
+		"comments":          ContentTypeSpreadSheetMLComments,
+		"drawings":          ContentTypeDrawing,
+		"table":             ContentTypeSpreadSheetMLTable,
+		"pivotTable":        ContentTypeSpreadSheetMLPivotTable,
+		"pivotCache":        ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"pivotCacheRecords": ContentTypeSpreadSheetMLPivotCacheRecords,
+		"timeline":          ContentTypeTimeline,
+		"timelineCache":     ContentTypeTimelineCache,
+		"sharedStrings":     ContentTypeSpreadSheetMLSharedStrings,
+		"metadata":          ContentTypeSpreadSheetMLSheetMetadata,
+		"styles":            ContentTypeSpreadSheetMLStyles,
+		"theme":             ContentTypeTheme,
+		"docPropsApp":       ContentTypeExtendedProperties,
+		"docPropsCore":      ContentTypeCoreProperties,
 	}
 	s, ok := setContentType[contentType]
 	if ok {
@@ -497,9 +652,120 @@ func (f *File) GetPicture(sheet, cell string) (string, []byte, error) {
 	return f.getPicture(row, col, drawingXML, drawingRelationships)
 }
 
-// DeletePicture provides a function to delete charts in spreadsheet by given
-// worksheet and cell name. Note that the image file won't be deleted from the
-// document currently.
+// GetPictureAnchors provides a function to get the anchor settings of all
+// pictures in the worksheet by given worksheet name, include the picture
+// name, file name, anchor type, positioning, the start and end cells with
+// offsets, the absolute position and size, the offsets, position and size are
+// in EMUs. For example, get the anchors of the pictures in Sheet1:
+//
+//    anchors, err := f.GetPictureAnchors("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    for _, anchor := range anchors {
+//        fmt.Println(anchor.Name, anchor.FromCell, anchor.FromOffsetX, anchor.Width, anchor.Height)
+//    }
+//
+func (f *File) GetPictureAnchors(sheet string) ([]PictureAnchor, error) {
+	var anchors []PictureAnchor
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.Drawing == nil {
+		return anchors, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+	drawingXML := strings.Replace(target, "..", "xl", -1)
+	if _, ok := f.Pkg.Load(drawingXML); !ok {
+		if _, ok = f.Drawings.Load(drawingXML); !ok {
+			return anchors, err
+		}
+	}
+	drawingRelationships := strings.Replace(
+		strings.Replace(target, "../drawings", "xl/drawings/_rels", -1), ".xml", ".xml.rels", -1)
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	for _, cellAnchors := range []struct {
+		anchor  string
+		anchors []*xdrCellAnchor
+	}{
+		{"absolute", wsDr.AbsoluteAnchor},
+		{"oneCell", wsDr.OneCellAnchor},
+		{"twoCell", wsDr.TwoCellAnchor},
+	} {
+		for _, cellAnchor := range cellAnchors.anchors {
+			deAnchor, err := f.decodeDrawingAnchor(cellAnchor)
+			if err != nil {
+				return anchors, err
+			}
+			if deAnchor.Pic == nil {
+				continue
+			}
+			anchors = append(anchors, f.newPictureAnchor(cellAnchors.anchor, cellAnchor.EditAs, drawingRelationships, deAnchor))
+		}
+	}
+	return anchors, err
+}
+
+// convertCellAnchor provides a function to convert the picture cell anchor
+// in the worksheet drawing to the decoded cell anchor.
+func convertCellAnchor(cellAnchor *xdrCellAnchor) *decodeTwoCellAnchor {
+	deAnchor := &decodeTwoCellAnchor{Pic: &decodePic{}}
+	if cellAnchor.Pos != nil {
+		deAnchor.Pos = &decodeOff{X: cellAnchor.Pos.X, Y: cellAnchor.Pos.Y}
+	}
+	if cellAnchor.From != nil {
+		deAnchor.From = &decodeFrom{Col: cellAnchor.From.Col, ColOff: cellAnchor.From.ColOff, Row: cellAnchor.From.Row, RowOff: cellAnchor.From.RowOff}
+	}
+	if cellAnchor.To != nil {
+		deAnchor.To = &decodeTo{Col: cellAnchor.To.Col, ColOff: cellAnchor.To.ColOff, Row: cellAnchor.To.Row, RowOff: cellAnchor.To.RowOff}
+	}
+	if cellAnchor.Ext != nil {
+		deAnchor.Ext = &decodeExt{Cx: cellAnchor.Ext.Cx, Cy: cellAnchor.Ext.Cy}
+	}
+	deAnchor.Pic.NvPicPr.CNvPr.ID = cellAnchor.Pic.NvPicPr.CNvPr.ID
+	deAnchor.Pic.NvPicPr.CNvPr.Name = cellAnchor.Pic.NvPicPr.CNvPr.Name
+	if cellAnchor.Pic.NvPicPr.CNvPr.HlinkClick != nil {
+		deAnchor.Pic.NvPicPr.CNvPr.HlinkClick = &decodeHlinkClick{RID: cellAnchor.Pic.NvPicPr.CNvPr.HlinkClick.RID}
+	}
+	deAnchor.Pic.BlipFill.Blip.Embed = cellAnchor.Pic.BlipFill.Blip.Embed
+	deAnchor.Pic.SpPr.Xfrm.Ext = decodeExt{Cx: cellAnchor.Pic.SpPr.Xfrm.Ext.Cx, Cy: cellAnchor.Pic.SpPr.Xfrm.Ext.Cy}
+	return deAnchor
+}
+
+// newPictureAnchor provides a function to get the anchor settings of the
+// picture by given anchor type, positioning, drawing relationships and the
+// decoded cell anchor.
+func (f *File) newPictureAnchor(anchor, editAs, drawingRelationships string, deAnchor *decodeTwoCellAnchor) PictureAnchor {
+	pa := PictureAnchor{
+		Name:        deAnchor.Pic.NvPicPr.CNvPr.Name,
+		Anchor:      anchor,
+		Positioning: editAs,
+		Width:       deAnchor.Pic.SpPr.Xfrm.Ext.Cx,
+		Height:      deAnchor.Pic.SpPr.Xfrm.Ext.Cy,
+	}
+	if drawRel := f.getDrawingRelationships(drawingRelationships, deAnchor.Pic.BlipFill.Blip.Embed); drawRel != nil {
+		pa.File = filepath.Base(drawRel.Target)
+	}
+	if deAnchor.Pos != nil {
+		pa.X, pa.Y = deAnchor.Pos.X, deAnchor.Pos.Y
+	}
+	if deAnchor.From != nil {
+		pa.FromCell, _ = CoordinatesToCellName(deAnchor.From.Col+1, deAnchor.From.Row+1)
+		pa.FromOffsetX, pa.FromOffsetY = deAnchor.From.ColOff, deAnchor.From.RowOff
+	}
+	if deAnchor.To != nil {
+		pa.ToCell, _ = CoordinatesToCellName(deAnchor.To.Col+1, deAnchor.To.Row+1)
+		pa.ToOffsetX, pa.ToOffsetY = deAnchor.To.ColOff, deAnchor.To.RowOff
+	}
+	if deAnchor.Ext != nil {
+		pa.Width, pa.Height = deAnchor.Ext.Cx, deAnchor.Ext.Cy
+	}
+	return pa
+}
+
+// DeletePicture provides a function to delete pictures in spreadsheet by
+// given worksheet and cell name. The image file will be deleted from the
+// document when it's not used by any other drawing objects.
 func (f *File) DeletePicture(sheet, cell string) (err error) {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
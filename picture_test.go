@@ -7,7 +7,10 @@ import (
 
 	_ "golang.org/x/image/tiff"
 
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"image"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -166,6 +169,66 @@ func TestAddDrawingPicture(t *testing.T) {
 	assert.EqualError(t, f.addDrawingPicture("sheet1", "", "A", "", 0, 0, 0, 0, nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestPictureAnchors(t *testing.T) {
+	f := NewFile()
+	img := filepath.Join("test", "images", "excel.png")
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "B", 20))
+	// Test add picture with offsets and size in EMUs.
+	assert.NoError(t, f.AddPicture("Sheet1", "B2", img, `{"x_offset": 10, "x_offset_emu": 100, "y_offset_emu": 200, "width_emu": 952500, "height_emu": 476250, "positioning": "oneCell"}`))
+	// Test add picture with the end cell and offsets.
+	assert.NoError(t, f.AddPicture("Sheet1", "D2", img, `{"to_cell": "E4", "to_x_offset_emu": 9525, "to_y_offset_emu": 19050}`))
+	// Test add picture with one cell anchor and absolute anchor.
+	assert.NoError(t, f.AddPicture("Sheet1", "G2", img, `{"anchor": "oneCell", "y_offset": 5, "width_emu": 95250, "height_emu": 95250}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", img, `{"anchor": "absolute", "x_offset": 30, "width_emu": 95250, "height_emu": 190500}`))
+	assert.NoError(t, f.AddChart("Sheet1", "J1", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`))
+	expected := []PictureAnchor{
+		{Name: "Picture 5", File: "image1.png", Anchor: "absolute", X: 285750, Width: 95250, Height: 190500},
+		{Name: "Picture 4", File: "image1.png", Anchor: "oneCell", FromCell: "G2", FromOffsetY: 47625, Width: 95250, Height: 95250},
+		{Name: "Picture 2", File: "image1.png", Anchor: "twoCell", Positioning: "oneCell", FromCell: "B2", FromOffsetX: 95350, FromOffsetY: 200, ToCell: "B4", ToOffsetX: 1047850, ToOffsetY: 95450, Width: 952500, Height: 476250},
+		{Name: "Picture 3", File: "image1.png", Anchor: "twoCell", FromCell: "D2", ToCell: "E4", ToOffsetX: 9525, ToOffsetY: 19050, Width: 619125, Height: 400050},
+	}
+	anchors, err := f.GetPictureAnchors("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, anchors)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPictureAnchors.xlsx")))
+
+	// Test get picture anchors from the saved spreadsheet.
+	f, err = OpenFile(filepath.Join("test", "TestPictureAnchors.xlsx"))
+	assert.NoError(t, err)
+	anchors, err = f.GetPictureAnchors("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, anchors)
+	// Test add picture after the existing anchors loaded.
+	assert.NoError(t, f.AddPicture("Sheet1", "A10", img, `{"anchor": "absolute"}`))
+	anchors, err = f.GetPictureAnchors("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, anchors, 5)
+	assert.Equal(t, "Picture 7", anchors[1].Name)
+
+	// Test add picture with invalid anchor settings.
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, `{"anchor": "center"}`), ErrPictureAnchor.Error())
+	assert.EqualError(t, f.AddPicture("Sheet1", "B2", img, `{"to_cell": "A1"}`), ErrPictureAnchorRange.Error())
+	assert.EqualError(t, f.AddPicture("Sheet1", "B2", img, `{"to_cell": "A"}`), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test get picture anchors on not exists worksheet.
+	_, err = f.GetPictureAnchors("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get picture anchors on the worksheet without drawing.
+	anchors, err = NewFile().GetPictureAnchors("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, anchors)
+	// Test get picture anchors with invalid drawing part.
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", []byte(xml.Header+`<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing"><xdr:oneCellAnchor><xdr:ext cx="x"/></xdr:oneCellAnchor></xdr:wsDr>`))
+	_, err = f.GetPictureAnchors("Sheet1")
+	assert.EqualError(t, err, `xml decode error: strconv.ParseInt: parsing "x": invalid syntax`)
+	// Test get picture anchors with not exists drawing part.
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Delete("xl/drawings/drawing1.xml")
+	anchors, err = f.GetPictureAnchors("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, anchors)
+}
+
 func TestAddPictureFromBytes(t *testing.T) {
 	f := NewFile()
 	imgFile, err := ioutil.ReadFile("logo.png")
@@ -181,6 +244,123 @@ func TestAddPictureFromBytes(t *testing.T) {
 	})
 	assert.Equal(t, 1, imageCount, "Duplicate image should only be stored once.")
 	assert.EqualError(t, f.AddPictureFromBytes("SheetN", fmt.Sprint("A", 1), "", "logo", ".png", imgFile), "sheet SheetN is not exist")
+
+	// Test add duplicate images on multiple worksheets.
+	for i := 2; i <= 5; i++ {
+		sheet := fmt.Sprintf("Sheet%d", i)
+		f.NewSheet(sheet)
+		assert.NoError(t, f.AddPictureFromBytes(sheet, "A1", "", "logo", ".png", imgFile))
+		assert.NoError(t, f.AddPictureFromBytes(sheet, "A10", "", "logo", ".png", imgFile))
+	}
+	imageCount = 0
+	f.Pkg.Range(func(fileName, v interface{}) bool {
+		if strings.Contains(fileName.(string), "media/image") {
+			imageCount++
+		}
+		return true
+	})
+	assert.Equal(t, 1, imageCount)
+	for i := 2; i <= 5; i++ {
+		rels := f.relsReader(fmt.Sprintf("xl/drawings/_rels/drawing%d.xml.rels", i))
+		assert.Len(t, rels.Relationships, 2)
+		for _, rel := range rels.Relationships {
+			assert.Equal(t, "../media/image1.png", rel.Target)
+		}
+	}
+
+	// Test add image after the duplicate image was deleted.
+	assert.NoError(t, f.DeletePicture("Sheet1", "A1"))
+	assert.NoError(t, f.DeletePicture("Sheet1", "A50"))
+	for i := 2; i <= 5; i++ {
+		sheet := fmt.Sprintf("Sheet%d", i)
+		assert.NoError(t, f.DeletePicture(sheet, "A1"))
+		assert.NoError(t, f.DeletePicture(sheet, "A10"))
+	}
+	_, ok := f.Pkg.Load("xl/media/image1.png")
+	assert.False(t, ok)
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "logo", ".png", imgFile))
+	file, ok := f.Pkg.Load("xl/media/image1.png")
+	assert.True(t, ok)
+	assert.Equal(t, imgFile, file)
+
+	// Test add image without overwriting the existing media part.
+	f = NewFile()
+	f.Pkg.Store("xl/media/image2.png", []byte("image"))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "logo", ".png", imgFile))
+	file, ok = f.Pkg.Load("xl/media/image2.png")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("image"), file)
+	file, ok = f.Pkg.Load("xl/media/image3.png")
+	assert.True(t, ok)
+	assert.Equal(t, imgFile, file)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPictureFromBytes.xlsx")))
+}
+
+func TestAddPictureCompression(t *testing.T) {
+	f := NewFile()
+	pngFile, err := ioutil.ReadFile(filepath.Join("test", "images", "chart.png"))
+	assert.NoError(t, err)
+	jpgFile, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	// Test downscale the PNG picture with the maximum dimensions.
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "chart.png"), `{"max_width": 100, "max_height": 100}`))
+	// Test downscale and convert the PNG picture to JPEG.
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "F1", `{"max_width": 200, "convert_to_jpeg": true, "jpeg_quality": 50}`, "chart", ".png", pngFile))
+	// Test re-encode the JPEG picture with the quality.
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "K1", `{"jpeg_quality": 10}`, "excel", ".jpg", jpgFile))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPictureCompression.xlsx")))
+	for cell, c := range map[string]struct {
+		width, height int
+		format        string
+	}{
+		"A1": {100, 64, "png"},
+		"F1": {200, 127, "jpeg"},
+		"K1": {200, 128, "jpeg"},
+	} {
+		file, raw, err := f.GetPicture("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, "."+c.format, filepath.Ext(file), cell)
+		img, format, err := image.DecodeConfig(bytes.NewReader(raw))
+		assert.NoError(t, err)
+		assert.Equal(t, c.format, format, cell)
+		assert.Equal(t, []int{c.width, c.height}, []int{img.Width, img.Height}, cell)
+	}
+	_, raw, err := f.GetPicture("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Less(t, len(raw), len(pngFile))
+	_, raw, err = f.GetPicture("Sheet1", "K1")
+	assert.NoError(t, err)
+	assert.Less(t, len(raw), len(jpgFile))
+
+	// Test keep the original picture which not downscaled and the processed
+	// picture is not smaller.
+	file, ext, err := compressPicture(jpgFile, ".jpeg", &formatPicture{JPEGQuality: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, ".jpeg", ext)
+	assert.Equal(t, jpgFile, file)
+	// Test compress the unsupported picture types and without options.
+	file, ext, err = compressPicture([]byte{}, ".gif", &formatPicture{MaxWidth: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{}, file)
+	assert.Equal(t, ".gif", ext)
+	file, ext, err = compressPicture(pngFile, ".png", &formatPicture{JPEGQuality: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, pngFile, file)
+	assert.Equal(t, ".png", ext)
+	// Test compress the picture with the minimum dimensions.
+	file, _, err = compressPicture(pngFile, ".png", &formatPicture{MaxWidth: 1, MaxHeight: 1})
+	assert.NoError(t, err)
+	img, _, err := image.DecodeConfig(bytes.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, []int{img.Width, img.Height})
+	// Test compress the invalid picture.
+	_, _, err = compressPicture([]byte{}, ".png", &formatPicture{MaxWidth: 10})
+	assert.EqualError(t, err, "unexpected EOF")
+	_, _, err = compressPicture([]byte{}, ".jpeg", &formatPicture{MaxWidth: 10})
+	assert.EqualError(t, err, "unexpected EOF")
+	// Test add picture with invalid JPEG quality.
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A1", `{"jpeg_quality": 101}`, "excel", ".jpg", jpgFile), ErrPictureJPEGQuality.Error())
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A1", `{"max_width": 10}`, "excel", ".jpg", []byte{}), "unexpected EOF")
 }
 
 func TestDeletePicture(t *testing.T) {
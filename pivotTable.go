@@ -12,21 +12,48 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // PivotTableOption directly maps the format settings of the pivot table.
+//
+// PivotTableStyleName specifies the name of the pivot table style, the
+// built-in styles are PivotStyleLight1 - PivotStyleLight28,
+// PivotStyleMedium1 - PivotStyleMedium28 and PivotStyleDark1 -
+// PivotStyleDark28, the custom pivot table styles in the workbook can also
+// be used. The default value is PivotStyleLight16. ShowRowStripes and
+// ShowColStripes specifies the banded rows and columns of the style.
+//
+// HideFieldHeaders specifies whether to hide the field headers (the captions
+// of the row and column fields) in the pivot table. ClassicLayout specifies
+// whether to use the classic (Excel 2003) pivot table layout, which enables
+// dragging of the fields in the grid.
+//
+// Name specifies the name of the pivot table, the default value is
+// "Pivot Table" followed by the sequence number of the pivot table in the
+// workbook.
+//
+// CalculatedFields specifies the calculated fields based on the formulas of
+// the other fields, which could be used in the data fields by the names.
+// CalculatedItems specifies the calculated items of the row, column or
+// filter fields based on the formulas of the other items in the same field.
 type PivotTableOption struct {
 	pivotTableSheetName string
+	Name                string
 	DataRange           string
 	PivotTableRange     string
 	Rows                []PivotTableField
 	Columns             []PivotTableField
 	Data                []PivotTableField
 	Filter              []PivotTableField
+	CalculatedFields    []PivotTableCalculatedField
+	CalculatedItems     []PivotTableCalculatedItem
 	RowGrandTotals      bool
 	ColGrandTotals      bool
 	ShowDrill           bool
@@ -40,6 +67,8 @@ type PivotTableOption struct {
 	ShowRowStripes      bool
 	ShowColStripes      bool
 	ShowLastColumn      bool
+	HideFieldHeaders    bool
+	ClassicLayout       bool
 	PivotTableStyleName string
 }
 
@@ -69,6 +98,26 @@ type PivotTableField struct {
 	DefaultSubtotal bool
 }
 
+// PivotTableCalculatedField directly maps the calculated field of the pivot
+// table. Formula specifies the formula of the calculated field which
+// references the other fields by the names, the names contain spaces should
+// be enclosed in single quotes, for example: 'Unit Price'*Quantity.
+type PivotTableCalculatedField struct {
+	Name    string
+	Formula string
+}
+
+// PivotTableCalculatedItem directly maps the calculated item of the pivot
+// table. Field specifies the name of the row, column or filter field which
+// the calculated item belongs to. Formula specifies the formula of the
+// calculated item which references the other items in the field by the
+// names, for example: East+West.
+type PivotTableCalculatedItem struct {
+	Field   string
+	Name    string
+	Formula string
+}
+
 // AddPivotTable provides the method to add pivot table by given pivot table
 // options. Note that the same fields can not in Columns, Rows and Filter
 // fields at the same time.
@@ -169,6 +218,9 @@ func (f *File) parseFormatPivotTableSet(opt *PivotTableOption) (*xlsxWorksheet,
 		return nil, "", fmt.Errorf("parameter 'PivotTableRange' parsing error: %s", err.Error())
 	}
 	opt.pivotTableSheetName = pivotTableSheetName
+	if !f.isPivotTableStyleName(opt.PivotTableStyleName) {
+		return nil, "", newInvalidStyleAttrError("pivot table style name", opt.PivotTableStyleName)
+	}
 	dataRange := f.getDefinedNameRefTo(opt.DataRange, pivotTableSheetName)
 	if dataRange == "" {
 		dataRange = opt.DataRange
@@ -185,7 +237,69 @@ func (f *File) parseFormatPivotTableSet(opt *PivotTableOption) (*xlsxWorksheet,
 	if !ok {
 		return dataSheet, pivotTableSheetPath, fmt.Errorf("sheet %s is not exist", pivotTableSheetName)
 	}
-	return dataSheet, pivotTableSheetPath, err
+	return dataSheet, pivotTableSheetPath, f.parseFormatPivotCalculations(opt)
+}
+
+// parseFormatPivotCalculations provides a function to validate the
+// calculated fields and calculated items of the pivot table.
+func (f *File) parseFormatPivotCalculations(opt *PivotTableOption) error {
+	order, err := f.getPivotFieldsOrder(opt)
+	if err != nil {
+		return err
+	}
+	for _, field := range opt.CalculatedFields {
+		if field.Name == "" || strings.TrimPrefix(field.Formula, "=") == "" {
+			return ErrParameterRequired
+		}
+		var count int
+		for _, name := range order {
+			if name == field.Name {
+				count++
+			}
+		}
+		if count > 1 {
+			return ErrPivotTableCalculatedFieldName
+		}
+		for _, fields := range [][]PivotTableField{opt.Rows, opt.Columns, opt.Filter} {
+			if inPivotTableField(fields, field.Name) != -1 {
+				return ErrPivotTableCalculatedField
+			}
+		}
+	}
+	for _, item := range opt.CalculatedItems {
+		if item.Name == "" || strings.TrimPrefix(item.Formula, "=") == "" {
+			return ErrParameterRequired
+		}
+		if inPivotTableField(opt.Rows, item.Field) == -1 && inPivotTableField(opt.Columns, item.Field) == -1 &&
+			inPivotTableField(opt.Filter, item.Field) == -1 {
+			return ErrPivotTableCalculatedItem
+		}
+	}
+	return nil
+}
+
+// isPivotTableStyleName provides a function to check if the given name is
+// empty, a built-in pivot table style name or a custom pivot table style in
+// the workbook.
+func (f *File) isPivotTableStyleName(name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, prefix := range []string{"PivotStyleLight", "PivotStyleMedium", "PivotStyleDark"} {
+		if strings.HasPrefix(name, prefix) {
+			if idx, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); err == nil && idx >= 1 && idx <= 28 {
+				return true
+			}
+		}
+	}
+	if styles := f.stylesReader(); styles.TableStyles != nil {
+		for _, style := range styles.TableStyles.TableStyles {
+			if style.Name == name {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // adjustRange adjust range, for example: adjust Sheet1!$E$31:$A$1 to Sheet1!$A$1:$E$31
@@ -238,6 +352,9 @@ func (f *File) getPivotFieldsOrder(opt *PivotTableOption) ([]string, error) {
 		}
 		order = append(order, name)
 	}
+	for _, field := range opt.CalculatedFields {
+		order = append(order, field.Name)
+	}
 	return order, nil
 }
 
@@ -282,19 +399,32 @@ func (f *File) addPivotCache(pivotCacheID int, pivotCacheXML string, opt *PivotT
 		sharedItems := xlsxSharedItems{
 			Count: 0,
 		}
-		s := xlsxString{}
 		if (rowOk && !defaultRowsSubtotal) || (colOk && !defaultColumnsSubtotal) {
-			s = xlsxString{
-				V: "",
-			}
 			sharedItems.Count++
-			sharedItems.S = &s
+			sharedItems.S = []*xlsxString{{V: ""}}
 		}
 
-		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+		cacheField := &xlsxCacheField{
 			Name:        name,
 			SharedItems: &sharedItems,
-		})
+		}
+		if idx := inPivotTableCalculatedField(opt.CalculatedFields, name); idx != -1 {
+			cacheField.Formula = strings.TrimPrefix(opt.CalculatedFields[idx].Formula, "=")
+			cacheField.DatabaseField = boolPtr(false)
+		}
+		for _, item := range opt.CalculatedItems {
+			if item.Field != name {
+				continue
+			}
+			if pc.CalculatedItems == nil {
+				pc.CalculatedItems = &xlsxCalculatedItems{}
+			}
+			pc.CalculatedItems.CalculatedItem = append(pc.CalculatedItems.CalculatedItem, newPivotCalculatedItem(item, len(pc.CacheFields.CacheField), sharedItems.Count))
+			pc.CalculatedItems.Count = len(pc.CalculatedItems.CalculatedItem)
+			sharedItems.S = append(sharedItems.S, &xlsxString{V: item.Name})
+			sharedItems.Count++
+		}
+		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, cacheField)
 	}
 	pc.CacheFields.Count = len(pc.CacheFields.CacheField)
 	pivotCache, err := xml.Marshal(pc)
@@ -320,8 +450,12 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		}
 		return opt.PivotTableStyleName
 	}
+	name := opt.Name
+	if name == "" {
+		name = fmt.Sprintf("Pivot Table%d", pivotTableID)
+	}
 	pt := xlsxPivotTableDefinition{
-		Name:                  fmt.Sprintf("Pivot Table%d", pivotTableID),
+		Name:                  name,
 		CacheID:               cacheID,
 		RowGrandTotals:        &opt.RowGrandTotals,
 		ColGrandTotals:        &opt.ColGrandTotals,
@@ -334,6 +468,7 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		CreatedVersion:        pivotTableVersion,
 		CompactData:           &opt.CompactData,
 		ShowError:             &opt.ShowError,
+		GridDropZones:         opt.ClassicLayout,
 		DataCaption:           "Values",
 		Location: &xlsxLocation{
 			Ref:            hcell + ":" + vcell,
@@ -364,6 +499,13 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		},
 	}
 
+	if opt.HideFieldHeaders {
+		pt.ShowHeaders = boolPtr(false)
+	}
+	if opt.ClassicLayout {
+		pt.CompactData = boolPtr(false)
+	}
+
 	// pivot fields
 	_ = f.addPivotFields(&pt, opt)
 
@@ -584,6 +726,13 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOptio
 			})
 			continue
 		}
+		if inPivotTableCalculatedField(opt.CalculatedFields, name) != -1 {
+			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{
+				DataField:       inPivotTableField(opt.Data, name) != -1,
+				DefaultSubtotal: boolPtr(false),
+			})
+			continue
+		}
 		if inPivotTableField(opt.Data, name) != -1 {
 			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{
 				DataField: true,
@@ -592,9 +741,72 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOptio
 		}
 		pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{})
 	}
+	for idx, name := range order {
+		addPivotCalculatedItems(pt.PivotFields.PivotField[idx], opt.CalculatedItems, name)
+	}
 	return err
 }
 
+// addPivotCalculatedItems provides a function to add the calculated items to
+// the items of the pivot field by given calculated items and the name of the
+// field. The calculated items will be placed before the default subtotal
+// item, and reference the shared items after the existing ones.
+func addPivotCalculatedItems(field *xlsxPivotField, calculatedItems []PivotTableCalculatedItem, name string) {
+	if field.Items == nil {
+		return
+	}
+	var x int
+	for _, item := range field.Items.Item {
+		if item.X != nil && !item.F {
+			x++
+		}
+	}
+	for _, calculatedItem := range calculatedItems {
+		if calculatedItem.Field != name {
+			continue
+		}
+		idx, items := x, field.Items.Item
+		pos := len(items)
+		if pos > 0 && items[pos-1].T == "default" {
+			pos--
+		}
+		field.Items.Item = append(items[:pos:pos], append([]*xlsxItem{{X: &idx, F: true}}, items[pos:]...)...)
+		x++
+	}
+	field.Items.Count = len(field.Items.Item)
+}
+
+// newPivotCalculatedItem provides a function to create the calculated item
+// of the pivot cache by given calculated item options, the index of the cache
+// field and the index of the shared item of the calculated item.
+func newPivotCalculatedItem(item PivotTableCalculatedItem, field, x int) *xlsxCalculatedItem {
+	return &xlsxCalculatedItem{
+		Formula: strings.TrimPrefix(item.Formula, "="),
+		PivotArea: &xlsxPivotArea{
+			CacheIndex:    true,
+			Outline:       boolPtr(false),
+			FieldPosition: intPtr(0),
+			References: &xlsxPivotAreaReferences{
+				Count: 1,
+				Reference: []*xlsxPivotAreaReference{
+					{Field: intPtr(field), Count: 1, X: []*xlsxX{{V: x}}},
+				},
+			},
+		},
+	}
+}
+
+// inPivotTableCalculatedField provides a function to get the index of the
+// calculated field by given name, -1 will be returned if not found.
+func inPivotTableCalculatedField(a []PivotTableCalculatedField, x string) int {
+	for idx, field := range a {
+		if field.Name == x {
+			return idx
+		}
+	}
+	return -1
+}
+
 // countPivotTables provides a function to get drawing files count storage in
 // the folder xl/pivotTables.
 func (f *File) countPivotTables() int {
@@ -708,3 +920,748 @@ func (f *File) addWorkbookPivotCache(RID int) int {
 	})
 	return cacheID
 }
+
+// GetPivotTables provides the method to get all pivot tables in a worksheet
+// by given worksheet name. The returned options contain the data source
+// range, the location, the row, column, data and filter fields, the
+// subtotal settings and the style of each pivot table, which could be used
+// to audit the pivot table layouts or add the same pivot table by
+// AddPivotTable. For example, get the pivot tables in Sheet1:
+//
+//    pivotTables, err := f.GetPivotTables("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, pivotTable := range pivotTables {
+//        fmt.Println(pivotTable.Name, pivotTable.DataRange, pivotTable.PivotTableRange)
+//    }
+//
+func (f *File) GetPivotTables(sheet string) ([]PivotTableOption, error) {
+	var pivotTables []PivotTableOption
+	pivotTableParts, err := f.getPivotTableParts(sheet)
+	if err != nil {
+		return pivotTables, err
+	}
+	for _, pivotTableXML := range pivotTableParts {
+		opt, err := f.getPivotTable(sheet, pivotTableXML)
+		if err != nil {
+			return pivotTables, err
+		}
+		pivotTables = append(pivotTables, opt)
+	}
+	return pivotTables, nil
+}
+
+// getPivotTableParts provides a function to get the paths of the pivot table
+// parts in the package by given worksheet name.
+func (f *File) getPivotTableParts(sheet string) ([]string, error) {
+	var parts []string
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return parts, err
+	}
+	name := f.sheetMap[trimSheetName(sheet)]
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(name, "xl/worksheets/") + ".rels"
+	rels := f.relsReader(sheetRels)
+	if rels == nil {
+		return parts, nil
+	}
+	rels.Lock()
+	defer rels.Unlock()
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipPivotTable {
+			parts = append(parts, getRelsTargetPath(sheetRels, rel.Target))
+		}
+	}
+	return parts, nil
+}
+
+// pivotTableReader provides a function to get the pointer to the structure
+// after deserialization of the pivot table part by given path.
+func (f *File) pivotTableReader(path string) (*xlsxPivotTableDefinition, error) {
+	pt := xlsxPivotTableDefinition{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&pt); err != nil && err != io.EOF {
+		return &pt, err
+	}
+	return &pt, nil
+}
+
+// pivotCacheReader provides a function to get the pointer to the structure
+// after deserialization of the pivot cache definition part by given path.
+func (f *File) pivotCacheReader(path string) (*xlsxPivotCacheDefinition, error) {
+	pc := xlsxPivotCacheDefinition{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&pc); err != nil && err != io.EOF {
+		return &pc, err
+	}
+	return &pc, nil
+}
+
+// getPivotCachePart provides a function to get the path of the pivot cache
+// definition part which is used by the given pivot table part.
+func (f *File) getPivotCachePart(pivotTableXML string) string {
+	pivotTableRels := getPartRelsPath(pivotTableXML)
+	rels := f.relsReader(pivotTableRels)
+	if rels == nil {
+		return ""
+	}
+	rels.Lock()
+	defer rels.Unlock()
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipPivotCache {
+			return getRelsTargetPath(pivotTableRels, rel.Target)
+		}
+	}
+	return ""
+}
+
+// getPivotTable provides a function to get the pivot table options by given
+// worksheet name and the path of the pivot table part.
+func (f *File) getPivotTable(sheet, pivotTableXML string) (PivotTableOption, error) {
+	opt := PivotTableOption{}
+	pt, err := f.pivotTableReader(pivotTableXML)
+	if err != nil {
+		return opt, err
+	}
+	pc := &xlsxPivotCacheDefinition{}
+	if pivotCacheXML := f.getPivotCachePart(pivotTableXML); pivotCacheXML != "" {
+		if pc, err = f.pivotCacheReader(pivotCacheXML); err != nil {
+			return opt, err
+		}
+	}
+	boolValue := func(val *bool, defaultValue bool) bool {
+		if val == nil {
+			return defaultValue
+		}
+		return *val
+	}
+	opt.Name = pt.Name
+	if pc.CacheSource != nil && pc.CacheSource.WorksheetSource != nil {
+		source := pc.CacheSource.WorksheetSource
+		opt.DataRange = source.Name
+		if source.Name == "" {
+			dataSheet := source.Sheet
+			if dataSheet == "" {
+				dataSheet = sheet
+			}
+			opt.DataRange = dataSheet + "!" + getAbsoluteRangeRef(source.Ref)
+		}
+	}
+	if pt.Location != nil {
+		opt.PivotTableRange = sheet + "!" + getAbsoluteRangeRef(pt.Location.Ref)
+	}
+	opt.RowGrandTotals = boolValue(pt.RowGrandTotals, true)
+	opt.ColGrandTotals = boolValue(pt.ColGrandTotals, true)
+	opt.ShowDrill = boolValue(pt.ShowDrill, true)
+	opt.UseAutoFormatting = boolValue(pt.UseAutoFormatting, false)
+	opt.PageOverThenDown = boolValue(pt.PageOverThenDown, false)
+	opt.MergeItem = boolValue(pt.MergeItem, false)
+	opt.CompactData = boolValue(pt.CompactData, true)
+	opt.ShowError = boolValue(pt.ShowError, false)
+	opt.HideFieldHeaders = !boolValue(pt.ShowHeaders, true)
+	opt.ClassicLayout = pt.GridDropZones
+	if pt.PivotTableStyleInfo != nil {
+		opt.PivotTableStyleName = pt.PivotTableStyleInfo.Name
+		opt.ShowRowHeaders = pt.PivotTableStyleInfo.ShowRowHeaders
+		opt.ShowColHeaders = pt.PivotTableStyleInfo.ShowColHeaders
+		opt.ShowRowStripes = pt.PivotTableStyleInfo.ShowRowStripes
+		opt.ShowColStripes = pt.PivotTableStyleInfo.ShowColStripes
+		opt.ShowLastColumn = pt.PivotTableStyleInfo.ShowLastColumn
+	}
+	f.getPivotTableFields(&opt, pt, pc)
+	getPivotTableCalculations(&opt, pc)
+	return opt, nil
+}
+
+// getPivotTableCalculations provides a function to get the calculated fields
+// and calculated items of the pivot table options by given pivot cache
+// definition.
+func getPivotTableCalculations(opt *PivotTableOption, pc *xlsxPivotCacheDefinition) {
+	if pc.CacheFields == nil {
+		return
+	}
+	for _, field := range pc.CacheFields.CacheField {
+		if field.Formula != "" {
+			opt.CalculatedFields = append(opt.CalculatedFields, PivotTableCalculatedField{Name: field.Name, Formula: field.Formula})
+		}
+	}
+	if pc.CalculatedItems == nil {
+		return
+	}
+	for _, item := range pc.CalculatedItems.CalculatedItem {
+		if item.PivotArea == nil || item.PivotArea.References == nil || len(item.PivotArea.References.Reference) == 0 {
+			continue
+		}
+		ref := item.PivotArea.References.Reference[0]
+		if ref.Field == nil || *ref.Field < 0 || *ref.Field >= len(pc.CacheFields.CacheField) || len(ref.X) == 0 {
+			continue
+		}
+		field := pc.CacheFields.CacheField[*ref.Field]
+		opt.CalculatedItems = append(opt.CalculatedItems, PivotTableCalculatedItem{
+			Field:   field.Name,
+			Name:    getPivotSharedItemValue(field.SharedItems, ref.X[0].V),
+			Formula: item.Formula,
+		})
+	}
+}
+
+// getPivotSharedItemValue provides a function to get the value of the shared
+// item in the cache field by given shared items and the index of the item.
+func getPivotSharedItemValue(sharedItems *xlsxSharedItems, x int) string {
+	if sharedItems == nil {
+		return ""
+	}
+	var values []string
+	values = append(values, make([]string, len(sharedItems.M))...)
+	for _, n := range sharedItems.N {
+		values = append(values, strconv.FormatFloat(n.V, 'f', -1, 64))
+	}
+	for _, b := range sharedItems.B {
+		values = append(values, strings.ToUpper(strconv.FormatBool(b.V)))
+	}
+	for _, e := range sharedItems.E {
+		values = append(values, e.V)
+	}
+	for _, str := range sharedItems.S {
+		values = append(values, str.V)
+	}
+	if x < 0 || x >= len(values) {
+		return ""
+	}
+	return values[x]
+}
+
+// getPivotTableFields provides a function to get the row, column, data and
+// filter fields of the pivot table options by given pivot table and pivot
+// cache definitions.
+func (f *File) getPivotTableFields(opt *PivotTableOption, pt *xlsxPivotTableDefinition, pc *xlsxPivotCacheDefinition) {
+	cacheFieldName := func(idx int) string {
+		if pc.CacheFields != nil && idx >= 0 && idx < len(pc.CacheFields.CacheField) {
+			return pc.CacheFields.CacheField[idx].Name
+		}
+		return ""
+	}
+	axisField := func(idx int) PivotTableField {
+		field := PivotTableField{Data: cacheFieldName(idx), DefaultSubtotal: true}
+		if pt.PivotFields != nil && idx < len(pt.PivotFields.PivotField) {
+			pivotField := pt.PivotFields.PivotField[idx]
+			field.Name = pivotField.Name
+			if pivotField.DefaultSubtotal != nil {
+				field.DefaultSubtotal = *pivotField.DefaultSubtotal
+			}
+		}
+		return field
+	}
+	if pt.RowFields != nil {
+		for _, field := range pt.RowFields.Field {
+			if field.X >= 0 {
+				opt.Rows = append(opt.Rows, axisField(field.X))
+			}
+		}
+	}
+	if pt.ColFields != nil {
+		for _, field := range pt.ColFields.Field {
+			if field.X >= 0 {
+				opt.Columns = append(opt.Columns, axisField(field.X))
+			}
+		}
+	}
+	if pt.PageFields != nil {
+		for _, field := range pt.PageFields.PageField {
+			opt.Filter = append(opt.Filter, PivotTableField{Data: cacheFieldName(field.Fld), Name: field.Name})
+		}
+	}
+	if pt.DataFields != nil {
+		for _, field := range pt.DataFields.DataField {
+			subtotal := field.Subtotal
+			if subtotal == "" {
+				subtotal = "sum"
+			}
+			opt.Data = append(opt.Data, PivotTableField{
+				Data:     cacheFieldName(field.Fld),
+				Name:     field.Name,
+				Subtotal: subtotal,
+			})
+		}
+	}
+}
+
+// getAbsoluteRangeRef provides a function to convert the given cell
+// reference or range reference to the absolute reference, for example
+// convert A1:E31 to $A$1:$E$31.
+func getAbsoluteRangeRef(ref string) string {
+	cells := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	for idx, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			return ref
+		}
+		colName, _ := ColumnNumberToName(col)
+		cells[idx] = "$" + colName + "$" + strconv.Itoa(row)
+	}
+	return strings.Join(cells, ":")
+}
+
+// pivotCacheValue directly maps the value of a cell in the data source range
+// of the pivot cache. The type of the value is one of "m" (missing), "n"
+// (number), "b" (boolean), "e" (error) and "s" (string), which is the same
+// as the element name of the shared item in the pivot cache.
+type pivotCacheValue struct {
+	typ string
+	val string
+	num float64
+}
+
+// pivotCacheTypes defined the order of the shared items with different types
+// in the pivot cache, which is the same as the serialization order.
+var pivotCacheTypes = []string{"m", "n", "b", "e", "s"}
+
+// RefreshPivotCache provides the method to rebuild the pivot cache of the
+// pivot table by given worksheet name and pivot table name from the current
+// values in the data source range. The cache fields, shared items and cache
+// records are regenerated, the items of the pivot fields in all pivot tables
+// that share the pivot cache are updated to match them, and the pivot cache
+// is marked to be refreshed on load, so the spreadsheet application shows
+// the pivot tables with up-to-date data when opening the workbook. For
+// example, refresh the pivot table named "Pivot Table1" in Sheet1 after
+// updating the source data:
+//
+//    err := f.RefreshPivotCache("Sheet1", "Pivot Table1")
+//
+func (f *File) RefreshPivotCache(sheet, pivotName string) error {
+	pivotTableParts, err := f.getPivotTableParts(sheet)
+	if err != nil {
+		return err
+	}
+	for _, pivotTableXML := range pivotTableParts {
+		pt, err := f.pivotTableReader(pivotTableXML)
+		if err != nil {
+			return err
+		}
+		if pt.Name == pivotName {
+			return f.refreshPivotCache(sheet, f.getPivotCachePart(pivotTableXML))
+		}
+	}
+	return ErrPivotTableNotExist
+}
+
+// refreshPivotCache provides a function to rebuild the pivot cache
+// definition and records parts by given worksheet name of the pivot table
+// and the path of the pivot cache definition part.
+func (f *File) refreshPivotCache(sheet, pivotCacheXML string) error {
+	pc, err := f.pivotCacheReader(pivotCacheXML)
+	if err != nil {
+		return err
+	}
+	dataSheet, coordinates, err := f.getPivotCacheSource(sheet, pc)
+	if err != nil {
+		return fmt.Errorf("parameter 'DataRange' parsing error: %s", err.Error())
+	}
+	values, err := f.getPivotCacheValues(dataSheet, coordinates)
+	if err != nil {
+		return err
+	}
+	var (
+		oldNames, names  []string
+		calculatedFields []*xlsxCacheField
+		calculatedItems  []PivotTableCalculatedItem
+	)
+	if pc.CacheFields != nil {
+		for _, field := range pc.CacheFields.CacheField {
+			oldNames = append(oldNames, field.Name)
+			if field.Formula != "" {
+				calculatedFields = append(calculatedFields, field)
+			}
+		}
+		opt := PivotTableOption{}
+		getPivotTableCalculations(&opt, pc)
+		calculatedItems = opt.CalculatedItems
+	}
+	records := make([]*xlsxRecord, coordinates[3]-coordinates[1])
+	for row := range records {
+		records[row] = &xlsxRecord{}
+	}
+	pc.CacheFields = &xlsxCacheFields{}
+	orders := make([][]int, coordinates[2]-coordinates[0]+1)
+	for col := range orders {
+		cell, _ := CoordinatesToCellName(coordinates[0]+col, coordinates[1])
+		name, err := f.GetCellValue(dataSheet, cell)
+		if err != nil {
+			return err
+		}
+		column := make([]pivotCacheValue, len(records))
+		for row := range records {
+			column[row] = values[row+1][col]
+		}
+		sharedItems, indexes, order := newPivotCacheSharedItems(column)
+		for row, idx := range indexes {
+			records[row].X = append(records[row].X, &xlsxX{V: idx})
+		}
+		orders[col] = order
+		names = append(names, name)
+		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+			Name:        name,
+			SharedItems: sharedItems,
+		})
+	}
+	for _, field := range calculatedFields {
+		field.SharedItems = &xlsxSharedItems{}
+		names, orders = append(names, field.Name), append(orders, nil)
+		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, field)
+	}
+	pc.CacheFields.Count = len(pc.CacheFields.CacheField)
+	pc.CalculatedItems = nil
+	indexes := map[int][]int{}
+	for _, item := range calculatedItems {
+		idx := inStrSlice(names, item.Field)
+		if idx == -1 {
+			continue
+		}
+		sharedItems := pc.CacheFields.CacheField[idx].SharedItems
+		if pc.CalculatedItems == nil {
+			pc.CalculatedItems = &xlsxCalculatedItems{}
+		}
+		pc.CalculatedItems.CalculatedItem = append(pc.CalculatedItems.CalculatedItem, newPivotCalculatedItem(item, idx, sharedItems.Count))
+		pc.CalculatedItems.Count = len(pc.CalculatedItems.CalculatedItem)
+		indexes[idx] = append(indexes[idx], sharedItems.Count)
+		sharedItems.S = append(sharedItems.S, &xlsxString{V: item.Name})
+		sharedItems.ContainsString, sharedItems.ContainsSemiMixedTypes = nil, nil
+		sharedItems.ContainsMixedTypes = sharedItems.ContainsMixedTypes || len(sharedItems.N)+len(sharedItems.B)+len(sharedItems.E) > 0
+		sharedItems.Count++
+	}
+	pc.SaveData, pc.RefreshOnLoad, pc.RecordCount = true, true, len(records)
+	if err = f.addPivotCacheRecords(pivotCacheXML, pc, records); err != nil {
+		return err
+	}
+	pivotCache, err := xml.Marshal(pc)
+	f.saveFileList(pivotCacheXML, pivotCache)
+	if err != nil {
+		return err
+	}
+	return f.refreshPivotTableFields(pivotCacheXML, oldNames, names, orders, indexes)
+}
+
+// getPivotCacheSource provides a function to get the worksheet name and the
+// coordinates of the data source range of the pivot cache.
+func (f *File) getPivotCacheSource(sheet string, pc *xlsxPivotCacheDefinition) (string, []int, error) {
+	if pc.CacheSource == nil || pc.CacheSource.WorksheetSource == nil {
+		return "", []int{}, ErrParameterRequired
+	}
+	source := pc.CacheSource.WorksheetSource
+	dataSheet := source.Sheet
+	if dataSheet == "" {
+		dataSheet = sheet
+	}
+	dataRange := dataSheet + "!" + source.Ref
+	if source.Name != "" {
+		if dataRange = f.getDefinedNameRefTo(source.Name, sheet); dataRange == "" {
+			return "", []int{}, ErrParameterInvalid
+		}
+	}
+	return f.adjustRange(dataRange)
+}
+
+// getPivotCacheValues provides a function to get the values of the cells in
+// the data source range of the pivot cache by given worksheet name and
+// coordinates of the range.
+func (f *File) getPivotCacheValues(sheet string, coordinates []int) ([][]pivotCacheValue, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]pivotCacheValue, coordinates[3]-coordinates[1]+1)
+	for row := range values {
+		values[row] = make([]pivotCacheValue, coordinates[2]-coordinates[0]+1)
+		for col := range values[row] {
+			values[row][col] = pivotCacheValue{typ: "m"}
+		}
+	}
+	sst := f.sharedStringsReader()
+	ws.Lock()
+	defer ws.Unlock()
+	for _, row := range ws.SheetData.Row {
+		if row.R < coordinates[1] || row.R > coordinates[3] {
+			continue
+		}
+		for _, c := range row.C {
+			col, _, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return values, err
+			}
+			if col >= coordinates[0] && col <= coordinates[2] {
+				values[row.R-coordinates[1]][col-coordinates[0]] = getPivotCacheValue(c, sst)
+			}
+		}
+	}
+	return values, nil
+}
+
+// getPivotCacheValue provides a function to convert the value of the cell to
+// the value of the pivot cache by given cell and shared string table.
+func getPivotCacheValue(c xlsxC, sst *xlsxSST) pivotCacheValue {
+	switch c.T {
+	case "s":
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(sst.SI) {
+			return pivotCacheValue{typ: "s", val: sst.SI[idx].String()}
+		}
+		return pivotCacheValue{typ: "s", val: c.V}
+	case "inlineStr":
+		if c.IS != nil {
+			return pivotCacheValue{typ: "s", val: c.IS.String()}
+		}
+		return pivotCacheValue{typ: "s", val: c.V}
+	case "str":
+		return pivotCacheValue{typ: "s", val: c.V}
+	case "b":
+		return pivotCacheValue{typ: "b", val: c.V}
+	case "e":
+		return pivotCacheValue{typ: "e", val: c.V}
+	}
+	if c.V == "" {
+		return pivotCacheValue{typ: "m"}
+	}
+	num, err := strconv.ParseFloat(c.V, 64)
+	if err != nil {
+		return pivotCacheValue{typ: "s", val: c.V}
+	}
+	return pivotCacheValue{typ: "n", val: strconv.FormatFloat(num, 'f', -1, 64), num: num}
+}
+
+// newPivotCacheSharedItems provides a function to create the shared items of
+// the cache field by given values of the field. It returns the shared items,
+// the index of the shared item for each value and the indexes of the shared
+// items in display order, which sorts the numbers ascending, followed by the
+// text, boolean and error values and the blank values at the end.
+func newPivotCacheSharedItems(values []pivotCacheValue) (*xlsxSharedItems, []int, []int) {
+	groups, positions := map[string][]pivotCacheValue{}, map[pivotCacheValue]int{}
+	for _, value := range values {
+		if _, ok := positions[value]; !ok {
+			positions[value] = len(groups[value.typ])
+			groups[value.typ] = append(groups[value.typ], value)
+		}
+	}
+	var items []pivotCacheValue
+	offsets := map[string]int{}
+	for _, typ := range pivotCacheTypes {
+		offsets[typ] = len(items)
+		items = append(items, groups[typ]...)
+	}
+	indexes := make([]int, len(values))
+	for idx, value := range values {
+		indexes[idx] = offsets[value.typ] + positions[value]
+	}
+	sharedItems := &xlsxSharedItems{Count: len(items)}
+	containsInteger, types := true, 0
+	for _, item := range items {
+		switch item.typ {
+		case "m":
+			sharedItems.M = append(sharedItems.M, &xlsxMissing{})
+		case "n":
+			sharedItems.N = append(sharedItems.N, &xlsxNumber{V: item.num})
+			containsInteger = containsInteger && item.num == float64(int64(item.num))
+			if sharedItems.MinValue == nil || item.num < *sharedItems.MinValue {
+				sharedItems.MinValue = float64Ptr(item.num)
+			}
+			if sharedItems.MaxValue == nil || item.num > *sharedItems.MaxValue {
+				sharedItems.MaxValue = float64Ptr(item.num)
+			}
+		case "b":
+			sharedItems.B = append(sharedItems.B, &xlsxBoolean{V: item.val == "1"})
+		case "e":
+			sharedItems.E = append(sharedItems.E, &xlsxError{V: item.val})
+		case "s":
+			sharedItems.S = append(sharedItems.S, &xlsxString{V: item.val})
+		}
+	}
+	for _, typ := range pivotCacheTypes[1:] {
+		if len(groups[typ]) > 0 {
+			types++
+		}
+	}
+	sharedItems.ContainsBlank = len(sharedItems.M) > 0
+	sharedItems.ContainsNumber = len(sharedItems.N) > 0
+	sharedItems.ContainsInteger = sharedItems.ContainsNumber && containsInteger
+	sharedItems.ContainsMixedTypes = types > 1
+	if len(sharedItems.S) == 0 {
+		sharedItems.ContainsString = boolPtr(false)
+		if !sharedItems.ContainsBlank {
+			sharedItems.ContainsSemiMixedTypes = boolPtr(false)
+		}
+	}
+	rank := map[string]int{"n": 0, "s": 1, "b": 2, "e": 3, "m": 4}
+	order := make([]int, len(items))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := items[order[i]], items[order[j]]
+		if a.typ != b.typ {
+			return rank[a.typ] < rank[b.typ]
+		}
+		if a.typ == "n" {
+			return a.num < b.num
+		}
+		return strings.ToLower(a.val) < strings.ToLower(b.val)
+	})
+	return sharedItems, indexes, order
+}
+
+// addPivotCacheRecords provides a function to save the pivot cache records
+// part by given path of the pivot cache definition part, the pivot cache
+// definition and the records. The relationship to the records part will be
+// created if it does not exist.
+func (f *File) addPivotCacheRecords(pivotCacheXML string, pc *xlsxPivotCacheDefinition, records []*xlsxRecord) error {
+	pivotCacheRels := getPartRelsPath(pivotCacheXML)
+	var pivotCacheRecordsXML string
+	if rels := f.relsReader(pivotCacheRels); rels != nil && pc.RID != "" {
+		rels.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.ID == pc.RID && rel.Type == SourceRelationshipPivotCacheRecords {
+				pivotCacheRecordsXML = getRelsTargetPath(pivotCacheRels, rel.Target)
+			}
+		}
+		rels.Unlock()
+	}
+	if pivotCacheRecordsXML == "" {
+		pivotCacheRecordsID := 1
+		for {
+			if _, ok := f.Pkg.Load("xl/pivotCache/pivotCacheRecords" + strconv.Itoa(pivotCacheRecordsID) + ".xml"); !ok {
+				break
+			}
+			pivotCacheRecordsID++
+		}
+		pivotCacheRecordsXML = "xl/pivotCache/pivotCacheRecords" + strconv.Itoa(pivotCacheRecordsID) + ".xml"
+		rID := f.addRels(pivotCacheRels, SourceRelationshipPivotCacheRecords, "pivotCacheRecords"+strconv.Itoa(pivotCacheRecordsID)+".xml", "")
+		pc.RID = "rId" + strconv.Itoa(rID)
+		f.addContentTypePart(pivotCacheRecordsID, "pivotCacheRecords")
+	}
+	pivotCacheRecords, err := xml.Marshal(xlsxPivotCacheRecords{Count: len(records), R: records})
+	f.saveFileList(pivotCacheRecordsXML, pivotCacheRecords)
+	return err
+}
+
+// refreshPivotTableFields provides a function to update the pivot fields of
+// all pivot tables which use the given pivot cache by given names of the
+// cache fields before and after refreshing and the display order of the
+// shared items in each cache field, and the indexes of the shared items of
+// the calculated items in each cache field. The pivot fields are matched by
+// the name of the cache fields, and the fields which no longer exist in the
+// data source will be removed from the pivot tables.
+func (f *File) refreshPivotTableFields(pivotCacheXML string, oldNames, names []string, orders [][]int, calculatedItems map[int][]int) error {
+	var pivotTableParts []string
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if part := k.(string); strings.HasPrefix(part, "xl/pivotTables/pivotTable") && strings.HasSuffix(part, ".xml") {
+			pivotTableParts = append(pivotTableParts, part)
+		}
+		return true
+	})
+	sort.Strings(pivotTableParts)
+	for _, pivotTableXML := range pivotTableParts {
+		if f.getPivotCachePart(pivotTableXML) != pivotCacheXML {
+			continue
+		}
+		pt, err := f.pivotTableReader(pivotTableXML)
+		if err != nil {
+			return err
+		}
+		updatePivotTableFields(pt, oldNames, names, orders, calculatedItems)
+		pivotTable, err := xml.Marshal(pt)
+		f.saveFileList(pivotTableXML, pivotTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updatePivotTableFields provides a function to update the pivot fields and
+// the references of the row, column, page and data fields in the pivot table
+// definition by given names of the cache fields before and after refreshing,
+// the display order of the shared items in each cache field and the indexes
+// of the shared items of the calculated items in each cache field.
+func updatePivotTableFields(pt *xlsxPivotTableDefinition, oldNames, names []string, orders [][]int, calculatedItems map[int][]int) {
+	var oldFields []*xlsxPivotField
+	if pt.PivotFields != nil {
+		oldFields = pt.PivotFields.PivotField
+	}
+	fieldsMap, used := map[int]int{}, make([]bool, len(oldFields))
+	pt.PivotFields = &xlsxPivotFields{Count: len(names)}
+	for idx, name := range names {
+		field := &xlsxPivotField{}
+		for i, oldName := range oldNames {
+			if i < len(oldFields) && !used[i] && oldName == name {
+				field, fieldsMap[i], used[i] = oldFields[i], idx, true
+				break
+			}
+		}
+		pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, field)
+		if field.Axis == "" && field.Items == nil {
+			continue
+		}
+		var items []*xlsxItem
+		for _, x := range orders[idx] {
+			x := x
+			items = append(items, &xlsxItem{X: &x})
+		}
+		for _, x := range calculatedItems[idx] {
+			x := x
+			items = append(items, &xlsxItem{X: &x, F: true})
+		}
+		if field.DefaultSubtotal == nil || *field.DefaultSubtotal {
+			items = append(items, &xlsxItem{T: "default"})
+		}
+		field.Items = &xlsxItems{Count: len(items), Item: items}
+	}
+	remapFields := func(fields []*xlsxField) []*xlsxField {
+		var remapped []*xlsxField
+		for _, field := range fields {
+			if field.X < 0 {
+				remapped = append(remapped, field)
+				continue
+			}
+			if idx, ok := fieldsMap[field.X]; ok {
+				remapped = append(remapped, &xlsxField{X: idx})
+			}
+		}
+		return remapped
+	}
+	if pt.RowFields != nil {
+		pt.RowFields.Field = remapFields(pt.RowFields.Field)
+		if pt.RowFields.Count = len(pt.RowFields.Field); pt.RowFields.Count == 0 {
+			pt.RowFields = nil
+		}
+	}
+	if pt.ColFields != nil {
+		pt.ColFields.Field = remapFields(pt.ColFields.Field)
+		if pt.ColFields.Count = len(pt.ColFields.Field); pt.ColFields.Count == 0 {
+			pt.ColFields = nil
+		}
+	}
+	if pt.PageFields != nil {
+		var pageFields []*xlsxPageField
+		for _, field := range pt.PageFields.PageField {
+			if idx, ok := fieldsMap[field.Fld]; ok {
+				field.Fld = idx
+				pageFields = append(pageFields, field)
+			}
+		}
+		pt.PageFields.PageField = pageFields
+		if pt.PageFields.Count = len(pageFields); pt.PageFields.Count == 0 {
+			pt.PageFields = nil
+		}
+	}
+	if pt.DataFields != nil {
+		var dataFields []*xlsxDataField
+		for _, field := range pt.DataFields.DataField {
+			if idx, ok := fieldsMap[field.Fld]; ok {
+				field.Fld = idx
+				dataFields = append(dataFields, field)
+			}
+		}
+		pt.DataFields.DataField = dataFields
+		if pt.DataFields.Count = len(dataFields); pt.DataFields.Count == 0 {
+			pt.DataFields = nil
+		}
+	}
+}
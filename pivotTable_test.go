@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"encoding/xml"
 	"fmt"
 	"math/rand"
 	"path/filepath"
@@ -156,6 +157,38 @@ func TestAddPivotTable(t *testing.T) {
 		ShowColHeaders:  true,
 		ShowLastColumn:  true,
 	}))
+	// Test add pivot table with the classic layout, hidden field headers and
+	// the custom style options
+	f.Styles.TableStyles = &xlsxTableStyles{TableStyles: []*xlsxTableStyle{{Name: "CustomPivotStyle", Pivot: 1}}}
+	for idx, styleName := range []string{"PivotStyleDark28", "CustomPivotStyle"} {
+		assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+			DataRange:           "Sheet1!$A$1:$E$31",
+			PivotTableRange:     fmt.Sprintf("Sheet2!$AT$%d:$AZ$%d", idx*40+1, idx*40+35),
+			Rows:                []PivotTableField{{Data: "Month", DefaultSubtotal: true}, {Data: "Year"}},
+			Columns:             []PivotTableField{{Data: "Type"}},
+			Data:                []PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+			CompactData:         true,
+			ShowRowStripes:      true,
+			ShowColStripes:      true,
+			HideFieldHeaders:    true,
+			ClassicLayout:       true,
+			PivotTableStyleName: styleName,
+		}))
+		pivotTable, ok := f.Pkg.Load(fmt.Sprintf("xl/pivotTables/pivotTable%d.xml", idx+10))
+		assert.True(t, ok)
+		assert.Contains(t, string(pivotTable.([]byte)), `showHeaders="false" compact="false" outline="false" compactData="false" gridDropZones="true"`)
+		assert.Contains(t, string(pivotTable.([]byte)), fmt.Sprintf(`<pivotTableStyleInfo name="%s" showRowHeaders="false" showColHeaders="false" showRowStripes="true" showColStripes="true"></pivotTableStyleInfo>`, styleName))
+	}
+	// Test add pivot table with invalid pivot table style name
+	for _, styleName := range []string{"PivotStyleDark29", "PivotStyleLight0", "TableStyleLight1"} {
+		assert.EqualError(t, f.AddPivotTable(&PivotTableOption{
+			DataRange:           "Sheet1!$A$1:$E$31",
+			PivotTableRange:     "Sheet2!$BB$1:$BH$35",
+			Rows:                []PivotTableField{{Data: "Month"}},
+			Data:                []PivotTableField{{Data: "Sales"}},
+			PivotTableStyleName: styleName,
+		}), fmt.Sprintf("invalid pivot table style name %q", styleName))
+	}
 
 	// Test empty pivot table options
 	assert.EqualError(t, f.AddPivotTable(nil), "parameter is required")
@@ -309,3 +342,338 @@ func TestGetPivotTableFieldName(t *testing.T) {
 	f := NewFile()
 	f.getPivotTableFieldName("-", []PivotTableField{})
 }
+
+func TestGetPivotTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for i := 0; i < 30; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", 2017 + i%3, "Meat", i * 100, "East"}))
+	}
+	opt := PivotTableOption{
+		Name:                "Sales Pivot",
+		DataRange:           "Sheet1!$A$1:$E$31",
+		PivotTableRange:     "Sheet1!$G$2:$M$34",
+		Rows:                []PivotTableField{{Data: "Month", Name: "Month", DefaultSubtotal: true}, {Data: "Year"}},
+		Filter:              []PivotTableField{{Data: "Region", Name: "Region"}},
+		Columns:             []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:                []PivotTableField{{Data: "Sales", Subtotal: "average", Name: "Average of Sales"}},
+		RowGrandTotals:      true,
+		ColGrandTotals:      true,
+		ShowDrill:           true,
+		CompactData:         true,
+		ShowRowHeaders:      true,
+		ShowColHeaders:      true,
+		ShowLastColumn:      true,
+		ShowError:           true,
+		PivotTableStyleName: "PivotStyleLight19",
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:        "Sheet1!$A$1:$E$31",
+		PivotTableRange:  "Sheet1!$O$2:$T$34",
+		Rows:             []PivotTableField{{Data: "Region"}},
+		Data:             []PivotTableField{{Data: "Sales"}},
+		HideFieldHeaders: true,
+		ClassicLayout:    true,
+	}))
+	opt.pivotTableSheetName = ""
+	second := PivotTableOption{
+		Name:                "Pivot Table2",
+		DataRange:           "Sheet1!$A$1:$E$31",
+		PivotTableRange:     "Sheet1!$O$2:$T$34",
+		Rows:                []PivotTableField{{Data: "Region"}},
+		Data:                []PivotTableField{{Data: "Sales", Subtotal: "sum"}},
+		HideFieldHeaders:    true,
+		ClassicLayout:       true,
+		PivotTableStyleName: "PivotStyleLight16",
+	}
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableOption{opt, second}, pivotTables)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetPivotTables.xlsx")))
+
+	// Test get pivot tables after reopen the workbook.
+	f, err = OpenFile(filepath.Join("test", "TestGetPivotTables.xlsx"))
+	assert.NoError(t, err)
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableOption{opt, second}, pivotTables)
+
+	// Test add the same pivot table in other worksheet by the returned options.
+	f.NewSheet("Sheet2")
+	clone := pivotTables[0]
+	clone.PivotTableRange = "Sheet2!$A$1:$G$33"
+	assert.NoError(t, f.AddPivotTable(&clone))
+	pivotTables, err = f.GetPivotTables("Sheet2")
+	assert.NoError(t, err)
+	clone.pivotTableSheetName = ""
+	assert.Equal(t, []PivotTableOption{clone}, pivotTables)
+
+	// Test get pivot tables in the worksheet without pivot table.
+	f.NewSheet("Sheet3")
+	pivotTables, err = f.GetPivotTables("Sheet3")
+	assert.NoError(t, err)
+	assert.Nil(t, pivotTables)
+
+	// Test get pivot tables with not exist worksheet.
+	_, err = f.GetPivotTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test get pivot tables with unsupported charset pivot table and cache.
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", []byte(`<pivotTableDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetAbsoluteRangeRef(t *testing.T) {
+	assert.Equal(t, "$A$1:$E$31", getAbsoluteRangeRef("A1:$E31"))
+	assert.Equal(t, "$B$2", getAbsoluteRangeRef("B2"))
+	assert.Equal(t, "A:", getAbsoluteRangeRef("A:"))
+}
+
+func TestRefreshPivotCache(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Month", "Year", "Type", "Sales", "Region"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Feb", 2018, "Meat", 100.5, "East"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Jan", 2017, "Dairy", 200, "West"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Feb", 2017, "Meat", 300, nil}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$E$4",
+		PivotTableRange: "Sheet1!$G$2:$M$20",
+		Rows:            []PivotTableField{{Data: "Month", DefaultSubtotal: true}, {Data: "Year"}},
+		Filter:          []PivotTableField{{Data: "Region"}},
+		Columns:         []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+	}))
+	// Test rebuild pivot cache from the updated source data.
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", true))
+	assert.NoError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.True(t, pc.SaveData)
+	assert.True(t, pc.RefreshOnLoad)
+	assert.Equal(t, 3, pc.RecordCount)
+	assert.Equal(t, 5, pc.CacheFields.Count)
+	month := pc.CacheFields.CacheField[0].SharedItems
+	assert.Equal(t, []*xlsxString{{V: "Feb"}, {V: "Jan"}}, month.S)
+	year := pc.CacheFields.CacheField[1].SharedItems
+	assert.Equal(t, []*xlsxNumber{{V: 2018}, {V: 2017}}, year.N)
+	assert.Equal(t, boolPtr(false), year.ContainsString)
+	assert.Equal(t, boolPtr(false), year.ContainsSemiMixedTypes)
+	assert.True(t, year.ContainsInteger)
+	assert.Equal(t, float64Ptr(2017), year.MinValue)
+	assert.Equal(t, float64Ptr(2018), year.MaxValue)
+	types := pc.CacheFields.CacheField[2].SharedItems
+	assert.True(t, types.ContainsMixedTypes)
+	assert.Equal(t, []*xlsxBoolean{{V: true}}, types.B)
+	assert.False(t, pc.CacheFields.CacheField[3].SharedItems.ContainsInteger)
+	region := pc.CacheFields.CacheField[4].SharedItems
+	assert.True(t, region.ContainsBlank)
+	assert.Nil(t, region.ContainsSemiMixedTypes)
+	assert.Equal(t, 3, region.Count)
+
+	records := xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheRecords1.xml"), &records))
+	assert.Equal(t, 3, records.Count)
+	// The blank value is the first shared item, then the string values.
+	assert.Equal(t, []*xlsxX{{V: 0}, {V: 1}, {V: 1}, {V: 2}, {V: 0}}, records.R[2].X)
+	assert.Equal(t, []*xlsxX{{V: 1}, {V: 1}, {V: 0}, {V: 1}, {V: 2}}, records.R[1].X)
+	rels := f.relsReader("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels")
+	assert.Equal(t, "rId1", pc.RID)
+	assert.Equal(t, "pivotCacheRecords1.xml", rels.Relationships[0].Target)
+
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	items := pt.PivotFields.PivotField[1].Items
+	// The items of the pivot field are sorted in display order.
+	assert.Equal(t, 2, items.Count)
+	assert.Equal(t, 1, *items.Item[0].X)
+	assert.Equal(t, 0, *items.Item[1].X)
+	assert.Equal(t, "default", pt.PivotFields.PivotField[0].Items.Item[2].T)
+	assert.Nil(t, pt.PivotFields.PivotField[3].Items)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRefreshPivotCache.xlsx")))
+
+	// Test refresh pivot cache after the source columns changed, the fields
+	// which no longer exist should be removed from the pivot table.
+	f, err = OpenFile(filepath.Join("test", "TestRefreshPivotCache.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "Quarter"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Region"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "Month"))
+	assert.NoError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"))
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableField{{Data: "Month", DefaultSubtotal: true}}, pivotTables[0].Rows)
+	assert.Equal(t, []PivotTableField{{Data: "Region"}}, pivotTables[0].Filter)
+	assert.Equal(t, []PivotTableField{{Data: "Type", DefaultSubtotal: true}}, pivotTables[0].Columns)
+	assert.Equal(t, []PivotTableField{{Data: "Sales", Subtotal: "sum"}}, pivotTables[0].Data)
+	// Test refresh pivot cache with records part exists.
+	assert.NoError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"))
+	rels = f.relsReader("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels")
+	assert.Len(t, rels.Relationships, 1)
+
+	// Test refresh pivot cache with the data range of a defined name.
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "dataRange", RefersTo: "Sheet1!$A$1:$E$4"}))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cacheSource type="worksheet"><worksheetSource name="dataRange"/></cacheSource></pivotCacheDefinition>`))
+	assert.NoError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cacheSource type="worksheet"><worksheetSource name="notExist"/></cacheSource></pivotCacheDefinition>`))
+	assert.EqualError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"), "parameter 'DataRange' parsing error: parameter is invalid")
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`))
+	assert.EqualError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"), "parameter 'DataRange' parsing error: parameter is required")
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cacheSource type="worksheet"><worksheetSource ref="A1:E4" sheet="SheetN"/></cacheSource></pivotCacheDefinition>`))
+	assert.EqualError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"), "sheet SheetN is not exist")
+	// Test refresh pivot cache with unsupported charset.
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"), "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"), "XML syntax error on line 1: invalid UTF-8")
+
+	// Test refresh pivot cache with not exist pivot table and worksheet.
+	assert.EqualError(t, NewFile().RefreshPivotCache("Sheet1", "Pivot Table1"), ErrPivotTableNotExist.Error())
+	assert.EqualError(t, f.RefreshPivotCache("SheetN", "Pivot Table1"), "sheet SheetN is not exist")
+}
+
+func TestGetPivotCacheValue(t *testing.T) {
+	sst := &xlsxSST{SI: []xlsxSI{{T: &xlsxT{Val: "text"}}}}
+	for _, c := range []struct {
+		cell     xlsxC
+		expected pivotCacheValue
+	}{
+		{xlsxC{T: "s", V: "0"}, pivotCacheValue{typ: "s", val: "text"}},
+		{xlsxC{T: "s", V: "1"}, pivotCacheValue{typ: "s", val: "1"}},
+		{xlsxC{T: "inlineStr", IS: &xlsxSI{T: &xlsxT{Val: "inline"}}}, pivotCacheValue{typ: "s", val: "inline"}},
+		{xlsxC{T: "inlineStr", V: "value"}, pivotCacheValue{typ: "s", val: "value"}},
+		{xlsxC{T: "str", V: "formula"}, pivotCacheValue{typ: "s", val: "formula"}},
+		{xlsxC{T: "e", V: "#N/A"}, pivotCacheValue{typ: "e", val: "#N/A"}},
+		{xlsxC{V: "1.50"}, pivotCacheValue{typ: "n", val: "1.5", num: 1.5}},
+		{xlsxC{V: "text"}, pivotCacheValue{typ: "s", val: "text"}},
+		{xlsxC{}, pivotCacheValue{typ: "m"}},
+	} {
+		assert.Equal(t, c.expected, getPivotCacheValue(c.cell, sst))
+	}
+	sharedItems, indexes, order := newPivotCacheSharedItems([]pivotCacheValue{{typ: "s", val: "b"}, {typ: "e", val: "#N/A"}, {typ: "s", val: "A"}, {typ: "b", val: "0"}, {typ: "n", val: "2", num: 2}, {typ: "m"}})
+	assert.Equal(t, 6, sharedItems.Count)
+	assert.Equal(t, []int{4, 3, 5, 2, 1, 0}, indexes)
+	assert.Equal(t, []int{1, 5, 4, 2, 3, 0}, order)
+}
+
+func TestAddPivotTableCalculations(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Region", "Revenue", "Cost"}))
+	for idx, region := range []string{"East", "West", "North"} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+2), &[]interface{}{region, (idx + 1) * 100, (idx + 1) * 40}))
+	}
+	opt := PivotTableOption{
+		DataRange:        "Sheet1!$A$1:$C$4",
+		PivotTableRange:  "Sheet1!$E$2:$H$10",
+		Rows:             []PivotTableField{{Data: "Region", DefaultSubtotal: true}},
+		Data:             []PivotTableField{{Data: "Profit", Name: "Sum of Profit"}, {Data: "Revenue", Name: "Sum of Revenue"}},
+		CalculatedFields: []PivotTableCalculatedField{{Name: "Profit", Formula: "=Revenue-Cost"}},
+		CalculatedItems:  []PivotTableCalculatedItem{{Field: "Region", Name: "East and West", Formula: "East+West"}},
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pc.CacheFields.Count)
+	assert.Equal(t, "Revenue-Cost", pc.CacheFields.CacheField[3].Formula)
+	assert.Equal(t, boolPtr(false), pc.CacheFields.CacheField[3].DatabaseField)
+	assert.Equal(t, []*xlsxString{{V: "East and West"}}, pc.CacheFields.CacheField[0].SharedItems.S)
+	assert.Equal(t, 1, pc.CalculatedItems.Count)
+	assert.Equal(t, "East+West", pc.CalculatedItems.CalculatedItem[0].Formula)
+	ref := pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0]
+	assert.Equal(t, 0, *ref.Field)
+	assert.Equal(t, []*xlsxX{{V: 0}}, ref.X)
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	items := pt.PivotFields.PivotField[0].Items
+	assert.Equal(t, 2, items.Count)
+	assert.True(t, items.Item[0].F)
+	assert.Equal(t, 0, *items.Item[0].X)
+	assert.Equal(t, "default", items.Item[1].T)
+	assert.True(t, pt.PivotFields.PivotField[3].DataField)
+	assert.Equal(t, 3, pt.DataFields.DataField[0].Fld)
+
+	// Test get the calculated fields and items of the pivot table.
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableCalculatedField{{Name: "Profit", Formula: "Revenue-Cost"}}, pivotTables[0].CalculatedFields)
+	assert.Equal(t, opt.CalculatedItems, pivotTables[0].CalculatedItems)
+	assert.Equal(t, []PivotTableField{{Data: "Profit", Name: "Sum of Profit", Subtotal: "sum"}, {Data: "Revenue", Name: "Sum of Revenue", Subtotal: "sum"}}, pivotTables[0].Data)
+
+	// Test refresh the pivot cache with the calculated fields and items.
+	assert.NoError(t, f.RefreshPivotCache("Sheet1", "Pivot Table1"))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pc.CacheFields.Count)
+	assert.Equal(t, "Revenue-Cost", pc.CacheFields.CacheField[3].Formula)
+	assert.Equal(t, []*xlsxString{{V: "East"}, {V: "West"}, {V: "North"}, {V: "East and West"}}, pc.CacheFields.CacheField[0].SharedItems.S)
+	ref = pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0]
+	assert.Equal(t, []*xlsxX{{V: 3}}, ref.X)
+	pt, err = f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	items = pt.PivotFields.PivotField[0].Items
+	assert.Equal(t, 5, items.Count)
+	for idx, x := range []int{0, 2, 1, 3} {
+		assert.Equal(t, x, *items.Item[idx].X)
+	}
+	assert.True(t, items.Item[3].F)
+	assert.Equal(t, "default", items.Item[4].T)
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, opt.CalculatedItems, pivotTables[0].CalculatedItems)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableCalculations.xlsx")))
+
+	// Test add pivot table with invalid calculated fields and items.
+	for _, c := range []struct {
+		fields []PivotTableCalculatedField
+		items  []PivotTableCalculatedItem
+		rows   []PivotTableField
+		err    error
+	}{
+		{fields: []PivotTableCalculatedField{{Name: "Profit", Formula: "="}}, err: ErrParameterRequired},
+		{fields: []PivotTableCalculatedField{{Name: "Revenue", Formula: "Revenue*2"}}, err: ErrPivotTableCalculatedFieldName},
+		{fields: []PivotTableCalculatedField{{Name: "Profit", Formula: "Revenue-Cost"}}, rows: []PivotTableField{{Data: "Profit"}}, err: ErrPivotTableCalculatedField},
+		{items: []PivotTableCalculatedItem{{Field: "Region", Formula: "East+West"}}, err: ErrParameterRequired},
+		{items: []PivotTableCalculatedItem{{Field: "Cost", Name: "Total", Formula: "1"}}, err: ErrPivotTableCalculatedItem},
+	} {
+		rows := c.rows
+		if rows == nil {
+			rows = []PivotTableField{{Data: "Region"}}
+		}
+		assert.EqualError(t, f.AddPivotTable(&PivotTableOption{
+			DataRange:        "Sheet1!$A$1:$C$4",
+			PivotTableRange:  "Sheet1!$J$2:$M$10",
+			Rows:             rows,
+			Data:             []PivotTableField{{Data: "Revenue"}},
+			CalculatedFields: c.fields,
+			CalculatedItems:  c.items,
+		}), c.err.Error())
+	}
+}
+
+func TestGetPivotSharedItemValue(t *testing.T) {
+	sharedItems := &xlsxSharedItems{
+		M: []*xlsxMissing{{}},
+		N: []*xlsxNumber{{V: 1.5}},
+		B: []*xlsxBoolean{{V: true}},
+		E: []*xlsxError{{V: "#N/A"}},
+		S: []*xlsxString{{V: "text"}},
+	}
+	for x, expected := range []string{"", "1.5", "TRUE", "#N/A", "text", ""} {
+		assert.Equal(t, expected, getPivotSharedItemValue(sharedItems, x))
+	}
+	assert.Equal(t, "", getPivotSharedItemValue(nil, 0))
+	// Test get the calculated items with invalid pivot area.
+	opt := PivotTableOption{}
+	getPivotTableCalculations(&opt, &xlsxPivotCacheDefinition{
+		CacheFields: &xlsxCacheFields{CacheField: []*xlsxCacheField{{Name: "Region"}}},
+		CalculatedItems: &xlsxCalculatedItems{CalculatedItem: []*xlsxCalculatedItem{
+			{}, {PivotArea: &xlsxPivotArea{References: &xlsxPivotAreaReferences{Reference: []*xlsxPivotAreaReference{{Field: intPtr(1)}}}}},
+		}},
+	})
+	assert.Nil(t, opt.CalculatedItems)
+}
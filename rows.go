@@ -18,7 +18,9 @@ import (
 	"io"
 	"log"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/mohae/deepcopy"
 )
@@ -58,6 +60,89 @@ func (f *File) GetRows(sheet string) ([][]string, error) {
 	return results[:max], nil
 }
 
+// GetSparseRows return all the rows in a sheet by given worksheet name (case
+// sensitive) in the sparse representation. Each row is a map of the cell
+// values with value or formula keyed by the zero-based column index, which
+// avoids padding the blank cells for the sparse wide worksheets. The rows
+// without any cell value will be nil maps. For example:
+//
+//    rows, err := f.GetSparseRows("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for rowIdx, row := range rows {
+//        for colIdx, cell := range row {
+//            fmt.Println(rowIdx, colIdx, cell)
+//        }
+//    }
+//
+func (f *File) GetSparseRows(sheet string) ([]map[int]string, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	results, cur, max := make([]map[int]string, 0, 64), 0, 0
+	for rows.Next() {
+		cur++
+		row, err := rows.SparseColumns()
+		if err != nil {
+			break
+		}
+		results = append(results, row)
+		if len(row) > 0 {
+			max = cur
+		}
+	}
+	return results[:max], nil
+}
+
+// GetRichTextRows return all the rows in a sheet by given worksheet name
+// (case sensitive) with the cell values as the rich text runs, which retains
+// the font settings of the rich text fragments such as the bold and colored
+// texts, so that the converters to other formats like HTML could render
+// them. The plain cells will be a single rich text run without font
+// settings, and the blank cells will be nil. For example:
+//
+//    rows, err := f.GetRichTextRows("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, row := range rows {
+//        for _, cell := range row {
+//            for _, run := range cell {
+//                if run.Font != nil && run.Font.Bold {
+//                    fmt.Print("<b>", run.Text, "</b>")
+//                    continue
+//                }
+//                fmt.Print(run.Text)
+//            }
+//            fmt.Print("\t")
+//        }
+//        fmt.Println()
+//    }
+//
+func (f *File) GetRichTextRows(sheet string) ([][][]RichTextRun, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	results, cur, max := make([][][]RichTextRun, 0, 64), 0, 0
+	for rows.Next() {
+		cur++
+		row, err := rows.RichTextColumns()
+		if err != nil {
+			break
+		}
+		results = append(results, row)
+		if len(row) > 0 {
+			max = cur
+		}
+	}
+	return results[:max], nil
+}
+
 // Rows defines an iterator to a sheet.
 type Rows struct {
 	err                        error
@@ -65,6 +150,23 @@ type Rows struct {
 	sheet                      string
 	f                          *File
 	decoder                    *xml.Decoder
+	sharedFormulas             map[string]string
+}
+
+// RowCell directly maps the cell value with the meta data of the cell in the
+// row, which returned by the CellsWithMeta function. The Value is the raw
+// value of the cell without number format applied, and the shared string
+// and inline string cell values will be resolved to the plain text. The
+// FormattedValue is the cell value with number format applied as the
+// Columns function returned.
+type RowCell struct {
+	Cell           string
+	Col            int
+	Type           CellType
+	Value          string
+	FormattedValue string
+	StyleID        int
+	Formula        string
 }
 
 // Next will return true if find the next row element.
@@ -80,11 +182,68 @@ func (rows *Rows) Error() error {
 
 // Columns return the current row's column values.
 func (rows *Rows) Columns() ([]string, error) {
-	var rowIterator rowXMLIterator
+	rowIterator := rows.readRow(false, false, false)
+	return rowIterator.columns, rowIterator.err
+}
+
+// SparseColumns return the current row's cell values with value or formula
+// keyed by the zero-based column index.
+func (rows *Rows) SparseColumns() (map[int]string, error) {
+	rowIterator := rows.readRow(true, false, false)
+	return rowIterator.cells, rowIterator.err
+}
+
+// CellsWithMeta return the current row's cells in the document order with
+// the value type, raw value, formatted value, style index and formula of
+// each cell. The cells without value, style and formula will be skipped. The
+// numeric cells with date and time number format will be number type cells,
+// use the StyleID of the cell to get the number format if needed. For
+// example:
+//
+//    rows, err := f.Rows("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for rows.Next() {
+//        cells, err := rows.CellsWithMeta()
+//        if err != nil {
+//            fmt.Println(err)
+//            break
+//        }
+//        for _, cell := range cells {
+//            if cell.Type == excelize.CellTypeNumber {
+//                fmt.Println(cell.Cell, cell.Value, cell.StyleID, cell.Formula)
+//            }
+//        }
+//    }
+//
+func (rows *Rows) CellsWithMeta() ([]RowCell, error) {
+	rowIterator := rows.readRow(false, true, false)
+	return rowIterator.metaCells, rowIterator.err
+}
+
+// RichTextColumns return the current row's cell values as the rich text
+// runs, the font settings of the rich text fragments of the shared strings
+// and inline strings will be retained. The plain string cells and the other
+// cells with value will be returned as a single rich text run without font
+// settings, the text of the run is the cell value with number format
+// applied as the Columns function returned. The blank cells will be nil.
+func (rows *Rows) RichTextColumns() ([][]RichTextRun, error) {
+	rowIterator := rows.readRow(false, false, true)
+	return rowIterator.richTextCells, rowIterator.err
+}
+
+// readRow provides a function to parse the current row by the SAX parser,
+// the cell values will be stored in the map when the sparse is true, the
+// cells with meta data will be stored in the slice when the meta is true,
+// and the rich text runs of the cells will be stored in the slice when the
+// richText is true.
+func (rows *Rows) readRow(sparse, meta, richText bool) (rowIterator rowXMLIterator) {
 	if rows.stashRow >= rows.curRow {
-		return rowIterator.columns, rowIterator.err
+		return
 	}
-	rowIterator.rows = rows
+	rowIterator.rows, rowIterator.sparse, rowIterator.meta, rowIterator.richText = rows, sparse, meta, richText
 	rowIterator.d = rows.f.sharedStringsReader()
 	for {
 		token, _ := rows.decoder.Token()
@@ -101,12 +260,12 @@ func (rows *Rows) Columns() ([]string, error) {
 				}
 				if rowIterator.row > rowIterator.rows.curRow {
 					rowIterator.rows.stashRow = rowIterator.row - 1
-					return rowIterator.columns, rowIterator.err
+					return
 				}
 			}
 			rowXMLHandler(&rowIterator, &xmlElement)
 			if rowIterator.err != nil {
-				return rowIterator.columns, rowIterator.err
+				return
 			}
 		case xml.EndElement:
 			rowIterator.inElement = xmlElement.Name.Local
@@ -114,22 +273,28 @@ func (rows *Rows) Columns() ([]string, error) {
 				rowIterator.row = rowIterator.rows.curRow
 			}
 			if rowIterator.inElement == "row" && rowIterator.row+1 < rowIterator.rows.curRow {
-				return rowIterator.columns, rowIterator.err
+				return
 			}
 			if rowIterator.inElement == "sheetData" {
-				return rowIterator.columns, rowIterator.err
+				return
 			}
 		}
 	}
-	return rowIterator.columns, rowIterator.err
+	return
 }
 
 // appendSpace append blank characters to slice by given length and source slice.
 func appendSpace(l int, s []string) []string {
-	for i := 1; i < l; i++ {
-		s = append(s, "")
+	n := len(s) + l - 1
+	if n <= len(s) {
+		return s
+	}
+	if n < cap(s) {
+		return s[:n]
 	}
-	return s
+	grown := make([]string, n, n+n/2+1)
+	copy(grown, s)
+	return grown
 }
 
 // ErrSheetNotExist defines an error of sheet is not exist
@@ -141,12 +306,30 @@ func (err ErrSheetNotExist) Error() string {
 	return fmt.Sprintf("sheet %s is not exist", string(err.SheetName))
 }
 
+// ErrNotWorksheet defines an error of the sheet is a chart sheet, dialog
+// sheet or macro sheet, which is not supported by the worksheet functions.
+// The SheetType is one of "chartsheet", "dialogsheet" and "macrosheet".
+type ErrNotWorksheet struct {
+	SheetName string
+	SheetType string
+}
+
+func (err ErrNotWorksheet) Error() string {
+	sheetType := map[string]string{"chartsheet": "chart sheet", "dialogsheet": "dialog sheet", "macrosheet": "macro sheet"}[err.SheetType]
+	return fmt.Sprintf("sheet %s is %s", err.SheetName, sheetType)
+}
+
 // rowXMLIterator defined runtime use field for the worksheet row SAX parser.
 type rowXMLIterator struct {
 	err                 error
 	inElement           string
 	attrR, cellCol, row int
 	columns             []string
+	cells               map[int]string
+	metaCells           []RowCell
+	richTextCells       [][]RichTextRun
+	sparse, meta        bool
+	richText            bool
 	rows                *Rows
 	d                   *xlsxSST
 }
@@ -158,17 +341,74 @@ func rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.StartElement) {
 		rowIterator.cellCol++
 		colCell := xlsxC{}
 		_ = rowIterator.rows.decoder.DecodeElement(&colCell, xmlElement)
-		if colCell.R != "" {
+		ref, skip := rowIterator.rows.f.checkCellBounds(colCell.R)
+		if skip {
+			rowIterator.cellCol--
+			return
+		}
+		if colCell.R = ref; colCell.R != "" {
 			if rowIterator.cellCol, _, rowIterator.err = CellNameToCoordinates(colCell.R); rowIterator.err != nil {
 				return
 			}
 		}
+		if rowIterator.meta {
+			if colCell.hasValue() {
+				rowIterator.metaCells = append(rowIterator.metaCells, rowIterator.rows.getRowCell(&colCell, rowIterator.cellCol, rowIterator.d))
+			}
+			return
+		}
+		if rowIterator.richText {
+			if runs, _ := rowIterator.rows.f.getCellRichTextRuns(&colCell, rowIterator.d, true); len(runs) > 0 {
+				for len(rowIterator.richTextCells) < rowIterator.cellCol {
+					rowIterator.richTextCells = append(rowIterator.richTextCells, nil)
+				}
+				rowIterator.richTextCells[rowIterator.cellCol-1] = runs
+			}
+			return
+		}
 		blank := rowIterator.cellCol - len(rowIterator.columns)
 		val, _ := colCell.getValueFrom(rowIterator.rows.f, rowIterator.d)
 		if val != "" || colCell.F != nil {
-			rowIterator.columns = append(appendSpace(blank, rowIterator.columns), val)
+			if rowIterator.sparse {
+				if rowIterator.cells == nil {
+					rowIterator.cells = make(map[int]string)
+				}
+				rowIterator.cells[rowIterator.cellCol-1] = val
+				return
+			}
+			if blank > 0 {
+				rowIterator.columns = append(appendSpace(blank, rowIterator.columns), val)
+				return
+			}
+			// the cells produced by some generators in random order
+			rowIterator.columns[rowIterator.cellCol-1] = val
+		}
+	}
+}
+
+// getRowCell provides a function to get the cell value with the meta data
+// of the cell by given cell and column number. The formula of the cells in
+// the shared formula range will be the formula of the master cell as the
+// GetCellFormula function returned.
+func (rows *Rows) getRowCell(c *xlsxC, col int, d *xlsxSST) RowCell {
+	if c.R == "" {
+		c.R, _ = CoordinatesToCellName(col, rows.curRow)
+	}
+	cell := RowCell{Cell: c.R, Col: col, Type: c.getCellType(), Value: c.getRawValueFrom(d), StyleID: c.S}
+	cell.FormattedValue, _ = c.getValueFrom(rows.f, d)
+	if c.F != nil {
+		cell.Formula = c.F.Content
+		if c.F.T == STCellFormulaTypeShared {
+			if rows.sharedFormulas == nil {
+				rows.sharedFormulas = make(map[string]string)
+			}
+			if c.F.Ref != "" {
+				rows.sharedFormulas[c.F.Si] = c.F.Content
+			}
+			cell.Formula = rows.sharedFormulas[c.F.Si]
 		}
 	}
+	return cell
 }
 
 // Rows returns a rows iterator, used for streaming reading data for a
@@ -195,6 +435,11 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	if !ok {
 		return nil, ErrSheetNotExist{sheet}
 	}
+	if !strings.HasPrefix(name, "xl/worksheets/") {
+		if sheetType := f.getSheetType(name); sheetType != "" && sheetType != "worksheet" {
+			return nil, ErrNotWorksheet{SheetName: sheet, SheetType: sheetType}
+		}
+	}
 	if ws, ok := f.Sheet.Load(name); ok && ws != nil {
 		worksheet := ws.(*xlsxWorksheet)
 		worksheet.Lock()
@@ -232,6 +477,9 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 			}
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
+				if f.outOfBoundsCells != "" && rows.totalRow > TotalRows {
+					rows.totalRow = TotalRows
+				}
 				rows.f = f
 				rows.sheet = name
 				rows.decoder = f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
@@ -243,8 +491,8 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	return &rows, nil
 }
 
-// SetRowHeight provides a function to set the height of a single row. For
-// example, set the height of the first row in Sheet1:
+// SetRowHeight provides a function to set the height in points of a single
+// row. For example, set the height of the first row in Sheet1:
 //
 //    err := f.SetRowHeight("Sheet1", 1, 50)
 //
@@ -284,8 +532,9 @@ func (f *File) getRowHeight(sheet string, row int) int {
 	return int(defaultRowHeightPixels)
 }
 
-// GetRowHeight provides a function to get row height by given worksheet name
-// and row number. For example, get the height of the first row in Sheet1:
+// GetRowHeight provides a function to get row height in points by given
+// worksheet name and row number. For example, get the height of the first row
+// in Sheet1:
 //
 //    height, err := f.GetRowHeight("Sheet1", 1)
 //
@@ -313,6 +562,36 @@ func (f *File) GetRowHeight(sheet string, row int) (float64, error) {
 	return ht, nil
 }
 
+// SetRowHeightPixels provides a function to set the height in pixels of a
+// single row, the height will be converted to points at 96 DPI. For example,
+// set the height of the first row in Sheet1 to 40 pixels:
+//
+//    err := f.SetRowHeightPixels("Sheet1", 1, 40)
+//
+func (f *File) SetRowHeightPixels(sheet string, row int, pixels float64) error {
+	return f.SetRowHeight(sheet, row, PixelsToPoints(pixels))
+}
+
+// GetRowHeightPixels provides a function to get the height in pixels of the
+// row by given worksheet name and row number, the height in points returned
+// by GetRowHeight will be converted to pixels at 96 DPI.
+func (f *File) GetRowHeightPixels(sheet string, row int) (float64, error) {
+	height, err := f.GetRowHeight(sheet, row)
+	return PointsToPixels(height), err
+}
+
+// PointsToPixels provides a function to convert the size in points to pixels
+// at 96 DPI.
+func PointsToPixels(points float64) float64 {
+	return points * 96 / 72
+}
+
+// PixelsToPoints provides a function to convert the size in pixels at 96 DPI
+// to points.
+func PixelsToPoints(pixels float64) float64 {
+	return pixels * 72 / 96
+}
+
 // sharedStringsReader provides a function to get the pointer to the structure
 // after deserialization of xl/sharedStrings.xml.
 func (f *File) sharedStringsReader() *xlsxSST {
@@ -355,8 +634,9 @@ func (f *File) sharedStringsReader() *xlsxSST {
 
 // getValueFrom return a value from a column/row cell, this function is
 // inteded to be used with for range on rows an argument with the spreadsheet
-// opened file.
-func (c *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
+// opened file. The options could be used to specify the culture to format
+// the cell value.
+func (c *xlsxC) getValueFrom(f *File, d *xlsxSST, opts ...Options) (string, error) {
 	f.Lock()
 	defer f.Unlock()
 	switch c.T {
@@ -365,26 +645,26 @@ func (c *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
 			xlsxSI := 0
 			xlsxSI, _ = strconv.Atoi(c.V)
 			if len(d.SI) > xlsxSI {
-				return f.formattedValue(c.S, d.SI[xlsxSI].String()), nil
+				return f.formattedValue(c.S, d.SI[xlsxSI].String(), opts...), nil
 			}
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return f.formattedValue(c.S, c.V, opts...), nil
 	case "str":
-		return f.formattedValue(c.S, c.V), nil
+		return f.formattedValue(c.S, c.V, opts...), nil
 	case "inlineStr":
 		if c.IS != nil {
-			return f.formattedValue(c.S, c.IS.String()), nil
+			return f.formattedValue(c.S, c.IS.String(), opts...), nil
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return f.formattedValue(c.S, c.V, opts...), nil
 	default:
 		isNum, precision := isNumeric(c.V)
 		if isNum && precision > 10 {
 			val, _ := roundPrecision(c.V)
 			if val != c.V {
-				return f.formattedValue(c.S, val), nil
+				return f.formattedValue(c.S, val, opts...), nil
 			}
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return f.formattedValue(c.S, c.V, opts...), nil
 	}
 }
 
@@ -438,6 +718,67 @@ func (f *File) GetRowVisible(sheet string, row int) (bool, error) {
 	return !ws.SheetData.Row[row-1].Hidden, nil
 }
 
+// SetRowsVisible provides a function to set visible of the rows by given
+// worksheet name, the first and the last Excel row number of the rows and
+// the visibility, which is much faster than setting the visible of the rows
+// one by one. For example, hide the rows from 2 to 10000 in Sheet1:
+//
+//    err := f.SetRowsVisible("Sheet1", 2, 10000, false)
+//
+func (f *File) SetRowsVisible(sheet string, start, end int, visible bool) error {
+	if end < start {
+		start, end = end, start
+	}
+	if start < 1 {
+		return newInvalidRowNumberError(start)
+	}
+	if end > TotalRows {
+		return ErrMaxRows
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	prepareSheetXML(ws, 0, end)
+	for row := start; row <= end; row++ {
+		ws.SheetData.Row[row-1].Hidden = !visible
+	}
+	return nil
+}
+
+// GetHiddenRows provides a function to get the hidden rows by given
+// worksheet name, the contiguous hidden rows are returned as one row range
+// reference in the order of the rows. For example, get the hidden rows in
+// Sheet1, the result will be [2:4 7:7] if the rows 2, 3, 4 and 7 are hidden:
+//
+//    rows, err := f.GetHiddenRows("Sheet1")
+//
+func (f *File) GetHiddenRows(sheet string) ([]string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var hiddenRows []string
+	start, end := 0, 0
+	for _, row := range ws.SheetData.Row {
+		if !row.Hidden {
+			continue
+		}
+		if start != 0 && row.R == end+1 {
+			end = row.R
+			continue
+		}
+		if start != 0 {
+			hiddenRows = append(hiddenRows, fmt.Sprintf("%d:%d", start, end))
+		}
+		start, end = row.R, row.R
+	}
+	if start != 0 {
+		hiddenRows = append(hiddenRows, fmt.Sprintf("%d:%d", start, end))
+	}
+	return hiddenRows, err
+}
+
 // SetRowOutlineLevel provides a function to set outline level number of a
 // single row by given worksheet name and Excel row number. The value of
 // parameter 'level' is 1-7. For example, outline row 2 in Sheet1 to level 1:
@@ -480,6 +821,145 @@ func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
 	return ws.SheetData.Row[row-1].OutlineLevel, nil
 }
 
+// RowOption is an option of a row of a worksheet. See SetRowOptions().
+type RowOption interface {
+	setRowOption(row *xlsxRow)
+}
+
+// RowOptionPtr is a writable RowOption. See GetRowOptions().
+type RowOptionPtr interface {
+	RowOption
+	getRowOption(row *xlsxRow)
+}
+
+type (
+	// RowThickTop is a RowOption
+	RowThickTop bool
+	// RowThickBottom is a RowOption
+	RowThickBottom bool
+	// RowPhonetic is a RowOption
+	RowPhonetic bool
+	// RowCollapsed is a RowOption
+	RowCollapsed bool
+	// RowCustomFormat is a RowOption
+	RowCustomFormat bool
+)
+
+// setRowOption implements the RowOption interface and specifies if the row
+// has a medium or thick top border, or if any cell in the row directly above
+// has a medium or thick bottom border.
+func (o RowThickTop) setRowOption(row *xlsxRow) {
+	row.ThickTop = bool(o)
+}
+
+// getRowOption implements the RowOptionPtr interface and get the settings of
+// whether the row has a thick top border.
+func (o *RowThickTop) getRowOption(row *xlsxRow) {
+	*o = RowThickTop(row.ThickTop)
+}
+
+// setRowOption implements the RowOption interface and specifies if the row
+// has a medium or thick bottom border, or if any cell in the row directly
+// below has a medium or thick top border.
+func (o RowThickBottom) setRowOption(row *xlsxRow) {
+	row.ThickBot = bool(o)
+}
+
+// getRowOption implements the RowOptionPtr interface and get the settings of
+// whether the row has a thick bottom border.
+func (o *RowThickBottom) getRowOption(row *xlsxRow) {
+	*o = RowThickBottom(row.ThickBot)
+}
+
+// setRowOption implements the RowOption interface and specifies if the
+// phonetic information should be displayed by default for the row.
+func (o RowPhonetic) setRowOption(row *xlsxRow) {
+	row.Ph = bool(o)
+}
+
+// getRowOption implements the RowOptionPtr interface and get the settings of
+// whether the phonetic information should be displayed for the row.
+func (o *RowPhonetic) getRowOption(row *xlsxRow) {
+	*o = RowPhonetic(row.Ph)
+}
+
+// setRowOption implements the RowOption interface and specifies if the rows
+// one level of outlining deeper than the current row are in the collapsed
+// outline state.
+func (o RowCollapsed) setRowOption(row *xlsxRow) {
+	row.Collapsed = bool(o)
+}
+
+// getRowOption implements the RowOptionPtr interface and get the settings of
+// whether the outline of the row is collapsed.
+func (o *RowCollapsed) getRowOption(row *xlsxRow) {
+	*o = RowCollapsed(row.Collapsed)
+}
+
+// setRowOption implements the RowOption interface and specifies if the row
+// style should be applied.
+func (o RowCustomFormat) setRowOption(row *xlsxRow) {
+	row.CustomFormat = bool(o)
+}
+
+// getRowOption implements the RowOptionPtr interface and get the settings of
+// whether the row style should be applied.
+func (o *RowCustomFormat) getRowOption(row *xlsxRow) {
+	*o = RowCustomFormat(row.CustomFormat)
+}
+
+// SetRowOptions provides a function to set the row attributes by given
+// worksheet name, Excel row number and row options. For example, set a thick
+// bottom border flag and collapse the outline of row 2 in Sheet1:
+//
+//    err := f.SetRowOptions("Sheet1", 2,
+//        excelize.RowThickBottom(true),
+//        excelize.RowCollapsed(true),
+//    )
+//
+func (f *File) SetRowOptions(sheet string, row int, opts ...RowOption) error {
+	if row < 1 {
+		return newInvalidRowNumberError(row)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	prepareSheetXML(ws, 0, row)
+	for _, opt := range opts {
+		opt.setRowOption(&ws.SheetData.Row[row-1])
+	}
+	return err
+}
+
+// GetRowOptions provides a function to get the row attributes by given
+// worksheet name, Excel row number and row options. For example, get the
+// thick bottom border flag and collapsed state of row 2 in Sheet1:
+//
+//    var (
+//        thickBottom excelize.RowThickBottom
+//        collapsed   excelize.RowCollapsed
+//    )
+//    err := f.GetRowOptions("Sheet1", 2, &thickBottom, &collapsed)
+//
+func (f *File) GetRowOptions(sheet string, row int, opts ...RowOptionPtr) error {
+	if row < 1 {
+		return newInvalidRowNumberError(row)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	rowData := xlsxRow{}
+	if row <= len(ws.SheetData.Row) {
+		rowData = ws.SheetData.Row[row-1]
+	}
+	for _, opt := range opts {
+		opt.getRowOption(&rowData)
+	}
+	return err
+}
+
 // RemoveRow provides a function to remove single row by given worksheet name
 // and Excel row number. For example, remove row 3 in Sheet1:
 //
@@ -685,6 +1165,8 @@ func checkRow(ws *xlsxWorksheet) error {
 			}
 			rowData.C[idx].R, _ = CoordinatesToCellName(rCount, rowIdx+1)
 		}
+		// sort the cells produced by some generators in random order
+		rowData.sortCells()
 		lastCol, _, err := CellNameToCoordinates(rowData.C[colCount-1].R)
 		if err != nil {
 			return err
@@ -719,6 +1201,47 @@ func checkRow(ws *xlsxWorksheet) error {
 	return nil
 }
 
+// prepareCells provides a function to sort the cells in the row by the
+// column number and set the spans attribute of the row by the first and
+// last column number of the cells, the spans attribute will be removed if
+// the row is empty. The strict consumers rely on the spans attribute and
+// the order of the cells.
+func (r *xlsxRow) prepareCells() {
+	r.Spans = ""
+	if cols := r.sortCells(); len(cols) > 0 && cols[0] > 0 {
+		r.Spans = fmt.Sprintf("%d:%d", cols[0], cols[len(cols)-1])
+	}
+}
+
+// sortCells provides a function to sort the cells in the row by the column
+// number stably, and returns the sorted column numbers of the cells, the
+// column number of the cell with invalid reference is 0.
+func (r *xlsxRow) sortCells() []int {
+	cols, sorted := make([]int, len(r.C)), true
+	for i, c := range r.C {
+		cols[i], _, _ = CellNameToCoordinates(c.R)
+		sorted = sorted && (i == 0 || cols[i-1] <= cols[i])
+	}
+	if !sorted {
+		sort.Stable(cellsByColumn{cells: r.C, cols: cols})
+	}
+	return cols
+}
+
+// cellsByColumn implements the sort.Interface to sort the cells by given
+// column number of the cells.
+type cellsByColumn struct {
+	cells []xlsxC
+	cols  []int
+}
+
+func (c cellsByColumn) Len() int           { return len(c.cells) }
+func (c cellsByColumn) Less(i, j int) bool { return c.cols[i] < c.cols[j] }
+func (c cellsByColumn) Swap(i, j int) {
+	c.cells[i], c.cells[j] = c.cells[j], c.cells[i]
+	c.cols[i], c.cols[j] = c.cols[j], c.cols[i]
+}
+
 // convertRowHeightToPixels provides a function to convert the height of a
 // cell from user's units to pixels. If the height hasn't been set by the user
 // we use the default value. If the row is hidden it has a value of zero.
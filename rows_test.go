@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -154,6 +155,25 @@ func TestRowHeight(t *testing.T) {
 	assert.Equal(t, 0.0, convertColWidthToPixels(0))
 }
 
+func TestRowHeightPixels(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetRowHeightPixels("Sheet1", 1, 40))
+	height, err := f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	pixels, err := f.GetRowHeightPixels("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 40.0, pixels)
+	pixels, err = f.GetRowHeightPixels("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRowHeightPixels, pixels)
+	assert.EqualError(t, f.SetRowHeightPixels("Sheet1", 1, PointsToPixels(MaxRowHeight)+1), ErrMaxRowHeight.Error())
+	_, err = f.GetRowHeightPixels("SheetN", 1)
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.Equal(t, 20.0, PointsToPixels(15))
+	assert.Equal(t, 15.0, PixelsToPoints(20))
+}
+
 func TestColumns(t *testing.T) {
 	f := NewFile()
 	rows, err := f.Rows("Sheet1")
@@ -221,6 +241,73 @@ func TestRowVisibility(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRowVisibility.xlsx")))
 }
 
+func TestSetRowsVisible(t *testing.T) {
+	f := NewFile()
+	hiddenRows, err := f.GetHiddenRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, hiddenRows)
+
+	assert.NoError(t, f.SetRowsVisible("Sheet1", 2, 50000, false))
+	assert.NoError(t, f.SetRowsVisible("Sheet1", 10, 5, true))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 50002, false))
+	for row, expected := range map[int]bool{1: true, 2: false, 4: false, 5: true, 10: true, 11: false, 50000: false, 50001: true} {
+		visible, err := f.GetRowVisible("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, visible, row)
+	}
+	hiddenRows, err = f.GetHiddenRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2:4", "11:50000", "50002:50002"}, hiddenRows)
+
+	assert.EqualError(t, f.SetRowsVisible("Sheet1", 0, 2, false), "invalid row number 0")
+	assert.EqualError(t, f.SetRowsVisible("Sheet1", 1, TotalRows+1, false), ErrMaxRows.Error())
+	assert.EqualError(t, f.SetRowsVisible("SheetN", 1, 2, false), "sheet SheetN is not exist")
+	_, err = f.GetHiddenRows("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestRowOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetRowOptions("Sheet1", 2,
+		RowThickTop(true),
+		RowThickBottom(true),
+		RowPhonetic(true),
+		RowCollapsed(true),
+		RowCustomFormat(true),
+	))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 2, 30))
+	path := filepath.Join("test", "TestRowOptions.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+
+	f, err := OpenFile(path)
+	assert.NoError(t, err)
+	var (
+		thickTop     RowThickTop
+		thickBottom  RowThickBottom
+		phonetic     RowPhonetic
+		collapsed    RowCollapsed
+		customFormat RowCustomFormat
+	)
+	assert.NoError(t, f.GetRowOptions("Sheet1", 2, &thickTop, &thickBottom, &phonetic, &collapsed, &customFormat))
+	assert.True(t, bool(thickTop))
+	assert.True(t, bool(thickBottom))
+	assert.True(t, bool(phonetic))
+	assert.True(t, bool(collapsed))
+	assert.True(t, bool(customFormat))
+	height, err := f.GetRowHeight("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	// Test get row options on not exists row
+	assert.NoError(t, f.GetRowOptions("Sheet1", 10, &thickTop))
+	assert.False(t, bool(thickTop))
+	// Test set and get row options with invalid row number
+	assert.EqualError(t, f.SetRowOptions("Sheet1", 0, RowThickTop(true)), newInvalidRowNumberError(0).Error())
+	assert.EqualError(t, f.GetRowOptions("Sheet1", 0, &thickTop), newInvalidRowNumberError(0).Error())
+	// Test set and get row options on not exists worksheet
+	assert.EqualError(t, f.SetRowOptions("SheetN", 1, RowThickTop(true)), "sheet SheetN is not exist")
+	assert.EqualError(t, f.GetRowOptions("SheetN", 1, &thickTop), "sheet SheetN is not exist")
+}
+
 func TestRemoveRow(t *testing.T) {
 	f := NewFile()
 	sheet1 := f.GetSheetName(0)
@@ -889,6 +976,38 @@ func TestCheckRow(t *testing.T) {
 	assert.EqualError(t, f.SetCellValue("Sheet1", "A1", false), `cannot convert cell "-" to coordinates: invalid cell name "-"`)
 }
 
+func TestRowSpans(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", "C1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "B1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E2", "E2"))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 3, 20))
+	f.workSheetWriter()
+	content, ok := f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<row r="1" spans="2:3"><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>0</v></c></row><row r="2" spans="5:5">`)
+	assert.Contains(t, string(content.([]byte)), `<row r="3" ht="20" customHeight="true"></row>`)
+
+	// Test read the row with incorrect spans and unsorted cells.
+	f = NewFile()
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1" spans="9:2"><c r="C1" t="str"><v>C1</v></c><c r="A1" t="str"><v>A1</v></c><c r="B1" t="str"><v>B1</v></c></row></sheetData></worksheet>`))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	delete(f.checked, "xl/worksheets/sheet1.xml")
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1", "B1", "C1"}}, rows)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", value)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", ws.SheetData.Row[0].C[0].R)
+	f.workSheetWriter()
+	content, ok = f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<row r="1" spans="1:3"><c r="A1" t="str"><v>A1</v></c><c r="B1" t="str"><v>B1</v></c><c r="C1" t="str"><v>C1</v></c></row>`)
+}
+
 func TestNumberFormats(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
@@ -910,6 +1029,152 @@ func TestNumberFormats(t *testing.T) {
 	assert.Equal(t, []string{"", "200", "450", "200", "510", "315", "127", "89", "348", "53", "37"}, cells[3])
 }
 
+func TestGetSparseRows(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "B1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "XFD1", "XFD1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "=1+1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A5", ""))
+	rows, err := f.GetSparseRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []map[int]string{{1: "B1", TotalColumns - 1: "XFD1"}, nil, {2: ""}}, rows)
+	// Test get sparse rows with the cells in random order.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0], ws.SheetData.Row[0].C[1] = ws.SheetData.Row[0].C[1], ws.SheetData.Row[0].C[0]
+	rows, err = f.GetSparseRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "B1", TotalColumns - 1: "XFD1"}, rows[0])
+	// Test get sparse rows with invalid cell reference.
+	ws.SheetData.Row[0].C[0].R = "A"
+	_, err = f.GetSparseRows("Sheet1")
+	assert.NoError(t, err)
+	// Test get sparse rows on not exists worksheet.
+	_, err = f.GetSparseRows("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestGetRichTextRows(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellRichText("Sheet1", "B1", []RichTextRun{
+		{Text: "bold", Font: &Font{Bold: true, Color: "2354E8"}},
+		{Text: " plain"},
+	}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "text"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", 1.5))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D3", true))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A5", ""))
+	runs, err := f.GetCellRichText("Sheet1", "B1")
+	assert.NoError(t, err)
+	rows, err := f.GetRichTextRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][][]RichTextRun{
+		{{{Text: "text"}}, runs},
+		nil,
+		{nil, nil, {{Text: "1.5"}}, {{Text: "1"}}},
+	}, rows)
+	assert.True(t, rows[0][1][0].Font.Bold)
+	assert.Nil(t, rows[0][1][1].Font)
+	// Test get rich text rows with the inline string and invalid shared
+	// string index.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0] = xlsxC{R: "A1", T: "inlineStr", IS: &xlsxSI{T: &xlsxT{Val: "inline"}}}
+	ws.SheetData.Row[0].C[1].V = "x"
+	rows, err = f.GetRichTextRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]RichTextRun{{{Text: "inline"}}}, rows[0])
+	// Test get rich text rows on not exists worksheet.
+	_, err = f.GetRichTextRows("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestCellsWithMeta(t *testing.T) {
+	f := NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" s="1"><v>1234567.8912345678</v></c><c r="C1" t="b"><v>1</v></c><c r="D1" t="e"><v>#DIV/0!</v></c><c r="E1"/><c r="F1" s="2"/></row>`+
+		`<row r="3"><c t="inlineStr"><is><t>inline</t></is></c><c><f t="shared" ref="B3:C3" si="0">A3&amp;1</f><v></v></c><c t="str"><f t="shared" si="0"/><v>x</v></c><c t="d"><v>2021-01-01T00:00:00</v></c></row>`+
+		`</sheetData></worksheet>`))
+	f.SharedStrings = &xlsxSST{Count: 1, UniqueCount: 1, SI: []xlsxSI{{T: &xlsxT{Val: "shared"}}}}
+	style, err := f.NewStyle(&Style{NumFmt: 3})
+	assert.NoError(t, err)
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, f.Styles.CellXfs.Xf[style])
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]RowCell
+	for rows.Next() {
+		cells, err := rows.CellsWithMeta()
+		assert.NoError(t, err)
+		results = append(results, cells)
+	}
+	assert.Equal(t, [][]RowCell{
+		{
+			{Cell: "A1", Col: 1, Type: CellTypeSharedString, Value: "shared", FormattedValue: "shared"},
+			{Cell: "B1", Col: 2, Type: CellTypeNumber, Value: "1234567.8912345678", FormattedValue: "1234567", StyleID: 1},
+			{Cell: "C1", Col: 3, Type: CellTypeBool, Value: "1", FormattedValue: "1"},
+			{Cell: "D1", Col: 4, Type: CellTypeError, Value: "#DIV/0!", FormattedValue: "#DIV/0!"},
+			{Cell: "F1", Col: 6, Type: CellTypeUnset, StyleID: 2},
+		},
+		nil,
+		{
+			{Cell: "A3", Col: 1, Type: CellTypeInlineString, Value: "inline", FormattedValue: "inline"},
+			{Cell: "B3", Col: 2, Type: CellTypeUnset, Formula: "A3&1"},
+			{Cell: "C3", Col: 3, Type: CellTypeFormula, Value: "x", FormattedValue: "x", Formula: "A3&1"},
+			{Cell: "D3", Col: 4, Type: CellTypeDate, Value: "2021-01-01T00:00:00", FormattedValue: "2021-01-01T00:00:00"},
+		},
+	}, results)
+
+	// Test get cells with meta data with invalid cell reference.
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A"><v>1</v></c></row></sheetData></worksheet>`))
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.CellsWithMeta()
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestAppendSpace(t *testing.T) {
+	assert.Equal(t, []string{"A"}, appendSpace(1, []string{"A"}))
+	assert.Equal(t, []string{"A", "", ""}, appendSpace(3, []string{"A"}))
+	s := make([]string, 1, 4)
+	assert.Equal(t, []string{"", ""}, appendSpace(2, s))
+}
+
+// prepareSparseWideSheet provides a function to create the workbook with the
+// sparse wide worksheet, which has one cell in each row on the diagonal.
+func prepareSparseWideSheet() *File {
+	f := NewFile()
+	var sb strings.Builder
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for row := 1; row <= 100; row++ {
+		cell, _ := CoordinatesToCellName(row*100, row)
+		sb.WriteString(fmt.Sprintf(`<row r="%d"><c r="%s"><v>%d</v></c></row>`, row, cell, row))
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(sb.String()))
+	return f
+}
+
+func BenchmarkGetRows(b *testing.B) {
+	f := prepareSparseWideSheet()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = f.GetRows("Sheet1")
+	}
+}
+
+func BenchmarkGetSparseRows(b *testing.B) {
+	f := prepareSparseWideSheet()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = f.GetSparseRows("Sheet1")
+	}
+}
+
 func BenchmarkRows(b *testing.B) {
 	f, _ := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	for i := 0; i < b.N; i++ {
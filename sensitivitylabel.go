@@ -0,0 +1,290 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultXMLPathCustomProperties defined the default path of the custom
+	// document properties part.
+	defaultXMLPathCustomProperties = "docProps/custom.xml"
+	// defaultXMLPathLabelInfo defined the default path of the sensitivity
+	// label metadata part.
+	defaultXMLPathLabelInfo = "docMetadata/LabelInfo.xml"
+	// customPropertiesFmtID defined the format identifier of the user defined
+	// custom document properties.
+	customPropertiesFmtID = "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}"
+	// sensitivityLabelPrefix defined the name prefix of the custom properties
+	// which store the sensitivity labels.
+	sensitivityLabelPrefix = "MSIP_Label_"
+)
+
+// getPackagePartPath provides a function to get the path of the package level
+// part by given relationship type from the package relationships.
+func (f *File) getPackagePartPath(relType string) string {
+	if rels := f.relsReader("_rels/.rels"); rels != nil {
+		rels.Lock()
+		defer rels.Unlock()
+		for _, rel := range rels.Relationships {
+			if rel.Type == relType {
+				return strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+	return ""
+}
+
+// customPropertiesReader provides a function to get the pointer to the
+// structure after deserialization of the custom document properties part.
+func (f *File) customPropertiesReader(propsPath string) (*decodeDocCustomProperties, error) {
+	props := new(decodeDocCustomProperties)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(propsPath)))).
+		Decode(props); err != nil && err != io.EOF {
+		return props, err
+	}
+	return props, nil
+}
+
+// labelInfoReader provides a function to get the pointer to the structure
+// after deserialization of the sensitivity label metadata part.
+func (f *File) labelInfoReader(labelInfoPath string) (*decodeClblLabelList, error) {
+	labelList := new(decodeClblLabelList)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(labelInfoPath)))).
+		Decode(labelList); err != nil && err != io.EOF {
+		return labelList, err
+	}
+	return labelList, nil
+}
+
+// normalizeLabelID provides a function to convert the sensitivity label or
+// tenant identifier to the lower case GUID without braces.
+func normalizeLabelID(id string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(id), "{"), "}"))
+}
+
+// parseLabelProperty provides a function to split the name of the sensitivity
+// label custom property into the label identifier and the setting name.
+func parseLabelProperty(name string) (string, string, bool) {
+	if !strings.HasPrefix(name, sensitivityLabelPrefix) {
+		return "", "", false
+	}
+	name = strings.TrimPrefix(name, sensitivityLabelPrefix)
+	idx := strings.LastIndex(name, "_")
+	if idx <= 0 {
+		return "", "", false
+	}
+	return normalizeLabelID(name[:idx]), name[idx+1:], true
+}
+
+// GetSensitivityLabels provides a function to get the Microsoft Information
+// Protection (MIP) sensitivity labels applied to the workbook. The labels are
+// read from the sensitivity label metadata part and the MSIP_Label_ custom
+// document properties. All of these parts will be preserved on saving the
+// workbook, so the processed documents keep their classification. For
+// example:
+//
+//	labels, err := f.GetSensitivityLabels()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, label := range labels {
+//	    fmt.Println(label.ID, label.Name, label.Enabled)
+//	}
+func (f *File) GetSensitivityLabels() ([]SensitivityLabel, error) {
+	var labels []SensitivityLabel
+	idx := map[string]int{}
+	if labelInfoPath := f.getPackagePartPath(SourceRelationshipClassificationLabels); labelInfoPath != "" {
+		labelList, err := f.labelInfoReader(labelInfoPath)
+		if err != nil {
+			return labels, err
+		}
+		for _, l := range labelList.Label {
+			label := SensitivityLabel{ID: normalizeLabelID(l.ID), SiteID: normalizeLabelID(l.SiteID), Method: l.Method}
+			label.Enabled, _ = strconv.ParseBool(l.Enabled)
+			label.Removed, _ = strconv.ParseBool(l.Removed)
+			label.ContentBits, _ = strconv.Atoi(l.ContentBits)
+			idx[label.ID] = len(labels)
+			labels = append(labels, label)
+		}
+	}
+	if propsPath := f.getPackagePartPath(SourceRelationshipCustomProperties); propsPath != "" {
+		props, err := f.customPropertiesReader(propsPath)
+		if err != nil {
+			return labels, err
+		}
+		for _, p := range props.Property {
+			id, setting, ok := parseLabelProperty(p.Name)
+			if !ok {
+				continue
+			}
+			i, ok := idx[id]
+			if !ok {
+				i, idx[id] = len(labels), len(labels)
+				labels = append(labels, SensitivityLabel{ID: id})
+			}
+			setLabelProperty(&labels[i], setting, p.Lpwstr)
+		}
+	}
+	return labels, nil
+}
+
+// setLabelProperty provides a function to set the sensitivity label setting
+// by given custom property setting name and value.
+func setLabelProperty(label *SensitivityLabel, setting, value string) {
+	switch setting {
+	case "Enabled":
+		label.Enabled, _ = strconv.ParseBool(value)
+	case "SetDate":
+		label.SetDate = value
+	case "Method":
+		label.Method = value
+	case "Name":
+		label.Name = value
+	case "SiteId":
+		label.SiteID = normalizeLabelID(value)
+	case "ActionId":
+		label.ActionID = value
+	case "ContentBits":
+		label.ContentBits, _ = strconv.Atoi(value)
+	}
+}
+
+// SetSensitivityLabel provides a function to apply or update a Microsoft
+// Information Protection (MIP) sensitivity label on the workbook. The label
+// identifier and the tenant identifier (SiteID) are required, the supported
+// Method are "Standard" (default) and "Privileged". The label will be written
+// into both the sensitivity label metadata part and the MSIP_Label_ custom
+// document properties, other custom properties will be preserved. For
+// example, apply the label "Confidential" of the tenant:
+//
+//	err := f.SetSensitivityLabel(&excelize.SensitivityLabel{
+//	    ID:      "3a8b6f0e-4c2d-4d1b-9c5f-2b7e8a1d6c90",
+//	    SiteID:  "72f988bf-86f1-41af-91ab-2d7cd011db47",
+//	    Name:    "Confidential",
+//	    Enabled: true,
+//	    SetDate: "2021-06-01T08:00:00Z",
+//	})
+func (f *File) SetSensitivityLabel(label *SensitivityLabel) error {
+	if label == nil || normalizeLabelID(label.ID) == "" || normalizeLabelID(label.SiteID) == "" {
+		return ErrParameterRequired
+	}
+	opt := *label
+	opt.ID, opt.SiteID = normalizeLabelID(opt.ID), normalizeLabelID(opt.SiteID)
+	if opt.Method == "" {
+		opt.Method = "Standard"
+	}
+	if opt.Method != "Standard" && opt.Method != "Privileged" {
+		return ErrParameterInvalid
+	}
+	if err := f.setLabelInfo(&opt); err != nil {
+		return err
+	}
+	return f.setLabelCustomProperties(&opt)
+}
+
+// setLabelInfo provides a function to add or update the sensitivity label in
+// the sensitivity label metadata part.
+func (f *File) setLabelInfo(opt *SensitivityLabel) error {
+	labelInfoPath := f.getPackagePartPath(SourceRelationshipClassificationLabels)
+	if labelInfoPath == "" {
+		labelInfoPath = defaultXMLPathLabelInfo
+		f.addRels("_rels/.rels", SourceRelationshipClassificationLabels, labelInfoPath, "")
+		f.setContentTypes("/"+labelInfoPath, ContentTypeClassificationLabels)
+	}
+	labelList, err := f.labelInfoReader(labelInfoPath)
+	if err != nil {
+		return err
+	}
+	boolStr := map[bool]string{true: "1", false: "0"}
+	label := &xlsxClblLabel{
+		ID:          "{" + opt.ID + "}",
+		Enabled:     boolStr[opt.Enabled],
+		Method:      opt.Method,
+		SiteID:      "{" + opt.SiteID + "}",
+		ContentBits: strconv.Itoa(opt.ContentBits),
+		Removed:     boolStr[opt.Removed],
+	}
+	content := xlsxClblLabelList{XMLNSClbl: NameSpaceMIPLabelMetadata, ExtLst: labelList.ExtLst}
+	var found bool
+	for _, l := range labelList.Label {
+		if normalizeLabelID(l.ID) == opt.ID {
+			l, found = label, true
+		}
+		content.Label = append(content.Label, l)
+	}
+	if !found {
+		content.Label = append(content.Label, label)
+	}
+	output, err := xml.Marshal(content)
+	f.saveFileList(labelInfoPath, output)
+	return err
+}
+
+// setLabelCustomProperties provides a function to add or update the custom
+// document properties of the sensitivity label.
+func (f *File) setLabelCustomProperties(opt *SensitivityLabel) error {
+	propsPath := f.getPackagePartPath(SourceRelationshipCustomProperties)
+	if propsPath == "" {
+		propsPath = defaultXMLPathCustomProperties
+		f.addRels("_rels/.rels", SourceRelationshipCustomProperties, propsPath, "")
+		f.setContentTypes("/"+propsPath, ContentTypeCustomProperties)
+	}
+	props, err := f.customPropertiesReader(propsPath)
+	if err != nil {
+		return err
+	}
+	content := xlsxDocCustomProperties{XMLNS: NameSpaceCustomProperties, XMLNSVT: NameSpaceDocPropsVTypes}
+	pid := 1
+	for _, p := range props.Property {
+		if id, _, ok := parseLabelProperty(p.Name); ok && id == opt.ID {
+			continue
+		}
+		if p.PID > pid {
+			pid = p.PID
+		}
+		content.Property = append(content.Property, &xlsxDocCustomProperty{FmtID: p.FmtID, PID: p.PID, Name: p.Name, Value: p.Content})
+	}
+	for _, setting := range [][]string{
+		{"Enabled", strconv.FormatBool(opt.Enabled)},
+		{"SetDate", opt.SetDate},
+		{"Method", opt.Method},
+		{"Name", opt.Name},
+		{"SiteId", opt.SiteID},
+		{"ActionId", opt.ActionID},
+		{"ContentBits", strconv.Itoa(opt.ContentBits)},
+	} {
+		if setting[1] == "" {
+			continue
+		}
+		var value bytes.Buffer
+		if err = xml.EscapeText(&value, []byte(setting[1])); err != nil {
+			return err
+		}
+		pid++
+		content.Property = append(content.Property, &xlsxDocCustomProperty{
+			FmtID: customPropertiesFmtID,
+			PID:   pid,
+			Name:  sensitivityLabelPrefix + opt.ID + "_" + setting[0],
+			Value: "<vt:lpwstr>" + value.String() + "</vt:lpwstr>",
+		})
+	}
+	output, err := xml.Marshal(content)
+	f.saveFileList(propsPath, output)
+	return err
+}
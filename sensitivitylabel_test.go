@@ -0,0 +1,103 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensitivityLabel(t *testing.T) {
+	f := NewFile()
+	labels, err := f.GetSensitivityLabels()
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+
+	assert.EqualError(t, f.SetSensitivityLabel(nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetSensitivityLabel(&SensitivityLabel{ID: "{}"}), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetSensitivityLabel(&SensitivityLabel{ID: "a", SiteID: "b", Method: "Unknown"}), ErrParameterInvalid.Error())
+
+	// Test apply the label on the workbook with a user defined custom property.
+	f.Pkg.Store(defaultXMLPathCustomProperties, []byte(`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Project"><vt:i4>42</vt:i4></property></Properties>`))
+	f.addRels("_rels/.rels", SourceRelationshipCustomProperties, defaultXMLPathCustomProperties, "")
+	f.setContentTypes("/"+defaultXMLPathCustomProperties, ContentTypeCustomProperties)
+	assert.NoError(t, f.SetSensitivityLabel(&SensitivityLabel{
+		ID:      "{3A8B6F0E-4C2D-4D1B-9C5F-2B7E8A1D6C90}",
+		SiteID:  "72f988bf-86f1-41af-91ab-2d7cd011db47",
+		Name:    "Confidential & Internal",
+		Enabled: true,
+		SetDate: "2021-06-01T08:00:00Z",
+	}))
+	assert.NoError(t, f.SetSensitivityLabel(&SensitivityLabel{
+		ID:          "9c1e2d3f-0000-4a4a-8b8b-123456789abc",
+		SiteID:      "72f988bf-86f1-41af-91ab-2d7cd011db47",
+		Method:      "Privileged",
+		ContentBits: 2,
+		Removed:     true,
+	}))
+	file := filepath.Join("test", "TestSensitivityLabel.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	labels, err = f.GetSensitivityLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, []SensitivityLabel{
+		{ID: "3a8b6f0e-4c2d-4d1b-9c5f-2b7e8a1d6c90", SiteID: "72f988bf-86f1-41af-91ab-2d7cd011db47", Name: "Confidential & Internal", Method: "Standard", Enabled: true, SetDate: "2021-06-01T08:00:00Z"},
+		{ID: "9c1e2d3f-0000-4a4a-8b8b-123456789abc", SiteID: "72f988bf-86f1-41af-91ab-2d7cd011db47", Method: "Privileged", Removed: true, ContentBits: 2},
+	}, labels)
+	content, ok := f.Pkg.Load(defaultXMLPathCustomProperties)
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Project"><vt:i4>42</vt:i4></property>`)
+	assert.Contains(t, string(content.([]byte)), `name="MSIP_Label_3a8b6f0e-4c2d-4d1b-9c5f-2b7e8a1d6c90_Name"><vt:lpwstr>Confidential &amp; Internal</vt:lpwstr>`)
+	content, ok = f.Pkg.Load(defaultXMLPathLabelInfo)
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<clbl:label id="{3a8b6f0e-4c2d-4d1b-9c5f-2b7e8a1d6c90}" enabled="1" method="Standard" siteId="{72f988bf-86f1-41af-91ab-2d7cd011db47}" contentBits="0" removed="0"></clbl:label>`)
+
+	// Test update the label and keep the label metadata on saving the
+	// workbook without changing the labels.
+	assert.NoError(t, f.SetSensitivityLabel(&SensitivityLabel{
+		ID:      "3a8b6f0e-4c2d-4d1b-9c5f-2b7e8a1d6c90",
+		SiteID:  "72f988bf-86f1-41af-91ab-2d7cd011db47",
+		Name:    "Public",
+		Enabled: true,
+	}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "label"))
+	assert.NoError(t, f.SaveAs(file))
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	labels, err = f.GetSensitivityLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "Public", labels[0].Name)
+	assert.Empty(t, labels[0].SetDate)
+	var count int
+	for _, rel := range f.relsReader("_rels/.rels").Relationships {
+		if rel.Type == SourceRelationshipCustomProperties || rel.Type == SourceRelationshipClassificationLabels {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	// Test get and set the labels with unsupported charset.
+	f.Pkg.Store(defaultXMLPathLabelInfo, MacintoshCyrillicCharset)
+	_, err = f.GetSensitivityLabels()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetSensitivityLabel(&labels[0]), "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Delete(defaultXMLPathLabelInfo)
+	f.Pkg.Store(defaultXMLPathCustomProperties, MacintoshCyrillicCharset)
+	_, err = f.GetSensitivityLabels()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetSensitivityLabel(&labels[0]), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestParseLabelProperty(t *testing.T) {
+	for _, name := range []string{"Project", "MSIP_Label_", "MSIP_Label__Name"} {
+		_, _, ok := parseLabelProperty(name)
+		assert.False(t, ok, name)
+	}
+	id, setting, ok := parseLabelProperty("MSIP_Label_{ABC}_SiteId")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+	assert.Equal(t, "SiteId", setting)
+}
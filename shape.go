@@ -265,6 +265,9 @@ func (f *File) AddShape(sheet, cell, format string) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	// Add first shape for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
@@ -404,7 +407,7 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 			text = " "
 		}
 		paragraph := &aP{
-			R: &aR{
+			R: []*aR{{
 				RPr: aRPr{
 					I:       p.Font.Italic,
 					B:       p.Font.Bold,
@@ -415,14 +418,14 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 					Latin:   &aLatin{Typeface: p.Font.Family},
 				},
 				T: text,
-			},
+			}},
 			EndParaRPr: &aEndParaRPr{
 				Lang: "en-US",
 			},
 		}
 		srgbClr := strings.Replace(strings.ToUpper(p.Font.Color), "#", "", -1)
 		if len(srgbClr) == 6 {
-			paragraph.R.RPr.SolidFill = &aSolidFill{
+			paragraph.R[0].RPr.SolidFill = &aSolidFill{
 				SrgbClr: &attrValString{
 					Val: stringPtr(srgbClr),
 				},
@@ -460,3 +463,23 @@ func setShapeRef(color string, i int) *aRef {
 		},
 	}
 }
+
+// DeleteShape provides a function to delete shapes in spreadsheet by given
+// worksheet and cell name.
+func (f *File) DeleteShape(sheet, cell string) (err error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return
+	}
+	col--
+	row--
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return
+	}
+	if ws.Drawing == nil {
+		return
+	}
+	drawingXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl", -1)
+	return f.deleteDrawing(col, row, drawingXML, "Shape")
+}
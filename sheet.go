@@ -76,6 +76,10 @@ func (f *File) contentTypesReader() *xlsxTypes {
 			Decode(f.ContentTypes); err != nil && err != io.EOF {
 			log.Printf("xml decode error: %s", err)
 		}
+		for idx := range f.ContentTypes.Overrides {
+			override := &f.ContentTypes.Overrides[idx]
+			override.PartName, override.ContentType = f.intern(override.PartName), f.intern(override.ContentType)
+		}
 	}
 
 	return f.ContentTypes
@@ -159,6 +163,7 @@ func (f *File) workSheetWriter() {
 			sheet := ws.(*xlsxWorksheet)
 			for k, v := range sheet.SheetData.Row {
 				sheet.SheetData.Row[k].C = trimCell(v.C)
+				sheet.SheetData.Row[k].prepareCells()
 			}
 			if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
 				f.addNameSpaces(p.(string), SourceRelationship)
@@ -289,8 +294,8 @@ func (f *File) SetActiveSheet(index int) {
 	for idx, name := range f.GetSheetList() {
 		ws, err := f.workSheetReader(name)
 		if err != nil {
-			// Chartsheet or dialogsheet
-			return
+			// Chartsheet, dialogsheet or macrosheet
+			continue
 		}
 		if ws.SheetViews == nil {
 			ws.SheetViews = &xlsxSheetViews{
@@ -362,6 +367,7 @@ func (f *File) SetSheetName(oldName, newName string) {
 			content.Sheets.Sheet[k].Name = newName
 			f.sheetMap[newName] = f.sheetMap[oldName]
 			delete(f.sheetMap, oldName)
+			f.updateAnnotationsSheet(oldName, newName)
 		}
 	}
 }
@@ -406,7 +412,9 @@ func (f *File) GetSheetIndex(name string) int {
 }
 
 // GetSheetMap provides a function to get worksheets, chart sheets, dialog
-// sheets ID and name map of the workbook. For example:
+// sheets ID and name map of the workbook. The iteration order of the map is
+// not specified, use GetSheets to get the sheets in the tab order. For
+// example:
 //
 //    f, err := excelize.OpenFile("Book1.xlsx")
 //    if err != nil {
@@ -446,17 +454,9 @@ func (f *File) getSheetMap() map[string]string {
 	for _, v := range f.workbookReader().Sheets.Sheet {
 		for _, rel := range f.relsReader(f.getWorkbookRelsPath()).Relationships {
 			if rel.ID == v.ID {
-				// Construct a target XML as xl/worksheets/sheet%d by split
-				// path, compatible with different types of relative paths in
-				// workbook.xml.rels, for example: worksheets/sheet%d.xml
-				// and /xl/worksheets/sheet%d.xml
-				path := filepath.ToSlash(strings.TrimPrefix(
-					strings.Replace(filepath.Clean(fmt.Sprintf("%s/%s", filepath.Dir(f.getWorkbookPath()), rel.Target)), "\\", "/", -1), "/"))
-				if strings.HasPrefix(rel.Target, "/") {
-					path = filepath.ToSlash(strings.TrimPrefix(strings.Replace(filepath.Clean(rel.Target), "\\", "/", -1), "/"))
-				}
+				path := f.getWorkbookRelsTargetPath(rel.Target)
 				if _, ok := f.Pkg.Load(path); ok {
-					maps[v.Name] = path
+					maps[v.Name] = f.intern(path)
 				}
 			}
 		}
@@ -464,6 +464,78 @@ func (f *File) getSheetMap() map[string]string {
 	return maps
 }
 
+// getWorkbookRelsTargetPath provides a function to get the part path in the
+// package by given relationship target in the workbook relationships.
+func (f *File) getWorkbookRelsTargetPath(target string) string {
+	// Construct a target XML as xl/worksheets/sheet%d by split path,
+	// compatible with different types of relative paths in
+	// workbook.xml.rels, for example: worksheets/sheet%d.xml and
+	// /xl/worksheets/sheet%d.xml
+	if strings.HasPrefix(target, "/") {
+		return filepath.ToSlash(strings.TrimPrefix(strings.Replace(filepath.Clean(target), "\\", "/", -1), "/"))
+	}
+	return filepath.ToSlash(strings.TrimPrefix(
+		strings.Replace(filepath.Clean(fmt.Sprintf("%s/%s", filepath.Dir(f.getWorkbookPath()), target)), "\\", "/", -1), "/"))
+}
+
+// getSheetType provides a function to get the type of the sheet ("worksheet",
+// "chartsheet", "dialogsheet" or "macrosheet") by given part path of the
+// sheet, an empty string will be returned if the part is not a sheet of the
+// workbook.
+func (f *File) getSheetType(partPath string) string {
+	for _, info := range f.GetSheets() {
+		if info.Path == partPath {
+			return info.Type
+		}
+	}
+	return ""
+}
+
+// GetSheets provides a function to get settings of the worksheets, chart
+// sheets and dialog sheets in the workbook in the tab order, include the tab
+// position, sheet name, sheet ID, relationship ID, visibility state ("visible",
+// "hidden" or "veryHidden"), sheet type ("worksheet", "chartsheet",
+// "dialogsheet" or "macrosheet") and the part path in the package. Unlike
+// GetSheetMap, the returned slice keeps the order of the sheet tabs. For
+// example:
+//
+//    for _, sheet := range f.GetSheets() {
+//        fmt.Println(sheet.Index, sheet.Name, sheet.SheetID, sheet.State)
+//    }
+//
+func (f *File) GetSheets() []SheetInfo {
+	var (
+		sheets []SheetInfo
+		wb     = f.workbookReader()
+		rels   = f.relsReader(f.getWorkbookRelsPath())
+		types  = map[string]string{
+			SourceRelationshipWorkSheet:   "worksheet",
+			SourceRelationshipChartsheet:  "chartsheet",
+			SourceRelationshipDialogsheet: "dialogsheet",
+			SourceRelationshipMacrosheet:  "macrosheet",
+		}
+	)
+	if wb == nil {
+		return sheets
+	}
+	for idx, sheet := range wb.Sheets.Sheet {
+		info := SheetInfo{Index: idx, Name: sheet.Name, SheetID: sheet.SheetID, RID: sheet.ID, State: sheet.State}
+		if info.State == "" {
+			info.State = "visible"
+		}
+		if rels != nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID == sheet.ID {
+					info.Type, info.Path = types[rel.Type], f.getWorkbookRelsTargetPath(rel.Target)
+					break
+				}
+			}
+		}
+		sheets = append(sheets, info)
+	}
+	return sheets
+}
+
 // SetSheetBackground provides a function to set background picture by given
 // worksheet name and file path.
 func (f *File) SetSheetBackground(sheet, picture string) error {
@@ -532,6 +604,7 @@ func (f *File) DeleteSheet(name string) {
 			target := f.deleteSheetFromWorkbookRels(sheet.ID)
 			f.deleteSheetFromContentTypes(target)
 			f.deleteCalcChain(sheet.SheetID, "")
+			f.updateAnnotationsSheet(sheet.Name, "")
 			delete(f.sheetMap, sheet.Name)
 			f.Pkg.Delete(sheetXML)
 			f.Pkg.Delete(rels)
@@ -653,7 +726,13 @@ func (f *File) SetSheetVisible(name string, visible bool) error {
 	for k, v := range content.Sheets.Sheet {
 		xlsx, err := f.workSheetReader(v.Name)
 		if err != nil {
-			return err
+			if _, ok := err.(ErrNotWorksheet); !ok {
+				return err
+			}
+			if v.Name == name && count > 1 {
+				content.Sheets.Sheet[k].State = "hidden"
+			}
+			continue
 		}
 		tabSelected := false
 		if len(xlsx.SheetViews.SheetView) > 0 {
@@ -791,6 +870,43 @@ func (f *File) SetPanes(sheet, panes string) error {
 	return err
 }
 
+// SetHeaderRow provides a function to write the header row of a report by
+// given worksheet name, header values and style ID. The values will be
+// written to the first row start with the cell A1, the top row will be
+// frozen and an auto filter will be added across the header cells. If the
+// style ID is 0, a style with bold font will be created and applied to the
+// header cells, otherwise a copy of the given style with bold font will be
+// applied. For example, create a header row on Sheet1:
+//
+//    err := f.SetHeaderRow("Sheet1", []interface{}{"Name", "Region", "Sales"}, 0)
+//
+func (f *File) SetHeaderRow(sheet string, values []interface{}, style int) error {
+	if len(values) == 0 {
+		return ErrParameterRequired
+	}
+	if err := f.SetSheetRow(sheet, "A1", &values); err != nil {
+		return err
+	}
+	lastCell, err := CoordinatesToCellName(len(values), 1)
+	if err != nil {
+		return err
+	}
+	if style == 0 {
+		if style, err = f.NewStyle(&Style{Font: &Font{Bold: true}}); err != nil {
+			return err
+		}
+	} else {
+		style = f.getBoldStyle(style)
+	}
+	if err = f.SetCellStyle(sheet, "A1", lastCell, style); err != nil {
+		return err
+	}
+	if err = f.SetPanes(sheet, `{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft","panes":[{"sqref":"A2","active_cell":"A2","pane":"bottomLeft"}]}`); err != nil {
+		return err
+	}
+	return f.AutoFilter(sheet, "A1", lastCell, "")
+}
+
 // GetSheetVisible provides a function to get worksheet visible by given worksheet
 // name. For example, get visible state of Sheet1:
 //
@@ -918,7 +1034,8 @@ func attrValToInt(name string, attrs []xml.Attr) (val int, err error) {
 }
 
 // SetHeaderFooter provides a function to set headers and footers by given
-// worksheet name and the control characters.
+// worksheet, chart sheet or dialog sheet name and the control characters. Use
+// AddHeaderFooterImage to add pictures which referenced by the &G code.
 //
 // Headers and footers are specified using the following settings fields:
 //
@@ -1034,44 +1151,49 @@ func attrValToInt(name string, attrs []xml.Attr) (val int, err error) {
 // - No footer on the first page
 //
 func (f *File) SetHeaderFooter(sheet string, settings *FormatHeaderFooter) error {
-	ws, err := f.workSheetReader(sheet)
-	if err != nil {
-		return err
-	}
-	if settings == nil {
-		ws.HeaderFooter = nil
-		return err
-	}
+	return f.setSheetHeaderFooter(sheet, func(headerFooter **xlsxHeaderFooter, _ **xlsxLegacyDrawingHF) error {
+		if settings == nil {
+			*headerFooter = nil
+			return nil
+		}
 
-	v := reflect.ValueOf(*settings)
-	// Check 6 string type fields: OddHeader, OddFooter, EvenHeader, EvenFooter,
-	// FirstFooter, FirstHeader
-	for i := 4; i < v.NumField()-1; i++ {
-		if v.Field(i).Len() >= 255 {
-			return fmt.Errorf("field %s must be less than 255 characters", v.Type().Field(i).Name)
+		v := reflect.ValueOf(*settings)
+		// Check 6 string type fields: OddHeader, OddFooter, EvenHeader, EvenFooter,
+		// FirstFooter, FirstHeader
+		for i := 4; i < v.NumField()-1; i++ {
+			if v.Field(i).Len() >= 255 {
+				return fmt.Errorf("field %s must be less than 255 characters", v.Type().Field(i).Name)
+			}
 		}
-	}
-	ws.HeaderFooter = &xlsxHeaderFooter{
-		AlignWithMargins: settings.AlignWithMargins,
-		DifferentFirst:   settings.DifferentFirst,
-		DifferentOddEven: settings.DifferentOddEven,
-		ScaleWithDoc:     settings.ScaleWithDoc,
-		OddHeader:        settings.OddHeader,
-		OddFooter:        settings.OddFooter,
-		EvenHeader:       settings.EvenHeader,
-		EvenFooter:       settings.EvenFooter,
-		FirstFooter:      settings.FirstFooter,
-		FirstHeader:      settings.FirstHeader,
-	}
-	return err
+		*headerFooter = &xlsxHeaderFooter{
+			AlignWithMargins: settings.AlignWithMargins,
+			DifferentFirst:   settings.DifferentFirst,
+			DifferentOddEven: settings.DifferentOddEven,
+			ScaleWithDoc:     settings.ScaleWithDoc,
+			OddHeader:        settings.OddHeader,
+			OddFooter:        settings.OddFooter,
+			EvenHeader:       settings.EvenHeader,
+			EvenFooter:       settings.EvenFooter,
+			FirstFooter:      settings.FirstFooter,
+			FirstHeader:      settings.FirstHeader,
+		}
+		return nil
+	})
 }
 
 // ProtectSheet provides a function to prevent other users from accidentally
-// or deliberately changing, moving, or deleting data in a worksheet. For
-// example, protect Sheet1 with protection settings:
+// or deliberately changing, moving, or deleting data in a worksheet. The
+// EditObjects and EditScenarios specifies if the users are allowed to edit
+// the objects (such as pictures, charts, shapes and comments) and scenarios
+// of the protected worksheet, when the objects are not allowed to edit, the
+// AddChart, AddComment, AddPicture, AddPictureFromBytes, AddShape,
+// AddTimeline and CopySheet will return ErrProtectedObjects, unless the
+// IgnoreObjectsProtection option of the workbook is enabled. For example,
+// protect Sheet1 with protection settings and allow users to edit objects:
 //
 //    err := f.ProtectSheet("Sheet1", &excelize.FormatSheetProtection{
 //        Password:      "password",
+//        EditObjects:   true,
 //        EditScenarios: false,
 //    })
 //
@@ -1082,8 +1204,6 @@ func (f *File) ProtectSheet(sheet string, settings *FormatSheetProtection) error
 	}
 	if settings == nil {
 		settings = &FormatSheetProtection{
-			EditObjects:       true,
-			EditScenarios:     true,
 			SelectLockedCells: true,
 		}
 	}
@@ -1097,9 +1217,9 @@ func (f *File) ProtectSheet(sheet string, settings *FormatSheetProtection) error
 		InsertColumns:       settings.InsertColumns,
 		InsertHyperlinks:    settings.InsertHyperlinks,
 		InsertRows:          settings.InsertRows,
-		Objects:             settings.EditObjects,
+		Objects:             !settings.EditObjects,
 		PivotTables:         settings.PivotTables,
-		Scenarios:           settings.EditScenarios,
+		Scenarios:           !settings.EditScenarios,
 		SelectLockedCells:   settings.SelectLockedCells,
 		SelectUnlockedCells: settings.SelectUnlockedCells,
 		Sheet:               true,
@@ -1121,6 +1241,108 @@ func (f *File) UnprotectSheet(sheet string) error {
 	return err
 }
 
+// GetSheetProtection provides a function to get worksheet protection settings
+// by given worksheet name. The password of the protected worksheet can't be
+// retrieved, and the Password field of the returned settings will always be
+// empty. For example, check if the users are allowed to edit the objects on
+// Sheet1:
+//
+//    settings, protected, err := f.GetSheetProtection("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if protected {
+//        fmt.Println(settings.EditObjects)
+//    }
+//
+func (f *File) GetSheetProtection(sheet string) (FormatSheetProtection, bool, error) {
+	var settings FormatSheetProtection
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.SheetProtection == nil || !ws.SheetProtection.Sheet {
+		return settings, false, err
+	}
+	protection := ws.SheetProtection
+	settings = FormatSheetProtection{
+		AutoFilter:          protection.AutoFilter,
+		DeleteColumns:       protection.DeleteColumns,
+		DeleteRows:          protection.DeleteRows,
+		EditObjects:         !protection.Objects,
+		EditScenarios:       !protection.Scenarios,
+		FormatCells:         protection.FormatCells,
+		FormatColumns:       protection.FormatColumns,
+		FormatRows:          protection.FormatRows,
+		InsertColumns:       protection.InsertColumns,
+		InsertHyperlinks:    protection.InsertHyperlinks,
+		InsertRows:          protection.InsertRows,
+		PivotTables:         protection.PivotTables,
+		SelectLockedCells:   protection.SelectLockedCells,
+		SelectUnlockedCells: protection.SelectUnlockedCells,
+		Sort:                protection.Sort,
+	}
+	return settings, true, err
+}
+
+// WithSheetUnprotected provides a function to remove the protection of the
+// worksheet by given worksheet name and password, run the given function to
+// change the worksheet, and then restore the original protection settings of
+// the worksheet include the password, even if the function returns an error.
+// The function will be run directly if the worksheet is not protected, and
+// ErrUnprotectSheetPassword will be returned without running the function if
+// the password doesn't match the password of the protected worksheet. For
+// example, add a picture to the protected Sheet1 which objects are locked:
+//
+//    err := f.WithSheetUnprotected("Sheet1", "password", func() error {
+//        return f.AddPicture("Sheet1", "A2", "image.png", "")
+//    })
+//
+func (f *File) WithSheetUnprotected(sheet, password string, fn func() error) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.SheetProtection == nil || !ws.SheetProtection.Sheet {
+		return fn()
+	}
+	ok, err := verifySheetPasswd(ws.SheetProtection, password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnprotectSheetPassword
+	}
+	protection := *ws.SheetProtection
+	ws.SheetProtection = nil
+	defer func() { ws.SheetProtection = &protection }()
+	return fn()
+}
+
+// verifySheetPasswd provides a function to check if the password matches the
+// password hash of the sheet protection, the worksheet protected without the
+// password could be unprotected by any password.
+func verifySheetPasswd(protection *xlsxSheetProtection, password string) (bool, error) {
+	if protection.HashValue != "" {
+		hashValue, err := genISOPasswdHash(password, protection.AlgorithmName, protection.SaltValue, protection.SpinCount)
+		return hashValue == protection.HashValue, err
+	}
+	if protection.Password != "" {
+		return strings.EqualFold(genSheetPasswd(password), protection.Password), nil
+	}
+	return true, nil
+}
+
+// checkObjectsProtection provides a function to check if the drawing objects
+// and comments are allowed to add to the worksheet, the objects of the
+// worksheet will be locked when the sheet protection is enabled without
+// allowing to edit objects, unless the IgnoreObjectsProtection option is
+// enabled.
+func (f *File) checkObjectsProtection(ws *xlsxWorksheet) error {
+	if !f.ignoreObjsProt && ws.SheetProtection != nil && ws.SheetProtection.Sheet && ws.SheetProtection.Objects {
+		return ErrProtectedObjects
+	}
+	return nil
+}
+
 // trimSheetName provides a function to trim invaild characters by given worksheet
 // name.
 func trimSheetName(name string) string {
@@ -1476,11 +1698,26 @@ func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
 //        Scope:    "Sheet2",
 //    })
 //
+// The names are case-insensitive, and the built-in names _xlnm._FilterDatabase,
+// _xlnm.Consolidate_Area, _xlnm.Criteria, _xlnm.Extract, _xlnm.Print_Area,
+// _xlnm.Print_Titles and _xlnm.Sheet_Title could be specified with or
+// without the _xlnm. prefix. The built-in names must be scoped to a
+// worksheet, and the references without the sheet name will be relocated
+// to the worksheet of the scope. The _xlnm._FilterDatabase name is always
+// hidden. For example, set the print area of the worksheet:
+//
+//    f.SetDefinedName(&excelize.DefinedName{
+//        Name:     "Print_Area",
+//        RefersTo: "$A$1:$D$20",
+//        Scope:    "Sheet1",
+//    })
+//
 func (f *File) SetDefinedName(definedName *DefinedName) error {
 	wb := f.workbookReader()
 	d := xlsxDefinedName{
 		Name:    definedName.Name,
 		Comment: definedName.Comment,
+		Hidden:  definedName.Hidden,
 		Data:    definedName.RefersTo,
 	}
 	if definedName.Scope != "" {
@@ -1488,13 +1725,20 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 			d.LocalSheetID = &sheetIndex
 		}
 	}
+	if name, ok := getBuiltInDefinedName(definedName.Name); ok {
+		if d.LocalSheetID == nil {
+			return ErrDefinedNameBuiltInScope
+		}
+		d.Name, d.Data = name, relocateDefinedNameRefersTo(d.Data, definedName.Scope)
+		d.Hidden = d.Hidden || name == builtInDefinedNameFilterDatabase
+	}
 	if wb.DefinedNames != nil {
 		for _, dn := range wb.DefinedNames.DefinedName {
-			var scope string
+			scope := -1
 			if dn.LocalSheetID != nil {
-				scope = f.GetSheetName(*dn.LocalSheetID)
+				scope = *dn.LocalSheetID
 			}
-			if scope == definedName.Scope && dn.Name == definedName.Name {
+			if (d.LocalSheetID == nil && scope == -1 || d.LocalSheetID != nil && scope == *d.LocalSheetID) && isSameDefinedName(dn.Name, d.Name) {
 				return ErrDefinedNameduplicate
 			}
 		}
@@ -1509,7 +1753,8 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 
 // DeleteDefinedName provides a function to delete the defined names of the
 // workbook or worksheet. If not specified scope, the default scope is
-// workbook. For example:
+// workbook. The names are case-insensitive, and the built-in names could be
+// specified with or without the _xlnm. prefix. For example:
 //
 //    f.DeleteDefinedName(&excelize.DefinedName{
 //        Name:     "Amount",
@@ -1528,7 +1773,7 @@ func (f *File) DeleteDefinedName(definedName *DefinedName) error {
 			if dn.LocalSheetID != nil {
 				scope = f.GetSheetName(*dn.LocalSheetID)
 			}
-			if scope == deleteScope && dn.Name == definedName.Name {
+			if scope == deleteScope && isSameDefinedName(dn.Name, definedName.Name) {
 				wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:idx], wb.DefinedNames.DefinedName[idx+1:]...)
 				return nil
 			}
@@ -1549,6 +1794,7 @@ func (f *File) GetDefinedName() []DefinedName {
 				Comment:  dn.Comment,
 				RefersTo: dn.Data,
 				Scope:    "Workbook",
+				Hidden:   dn.Hidden,
 			}
 			if dn.LocalSheetID != nil && *dn.LocalSheetID >= 0 {
 				definedName.Scope = f.GetSheetName(*dn.LocalSheetID)
@@ -1559,6 +1805,60 @@ func (f *File) GetDefinedName() []DefinedName {
 	return definedNames
 }
 
+// Define the built-in defined names.
+const (
+	builtInDefinedNamePrefix         = "_xlnm."
+	builtInDefinedNameFilterDatabase = "_xlnm._FilterDatabase"
+)
+
+// builtInDefinedNames defined the list of the built-in defined names without
+// the _xlnm. prefix.
+var builtInDefinedNames = []string{"_FilterDatabase", "Consolidate_Area", "Criteria", "Extract", "Print_Area", "Print_Titles", "Sheet_Title"}
+
+// getBuiltInDefinedName provides a function to get the built-in defined name
+// with the _xlnm. prefix by given defined name with or without the prefix,
+// the second returned value indicates whether the name is a built-in name.
+// The name with the _xlnm. prefix is always treated as a built-in name.
+func getBuiltInDefinedName(name string) (string, bool) {
+	short := name
+	if len(name) >= len(builtInDefinedNamePrefix) && strings.EqualFold(name[:len(builtInDefinedNamePrefix)], builtInDefinedNamePrefix) {
+		short = name[len(builtInDefinedNamePrefix):]
+	}
+	for _, builtIn := range builtInDefinedNames {
+		if strings.EqualFold(short, builtIn) {
+			return builtInDefinedNamePrefix + builtIn, true
+		}
+	}
+	return builtInDefinedNamePrefix + short, short != name
+}
+
+// isSameDefinedName provides a function to check if the given defined names
+// are the same name, the names are case-insensitive and the built-in names
+// with or without the _xlnm. prefix are the same name.
+func isSameDefinedName(a, b string) bool {
+	if builtInA, ok := getBuiltInDefinedName(a); ok {
+		a = builtInA
+	}
+	if builtInB, ok := getBuiltInDefinedName(b); ok {
+		b = builtInB
+	}
+	return strings.EqualFold(a, b)
+}
+
+// relocateDefinedNameRefersTo provides a function to add the sheet name to
+// the references without the sheet name in the comma-separated references
+// of the defined name by given references and sheet name.
+func relocateDefinedNameRefersTo(refersTo, sheet string) string {
+	if refersTo == "" || strings.ContainsAny(refersTo, "!()\"") {
+		return refersTo
+	}
+	refs := strings.Split(strings.TrimPrefix(refersTo, "="), ",")
+	for i, ref := range refs {
+		refs[i] = quoteSheetName(sheet) + "!" + strings.TrimSpace(ref)
+	}
+	return strings.Join(refs, ",")
+}
+
 // GroupSheets provides a function to group worksheets by given worksheets
 // name. Group worksheets must contain an active worksheet.
 func (f *File) GroupSheets(sheets []string) error {
@@ -1604,7 +1904,10 @@ func (f *File) UngroupSheets() error {
 		if activeSheet == index {
 			continue
 		}
-		ws, _ := f.workSheetReader(sheet)
+		ws, err := f.workSheetReader(sheet)
+		if err != nil || ws.SheetViews == nil {
+			continue
+		}
 		sheetViews := ws.SheetViews.SheetView
 		if len(sheetViews) > 0 {
 			for idx := range sheetViews {
@@ -1736,6 +2039,7 @@ func (f *File) relsReader(path string) *xlsxRelationships {
 				Decode(&c); err != nil && err != io.EOF {
 				log.Printf("xml decode error: %s", err)
 			}
+			f.internRels(&c)
 			f.Relationships.Store(path, &c)
 		}
 	}
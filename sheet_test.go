@@ -105,6 +105,43 @@ func TestSetPane(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetPane.xlsx")))
 }
 
+func TestSetHeaderRow(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetHeaderRow("Sheet1", []interface{}{"Name", "Region", "Sales"}, 0))
+	val, err := f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales", val)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	pane := ws.SheetViews.SheetView[0].Pane
+	assert.Equal(t, "frozen", pane.State)
+	assert.Equal(t, float64(1), pane.YSplit)
+	assert.Equal(t, "$A$1:$C$1", ws.AutoFilter.Ref)
+	styleID, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.True(t, *f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[styleID].FontID].B.Val)
+	// Test set header row with given style
+	style, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetHeaderRow("Sheet2", []interface{}{"ID"}, style))
+	styleID, err = f.GetCellStyle("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, style, styleID)
+	assert.True(t, *f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[styleID].FontID].B.Val)
+	assert.Equal(t, f.Styles.CellXfs.Xf[style].FillID, f.Styles.CellXfs.Xf[styleID].FillID)
+	assert.Nil(t, f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[style].FontID].B)
+	// Test set header row with the style which has bold font
+	assert.NoError(t, f.SetHeaderRow("Sheet2", []interface{}{"ID"}, styleID))
+	boldStyleID, err := f.GetCellStyle("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, boldStyleID)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetHeaderRow.xlsx")))
+	// Test set header row with invalid parameters
+	assert.EqualError(t, f.SetHeaderRow("Sheet1", nil, 0), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetHeaderRow("SheetN", []interface{}{"ID"}, 0), "sheet SheetN is not exist")
+}
+
 func TestPageLayoutOption(t *testing.T) {
 	const sheet = "Sheet1"
 
@@ -259,7 +296,65 @@ func TestDefinedName(t *testing.T) {
 	}))
 	assert.Exactly(t, "Sheet1!$A$2:$D$5", f.GetDefinedName()[0].RefersTo)
 	assert.Exactly(t, 1, len(f.GetDefinedName()))
+	// Test the defined names are case-insensitive.
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{
+		Name:     "AMOUNT",
+		RefersTo: "Sheet1!$A$2:$D$5",
+		Scope:    "Sheet1",
+	}), ErrDefinedNameduplicate.Error())
+	// Test set hidden defined name.
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Secret",
+		RefersTo: "Sheet1!$A$1",
+		Hidden:   true,
+	}))
+	assert.True(t, f.GetDefinedName()[1].Hidden)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDefinedName.xlsx")))
+
+	// Test set built-in defined names.
+	f = NewFile()
+	f.NewSheet("Sheet 2")
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Print_Area",
+		RefersTo: "Sheet1!$A$1:$D$20",
+	}), ErrDefinedNameBuiltInScope.Error())
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "print_area",
+		RefersTo: "$A$1:$D$20",
+		Scope:    "Sheet1",
+	}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "_xlnm.Print_Titles",
+		RefersTo: "=$A:$A,$1:$1",
+		Scope:    "Sheet 2",
+	}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "_FilterDatabase",
+		RefersTo: "Sheet1!$A$1:$D$20",
+		Scope:    "Sheet1",
+	}))
+	for _, name := range []string{"Print_Area", "_xlnm.PRINT_AREA", "_xlnm._FilterDatabase"} {
+		assert.EqualError(t, f.SetDefinedName(&DefinedName{
+			Name:     name,
+			RefersTo: "Sheet1!$A$1:$B$2",
+			Scope:    "Sheet1",
+		}), ErrDefinedNameduplicate.Error())
+	}
+	assert.Equal(t, []DefinedName{
+		{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$D$20", Scope: "Sheet1"},
+		{Name: "_xlnm.Print_Titles", RefersTo: "'Sheet 2'!$A:$A,'Sheet 2'!$1:$1", Scope: "Sheet 2"},
+		{Name: "_xlnm._FilterDatabase", RefersTo: "Sheet1!$A$1:$D$20", Scope: "Sheet1", Hidden: true},
+	}, f.GetDefinedName())
+	// Test the auto filter reuses the built-in filter database name.
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1", "C10", ""))
+	assert.Len(t, f.GetDefinedName(), 3)
+	assert.Equal(t, "Sheet1!$A$1:$C$10", f.GetDefinedName()[2].RefersTo)
+	// Test delete built-in defined name without the prefix.
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "Print_Titles", Scope: "Sheet 2"}))
+	assert.Len(t, f.GetDefinedName(), 2)
+	// Test the unknown name with the prefix must be scoped to a worksheet.
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Unknown", RefersTo: "Sheet1!$A$1"}), ErrDefinedNameBuiltInScope.Error())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDefinedName2.xlsx")))
 }
 
 func TestGroupSheets(t *testing.T) {
@@ -343,6 +438,28 @@ func TestGetSheetMap(t *testing.T) {
 	assert.Equal(t, len(sheetMap), 2)
 }
 
+func TestGetSheets(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`))
+	f.SetSheetVisible("Sheet2", false)
+	// Move the new worksheet to the first tab position.
+	f.WorkBook.Sheets.Sheet[0], f.WorkBook.Sheets.Sheet[1] = f.WorkBook.Sheets.Sheet[1], f.WorkBook.Sheets.Sheet[0]
+	assert.Equal(t, []SheetInfo{
+		{Index: 0, Name: "Sheet2", SheetID: 2, RID: "rId4", State: "hidden", Type: "worksheet", Path: "xl/worksheets/sheet2.xml"},
+		{Index: 1, Name: "Sheet1", SheetID: 1, RID: "rId1", State: "visible", Type: "worksheet", Path: "xl/worksheets/sheet1.xml"},
+		{Index: 2, Name: "Chart1", SheetID: 3, RID: "rId5", State: "visible", Type: "chartsheet", Path: "xl/chartsheets/sheet3.xml"},
+	}, f.GetSheets())
+	// Test get sheets with absolute relationship target.
+	rels := f.relsReader(f.getWorkbookRelsPath())
+	rels.Relationships[0].Target = "/xl/worksheets/sheet1.xml"
+	assert.Equal(t, "xl/worksheets/sheet1.xml", f.GetSheets()[1].Path)
+	// Test get sheets without workbook relationships.
+	f.Relationships.Delete(f.getWorkbookRelsPath())
+	f.Pkg.Delete(f.getWorkbookRelsPath())
+	assert.Equal(t, SheetInfo{Index: 1, Name: "Sheet1", SheetID: 1, RID: "rId1", State: "visible"}, f.GetSheets()[1])
+}
+
 func TestSetActiveSheet(t *testing.T) {
 	f := NewFile()
 	f.WorkBook.BookViews = nil
@@ -435,3 +552,57 @@ func newSheetWithSave() {
 	}
 	_ = file.Save()
 }
+
+func TestDialogSheetAndMacroSheet(t *testing.T) {
+	f := NewFile()
+	parts := map[string][]byte{
+		"xl/dialogsheets/sheet1.xml": []byte(`<dialogsheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"/></sheetViews></dialogsheet>`),
+		"xl/macrosheets/sheet1.xml":  []byte(`<xm:macrosheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><sheetData><row r="1"><c r="A1"><f>HALT()</f></c></row></sheetData></xm:macrosheet>`),
+	}
+	wb := f.workbookReader()
+	for i, sheet := range []struct{ name, relType, target, partPath string }{
+		{"Dialog1", SourceRelationshipDialogsheet, "dialogsheets/sheet1.xml", "xl/dialogsheets/sheet1.xml"},
+		{"Macro1", SourceRelationshipMacrosheet, "macrosheets/sheet1.xml", "xl/macrosheets/sheet1.xml"},
+	} {
+		f.Pkg.Store(sheet.partPath, parts[sheet.partPath])
+		rID := f.addRels(f.getWorkbookRelsPath(), sheet.relType, sheet.target, "")
+		wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: sheet.name, SheetID: 10 + i, ID: "rId" + strconv.Itoa(rID)})
+		f.sheetMap[sheet.name] = sheet.partPath
+		f.SheetCount++
+	}
+	var types []string
+	for _, sheet := range f.GetSheets() {
+		types = append(types, sheet.Type)
+	}
+	assert.Equal(t, []string{"worksheet", "dialogsheet", "macrosheet"}, types)
+
+	// Test the worksheet functions return typed errors on the dialog sheet and
+	// macro sheet.
+	for sheet, sheetType := range map[string]string{"Dialog1": "dialogsheet", "Macro1": "macrosheet"} {
+		err := f.SetCellValue(sheet, "A1", 1)
+		assert.Equal(t, ErrNotWorksheet{SheetName: sheet, SheetType: sheetType}, err)
+		_, err = f.GetRows(sheet)
+		assert.IsType(t, ErrNotWorksheet{}, err)
+	}
+	assert.EqualError(t, f.SetCellValue("Dialog1", "A1", 1), "sheet Dialog1 is dialog sheet")
+	assert.EqualError(t, f.SetCellValue("Macro1", "A1", 1), "sheet Macro1 is macro sheet")
+
+	// Test the workbook level functions skip the dialog sheet and macro sheet.
+	f.SetActiveSheet(0)
+	assert.NoError(t, f.UngroupSheets())
+	assert.NoError(t, f.UpdateLinkedValue())
+	assert.NoError(t, f.SetSheetVisible("Macro1", false))
+	assert.Equal(t, "hidden", wb.Sheets.Sheet[2].State)
+	assert.NoError(t, f.SetSheetVisible("Sheet1", false))
+	assert.Empty(t, f.GetComments())
+
+	// Test the dialog sheet and macro sheet parts are kept on saving.
+	file := filepath.Join("test", "TestDialogSheetAndMacroSheet.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+	f, err := OpenFile(file)
+	assert.NoError(t, err)
+	for partPath, content := range parts {
+		assert.Equal(t, content, append(XMLHeaderByte, f.readXML(partPath)...)[len(XMLHeader):], partPath)
+	}
+	assert.Equal(t, []string{"Sheet1", "Dialog1", "Macro1"}, f.GetSheetList())
+}
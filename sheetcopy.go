@@ -0,0 +1,247 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mohae/deepcopy"
+)
+
+// SheetPartsMask is the bit mask of the parts of the worksheet which would be
+// copied by the CopySheetParts function.
+type SheetPartsMask uint8
+
+// This section defines the parts of the worksheet which could be copied by
+// the CopySheetParts function, combine them with the bitwise OR operator.
+const (
+	SheetPartValues SheetPartsMask = 1 << iota
+	SheetPartStyles
+	SheetPartDataValidations
+	SheetPartConditionalFormats
+	SheetPartDrawings
+	SheetPartAll = SheetPartValues | SheetPartStyles | SheetPartDataValidations | SheetPartConditionalFormats | SheetPartDrawings
+)
+
+// drawingRIDRegexp defined the regular expression to match the relationship
+// identifiers in the content of the drawing objects.
+var drawingRIDRegexp = regexp.MustCompile(`\br:(embed|id|link|pict)="([^"]*)"`)
+
+// CopySheetParts provides a function to copy the selected parts of the source
+// worksheet to the target worksheet by given worksheet names and the bit mask
+// of the parts, it works like the paste special in Excel for the whole
+// worksheet. The parts of the target worksheet which are not selected will be
+// kept. The supported parts are:
+//
+//    SheetPartValues             | Values and formulas of the cells
+//    SheetPartStyles             | Styles of the cells, formats of the rows and columns and merged cells
+//    SheetPartDataValidations    | Data validations
+//    SheetPartConditionalFormats | Conditional formats
+//    SheetPartDrawings           | Pictures, charts and shapes
+//    SheetPartAll                | All of the parts above
+//
+// The values and styles only apply to the cells which exist in the source
+// worksheet. The data validations and conditional formats of the target
+// worksheet will be replaced. The drawing objects will be appended to the
+// drawing objects of the target worksheet, and the charts will be duplicated.
+// For example, copy the values and styles of the cells in the worksheet
+// named Sheet1 to Sheet2:
+//
+//    err := f.CopySheetParts("Sheet1", "Sheet2", excelize.SheetPartValues|excelize.SheetPartStyles)
+//
+func (f *File) CopySheetParts(src, dst string, parts SheetPartsMask) error {
+	if trimSheetName(src) == trimSheetName(dst) {
+		return ErrCopySameSheet
+	}
+	srcWs, err := f.workSheetReader(src)
+	if err != nil {
+		return err
+	}
+	dstWs, err := f.workSheetReader(dst)
+	if err != nil {
+		return err
+	}
+	if parts&SheetPartDrawings != 0 {
+		if err = f.checkObjectsProtection(dstWs); err != nil {
+			return err
+		}
+	}
+	if parts&(SheetPartValues|SheetPartStyles) != 0 {
+		f.copySheetCells(srcWs, dstWs, dst, parts)
+	}
+	if parts&SheetPartStyles != 0 {
+		dstWs.Cols = deepcopy.Copy(srcWs.Cols).(*xlsxCols)
+		dstWs.MergeCells = deepcopy.Copy(srcWs.MergeCells).(*xlsxMergeCells)
+	}
+	if parts&SheetPartDataValidations != 0 {
+		dstWs.DataValidations = deepcopy.Copy(srcWs.DataValidations).(*xlsxDataValidations)
+	}
+	if parts&SheetPartConditionalFormats != 0 {
+		dstWs.ConditionalFormatting = deepcopy.Copy(srcWs.ConditionalFormatting).([]*xlsxConditionalFormatting)
+	}
+	if parts&SheetPartDrawings != 0 {
+		return f.copySheetDrawings(src, dst, dstWs)
+	}
+	return err
+}
+
+// copySheetCells provides a function to copy the values or styles of the
+// cells and the formats of the rows from the source worksheet to the target
+// worksheet. The indexes of the shared formulas will be shifted to avoid
+// conflict with the shared formulas in the target worksheet.
+func (f *File) copySheetCells(srcWs, dstWs *xlsxWorksheet, dst string, parts SheetPartsMask) {
+	var si int
+	for _, row := range dstWs.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil && c.F.T == STCellFormulaTypeShared {
+				if idx, err := strconv.Atoi(c.F.Si); err == nil && idx >= si {
+					si = idx + 1
+				}
+			}
+		}
+	}
+	sheetID := f.getSheetID(dst)
+	for _, row := range srcWs.SheetData.Row {
+		for _, c := range row.C {
+			col, rowNum, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				continue
+			}
+			prepareSheetXML(dstWs, col, rowNum)
+			cell := &dstWs.SheetData.Row[rowNum-1].C[col-1]
+			if parts&SheetPartValues != 0 {
+				if cell.F != nil && c.F == nil {
+					f.deleteCalcChain(sheetID, cell.R)
+				}
+				cell.T, cell.V, cell.XMLSpace = c.T, c.V, c.XMLSpace
+				cell.IS = deepcopy.Copy(c.IS).(*xlsxSI)
+				cell.F = deepcopy.Copy(c.F).(*xlsxF)
+				if cell.F != nil && cell.F.T == STCellFormulaTypeShared {
+					if idx, err := strconv.Atoi(cell.F.Si); err == nil {
+						cell.F.Si = strconv.Itoa(idx + si)
+					}
+				}
+			}
+			if parts&SheetPartStyles != 0 {
+				cell.S = c.S
+			}
+		}
+		if parts&SheetPartStyles == 0 || row.R < 1 {
+			continue
+		}
+		prepareSheetXML(dstWs, 0, row.R)
+		dstRow := &dstWs.SheetData.Row[row.R-1]
+		dstRow.S, dstRow.CustomFormat = row.S, row.CustomFormat
+		dstRow.Ht, dstRow.CustomHeight = row.Ht, row.CustomHeight
+	}
+}
+
+// copySheetDrawings provides a function to append the drawing objects of the
+// source worksheet to the drawing part of the target worksheet. The
+// relationships of the drawing objects will be copied, the charts will be
+// duplicated and the identifiers of the drawing objects will be renumbered.
+func (f *File) copySheetDrawings(src, dst string, dstWs *xlsxWorksheet) error {
+	srcDrawingXML, err := f.getSheetDrawingXML(src)
+	if err != nil || srcDrawingXML == "" {
+		return err
+	}
+	srcWsDr, _ := f.drawingParser(srcDrawingXML)
+	srcWsDr.Lock()
+	anchors := [][]*xdrCellAnchor{srcWsDr.AbsoluteAnchor, srcWsDr.OneCellAnchor, srcWsDr.TwoCellAnchor}
+	contents := make([][]string, len(anchors))
+	for i, cellAnchors := range anchors {
+		for _, cellAnchor := range cellAnchors {
+			contents[i] = append(contents[i], getCellAnchorContent(cellAnchor))
+		}
+	}
+	srcWsDr.Unlock()
+	drawingID := f.countDrawings() + 1
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(dstWs, drawingID, dst, drawingXML)
+	f.addContentTypePart(drawingID, "drawings")
+	srcRelsPath, dstRelsPath := getPartRelsPath(srcDrawingXML), getPartRelsPath(drawingXML)
+	rIDs := map[string]string{}
+	if rels := f.relsReader(srcRelsPath); rels != nil {
+		rels.Lock()
+		relationships := append([]xlsxRelationship{}, rels.Relationships...)
+		rels.Unlock()
+		for _, rel := range relationships {
+			target := rel.Target
+			if rel.Type == SourceRelationshipChart || rel.Type == SourceRelationshipChartEx {
+				target = f.copyDrawingChart(getRelsTargetPath(srcRelsPath, rel.Target), rel.Type, target)
+			}
+			rIDs[rel.ID] = "rId" + strconv.Itoa(f.addRels(dstRelsPath, rel.Type, target, rel.TargetMode))
+		}
+	}
+	dstWsDr, _ := f.drawingParser(drawingXML)
+	dstWsDr.Lock()
+	defer dstWsDr.Unlock()
+	dstWsDr.R = SourceRelationship.Value
+	offset := getDrawingMaxCNvPrID(dstWsDr)
+	for i, cellAnchors := range []*[]*xdrCellAnchor{&dstWsDr.AbsoluteAnchor, &dstWsDr.OneCellAnchor, &dstWsDr.TwoCellAnchor} {
+		for idx, content := range contents[i] {
+			content = drawingRIDRegexp.ReplaceAllStringFunc(content, func(attr string) string {
+				match := drawingRIDRegexp.FindStringSubmatch(attr)
+				if rID, ok := rIDs[match[2]]; ok {
+					return "r:" + match[1] + "=\"" + rID + "\""
+				}
+				return attr
+			})
+			content = shiftDrawingCNvPrID(content, offset)
+			*cellAnchors = append(*cellAnchors, &xdrCellAnchor{EditAs: anchors[i][idx].EditAs, GraphicFrame: content})
+		}
+	}
+	return err
+}
+
+// copyDrawingChart provides a function to duplicate the chart part and its
+// relationships by given chart part path and relationship type, and returns
+// the target of the relationship for the new chart part.
+func (f *File) copyDrawingChart(chartXML, relType, target string) string {
+	content, ok := f.Pkg.Load(chartXML)
+	if !ok {
+		return target
+	}
+	name, chartID := "chart", f.countCharts()+1
+	if relType == SourceRelationshipChartEx {
+		name, chartID = "chartEx", f.countChartExs()+1
+	}
+	newChartXML := "xl/charts/" + name + strconv.Itoa(chartID) + ".xml"
+	f.Pkg.Store(newChartXML, append([]byte{}, content.([]byte)...))
+	f.addContentTypePart(chartID, name)
+	if rels := f.relsReader(getPartRelsPath(chartXML)); rels != nil {
+		rels.Lock()
+		f.Relationships.Store(getPartRelsPath(newChartXML), &xlsxRelationships{
+			Relationships: append([]xlsxRelationship{}, rels.Relationships...),
+		})
+		rels.Unlock()
+	}
+	return "../charts/" + name + strconv.Itoa(chartID) + ".xml"
+}
+
+// shiftDrawingCNvPrID provides a function to shift the identifiers of the
+// non-visual drawing properties in the content of the drawing object by given
+// offset.
+func shiftDrawingCNvPrID(content string, offset int) string {
+	var buf strings.Builder
+	last := 0
+	for _, loc := range cNvPrIDRegexp.FindAllStringSubmatchIndex(content, -1) {
+		id, _ := strconv.Atoi(content[loc[2]:loc[3]])
+		buf.WriteString(content[last:loc[2]])
+		buf.WriteString(strconv.Itoa(id + offset))
+		last = loc[3]
+	}
+	buf.WriteString(content[last:])
+	return buf.String()
+}
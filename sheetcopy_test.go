@@ -0,0 +1,157 @@
+package excelize
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopySheetParts(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	for idx, row := range [][]interface{}{{"Month", "Sales"}, {"Jan", 10}, {"Feb", 20}, {"Mar", 30}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	setSharedFormula := func(sheet, formula string, cells ...string) {
+		ws, err := f.workSheetReader(sheet)
+		assert.NoError(t, err)
+		for i, cell := range cells {
+			c, _, _, err := f.prepareCell(ws, sheet, cell)
+			assert.NoError(t, err)
+			c.F = &xlsxF{T: STCellFormulaTypeShared, Si: "0"}
+			if i == 0 {
+				c.F.Content, c.F.Ref = formula, cells[0]+":"+cells[len(cells)-1]
+			}
+		}
+	}
+	setSharedFormula("Sheet1", "B2*2", "C2", "C3", "C4")
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	assert.NoError(t, f.MergeCell("Sheet1", "D1", "E1"))
+	dv := NewDataValidation(true)
+	dv.Sqref = "B2:B4"
+	assert.NoError(t, dv.SetRange(0, 100, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B2:B4", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6"}]`))
+	assert.NoError(t, f.AddPicture("Sheet1", "F1", filepath.Join("test", "images", "excel.png"), `{"x_scale":0.5,"y_scale":0.5}`))
+	assert.NoError(t, f.AddChart("Sheet1", "F10", `{"type":"col","series":[{"name":"Sheet1!$B$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$B$2:$B$4"}]}`))
+
+	// Test copy the values of the cells only.
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Keep"))
+	assert.NoError(t, f.SetCellStyle("Sheet2", "A1", "A1", style))
+	assert.NoError(t, f.SetCellValue("Sheet2", "H1", "Keep"))
+	setSharedFormula("Sheet2", "G2+1", "H2", "H3")
+	assert.NoError(t, f.CopySheetParts("Sheet1", "Sheet2", SheetPartValues))
+	for cell, expected := range map[string]string{"A1": "Month", "B3": "20", "H1": "Keep"} {
+		value, err := f.GetCellValue("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet2", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2*2", formula)
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", ws.SheetData.Row[1].C[7].F.Si)
+	assert.Equal(t, "1", ws.SheetData.Row[1].C[2].F.Si)
+	styleID, err := f.GetCellStyle("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	styleID, err = f.GetCellStyle("Sheet2", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+	assert.Nil(t, ws.Cols)
+	assert.Nil(t, ws.MergeCells)
+	assert.Nil(t, ws.DataValidations)
+	assert.Nil(t, ws.Drawing)
+
+	// Test copy the styles, data validations and conditional formats.
+	assert.NoError(t, f.CopySheetParts("Sheet1", "Sheet2", SheetPartStyles|SheetPartDataValidations|SheetPartConditionalFormats))
+	styleID, err = f.GetCellStyle("Sheet2", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	width, err := f.GetColWidth("Sheet2", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	height, err := f.GetRowHeight("Sheet2", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	mergeCells, err := f.GetMergeCells("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Len(t, ws.DataValidations.DataValidation, 1)
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	// Test the copied parts are independent from the source worksheet.
+	ws.DataValidations.DataValidation[0].Sqref = "C1"
+	srcWs, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:B4", srcWs.DataValidations.DataValidation[0].Sqref)
+
+	// Test copy the drawing objects to the worksheet with drawing objects.
+	assert.NoError(t, f.AddPicture("Sheet2", "K1", filepath.Join("test", "images", "excel.jpg"), ""))
+	assert.NoError(t, f.CopySheetParts("Sheet1", "Sheet2", SheetPartDrawings))
+	_, ok := f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	drawing, err := f.getSheetDrawingXML("Sheet2")
+	assert.NoError(t, err)
+	wsDr, _ := f.drawingParser(drawing)
+	assert.Len(t, wsDr.TwoCellAnchor, 3)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `<xdr:cNvPr id="4" name="Picture 2"`)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `r:embed="rId2"`)
+	assert.Contains(t, wsDr.TwoCellAnchor[2].GraphicFrame, `<xdr:cNvPr id="5" name="Chart 3"`)
+	assert.Contains(t, wsDr.TwoCellAnchor[2].GraphicFrame, `r:id="rId3"`)
+	rels := f.relsReader(getPartRelsPath(drawing))
+	assert.Equal(t, "../media/image1.png", rels.Relationships[1].Target)
+	assert.Equal(t, "../charts/chart2.xml", rels.Relationships[2].Target)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheetParts.xlsx")))
+
+	// Test copy all parts to a new worksheet after reopen the workbook.
+	f, err = OpenFile(filepath.Join("test", "TestCopySheetParts.xlsx"))
+	assert.NoError(t, err)
+	for _, cell := range []string{"F1", "K1"} {
+		file, raw, err := f.GetPicture("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, file, cell)
+		assert.NotEmpty(t, raw, cell)
+	}
+	f.NewSheet("Sheet3")
+	assert.NoError(t, f.CopySheetParts("Sheet2", "Sheet3", SheetPartAll))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheetParts.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestCopySheetParts.xlsx"))
+	assert.NoError(t, err)
+	charts := 0
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/charts/chart") {
+			charts++
+		}
+		return true
+	})
+	assert.Equal(t, 3, charts)
+	for _, cell := range []string{"F1", "K1"} {
+		file, _, err := f.GetPicture("Sheet3", cell)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, file, cell)
+	}
+	// Test copy the parts of the worksheet without drawing objects.
+	f.NewSheet("Sheet4")
+	assert.NoError(t, f.CopySheetParts("Sheet4", "Sheet1", SheetPartAll))
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Month", value)
+
+	// Test copy the parts with invalid worksheets.
+	assert.EqualError(t, f.CopySheetParts("Sheet1", "Sheet1", SheetPartAll), ErrCopySameSheet.Error())
+	assert.EqualError(t, f.CopySheetParts("SheetN", "Sheet1", SheetPartAll), "sheet SheetN is not exist")
+	assert.EqualError(t, f.CopySheetParts("Sheet1", "SheetN", SheetPartAll), "sheet SheetN is not exist")
+	// Test copy the drawing objects to the protected worksheet.
+	assert.NoError(t, f.ProtectSheet("Sheet4", &FormatSheetProtection{}))
+	assert.EqualError(t, f.CopySheetParts("Sheet1", "Sheet4", SheetPartDrawings), ErrProtectedObjects.Error())
+	assert.NoError(t, f.CopySheetParts("Sheet1", "Sheet4", SheetPartValues))
+}
@@ -43,7 +43,10 @@ type StreamWriter struct {
 // rows, you must call the 'Flush' method to end the streaming writing
 // process and ensure that the order of line numbers is ascending, the common
 // API and stream API can't be work mixed to writing data on the worksheets,
-// you can't get cell value when in-memory chunks data over 16MB. For
+// use the AddDataValidation, SetConditionalFormat and AddComment functions
+// of the StreamWriter to add the data validations, conditional formats and
+// comments on the streamed worksheet. You can't get cell value when
+// in-memory chunks data over 16MB. For
 // example, set data for worksheet of size 102400 rows x 50 columns with
 // numbers and style:
 //
@@ -138,6 +141,9 @@ func (sw *StreamWriter) AddTable(hcell, vcell, format string) error {
 	if err != nil {
 		return err
 	}
+	if err = sw.File.checkTableName(formatSet.TableName); err != nil {
+		return err
+	}
 
 	coordinates, err := areaRangeToCoordinates(hcell, vcell)
 	if err != nil {
@@ -280,12 +286,15 @@ func getRowElement(token xml.Token, hrow int) (startElement xml.StartElement, ok
 	return
 }
 
-// Cell can be used directly in StreamWriter.SetRow to specify a style and
-// a value.
+// Cell can be used directly in StreamWriter.SetRow, SetSheetRow and
+// SetSheetCol to specify a style, a formula and a value. MergeAcross
+// specifies the number of the columns on the right of the cell to be merged
+// with the cell, which only works in the StreamWriter.SetRow.
 type Cell struct {
-	StyleID int
-	Formula string
-	Value   interface{}
+	StyleID     int
+	Formula     string
+	Value       interface{}
+	MergeAcross int
 }
 
 // SetRow writes an array to stream rows by giving a worksheet name, starting
@@ -293,7 +302,26 @@ type Cell struct {
 // 'Flush' method to end the streaming writing process.
 //
 // As a special case, if Cell is used as a value, then the Cell.StyleID will be
-// applied to that cell.
+// applied to that cell. If the Cell.MergeAcross is greater than zero, the
+// cell will be merged with the given number of the columns on the right, the
+// blank cells with the same style will be written in the merged area, and
+// the next value will be written after the merged cell. For example, write a
+// section header merged across the columns A to D with a centered style,
+// followed by a row of the values:
+//
+//    styleID, err := file.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}})
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := streamWriter.SetRow("A1", []interface{}{
+//        excelize.Cell{StyleID: styleID, Value: "Section", MergeAcross: 3},
+//    }); err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := streamWriter.SetRow("A2", []interface{}{1, 2, 3, 4}); err != nil {
+//        fmt.Println(err)
+//    }
+//
 func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
@@ -306,20 +334,34 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 		_, _ = sw.rawData.WriteString(`<sheetData>`)
 		sw.sheetWritten = true
 	}
-	fmt.Fprintf(&sw.rawData, `<row r="%d">`, row)
-	for i, val := range values {
-		axis, err := CoordinatesToCellName(col+i, row)
+	width := len(values)
+	for _, val := range values {
+		if v, ok := val.(Cell); ok && v.MergeAcross > 0 {
+			width += v.MergeAcross
+		} else if v, ok := val.(*Cell); ok && v != nil && v.MergeAcross > 0 {
+			width += v.MergeAcross
+		}
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&sw.rawData, `<row r="%d" spans="%d:%d">`, row, col, col+width-1)
+	} else {
+		fmt.Fprintf(&sw.rawData, `<row r="%d">`, row)
+	}
+	for _, val := range values {
+		axis, err := CoordinatesToCellName(col, row)
 		if err != nil {
+			_, _ = sw.rawData.WriteString(`</row>`)
 			return err
 		}
 		c := xlsxC{R: axis}
+		var mergeAcross int
 		if v, ok := val.(Cell); ok {
 			c.S = v.StyleID
-			val = v.Value
+			val, mergeAcross = v.Value, v.MergeAcross
 			setCellFormula(&c, v.Formula)
 		} else if v, ok := val.(*Cell); ok && v != nil {
 			c.S = v.StyleID
-			val = v.Value
+			val, mergeAcross = v.Value, v.MergeAcross
 			setCellFormula(&c, v.Formula)
 		}
 		if err = setCellValFunc(&c, val); err != nil {
@@ -327,6 +369,11 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 			return err
 		}
 		writeCell(&sw.rawData, c)
+		if err = sw.writeMergeAcross(c, col, row, mergeAcross); err != nil {
+			_, _ = sw.rawData.WriteString(`</row>`)
+			return err
+		}
+		col += mergeAcross + 1
 	}
 	_, _ = sw.rawData.WriteString(`</row>`)
 	return sw.rawData.Sync()
@@ -372,6 +419,71 @@ func (sw *StreamWriter) MergeCell(hcell, vcell string) error {
 	return nil
 }
 
+// AddDataValidation provides a function to add the data validation to the
+// range of the worksheet for the StreamWriter, the range could contain the
+// cells which will be written after the data validation is added. Note that
+// you must call the 'AddDataValidation' function before the 'Flush'
+// function. For example, allow only the whole numbers between 1 and 100 in
+// A2:A10000:
+//
+//    dv := excelize.NewDataValidation(true)
+//    dv.Sqref = "A2:A10000"
+//    dv.SetRange(1, 100, excelize.DataValidationTypeWhole, excelize.DataValidationOperatorBetween)
+//    err := streamWriter.AddDataValidation(dv)
+//
+// See File.AddDataValidation for details on the data validation settings.
+func (sw *StreamWriter) AddDataValidation(dv *DataValidation) error {
+	return sw.File.AddDataValidation(sw.Sheet, dv)
+}
+
+// SetConditionalFormat provides a function to create the conditional
+// formatting rules on the range of the worksheet for the StreamWriter, the
+// range could contain the cells which will be written after the rules are
+// created. Note that you must call the 'SetConditionalFormat' function
+// before the 'Flush' function. For example, create the data bars in
+// B2:B10000:
+//
+//    err := streamWriter.SetConditionalFormat("B2:B10000", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6"}]`)
+//
+// See File.SetConditionalFormat for details on the format set.
+func (sw *StreamWriter) SetConditionalFormat(area, formatSet string) error {
+	return sw.File.SetConditionalFormat(sw.Sheet, area, formatSet)
+}
+
+// AddComment provides a function to add the comment to the cell of the
+// worksheet for the StreamWriter, the cell could be written after the
+// comment is added. Note that you must call the 'AddComment' function before
+// the 'Flush' function. For example, add a comment in A1:
+//
+//    err := streamWriter.AddComment("A1", `{"author":"Excelize: ","text":"This is a comment."}`)
+//
+// See File.AddComment for details on the comment format set.
+func (sw *StreamWriter) AddComment(cell, format string) error {
+	return sw.File.AddComment(sw.Sheet, cell, format)
+}
+
+// writeMergeAcross provides a function to write the blank cells with the
+// style of the given cell in the merged area, and merge the cells by given
+// coordinates of the cell and the number of the columns on the right of the
+// cell to be merged.
+func (sw *StreamWriter) writeMergeAcross(c xlsxC, col, row, mergeAcross int) error {
+	if mergeAcross < 0 {
+		return ErrParameterInvalid
+	}
+	if mergeAcross == 0 {
+		return nil
+	}
+	vcell, err := CoordinatesToCellName(col+mergeAcross, row)
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= mergeAcross; i++ {
+		axis, _ := CoordinatesToCellName(col+i, row)
+		writeCell(&sw.rawData, xlsxC{R: axis, S: c.S})
+	}
+	return sw.MergeCell(c.R, vcell)
+}
+
 // setCellFormula provides a function to set formula of a cell.
 func setCellFormula(c *xlsxC, formula string) {
 	if formula != "" {
@@ -389,9 +501,9 @@ func setCellValFunc(c *xlsxC, val interface{}) (err error) {
 	case float64:
 		c.T, c.V = setCellFloat(val, -1, 64)
 	case string:
-		c.T, c.V, c.XMLSpace = setCellStr(val)
+		c.T, c.V, c.XMLSpace, err = setCellStr(val)
 	case []byte:
-		c.T, c.V, c.XMLSpace = setCellStr(string(val))
+		c.T, c.V, c.XMLSpace, err = setCellStr(string(val))
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
@@ -399,9 +511,9 @@ func setCellValFunc(c *xlsxC, val interface{}) (err error) {
 	case bool:
 		c.T, c.V = setCellBool(val)
 	case nil:
-		c.T, c.V, c.XMLSpace = setCellStr("")
+		c.T, c.V, c.XMLSpace, err = setCellStr("")
 	default:
-		c.T, c.V, c.XMLSpace = setCellStr(fmt.Sprint(val))
+		c.T, c.V, c.XMLSpace, err = setCellStr(fmt.Sprint(val))
 	}
 	return err
 }
@@ -495,7 +607,8 @@ func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
 	enc := xml.NewEncoder(w)
 	for i := 0; i < s.NumField(); i++ {
 		if from <= i && i <= to {
-			_ = enc.Encode(s.Field(i).Interface())
+			name := strings.Split(s.Type().Field(i).Tag.Get("xml"), ",")[0]
+			_ = enc.EncodeElement(s.Field(i).Interface(), xml.StartElement{Name: xml.Name{Local: name}})
 		}
 	}
 }
@@ -41,6 +41,8 @@ func TestStreamWriter(t *testing.T) {
 	// Test max characters in a cell.
 	row := make([]interface{}, 1)
 	row[0] = strings.Repeat("c", TotalCellChars+2)
+	assert.EqualError(t, streamWriter.SetRow("A1", row), ErrCellCharsLength.Error())
+	row[0] = strings.Repeat("c", TotalCellChars)
 	assert.NoError(t, streamWriter.SetRow("A1", row))
 
 	// Test leading and ending space(s) character characters in a cell.
@@ -75,6 +77,15 @@ func TestStreamWriter(t *testing.T) {
 	// Test set cell column overflow.
 	assert.EqualError(t, streamWriter.SetRow("XFD1", []interface{}{"A", "B", "C"}), ErrColumnNumber.Error())
 
+	// Test the spans attribute of the rows.
+	file = NewFile()
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("B1", []interface{}{1, 2, 3}))
+	assert.NoError(t, streamWriter.SetRow("A2", nil))
+	assert.NoError(t, streamWriter.Flush())
+	assert.Contains(t, string(file.readXML("xl/worksheets/sheet1.xml")), `<row r="1" spans="2:4"><c r="B1"><v>1</v></c><c r="C1"><v>2</v></c><c r="D1"><v>3</v></c></row><row r="2"></row>`)
+
 	// Test close temporary file error.
 	file = NewFile()
 	streamWriter, err = file.NewStreamWriter("Sheet1")
@@ -173,6 +184,46 @@ func TestStreamMergeCells(t *testing.T) {
 	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamMergeCells.xlsx")))
 }
 
+func TestStreamDataValidationConditionalFormatComment(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:A100"
+	assert.NoError(t, dv.SetRange(1, 100, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, streamWriter.AddDataValidation(dv))
+	assert.NoError(t, streamWriter.SetConditionalFormat("A1:A100", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_solid":true}]`))
+	assert.NoError(t, streamWriter.AddComment("A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	for row := 1; row <= 100; row++ {
+		cell, err := CoordinatesToCellName(1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, streamWriter.SetRow(cell, []interface{}{row}))
+	}
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamDataValidationConditionalFormatComment.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamDataValidationConditionalFormatComment.xlsx"))
+	assert.NoError(t, err)
+	dvs, err := file.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Equal(t, "A1:A100", dvs[0].Sqref)
+	opts, err := file.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, opts["A1:A100"], 1)
+	assert.True(t, opts["A1:A100"][0].BarSolid)
+	assert.Len(t, file.GetComments()["Sheet1"], 1)
+	cell, err := file.GetCellValue("Sheet1", "A100")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", cell)
+
+	// Test add conditional format and comment with illegal settings
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.SetConditionalFormat("A1", "["), "unexpected end of JSON input")
+	assert.EqualError(t, streamWriter.AddComment("A1", "{"), "unexpected end of JSON input")
+}
+
 func TestNewStreamWriter(t *testing.T) {
 	// Test error exceptions
 	file := NewFile()
@@ -212,3 +263,38 @@ func TestSetCellValFunc(t *testing.T) {
 	assert.NoError(t, setCellValFunc(c, nil))
 	assert.NoError(t, setCellValFunc(c, complex64(5+10i)))
 }
+
+func TestStreamMergeAcross(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	styleID, err := file.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center"}})
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{Cell{StyleID: styleID, Value: "Section", MergeAcross: 2}, "Next"}))
+	assert.NoError(t, streamWriter.SetRow("B2", []interface{}{1, &Cell{StyleID: styleID, Value: "Merged", MergeAcross: 1}, 2}))
+	// Test write merged cell with invalid number of columns.
+	assert.EqualError(t, streamWriter.SetRow("A3", []interface{}{Cell{MergeAcross: -1}}), ErrParameterInvalid.Error())
+	assert.EqualError(t, streamWriter.SetRow("XFC4", []interface{}{Cell{MergeAcross: 2}}), ErrColumnNumber.Error())
+	assert.EqualError(t, streamWriter.SetRow("XFC5", []interface{}{Cell{MergeAcross: 1}, 1}), ErrColumnNumber.Error())
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamMergeAcross.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamMergeAcross.xlsx"))
+	assert.NoError(t, err)
+	mergeCells, err := file.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 3)
+	assert.Equal(t, "A1:C1", mergeCells[0][0])
+	assert.Equal(t, "C2:D2", mergeCells[1][0])
+	assert.Equal(t, "XFC5:XFD5", mergeCells[2][0])
+	for cell, expected := range map[string]string{"A1": "Section", "D1": "Next", "B2": "1", "C2": "Merged", "E2": "2"} {
+		val, err := file.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	for _, cell := range []string{"B1", "C1", "D2"} {
+		style, err := file.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, style, cell)
+	}
+}
@@ -16,11 +16,13 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"image/color"
 	"io"
 	"log"
 	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -800,16 +802,56 @@ var validType = map[string]string{
 	"unique":        "uniqueValues",
 	"top":           "top10",
 	"bottom":        "top10",
-	"text":          "text",              // Doesn't support currently
-	"time_period":   "timePeriod",        // Doesn't support currently
-	"blanks":        "containsBlanks",    // Doesn't support currently
-	"no_blanks":     "notContainsBlanks", // Doesn't support currently
-	"errors":        "containsErrors",    // Doesn't support currently
-	"no_errors":     "notContainsErrors", // Doesn't support currently
+	"text":          "text",
+	"time_period":   "timePeriod",
+	"blanks":        "containsBlanks",
+	"no_blanks":     "notContainsBlanks",
+	"errors":        "containsErrors",
+	"no_errors":     "notContainsErrors",
 	"2_color_scale": "2_color_scale",
 	"3_color_scale": "3_color_scale",
 	"data_bar":      "dataBar",
 	"formula":       "expression",
+	"icon_set":      "iconSet",
+}
+
+// validIconSets defined the list of valid icon set types and the number of
+// icons in each set.
+var validIconSets = map[string]int{
+	"3Arrows":         3,
+	"3ArrowsGray":     3,
+	"3Flags":          3,
+	"3Signs":          3,
+	"3Stars":          3,
+	"3Symbols":        3,
+	"3Symbols2":       3,
+	"3TrafficLights1": 3,
+	"3TrafficLights2": 3,
+	"3Triangles":      3,
+	"4Arrows":         4,
+	"4ArrowsGray":     4,
+	"4Rating":         4,
+	"4RedToBlack":     4,
+	"4TrafficLights":  4,
+	"5Arrows":         5,
+	"5ArrowsGray":     5,
+	"5Boxes":          5,
+	"5Quarters":       5,
+	"5Rating":         5,
+}
+
+// x14IconSets defined the list of icon set types which introduced in Excel
+// 2010, these icon sets are stored in the worksheet extension list.
+var x14IconSets = map[string]bool{"3Stars": true, "3Triangles": true, "5Boxes": true}
+
+// cfvoTypes defined the list of valid threshold types of the icon set
+// conditional formatting rule.
+var cfvoTypes = map[string]string{
+	"number":     "num",
+	"num":        "num",
+	"percent":    "percent",
+	"percentile": "percentile",
+	"formula":    "formula",
 }
 
 // criteriaType defined the list of valid criteria types.
@@ -839,10 +881,13 @@ var criteriaType = map[string]string{
 	"last 7 days":              "last7Days",
 	"last week":                "lastWeek",
 	"this week":                "thisWeek",
-	"continue week":            "continueWeek",
+	"continue week":            "nextWeek",
+	"next week":                "nextWeek",
 	"last month":               "lastMonth",
 	"this month":               "thisMonth",
-	"continue month":           "continueMonth",
+	"continue month":           "nextMonth",
+	"next month":               "nextMonth",
+	"tomorrow":                 "tomorrow",
 }
 
 // formatToString provides a function to return original string by given
@@ -930,6 +975,9 @@ func formatToE(v string, format string) string {
 	if err != nil {
 		return v
 	}
+	if format == builtInNumFmt[11] {
+		return fmt.Sprintf("%.2E", f)
+	}
 	return fmt.Sprintf("%.e", f)
 }
 
@@ -945,6 +993,14 @@ func formatToE(v string, format string) string {
 // turn them to what they should actually be. Based off:
 // http://www.ozgrid.com/Excel/CustomFormats.htm
 func parseTime(v string, format string) string {
+	return formatTime(v, format, nil)
+}
+
+// formatTime provides a function to returns a string parsed using time.Time
+// by given cell value, number format code and culture info, the month
+// names, day names and AM/PM designators will be replaced by the culture
+// specific names if the culture info is not nil.
+func formatTime(v string, format string, culture *CultureInfo) string {
 	var (
 		f     float64
 		err   error
@@ -983,6 +1039,7 @@ func parseTime(v string, format string) string {
 		{"yy", "06"},
 		{"mmmm", "%%%%"},
 		{"dddd", "&&&&"},
+		{"ddd", "Mon"},
 		{"dd", "02"},
 		{"d", "2"},
 		{"mmm", "Jan"},
@@ -1046,10 +1103,16 @@ func parseTime(v string, format string) string {
 		goFmt = strings.Replace(goFmt, "[3]", "3", 1)
 		goFmt = strings.Replace(goFmt, "[15]", "15", 1)
 	}
+	if culture != nil {
+		goFmt = timeNameMarkers.Replace(goFmt)
+	}
 	s := val.Format(goFmt)
 	if padding {
 		s = strings.Replace(s, "00:", "0:", 1)
 	}
+	if culture != nil {
+		s = culture.replaceTimeNameMarkers(s, val)
+	}
 	return s
 }
 
@@ -1201,6 +1264,17 @@ func parseFormatStyleSet(style interface{}) (*Style, error) {
 //     single
 //     double
 //
+// The following the type of font vertical alignment (vert_align) style:
+//
+//     Style
+//    ------------------
+//     baseline
+//     superscript
+//     subscript
+//
+// The font scheme (scheme) can be set as major, minor or none, and the charset,
+// condense, extend, outline and shadow of the font can also be specified.
+//
 // Excel's built-in all languages formats are shown in the following table:
 //
 //     Index | Format String
@@ -1978,6 +2052,15 @@ func parseFormatStyleSet(style interface{}) (*Style, error) {
 //
 // Cell Sheet1!A6 in the Excel Application: martes, 04 de Julio de 2017
 //
+// Set the quote prefix flag to force the text which begins with '=' or looks
+// like a number to be displayed as entered, matching the result of typing a
+// leading apostrophe in Excel. For example, keep the text "=A1" of Sheet1!A7
+// as literal text:
+//
+//    f.SetCellStr("Sheet1", "A7", "=A1")
+//    style, err := f.NewStyle(&excelize.Style{QuotePrefix: true})
+//    err = f.SetCellStyle("Sheet1", "A7", "A7", style)
+//
 func (f *File) NewStyle(style interface{}) (int, error) {
 	var fs *Style
 	var err error
@@ -2032,9 +2115,198 @@ func (f *File) NewStyle(style interface{}) (int, error) {
 	applyAlignment, alignment := fs.Alignment != nil, newAlignment(fs)
 	applyProtection, protection := fs.Protection != nil, newProtection(fs)
 	cellXfsID = setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
+	if fs.QuotePrefix {
+		s.CellXfs.Xf[cellXfsID].QuotePrefix = boolPtr(true)
+	}
 	return cellXfsID, nil
 }
 
+// StyleBuilder directly maps the fluent builder of the cell style. The
+// settings are validated and the style will be created by the Build
+// function. For example, create a style with bold font, solid fill and
+// percentage number format:
+//
+//    style, err := excelize.NewStyleBuilder().
+//        Font(excelize.Font{Bold: true, Color: "#FFFFFF"}).
+//        Fill(excelize.Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1}).
+//        NumFmt(10).
+//        Build(f)
+//
+type StyleBuilder struct {
+	style Style
+}
+
+// NewStyleBuilder provides a function to create a new style builder.
+func NewStyleBuilder() *StyleBuilder {
+	return &StyleBuilder{}
+}
+
+// Font provides a function to set the font of the style.
+func (sb *StyleBuilder) Font(font Font) *StyleBuilder {
+	sb.style.Font = &font
+	return sb
+}
+
+// Fill provides a function to set the fill of the style.
+func (sb *StyleBuilder) Fill(fill Fill) *StyleBuilder {
+	sb.style.Fill = fill
+	return sb
+}
+
+// Border provides a function to append borders to the style.
+func (sb *StyleBuilder) Border(borders ...Border) *StyleBuilder {
+	sb.style.Border = append(sb.style.Border, borders...)
+	return sb
+}
+
+// Alignment provides a function to set the alignment of the style.
+func (sb *StyleBuilder) Alignment(alignment Alignment) *StyleBuilder {
+	sb.style.Alignment = &alignment
+	return sb
+}
+
+// Protection provides a function to set the protection of the style.
+func (sb *StyleBuilder) Protection(protection Protection) *StyleBuilder {
+	sb.style.Protection = &protection
+	return sb
+}
+
+// NumFmt provides a function to set the built-in number format index of the
+// style.
+func (sb *StyleBuilder) NumFmt(numFmt int) *StyleBuilder {
+	sb.style.NumFmt = numFmt
+	return sb
+}
+
+// CustomNumFmt provides a function to set the custom number format code of
+// the style.
+func (sb *StyleBuilder) CustomNumFmt(code string) *StyleBuilder {
+	sb.style.CustomNumFmt = &code
+	return sb
+}
+
+// DecimalPlaces provides a function to set the decimal places of the
+// currency number format.
+func (sb *StyleBuilder) DecimalPlaces(places int) *StyleBuilder {
+	sb.style.DecimalPlaces = places
+	return sb
+}
+
+// Lang provides a function to set the language of the number format.
+func (sb *StyleBuilder) Lang(lang string) *StyleBuilder {
+	sb.style.Lang = lang
+	return sb
+}
+
+// NegRed provides a function to set whether to display the negative number
+// in red color.
+func (sb *StyleBuilder) NegRed(negRed bool) *StyleBuilder {
+	sb.style.NegRed = negRed
+	return sb
+}
+
+// QuotePrefix provides a function to set whether the text of the cell should
+// be displayed as entered, the same as typing a leading apostrophe.
+func (sb *StyleBuilder) QuotePrefix(quotePrefix bool) *StyleBuilder {
+	sb.style.QuotePrefix = quotePrefix
+	return sb
+}
+
+// Style provides a function to validate the settings and return a copy of
+// the style definition built by the builder.
+func (sb *StyleBuilder) Style() (*Style, error) {
+	style := sb.style
+	return &style, validateStyle(&style)
+}
+
+// Build provides a function to validate the settings and create the style
+// in the given workbook, the style ID will be returned.
+func (sb *StyleBuilder) Build(f *File) (int, error) {
+	style, err := sb.Style()
+	if err != nil {
+		return 0, err
+	}
+	return f.NewStyle(style)
+}
+
+// isRGBColor provides a function to check if the given string is a RGB color
+// code with an optional leading '#'.
+func isRGBColor(color string) bool {
+	_, err := ParseHexColor(color)
+	return err == nil
+}
+
+// validateStyle provides a function to validate the given style definition.
+func validateStyle(style *Style) error {
+	if style.Font != nil {
+		if len(style.Font.Family) > MaxFontFamilyLength {
+			return ErrFontLength
+		}
+		if style.Font.Size < 0 || style.Font.Size > MaxFontSize {
+			return ErrFontSize
+		}
+		if style.Font.Color != "" && !isRGBColor(style.Font.Color) {
+			return ErrFontColor
+		}
+		if u := style.Font.Underline; u != "" && u != "single" && u != "double" {
+			return newInvalidStyleAttrError("font underline", u)
+		}
+		if v := style.Font.VertAlign; v != "" && inStrSlice([]string{"baseline", "superscript", "subscript"}, v) == -1 {
+			return newInvalidStyleAttrError("font vert_align", v)
+		}
+		if s := style.Font.Scheme; s != "" && s != "major" && s != "minor" && s != "none" {
+			return newInvalidStyleAttrError("font scheme", s)
+		}
+	}
+	switch style.Fill.Type {
+	case "":
+	case "gradient":
+		if style.Fill.Shading < 0 || style.Fill.Shading > 5 {
+			return ErrFillShading
+		}
+		if len(style.Fill.Color) != 2 {
+			return ErrFillColor
+		}
+	case "pattern":
+		if style.Fill.Pattern < 0 || style.Fill.Pattern > 18 {
+			return ErrFillPattern
+		}
+		if len(style.Fill.Color) < 1 {
+			return ErrFillColor
+		}
+	default:
+		return ErrFillType
+	}
+	for _, color := range style.Fill.Color {
+		if !isRGBColor(color) {
+			return ErrFillColor
+		}
+	}
+	for _, border := range style.Border {
+		if inStrSlice([]string{"left", "right", "top", "bottom", "diagonalUp", "diagonalDown"}, border.Type) == -1 {
+			return newInvalidStyleAttrError("border type", border.Type)
+		}
+		if border.Style < 0 || border.Style > 13 {
+			return ErrBorderStyle
+		}
+		if border.Color != "" && !isRGBColor(border.Color) {
+			return newInvalidStyleAttrError("border color", border.Color)
+		}
+	}
+	if style.Alignment != nil {
+		if h := style.Alignment.Horizontal; h != "" && inStrSlice([]string{"left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}, h) == -1 {
+			return newInvalidStyleAttrError("horizontal alignment", h)
+		}
+		if v := style.Alignment.Vertical; v != "" && inStrSlice([]string{"top", "center", "bottom", "justify", "distributed"}, v) == -1 {
+			return newInvalidStyleAttrError("vertical alignment", v)
+		}
+	}
+	if style.NumFmt < 0 {
+		return newInvalidStyleAttrError("number format", strconv.Itoa(style.NumFmt))
+	}
+	return nil
+}
+
 var getXfIDFuncs = map[string]func(int, xlsxXf, *Style) bool{
 	"numFmt": func(numFmtID int, xf xlsxXf, style *Style) bool {
 		if style.NumFmt == 0 && style.CustomNumFmt == nil && numFmtID == -1 {
@@ -2075,6 +2347,9 @@ var getXfIDFuncs = map[string]func(int, xlsxXf, *Style) bool{
 		}
 		return reflect.DeepEqual(xf.Protection, newProtection(style)) && xf.ApplyProtection != nil && *xf.ApplyProtection
 	},
+	"quotePrefix": func(ID int, xf xlsxXf, style *Style) bool {
+		return style.QuotePrefix == (xf.QuotePrefix != nil && *xf.QuotePrefix)
+	},
 }
 
 // getStyleID provides a function to get styleID by given style. If given
@@ -2094,7 +2369,8 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (styleID int) {
 			getXfIDFuncs["fill"](fillID, xf, style) &&
 			getXfIDFuncs["border"](borderID, xf, style) &&
 			getXfIDFuncs["alignment"](0, xf, style) &&
-			getXfIDFuncs["protection"](0, xf, style) {
+			getXfIDFuncs["protection"](0, xf, style) &&
+			getXfIDFuncs["quotePrefix"](0, xf, style) {
 			styleID = xfID
 			return
 		}
@@ -2107,11 +2383,19 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (styleID int) {
 // NewStyle(). Note that the color field uses RGB color code and only support
 // to set font, fills, alignment and borders currently.
 func (f *File) NewConditionalStyle(style string) (int, error) {
-	s := f.stylesReader()
 	fs, err := parseFormatStyleSet(style)
 	if err != nil {
 		return 0, err
 	}
+	return f.newConditionalStyle(fs, false), nil
+}
+
+// newConditionalStyle provides a function to create style for conditional
+// format by given style settings, and returns the differential formatting ID.
+// The existing differential formatting with the same settings will be reused
+// if the reuse is true.
+func (f *File) newConditionalStyle(fs *Style, reuse bool) int {
+	s := f.stylesReader()
 	dxf := dxf{
 		Fill: newFills(fs, false),
 	}
@@ -2128,11 +2412,197 @@ func (f *File) NewConditionalStyle(style string) (int, error) {
 	if s.Dxfs == nil {
 		s.Dxfs = &xlsxDxfs{}
 	}
+	if reuse {
+		for idx, d := range s.Dxfs.Dxfs {
+			if d != nil && d.Dxf == string(dxfStr[5:len(dxfStr)-6]) {
+				return idx
+			}
+		}
+	}
 	s.Dxfs.Count++
 	s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{
 		Dxf: string(dxfStr[5 : len(dxfStr)-6]),
 	})
-	return s.Dxfs.Count - 1, nil
+	return s.Dxfs.Count - 1
+}
+
+// getConditionalStyle provides a function to get the resolved style settings
+// of the differential formatting by given differential formatting ID, the
+// theme colors will be resolved into RGB color codes. It returns nil if the
+// differential formatting doesn't exist.
+func (f *File) getConditionalStyle(dxfID int, theme *xlsxTheme) *Style {
+	s := f.stylesReader()
+	if s.Dxfs == nil || dxfID < 0 || dxfID >= len(s.Dxfs.Dxfs) || s.Dxfs.Dxfs[dxfID] == nil {
+		return nil
+	}
+	var d dxf
+	if err := xml.Unmarshal([]byte("<dxf>"+s.Dxfs.Dxfs[dxfID].Dxf+"</dxf>"), &d); err != nil {
+		return nil
+	}
+	style := Style{}
+	if d.Font != nil {
+		style.Font = extractFont(d.Font, theme)
+	}
+	if d.Fill != nil {
+		style.Fill = extractFill(d.Fill, theme)
+	}
+	if d.Alignment != nil {
+		style.Alignment = &Alignment{
+			Horizontal:      d.Alignment.Horizontal,
+			Indent:          d.Alignment.Indent,
+			JustifyLastLine: d.Alignment.JustifyLastLine,
+			ReadingOrder:    d.Alignment.ReadingOrder,
+			RelativeIndent:  d.Alignment.RelativeIndent,
+			ShrinkToFit:     d.Alignment.ShrinkToFit,
+			TextRotation:    d.Alignment.TextRotation,
+			Vertical:        d.Alignment.Vertical,
+			WrapText:        d.Alignment.WrapText,
+		}
+	}
+	if d.Border != nil {
+		style.Border = extractBorders(d.Border, theme)
+	}
+	return &style
+}
+
+// extractFont provides a function to extract the font settings by given font
+// and theme.
+func extractFont(fnt *xlsxFont, theme *xlsxTheme) *Font {
+	boolValue := func(v *attrValBool) bool {
+		return v != nil && (v.Val == nil || *v.Val)
+	}
+	font := Font{
+		Bold:     boolValue(fnt.B),
+		Italic:   boolValue(fnt.I),
+		Strike:   boolValue(fnt.Strike),
+		Outline:  boolValue(fnt.Outline),
+		Shadow:   boolValue(fnt.Shadow),
+		Condense: boolValue(fnt.Condense),
+		Extend:   boolValue(fnt.Extend),
+		Color:    getColorRGB(fnt.Color, theme),
+	}
+	if fnt.U != nil {
+		font.Underline = "single"
+		if fnt.U.Val != nil {
+			font.Underline = *fnt.U.Val
+		}
+	}
+	if fnt.VertAlign != nil && fnt.VertAlign.Val != nil {
+		font.VertAlign = *fnt.VertAlign.Val
+	}
+	if fnt.Sz != nil && fnt.Sz.Val != nil {
+		font.Size = *fnt.Sz.Val
+	}
+	if fnt.Name != nil && fnt.Name.Val != nil {
+		font.Family = *fnt.Name.Val
+	}
+	if fnt.Charset != nil && fnt.Charset.Val != nil {
+		font.Charset = intPtr(*fnt.Charset.Val)
+	}
+	if fnt.Scheme != nil && fnt.Scheme.Val != nil {
+		font.Scheme = *fnt.Scheme.Val
+	}
+	return &font
+}
+
+// extractFill provides a function to extract the fill settings by given fill
+// and theme.
+func extractFill(fill *xlsxFill, theme *xlsxTheme) Fill {
+	var format Fill
+	if fill.GradientFill != nil {
+		format.Type = "gradient"
+		switch fill.GradientFill.Type {
+		case "path":
+			format.Shading = 4
+			if fill.GradientFill.Top != 0 || fill.GradientFill.Left != 0 {
+				format.Shading = 5
+			}
+		default:
+			for idx, degree := range fillGradientVariants {
+				if degree == fill.GradientFill.Degree {
+					format.Shading = idx
+				}
+			}
+		}
+		for _, stop := range fill.GradientFill.Stop {
+			format.Color = append(format.Color, getColorRGB(&stop.Color, theme))
+		}
+		return format
+	}
+	if fill.PatternFill != nil {
+		format.Type = "pattern"
+		if format.Pattern = inStrSlice(fillPatterns, fill.PatternFill.PatternType); format.Pattern == -1 {
+			format.Pattern = 1
+		}
+		clr := fill.PatternFill.BgColor
+		if clr == nil {
+			clr = fill.PatternFill.FgColor
+		}
+		if color := getColorRGB(clr, theme); color != "" {
+			format.Color = []string{color}
+		}
+	}
+	return format
+}
+
+// extractBorders provides a function to extract the border settings by given
+// border and theme.
+func extractBorders(border *xlsxBorder, theme *xlsxTheme) []Border {
+	var borders []Border
+	for _, line := range []struct {
+		typ  string
+		line xlsxLine
+	}{
+		{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom},
+	} {
+		if idx := inStrSlice(borderStyles, line.line.Style); idx > 0 {
+			borders = append(borders, Border{Type: line.typ, Color: getColorRGB(line.line.Color, theme), Style: idx})
+		}
+	}
+	if idx := inStrSlice(borderStyles, border.Diagonal.Style); idx > 0 {
+		color := getColorRGB(border.Diagonal.Color, theme)
+		if border.DiagonalUp {
+			borders = append(borders, Border{Type: "diagonalUp", Color: color, Style: idx})
+		}
+		if border.DiagonalDown {
+			borders = append(borders, Border{Type: "diagonalDown", Color: color, Style: idx})
+		}
+	}
+	return borders
+}
+
+// getColorRGB provides a function to get the RGB color code in the format of
+// #RRGGBB by given color and theme, the theme color will be resolved with the
+// tint value. It returns empty string if the color is not a RGB or theme
+// color.
+func getColorRGB(clr *xlsxColor, theme *xlsxTheme) string {
+	if clr == nil {
+		return ""
+	}
+	var color string
+	if clr.RGB != "" {
+		color = ThemeColor(clr.RGB, clr.Tint)
+	}
+	if clr.Theme != nil && theme != nil {
+		// The theme color index 0 and 1 are mapped to the light 1 and dark 1
+		// color, 2 and 3 are mapped to the light 2 and dark 2 color.
+		idx := *clr.Theme
+		if idx < 4 {
+			idx ^= 1
+		}
+		if children := theme.ThemeElements.ClrScheme.Children; idx >= 0 && idx < len(children) {
+			if children[idx].SrgbClr != nil && children[idx].SrgbClr.Val != nil {
+				color = ThemeColor(*children[idx].SrgbClr.Val, clr.Tint)
+			}
+			if children[idx].SysClr != nil {
+				color = ThemeColor(children[idx].SysClr.LastClr, clr.Tint)
+			}
+		}
+	}
+	if len(color) != 8 {
+		return ""
+	}
+	return "#" + color[2:]
 }
 
 // GetDefaultFont provides the default font name currently set in the workbook
@@ -2174,6 +2644,65 @@ func (f *File) getFontID(styleSheet *xlsxStyleSheet, style *Style) (fontID int)
 	return
 }
 
+// getStyleFont provides a function to get the font family, size, bold and
+// italic settings of the font by given style index, the default font of the
+// spreadsheet will be used if the style doesn't specify a font.
+func (f *File) getStyleFont(styleID int) *Font {
+	font := &Font{Family: defaultFontFamily, Size: defaultFontSize}
+	styleSheet := f.stylesReader()
+	if styleSheet.Fonts == nil || len(styleSheet.Fonts.Font) == 0 {
+		return font
+	}
+	fontID := 0
+	if styleSheet.CellXfs != nil && styleID > 0 && styleID < len(styleSheet.CellXfs.Xf) && styleSheet.CellXfs.Xf[styleID].FontID != nil {
+		fontID = *styleSheet.CellXfs.Xf[styleID].FontID
+	}
+	if fontID < 0 || fontID >= len(styleSheet.Fonts.Font) {
+		fontID = 0
+	}
+	fnt := styleSheet.Fonts.Font[fontID]
+	if fnt.Name != nil && fnt.Name.Val != nil {
+		font.Family = *fnt.Name.Val
+	}
+	if fnt.Sz != nil && fnt.Sz.Val != nil {
+		font.Size = *fnt.Sz.Val
+	}
+	font.Bold = fnt.B != nil && (fnt.B.Val == nil || *fnt.B.Val)
+	font.Italic = fnt.I != nil && (fnt.I.Val == nil || *fnt.I.Val)
+	return font
+}
+
+// getBoldStyle provides a function to get the copy of the cell style with
+// the bold font by given style index, the style index will be returned
+// without changes if the font of the style is already bold or the style
+// doesn't exist.
+func (f *File) getBoldStyle(styleID int) int {
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if s.CellXfs == nil || s.Fonts == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return styleID
+	}
+	xf, fontID := s.CellXfs.Xf[styleID], 0
+	if xf.FontID != nil && *xf.FontID >= 0 && *xf.FontID < len(s.Fonts.Font) {
+		fontID = *xf.FontID
+	}
+	fnt := xlsxFont{}
+	if fontID < len(s.Fonts.Font) {
+		fnt = *s.Fonts.Font[fontID]
+	}
+	if fnt.B != nil && (fnt.B.Val == nil || *fnt.B.Val) {
+		return styleID
+	}
+	fnt.B = &attrValBool{Val: boolPtr(true)}
+	s.Fonts.Font = append(s.Fonts.Font, &fnt)
+	s.Fonts.Count = len(s.Fonts.Font)
+	xf.FontID, xf.ApplyFont = intPtr(len(s.Fonts.Font)-1), boolPtr(true)
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return s.CellXfs.Count - 1
+}
+
 // newFont provides a function to add font style by given cell format
 // settings.
 func (f *File) newFont(style *Style) *xlsxFont {
@@ -2206,6 +2735,27 @@ func (f *File) newFont(style *Style) *xlsxFont {
 	if ok {
 		fnt.U = &attrValString{Val: stringPtr(val)}
 	}
+	if style.Font.Outline {
+		fnt.Outline = &attrValBool{Val: &style.Font.Outline}
+	}
+	if style.Font.Shadow {
+		fnt.Shadow = &attrValBool{Val: &style.Font.Shadow}
+	}
+	if style.Font.Condense {
+		fnt.Condense = &attrValBool{Val: &style.Font.Condense}
+	}
+	if style.Font.Extend {
+		fnt.Extend = &attrValBool{Val: &style.Font.Extend}
+	}
+	if style.Font.VertAlign != "" {
+		fnt.VertAlign = &attrValString{Val: stringPtr(style.Font.VertAlign)}
+	}
+	if style.Font.Charset != nil {
+		fnt.Charset = &attrValInt{Val: intPtr(*style.Font.Charset)}
+	}
+	if style.Font.Scheme != "" {
+		fnt.Scheme = &attrValString{Val: stringPtr(style.Font.Scheme)}
+	}
 	return &fnt
 }
 
@@ -2363,38 +2913,61 @@ func getFillID(styleSheet *xlsxStyleSheet, style *Style) (fillID int) {
 	return
 }
 
+// fillPatterns defined the pattern types of the cell fill, the index of the
+// pattern type is the pattern ID of the fill settings.
+var fillPatterns = []string{
+	"none",
+	"solid",
+	"mediumGray",
+	"darkGray",
+	"lightGray",
+	"darkHorizontal",
+	"darkVertical",
+	"darkDown",
+	"darkUp",
+	"darkGrid",
+	"darkTrellis",
+	"lightHorizontal",
+	"lightVertical",
+	"lightDown",
+	"lightUp",
+	"lightGrid",
+	"lightTrellis",
+	"gray125",
+	"gray0625",
+}
+
+// fillGradientVariants defined the degrees of the linear gradient fill, the
+// index of the degree is the shading ID of the fill settings.
+var fillGradientVariants = []float64{
+	90,
+	0,
+	45,
+	135,
+}
+
+// borderStyles defined the line styles of the cell border, the index of the
+// line style is the style ID of the border settings.
+var borderStyles = []string{
+	"none",
+	"thin",
+	"medium",
+	"dashed",
+	"dotted",
+	"thick",
+	"double",
+	"hair",
+	"mediumDashed",
+	"dashDot",
+	"mediumDashDot",
+	"dashDotDot",
+	"mediumDashDotDot",
+	"slantDashDot",
+}
+
 // newFills provides a function to add fill elements in the styles.xml by
 // given cell format settings.
 func newFills(style *Style, fg bool) *xlsxFill {
-	var patterns = []string{
-		"none",
-		"solid",
-		"mediumGray",
-		"darkGray",
-		"lightGray",
-		"darkHorizontal",
-		"darkVertical",
-		"darkDown",
-		"darkUp",
-		"darkGrid",
-		"darkTrellis",
-		"lightHorizontal",
-		"lightVertical",
-		"lightDown",
-		"lightUp",
-		"lightGrid",
-		"lightTrellis",
-		"gray125",
-		"gray0625",
-	}
-
-	var variants = []float64{
-		90,
-		0,
-		45,
-		135,
-	}
-
 	var fill xlsxFill
 	switch style.Fill.Type {
 	case "gradient":
@@ -2404,7 +2977,7 @@ func newFills(style *Style, fg bool) *xlsxFill {
 		var gradient xlsxGradientFill
 		switch style.Fill.Shading {
 		case 0, 1, 2, 3:
-			gradient.Degree = variants[style.Fill.Shading]
+			gradient.Degree = fillGradientVariants[style.Fill.Shading]
 		case 4:
 			gradient.Type = "path"
 		case 5:
@@ -2431,7 +3004,7 @@ func newFills(style *Style, fg bool) *xlsxFill {
 			break
 		}
 		var pattern xlsxPatternFill
-		pattern.PatternType = patterns[style.Fill.Pattern]
+		pattern.PatternType = fillPatterns[style.Fill.Pattern]
 		if fg {
 			if pattern.FgColor == nil {
 				pattern.FgColor = new(xlsxColor)
@@ -2500,23 +3073,6 @@ func getBorderID(styleSheet *xlsxStyleSheet, style *Style) (borderID int) {
 // newBorders provides a function to add border elements in the styles.xml by
 // given borders format settings.
 func newBorders(style *Style) *xlsxBorder {
-	var styles = []string{
-		"none",
-		"thin",
-		"medium",
-		"dashed",
-		"dotted",
-		"thick",
-		"double",
-		"hair",
-		"mediumDashed",
-		"dashDot",
-		"mediumDashDot",
-		"dashDotDot",
-		"mediumDashDotDot",
-		"slantDashDot",
-	}
-
 	var border xlsxBorder
 	for _, v := range style.Border {
 		if 0 <= v.Style && v.Style < 14 {
@@ -2524,23 +3080,23 @@ func newBorders(style *Style) *xlsxBorder {
 			color.RGB = getPaletteColor(v.Color)
 			switch v.Type {
 			case "left":
-				border.Left.Style = styles[v.Style]
+				border.Left.Style = borderStyles[v.Style]
 				border.Left.Color = &color
 			case "right":
-				border.Right.Style = styles[v.Style]
+				border.Right.Style = borderStyles[v.Style]
 				border.Right.Color = &color
 			case "top":
-				border.Top.Style = styles[v.Style]
+				border.Top.Style = borderStyles[v.Style]
 				border.Top.Color = &color
 			case "bottom":
-				border.Bottom.Style = styles[v.Style]
+				border.Bottom.Style = borderStyles[v.Style]
 				border.Bottom.Color = &color
 			case "diagonalUp":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = borderStyles[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalUp = true
 			case "diagonalDown":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = borderStyles[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalDown = true
 			}
@@ -2598,6 +3154,122 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 	return f.prepareCellStyle(ws, col, cellData.S), err
 }
 
+// GetCellStyles provides a function to get the style indexes of the cells in
+// the range by given worksheet name and range reference. The style indexes
+// will be returned row by row in one pass of the worksheet, which are the
+// same as the GetCellStyle function returns for each cell, and the cells
+// will not be created in the worksheet. For example, get the style indexes
+// of the cells in the range A1:D10 of Sheet1:
+//
+//    styles, err := f.GetCellStyles("Sheet1", "A1:D10")
+//
+func (f *File) GetCellStyles(sheet, rangeRef string) ([][]int, error) {
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := f.areaRefToCoordinates(rangeRef)
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return f.getCellStyles(ws, coordinates)
+}
+
+// GetRowStyles provides a function to get the style indexes of the cells in
+// the row by given worksheet name and row number. The style indexes of the
+// cells from the first column to the last cell of the row will be returned,
+// which are the same as the GetCellStyle function returns for each cell. For
+// example, get the style indexes of the cells in the second row of Sheet1:
+//
+//    styles, err := f.GetRowStyles("Sheet1", 2)
+//
+func (f *File) GetRowStyles(sheet string, row int) ([]int, error) {
+	if row < 1 || row > TotalRows {
+		return nil, newInvalidRowNumberError(row)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	ws.Lock()
+	var cols int
+	if row <= len(ws.SheetData.Row) {
+		cols = len(ws.SheetData.Row[row-1].C)
+	}
+	ws.Unlock()
+	if cols == 0 {
+		return []int{}, err
+	}
+	styles, err := f.getCellStyles(ws, []int{1, row, cols, row})
+	if err != nil {
+		return nil, err
+	}
+	return styles[0], err
+}
+
+// getCellStyles provides a function to get the style indexes of the cells by
+// given worksheet and the sorted coordinates of the range, the style of the
+// merged cell is the style of the top left cell of the merged range, and the
+// style of the column will be used for the cell without style.
+func (f *File) getCellStyles(ws *xlsxWorksheet, coordinates []int) ([][]int, error) {
+	var merges [][]int
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			rect, err := f.areaRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return nil, err
+			}
+			_ = sortCoordinates(rect)
+			merges = append(merges, rect)
+		}
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	styles := make([][]int, 0, coordinates[3]-coordinates[1]+1)
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		rowStyles := make([]int, 0, coordinates[2]-coordinates[0]+1)
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			c, r := col, row
+			for _, rect := range merges {
+				if rect[0] <= col && col <= rect[2] && rect[1] <= row && row <= rect[3] {
+					c, r = rect[0], rect[1]
+				}
+			}
+			var style int
+			if r <= len(ws.SheetData.Row) && c <= len(ws.SheetData.Row[r-1].C) {
+				style = ws.SheetData.Row[r-1].C[c-1].S
+			}
+			rowStyles = append(rowStyles, f.prepareCellStyle(ws, c, style))
+		}
+		styles = append(styles, rowStyles)
+	}
+	return styles, nil
+}
+
+// GetCellQuotePrefix provides a function to get the quote prefix flag of the
+// cell style by given worksheet name and cell coordinates. The flag indicates
+// that the text of the cell will be displayed as entered. For example, get
+// the quote prefix flag of Sheet1!A7:
+//
+//    quotePrefix, err := f.GetCellQuotePrefix("Sheet1", "A7")
+//
+func (f *File) GetCellQuotePrefix(sheet, axis string) (bool, error) {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return false, err
+	}
+	s := f.stylesReader()
+	if s.CellXfs == nil || styleID >= len(s.CellXfs.Xf) {
+		return false, err
+	}
+	xf := s.CellXfs.Xf[styleID]
+	return xf.QuotePrefix != nil && *xf.QuotePrefix, err
+}
+
 // SetCellStyle provides a function to add style attribute for cells by given
 // worksheet name, coordinate area and style ID. Note that diagonalDown and
 // diagonalUp type border should be use same color in the same coordinate
@@ -2705,12 +3377,110 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 	return err
 }
 
+// SetCellStyles provides a function to set the style for the given cells
+// in one pass by given worksheet name, cell references and style ID. All
+// cell references will be validated before the worksheet is updated, it is
+// faster than calling the SetCellStyle function for each cell when styling
+// a large number of scattered cells. For example, set the style for the
+// cells A1, C3 and E5 on Sheet1:
+//
+//    err := f.SetCellStyles("Sheet1", []string{"A1", "C3", "E5"}, style)
+//
+func (f *File) SetCellStyles(sheet string, cells []string, styleID int) error {
+	areas := make([][]int, 0, len(cells))
+	for _, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			return err
+		}
+		areas = append(areas, []int{col, row, col, row})
+	}
+	return f.setCellStyleAreas(sheet, areas, styleID)
+}
+
+// SetRangeStyles provides a function to set the style for the given cell
+// ranges in one pass by given worksheet name, the list of cell ranges and
+// style ID. Each item of the list could be a cell range or a single cell
+// reference. For example, set the style for the ranges A1:C3 and E5:F6 and
+// the cell H8 on Sheet1:
+//
+//    err := f.SetRangeStyles("Sheet1", []string{"A1:C3", "E5:F6", "H8"}, style)
+//
+func (f *File) SetRangeStyles(sheet string, ranges []string, styleID int) error {
+	areas := make([][]int, 0, len(ranges))
+	for _, ref := range ranges {
+		rng := strings.Split(ref, ":")
+		if len(rng) == 1 {
+			rng = append(rng, rng[0])
+		}
+		if len(rng) != 2 {
+			return ErrParameterInvalid
+		}
+		coordinates, err := areaRangeToCoordinates(rng[0], rng[1])
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+		areas = append(areas, coordinates)
+	}
+	return f.setCellStyleAreas(sheet, areas, styleID)
+}
+
+// setCellStyleAreas provides a function to set the style for the cell areas
+// by given worksheet name, the coordinates of the areas and style ID. The
+// missing rows and cells of the areas will be created in one pass.
+func (f *File) setCellStyleAreas(sheet string, areas [][]int, styleID int) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || len(areas) == 0 {
+		return err
+	}
+	var maxRow int
+	for _, area := range areas {
+		if area[3] > maxRow {
+			maxRow = area[3]
+		}
+	}
+	colCount := make([]int, maxRow+1)
+	for _, area := range areas {
+		for row := area[1]; row <= area[3]; row++ {
+			if area[2] > colCount[row] {
+				colCount[row] = area[2]
+			}
+		}
+	}
+	prepareSheetXML(ws, colCount[maxRow], maxRow)
+	ws.Lock()
+	defer ws.Unlock()
+	for row := 1; row < maxRow; row++ {
+		if colCount[row] > 0 {
+			fillColumns(&ws.SheetData.Row[row-1], colCount[row], row)
+		}
+	}
+	for _, area := range areas {
+		for row := area[1] - 1; row < area[3]; row++ {
+			for col := area[0] - 1; col < area[2]; col++ {
+				ws.SheetData.Row[row].C[col].S = styleID
+			}
+		}
+	}
+	return err
+}
+
 // SetConditionalFormat provides a function to create conditional formatting
 // rule for cell value. Conditional formatting is a feature of Excel which
 // allows you to apply a format to a cell or a range of cells based on certain
 // criteria.
 //
-// The type option is a required parameter and it has no default value.
+// The range reference could be the structured reference of the table
+// columns in the worksheet, such as Sales[Amount], Sales[[Region]:[Amount]]
+// or Sales[#Data], which will be applied on the data rows of the table
+// columns, and the range will be expanded or shrunk with the table when
+// resizing the table by the SetTableRange function. For example, highlight
+// the duplicate values in the Region column of the Sales table:
+//
+//    err := f.SetConditionalFormat("Sheet1", "Sales[Region]", fmt.Sprintf(`[{"type":"duplicate","criteria":"=","format":%d}]`, format))
+//
+// The type option is a required parameter and it has no default value.
 // Allowable type values and their associated parameters are:
 //
 //     Type          | Parameters
@@ -2758,6 +3528,11 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //                   | max_value
 //                   | bar_color
 //     formula       | criteria
+//     icon_set      | icon_style
+//                   | reverse_icons
+//                   | icons_only
+//                   | icons
+//                   | custom_icons
 //
 // The criteria parameter is used to set the criteria by which the cell data
 // will be evaluated. It has no default value. The most common criteria as
@@ -2798,6 +3573,13 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //    }
 //    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"6"}]`, format))
 //
+// The style parameter could be used instead of the format to specify the
+// style settings of the format directly, such as the style returned by the
+// GetConditionalFormats function, the existing format with the same settings
+// will be reused:
+//
+//    f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"cell","criteria":">","style":{"font":{"color":"#9A0511"}},"value":"6"}]`)
+//
 // Note: In Excel, a conditional format is superimposed over the existing cell
 // format and not all cell format properties can be modified. Properties that
 // cannot be modified in a conditional format are font name, font size,
@@ -2854,6 +3636,41 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 //    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"top","criteria":"=","format":%d,"value":"6","percent":true}]`, format))
 //
+// type: bottom - The bottom type is used to specify the bottom n values by
+// number or percentage in a range. It takes the same parameters as top, see
+// above.
+//
+//    // Top/Bottom rules: Bottom 10%.
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"bottom","criteria":"=","format":%d,"value":"10","percent":true}]`, format))
+//
+// type: text - The text type is used to specify Excel's "Specific Text" style
+// conditional format, the criteria could be containing, not containing,
+// begins with or ends with:
+//
+//    // Highlight cells rules: Text that Contains...
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"text","criteria":"containing","format":%d,"value":"foo"}]`, format))
+//
+// type: time_period - The time_period type is used to specify Excel's "Dates
+// Occurring" style conditional format, the criteria could be yesterday,
+// today, tomorrow, last 7 days, last week, this week, next week, last month,
+// this month or next month:
+//
+//    // Highlight cells rules: A Date Occurring...
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"time_period","criteria":"last week","format":%d}]`, format))
+//
+// type: blanks - The blanks type is used to highlight blank cells in a range:
+//
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"blanks","format":%d}]`, format))
+//
+// type: no_blanks - The no_blanks type is used to highlight non blank cells
+// in a range. Similarly, the errors and no_errors type are used to highlight
+// error and non error cells in a range:
+//
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"no_blanks","format":%d}]`, format))
+//
+// The formulas of the text, time_period, blanks, no_blanks, errors and
+// no_errors type are relative to the top left cell of the range.
+//
 // type: 2_color_scale - The 2_color_scale type is used to specify Excel's "2
 // Color Scale" style conditional format:
 //
@@ -2916,46 +3733,211 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 // bar_color - Used for data_bar. Same as min_color, see above.
 //
+// bar_solid - Used for data_bar. Fill the bars with the solid color instead
+// of the gradient color.
+//
+// bar_border_color - Used for data_bar. Set the border color of the bars.
+//
+// bar_negative_color - Used for data_bar. Set the fill color of the bars for
+// negative values, the bars for negative values use the bar_color by
+// default.
+//
+// bar_negative_border_color - Used for data_bar. Set the border color of the
+// bars for negative values, it's available when the bar_border_color is
+// specified.
+//
+// bar_direction - Used for data_bar. Set the direction of the bars, the
+// available directions are context (default), leftToRight and rightToLeft.
+//
+// bar_axis_position - Used for data_bar. Set the position of the axis between
+// the bars for positive and negative values, the available positions are
+// automatic (default), middle and none.
+//
+// bar_axis_color - Used for data_bar. Set the color of the axis, default is
+// black. For example, create solid data bars with the border, red data bars
+// for negative values and the axis in the middle of the cells:
+//
+//    f.SetConditionalFormat("Sheet1", "O1:O10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_solid":true,"bar_border_color":"#0070C0","bar_negative_color":"#FF0000","bar_axis_position":"middle"}]`)
+//
+// type: icon_set - The icon_set type is used to specify Excel's "Icon Set"
+// style conditional format:
+//
+//    // Icon Sets: 3 Arrows.
+//    f.SetConditionalFormat("Sheet1", "L1:L10", `[{"type":"icon_set","icon_style":"3Arrows"}]`)
+//
+// icon_style - The icon_style parameter is used to specify the icon set,
+// default is 3TrafficLights1. The available icon sets are:
+//
+//    3Arrows            4Arrows            5Arrows
+//    3ArrowsGray        4ArrowsGray        5ArrowsGray
+//    3Flags             4Rating            5Boxes
+//    3Signs             4RedToBlack        5Quarters
+//    3Stars             4TrafficLights     5Rating
+//    3Symbols
+//    3Symbols2
+//    3TrafficLights1
+//    3TrafficLights2
+//    3Triangles
+//
+// reverse_icons - Reverse the display order of the icons.
+//
+// icons_only - Only show the icons in the cells and hide the cell values.
+//
+// icons - The icons parameter is used to specify the thresholds of the icons
+// from the first icon, and the last icon will be used for the rest values.
+// Each threshold has the criteria ">=" (default) or ">", the type number,
+// percent (default), percentile or formula and the value. For example, show
+// the first icon for the values greater than or equal to 90, the second icon
+// for the values greater than 50 percentile and the last icon for the rest
+// values:
+//
+//    f.SetConditionalFormat("Sheet1", "M1:M10", `[{"type":"icon_set","icon_style":"3Flags","icons":[{"criteria":">=","type":"number","value":"90"},{"criteria":">","type":"percentile","value":"50"}]}]`)
+//
+// custom_icons - The custom_icons parameter is used to mix the icons from
+// different icon sets in display order, the icon_style of each custom icon
+// specified the icon set and the icon_index specified the zero-based index of
+// the icon in the icon set, use the NoIcons icon style to hide the icon. For
+// example, use a green flag, a yellow star and no icon:
+//
+//    f.SetConditionalFormat("Sheet1", "N1:N10", `[{"type":"icon_set","icon_style":"3Flags","custom_icons":[{"icon_style":"3Flags","icon_index":2},{"icon_style":"3Stars","icon_index":1},{"icon_style":"NoIcons","icon_index":0}]}]`)
+//
+// priority - The priority parameter is used to specify the evaluation order
+// of the rules in the worksheet, the rule with the smaller priority number
+// will be evaluated first. The rules have the priorities in the order of the
+// settings by default, use the SetConditionalFormatPriority function to
+// reorder the rules after creation.
+//
+// stop_if_true - Stop evaluating the rules with the larger priority numbers
+// if this rule evaluates to true. For example, highlight the values greater than 90
+// and skip the data bar for these values:
+//
+//    f.SetConditionalFormat("Sheet1", "P1:P10", fmt.Sprintf(`[{"type":"cell","criteria":">","value":"90","format":%d,"priority":1,"stop_if_true":true},{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","priority":2}]`, format))
+//
 func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
-	var format []*formatConditional
+	var format []*ConditionalFormatOptions
 	err := json.Unmarshal([]byte(formatSet), &format)
 	if err != nil {
 		return err
 	}
-	drawContFmtFunc := map[string]func(p int, ct string, fmtCond *formatConditional) *xlsxCfRule{
-		"cellIs":          drawCondFmtCellIs,
-		"top10":           drawCondFmtTop10,
-		"aboveAverage":    drawCondFmtAboveAverage,
-		"duplicateValues": drawCondFmtDuplicateUniqueValues,
-		"uniqueValues":    drawCondFmtDuplicateUniqueValues,
-		"2_color_scale":   drawCondFmtColorScale,
-		"3_color_scale":   drawCondFmtColorScale,
-		"dataBar":         drawCondFmtDataBar,
-		"expression":      drawConfFmtExp,
+	return f.setConditionalFormat(sheet, area, format)
+}
+
+// SetConditionalFormatOptions provides a function to create conditional
+// formatting rules by given worksheet name, range reference and typed format
+// settings. The fields of the settings are same as the parameters of the
+// SetConditionalFormat. For example, highlight the top 10 percent values and
+// the cells which containing the text "error" in Sheet1!A1:A10:
+//
+//    err := f.SetConditionalFormatOptions("Sheet1", "A1:A10", []excelize.ConditionalFormatOptions{
+//        {Type: "top", Criteria: "=", Value: "10", Percent: true, Format: format1},
+//        {Type: "text", Criteria: "containing", Value: "error", Format: format2},
+//    })
+//
+func (f *File) SetConditionalFormatOptions(sheet, area string, opts []ConditionalFormatOptions) error {
+	format := make([]*ConditionalFormatOptions, len(opts))
+	for i := range opts {
+		opt := opts[i]
+		format[i] = &opt
+	}
+	return f.setConditionalFormat(sheet, area, format)
+}
+
+// setConditionalFormat provides a function to create conditional formatting
+// rules by given worksheet name, range reference and format settings.
+func (f *File) setConditionalFormat(sheet, area string, format []*ConditionalFormatOptions) error {
+	drawContFmtFunc := map[string]func(p int, ct, ref string, fmtCond *ConditionalFormatOptions) *xlsxCfRule{
+		"cellIs":            drawCondFmtCellIs,
+		"top10":             drawCondFmtTop10,
+		"aboveAverage":      drawCondFmtAboveAverage,
+		"duplicateValues":   drawCondFmtDuplicateUniqueValues,
+		"uniqueValues":      drawCondFmtDuplicateUniqueValues,
+		"text":              drawCondFmtText,
+		"timePeriod":        drawCondFmtTimePeriod,
+		"containsBlanks":    drawCondFmtBlanksErrors,
+		"notContainsBlanks": drawCondFmtBlanksErrors,
+		"containsErrors":    drawCondFmtBlanksErrors,
+		"notContainsErrors": drawCondFmtBlanksErrors,
+		"2_color_scale":     drawCondFmtColorScale,
+		"3_color_scale":     drawCondFmtColorScale,
+		"dataBar":           drawCondFmtDataBar,
+		"expression":        drawConfFmtExp,
+		"iconSet":           drawCondFmtIconSet,
 	}
+	// The criteria is required for these types of rule.
+	criteriaRequired := map[string]bool{"cellIs": true, "text": true, "timePeriod": true}
 
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	cfRule := []*xlsxCfRule{}
+	if area, err = f.resolveStructRefs(sheet, area); err != nil {
+		return err
+	}
+	ref := condFmtTopLeftCell(area)
+	cfRule, x14CfRule := []*xlsxCfRule{}, []*xlsxX14CfRule{}
 	for p, v := range format {
 		var vt, ct string
 		var ok bool
+		if v.Style != nil {
+			v.Format = f.newConditionalStyle(v.Style, true)
+		}
 		// "type" is a required parameter, check for valid validation types.
 		vt, ok = validType[v.Type]
 		if ok {
+			if vt == "iconSet" {
+				if err = checkCondFmtIconSet(v); err != nil {
+					return err
+				}
+				if isX14CondFmtIconSet(v) {
+					rule, err := drawCondFmtX14IconSet(p, v)
+					if err != nil {
+						return err
+					}
+					if v.Priority > 0 {
+						rule.Priority = v.Priority
+					}
+					rule.StopIfTrue = v.StopIfTrue
+					x14CfRule = append(x14CfRule, rule)
+					continue
+				}
+			}
+			if vt == "dataBar" {
+				if err = checkCondFmtDataBar(v); err != nil {
+					return err
+				}
+			}
 			// Check for valid criteria types.
 			ct, ok = criteriaType[v.Criteria]
-			if ok || vt == "expression" {
+			if ok || !criteriaRequired[vt] {
 				drawfunc, ok := drawContFmtFunc[vt]
 				if ok {
-					cfRule = append(cfRule, drawfunc(p, ct, v))
+					if rule := drawfunc(p, ct, ref, v); rule != nil {
+						if v.Priority > 0 {
+							rule.Priority = v.Priority
+						}
+						rule.StopIfTrue = v.StopIfTrue
+						if vt == "dataBar" && isX14CondFmtDataBar(v) {
+							x14Rule, err := drawCondFmtX14DataBar(rule, v)
+							if err != nil {
+								return err
+							}
+							x14CfRule = append(x14CfRule, x14Rule)
+						}
+						cfRule = append(cfRule, rule)
+					}
 				}
 			}
 		}
 	}
-
+	if len(x14CfRule) > 0 {
+		if err = f.appendCondFmtX14(ws, area, x14CfRule); err != nil {
+			return err
+		}
+		f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+		if len(cfRule) == 0 {
+			return err
+		}
+	}
 	ws.ConditionalFormatting = append(ws.ConditionalFormatting, &xlsxConditionalFormatting{
 		SQRef:  area,
 		CfRule: cfRule,
@@ -2963,6 +3945,127 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 	return err
 }
 
+// condFmtTopLeftCell provides a function to get the top left cell reference
+// of the first range in the conditional formatting range reference, the
+// formulas of the text, time period, blanks and errors rules are relative to
+// this cell.
+func condFmtTopLeftCell(area string) string {
+	ref := strings.Split(strings.Split(strings.TrimSpace(area), " ")[0], ":")[0]
+	return strings.Replace(ref, "$", "", -1)
+}
+
+// GetConditionalFormats returns conditional format settings by given
+// worksheet name, the key of the result is the range reference and the value
+// is the format settings in the same JSON format as the SetConditionalFormat
+// function accepted. The priority and the resolved style definition of each
+// rule are also returned, so the rules could be cloned into another workbook
+// by the SetConditionalFormat function. For example:
+//
+//    formats, err := f.GetConditionalFormats("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for area, format := range formats {
+//        fmt.Println(area, format)
+//    }
+//
+func (f *File) GetConditionalFormats(sheet string) (map[string]string, error) {
+	conditionalFormats := make(map[string]string)
+	formats, err := f.GetConditionalFormatOptions(sheet)
+	if err != nil {
+		return conditionalFormats, err
+	}
+	for area, format := range formats {
+		formatSet, _ := json.Marshal(format)
+		conditionalFormats[area] = string(formatSet)
+	}
+	return conditionalFormats, err
+}
+
+// GetConditionalFormatOptions provides a function to get the typed
+// conditional format settings by given worksheet name, the key of the result
+// is the range reference and the value is the rules of the range, include the
+// types, criteria, priority and the resolved style definition of the rules.
+// For example, get the rules and their styles in Sheet1:
+//
+//    formats, err := f.GetConditionalFormatOptions("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for area, rules := range formats {
+//        for _, rule := range rules {
+//            fmt.Println(area, rule.Type, rule.Criteria, rule.Priority, rule.Style)
+//        }
+//    }
+//
+func (f *File) GetConditionalFormatOptions(sheet string) (map[string][]ConditionalFormatOptions, error) {
+	conditionalFormats := make(map[string][]ConditionalFormatOptions)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return conditionalFormats, err
+	}
+	extractContFmtFunc := map[string]func(c *xlsxCfRule) *ConditionalFormatOptions{
+		"cellIs":            extractCondFmtCellIs,
+		"top10":             extractCondFmtTop10,
+		"aboveAverage":      extractCondFmtAboveAverage,
+		"duplicateValues":   extractCondFmtDuplicateUniqueValues,
+		"uniqueValues":      extractCondFmtDuplicateUniqueValues,
+		"containsText":      extractCondFmtText,
+		"notContainsText":   extractCondFmtText,
+		"beginsWith":        extractCondFmtText,
+		"endsWith":          extractCondFmtText,
+		"timePeriod":        extractCondFmtTimePeriod,
+		"containsBlanks":    extractCondFmtBlanksErrors,
+		"notContainsBlanks": extractCondFmtBlanksErrors,
+		"containsErrors":    extractCondFmtBlanksErrors,
+		"notContainsErrors": extractCondFmtBlanksErrors,
+		"colorScale":        extractCondFmtColorScale,
+		"dataBar":           extractCondFmtDataBar,
+		"expression":        extractCondFmtExp,
+		"iconSet":           extractCondFmtIconSet,
+	}
+	x14CondFmts, err := f.getCondFmtX14(ws)
+	if err != nil {
+		return conditionalFormats, err
+	}
+	x14DataBars := make(map[string]*decodeX14DataBar)
+	for _, cf := range x14CondFmts {
+		for _, cr := range cf.CfRule {
+			if cr.Type == "dataBar" && cr.DataBar != nil {
+				x14DataBars[cr.ID] = cr.DataBar
+			}
+		}
+	}
+	theme := f.themeReader()
+	for _, cf := range ws.ConditionalFormatting {
+		for _, cr := range cf.CfRule {
+			if extractFunc, ok := extractContFmtFunc[cr.Type]; ok {
+				format := extractFunc(cr)
+				format.Priority, format.StopIfTrue = cr.Priority, cr.StopIfTrue
+				if dataBar, ok := x14DataBars[f.getCondFmtX14RuleID(cr)]; ok && cr.Type == "dataBar" {
+					extractCondFmtX14DataBar(format, dataBar)
+				}
+				if cr.DxfID != nil {
+					format.Style = f.getConditionalStyle(*cr.DxfID, theme)
+				}
+				conditionalFormats[cf.SQRef] = append(conditionalFormats[cf.SQRef], *format)
+			}
+		}
+	}
+	for _, cf := range x14CondFmts {
+		for _, cr := range cf.CfRule {
+			if cr.Type == "iconSet" && cr.IconSet != nil {
+				format := extractCondFmtX14IconSet(cr)
+				format.Priority, format.StopIfTrue = cr.Priority, cr.StopIfTrue
+				conditionalFormats[cf.Sqref] = append(conditionalFormats[cf.Sqref], *format)
+			}
+		}
+	}
+	return conditionalFormats, err
+}
+
 // UnsetConditionalFormat provides a function to unset the conditional format
 // by given worksheet name and range.
 func (f *File) UnsetConditionalFormat(sheet, area string) error {
@@ -2979,10 +4082,165 @@ func (f *File) UnsetConditionalFormat(sheet, area string) error {
 	return nil
 }
 
+// condFmtRule directly maps the conditional formatting rule in the worksheet,
+// the rule in the worksheet extension list is located by the index of the
+// rule in the extension list.
+type condFmtRule struct {
+	sqref    string
+	priority int
+	rule     *xlsxCfRule
+	x14Index int
+}
+
+// x14CfRuleTagRegexp defined the regular expression to match the start tags
+// of the conditional formatting rules in the worksheet extension list.
+var x14CfRuleTagRegexp = regexp.MustCompile(`<(?:[\w-]+:)?cfRule\b[^>]*>`)
+
+// getCondFmtRules provides a function to get the conditional formatting rules
+// and the rules in the worksheet extension list in the order of the ranges,
+// the rules which are referred by the other rules will be ignored.
+func (f *File) getCondFmtRules(ws *xlsxWorksheet) ([]*condFmtRule, error) {
+	var rules []*condFmtRule
+	for _, cf := range ws.ConditionalFormatting {
+		for _, cr := range cf.CfRule {
+			rules = append(rules, &condFmtRule{sqref: cf.SQRef, priority: cr.Priority, rule: cr, x14Index: -1})
+		}
+	}
+	x14CondFmts, err := f.getCondFmtX14(ws)
+	var x14Index int
+	for _, cf := range x14CondFmts {
+		for _, cr := range cf.CfRule {
+			if cr.Priority > 0 {
+				rules = append(rules, &condFmtRule{sqref: cf.Sqref, priority: cr.Priority, x14Index: x14Index})
+			}
+			x14Index++
+		}
+	}
+	return rules, err
+}
+
+// getCondFmtRule provides a function to get the conditional formatting rule
+// by given worksheet name, range reference and the index of the rule in the
+// range.
+func (f *File) getCondFmtRule(sheet, area string, index int) (*xlsxWorksheet, []*condFmtRule, *condFmtRule, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return ws, nil, nil, err
+	}
+	rules, err := f.getCondFmtRules(ws)
+	if err != nil {
+		return ws, rules, nil, err
+	}
+	var count int
+	for _, rule := range rules {
+		if rule.sqref != area {
+			continue
+		}
+		if count == index {
+			return ws, rules, rule, err
+		}
+		count++
+	}
+	return ws, rules, nil, ErrConditionalFormatNotExist
+}
+
+// setCondFmtX14RuleAttr provides a function to set the attribute of the
+// rules in the worksheet extension list by given attribute name and values,
+// the key of the values is the index of the rule in the extension list.
+func setCondFmtX14RuleAttr(ws *xlsxWorksheet, name string, values map[int]string) {
+	if ws.ExtLst == nil || len(values) == 0 {
+		return
+	}
+	var idx int
+	attrExp := regexp.MustCompile(`\s` + name + `="[^"]*"`)
+	ws.ExtLst.Ext = x14CfRuleTagRegexp.ReplaceAllStringFunc(ws.ExtLst.Ext, func(tag string) string {
+		value, ok := values[idx]
+		idx++
+		if !ok {
+			return tag
+		}
+		attr := fmt.Sprintf(` %s="%s"`, name, value)
+		if attrExp.MatchString(tag) {
+			return attrExp.ReplaceAllLiteralString(tag, attr)
+		}
+		if strings.HasSuffix(tag, "/>") {
+			return strings.TrimSuffix(tag, "/>") + attr + "/>"
+		}
+		return strings.TrimSuffix(tag, ">") + attr + ">"
+	})
+}
+
+// SetConditionalFormatPriority provides a function to move the conditional
+// formatting rule to the given priority by given worksheet name, range
+// reference and the zero-based index of the rule in the range, the index is
+// the same as the order of the rules of the range returned by the
+// GetConditionalFormatOptions function. The priorities of all rules in the
+// worksheet will be renumbered from 1 in the evaluation order, the rule with
+// the smaller priority number will be evaluated first. For example, move the second
+// rule of Sheet1!A1:A10 to the top of the rules in the worksheet:
+//
+//    err := f.SetConditionalFormatPriority("Sheet1", "A1:A10", 1, 1)
+//
+func (f *File) SetConditionalFormatPriority(sheet, area string, index, priority int) error {
+	if priority < 1 {
+		return ErrParameterInvalid
+	}
+	ws, rules, target, err := f.getCondFmtRule(sheet, area, index)
+	if err != nil {
+		return err
+	}
+	ordered := make([]*condFmtRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule != target {
+			ordered = append(ordered, rule)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].priority < ordered[j].priority })
+	if priority > len(rules) {
+		priority = len(rules)
+	}
+	ordered = append(ordered[:priority-1], append([]*condFmtRule{target}, ordered[priority-1:]...)...)
+	x14Priorities := make(map[int]string)
+	for i, rule := range ordered {
+		if rule.priority = i + 1; rule.rule != nil {
+			rule.rule.Priority = rule.priority
+			continue
+		}
+		x14Priorities[rule.x14Index] = strconv.Itoa(rule.priority)
+	}
+	setCondFmtX14RuleAttr(ws, "priority", x14Priorities)
+	return err
+}
+
+// SetConditionalFormatStopIfTrue provides a function to set if stop
+// evaluating the conditional formatting rules with the larger priority
+// numbers when the rule evaluates to true, by given worksheet name, range reference and
+// the zero-based index of the rule in the range. For example, stop evaluating
+// the other rules if the first rule of Sheet1!A1:A10 evaluates to true:
+//
+//    err := f.SetConditionalFormatStopIfTrue("Sheet1", "A1:A10", 0, true)
+//
+func (f *File) SetConditionalFormatStopIfTrue(sheet, area string, index int, stopIfTrue bool) error {
+	ws, _, target, err := f.getCondFmtRule(sheet, area, index)
+	if err != nil {
+		return err
+	}
+	if target.rule != nil {
+		target.rule.StopIfTrue = stopIfTrue
+		return err
+	}
+	value := "0"
+	if stopIfTrue {
+		value = "1"
+	}
+	setCondFmtX14RuleAttr(ws, "stopIfTrue", map[int]string{target.x14Index: value})
+	return err
+}
+
 // drawCondFmtCellIs provides a function to create conditional formatting rule
 // for cell value (include between, not between, equal, not equal, greater
 // than and less than) by given priority, criteria type and format settings.
-func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtCellIs(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3005,10 +4263,11 @@ func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule
 // drawCondFmtTop10 provides a function to create conditional formatting rule
 // for top N (default is top 10) by given priority, criteria type and format
 // settings.
-func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtTop10(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
+		Bottom:   format.Type == "bottom",
 		Rank:     10,
 		DxfID:    &format.Format,
 		Percent:  format.Percent,
@@ -3023,7 +4282,7 @@ func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
 // drawCondFmtAboveAverage provides a function to create conditional
 // formatting rule for above average and below average by given priority,
 // criteria type and format settings.
-func drawCondFmtAboveAverage(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtAboveAverage(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority:     p + 1,
 		Type:         validType[format.Type],
@@ -3035,7 +4294,7 @@ func drawCondFmtAboveAverage(p int, ct string, format *formatConditional) *xlsxC
 // drawCondFmtDuplicateUniqueValues provides a function to create conditional
 // formatting rule for duplicate and unique values by given priority, criteria
 // type and format settings.
-func drawCondFmtDuplicateUniqueValues(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtDuplicateUniqueValues(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3043,10 +4302,86 @@ func drawCondFmtDuplicateUniqueValues(p int, ct string, format *formatConditiona
 	}
 }
 
+// drawCondFmtText provides a function to create conditional formatting rule
+// for text (include containing, not containing, begins with and ends with)
+// by given priority, criteria type, top left cell reference and format
+// settings.
+func drawCondFmtText(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
+	text := strings.Replace(format.Value, `"`, `""`, -1)
+	formula, ok := map[string]string{
+		"containsText": fmt.Sprintf(`NOT(ISERROR(SEARCH("%s",%s)))`, text, ref),
+		"notContains":  fmt.Sprintf(`ISERROR(SEARCH("%s",%s))`, text, ref),
+		"beginsWith":   fmt.Sprintf(`LEFT(%s,LEN("%s"))="%s"`, ref, text, text),
+		"endsWith":     fmt.Sprintf(`RIGHT(%s,LEN("%s"))="%s"`, ref, text, text),
+	}[ct]
+	if !ok {
+		return nil
+	}
+	typ := ct
+	if ct == "notContains" {
+		typ = "notContainsText"
+	}
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     typ,
+		Operator: ct,
+		Text:     format.Value,
+		DxfID:    &format.Format,
+		Formula:  []string{formula},
+	}
+}
+
+// drawCondFmtTimePeriod provides a function to create conditional formatting
+// rule for dates occurring (include yesterday, today, tomorrow, last 7 days,
+// last week, this week, next week, last month, this month and next month) by
+// given priority, criteria type, top left cell reference and format settings.
+func drawCondFmtTimePeriod(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
+	formula, ok := map[string]string{
+		"yesterday": fmt.Sprintf("FLOOR(%s,1)=TODAY()-1", ref),
+		"today":     fmt.Sprintf("FLOOR(%s,1)=TODAY()", ref),
+		"tomorrow":  fmt.Sprintf("FLOOR(%s,1)=TODAY()+1", ref),
+		"last7Days": fmt.Sprintf("AND(TODAY()-FLOOR(%[1]s,1)<=6,FLOOR(%[1]s,1)<=TODAY())", ref),
+		"lastWeek":  fmt.Sprintf("AND(TODAY()-ROUNDDOWN(%[1]s,0)>=(WEEKDAY(TODAY())),TODAY()-ROUNDDOWN(%[1]s,0)<(WEEKDAY(TODAY())+7))", ref),
+		"thisWeek":  fmt.Sprintf("AND(TODAY()-ROUNDDOWN(%[1]s,0)<=WEEKDAY(TODAY())-1,ROUNDDOWN(%[1]s,0)-TODAY()<=7-WEEKDAY(TODAY()))", ref),
+		"nextWeek":  fmt.Sprintf("AND(ROUNDDOWN(%[1]s,0)-TODAY()>(7-WEEKDAY(TODAY())),ROUNDDOWN(%[1]s,0)-TODAY()<(15-WEEKDAY(TODAY())))", ref),
+		"lastMonth": fmt.Sprintf("AND(MONTH(%[1]s)=MONTH(EDATE(TODAY(),0-1)),YEAR(%[1]s)=YEAR(EDATE(TODAY(),0-1)))", ref),
+		"thisMonth": fmt.Sprintf("AND(MONTH(%[1]s)=MONTH(TODAY()),YEAR(%[1]s)=YEAR(TODAY()))", ref),
+		"nextMonth": fmt.Sprintf("AND(MONTH(%[1]s)=MONTH(EDATE(TODAY(),0+1)),YEAR(%[1]s)=YEAR(EDATE(TODAY(),0+1)))", ref),
+	}[ct]
+	if !ok {
+		return nil
+	}
+	return &xlsxCfRule{
+		Priority:   p + 1,
+		Type:       validType[format.Type],
+		TimePeriod: ct,
+		DxfID:      &format.Format,
+		Formula:    []string{formula},
+	}
+}
+
+// drawCondFmtBlanksErrors provides a function to create conditional
+// formatting rule for blanks, no blanks, errors and no errors by given
+// priority, criteria type, top left cell reference and format settings.
+func drawCondFmtBlanksErrors(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
+	vt := validType[format.Type]
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     vt,
+		DxfID:    &format.Format,
+		Formula: []string{map[string]string{
+			"containsBlanks":    fmt.Sprintf("LEN(TRIM(%s))=0", ref),
+			"notContainsBlanks": fmt.Sprintf("LEN(TRIM(%s))>0", ref),
+			"containsErrors":    fmt.Sprintf("ISERROR(%s)", ref),
+			"notContainsErrors": fmt.Sprintf("NOT(ISERROR(%s))", ref),
+		}[vt]},
+	}
+}
+
 // drawCondFmtColorScale provides a function to create conditional formatting
 // rule for color scale (include 2 color scale and 3 color scale) by given
 // priority, criteria type and format settings.
-func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtColorScale(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	minValue := format.MinValue
 	if minValue == "" {
 		minValue = "0"
@@ -3083,7 +4418,7 @@ func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfR
 
 // drawCondFmtDataBar provides a function to create conditional formatting
 // rule for data bar by given priority, criteria type and format settings.
-func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtDataBar(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3094,9 +4429,74 @@ func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule
 	}
 }
 
+// checkCondFmtDataBar provides a function to validate the data bar
+// conditional formatting rule settings.
+func checkCondFmtDataBar(format *ConditionalFormatOptions) error {
+	if _, ok := map[string]bool{"": true, "context": true, "leftToRight": true, "rightToLeft": true}[format.BarDirection]; !ok {
+		return newInvalidStyleAttrError("data bar direction", format.BarDirection)
+	}
+	if _, ok := map[string]bool{"": true, "automatic": true, "middle": true, "none": true}[format.BarAxisPosition]; !ok {
+		return newInvalidStyleAttrError("data bar axis position", format.BarAxisPosition)
+	}
+	return nil
+}
+
+// isX14CondFmtDataBar provides a function to check if the data bar
+// conditional formatting rule requires the settings in the worksheet
+// extension list.
+func isX14CondFmtDataBar(format *ConditionalFormatOptions) bool {
+	return format.BarSolid || format.BarBorderColor != "" || format.BarNegativeColor != "" ||
+		format.BarNegativeBorderColor != "" || format.BarDirection != "" ||
+		format.BarAxisPosition != "" || format.BarAxisColor != ""
+}
+
+// drawCondFmtX14DataBar provides a function to create the data bar
+// conditional formatting rule in the worksheet extension list by given
+// format settings, and refer it in the given rule by the ID of the rule.
+func drawCondFmtX14DataBar(c *xlsxCfRule, format *ConditionalFormatOptions) (*xlsxX14CfRule, error) {
+	id, err := newGUID()
+	if err != nil {
+		return nil, err
+	}
+	ext, _ := xml.Marshal(&xlsxX14CfRuleID{URI: ExtURIConditionalFormatRule, XMLNSX14: NameSpaceSpreadSheetX14.Value, ID: id})
+	c.ExtLst = &xlsxExtLst{Ext: string(ext)}
+	dataBar := &xlsxX14DataBar{MaxLength: 100, Direction: format.BarDirection, AxisPosition: format.BarAxisPosition}
+	if format.BarSolid {
+		dataBar.Gradient = boolPtr(false)
+	}
+	// The min and max types are stored as the automatic types, which include
+	// the zero value in the bars for negative values.
+	for _, cfvo := range [][]string{{format.MinType, format.MinValue, "min", "autoMin"}, {format.MaxType, format.MaxValue, "max", "autoMax"}} {
+		if cfvo[0] == "" || cfvo[0] == cfvo[2] {
+			dataBar.Cfvo = append(dataBar.Cfvo, &xlsxX14Cfvo{Type: cfvo[3]})
+			continue
+		}
+		dataBar.Cfvo = append(dataBar.Cfvo, &xlsxX14Cfvo{Type: cfvo[0], F: cfvo[1]})
+	}
+	if format.BarBorderColor != "" {
+		dataBar.Border = true
+		dataBar.BorderColor = &xlsxX14Color{RGB: getPaletteColor(format.BarBorderColor)}
+		if format.BarNegativeBorderColor != "" {
+			dataBar.NegativeBarBorderColorSameAsPositive = boolPtr(false)
+			dataBar.NegativeBorderColor = &xlsxX14Color{RGB: getPaletteColor(format.BarNegativeBorderColor)}
+		}
+	}
+	dataBar.NegativeBarColorSameAsPositive = format.BarNegativeColor == ""
+	if format.BarNegativeColor != "" {
+		dataBar.NegativeFillColor = &xlsxX14Color{RGB: getPaletteColor(format.BarNegativeColor)}
+	}
+	if format.BarAxisPosition != "none" {
+		dataBar.AxisColor = &xlsxX14Color{RGB: "FF000000"}
+		if format.BarAxisColor != "" {
+			dataBar.AxisColor.RGB = getPaletteColor(format.BarAxisColor)
+		}
+	}
+	return &xlsxX14CfRule{Type: c.Type, ID: id, DataBar: dataBar}, err
+}
+
 // drawConfFmtExp provides a function to create conditional formatting rule
 // for expression by given priority, criteria type and format settings.
-func drawConfFmtExp(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawConfFmtExp(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3105,9 +4505,501 @@ func drawConfFmtExp(p int, ct string, format *formatConditional) *xlsxCfRule {
 	}
 }
 
+// checkCondFmtIconSet provides a function to validate the icon set
+// conditional formatting rule settings.
+func checkCondFmtIconSet(format *ConditionalFormatOptions) error {
+	if format.IconStyle == "" {
+		format.IconStyle = "3TrafficLights1"
+	}
+	count, ok := validIconSets[format.IconStyle]
+	if !ok {
+		return newInvalidStyleAttrError("icon set", format.IconStyle)
+	}
+	if len(format.Icons) >= count || (len(format.CustomIcons) > 0 && len(format.CustomIcons) != count) {
+		return ErrParameterInvalid
+	}
+	for _, icon := range format.Icons {
+		if icon.Criteria != "" && icon.Criteria != ">=" && icon.Criteria != ">" {
+			return newInvalidStyleAttrError("icon criteria", icon.Criteria)
+		}
+		if _, ok = cfvoTypes[icon.Type]; !ok && icon.Type != "" {
+			return newInvalidStyleAttrError("icon type", icon.Type)
+		}
+	}
+	for _, icon := range format.CustomIcons {
+		if icon.IconStyle == "NoIcons" {
+			continue
+		}
+		if count, ok = validIconSets[icon.IconStyle]; !ok {
+			return newInvalidStyleAttrError("icon set", icon.IconStyle)
+		}
+		if icon.IconIndex < 0 || icon.IconIndex >= count {
+			return ErrParameterInvalid
+		}
+	}
+	return nil
+}
+
+// isX14CondFmtIconSet provides a function to check if the icon set
+// conditional formatting rule should be stored in the worksheet extension
+// list.
+func isX14CondFmtIconSet(format *ConditionalFormatOptions) bool {
+	return x14IconSets[format.IconStyle] || len(format.CustomIcons) > 0
+}
+
+// condFmtIconSetThresholds provides a function to get the thresholds of the
+// icons in the icon set conditional formatting rule from the last icon to the
+// first icon. The threshold of the last icon is always 0 percent, the default
+// thresholds of the other icons are evenly spaced percents.
+func condFmtIconSetThresholds(format *ConditionalFormatOptions) []*ConditionalFormatIcon {
+	count := validIconSets[format.IconStyle]
+	thresholds := []*ConditionalFormatIcon{{Criteria: ">=", Type: "percent", Value: "0"}}
+	for i := 1; i < count; i++ {
+		threshold := &ConditionalFormatIcon{Criteria: ">=", Type: "percent", Value: strconv.Itoa(int(math.Round(float64(i) * 100 / float64(count))))}
+		if idx := count - 1 - i; idx < len(format.Icons) {
+			icon := format.Icons[idx]
+			if icon.Criteria != "" {
+				threshold.Criteria = icon.Criteria
+			}
+			if icon.Type != "" {
+				threshold.Type = cfvoTypes[icon.Type]
+			}
+			threshold.Value = icon.Value
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds
+}
+
+// drawCondFmtIconSet provides a function to create conditional formatting
+// rule for icon set by given priority, criteria type and format settings.
+func drawCondFmtIconSet(p int, ct, ref string, format *ConditionalFormatOptions) *xlsxCfRule {
+	c := &xlsxCfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		IconSet: &xlsxIconSet{
+			IconSet: format.IconStyle,
+			Reverse: format.ReverseIcons,
+		},
+	}
+	if format.IconsOnly {
+		c.IconSet.ShowValue = boolPtr(false)
+	}
+	for _, threshold := range condFmtIconSetThresholds(format) {
+		cfvo := &xlsxCfvo{Type: threshold.Type, Val: threshold.Value}
+		if threshold.Criteria == ">" {
+			cfvo.Gte = boolPtr(false)
+		}
+		c.IconSet.Cfvo = append(c.IconSet.Cfvo, cfvo)
+	}
+	return c
+}
+
+// drawCondFmtX14IconSet provides a function to create conditional formatting
+// rule for icon set in the worksheet extension list by given priority and
+// format settings.
+func drawCondFmtX14IconSet(p int, format *ConditionalFormatOptions) (*xlsxX14CfRule, error) {
+	id, err := newGUID()
+	if err != nil {
+		return nil, err
+	}
+	c := &xlsxX14CfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		ID:       id,
+		IconSet: &xlsxX14IconSet{
+			IconSet: format.IconStyle,
+			Reverse: format.ReverseIcons,
+			Custom:  len(format.CustomIcons) > 0,
+		},
+	}
+	if format.IconsOnly {
+		c.IconSet.ShowValue = boolPtr(false)
+	}
+	for _, threshold := range condFmtIconSetThresholds(format) {
+		cfvo := &xlsxX14Cfvo{Type: threshold.Type, F: threshold.Value}
+		if threshold.Criteria == ">" {
+			cfvo.Gte = boolPtr(false)
+		}
+		c.IconSet.Cfvo = append(c.IconSet.Cfvo, cfvo)
+	}
+	for i := len(format.CustomIcons) - 1; i >= 0; i-- {
+		c.IconSet.CfIcon = append(c.IconSet.CfIcon, &xlsxX14CfIcon{
+			IconSet: format.CustomIcons[i].IconStyle,
+			IconID:  format.CustomIcons[i].IconIndex,
+		})
+	}
+	return c, err
+}
+
+// getCondFmtX14 provides a function to get the conditional formatting rules
+// in the worksheet extension list.
+func (f *File) getCondFmtX14(ws *xlsxWorksheet) ([]*decodeX14ConditionalFormatting, error) {
+	if ws.ExtLst == nil || ws.ExtLst.Ext == "" {
+		return nil, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIConditionalFormattings {
+			continue
+		}
+		condFmts := new(decodeX14ConditionalFormattings)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(condFmts); err != nil && err != io.EOF {
+			return nil, err
+		}
+		return condFmts.ConditionalFormatting, nil
+	}
+	return nil, nil
+}
+
+// appendCondFmtX14 provides a function to append the conditional formatting
+// rules into the worksheet extension list by given range reference.
+func (f *File) appendCondFmtX14(ws *xlsxWorksheet, area string, rules []*xlsxX14CfRule) error {
+	condFmtBytes, err := xml.Marshal(&xlsxX14ConditionalFormatting{
+		XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value,
+		CfRule:  rules,
+		Sqref:   area,
+	})
+	if err != nil {
+		return err
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if ws.ExtLst != nil {
+		if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	var condFmtsBytes, extLstBytes []byte
+	for idx, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIConditionalFormattings {
+			condFmts := new(decodeX14ConditionalFormattings)
+			if err = f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(condFmts); err != nil && err != io.EOF {
+				return err
+			}
+			condFmtsBytes, _ = xml.Marshal(&xlsxX14ConditionalFormattings{Content: condFmts.Content + string(condFmtBytes)})
+			decodeExtLst.Ext[idx].Content = string(condFmtsBytes)
+		}
+	}
+	if condFmtsBytes == nil {
+		condFmtsBytes, _ = xml.Marshal(&xlsxX14ConditionalFormattings{Content: string(condFmtBytes)})
+		// The conditional formattings extension should be the first in the
+		// worksheet extension list.
+		decodeExtLst.Ext = append([]*xlsxWorksheetExt{{
+			URI:     ExtURIConditionalFormattings,
+			Content: string(condFmtsBytes),
+		}}, decodeExtLst.Ext...)
+	}
+	if extLstBytes, err = xml.Marshal(decodeExtLst); err != nil {
+		return err
+	}
+	ws.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	return err
+}
+
+// criteriaTypeNames defined the textual description of the criteria types
+// for extracting the conditional formatting rules.
+var criteriaTypeNames = map[string]string{
+	"between":            "between",
+	"notBetween":         "not between",
+	"equal":              "equal to",
+	"notEqual":           "not equal to",
+	"greaterThan":        "greater than",
+	"lessThan":           "less than",
+	"greaterThanOrEqual": "greater than or equal to",
+	"lessThanOrEqual":    "less than or equal to",
+	"containsText":       "containing",
+	"notContains":        "not containing",
+	"beginsWith":         "begins with",
+	"endsWith":           "ends with",
+	"yesterday":          "yesterday",
+	"today":              "today",
+	"tomorrow":           "tomorrow",
+	"last7Days":          "last 7 days",
+	"lastWeek":           "last week",
+	"thisWeek":           "this week",
+	"nextWeek":           "next week",
+	"lastMonth":          "last month",
+	"thisMonth":          "this month",
+	"nextMonth":          "next month",
+}
+
+// extractCondFmtCellIs provides a function to extract conditional format
+// settings for cell value (include between, not between, equal, not equal,
+// greater than and less than) by given conditional formatting rule.
+func extractCondFmtCellIs(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "cell", Criteria: criteriaTypeNames[c.Operator]}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	if len(c.Formula) == 2 {
+		format.Minimum, format.Maximum = c.Formula[0], c.Formula[1]
+		return &format
+	}
+	if len(c.Formula) > 0 {
+		format.Value = c.Formula[0]
+	}
+	return &format
+}
+
+// extractCondFmtTop10 provides a function to extract conditional format
+// settings for top N (default is top 10) by given conditional formatting
+// rule.
+func extractCondFmtTop10(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{
+		Type:     "top",
+		Criteria: "=",
+		Percent:  c.Percent,
+		Value:    strconv.Itoa(c.Rank),
+	}
+	if c.Bottom {
+		format.Type = "bottom"
+	}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtAboveAverage provides a function to extract conditional
+// format settings for above average and below average by given conditional
+// formatting rule.
+func extractCondFmtAboveAverage(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "average", Criteria: "=", AboveAverage: true}
+	if c.AboveAverage != nil {
+		format.AboveAverage = *c.AboveAverage
+	}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtDuplicateUniqueValues provides a function to extract
+// conditional format settings for duplicate and unique values by given
+// conditional formatting rule.
+func extractCondFmtDuplicateUniqueValues(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{
+		Type:     map[string]string{"duplicateValues": "duplicate", "uniqueValues": "unique"}[c.Type],
+		Criteria: "=",
+	}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtText provides a function to extract conditional format
+// settings for text (include containing, not containing, begins with and
+// ends with) by given conditional formatting rule.
+func extractCondFmtText(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "text", Criteria: criteriaTypeNames[c.Operator], Value: c.Text}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtTimePeriod provides a function to extract conditional format
+// settings for dates occurring by given conditional formatting rule.
+func extractCondFmtTimePeriod(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "time_period", Criteria: criteriaTypeNames[c.TimePeriod]}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtBlanksErrors provides a function to extract conditional
+// format settings for blanks, no blanks, errors and no errors by given
+// conditional formatting rule.
+func extractCondFmtBlanksErrors(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{
+		Type: map[string]string{
+			"containsBlanks":    "blanks",
+			"notContainsBlanks": "no_blanks",
+			"containsErrors":    "errors",
+			"notContainsErrors": "no_errors",
+		}[c.Type],
+	}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	return &format
+}
+
+// extractCondFmtColorScale provides a function to extract conditional format
+// settings for color scale (include 2 color scale and 3 color scale) by given
+// conditional formatting rule.
+func extractCondFmtColorScale(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "2_color_scale", Criteria: "="}
+	if c.ColorScale == nil {
+		return &format
+	}
+	cfvo, color := c.ColorScale.Cfvo, c.ColorScale.Color
+	if len(cfvo) == 3 && len(color) == 3 {
+		format.Type = "3_color_scale"
+		format.MidType, format.MidValue = cfvo[1].Type, cfvo[1].Val
+		format.MidColor = "#" + strings.TrimPrefix(color[1].RGB, "FF")
+	}
+	if len(cfvo) > 1 && len(color) > 1 {
+		format.MinType, format.MinValue = cfvo[0].Type, cfvo[0].Val
+		format.MinColor = "#" + strings.TrimPrefix(color[0].RGB, "FF")
+		format.MaxType, format.MaxValue = cfvo[len(cfvo)-1].Type, cfvo[len(cfvo)-1].Val
+		format.MaxColor = "#" + strings.TrimPrefix(color[len(color)-1].RGB, "FF")
+	}
+	return &format
+}
+
+// extractCondFmtDataBar provides a function to extract conditional format
+// settings for data bar by given conditional formatting rule.
+func extractCondFmtDataBar(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "data_bar", Criteria: "="}
+	if c.DataBar != nil {
+		if len(c.DataBar.Cfvo) == 2 {
+			format.MinType, format.MaxType = c.DataBar.Cfvo[0].Type, c.DataBar.Cfvo[1].Type
+		}
+		if len(c.DataBar.Color) > 0 {
+			format.BarColor = "#" + strings.TrimPrefix(c.DataBar.Color[0].RGB, "FF")
+		}
+	}
+	return &format
+}
+
+// getCondFmtX14RuleID provides a function to get the ID of the rule in the
+// worksheet extension list which is referred by the given conditional
+// formatting rule.
+func (f *File) getCondFmtX14RuleID(c *xlsxCfRule) string {
+	if c.ExtLst == nil {
+		return ""
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + c.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return ""
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIConditionalFormatRule {
+			decodeID := new(decodeX14CfRuleID)
+			_ = f.xmlNewDecoder(strings.NewReader(ext.Content)).Decode(decodeID)
+			return decodeID.Value
+		}
+	}
+	return ""
+}
+
+// extractCondFmtX14DataBar provides a function to extract the data bar
+// settings in the worksheet extension list into the given format settings.
+func extractCondFmtX14DataBar(format *ConditionalFormatOptions, c *decodeX14DataBar) {
+	format.BarSolid = c.Gradient != nil && !*c.Gradient
+	format.BarDirection, format.BarAxisPosition = c.Direction, c.AxisPosition
+	if c.Border && c.BorderColor != nil {
+		format.BarBorderColor = "#" + strings.TrimPrefix(c.BorderColor.RGB, "FF")
+		if c.NegativeBarBorderColorSameAsPositive != nil && !*c.NegativeBarBorderColorSameAsPositive && c.NegativeBorderColor != nil {
+			format.BarNegativeBorderColor = "#" + strings.TrimPrefix(c.NegativeBorderColor.RGB, "FF")
+		}
+	}
+	if !c.NegativeBarColorSameAsPositive && c.NegativeFillColor != nil {
+		format.BarNegativeColor = "#" + strings.TrimPrefix(c.NegativeFillColor.RGB, "FF")
+	}
+	if c.AxisColor != nil && c.AxisPosition != "none" {
+		format.BarAxisColor = "#" + strings.TrimPrefix(c.AxisColor.RGB, "FF")
+	}
+}
+
+// extractCondFmtExp provides a function to extract conditional format
+// settings for expression by given conditional formatting rule.
+func extractCondFmtExp(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "formula"}
+	if c.DxfID != nil {
+		format.Format = *c.DxfID
+	}
+	if len(c.Formula) > 0 {
+		format.Criteria = c.Formula[0]
+	}
+	return &format
+}
+
+// extractCondFmtIconSetThresholds provides a function to convert the
+// thresholds of the icons from the last icon to the first icon into the
+// threshold settings from the first icon.
+func extractCondFmtIconSetThresholds(thresholds []*ConditionalFormatIcon) []*ConditionalFormatIcon {
+	var icons []*ConditionalFormatIcon
+	for i := len(thresholds) - 1; i > 0; i-- {
+		if thresholds[i].Type == "num" {
+			thresholds[i].Type = "number"
+		}
+		icons = append(icons, thresholds[i])
+	}
+	return icons
+}
+
+// extractCondFmtIconSet provides a function to extract conditional format
+// settings for icon set by given conditional formatting rule.
+func extractCondFmtIconSet(c *xlsxCfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "icon_set", IconStyle: "3TrafficLights1"}
+	if c.IconSet == nil {
+		return &format
+	}
+	if c.IconSet.IconSet != "" {
+		format.IconStyle = c.IconSet.IconSet
+	}
+	format.ReverseIcons = c.IconSet.Reverse
+	format.IconsOnly = c.IconSet.ShowValue != nil && !*c.IconSet.ShowValue
+	var thresholds []*ConditionalFormatIcon
+	for _, cfvo := range c.IconSet.Cfvo {
+		threshold := &ConditionalFormatIcon{Criteria: ">=", Type: cfvo.Type, Value: cfvo.Val}
+		if cfvo.Gte != nil && !*cfvo.Gte {
+			threshold.Criteria = ">"
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	format.Icons = extractCondFmtIconSetThresholds(thresholds)
+	return &format
+}
+
+// extractCondFmtX14IconSet provides a function to extract conditional format
+// settings for icon set by given conditional formatting rule in the
+// worksheet extension list.
+func extractCondFmtX14IconSet(c *decodeX14CfRule) *ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "icon_set", IconStyle: "3TrafficLights1"}
+	if c.IconSet.IconSet != "" {
+		format.IconStyle = c.IconSet.IconSet
+	}
+	format.ReverseIcons = c.IconSet.Reverse
+	format.IconsOnly = c.IconSet.ShowValue != nil && !*c.IconSet.ShowValue
+	var thresholds []*ConditionalFormatIcon
+	for _, cfvo := range c.IconSet.Cfvo {
+		threshold := &ConditionalFormatIcon{Criteria: ">=", Type: cfvo.Type, Value: cfvo.F}
+		if cfvo.Gte != nil && !*cfvo.Gte {
+			threshold.Criteria = ">"
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	format.Icons = extractCondFmtIconSetThresholds(thresholds)
+	for i := len(c.IconSet.CfIcon) - 1; i >= 0; i-- {
+		format.CustomIcons = append(format.CustomIcons, &ConditionalFormatCustomIcon{
+			IconStyle: c.IconSet.CfIcon[i].IconSet,
+			IconIndex: c.IconSet.CfIcon[i].IconID,
+		})
+	}
+	return &format
+}
+
 // getPaletteColor provides a function to convert the RBG color by given
 // string.
 func getPaletteColor(color string) string {
+	if c, err := ParseHexColor(color); err == nil {
+		return FormatHexColor(c)
+	}
 	return "FF" + strings.Replace(strings.ToUpper(color), "#", "", -1)
 }
 
@@ -3125,23 +5017,69 @@ func (f *File) themeReader() *xlsxTheme {
 	return &theme
 }
 
-// ThemeColor applied the color with tint value.
+// ThemeColor applied the color with tint value. The base color could be a hex
+// color code in RRGGBB or AARRGGBB format with an optional leading '#', and
+// the returned color is in the AARRGGBB format. For example, lighter the
+// accent color by 40%:
+//
+//    color := excelize.ThemeColor("4472C4", 0.4)
+//
 func ThemeColor(baseColor string, tint float64) string {
+	c, err := ParseHexColor(baseColor)
 	if tint == 0 {
-		return "FF" + baseColor
+		if err != nil {
+			return "FF" + baseColor
+		}
+		return FormatHexColor(c)
 	}
-	r, _ := strconv.ParseInt(baseColor[0:2], 16, 64)
-	g, _ := strconv.ParseInt(baseColor[2:4], 16, 64)
-	b, _ := strconv.ParseInt(baseColor[4:6], 16, 64)
-	var h, s, l float64
-	if r >= 0 && r <= math.MaxUint8 && g >= 0 && g <= math.MaxUint8 && b >= 0 && b <= math.MaxUint8 {
-		h, s, l = RGBToHSL(uint8(r), uint8(g), uint8(b))
+	if err != nil {
+		c = color.NRGBA{A: math.MaxUint8}
 	}
+	return FormatHexColor(TintColor(c, tint))
+}
+
+// TintColor provides a function to apply the tint value to the color by the
+// same way as the spreadsheet application does, the tint value must be
+// between -1.0 and 1.0, a negative value darken the color and a positive
+// value lighten the color, the alpha channel of the color will be kept.
+func TintColor(c color.Color, tint float64) color.NRGBA {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	h, s, l := RGBToHSL(nc.R, nc.G, nc.B)
 	if tint < 0 {
-		l *= (1 + tint)
+		l *= 1 + tint
 	} else {
-		l = l*(1-tint) + (1 - (1 - tint))
+		l = l*(1-tint) + tint
 	}
-	br, bg, bb := HSLToRGB(h, s, l)
-	return fmt.Sprintf("FF%02X%02X%02X", br, bg, bb)
+	nc.R, nc.G, nc.B = HSLToRGB(h, s, l)
+	return nc
+}
+
+// ParseHexColor provides a function to parse the hex color code in RRGGBB or
+// AARRGGBB format with an optional leading '#' to the color, the alpha
+// channel will be opaque if the color code doesn't include it. For example:
+//
+//    c, err := excelize.ParseHexColor("#804472C4")
+//
+func ParseHexColor(hex string) (color.NRGBA, error) {
+	var c color.NRGBA
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 6 {
+		hex = "FF" + hex
+	}
+	if len(hex) != 8 {
+		return c, ErrHexColor
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return c, ErrHexColor
+	}
+	c.A, c.R, c.G, c.B = uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v)
+	return c, nil
+}
+
+// FormatHexColor provides a function to format the color to the hex color
+// code in AARRGGBB format which used in the spreadsheet.
+func FormatHexColor(c color.Color) string {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("%02X%02X%02X%02X", nc.A, nc.R, nc.G, nc.B)
 }
@@ -2,6 +2,7 @@ package excelize
 
 import (
 	"fmt"
+	"image/color"
 	"math"
 	"path/filepath"
 	"strings"
@@ -189,6 +190,318 @@ func TestUnsetConditionalFormat(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnsetConditionalFormat.xlsx")))
 }
 
+func TestSetConditionalFormatIconSet(t *testing.T) {
+	f := NewFile()
+	for r := 1; r <= 10; r++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", r), &[]int{r, r * 10, r * 100, r * 1000}))
+	}
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"icon_set"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"icon_set","icon_style":"4Arrows","reverse_icons":true,"icons_only":true,"icons":[{"criteria":">=","type":"number","value":"90"},{"criteria":">","type":"percentile","value":"50"},{"type":"formula","value":"$E$1"}]}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"icon_set","icon_style":"3Stars"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "D1:D10", `[{"type":"icon_set","icon_style":"3Flags","custom_icons":[{"icon_style":"3Flags","icon_index":2},{"icon_style":"3Stars","icon_index":1},{"icon_style":"NoIcons","icon_index":0}]}]`))
+
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 2)
+	assert.Equal(t, &xlsxIconSet{
+		IconSet: "3TrafficLights1",
+		Cfvo:    []*xlsxCfvo{{Type: "percent", Val: "0"}, {Type: "percent", Val: "33"}, {Type: "percent", Val: "67"}},
+	}, ws.ConditionalFormatting[0].CfRule[0].IconSet)
+	assert.Equal(t, &xlsxIconSet{
+		IconSet:   "4Arrows",
+		ShowValue: boolPtr(false),
+		Reverse:   true,
+		Cfvo: []*xlsxCfvo{
+			{Type: "percent", Val: "0"},
+			{Type: "formula", Val: "$E$1"},
+			{Type: "percentile", Val: "50", Gte: boolPtr(false)},
+			{Type: "num", Val: "90"},
+		},
+	}, ws.ConditionalFormatting[1].CfRule[0].IconSet)
+	assert.Contains(t, ws.ExtLst.Ext, `<ext uri="{78C0D931-6437-407D-A8EE-F0AAD7539E65}"><x14:conditionalFormattings><x14:conditionalFormatting xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><x14:cfRule type="iconSet" priority="1" id="{`)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:iconSet iconSet="3Flags" custom="true"><x14:cfvo type="percent"><xm:f>0</xm:f></x14:cfvo><x14:cfvo type="percent"><xm:f>33</xm:f></x14:cfvo><x14:cfvo type="percent"><xm:f>67</xm:f></x14:cfvo><x14:cfIcon iconSet="NoIcons" iconId="0"></x14:cfIcon><x14:cfIcon iconSet="3Stars" iconId="1"></x14:cfIcon><x14:cfIcon iconSet="3Flags" iconId="2"></x14:cfIcon></x14:iconSet></x14:cfRule><xm:sqref>D1:D10</xm:sqref>`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatIconSet.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestSetConditionalFormatIconSet.xlsx"))
+	assert.NoError(t, err)
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"A1:A10": `[{"type":"icon_set","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"","icon_style":"3TrafficLights1","icons":[{"criteria":"\u003e=","type":"percent","value":"67"},{"criteria":"\u003e=","type":"percent","value":"33"}]}]`,
+		"B1:B10": `[{"type":"icon_set","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"","icon_style":"4Arrows","reverse_icons":true,"icons_only":true,"icons":[{"criteria":"\u003e=","type":"number","value":"90"},{"criteria":"\u003e","type":"percentile","value":"50"},{"criteria":"\u003e=","type":"formula","value":"$E$1"}]}]`,
+		"C1:C10": `[{"type":"icon_set","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"","icon_style":"3Stars","icons":[{"criteria":"\u003e=","type":"percent","value":"67"},{"criteria":"\u003e=","type":"percent","value":"33"}]}]`,
+		"D1:D10": `[{"type":"icon_set","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"","icon_style":"3Flags","icons":[{"criteria":"\u003e=","type":"percent","value":"67"},{"criteria":"\u003e=","type":"percent","value":"33"}],"custom_icons":[{"icon_style":"3Flags","icon_index":2},{"icon_style":"3Stars","icon_index":1},{"icon_style":"NoIcons","icon_index":0}]}]`,
+	}, formats)
+	// Test the extracted format settings could be applied to other ranges.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "E1:E10", formats["D1:D10"]))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "F1:F10", formats["B1:B10"]))
+	clone, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, formats["D1:D10"], clone["E1:E10"])
+	assert.Equal(t, formats["B1:B10"], clone["F1:F10"])
+
+	// Test set icon set conditional format with invalid settings.
+	for format, expected := range map[string]string{
+		`[{"type":"icon_set","icon_style":"6Arrows"}]`:                                                               `invalid icon set "6Arrows"`,
+		`[{"type":"icon_set","icon_style":"3Arrows","icons":[{},{},{}]}]`:                                            ErrParameterInvalid.Error(),
+		`[{"type":"icon_set","icon_style":"3Arrows","icons":[{"criteria":"<"}]}]`:                                    `invalid icon criteria "<"`,
+		`[{"type":"icon_set","icon_style":"3Arrows","icons":[{"type":"min"}]}]`:                                      `invalid icon type "min"`,
+		`[{"type":"icon_set","icon_style":"3Arrows","custom_icons":[{"icon_style":"3Flags"}]}]`:                      ErrParameterInvalid.Error(),
+		`[{"type":"icon_set","icon_style":"3Arrows","custom_icons":[{"icon_style":"3Flag"},{},{}]}]`:                 `invalid icon set "3Flag"`,
+		`[{"type":"icon_set","icon_style":"3Arrows","custom_icons":[{"icon_style":"3Flags","icon_index":3},{},{}]}]`: ErrParameterInvalid.Error(),
+	} {
+		assert.EqualError(t, f.SetConditionalFormat("Sheet1", "A1:A10", format), expected)
+	}
+	// Test get and set conditional formats with invalid worksheet extension list.
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst.Ext = "<ext><x14:conditionalFormattings></ext>"
+	_, err = f.GetConditionalFormats("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: element <conditionalFormattings> closed by </ext>")
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"icon_set","icon_style":"3Stars"}]`), "XML syntax error on line 1: element <conditionalFormattings> closed by </ext>")
+	ws.ExtLst.Ext = fmt.Sprintf(`<ext uri="%s"><x14:conditionalFormattings><x14:conditionalFormatting></x14:conditionalFormattings></ext>`, ExtURIConditionalFormattings)
+	_, err = f.GetConditionalFormats("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: element <conditionalFormatting> closed by </conditionalFormattings>")
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"icon_set","icon_style":"3Stars"}]`), "XML syntax error on line 1: element <conditionalFormatting> closed by </conditionalFormattings>")
+	// Test get conditional formats on not exists worksheet.
+	_, err = f.GetConditionalFormats("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestGetConditionalFormats(t *testing.T) {
+	for _, format := range []string{
+		`[{"type":"cell","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"greater than","value":"6"}]`,
+		`[{"type":"cell","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"between","minimum":"6","maximum":"8"}]`,
+		`[{"type":"top","above_average":false,"percent":true,"format":1,"priority":1,"criteria":"=","value":"6"}]`,
+		`[{"type":"bottom","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"=","value":"3"}]`,
+		`[{"type":"average","above_average":true,"percent":false,"format":1,"priority":1,"criteria":"="}]`,
+		`[{"type":"text","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"not containing","value":"foo"}]`,
+		`[{"type":"text","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"ends with","value":"foo"}]`,
+		`[{"type":"time_period","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"next month"}]`,
+		`[{"type":"no_errors","above_average":false,"percent":false,"format":1,"priority":1,"criteria":""}]`,
+		`[{"type":"duplicate","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"="}]`,
+		`[{"type":"unique","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"="}]`,
+		`[{"type":"3_color_scale","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"=","min_type":"num","mid_type":"num","max_type":"num","min_value":"-10","mid_value":"50","max_value":"10","min_color":"#FF0000","mid_color":"#00FF00","max_color":"#0000FF"}]`,
+		`[{"type":"2_color_scale","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"=","min_type":"num","max_type":"num","min_value":"-10","max_value":"10","min_color":"#FF0000","max_color":"#0000FF"}]`,
+		`[{"type":"data_bar","above_average":false,"percent":false,"format":0,"priority":1,"criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6"}]`,
+		`[{"type":"formula","above_average":false,"percent":false,"format":1,"priority":1,"criteria":"$A$1\u003c$B$1"}]`,
+	} {
+		f := NewFile()
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A2", format))
+		formats, err := f.GetConditionalFormats("Sheet1")
+		assert.NoError(t, err)
+		assert.Equal(t, format, formats["A1:A2"])
+	}
+}
+
+func TestSetConditionalFormatDataBar(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"num","max_value":"100","bar_color":"#638EC6","bar_solid":true,"bar_border_color":"#0070C0","bar_negative_color":"#FF0000","bar_negative_border_color":"#C00000","bar_direction":"rightToLeft","bar_axis_position":"middle","bar_axis_color":"#7F7F7F"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_axis_position":"none"}]`))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, ws.(*xlsxWorksheet).ConditionalFormatting[0].CfRule[0].ExtLst.Ext, ExtURIConditionalFormatRule)
+	assert.Contains(t, ws.(*xlsxWorksheet).ExtLst.Ext, `<x14:dataBar minLength="0" maxLength="100" gradient="false" border="true" direction="rightToLeft" negativeBarBorderColorSameAsPositive="false" axisPosition="middle"><x14:cfvo type="autoMin"></x14:cfvo><x14:cfvo type="num"><xm:f>100</xm:f></x14:cfvo><x14:borderColor rgb="FF0070C0"></x14:borderColor><x14:negativeFillColor rgb="FFFF0000"></x14:negativeFillColor><x14:negativeBorderColor rgb="FFC00000"></x14:negativeBorderColor><x14:axisColor rgb="FF7F7F7F"></x14:axisColor></x14:dataBar>`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatDataBar.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestSetConditionalFormatDataBar.xlsx"))
+	assert.NoError(t, err)
+	formats, err := f.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "data_bar", Criteria: "=", Priority: 1, MinType: "min", MaxType: "num", BarColor: "#638EC6",
+		BarSolid: true, BarBorderColor: "#0070C0", BarNegativeColor: "#FF0000", BarNegativeBorderColor: "#C00000",
+		BarDirection: "rightToLeft", BarAxisPosition: "middle", BarAxisColor: "#7F7F7F",
+	}}, formats["A1:A10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "data_bar", Criteria: "=", Priority: 1, MinType: "min", MaxType: "max", BarColor: "#638EC6", BarAxisPosition: "none",
+	}}, formats["B1:B10"])
+
+	// Test set data bar with invalid direction and axis position.
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"data_bar","bar_direction":"up"}]`), `invalid data bar direction "up"`)
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"data_bar","bar_axis_position":"top"}]`), `invalid data bar axis position "top"`)
+}
+
+func TestSetConditionalFormatPriority(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormatOptions("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Value: "90", Format: format, StopIfTrue: true},
+		{Type: "data_bar", MinType: "min", MaxType: "max", BarColor: "#638EC6", Priority: 3},
+	}))
+	assert.NoError(t, f.SetConditionalFormatOptions("Sheet1", "B1:B10", []ConditionalFormatOptions{
+		{Type: "icon_set", IconStyle: "3Stars", Priority: 2},
+	}))
+	getRules := func() (priorities []int, stopIfTrue []bool) {
+		formats, err := f.GetConditionalFormatOptions("Sheet1")
+		assert.NoError(t, err)
+		for _, area := range []string{"A1:A10", "B1:B10"} {
+			for _, rule := range formats[area] {
+				priorities, stopIfTrue = append(priorities, rule.Priority), append(stopIfTrue, rule.StopIfTrue)
+			}
+		}
+		return
+	}
+	priorities, stopIfTrue := getRules()
+	assert.Equal(t, []int{1, 3, 2}, priorities)
+	assert.Equal(t, []bool{true, false, false}, stopIfTrue)
+
+	// Test move the rule to the top and the bottom of the rules.
+	assert.NoError(t, f.SetConditionalFormatPriority("Sheet1", "A1:A10", 1, 1))
+	priorities, _ = getRules()
+	assert.Equal(t, []int{2, 1, 3}, priorities)
+	assert.NoError(t, f.SetConditionalFormatPriority("Sheet1", "B1:B10", 0, 1))
+	priorities, _ = getRules()
+	assert.Equal(t, []int{3, 2, 1}, priorities)
+	assert.NoError(t, f.SetConditionalFormatPriority("Sheet1", "B1:B10", 0, 10))
+	priorities, _ = getRules()
+	assert.Equal(t, []int{2, 1, 3}, priorities)
+
+	// Test set stop if true of the rules.
+	assert.NoError(t, f.SetConditionalFormatStopIfTrue("Sheet1", "A1:A10", 0, false))
+	assert.NoError(t, f.SetConditionalFormatStopIfTrue("Sheet1", "B1:B10", 0, true))
+	_, stopIfTrue = getRules()
+	assert.Equal(t, []bool{false, false, true}, stopIfTrue)
+	assert.NoError(t, f.SetConditionalFormatStopIfTrue("Sheet1", "B1:B10", 0, false))
+	_, stopIfTrue = getRules()
+	assert.Equal(t, []bool{false, false, false}, stopIfTrue)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatPriority.xlsx")))
+
+	// Test set priority and stop if true with invalid parameters.
+	assert.EqualError(t, f.SetConditionalFormatPriority("Sheet1", "A1:A10", 0, 0), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.SetConditionalFormatPriority("Sheet1", "A1:A10", 2, 1), ErrConditionalFormatNotExist.Error())
+	assert.EqualError(t, f.SetConditionalFormatStopIfTrue("Sheet1", "C1:C10", 0, true), ErrConditionalFormatNotExist.Error())
+	assert.EqualError(t, f.SetConditionalFormatPriority("SheetN", "A1:A10", 0, 1), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetConditionalFormatStopIfTrue("SheetN", "A1:A10", 0, true), "sheet SheetN is not exist")
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ExtLst.Ext = fmt.Sprintf(`<ext uri="%s"><x14:conditionalFormattings><x14:conditionalFormatting><x14:cfRule type="iconSet" priority="1">`, ExtURIConditionalFormattings)
+	assert.Error(t, f.SetConditionalFormatPriority("Sheet1", "A1:A10", 0, 1))
+}
+
+func TestGetConditionalFormatOptions(t *testing.T) {
+	f := NewFile()
+	format1, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511","bold":true,"underline":"double"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	format2, err := f.NewConditionalStyle(`{"alignment":{"wrap_text":true},"fill":{"type":"gradient","color":["#FFFFFF","#E0EBF5"],"shading":1},"border":[{"type":"left","color":"#000000","style":1},{"type":"diagonalUp","color":"#FF0000","style":2}]}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormatOptions("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Value: "6", Format: format1},
+		{Type: "duplicate", Format: format2},
+		{Type: "data_bar", MinType: "min", MaxType: "max", BarColor: "#638EC6"},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetConditionalFormatOptions.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestGetConditionalFormatOptions.xlsx"))
+	assert.NoError(t, err)
+	formats, err := f.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	rules := formats["A1:A10"]
+	assert.Len(t, rules, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{rules[0].Priority, rules[1].Priority, rules[2].Priority})
+	assert.Equal(t, []string{"cell", "duplicate", "data_bar"}, []string{rules[0].Type, rules[1].Type, rules[2].Type})
+	assert.Equal(t, format1, rules[0].Format)
+	assert.Equal(t, &Style{
+		Font: &Font{Bold: true, Underline: "double", Family: "Calibri", Size: 11, Color: "#9A0511"},
+		Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"#FEC7CE"}},
+	}, rules[0].Style)
+	assert.Equal(t, &Style{
+		Alignment: &Alignment{WrapText: true},
+		Fill:      Fill{Type: "gradient", Shading: 1, Color: []string{"#FFFFFF", "#E0EBF5"}},
+		Border:    []Border{{Type: "left", Color: "#000000", Style: 1}, {Type: "diagonalUp", Color: "#FF0000", Style: 2}},
+	}, rules[1].Style)
+	assert.Nil(t, rules[2].Style)
+
+	// Test clone the conditional formats into another workbook.
+	f2 := NewFile()
+	assert.NoError(t, f2.SetConditionalFormatOptions("Sheet1", "B1:B10", rules))
+	assert.Len(t, f2.Styles.Dxfs.Dxfs, 2)
+	assert.Equal(t, f.Styles.Dxfs.Dxfs[format1].Dxf, f2.Styles.Dxfs.Dxfs[0].Dxf)
+	assert.Equal(t, f.Styles.Dxfs.Dxfs[format2].Dxf, f2.Styles.Dxfs.Dxfs[1].Dxf)
+	// Test the existing conditional style will be reused.
+	assert.NoError(t, f2.SetConditionalFormatOptions("Sheet1", "C1:C10", rules))
+	assert.Len(t, f2.Styles.Dxfs.Dxfs, 2)
+	cloned, err := f2.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, rules[1].Style, cloned["C1:C10"][1].Style)
+
+	// Test get conditional formats with theme colors.
+	f.Styles.Dxfs.Dxfs[format1].Dxf = `<font><color theme="1"/></font><fill><patternFill patternType="lightGray"><fgColor theme="4" tint="0.4"/></patternFill></fill><border diagonalDown="1"><top style="unknown"/><diagonal style="thin"><color theme="0"/></diagonal></border>`
+	f.Styles.Dxfs.Dxfs[format2].Dxf = `<fill><gradientFill type="path" top="0.5"><stop position="0"><color indexed="64"/></stop></gradientFill></fill><border diagonalDown="1"><diagonal style="thin"><color theme="100"/></diagonal></border>`
+	formats, err = f.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{
+		Font:   &Font{Color: "#000000"},
+		Fill:   Fill{Type: "pattern", Pattern: 4, Color: []string{"#" + ThemeColor("5B9BD5", 0.4)[2:]}},
+		Border: []Border{{Type: "diagonalDown", Color: "#FFFFFF", Style: 1}},
+	}, formats["A1:A10"][0].Style)
+	assert.Equal(t, &Style{
+		Fill:   Fill{Type: "gradient", Shading: 5, Color: []string{""}},
+		Border: []Border{{Type: "diagonalDown", Style: 1}},
+	}, formats["A1:A10"][1].Style)
+	// Test get conditional formats with invalid differential formatting.
+	f.Styles.Dxfs.Dxfs[format1].Dxf = "<font>"
+	f.Styles.Dxfs.Dxfs[format2] = nil
+	formats, err = f.GetConditionalFormatOptions("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, formats["A1:A10"][0].Style)
+	assert.Nil(t, formats["A1:A10"][1].Style)
+	assert.Nil(t, f.getConditionalStyle(-1, nil))
+	assert.Empty(t, getColorRGB(&xlsxColor{RGB: "FF"}, nil))
+	// Test get conditional formats on not exists worksheet.
+	_, err = f.GetConditionalFormatOptions("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestSetConditionalFormatOptions(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormatOptions("Sheet1", "$B$2:$B$10 D2:D10", []ConditionalFormatOptions{
+		{Type: "top", Criteria: "=", Value: "10", Percent: true, Format: format},
+		{Type: "bottom", Criteria: "=", Value: "5", Format: format},
+		{Type: "duplicate", Format: format},
+		{Type: "unique", Format: format},
+		{Type: "text", Criteria: "containing", Value: `say "hi"`, Format: format},
+		{Type: "text", Criteria: "not containing", Value: "foo", Format: format},
+		{Type: "text", Criteria: "begins with", Value: "foo", Format: format},
+		{Type: "text", Criteria: "ends with", Value: "foo", Format: format},
+		{Type: "time_period", Criteria: "yesterday", Format: format},
+		{Type: "time_period", Criteria: "last week", Format: format},
+		{Type: "blanks", Format: format},
+		{Type: "no_blanks", Format: format},
+		{Type: "errors", Format: format},
+		{Type: "no_errors", Format: format},
+		// Test the rules with invalid criteria will be ignored.
+		{Type: "text", Criteria: "yesterday", Value: "foo", Format: format},
+		{Type: "time_period", Criteria: "begins with", Format: format},
+		{Type: "text", Value: "foo", Format: format},
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.Equal(t, []*xlsxCfRule{
+		{Priority: 1, Type: "top10", Rank: 10, Percent: true, DxfID: &format},
+		{Priority: 2, Type: "top10", Rank: 5, Bottom: true, DxfID: &format},
+		{Priority: 3, Type: "duplicateValues", DxfID: &format},
+		{Priority: 4, Type: "uniqueValues", DxfID: &format},
+		{Priority: 5, Type: "containsText", Operator: "containsText", Text: `say "hi"`, DxfID: &format, Formula: []string{`NOT(ISERROR(SEARCH("say ""hi""",B2)))`}},
+		{Priority: 6, Type: "notContainsText", Operator: "notContains", Text: "foo", DxfID: &format, Formula: []string{`ISERROR(SEARCH("foo",B2))`}},
+		{Priority: 7, Type: "beginsWith", Operator: "beginsWith", Text: "foo", DxfID: &format, Formula: []string{`LEFT(B2,LEN("foo"))="foo"`}},
+		{Priority: 8, Type: "endsWith", Operator: "endsWith", Text: "foo", DxfID: &format, Formula: []string{`RIGHT(B2,LEN("foo"))="foo"`}},
+		{Priority: 9, Type: "timePeriod", TimePeriod: "yesterday", DxfID: &format, Formula: []string{"FLOOR(B2,1)=TODAY()-1"}},
+		{Priority: 10, Type: "timePeriod", TimePeriod: "lastWeek", DxfID: &format, Formula: []string{"AND(TODAY()-ROUNDDOWN(B2,0)>=(WEEKDAY(TODAY())),TODAY()-ROUNDDOWN(B2,0)<(WEEKDAY(TODAY())+7))"}},
+		{Priority: 11, Type: "containsBlanks", DxfID: &format, Formula: []string{"LEN(TRIM(B2))=0"}},
+		{Priority: 12, Type: "notContainsBlanks", DxfID: &format, Formula: []string{"LEN(TRIM(B2))>0"}},
+		{Priority: 13, Type: "containsErrors", DxfID: &format, Formula: []string{"ISERROR(B2)"}},
+		{Priority: 14, Type: "notContainsErrors", DxfID: &format, Formula: []string{"NOT(ISERROR(B2))"}},
+	}, ws.ConditionalFormatting[0].CfRule)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatOptions.xlsx")))
+	// Test set conditional format options on not exists worksheet.
+	assert.EqualError(t, f.SetConditionalFormatOptions("SheetN", "A1:A10", nil), "sheet SheetN is not exist")
+}
+
 func TestNewStyle(t *testing.T) {
 	f := NewFile()
 	styleID, err := f.NewStyle(`{"font":{"bold":true,"italic":true,"family":"Times New Roman","size":36,"color":"#777777"}}`)
@@ -242,6 +555,95 @@ func TestNewStyle(t *testing.T) {
 	assert.Equal(t, 32, *nf.NumFmtID)
 }
 
+func TestQuotePrefix(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "=A2"))
+	quotePrefix, err := f.GetCellQuotePrefix("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.False(t, quotePrefix)
+	style, err := f.NewStyle(&Style{QuotePrefix: true})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, style)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	quotePrefix, err = f.GetCellQuotePrefix("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, quotePrefix)
+	// Test create the same style with quote prefix flag
+	styleID, err := NewStyleBuilder().QuotePrefix(true).Build(f)
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestQuotePrefix.xlsx")))
+	// Test get quote prefix flag on not exists worksheet
+	_, err = f.GetCellQuotePrefix("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get quote prefix flag with invalid style ID
+	f.Styles.CellXfs.Xf = nil
+	quotePrefix, err = f.GetCellQuotePrefix("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.False(t, quotePrefix)
+}
+
+func TestStyleBuilder(t *testing.T) {
+	f := NewFile()
+	styleID, err := NewStyleBuilder().
+		Font(Font{Bold: true, Color: "#FFFFFF"}).
+		Fill(Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1}).
+		Border(Border{Type: "left", Color: "000000", Style: 1}, Border{Type: "right", Color: "000000", Style: 1}).
+		Alignment(Alignment{Horizontal: "center", Vertical: "bottom"}).
+		Protection(Protection{Locked: true}).
+		NumFmt(10).
+		Build(f)
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	assert.Equal(t, 10, *xf.NumFmtID)
+	assert.True(t, *f.Styles.Fonts.Font[*xf.FontID].B.Val)
+	assert.Equal(t, "FF4472C4", f.Styles.Fills.Fill[*xf.FillID].PatternFill.FgColor.RGB)
+	assert.Equal(t, "center", xf.Alignment.Horizontal)
+	// Test build style with custom number format
+	styleID, err = NewStyleBuilder().CustomNumFmt("0.000").DecimalPlaces(3).Lang("zh-cn").NegRed(true).Build(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000", f.Styles.NumFmts.NumFmt[len(f.Styles.NumFmts.NumFmt)-1].FormatCode)
+	style, err := NewStyleBuilder().Fill(Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 5}).Style()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, style.Fill.Shading)
+	// Test build style with font vertical alignment, charset and scheme
+	styleID, err = NewStyleBuilder().Font(Font{VertAlign: "superscript", Charset: intPtr(0), Scheme: "minor", Shadow: true}).Build(f)
+	assert.NoError(t, err)
+	fnt := f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[styleID].FontID]
+	assert.Equal(t, "superscript", *fnt.VertAlign.Val)
+	assert.Equal(t, 0, *fnt.Charset.Val)
+	assert.Equal(t, "minor", *fnt.Scheme.Val)
+	assert.True(t, *fnt.Shadow.Val)
+	assert.Nil(t, fnt.Outline)
+	// Test build style with invalid settings
+	for _, c := range []struct {
+		builder *StyleBuilder
+		err     string
+	}{
+		{NewStyleBuilder().Font(Font{Family: strings.Repeat("s", MaxFontFamilyLength+1)}), ErrFontLength.Error()},
+		{NewStyleBuilder().Font(Font{Size: MaxFontSize + 1}), ErrFontSize.Error()},
+		{NewStyleBuilder().Font(Font{Color: "red"}), ErrFontColor.Error()},
+		{NewStyleBuilder().Font(Font{Underline: "triple"}), `invalid font underline "triple"`},
+		{NewStyleBuilder().Font(Font{VertAlign: "top"}), `invalid font vert_align "top"`},
+		{NewStyleBuilder().Font(Font{Scheme: "minr"}), `invalid font scheme "minr"`},
+		{NewStyleBuilder().Fill(Fill{Type: "solid"}), ErrFillType.Error()},
+		{NewStyleBuilder().Fill(Fill{Type: "pattern", Color: []string{"#FFFFFF"}, Pattern: 19}), ErrFillPattern.Error()},
+		{NewStyleBuilder().Fill(Fill{Type: "pattern", Pattern: 1}), ErrFillColor.Error()},
+		{NewStyleBuilder().Fill(Fill{Type: "pattern", Color: []string{"#GGGGGG"}, Pattern: 1}), ErrFillColor.Error()},
+		{NewStyleBuilder().Fill(Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 6}), ErrFillShading.Error()},
+		{NewStyleBuilder().Fill(Fill{Type: "gradient", Color: []string{"#FFFFFF"}}), ErrFillColor.Error()},
+		{NewStyleBuilder().Border(Border{Type: "inside", Style: 1}), `invalid border type "inside"`},
+		{NewStyleBuilder().Border(Border{Type: "top", Style: 14}), ErrBorderStyle.Error()},
+		{NewStyleBuilder().Border(Border{Type: "top", Color: "#000", Style: 1}), `invalid border color "#000"`},
+		{NewStyleBuilder().Alignment(Alignment{Horizontal: "middle"}), `invalid horizontal alignment "middle"`},
+		{NewStyleBuilder().Alignment(Alignment{Vertical: "middle"}), `invalid vertical alignment "middle"`},
+		{NewStyleBuilder().NumFmt(-1), `invalid number format "-1"`},
+	} {
+		_, err = c.builder.Build(f)
+		assert.EqualError(t, err, c.err)
+	}
+}
+
 func TestGetDefaultFont(t *testing.T) {
 	f := NewFile()
 	s := f.GetDefaultFont()
@@ -278,6 +680,117 @@ func TestSetCellStyle(t *testing.T) {
 	assert.EqualError(t, f.SetCellStyle("SheetN", "A1", "A2", 1), "sheet SheetN is not exist")
 }
 
+func TestSetCellStyles(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "B2"))
+	assert.NoError(t, f.SetCellStyles("Sheet1", []string{"A1", "C3", "B2", "E5"}, style))
+	for _, cell := range []string{"A1", "B2", "C3", "E5"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, style, styleID, cell)
+	}
+	for _, cell := range []string{"B1", "A2", "D5"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, styleID, cell)
+	}
+	value, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", value)
+	assert.NoError(t, f.SetCellStyles("Sheet1", nil, style))
+	// Test set cell styles with invalid cell reference.
+	assert.EqualError(t, f.SetCellStyles("Sheet1", []string{"A1", "A"}, style), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test set cell styles on not exists worksheet.
+	assert.EqualError(t, f.SetCellStyles("SheetN", []string{"A1"}, style), "sheet SheetN is not exist")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellStyles.xlsx")))
+}
+
+func TestSetRangeStyles(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Italic: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetRangeStyles("Sheet1", []string{"C3:A1", "E5:F6", "H8"}, style))
+	for cell, expected := range map[string]int{"A1": style, "B2": style, "C3": style, "D4": 0, "F6": style, "E6": style, "G7": 0, "H8": style} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test set range styles with invalid range.
+	assert.EqualError(t, f.SetRangeStyles("Sheet1", []string{"A1:B2:C3"}, style), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.SetRangeStyles("Sheet1", []string{"A1:B"}, style), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
+}
+
+func BenchmarkSetCellStyles(b *testing.B) {
+	f := NewFile()
+	style, _ := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	cells := make([]string, 0, 10000)
+	for row := 1; row <= 1000; row++ {
+		for col := 1; col <= 10; col++ {
+			cell, _ := CoordinatesToCellName(col*2, row)
+			cells = append(cells, cell)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = f.SetCellStyles("Sheet1", cells, style)
+	}
+}
+
+func TestGetCellStyles(t *testing.T) {
+	f := NewFile()
+	style1, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	style2, err := f.NewStyle(&Style{Font: &Font{Italic: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetColStyle("Sheet1", "C", style2))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", style1))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B3", "B3", style2))
+	assert.NoError(t, f.MergeCell("Sheet1", "D1", "E2"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D1", "D1", style1))
+	styles, err := f.GetCellStyles("Sheet1", "E3:A1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{style1, 0, style2, style1, style1}, {style1, 0, style2, style1, style1}, {0, style2, style2, 0, 0}}, styles)
+	for r, row := range styles {
+		for c, style := range row {
+			cell, err := CoordinatesToCellName(c+1, r+1)
+			assert.NoError(t, err)
+			expected, err := f.GetCellStyle("Sheet1", cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, style, cell)
+		}
+	}
+	styles, err = f.GetCellStyles("Sheet1", "C100")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{style2}}, styles)
+
+	rowStyles, err := f.GetRowStyles("Sheet1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, style2, style2, 0, 0}, rowStyles)
+	rowStyles, err = f.GetRowStyles("Sheet1", 200)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{}, rowStyles)
+
+	// Test get cell styles with invalid parameters.
+	_, err = f.GetCellStyles("SheetN", "A1:B2")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	_, err = f.GetCellStyles("Sheet1", "A:B2")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.GetRowStyles("SheetN", 1)
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	_, err = f.GetRowStyles("Sheet1", 0)
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+	// Test get cell styles with invalid merged cell reference.
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells.Cells[0].Ref = "D"
+	_, err = f.GetCellStyles("Sheet1", "A1:B2")
+	assert.EqualError(t, err, ErrParameterInvalid.Error())
+	_, err = f.GetRowStyles("Sheet1", 1)
+	assert.EqualError(t, err, ErrParameterInvalid.Error())
+}
+
 func TestGetStyleID(t *testing.T) {
 	assert.Equal(t, -1, NewFile().getStyleID(&xlsxStyleSheet{}, nil))
 }
@@ -313,7 +826,35 @@ func TestThemeColor(t *testing.T) {
 		{"FFFFFFFF", ThemeColor("000000", 1)},
 		{"FFFFFFFF", ThemeColor(strings.Repeat(string(rune(math.MaxUint8+1)), 6), 1)},
 		{"FFFFFFFF", ThemeColor(strings.Repeat(string(rune(-1)), 6), 1)},
+		{"80FFFFFF", ThemeColor("#80000000", 1)},
+		{"804472C4", ThemeColor("804472C4", 0)},
 	} {
 		assert.Equal(t, clr[0], clr[1])
 	}
 }
+
+func TestHexColor(t *testing.T) {
+	for hex, expected := range map[string]color.NRGBA{
+		"4472C4":    {R: 0x44, G: 0x72, B: 0xC4, A: 0xFF},
+		"#4472c4":   {R: 0x44, G: 0x72, B: 0xC4, A: 0xFF},
+		"804472C4":  {R: 0x44, G: 0x72, B: 0xC4, A: 0x80},
+		"#00FFFFFF": {R: 0xFF, G: 0xFF, B: 0xFF},
+	} {
+		c, err := ParseHexColor(hex)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, c)
+	}
+	for _, hex := range []string{"", "#", "44C4", "4472C4F", "#GG72C4", "+472C4", "FF4472C4FF"} {
+		_, err := ParseHexColor(hex)
+		assert.EqualError(t, err, ErrHexColor.Error(), hex)
+	}
+	assert.Equal(t, "FF4472C4", FormatHexColor(color.RGBA{R: 0x44, G: 0x72, B: 0xC4, A: 0xFF}))
+	assert.Equal(t, "80FF0000", FormatHexColor(color.NRGBA{R: 0xFF, A: 0x80}))
+	assert.Equal(t, "FF808080", FormatHexColor(HSL{L: 0.5}))
+	// Test apply tint value on the color.
+	assert.Equal(t, color.NRGBA{R: 0x8F, G: 0xAA, B: 0xDC, A: 0xFF}, TintColor(color.NRGBA{R: 0x44, G: 0x72, B: 0xC4, A: 0xFF}, 0.4))
+	assert.Equal(t, color.NRGBA{R: 0x2F, G: 0x55, B: 0x97, A: 0x80}, TintColor(color.NRGBA{R: 0x44, G: 0x72, B: 0xC4, A: 0x80}, -0.25))
+	// Test the palette color keep the alpha channel of ARGB color.
+	assert.Equal(t, "804472C4", getPaletteColor("#804472c4"))
+	assert.Equal(t, "FF4472C4", getPaletteColor("4472c4"))
+}
@@ -12,12 +12,18 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2/parse"
 )
 
 // parseFormatTableSet provides a function to parse the format settings of the
@@ -51,9 +57,15 @@ func parseFormatTableSet(formatSet string) (*formatTable, error) {
 // Note that the table must be at least two lines including the header. The
 // header cells must contain strings and must be unique, and must set the
 // header row data of the table before calling the AddTable function. Multiple
-// tables coordinate areas that can't have an intersection.
+// tables coordinate areas that can't have an intersection, and the table
+// can't overlap with the merged cells or the auto filter of the worksheet,
+// the ErrTableOverlap, ErrTableMergeCell or ErrAutoFilterTable will be
+// returned in these cases.
 //
-// table_name: The name of the table, in the same worksheet name of the table should be unique
+// table_name: The name of the table, the name of the table should be unique
+// in the workbook, must begin with a letter, an underscore or a backslash,
+// and the remaining characters can be letters, numbers, periods and
+// underscores
 //
 // table_style: The built-in table style names
 //
@@ -83,6 +95,17 @@ func (f *File) AddTable(sheet, hcell, vcell, format string) error {
 	if vrow < hrow {
 		vrow, hrow = hrow, vrow
 	}
+	if err = f.checkTableName(formatSet.TableName); err != nil {
+		return err
+	}
+	// Correct the minimum number of rows, the table at least two lines.
+	rect := []int{hcol, hrow, vcol, vrow}
+	if hrow == vrow {
+		rect[3]++
+	}
+	if err = f.checkTableRange(sheet, rect, ""); err != nil {
+		return err
+	}
 
 	tableID := f.countTables() + 1
 	sheetRelationshipsTableXML := "../tables/table" + strconv.Itoa(tableID) + ".xml"
@@ -114,6 +137,159 @@ func (f *File) countTables() int {
 	return count
 }
 
+// getTables provides a function to get the tables in the workbook by given
+// table parts path, all tables in the workbook will be returned if the paths
+// are not specified.
+func (f *File) getTables(paths ...string) ([]*xlsxTable, error) {
+	var tables []*xlsxTable
+	if len(paths) == 0 {
+		f.Pkg.Range(func(k, v interface{}) bool {
+			if name := k.(string); strings.HasPrefix(name, "xl/tables/table") && strings.HasSuffix(name, ".xml") {
+				paths = append(paths, name)
+			}
+			return true
+		})
+	}
+	for _, tableXML := range paths {
+		if _, ok := f.Pkg.Load(tableXML); !ok {
+			continue
+		}
+		table := new(xlsxTable)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(tableXML)))).
+			Decode(table); err != nil && err != io.EOF {
+			return tables, fmt.Errorf("xml decode error: %s", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// getSheetTables provides a function to get the tables in a worksheet by
+// given worksheet name.
+func (f *File) getSheetTables(sheet string) ([]*xlsxTable, error) {
+	paths, err := f.getSheetTablePaths(sheet)
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	return f.getTables(paths...)
+}
+
+// getSheetTablePaths provides a function to get the paths of the table parts
+// in a worksheet by given worksheet name.
+func (f *File) getSheetTablePaths(sheet string) ([]string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.TableParts == nil {
+		return nil, err
+	}
+	var paths []string
+	for _, tablePart := range ws.TableParts.TableParts {
+		target := f.getSheetRelationshipsTargetByID(sheet, tablePart.RID)
+		if target == "" {
+			continue
+		}
+		paths = append(paths, strings.Replace(target, "..", "xl", -1))
+	}
+	return paths, err
+}
+
+// getSheetTable provides a function to get the table and the path of the
+// table part in a worksheet by given worksheet name and case-insensitive
+// table name.
+func (f *File) getSheetTable(sheet, name string) (*xlsxTable, string, error) {
+	paths, err := f.getSheetTablePaths(sheet)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, tableXML := range paths {
+		tables, err := f.getTables(tableXML)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(tables) == 1 && strings.EqualFold(tables[0].Name, name) {
+			return tables[0], tableXML, err
+		}
+	}
+	return nil, "", ErrTableNotExist
+}
+
+// checkTableName provides a function to check if the table name is valid
+// and unique in the workbook. The name of the table must begin with a
+// letter, an underscore or a backslash, the remaining characters can be
+// letters, numbers, periods and underscores, and the name can't be the same
+// as a cell reference.
+func (f *File) checkTableName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !tableNameRegexp.MatchString(name) || utf8.RuneCountInString(name) > 255 {
+		return ErrTableName
+	}
+	if _, _, err := CellNameToCoordinates(name); err == nil {
+		return ErrTableName
+	}
+	tables, err := f.getTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table.Name, name) {
+			return ErrTableNameDuplicate
+		}
+	}
+	return err
+}
+
+// tableNameRegexp defined the regular expression to match the valid table
+// name.
+var tableNameRegexp = regexp.MustCompile(`^[\p{L}_\\][\p{L}\p{N}_.\\]*$`)
+
+// checkTableRange provides a function to check if the range of the table
+// overlaps with the other tables, the merged cells or the auto filter in the
+// worksheet by given worksheet name, the sorted coordinates of the range and
+// the name of the table which will be skipped in the overlap checking.
+func (f *File) checkTableRange(sheet string, rect []int, exclude string) error {
+	if err := f.checkSheetTablesOverlap(sheet, rect, ErrTableOverlap, exclude); err != nil {
+		return err
+	}
+	ws, _ := f.workSheetReader(sheet)
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			if coordinates, err := f.areaRefToCoordinates(mergeCell.Ref); err == nil && isOverlap(rect, coordinates) {
+				return ErrTableMergeCell
+			}
+		}
+	}
+	if ws.AutoFilter != nil {
+		if coordinates, err := f.areaRefToCoordinates(ws.AutoFilter.Ref); err == nil && isOverlap(rect, coordinates) {
+			return ErrAutoFilterTable
+		}
+	}
+	return nil
+}
+
+// checkSheetTablesOverlap provides a function to check if the given range
+// overlaps with any tables in the worksheet by given worksheet name, the
+// sorted coordinates of the range, the error on overlapped and the name of
+// the table which will be skipped.
+func (f *File) checkSheetTablesOverlap(sheet string, rect []int, overlapErr error, exclude string) error {
+	tables, err := f.getSheetTables(sheet)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if exclude != "" && strings.EqualFold(table.Name, exclude) {
+			continue
+		}
+		if coordinates, err := f.areaRefToCoordinates(table.Ref); err == nil && isOverlap(rect, coordinates) {
+			return overlapErr
+		}
+	}
+	return nil
+}
+
 // addSheetTable provides a function to add tablePart element to
 // xl/worksheets/sheet%d.xml by given worksheet name and relationship index.
 func (f *File) addSheetTable(sheet string, rID int) error {
@@ -198,6 +374,648 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet
 	return nil
 }
 
+// SetTableRange provides a function to resize the table by given worksheet
+// name, table name and the new range reference of the table. The header row
+// of the table can't be moved and the new range must overlap the original
+// range, the ErrTableRange will be returned in these cases. The conditional
+// formats and data validations which cover the entire data rows of the
+// table columns will be expanded or shrunk with the table. For example,
+// resize the table named Sales to the range A1:D20 on Sheet1:
+//
+//    err := f.SetTableRange("Sheet1", "Sales", "A1:D20")
+//
+func (f *File) SetTableRange(sheet, name, area string) error {
+	table, tableXML, err := f.getSheetTable(sheet, name)
+	if err != nil {
+		return err
+	}
+	rect, err := f.areaRefToCoordinates(area)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(rect)
+	origin, err := f.areaRefToCoordinates(table.Ref)
+	if err != nil {
+		return err
+	}
+	// Correct the minimum number of rows, the table at least two lines.
+	if rect[1] == rect[3] {
+		rect[3]++
+	}
+	if rect[1] != origin[1] || !isOverlap(rect, origin) {
+		return ErrTableRange
+	}
+	if err = f.checkTableRange(sheet, rect, table.Name); err != nil {
+		return err
+	}
+	ref, err := f.coordinatesToAreaRef(rect)
+	if err != nil {
+		return err
+	}
+	var tableColumns []*xlsxTableColumn
+	var maxID int
+	if table.TableColumns != nil {
+		for _, column := range table.TableColumns.TableColumn {
+			if column.ID > maxID {
+				maxID = column.ID
+			}
+		}
+	}
+	for col := rect[0]; col <= rect[2]; col++ {
+		if idx := col - origin[0]; idx >= 0 && table.TableColumns != nil && idx < len(table.TableColumns.TableColumn) {
+			tableColumns = append(tableColumns, table.TableColumns.TableColumn[idx])
+			continue
+		}
+		maxID++
+		cell, _ := CoordinatesToCellName(col, rect[1])
+		columnName, _ := f.GetCellValue(sheet, cell)
+		if columnName == "" {
+			columnName = "Column" + strconv.Itoa(maxID)
+			_ = f.SetCellStr(sheet, cell, columnName)
+		}
+		tableColumns = append(tableColumns, &xlsxTableColumn{ID: maxID, Name: columnName})
+	}
+	table.Ref = ref
+	if table.AutoFilter != nil {
+		table.AutoFilter.Ref = ref
+	}
+	table.TableColumns = &xlsxTableColumns{Count: len(tableColumns), TableColumn: tableColumns}
+	content, _ := xml.Marshal(table)
+	f.saveFileList(tableXML, content)
+	return f.adjustTableDataRanges(sheet,
+		[]int{origin[0], origin[1] + 1, origin[2], origin[3] - table.TotalsRowCount},
+		[]int{rect[0], rect[1] + 1, rect[2], rect[3] - table.TotalsRowCount})
+}
+
+// adjustTableDataRanges provides a function to adjust the ranges of the
+// conditional formats and data validations which cover the entire data rows
+// of the table columns by given worksheet name, the coordinates of the data
+// rows of the table before and after resizing.
+func (f *File) adjustTableDataRanges(sheet string, origin, rect []int) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	adjust := func(sqref string) string {
+		refs := strings.Fields(sqref)
+		for idx, r := range refs {
+			coordinates, err := f.areaRefToCoordinates(r)
+			if err != nil || coordinates[1] != origin[1] || coordinates[3] != origin[3] ||
+				coordinates[0] < origin[0] || coordinates[2] > origin[2] ||
+				coordinates[0] < rect[0] || coordinates[2] > rect[2] {
+				continue
+			}
+			coordinates[1], coordinates[3] = rect[1], rect[3]
+			refs[idx], _ = f.coordinatesToAreaRef(coordinates)
+		}
+		return strings.Join(refs, " ")
+	}
+	for _, condFmt := range ws.ConditionalFormatting {
+		if condFmt != nil {
+			condFmt.SQRef = adjust(condFmt.SQRef)
+		}
+	}
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			if dv != nil {
+				dv.Sqref = adjust(dv.Sqref)
+			}
+		}
+	}
+	return err
+}
+
+// RenameTableColumn provides a function to rename the column of the table by
+// given worksheet name, table name, the original column name and the new
+// column name. The header cell of the column and the structured references
+// of the column in the formulas of the cells and the defined names in the
+// workbook will be updated. The new column name must be unique in the table.
+// For example, rename the column Amount of the table named Sales on Sheet1
+// to Total:
+//
+//    err := f.RenameTableColumn("Sheet1", "Sales", "Amount", "Total")
+//
+func (f *File) RenameTableColumn(sheet, table, column, newName string) error {
+	t, tableXML, err := f.getSheetTable(sheet, table)
+	if err != nil {
+		return err
+	}
+	idx := getTableColumnIndex(t, column)
+	if idx == -1 {
+		return ErrTableColumnNotExist
+	}
+	if newName == "" || strings.ContainsAny(newName, "\r\n") {
+		return ErrTableColumnName
+	}
+	if i := getTableColumnIndex(t, newName); i != -1 && i != idx {
+		return ErrTableColumnName
+	}
+	coordinates, err := f.areaRefToCoordinates(t.Ref)
+	if err != nil {
+		return err
+	}
+	tableColumn := t.TableColumns.TableColumn[idx]
+	origin := tableColumn.Name
+	tableColumn.Name = newName
+	content, _ := xml.Marshal(t)
+	f.saveFileList(tableXML, content)
+	cell, _ := CoordinatesToCellName(coordinates[0]+idx, coordinates[1])
+	if err = f.SetCellStr(sheet, cell, newName); err != nil {
+		return err
+	}
+	return f.rewriteTableStructRefs(sheet, t, coordinates, func(ref *structRef) {
+		for i, name := range ref.columns {
+			if strings.EqualFold(name, origin) {
+				ref.columns[i] = newName
+			}
+		}
+	})
+}
+
+// rewriteTableStructRefs provides a function to rewrite the structured
+// references of the table in the formulas of the cells and the defined names
+// in the workbook by given worksheet name of the table, the table, the
+// coordinates of the table range and the function to update the structured
+// references. The structured references without the table name in the
+// cells of the table are also rewritten.
+func (f *File) rewriteTableStructRefs(sheet string, table *xlsxTable, coordinates []int, fn func(ref *structRef)) error {
+	rewrite := func(formula string, inTable bool) string {
+		formula, _ = replaceStructRefs(formula, func(name, specifier string) (string, error) {
+			ref, err := parseStructRef(name, specifier)
+			if err != nil || name == "" && !inTable || name != "" && !strings.EqualFold(name, table.Name) {
+				return name + "[" + specifier + "]", nil
+			}
+			fn(ref)
+			return ref.String(), nil
+		})
+		return formula
+	}
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			return err
+		}
+		for i := range ws.SheetData.Row {
+			for j := range ws.SheetData.Row[i].C {
+				c := &ws.SheetData.Row[i].C[j]
+				if c.F == nil || c.F.Content == "" {
+					continue
+				}
+				col, row, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return err
+				}
+				c.F.Content = rewrite(c.F.Content, name == sheet &&
+					col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3])
+			}
+		}
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			wb.DefinedNames.DefinedName[idx].Data = rewrite(wb.DefinedNames.DefinedName[idx].Data, false)
+		}
+	}
+	return nil
+}
+
+// structRefRegexp defined the regular expression to match the structured
+// reference of the table columns, such as Sales[Amount], Sales[#Data] and
+// Sales[[Region]:[Amount]].
+var structRefRegexp = regexp.MustCompile(`^([\p{L}_\\][\p{L}\p{N}_.\\]*)\[(.*)\]$`)
+
+// resolveStructRefs provides a function to convert the structured
+// references of the table columns in the space separated range references to
+// the range references of the data rows of the table columns by given
+// worksheet name and range references. The special characters in the column
+// name should be escaped by the single quotation mark.
+func (f *File) resolveStructRefs(sheet, sqref string) (string, error) {
+	if !strings.Contains(sqref, "[") {
+		return sqref, nil
+	}
+	refs := splitStructRefs(sqref)
+	for idx, r := range refs {
+		matches := structRefRegexp.FindStringSubmatch(r)
+		if matches == nil {
+			continue
+		}
+		table, _, err := f.getSheetTable(sheet, matches[1])
+		if err != nil {
+			return sqref, err
+		}
+		coordinates, err := f.areaRefToCoordinates(table.Ref)
+		if err != nil {
+			return sqref, err
+		}
+		coordinates[1], coordinates[3] = coordinates[1]+1, coordinates[3]-table.TotalsRowCount
+		if !strings.EqualFold(matches[2], "#Data") {
+			specifiers := []string{matches[2]}
+			if strings.HasPrefix(matches[2], "[") && strings.HasSuffix(matches[2], "]") {
+				specifiers = strings.Split(strings.TrimSuffix(strings.TrimPrefix(matches[2], "["), "]"), "]:[")
+			}
+			if len(specifiers) > 2 {
+				return sqref, ErrTableColumnNotExist
+			}
+			var cols []int
+			for _, specifier := range specifiers {
+				col := getTableColumnIndex(table, unescapeStructRefColumn(specifier))
+				if col == -1 {
+					return sqref, ErrTableColumnNotExist
+				}
+				cols = append(cols, coordinates[0]+col)
+			}
+			coordinates[0], coordinates[2] = cols[0], cols[len(cols)-1]
+			_ = sortCoordinates(coordinates)
+		}
+		if refs[idx], err = f.coordinatesToAreaRef(coordinates); err != nil {
+			return sqref, err
+		}
+	}
+	return strings.Join(refs, " "), nil
+}
+
+// splitStructRefs provides a function to split the space separated range
+// references, the spaces in the brackets of the structured references will
+// be kept.
+func splitStructRefs(sqref string) []string {
+	var refs []string
+	var depth, start int
+	for i, c := range sqref + " " {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case c == ' ' && depth == 0:
+			if i > start {
+				refs = append(refs, sqref[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return refs
+}
+
+// getTableColumnIndex provides a function to get the zero-based index of the
+// table column by given table and case-insensitive column name, returns -1 if
+// the column does not exist.
+func getTableColumnIndex(table *xlsxTable, name string) int {
+	if table.TableColumns == nil {
+		return -1
+	}
+	for idx, column := range table.TableColumns.TableColumn {
+		if strings.EqualFold(column.Name, name) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// unescapeStructRefColumn provides a function to remove the single
+// quotation marks which escape the special characters in the column name of
+// the structured reference.
+func unescapeStructRefColumn(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\'' && i+1 < len(name) {
+			i++
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// structRef directly maps the structured reference of the table in the
+// formula, such as Sales[Amount], Sales[[#Headers],[Region]:[Amount]] and
+// [@Amount]. The special items are #All, #Data, #Headers, #Totals and #This
+// Row, and the columns are the name of the column or the first and last
+// column names of the column range.
+type structRef struct {
+	table   string
+	items   []string
+	columns []string
+	thisRow bool
+}
+
+var (
+	// structRefItems defined the special items of the structured references.
+	structRefItems = []string{"#ALL", "#DATA", "#HEADERS", "#TOTALS", "#THIS ROW"}
+	// structRefSpecialChars defined the characters in the column name which
+	// require the column name to be enclosed in the brackets.
+	structRefSpecialChars = " \t\r\n,:.[]#'\"{}$^&*+=-<>/@!%()"
+)
+
+// escapeStructRefColumn provides a function to escape the special
+// characters in the column name of the structured reference by the single
+// quotation mark.
+func escapeStructRefColumn(name string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(name)
+}
+
+// parseStructRef provides a function to parse the structured reference by
+// given table name and the specifier in the brackets.
+func parseStructRef(table, specifier string) (*structRef, error) {
+	ref := &structRef{table: table}
+	specifier = strings.TrimSpace(specifier)
+	if strings.HasPrefix(specifier, "@") {
+		ref.thisRow, specifier = true, strings.TrimSpace(specifier[1:])
+	}
+	if !strings.HasPrefix(specifier, "[") {
+		if strings.HasPrefix(specifier, "#") && !ref.thisRow {
+			ref.items = append(ref.items, specifier)
+			return ref, ref.check()
+		}
+		if specifier != "" {
+			ref.columns = append(ref.columns, unescapeStructRefColumn(specifier))
+		}
+		return ref, nil
+	}
+	var isRange bool
+	for i := 0; i < len(specifier); i++ {
+		switch specifier[i] {
+		case ' ', ',':
+			continue
+		case ':':
+			if isRange || len(ref.columns) != 1 {
+				return ref, ErrInvalidFormula
+			}
+			isRange = true
+		case '[':
+			end := i + 1
+			for ; end < len(specifier) && specifier[end] != ']'; end++ {
+				if specifier[end] == '\'' {
+					end++
+				}
+			}
+			if end >= len(specifier) {
+				return ref, ErrInvalidFormula
+			}
+			name := specifier[i+1 : end]
+			if strings.HasPrefix(name, "#") {
+				ref.items = append(ref.items, name)
+			} else if len(ref.columns) == 0 || isRange && len(ref.columns) == 1 {
+				ref.columns = append(ref.columns, unescapeStructRefColumn(name))
+			} else {
+				return ref, ErrInvalidFormula
+			}
+			i = end
+		default:
+			return ref, ErrInvalidFormula
+		}
+	}
+	if isRange && len(ref.columns) != 2 {
+		return ref, ErrInvalidFormula
+	}
+	return ref, ref.check()
+}
+
+// check provides a function to check the special items of the structured
+// reference.
+func (ref *structRef) check() error {
+	for _, item := range ref.items {
+		if inStrSlice(structRefItems, strings.ToUpper(item)) == -1 {
+			return ErrInvalidFormula
+		}
+	}
+	return nil
+}
+
+// String returns the formula text of the structured reference, the special
+// characters in the column names will be escaped.
+func (ref *structRef) String() string {
+	var columns []string
+	for _, column := range ref.columns {
+		columns = append(columns, "["+escapeStructRefColumn(column)+"]")
+	}
+	column := strings.Join(columns, ":")
+	simple := len(ref.columns) == 1 && !strings.ContainsAny(ref.columns[0], structRefSpecialChars)
+	if len(ref.items) == 0 {
+		prefix := ""
+		if ref.thisRow {
+			prefix = "@"
+		}
+		if simple || len(ref.columns) == 0 {
+			return ref.table + "[" + prefix + strings.Join(ref.columns, "") + "]"
+		}
+		if ref.thisRow {
+			return ref.table + "[@" + column + "]"
+		}
+		return ref.table + "[" + column + "]"
+	}
+	if len(ref.columns) == 0 && len(ref.items) == 1 {
+		return ref.table + "[" + ref.items[0] + "]"
+	}
+	var specifiers []string
+	for _, item := range ref.items {
+		specifiers = append(specifiers, "["+item+"]")
+	}
+	if column != "" {
+		specifiers = append(specifiers, column)
+	}
+	return ref.table + "[" + strings.Join(specifiers, ",") + "]"
+}
+
+// isStructRefNameChar returns if the character could be used in the table
+// name of the structured reference.
+func isStructRefNameChar(c byte) bool {
+	return c == '_' || c == '.' || c == '\\' || c >= '0' && c <= '9' ||
+		c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= utf8.RuneSelf
+}
+
+// replaceStructRefs provides a function to replace the structured
+// references in the formula by given function, which returns the
+// replacement text of each structured reference by given table name and the
+// specifier in the brackets. The table name will be empty if the structured
+// reference is used in the table without the table name. The texts and the
+// quoted worksheet names in the formula will be kept.
+func replaceStructRefs(formula string, fn func(table, specifier string) (string, error)) (string, error) {
+	if !strings.Contains(formula, "[") {
+		return formula, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		if c == '"' || c == '\'' {
+			end := i + 1
+			for ; end < len(formula); end++ {
+				if formula[end] == c {
+					if end+1 < len(formula) && formula[end+1] == c {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			if end >= len(formula) {
+				end = len(formula) - 1
+			}
+			b.WriteString(formula[i : end+1])
+			i = end
+			continue
+		}
+		if c != '[' {
+			b.WriteByte(c)
+			continue
+		}
+		depth, end := 0, i
+		for ; end < len(formula); end++ {
+			if formula[end] == '\'' {
+				end++
+				continue
+			}
+			if formula[end] == '[' {
+				depth++
+			}
+			if formula[end] == ']' {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		if end >= len(formula) || end+1 < len(formula) && (isStructRefNameChar(formula[end+1]) || formula[end+1] == '\'') {
+			// the external workbook reference such as [1]Sheet1!A1
+			if end >= len(formula) {
+				end = len(formula) - 1
+			}
+			b.WriteString(formula[i : end+1])
+			i = end
+			continue
+		}
+		start := b.Len()
+		text := b.String()
+		for start > 0 && isStructRefNameChar(text[start-1]) {
+			start--
+		}
+		replacement, err := fn(text[start:], formula[i+1:end])
+		if err != nil {
+			return formula, err
+		}
+		b.Reset()
+		b.WriteString(text[:start])
+		b.WriteString(replacement)
+		i = end
+	}
+	return b.String(), nil
+}
+
+// getWorkbookTable provides a function to get the table, the worksheet name
+// and the path of the table part in the workbook by given case-insensitive
+// table name.
+func (f *File) getWorkbookTable(name string) (*xlsxTable, string, string, error) {
+	for _, sheet := range f.GetSheetList() {
+		if table, tableXML, err := f.getSheetTable(sheet, name); err == nil {
+			return table, sheet, tableXML, err
+		}
+	}
+	return nil, "", "", ErrTableNotExist
+}
+
+// getCellTable provides a function to get the table which contains the cell
+// by given worksheet name and cell coordinates.
+func (f *File) getCellTable(sheet string, col, row int) (*xlsxTable, error) {
+	tables, err := f.getSheetTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if coordinates, err := f.areaRefToCoordinates(table.Ref); err == nil &&
+			col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+			return table, nil
+		}
+	}
+	return nil, ErrTableNotExist
+}
+
+// resolveFormulaStructRefs provides a function to convert the structured
+// references in the formula to the range references by given worksheet name
+// and cell coordinates of the formula cell.
+func (f *File) resolveFormulaStructRefs(sheet, cell, formula string) (string, error) {
+	return replaceStructRefs(formula, func(name, specifier string) (string, error) {
+		var table *xlsxTable
+		tableSheet, err := sheet, ErrTableNotExist
+		col, row, _ := CellNameToCoordinates(cell)
+		if name == "" {
+			table, err = f.getCellTable(sheet, col, row)
+		} else {
+			table, tableSheet, _, err = f.getWorkbookTable(name)
+		}
+		if err != nil {
+			return "", err
+		}
+		ref, err := parseStructRef(table.Name, specifier)
+		if err != nil {
+			return "", err
+		}
+		coordinates, err := f.areaRefToCoordinates(table.Ref)
+		if err != nil {
+			return "", err
+		}
+		if err = ref.coordinates(table, coordinates, row); err != nil {
+			return "", err
+		}
+		area, err := f.coordinatesToAreaRef(coordinates)
+		if coordinates[0] == coordinates[2] && coordinates[1] == coordinates[3] {
+			area, err = CoordinatesToCellName(coordinates[0], coordinates[1])
+		}
+		if tableSheet != sheet {
+			return parse.Reference{Sheet: tableSheet, Ref: area}.String(), err
+		}
+		return area, err
+	})
+}
+
+// coordinates provides a function to update the coordinates of the table
+// range to the range which is referenced by the structured reference by
+// given table, the coordinates of the table range and the row number of the
+// formula cell.
+func (ref *structRef) coordinates(table *xlsxTable, coordinates []int, row int) error {
+	header, data, totals := coordinates[1], []int{coordinates[1] + 1, coordinates[3] - table.TotalsRowCount}, coordinates[3]-table.TotalsRowCount+1
+	rows := make([]int, 0, 2)
+	if ref.thisRow {
+		if row < data[0] || row > data[1] {
+			return ErrInvalidFormula
+		}
+		rows = append(rows, row, row)
+	}
+	for _, item := range ref.items {
+		switch strings.ToUpper(item) {
+		case "#ALL":
+			rows = append(rows, coordinates[1], coordinates[3])
+		case "#DATA":
+			rows = append(rows, data...)
+		case "#HEADERS":
+			rows = append(rows, header, header)
+		case "#TOTALS":
+			if table.TotalsRowCount == 0 {
+				return ErrInvalidFormula
+			}
+			rows = append(rows, totals, coordinates[3])
+		case "#THIS ROW":
+			if row < data[0] || row > data[1] {
+				return ErrInvalidFormula
+			}
+			rows = append(rows, row, row)
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, data...)
+	}
+	sort.Ints(rows)
+	coordinates[1], coordinates[3] = rows[0], rows[len(rows)-1]
+	if len(ref.columns) > 0 {
+		var cols []int
+		for _, column := range ref.columns {
+			idx := getTableColumnIndex(table, column)
+			if idx == -1 {
+				return ErrTableColumnNotExist
+			}
+			cols = append(cols, coordinates[0]+idx)
+		}
+		sort.Ints(cols)
+		coordinates[0], coordinates[2] = cols[0], cols[len(cols)-1]
+	}
+	return nil
+}
+
 // parseAutoFilterSet provides a function to parse the settings of the auto
 // filter.
 func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
@@ -294,13 +1112,16 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 		vrow, hrow = hrow, vrow
 	}
 
+	if err = f.checkSheetTablesOverlap(sheet, []int{hcol, hrow, vcol, vrow}, ErrAutoFilterTable, ""); err != nil {
+		return err
+	}
 	formatSet, _ := parseAutoFilterSet(format)
 	cellStart, _ := CoordinatesToCellName(hcol, hrow, true)
 	cellEnd, _ := CoordinatesToCellName(vcol, vrow, true)
-	ref, filterDB := cellStart+":"+cellEnd, "_xlnm._FilterDatabase"
+	ref, filterDB := cellStart+":"+cellEnd, builtInDefinedNameFilterDatabase
 	wb := f.workbookReader()
 	sheetID := f.GetSheetIndex(sheet)
-	filterRange := fmt.Sprintf("%s!%s", sheet, ref)
+	filterRange := fmt.Sprintf("%s!%s", quoteSheetName(sheet), ref)
 	d := xlsxDefinedName{
 		Name:         filterDB,
 		Hidden:       true,
@@ -315,7 +1136,7 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 		var definedNameExists bool
 		for idx := range wb.DefinedNames.DefinedName {
 			definedName := wb.DefinedNames.DefinedName[idx]
-			if definedName.Name == filterDB && *definedName.LocalSheetID == sheetID && definedName.Hidden {
+			if definedName.LocalSheetID != nil && *definedName.LocalSheetID == sheetID && isSameDefinedName(definedName.Name, filterDB) {
 				wb.DefinedNames.DefinedName[idx].Data = filterRange
 				definedNameExists = true
 			}
@@ -3,6 +3,7 @@ package excelize
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,6 +46,143 @@ func TestAddTable(t *testing.T) {
 	assert.EqualError(t, f.addTable("sheet1", "", 1, 1, 0, 0, 0, nil), "invalid cell coordinates [0, 0]")
 }
 
+func TestTableValidation(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddTable("Sheet1", "B2", "D5", `{"table_name":"Sales"}`))
+	// Test add table with the same name in the workbook.
+	f.NewSheet("Sheet2")
+	assert.EqualError(t, f.AddTable("Sheet2", "A1", "B2", `{"table_name":"sales"}`), ErrTableNameDuplicate.Error())
+	// Test add table with invalid table names.
+	for _, name := range []string{"Sales 2021", "1Sales", "A1", "XFD1048576", "Sales-2021", strings.Repeat("s", 256)} {
+		assert.EqualError(t, f.AddTable("Sheet2", "A1", "B2", fmt.Sprintf(`{"table_name":%q}`, name)), ErrTableName.Error())
+	}
+	assert.NoError(t, f.AddTable("Sheet2", "A1", "B2", `{"table_name":"_Sales.2021"}`))
+	// Test add table which overlaps with an existing table.
+	for _, cells := range [][]string{{"A1", "B2"}, {"D5", "F6"}, {"C1", "C9"}, {"A3", "F3"}} {
+		assert.EqualError(t, f.AddTable("Sheet1", cells[0], cells[1], ""), ErrTableOverlap.Error())
+	}
+	// Test add table with the single row which overlaps with a table.
+	assert.EqualError(t, f.AddTable("Sheet1", "B1", "B1", ""), ErrTableOverlap.Error())
+	// Test add table which overlaps with the merged cells.
+	assert.NoError(t, f.MergeCell("Sheet1", "F1", "G2"))
+	assert.EqualError(t, f.AddTable("Sheet1", "G2", "H4", ""), ErrTableMergeCell.Error())
+	// Test merge cells which overlaps with a table.
+	assert.EqualError(t, f.MergeCell("Sheet1", "A3", "B3"), ErrTableMergeCell.Error())
+	// Test add table which overlaps with the auto filter.
+	assert.NoError(t, f.AutoFilter("Sheet1", "J1", "K5", ""))
+	assert.EqualError(t, f.AddTable("Sheet1", "K2", "L4", ""), ErrAutoFilterTable.Error())
+	// Test add auto filter which overlaps with a table.
+	assert.EqualError(t, f.AutoFilter("Sheet1", "A4", "E4", ""), ErrAutoFilterTable.Error())
+	assert.NoError(t, f.AddTable("Sheet1", "M1", "N5", ""))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestTableValidation.xlsx")))
+	assert.EqualError(t, f.AutoFilter("SheetN", "A4", "E4", ""), "sheet SheetN is not exist")
+
+	// Test add table with the tables which are loaded from the file.
+	f, err := OpenFile(filepath.Join("test", "TestTableValidation.xlsx"))
+	assert.NoError(t, err)
+	assert.EqualError(t, f.AddTable("Sheet1", "C3", "C4", ""), ErrTableOverlap.Error())
+	assert.EqualError(t, f.AddTable("Sheet1", "P1", "Q2", `{"table_name":"Sales"}`), ErrTableNameDuplicate.Error())
+	// Test add table in the stream writer with the same name in the workbook.
+	streamWriter, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.AddTable("A1", "B2", `{"table_name":"Sales"}`), ErrTableNameDuplicate.Error())
+	// Test check table with invalid table part.
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddTable("Sheet1", "P1", "Q2", `{"table_name":"Costs"}`), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddTable("Sheet1", "P1", "Q2", ""), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSetTableRange(t *testing.T) {
+	f := NewFile()
+	for idx, header := range []string{"Region", "Unit Price", "Amount"} {
+		cell, _ := CoordinatesToCellName(idx+2, 2)
+		assert.NoError(t, f.SetCellStr("Sheet1", cell, header))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "B2", "D5", `{"table_name":"Sales"}`))
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	// Test set conditional formats and data validations by the structured
+	// references of the table columns.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "Sales[Region]", fmt.Sprintf(`[{"type":"duplicate","criteria":"=","format":%d}]`, format)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "sales[[Unit Price]:[Amount]] F1:F3", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"0"}]`, format)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "Sales[#Data]", fmt.Sprintf(`[{"type":"unique","criteria":"=","format":%d}]`, format)))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "Sales[Amount]"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	var sqref []string
+	for _, condFmt := range ws.ConditionalFormatting {
+		sqref = append(sqref, condFmt.SQRef)
+	}
+	assert.Equal(t, []string{"B3:B5", "C3:D5 F1:F3", "B3:D5"}, sqref)
+	assert.Equal(t, "D3:D5", ws.DataValidations.DataValidation[0].Sqref)
+
+	// Test resize the table.
+	assert.NoError(t, f.SetTableRange("Sheet1", "sales", "B2:E8"))
+	table, _, err := f.getSheetTable("Sheet1", "Sales")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:E8", table.Ref)
+	assert.Equal(t, "B2:E8", table.AutoFilter.Ref)
+	assert.Equal(t, 4, table.TableColumns.Count)
+	assert.Equal(t, "Column4", table.TableColumns.TableColumn[3].Name)
+	assert.Equal(t, 4, table.TableColumns.TableColumn[3].ID)
+	header, err := f.GetCellValue("Sheet1", "E2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Column4", header)
+	sqref = sqref[:0]
+	for _, condFmt := range ws.ConditionalFormatting {
+		sqref = append(sqref, condFmt.SQRef)
+	}
+	assert.Equal(t, []string{"B3:B8", "C3:D8 F1:F3", "B3:D8"}, sqref)
+	assert.Equal(t, "D3:D8", ws.DataValidations.DataValidation[0].Sqref)
+	// Test shrink the table with the header row only.
+	assert.NoError(t, f.SetTableRange("Sheet1", "Sales", "C2:B2"))
+	table, _, err = f.getSheetTable("Sheet1", "Sales")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:C3", table.Ref)
+	assert.Equal(t, []string{"Region", "Unit Price"}, []string{table.TableColumns.TableColumn[0].Name, table.TableColumns.TableColumn[1].Name})
+	assert.Equal(t, "B3:B3", ws.ConditionalFormatting[0].SQRef)
+	assert.Equal(t, "C3:D8 F1:F3", ws.ConditionalFormatting[1].SQRef)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetTableRange.xlsx")))
+
+	// Test resize the table with the moved header row or the range which not
+	// overlaps with the original range.
+	assert.EqualError(t, f.SetTableRange("Sheet1", "Sales", "B1:C3"), ErrTableRange.Error())
+	assert.EqualError(t, f.SetTableRange("Sheet1", "Sales", "H2:I3"), ErrTableRange.Error())
+	// Test resize the table which overlaps with another table.
+	assert.NoError(t, f.AddTable("Sheet1", "H1", "I4", `{"table_name":"Costs"}`))
+	assert.EqualError(t, f.SetTableRange("Sheet1", "Sales", "B2:H5"), ErrTableOverlap.Error())
+	// Test resize the table with invalid range reference.
+	assert.EqualError(t, f.SetTableRange("Sheet1", "Sales", "B2"), "parameter is invalid")
+	// Test resize not exist table.
+	assert.EqualError(t, f.SetTableRange("Sheet1", "Table1", "B2:C3"), ErrTableNotExist.Error())
+	assert.EqualError(t, f.SetTableRange("SheetN", "Sales", "B2:C3"), "sheet SheetN is not exist")
+	// Test structured references with not exist table and columns.
+	for ref, expected := range map[string]error{
+		"Table1[Region]":             ErrTableNotExist,
+		"Sales[Amount]":              ErrTableColumnNotExist,
+		"Sales[[Region]:[Amount]]":   ErrTableColumnNotExist,
+		"Sales[[Region]:[A]:[B]]":    ErrTableColumnNotExist,
+		"Sales[Region] Costs[Price]": ErrTableColumnNotExist,
+	} {
+		assert.EqualError(t, f.SetConditionalFormat("Sheet1", ref, fmt.Sprintf(`[{"type":"duplicate","criteria":"=","format":%d}]`, format)), expected.Error(), ref)
+		dvRange.Sqref = ref
+		assert.EqualError(t, f.AddDataValidation("Sheet1", dvRange), expected.Error(), ref)
+	}
+	// Test structured references with the escaped column name.
+	assert.NoError(t, f.SetCellStr("Sheet1", "J1", "[Price]"))
+	assert.NoError(t, f.SetTableRange("Sheet1", "Costs", "H1:J4"))
+	ref, err := f.resolveStructRefs("Sheet1", "Costs['[Price']]")
+	assert.NoError(t, err)
+	assert.Equal(t, "J2:J4", ref)
+	// Test structured references with invalid table part.
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	_, err = f.resolveStructRefs("Sheet1", "Sales[Region]")
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestAutoFilter(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilter%d.xlsx")
 
@@ -129,3 +267,69 @@ func TestParseFilterTokens(t *testing.T) {
 	_, _, err = f.parseFilterTokens("", []string{"", "<", "x != blanks"})
 	assert.EqualError(t, err, "the operator '<' in expression '' is not valid in relation to Blanks/NonBlanks'")
 }
+
+func TestRenameTableColumn(t *testing.T) {
+	f := NewFile()
+	for idx, header := range []string{"Price", "Qty", "Amount"} {
+		cell, _ := CoordinatesToCellName(idx+1, 1)
+		assert.NoError(t, f.SetCellStr("Sheet1", cell, header))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C4", `{"table_name":"Sales"}`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "[@Price]*[@Qty]"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", `SUM(Sales[Price])&"[Price]"`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E2", "SUM(Sales[[#This Row],[Price]:[Qty]])+SUM(Costs[Price])"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E3", "[@Price]"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "TotalPrice", RefersTo: "SUM(sales[Price])"}))
+	assert.NoError(t, f.RenameTableColumn("Sheet1", "Sales", "price", "Unit Price"))
+	table, _, err := f.getSheetTable("Sheet1", "Sales")
+	assert.NoError(t, err)
+	assert.Equal(t, "Unit Price", table.TableColumns.TableColumn[0].Name)
+	header, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Unit Price", header)
+	for cell, expected := range map[string]string{
+		"C2": "[@[Unit Price]]*[@Qty]",
+		"E1": `SUM(Sales[[Unit Price]])&"[Price]"`,
+		"E2": "SUM(Sales[[#This Row],[Unit Price]:[Qty]])+SUM(Costs[Price])",
+		"E3": "[@Price]",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	assert.Equal(t, "SUM(sales[[Unit Price]])", f.GetDefinedName()[0].RefersTo)
+	// Test rename the table column with invalid or duplicate name.
+	assert.EqualError(t, f.RenameTableColumn("Sheet1", "Sales", "Qty", ""), ErrTableColumnName.Error())
+	assert.EqualError(t, f.RenameTableColumn("Sheet1", "Sales", "Qty", "AMOUNT"), ErrTableColumnName.Error())
+	assert.NoError(t, f.RenameTableColumn("Sheet1", "Sales", "Qty", "QTY"))
+	// Test rename not exist table or column.
+	assert.EqualError(t, f.RenameTableColumn("Sheet1", "Sales", "Price", "Cost"), ErrTableColumnNotExist.Error())
+	assert.EqualError(t, f.RenameTableColumn("Sheet1", "Table1", "Qty", "Cost"), ErrTableNotExist.Error())
+	assert.EqualError(t, f.RenameTableColumn("SheetN", "Sales", "Qty", "Cost"), "sheet SheetN is not exist")
+}
+
+func TestParseStructRef(t *testing.T) {
+	for _, c := range []struct {
+		table, specifier, expected string
+	}{
+		{"Sales", "Amount", "Sales[Amount]"},
+		{"Sales", "#Data", "Sales[#Data]"},
+		{"Sales", "", "Sales[]"},
+		{"", "@", "[@]"},
+		{"", "@Price", "[@Price]"},
+		{"", "@[Unit Price]", "[@[Unit Price]]"},
+		{"Sales", "[Unit Price]", "Sales[[Unit Price]]"},
+		{"Sales", "[Region]:[Amount]", "Sales[[Region]:[Amount]]"},
+		{"Sales", "@[Region]:[Amount]", "Sales[@[Region]:[Amount]]"},
+		{"Sales", "[#Headers],[#Data],[Amount]", "Sales[[#Headers],[#Data],[Amount]]"},
+		{"Sales", "['[Price']]", "Sales[['[Price']]]"},
+	} {
+		ref, err := parseStructRef(c.table, c.specifier)
+		assert.NoError(t, err, c.specifier)
+		assert.Equal(t, c.expected, ref.String(), c.specifier)
+	}
+	for _, specifier := range []string{"#Nope", "[#Nope]", "[A]:[B]:[C]", "[A],[B]", "[#Data]:[A]", "[A]:", "[A", "[A]x"} {
+		_, err := parseStructRef("Sales", specifier)
+		assert.EqualError(t, err, ErrInvalidFormula.Error(), specifier)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// themePreset defined the color scheme and the font scheme of the built-in
+// document theme. The colors are in the order of the dark 1, light 1, dark
+// 2, light 2, accent 1 to 6, hyperlink and followed hyperlink theme colors.
+type themePreset struct {
+	colors               [12]string
+	majorFont, minorFont string
+}
+
+var (
+	// themeColorSchemeElements defined the element names of the theme colors
+	// in the color scheme of the theme.
+	themeColorSchemeElements = [12]string{"dk1", "lt1", "dk2", "lt2", "accent1", "accent2", "accent3", "accent4", "accent5", "accent6", "hlink", "folHlink"}
+	// themePresets defined the built-in document themes by the theme name.
+	themePresets = map[string]themePreset{
+		"Office": {
+			colors:    [12]string{"000000", "FFFFFF", "44546A", "E7E6E6", "5B9BD5", "ED7D31", "A5A5A5", "FFC000", "4472C4", "70AD47", "0563C1", "954F72"},
+			majorFont: "Calibri Light", minorFont: "Calibri",
+		},
+		"Facet": {
+			colors:    [12]string{"000000", "FFFFFF", "2B3B3F", "EDEEEA", "8CB84A", "3E9B8B", "4178B8", "7D62AE", "C15A4B", "D3933C", "5EA131", "8A6BA8"},
+			majorFont: "Trebuchet MS", minorFont: "Trebuchet MS",
+		},
+		"Ion": {
+			colors:    [12]string{"000000", "FFFFFF", "1C4F54", "E9ECEA", "AE1C1A", "E5661A", "DFB02F", "68A98D", "5B9A9C", "9A5B98", "4FB3AC", "8DD9B4"},
+			majorFont: "Century Gothic", minorFont: "Century Gothic",
+		},
+		"Slate": {
+			colors:    [12]string{"000000", "FFFFFF", "262B33", "E4E6E9", "4E6A8C", "7A8FA6", "C98A3D", "8A9A5B", "A2574F", "5F7F7A", "3C6FA8", "7E6A94"},
+			majorFont: "Arial", minorFont: "Arial",
+		},
+		"Meadow": {
+			colors:    [12]string{"000000", "FFFFFF", "3A4A2C", "F1EFE2", "6E9C3A", "B5A33A", "D9773A", "4C8C85", "A45E8C", "7A6A52", "4E8C3A", "8C7A4E"},
+			majorFont: "Georgia", minorFont: "Verdana",
+		},
+	}
+	themeNameRegexp       = regexp.MustCompile(`(<a:theme\b[^>]*?\sname=")[^"]*(")`)
+	themeClrSchemeRegexp  = regexp.MustCompile(`<a:clrScheme\b[^>]*>[\s\S]*?</a:clrScheme>`)
+	themeFontSchemeRegexp = regexp.MustCompile(`<a:fontScheme\b[^>]*>[\s\S]*?</a:fontScheme>`)
+	themeFontNameRegexp   = regexp.MustCompile(`^(<a:fontScheme\b[^>]*?\sname=")[^"]*(")`)
+	themeMajorFontRegexp  = regexp.MustCompile(`(<a:majorFont>\s*)<a:latin\b[^>]*/>`)
+	themeMinorFontRegexp  = regexp.MustCompile(`(<a:minorFont>\s*)<a:latin\b[^>]*/>`)
+)
+
+// GetThemePresets provides a function to get the names of the built-in
+// document themes in alphabetical order, which could be used in the
+// SetThemePreset function.
+func GetThemePresets() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetThemePreset provides a function to apply the built-in document theme by
+// given theme name. The color scheme and the font scheme of the workbook
+// theme will be replaced, and the format scheme of the theme will be kept.
+// The supported theme names are:
+//
+//    Facet
+//    Ion
+//    Meadow
+//    Office
+//    Slate
+//
+// The fonts in the styles which bound to the major or minor font scheme and
+// the default font of the workbook which using the minor font of the
+// current theme will be replaced by the fonts of the new theme. For example,
+// apply the Facet theme to the workbook:
+//
+//    err := f.SetThemePreset("Facet")
+//
+func (f *File) SetThemePreset(name string) error {
+	preset, ok := themePresets[name]
+	if !ok {
+		return ErrThemePreset
+	}
+	oldMinorFont := f.getThemeMinorFont()
+	content := f.readXML("xl/theme/theme1.xml")
+	if len(content) == 0 {
+		f.addThemeRels()
+	}
+	if !themeClrSchemeRegexp.Match(content) || !themeFontSchemeRegexp.Match(content) {
+		content = []byte(templateTheme)
+	}
+	if idx := bytes.Index(content, []byte("?>")); bytes.HasPrefix(content, []byte("<?xml")) && idx != -1 {
+		content = bytes.TrimSpace(content[idx+2:])
+	}
+	content = themeNameRegexp.ReplaceAll(content, []byte("${1}"+name+"${2}"))
+	content = themeClrSchemeRegexp.ReplaceAllLiteral(content, []byte(preset.colorScheme(name)))
+	content = themeFontSchemeRegexp.ReplaceAllFunc(content, func(fontScheme []byte) []byte {
+		fontScheme = themeFontNameRegexp.ReplaceAll(fontScheme, []byte("${1}"+name+"${2}"))
+		fontScheme = themeMajorFontRegexp.ReplaceAll(fontScheme, []byte(fmt.Sprintf(`${1}<a:latin typeface="%s"/>`, preset.majorFont)))
+		return themeMinorFontRegexp.ReplaceAll(fontScheme, []byte(fmt.Sprintf(`${1}<a:latin typeface="%s"/>`, preset.minorFont)))
+	})
+	f.saveFileList("xl/theme/theme1.xml", content)
+	f.Theme = f.themeReader()
+	f.setThemeFonts(oldMinorFont, preset)
+	return nil
+}
+
+// addThemeRels provides a function to add the relationship and the content
+// type of the theme part if it doesn't exist.
+func (f *File) addThemeRels() {
+	relsPath := f.getWorkbookRelsPath()
+	if rels := f.relsReader(relsPath); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipTheme {
+				f.addContentTypeOverride("/xl/theme/theme1.xml", ContentTypeTheme)
+				return
+			}
+		}
+	}
+	f.addRels(relsPath, SourceRelationshipTheme, "theme/theme1.xml", "")
+	f.addContentTypeOverride("/xl/theme/theme1.xml", ContentTypeTheme)
+}
+
+// colorScheme provides a function to get the color scheme XML of the
+// built-in document theme by given theme name. The dark 1 and light 1 colors
+// are bound to the window text and window system colors.
+func (p themePreset) colorScheme(name string) string {
+	var sb strings.Builder
+	sb.WriteString(`<a:clrScheme name="` + name + `">`)
+	for idx, el := range themeColorSchemeElements {
+		sb.WriteString("<a:" + el + ">")
+		switch el {
+		case "dk1":
+			sb.WriteString(`<a:sysClr val="windowText" lastClr="` + p.colors[idx] + `"/>`)
+		case "lt1":
+			sb.WriteString(`<a:sysClr val="window" lastClr="` + p.colors[idx] + `"/>`)
+		default:
+			sb.WriteString(`<a:srgbClr val="` + p.colors[idx] + `"/>`)
+		}
+		sb.WriteString("</a:" + el + ">")
+	}
+	sb.WriteString("</a:clrScheme>")
+	return sb.String()
+}
+
+// getThemeMinorFont provides a function to get the Latin typeface of the
+// minor font in the font scheme of the workbook theme.
+func (f *File) getThemeMinorFont() (minor string) {
+	if f.Theme == nil {
+		return
+	}
+	for _, el := range f.Theme.ThemeElements.FontScheme.MinorFont.Children {
+		if el.XMLName.Local == "latin" {
+			minor = el.Typeface
+		}
+	}
+	return
+}
+
+// setThemeFonts provides a function to replace the fonts in the styles
+// which bound to the font scheme of the theme, and the default font of the
+// workbook which using the minor font of the previous theme by given
+// previous minor font and the built-in document theme.
+func (f *File) setThemeFonts(oldMinorFont string, preset themePreset) {
+	s := f.stylesReader()
+	if s.Fonts == nil {
+		return
+	}
+	for idx, font := range s.Fonts.Font {
+		if font == nil {
+			continue
+		}
+		var scheme string
+		if font.Scheme != nil && font.Scheme.Val != nil {
+			scheme = *font.Scheme.Val
+		}
+		switch {
+		case scheme == "major":
+			font.Name = &attrValString{Val: stringPtr(preset.majorFont)}
+		case scheme == "minor":
+			font.Name = &attrValString{Val: stringPtr(preset.minorFont)}
+		case idx == 0 && font.Name != nil && font.Name.Val != nil && oldMinorFont != "" && *font.Name.Val == oldMinorFont:
+			font.Name.Val = stringPtr(preset.minorFont)
+		}
+	}
+}
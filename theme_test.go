@@ -0,0 +1,83 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetThemePreset(t *testing.T) {
+	assert.Equal(t, []string{"Facet", "Ion", "Meadow", "Office", "Slate"}, GetThemePresets())
+
+	f := NewFile()
+	style, err := f.NewStyle(`{"font":{"family":"Calibri","bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	s := f.stylesReader()
+	s.Fonts.Font = append(s.Fonts.Font, &xlsxFont{Scheme: &attrValString{Val: stringPtr("major")}}, nil)
+	assert.NoError(t, f.SetThemePreset("Facet"))
+	content, ok := f.Pkg.Load("xl/theme/theme1.xml")
+	assert.True(t, ok)
+	theme := string(content.([]byte))
+	for _, expected := range []string{
+		XMLHeader + `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Facet">`,
+		`<a:clrScheme name="Facet"><a:dk1><a:sysClr val="windowText" lastClr="000000"/></a:dk1><a:lt1><a:sysClr val="window" lastClr="FFFFFF"/></a:lt1><a:dk2><a:srgbClr val="2B3B3F"/></a:dk2>`,
+		`<a:accent1><a:srgbClr val="8CB84A"/></a:accent1>`,
+		`<a:fontScheme name="Facet"><a:majorFont><a:latin typeface="Trebuchet MS"/>`,
+		`<a:minorFont><a:latin typeface="Trebuchet MS"/>`,
+		`<a:fmtScheme name="Office">`,
+	} {
+		assert.Contains(t, theme, expected)
+	}
+	assert.Equal(t, "2B3B3F", *f.Theme.ThemeElements.ClrScheme.Children[2].SrgbClr.Val)
+	assert.Equal(t, "Trebuchet MS", f.GetDefaultFont())
+	assert.Equal(t, "Trebuchet MS", *s.Fonts.Font[len(s.Fonts.Font)-2].Name.Val)
+	// Test the fonts not bound to the theme will be kept.
+	assert.Equal(t, "Calibri", *s.Fonts.Font[1].Name.Val)
+
+	// Test apply the theme on the workbook with the XML declaration in the
+	// theme part.
+	assert.NoError(t, f.SetThemePreset("Meadow"))
+	content, _ = f.Pkg.Load("xl/theme/theme1.xml")
+	theme = string(content.([]byte))
+	assert.Contains(t, theme, XMLHeader+`<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Meadow">`)
+	assert.Contains(t, theme, `<a:majorFont><a:latin typeface="Georgia"/>`)
+	assert.Contains(t, theme, `<a:minorFont><a:latin typeface="Verdana"/>`)
+	assert.Equal(t, "Verdana", f.GetDefaultFont())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetThemePreset.xlsx")))
+
+	// Test apply unsupported theme.
+	assert.EqualError(t, f.SetThemePreset("Unknown"), ErrThemePreset.Error())
+
+	// Test apply the theme on the workbook without theme part.
+	f = NewFile()
+	f.Pkg.Delete("xl/theme/theme1.xml")
+	f.Theme = nil
+	f.Styles.Fonts = nil
+	assert.NoError(t, f.SetThemePreset("Ion"))
+	content, ok = f.Pkg.Load("xl/theme/theme1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<a:clrScheme name="Ion">`)
+	var count int
+	for _, rel := range f.relsReader(f.getWorkbookRelsPath()).Relationships {
+		if rel.Type == SourceRelationshipTheme {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// Test apply the theme on the workbook without theme relationship.
+	f = NewFile()
+	f.Pkg.Delete("xl/theme/theme1.xml")
+	rels := f.relsReader(f.getWorkbookRelsPath())
+	for idx, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipTheme {
+			rels.Relationships = append(rels.Relationships[:idx], rels.Relationships[idx+1:]...)
+			break
+		}
+	}
+	assert.NoError(t, f.SetThemePreset("Slate"))
+	assert.Equal(t, SourceRelationshipTheme, rels.Relationships[len(rels.Relationships)-1].Type)
+	assert.Equal(t, "theme/theme1.xml", rels.Relationships[len(rels.Relationships)-1].Target)
+}
@@ -0,0 +1,504 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// timelineVersion defined the minimum version of the application which
+// supports the timelines.
+const timelineVersion = 6
+
+// timelineLevels defined the mapping of the time levels and the level
+// attribute values of the timeline.
+var timelineLevels = map[string]int{
+	"years":    0,
+	"quarters": 1,
+	"months":   2,
+	"days":     3,
+}
+
+// timelineCacheNameRegexp defined the regular expression to match the
+// characters which are invalid in the defined name of the timeline cache.
+var timelineCacheNameRegexp = regexp.MustCompile(`[^\p{L}\p{N}_]`)
+
+// AddTimeline provides a function to add a timeline for the pivot table by
+// given worksheet name and timeline options. The timeline filters the pivot
+// table on a date field of the pivot table data source, and the filter
+// selection will be made in Excel 2013 or later. The default width and height
+// of the timeline are 326 and 141 pixels, the default style is
+// TimeSlicerStyleLight1, and the time level could be the one of the
+// followings:
+//
+//    years
+//    quarters
+//    months
+//    days
+//
+// For example, add a timeline on the Date field of the pivot table named
+// PivotTable1 in the cell H2 on Sheet1:
+//
+//    err := f.AddTimeline("Sheet1", &excelize.TimelineOptions{
+//        Cell:                    "H2",
+//        PivotTable:              "PivotTable1",
+//        Field:                   "Date",
+//        Level:                   "months",
+//        ShowHeader:              true,
+//        ShowSelectionLabel:      true,
+//        ShowTimeLevel:           true,
+//        ShowHorizontalScrollbar: true,
+//    })
+//
+func (f *File) AddTimeline(sheet string, opts *TimelineOptions) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
+	opt, err := parseFormatTimelineSet(opts)
+	if err != nil {
+		return err
+	}
+	tabID, pt, pivotCacheXML, err := f.getTimelinePivotTable(opt.PivotTable)
+	if err != nil {
+		return err
+	}
+	pc, err := f.pivotCacheReader(pivotCacheXML)
+	if err != nil {
+		return err
+	}
+	if !inTimelineCacheFields(pc, opt.Field) {
+		return ErrTimelineField
+	}
+	pivotCacheID, err := f.setPivotCacheExtID(pivotCacheXML, pc, pt.CacheID)
+	if err != nil {
+		return err
+	}
+	if opt.Name, err = f.getTimelineName(opt.Name); err != nil {
+		return err
+	}
+	cacheName := f.getTimelineCacheName(opt.Field)
+	if err = f.addTimelineCache(&xlsxTimelineCacheDefinition{
+		Name:       cacheName,
+		SourceName: opt.Field,
+		PivotTables: &xlsxTimelineCachePivotTables{
+			PivotTable: []*xlsxTimelineCachePivotTable{{TabID: tabID, Name: pt.Name}},
+		},
+		State: &xlsxTimelineState{
+			MinimalRefreshVersion: timelineVersion,
+			LastRefreshVersion:    timelineVersion,
+			PivotCacheID:          pivotCacheID,
+			FilterType:            "unknown",
+		},
+	}); err != nil {
+		return err
+	}
+	if err = f.addTimelinePart(sheet, ws, &xlsxTimeline{
+		Name:                    opt.Name,
+		Cache:                   cacheName,
+		Caption:                 opt.Caption,
+		ShowHeader:              opt.ShowHeader,
+		ShowSelectionLabel:      opt.ShowSelectionLabel,
+		ShowTimeLevel:           opt.ShowTimeLevel,
+		ShowHorizontalScrollbar: opt.ShowHorizontalScrollbar,
+		Level:                   timelineLevels[opt.Level],
+		SelectionLevel:          timelineLevels[opt.Level],
+		Style:                   opt.Style,
+	}); err != nil {
+		return err
+	}
+	drawingID := f.countDrawings() + 1
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
+	if err = f.addDrawingTimeline(sheet, drawingXML, opt); err != nil {
+		return err
+	}
+	f.addContentTypePart(drawingID, "drawings")
+	f.addSheetNameSpace(sheet, SourceRelationship)
+	return err
+}
+
+// parseFormatTimelineSet provides a function to validate the timeline
+// options and set the default values.
+func parseFormatTimelineSet(opts *TimelineOptions) (*TimelineOptions, error) {
+	if opts == nil || opts.PivotTable == "" || opts.Field == "" {
+		return nil, ErrParameterRequired
+	}
+	opt := *opts
+	if _, _, err := CellNameToCoordinates(opt.Cell); err != nil {
+		return nil, err
+	}
+	if opt.Level == "" {
+		opt.Level = "months"
+	}
+	if _, ok := timelineLevels[opt.Level]; !ok {
+		return nil, ErrParameterInvalid
+	}
+	if opt.Name == "" {
+		opt.Name = opt.Field
+	}
+	if opt.Caption == "" {
+		opt.Caption = opt.Name
+	}
+	if opt.Style == "" {
+		opt.Style = "TimeSlicerStyleLight1"
+	}
+	if opt.Width == 0 {
+		opt.Width = 326
+	}
+	if opt.Height == 0 {
+		opt.Height = 141
+	}
+	return &opt, nil
+}
+
+// getTimelinePivotTable provides a function to find the pivot table by given
+// pivot table name in the worksheets, and returns the sheet ID of the pivot
+// table, the pivot table definition and the path of the pivot cache
+// definition part.
+func (f *File) getTimelinePivotTable(name string) (int, *xlsxPivotTableDefinition, string, error) {
+	for _, sheet := range f.GetSheets() {
+		if sheet.Type != "worksheet" {
+			continue
+		}
+		pivotTableParts, err := f.getPivotTableParts(sheet.Name)
+		if err != nil {
+			return 0, nil, "", err
+		}
+		for _, pivotTableXML := range pivotTableParts {
+			pt, err := f.pivotTableReader(pivotTableXML)
+			if err != nil {
+				return 0, nil, "", err
+			}
+			if pt.Name == name {
+				return sheet.SheetID, pt, f.getPivotCachePart(pivotTableXML), err
+			}
+		}
+	}
+	return 0, nil, "", ErrPivotTableNotExist
+}
+
+// inTimelineCacheFields provides a function to check if the given field name
+// is a database field of the pivot cache.
+func inTimelineCacheFields(pc *xlsxPivotCacheDefinition, field string) bool {
+	if pc.CacheFields == nil {
+		return false
+	}
+	for _, cacheField := range pc.CacheFields.CacheField {
+		if cacheField.Name == field && cacheField.Formula == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setPivotCacheExtID provides a function to get the pivot cache identifier
+// in the pivot cache definition extension list which is referenced by the
+// timelines. The identifier will be created by given default value if it
+// doesn't exist.
+func (f *File) setPivotCacheExtID(pivotCacheXML string, pc *xlsxPivotCacheDefinition, pivotCacheID int) (int, error) {
+	decodeExtLst := new(decodeWorksheetExt)
+	if pc.ExtLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + pc.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return pivotCacheID, err
+		}
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIPivotCacheDefinition {
+			pivotCacheDefinition := new(decodeX14PivotCacheDefinition)
+			if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(pivotCacheDefinition); err != nil && err != io.EOF {
+				return pivotCacheID, err
+			}
+			return pivotCacheDefinition.PivotCacheID, nil
+		}
+	}
+	pivotCacheDefinition, _ := xml.Marshal(xlsxX14PivotCacheDefinition{
+		XMLNSX14:     NameSpaceSpreadSheetX14.Value,
+		PivotCacheID: pivotCacheID,
+	})
+	decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxWorksheetExt{
+		URI:     ExtURIPivotCacheDefinition,
+		Content: string(pivotCacheDefinition),
+	})
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	if err != nil {
+		return pivotCacheID, err
+	}
+	pc.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	pivotCache, err := xml.Marshal(pc)
+	f.saveFileList(pivotCacheXML, pivotCache)
+	return pivotCacheID, err
+}
+
+// getTimelines provides a function to get the timelines in all timeline
+// parts of the workbook.
+func (f *File) getTimelines() ([]*xlsxTimeline, error) {
+	var (
+		timelines []*xlsxTimeline
+		err       error
+	)
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/timelines/timeline") {
+			var content *decodeTimelines
+			if content, err = f.timelinesReader(k.(string)); err != nil {
+				return false
+			}
+			timelines = append(timelines, content.Timeline...)
+		}
+		return true
+	})
+	return timelines, err
+}
+
+// timelinesReader provides a function to get the pointer to the structure
+// after deserialization of the timeline part by given path.
+func (f *File) timelinesReader(path string) (*decodeTimelines, error) {
+	content := decodeTimelines{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&content); err != nil && err != io.EOF {
+		return &content, err
+	}
+	return &content, nil
+}
+
+// getTimelineName provides a function to get the unique name of the
+// timeline in the workbook by given the name of the timeline.
+func (f *File) getTimelineName(name string) (string, error) {
+	timelines, err := f.getTimelines()
+	if err != nil {
+		return name, err
+	}
+	names := map[string]bool{}
+	for _, timeline := range timelines {
+		names[strings.ToLower(timeline.Name)] = true
+	}
+	uniqueName := name
+	for idx := 1; names[strings.ToLower(uniqueName)]; idx++ {
+		uniqueName = fmt.Sprintf("%s %d", name, idx)
+	}
+	return uniqueName, err
+}
+
+// getTimelineCacheName provides a function to get the unique defined name of
+// the timeline cache in the workbook by given the source field name.
+func (f *File) getTimelineCacheName(field string) string {
+	names := map[string]bool{}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for _, definedName := range wb.DefinedNames.DefinedName {
+			names[strings.ToLower(definedName.Name)] = true
+		}
+	}
+	name := "NativeTimeline_" + timelineCacheNameRegexp.ReplaceAllString(field, "_")
+	uniqueName := name
+	for idx := 1; names[strings.ToLower(uniqueName)]; idx++ {
+		uniqueName = name + strconv.Itoa(idx)
+	}
+	return uniqueName
+}
+
+// countTimelineParts provides a function to get the number of the parts in
+// the workbook by given path prefix.
+func (f *File) countTimelineParts(prefix string) int {
+	count := 0
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), prefix) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// addTimelineCache provides a function to create the timeline cache part,
+// and add the timeline cache reference and the defined name of the timeline
+// cache into the workbook.
+func (f *File) addTimelineCache(cache *xlsxTimelineCacheDefinition) error {
+	cacheID := f.countTimelineParts("xl/timelineCaches/timelineCache") + 1
+	cache.XMLNS, cache.XMLNSMC, cache.XMLNSX = NameSpaceSpreadSheetX15.Value, SourceRelationshipCompatibility.Value, NameSpaceSpreadSheet.Value
+	cache.Ignorable = "x"
+	content, err := xml.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	f.saveFileList("xl/timelineCaches/timelineCache"+strconv.Itoa(cacheID)+".xml", content)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipTimelineCache, "timelineCaches/timelineCache"+strconv.Itoa(cacheID)+".xml", "")
+	f.addContentTypePart(cacheID, "timelineCache")
+	wb := f.workbookReader()
+	if wb.DefinedNames == nil {
+		wb.DefinedNames = &xlsxDefinedNames{}
+	}
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, xlsxDefinedName{Name: cache.Name, Data: formulaErrorNA})
+	wb.ExtLst, err = f.appendTimelineRef(wb.ExtLst, ExtURITimelineCacheRefs, "timelineCache", rID)
+	return err
+}
+
+// addTimelinePart provides a function to add the timeline into the timeline
+// part of the worksheet, the timeline part and the timeline reference will be
+// created if the worksheet doesn't have the timelines.
+func (f *File) addTimelinePart(sheet string, ws *xlsxWorksheet, timeline *xlsxTimeline) error {
+	content := &xlsxTimelines{
+		XMLNS:     NameSpaceSpreadSheetX15.Value,
+		XMLNSMC:   SourceRelationshipCompatibility.Value,
+		XMLNSX:    NameSpaceSpreadSheet.Value,
+		Ignorable: "x",
+	}
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	var timelineXML string
+	if rels := f.relsReader(sheetRels); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipTimeline {
+				timelineXML = getRelsTargetPath(sheetRels, rel.Target)
+			}
+		}
+	}
+	if timelineXML != "" {
+		timelines, err := f.timelinesReader(timelineXML)
+		if err != nil {
+			return err
+		}
+		content.Timeline = timelines.Timeline
+	} else {
+		timelineID := f.countTimelineParts("xl/timelines/timeline") + 1
+		timelineXML = "xl/timelines/timeline" + strconv.Itoa(timelineID) + ".xml"
+		rID := f.addRels(sheetRels, SourceRelationshipTimeline, "../timelines/timeline"+strconv.Itoa(timelineID)+".xml", "")
+		f.addContentTypePart(timelineID, "timeline")
+		extLst, err := f.appendTimelineRef(ws.ExtLst, ExtURITimelineRefs, "timeline", rID)
+		if err != nil {
+			return err
+		}
+		ws.ExtLst = extLst
+	}
+	content.Timeline = append(content.Timeline, timeline)
+	output, err := xml.Marshal(content)
+	f.saveFileList(timelineXML, output)
+	return err
+}
+
+// appendTimelineRef provides a function to append the timeline or timeline
+// cache reference into the extension list by given extension URI, element
+// name prefix and relationship index.
+func (f *File) appendTimelineRef(extLst *xlsxExtLst, uri, element string, rID int) (*xlsxExtLst, error) {
+	decodeExtLst := new(decodeWorksheetExt)
+	if extLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + extLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return extLst, err
+		}
+	}
+	refs := xlsxX15TimelineRefs{
+		XMLName:  xml.Name{Local: "x15:" + element + "Refs"},
+		XMLNSX15: NameSpaceSpreadSheetX15.Value,
+	}
+	newRef := func(rID string) *xlsxX15TimelineRef {
+		return &xlsxX15TimelineRef{XMLName: xml.Name{Local: "x15:" + element + "Ref"}, RID: rID}
+	}
+	var ext *xlsxWorksheetExt
+	for _, e := range decodeExtLst.Ext {
+		if e.URI == uri {
+			ext = e
+			decodeRefs := new(decodeX15TimelineRefs)
+			if err := f.xmlNewDecoder(strings.NewReader(e.Content)).
+				Decode(decodeRefs); err != nil && err != io.EOF {
+				return extLst, err
+			}
+			for _, ref := range decodeRefs.Ref {
+				refs.Ref = append(refs.Ref, newRef(ref.RID))
+			}
+		}
+	}
+	if ext == nil {
+		ext = &xlsxWorksheetExt{URI: uri}
+		decodeExtLst.Ext = append(decodeExtLst.Ext, ext)
+	}
+	refs.Ref = append(refs.Ref, newRef("rId"+strconv.Itoa(rID)))
+	refsBytes, _ := xml.Marshal(refs)
+	ext.Content = string(refsBytes)
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	if err != nil {
+		return extLst, err
+	}
+	return &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}, err
+}
+
+// addDrawingTimeline provides a function to add the timeline graphic frame
+// by given worksheet name, drawingXML and timeline options. The graphic
+// frame is wrapped in the alternate content with a fallback shape for the
+// applications which doesn't support the timelines.
+func (f *File) addDrawingTimeline(sheet, drawingXML string, opt *TimelineOptions) error {
+	col, row, err := CellNameToCoordinates(opt.Cell)
+	if err != nil {
+		return err
+	}
+	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
+		f.positionObjectPixels(sheet, col-1, row-1, 0, 0, opt.Width, opt.Height)
+	content, cNvPrID := f.drawingParser(drawingXML)
+	twoCellAnchor := xdrCellAnchor{
+		From: &xlsxFrom{Col: colStart, Row: rowStart},
+		To:   &xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+	}
+	alternateContent := xlsxTimelineAlternateContent{
+		XMLNSMC: SourceRelationshipCompatibility.Value,
+		Choice: xlsxTimelineChoice{
+			XMLNSTSLE: NameSpaceDrawingMLTimeslicer,
+			Requires:  "tsle",
+			GraphicFrame: xlsxGraphicFrame{
+				NvGraphicFramePr: xlsxNvGraphicFramePr{CNvPr: &xlsxCNvPr{ID: cNvPrID, Name: opt.Name}},
+				Graphic: &xlsxGraphic{
+					GraphicData: &xlsxGraphicData{
+						URI:        NameSpaceDrawingMLTimeslicer,
+						Timeslicer: &xlsxTimeslicer{Name: opt.Name},
+					},
+				},
+			},
+		},
+		Fallback: xlsxChartExFallback{
+			Sp: &xdrSp{
+				NvSpPr: &xdrNvSpPr{CNvPr: &xlsxCNvPr{ID: 0, Name: ""}, CNvSpPr: &xdrCNvSpPr{TxBox: true}},
+				SpPr: &xlsxSpPr{
+					Xfrm: xlsxXfrm{
+						Off: xlsxOff{X: f.getColOffsetEMU(sheet, colStart), Y: f.getRowOffsetEMU(sheet, rowStart)},
+						Ext: xlsxExt{Cx: opt.Width * EMU, Cy: opt.Height * EMU},
+					},
+					PrstGeom: xlsxPrstGeom{Prst: "rect"},
+				},
+				TxBody: &xdrTxBody{
+					BodyPr: &aBodyPr{},
+					P: []*aP{{R: []*aR{{
+						RPr: aRPr{Lang: "en-US", Sz: 1100},
+						T:   "Timeline: Works in Excel 2013 or higher. Do not move or resize.",
+					}}}},
+				},
+			},
+		},
+	}
+	graphic, _ := xml.Marshal(alternateContent)
+	twoCellAnchor.GraphicFrame = string(graphic)
+	twoCellAnchor.ClientData = &xdrClientData{}
+	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
+	f.Drawings.Store(drawingXML, content)
+	return err
+}
@@ -0,0 +1,94 @@
+package excelize
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTimeline(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Region", "Sales"}))
+	for i := 0; i < 30; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{
+			time.Date(2021, time.Month(i%12+1), i%28+1, 0, 0, 0, 0, time.UTC), []string{"East", "West"}[i%2], i * 100,
+		}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		Name:            "PivotTable1",
+		DataRange:       "Sheet1!$A$1:$C$31",
+		PivotTableRange: "Sheet1!$E$2:$G$10",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+	}))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddTimeline("Sheet2", &TimelineOptions{
+		Cell:                    "B2",
+		PivotTable:              "PivotTable1",
+		Field:                   "Date",
+		Level:                   "quarters",
+		ShowHeader:              true,
+		ShowSelectionLabel:      true,
+		ShowTimeLevel:           true,
+		ShowHorizontalScrollbar: true,
+	}))
+	assert.NoError(t, f.AddTimeline("Sheet2", &TimelineOptions{Cell: "B12", PivotTable: "PivotTable1", Field: "Date"}))
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOptions{Name: "Sales Date", Cell: "I2", PivotTable: "PivotTable1", Field: "Date", Level: "days"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTimeline.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestAddTimeline.xlsx"))
+	assert.NoError(t, err)
+	timelines, err := f.getTimelines()
+	assert.NoError(t, err)
+	names := map[string]string{}
+	for _, timeline := range timelines {
+		names[timeline.Name] = timeline.Cache
+	}
+	assert.Equal(t, map[string]string{"Date": "NativeTimeline_Date", "Date 1": "NativeTimeline_Date1", "Sales Date": "NativeTimeline_Date2"}, names)
+	timeline, err := f.timelinesReader("xl/timelines/timeline1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, timeline.Timeline, 2)
+	assert.Equal(t, 1, timeline.Timeline[0].Level)
+	assert.True(t, timeline.Timeline[0].ShowHeader)
+	assert.Equal(t, 2, timeline.Timeline[1].Level)
+	assert.False(t, timeline.Timeline[1].ShowHeader)
+	for _, part := range []string{"xl/timelineCaches/timelineCache1.xml", "xl/timelineCaches/timelineCache2.xml", "xl/timelineCaches/timelineCache3.xml", "xl/timelines/timeline2.xml"} {
+		_, ok := f.Pkg.Load(part)
+		assert.True(t, ok, part)
+	}
+	cache := string(f.readXML("xl/timelineCaches/timelineCache1.xml"))
+	assert.True(t, strings.Contains(cache, `<pivotTable tabId="1" name="PivotTable1"></pivotTable>`))
+	assert.True(t, strings.Contains(cache, `pivotCacheId="2"`))
+	assert.True(t, strings.Contains(string(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml")), `<x14:pivotCacheDefinition xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" pivotCacheId="2"></x14:pivotCacheDefinition>`))
+	wb := f.workbookReader()
+	assert.Len(t, wb.DefinedNames.DefinedName, 3)
+	assert.Equal(t, formulaErrorNA, wb.DefinedNames.DefinedName[0].Data)
+	assert.Equal(t, 3, strings.Count(wb.ExtLst.Ext, "<x15:timelineCacheRef "))
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x15:timelineRef "))
+	assert.True(t, strings.Contains(ws.ExtLst.Ext, ExtURITimelineRefs))
+	drawing, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.Len(t, drawing.TwoCellAnchor, 2)
+
+	// Test add timeline with invalid options.
+	assert.EqualError(t, f.AddTimeline("Sheet1", nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{PivotTable: "PivotTable1"}), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{Cell: "A", PivotTable: "PivotTable1", Field: "Date"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{Cell: "A1", PivotTable: "PivotTable1", Field: "Date", Level: "weeks"}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{Cell: "A1", PivotTable: "PivotTable2", Field: "Date"}), ErrPivotTableNotExist.Error())
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{Cell: "A1", PivotTable: "PivotTable1", Field: "Time"}), ErrTimelineField.Error())
+	assert.EqualError(t, f.AddTimeline("SheetN", &TimelineOptions{Cell: "A1", PivotTable: "PivotTable1", Field: "Date"}), "sheet SheetN is not exist")
+	// Test add timeline on the protected worksheet.
+	assert.NoError(t, f.ProtectSheet("Sheet1", &FormatSheetProtection{EditObjects: false}))
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{Cell: "A1", PivotTable: "PivotTable1", Field: "Date"}), ErrProtectedObjects.Error())
+	// Test add timeline with unsupported charset.
+	f = NewFile()
+	f.Pkg.Store("xl/timelines/timeline1.xml", MacintoshCyrillicCharset)
+	_, err = f.getTimelineName("Date")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
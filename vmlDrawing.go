@@ -42,14 +42,15 @@ type xlsxIDmap struct {
 
 // xlsxShape directly maps the shape element.
 type xlsxShape struct {
-	XMLName     xml.Name `xml:"v:shape"`
-	ID          string   `xml:"id,attr"`
-	Type        string   `xml:"type,attr"`
-	Style       string   `xml:"style,attr"`
-	Fillcolor   string   `xml:"fillcolor,attr"`
-	Insetmode   string   `xml:"urn:schemas-microsoft-com:office:office insetmode,attr,omitempty"`
-	Strokecolor string   `xml:"strokecolor,attr,omitempty"`
-	Val         string   `xml:",innerxml"`
+	XMLName      xml.Name `xml:"v:shape"`
+	ID           string   `xml:"id,attr"`
+	Type         string   `xml:"type,attr"`
+	Style        string   `xml:"style,attr"`
+	Fillcolor    string   `xml:"fillcolor,attr"`
+	Insetmode    string   `xml:"urn:schemas-microsoft-com:office:office insetmode,attr,omitempty"`
+	Strokecolor  string   `xml:"strokecolor,attr,omitempty"`
+	Strokeweight string   `xml:"strokeweight,attr,omitempty"`
+	Val          string   `xml:",innerxml"`
 }
 
 // xlsxShapetype directly maps the shapetype element.
@@ -125,6 +126,16 @@ type xClientData struct {
 	Column        int    `xml:"x:Column"`
 }
 
+// decodeVmlShapelayout defines the structure used to parse the drawing block
+// IDs in the shapelayout element of the VML drawing part.
+type decodeVmlShapelayout struct {
+	Shapelayout struct {
+		IDmap struct {
+			Data string `xml:"data,attr"`
+		} `xml:"idmap"`
+	} `xml:"shapelayout"`
+}
+
 // decodeVmlDrawing defines the structure used to parse the file
 // xl/drawings/vmlDrawing%d.vml.
 type decodeVmlDrawing struct {
@@ -133,7 +144,14 @@ type decodeVmlDrawing struct {
 
 // decodeShape defines the structure used to parse the particular shape element.
 type decodeShape struct {
-	Val string `xml:",innerxml"`
+	ID           string `xml:"id,attr"`
+	Type         string `xml:"type,attr"`
+	Style        string `xml:"style,attr"`
+	Fillcolor    string `xml:"fillcolor,attr"`
+	Insetmode    string `xml:"urn:schemas-microsoft-com:office:office insetmode,attr,omitempty"`
+	Strokecolor  string `xml:"strokecolor,attr,omitempty"`
+	Strokeweight string `xml:"strokeweight,attr,omitempty"`
+	Val          string `xml:",innerxml"`
 }
 
 // encodeShape defines the structure used to re-serialization shape element.
@@ -144,3 +162,75 @@ type encodeShape struct {
 	Textbox    *vTextbox    `xml:"v:textbox"`
 	ClientData *xClientData `xml:"x:ClientData"`
 }
+
+// vmlHeaderFooter directly maps the root element in the file
+// xl/drawings/vmlDrawingHF%d.vml. This part contains the pictures in the
+// headers and footers of the sheet.
+type vmlHeaderFooter struct {
+	XMLName     xml.Name                `xml:"xml"`
+	XMLNSv      string                  `xml:"xmlns:v,attr"`
+	XMLNSo      string                  `xml:"xmlns:o,attr"`
+	XMLNSx      string                  `xml:"xmlns:x,attr"`
+	Shapelayout *xlsxShapelayout        `xml:"o:shapelayout"`
+	Shapetype   *vmlPictureShapetype    `xml:"v:shapetype"`
+	Shape       []*vmlHeaderFooterShape `xml:"v:shape"`
+}
+
+// vmlPictureShapetype directly maps the shapetype element of the picture
+// frame shape type.
+type vmlPictureShapetype struct {
+	ID             string `xml:"id,attr"`
+	Coordsize      string `xml:"coordsize,attr"`
+	Spt            int    `xml:"o:spt,attr"`
+	Preferrelative string `xml:"o:preferrelative,attr"`
+	Path           string `xml:"path,attr"`
+	Filled         string `xml:"filled,attr"`
+	Stroked        string `xml:"stroked,attr"`
+	Val            string `xml:",innerxml"`
+}
+
+// vmlHeaderFooterShape directly maps the shape element of a picture in the
+// header or footer, the ID of the shape specifies the section of the header
+// or footer, such as "LH", "CF" or "RHFIRST".
+type vmlHeaderFooterShape struct {
+	ID        string      `xml:"id,attr"`
+	Spid      string      `xml:"o:spid,attr"`
+	Type      string      `xml:"type,attr"`
+	Style     string      `xml:"style,attr"`
+	ImageData *vImageData `xml:"v:imagedata"`
+	Lock      *oLock      `xml:"o:lock"`
+}
+
+// vImageData directly maps the v:imagedata element. This element specifies
+// the relationship to the picture.
+type vImageData struct {
+	RelID string `xml:"o:relid,attr"`
+	Title string `xml:"o:title,attr"`
+}
+
+// oLock directly maps the o:lock element.
+type oLock struct {
+	Ext      string `xml:"v:ext,attr"`
+	Rotation string `xml:"rotation,attr,omitempty"`
+}
+
+// decodeVmlHeaderFooter defines the structure used to parse the file
+// xl/drawings/vmlDrawingHF%d.vml.
+type decodeVmlHeaderFooter struct {
+	Shape []*decodeHeaderFooterShape `xml:"urn:schemas-microsoft-com:vml shape"`
+}
+
+// decodeHeaderFooterShape defines the structure used to parse the shape
+// element of a picture in the header or footer.
+type decodeHeaderFooterShape struct {
+	ID        string            `xml:"id,attr"`
+	Style     string            `xml:"style,attr"`
+	ImageData *decodeVImageData `xml:"urn:schemas-microsoft-com:vml imagedata"`
+}
+
+// decodeVImageData defines the structure used to parse the v:imagedata
+// element.
+type decodeVImageData struct {
+	RelID string `xml:"urn:schemas-microsoft-com:office:office relid,attr"`
+	Title string `xml:"urn:schemas-microsoft-com:office:office title,attr"`
+}
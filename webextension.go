@@ -0,0 +1,253 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// defaultXMLPathTaskpanes defined the default path of the web extension task
+// panes part.
+const defaultXMLPathTaskpanes = "xl/webextensions/taskpanes.xml"
+
+// getTaskpanesPath provides a function to get the path of the web extension
+// task panes part from the package relationships.
+func (f *File) getTaskpanesPath() string {
+	if rels := f.relsReader("_rels/.rels"); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipWebExtensionTaskPanes {
+				return strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+	return ""
+}
+
+// taskpanesReader provides a function to get the pointer to the structure
+// after deserialization of the web extension task panes part.
+func (f *File) taskpanesReader(taskpanesPath string) (*decodeWetpTaskpanes, error) {
+	taskpanes := new(decodeWetpTaskpanes)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(taskpanesPath)))).
+		Decode(taskpanes); err != nil && err != io.EOF {
+		return taskpanes, err
+	}
+	return taskpanes, nil
+}
+
+// GetWebExtensions provides a function to get the Office Add-ins (web
+// extensions) hosted by the task panes in the workbook. The web extension
+// parts will be preserved on saving the workbook. For example:
+//
+//    exts, err := f.GetWebExtensions()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, ext := range exts {
+//        fmt.Println(ext.AddinID, ext.Store, ext.Properties)
+//    }
+//
+func (f *File) GetWebExtensions() ([]WebExtension, error) {
+	var exts []WebExtension
+	taskpanesPath := f.getTaskpanesPath()
+	if taskpanesPath == "" {
+		return exts, nil
+	}
+	taskpanes, err := f.taskpanesReader(taskpanesPath)
+	if err != nil {
+		return exts, err
+	}
+	relsPath := path.Join(path.Dir(taskpanesPath), "_rels", path.Base(taskpanesPath)+".rels")
+	rels := f.relsReader(relsPath)
+	for _, taskpane := range taskpanes.Taskpane {
+		ext := WebExtension{
+			DockState: taskpane.DockState,
+			Visible:   taskpane.Visibility,
+			Width:     taskpane.Width,
+			Row:       taskpane.Row,
+			Locked:    taskpane.Locked,
+		}
+		if taskpane.WebExtensionRef != nil && rels != nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID != taskpane.WebExtensionRef.RID {
+					continue
+				}
+				target := path.Join(path.Dir(taskpanesPath), rel.Target)
+				if strings.HasPrefix(rel.Target, "/") {
+					target = strings.TrimPrefix(rel.Target, "/")
+				}
+				if err = f.getWebExtension(target, &ext); err != nil {
+					return exts, err
+				}
+			}
+		}
+		exts = append(exts, ext)
+	}
+	return exts, err
+}
+
+// getWebExtension provides a function to read the settings of the web
+// extension by given part path.
+func (f *File) getWebExtension(extPath string, ext *WebExtension) error {
+	webExt := new(decodeWeWebExtension)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(extPath)))).
+		Decode(webExt); err != nil && err != io.EOF {
+		return err
+	}
+	ext.ID = webExt.ID
+	if webExt.Reference != nil {
+		ext.AddinID = webExt.Reference.ID
+		ext.Version = webExt.Reference.Version
+		ext.Store = webExt.Reference.Store
+		ext.StoreType = webExt.Reference.StoreType
+	}
+	if webExt.Properties != nil {
+		ext.Properties = make(map[string]string, len(webExt.Properties.Property))
+		for _, p := range webExt.Properties.Property {
+			ext.Properties[p.Name] = p.Value
+		}
+	}
+	return nil
+}
+
+// newGUID provides a function to generate a random GUID in registry format.
+func newGUID() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(fmt.Sprintf("{%x-%x-%x-%x-%x}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), err
+}
+
+// AddWebExtension provides a function to add an Office Add-in (web
+// extension) hosted by a task pane into the workbook. The AddinID is the
+// identifier of the add-in in the store. The default store type is "OMEX"
+// (the Office Store), the default version is "1.0.0.0", the default dock
+// state is "right" and the default task pane width is 350. The Properties
+// are name and value pairs which persisted by the add-in, the values are
+// usually JSON encoded. For example, add a visible task pane for the add-in
+// wa104379955 from the en-US Office Store:
+//
+//    err := f.AddWebExtension(&excelize.WebExtension{
+//        AddinID:    "wa104379955",
+//        Store:      "en-US",
+//        Visible:    true,
+//        Properties: map[string]string{"Office.AutoShowTaskpaneWithDocument": "true"},
+//    })
+//
+func (f *File) AddWebExtension(ext *WebExtension) error {
+	if ext == nil || ext.AddinID == "" {
+		return ErrParameterRequired
+	}
+	opt := *ext
+	if opt.Version == "" {
+		opt.Version = "1.0.0.0"
+	}
+	if opt.StoreType == "" {
+		opt.StoreType = "OMEX"
+	}
+	if opt.DockState == "" {
+		opt.DockState = "right"
+	}
+	if opt.Width == 0 {
+		opt.Width = 350
+	}
+	var err error
+	if opt.ID == "" {
+		if opt.ID, err = newGUID(); err != nil {
+			return err
+		}
+	}
+	taskpanesPath := f.getTaskpanesPath()
+	if taskpanesPath == "" {
+		taskpanesPath = defaultXMLPathTaskpanes
+		f.addRels("_rels/.rels", SourceRelationshipWebExtensionTaskPanes, taskpanesPath, "")
+		f.setContentTypes("/"+taskpanesPath, ContentTypeWebExtensionTaskPanes)
+	}
+	taskpanes, err := f.taskpanesReader(taskpanesPath)
+	if err != nil {
+		return err
+	}
+	var extCount int
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/webextensions/webextension") {
+			extCount++
+		}
+		return true
+	})
+	extName := fmt.Sprintf("webextension%d.xml", extCount+1)
+	extPath := path.Join(path.Dir(taskpanesPath), extName)
+	for _, ok := f.Pkg.Load(extPath); ok; _, ok = f.Pkg.Load(extPath) {
+		extCount++
+		extName = fmt.Sprintf("webextension%d.xml", extCount+1)
+		extPath = path.Join(path.Dir(taskpanesPath), extName)
+	}
+	relsPath := path.Join(path.Dir(taskpanesPath), "_rels", path.Base(taskpanesPath)+".rels")
+	rID := f.addRels(relsPath, SourceRelationshipWebExtension, extName, "")
+	f.setContentTypes("/"+extPath, ContentTypeWebExtension)
+	f.addWebExtension(extPath, &opt)
+
+	content := xlsxWetpTaskpanes{XMLNSWetp: NameSpaceWebExtensionTaskPanes}
+	for _, t := range taskpanes.Taskpane {
+		taskpane := &xlsxWetpTaskpane{DockState: t.DockState, Visibility: t.Visibility, Width: t.Width, Row: t.Row, Locked: t.Locked}
+		if t.WebExtensionRef != nil {
+			taskpane.WebExtensionRef = &xlsxWetpWebExtensionRef{XMLNSR: SourceRelationship.Value, RID: t.WebExtensionRef.RID}
+		}
+		content.Taskpane = append(content.Taskpane, taskpane)
+	}
+	content.Taskpane = append(content.Taskpane, &xlsxWetpTaskpane{
+		DockState:       opt.DockState,
+		Visibility:      opt.Visible,
+		Width:           opt.Width,
+		Row:             opt.Row,
+		Locked:          opt.Locked,
+		WebExtensionRef: &xlsxWetpWebExtensionRef{XMLNSR: SourceRelationship.Value, RID: fmt.Sprintf("rId%d", rID)},
+	})
+	output, err := xml.Marshal(content)
+	f.saveFileList(taskpanesPath, output)
+	return err
+}
+
+// addWebExtension provides a function to create the web extension part by
+// given part path and settings.
+func (f *File) addWebExtension(extPath string, opt *WebExtension) {
+	webExt := xlsxWeWebExtension{
+		XMLNSWe: NameSpaceWebExtension,
+		ID:      opt.ID,
+		Reference: &xlsxWeReference{
+			ID:        opt.AddinID,
+			Version:   opt.Version,
+			Store:     opt.Store,
+			StoreType: opt.StoreType,
+		},
+		AlternateReferences: &xlsxInnerXML{},
+		Properties:          &xlsxWeProperties{},
+		Bindings:            &xlsxInnerXML{},
+		Snapshot:            &xlsxWeSnapshot{XMLNSR: SourceRelationship.Value},
+	}
+	names := make([]string, 0, len(opt.Properties))
+	for name := range opt.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		webExt.Properties.Property = append(webExt.Properties.Property, &xlsxWeProperty{Name: name, Value: opt.Properties[name]})
+	}
+	output, _ := xml.Marshal(webExt)
+	f.saveFileList(extPath, output)
+}
@@ -0,0 +1,98 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebExtension(t *testing.T) {
+	f := NewFile()
+	exts, err := f.GetWebExtensions()
+	assert.NoError(t, err)
+	assert.Empty(t, exts)
+
+	assert.EqualError(t, f.AddWebExtension(nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.AddWebExtension(&WebExtension{}), ErrParameterRequired.Error())
+	assert.NoError(t, f.AddWebExtension(&WebExtension{
+		AddinID:    "wa104379955",
+		Store:      "en-US",
+		Visible:    true,
+		Properties: map[string]string{"Office.AutoShowTaskpaneWithDocument": "true", "settings": `{"a":1}`},
+	}))
+	assert.NoError(t, f.AddWebExtension(&WebExtension{
+		ID:        "{6A2B4E30-5D3A-4B4B-9C09-2B8E5B6C1A11}",
+		AddinID:   "wa200000000",
+		Version:   "2.0.0.0",
+		Store:     "en-US",
+		DockState: "left",
+		Width:     400,
+		Row:       1,
+		Locked:    true,
+	}))
+	file := filepath.Join("test", "TestWebExtension.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	exts, err = f.GetWebExtensions()
+	assert.NoError(t, err)
+	if assert.Len(t, exts, 2) {
+		assert.Equal(t, "wa104379955", exts[0].AddinID)
+		assert.Equal(t, "1.0.0.0", exts[0].Version)
+		assert.Equal(t, "OMEX", exts[0].StoreType)
+		assert.Equal(t, "right", exts[0].DockState)
+		assert.Equal(t, 350.0, exts[0].Width)
+		assert.True(t, exts[0].Visible)
+		assert.Len(t, exts[0].ID, 38)
+		assert.Equal(t, map[string]string{"Office.AutoShowTaskpaneWithDocument": "true", "settings": `{"a":1}`}, exts[0].Properties)
+		assert.Equal(t, WebExtension{
+			ID:         "{6A2B4E30-5D3A-4B4B-9C09-2B8E5B6C1A11}",
+			AddinID:    "wa200000000",
+			Version:    "2.0.0.0",
+			Store:      "en-US",
+			StoreType:  "OMEX",
+			Properties: map[string]string{},
+			DockState:  "left",
+			Width:      400,
+			Row:        1,
+			Locked:     true,
+		}, exts[1])
+	}
+	// Test add web extension to the workbook with existing task panes.
+	assert.NoError(t, f.AddWebExtension(&WebExtension{AddinID: "wa300000000"}))
+	exts, err = f.GetWebExtensions()
+	assert.NoError(t, err)
+	assert.Len(t, exts, 3)
+	assert.Equal(t, "wa300000000", exts[2].AddinID)
+	// Test add web extension to the workbook with the gap in the part names.
+	content, ok := f.Pkg.Load("xl/webextensions/webextension2.xml")
+	assert.True(t, ok)
+	f.Pkg.Store("xl/webextensions/webextension4.xml", content)
+	f.Pkg.Delete("xl/webextensions/webextension2.xml")
+	rels := f.relsReader("xl/webextensions/_rels/taskpanes.xml.rels")
+	for idx := range rels.Relationships {
+		if rels.Relationships[idx].Target == "webextension2.xml" {
+			rels.Relationships[idx].Target = "webextension4.xml"
+		}
+	}
+	assert.NoError(t, f.AddWebExtension(&WebExtension{AddinID: "wa400000000"}))
+	_, ok = f.Pkg.Load("xl/webextensions/webextension5.xml")
+	assert.True(t, ok)
+	exts, err = f.GetWebExtensions()
+	assert.NoError(t, err)
+	if assert.Len(t, exts, 4) {
+		assert.Equal(t, "wa200000000", exts[1].AddinID)
+		assert.Equal(t, "wa400000000", exts[3].AddinID)
+	}
+
+	// Test get and add web extensions with unsupported charset.
+	f.Pkg.Store("xl/webextensions/webextension1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetWebExtensions()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Store(defaultXMLPathTaskpanes, MacintoshCyrillicCharset)
+	_, err = f.GetWebExtensions()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddWebExtension(&WebExtension{AddinID: "wa300000000"}), "XML syntax error on line 1: invalid UTF-8")
+}
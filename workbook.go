@@ -0,0 +1,164 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "strconv"
+
+var (
+	// supportedCalcMode defined supported calculation mode.
+	supportedCalcMode = []string{"manual", "auto", "autoNoTable"}
+	// supportedRefMode defined supported reference mode of the formulas.
+	supportedRefMode = []string{"A1", "R1C1"}
+)
+
+// CalcPropsOptions defines the collection of properties the application uses
+// to record calculation status and details.
+type CalcPropsOptions struct {
+	CalcID                *uint
+	CalcMode              *string
+	FullCalcOnLoad        *bool
+	RefMode               *string
+	Iterate               *bool
+	IterateCount          *uint
+	IterateDelta          *float64
+	FullPrecision         *bool
+	CalcCompleted         *bool
+	CalcOnSave            *bool
+	ConcurrentCalc        *bool
+	ConcurrentManualCount *uint
+	ForceFullCalc         *bool
+}
+
+// SetCalcProps provides a function to set calculation properties. The
+// optional value of CalcMode are manual, auto and autoNoTable, the optional
+// value of RefMode are A1 and R1C1. Set the FullPrecision to false to enable
+// the "Set precision as displayed" option of the workbook, the numeric
+// values of the cells will be rounded to the precision of their number
+// formats when they are referenced by the formulas in the CalcCellValue
+// function, and so are the calculated results. Note that the stored values
+// of the cells will not be changed. For example, calculate the formulas with
+// the precision as displayed:
+//
+//    fullPrecision := false
+//    err := f.SetCalcProps(&excelize.CalcPropsOptions{
+//        FullPrecision: &fullPrecision,
+//    })
+//
+func (f *File) SetCalcProps(opts *CalcPropsOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.CalcMode != nil && inStrSlice(supportedCalcMode, *opts.CalcMode) == -1 {
+		return newInvalidOptionalValue("CalcMode", *opts.CalcMode, supportedCalcMode)
+	}
+	if opts.RefMode != nil && inStrSlice(supportedRefMode, *opts.RefMode) == -1 {
+		return newInvalidOptionalValue("RefMode", *opts.RefMode, supportedRefMode)
+	}
+	wb := f.workbookReader()
+	if wb.CalcPr == nil {
+		wb.CalcPr = new(xlsxCalcPr)
+	}
+	if opts.CalcID != nil {
+		wb.CalcPr.CalcID = strconv.FormatUint(uint64(*opts.CalcID), 10)
+	}
+	if opts.CalcMode != nil {
+		wb.CalcPr.CalcMode = *opts.CalcMode
+	}
+	if opts.FullCalcOnLoad != nil {
+		wb.CalcPr.FullCalcOnLoad = *opts.FullCalcOnLoad
+	}
+	if opts.RefMode != nil {
+		wb.CalcPr.RefMode = *opts.RefMode
+	}
+	if opts.Iterate != nil {
+		wb.CalcPr.Iterate = *opts.Iterate
+	}
+	if opts.IterateCount != nil {
+		wb.CalcPr.IterateCount = int(*opts.IterateCount)
+	}
+	if opts.IterateDelta != nil {
+		wb.CalcPr.IterateDelta = *opts.IterateDelta
+	}
+	if opts.FullPrecision != nil {
+		wb.CalcPr.FullPrecision = boolPtr(*opts.FullPrecision)
+	}
+	if opts.CalcCompleted != nil {
+		wb.CalcPr.CalcCompleted = boolPtr(*opts.CalcCompleted)
+	}
+	if opts.CalcOnSave != nil {
+		wb.CalcPr.CalcOnSave = boolPtr(*opts.CalcOnSave)
+	}
+	if opts.ConcurrentCalc != nil {
+		wb.CalcPr.ConcurrentCalc = boolPtr(*opts.ConcurrentCalc)
+	}
+	if opts.ConcurrentManualCount != nil {
+		wb.CalcPr.ConcurrentManualCount = int(*opts.ConcurrentManualCount)
+	}
+	if opts.ForceFullCalc != nil {
+		wb.CalcPr.ForceFullCalc = *opts.ForceFullCalc
+	}
+	return nil
+}
+
+// GetCalcProps provides a function to get calculation properties, the
+// properties which are not specified in the workbook will be returned with
+// their default values.
+func (f *File) GetCalcProps() (CalcPropsOptions, error) {
+	var (
+		wb    = f.workbookReader()
+		opts  = CalcPropsOptions{}
+		calc  = wb.CalcPr
+		mode  = "auto"
+		ref   = "A1"
+		count = uint(100)
+		delta = 0.001
+	)
+	if calc == nil {
+		calc = new(xlsxCalcPr)
+	}
+	if calc.CalcID != "" {
+		if id, err := strconv.ParseUint(calc.CalcID, 10, 32); err == nil {
+			opts.CalcID = uintPtr(uint(id))
+		}
+	}
+	if calc.CalcMode != "" {
+		mode = calc.CalcMode
+	}
+	if calc.RefMode != "" {
+		ref = calc.RefMode
+	}
+	if calc.IterateCount != 0 {
+		count = uint(calc.IterateCount)
+	}
+	if calc.IterateDelta != 0 {
+		delta = calc.IterateDelta
+	}
+	opts.CalcMode, opts.RefMode = stringPtr(mode), stringPtr(ref)
+	opts.IterateCount, opts.IterateDelta = uintPtr(count), float64Ptr(delta)
+	opts.FullCalcOnLoad, opts.Iterate = boolPtr(calc.FullCalcOnLoad), boolPtr(calc.Iterate)
+	opts.ForceFullCalc = boolPtr(calc.ForceFullCalc)
+	opts.FullPrecision = boolPtr(calc.FullPrecision == nil || *calc.FullPrecision)
+	opts.CalcCompleted = boolPtr(calc.CalcCompleted == nil || *calc.CalcCompleted)
+	opts.CalcOnSave = boolPtr(calc.CalcOnSave == nil || *calc.CalcOnSave)
+	opts.ConcurrentCalc = boolPtr(calc.ConcurrentCalc == nil || *calc.ConcurrentCalc)
+	if calc.ConcurrentManualCount != 0 {
+		opts.ConcurrentManualCount = uintPtr(uint(calc.ConcurrentManualCount))
+	}
+	return opts, nil
+}
+
+// isPrecisionAsDisplayed provides a function to check if the "Set precision
+// as displayed" option of the workbook is enabled.
+func (f *File) isPrecisionAsDisplayed() bool {
+	wb := f.workbookReader()
+	return wb.CalcPr != nil && wb.CalcPr.FullPrecision != nil && !*wb.CalcPr.FullPrecision
+}
@@ -0,0 +1,72 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCalcProps(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCalcProps(nil))
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{
+		CalcID:                uintPtr(122211),
+		CalcMode:              stringPtr("manual"),
+		FullCalcOnLoad:        boolPtr(true),
+		RefMode:               stringPtr("R1C1"),
+		Iterate:               boolPtr(true),
+		IterateCount:          uintPtr(10),
+		IterateDelta:          float64Ptr(0.0001),
+		FullPrecision:         boolPtr(false),
+		CalcCompleted:         boolPtr(false),
+		CalcOnSave:            boolPtr(false),
+		ConcurrentCalc:        boolPtr(false),
+		ConcurrentManualCount: uintPtr(4),
+		ForceFullCalc:         boolPtr(true),
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCalcProps.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestSetCalcProps.xlsx"))
+	assert.NoError(t, err)
+	opts, err := f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, CalcPropsOptions{
+		CalcID:                uintPtr(122211),
+		CalcMode:              stringPtr("manual"),
+		FullCalcOnLoad:        boolPtr(true),
+		RefMode:               stringPtr("R1C1"),
+		Iterate:               boolPtr(true),
+		IterateCount:          uintPtr(10),
+		IterateDelta:          float64Ptr(0.0001),
+		FullPrecision:         boolPtr(false),
+		CalcCompleted:         boolPtr(false),
+		CalcOnSave:            boolPtr(false),
+		ConcurrentCalc:        boolPtr(false),
+		ConcurrentManualCount: uintPtr(4),
+		ForceFullCalc:         boolPtr(true),
+	}, opts)
+
+	// Test get calculation properties with default values.
+	f = NewFile()
+	f.WorkBook.CalcPr = nil
+	opts, err = f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, CalcPropsOptions{
+		CalcMode:       stringPtr("auto"),
+		FullCalcOnLoad: boolPtr(false),
+		RefMode:        stringPtr("A1"),
+		Iterate:        boolPtr(false),
+		IterateCount:   uintPtr(100),
+		IterateDelta:   float64Ptr(0.001),
+		FullPrecision:  boolPtr(true),
+		CalcCompleted:  boolPtr(true),
+		CalcOnSave:     boolPtr(true),
+		ConcurrentCalc: boolPtr(true),
+		ForceFullCalc:  boolPtr(false),
+	}, opts)
+
+	// Test set calculation properties with invalid options.
+	assert.EqualError(t, f.SetCalcProps(&CalcPropsOptions{CalcMode: stringPtr("unknown")}), `invalid CalcMode value "unknown", acceptable value should be one of manual, auto, autoNoTable`)
+	assert.EqualError(t, f.SetCalcProps(&CalcPropsOptions{RefMode: stringPtr("unknown")}), `invalid RefMode value "unknown", acceptable value should be one of A1, R1C1`)
+}
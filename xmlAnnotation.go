@@ -0,0 +1,77 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// xlsxAnnotations directly maps the root element of the custom XML part
+// which stores the cell annotations in the namespace
+// http://schemas.excelize.org/2021/cellAnnotations.
+type xlsxAnnotations struct {
+	XMLName    xml.Name          `xml:"annotations"`
+	XMLNS      string            `xml:"xmlns,attr"`
+	Annotation []*xlsxAnnotation `xml:"annotation"`
+}
+
+// xlsxAnnotation directly maps the annotation element. This element
+// specifies the provenance metadata of the range in the worksheet.
+type xlsxAnnotation struct {
+	Sheet    string                    `xml:"sheet,attr"`
+	Ref      string                    `xml:"ref,attr"`
+	Source   string                    `xml:"source,attr,omitempty"`
+	Time     string                    `xml:"time,attr,omitempty"`
+	Property []*xlsxAnnotationProperty `xml:"property"`
+}
+
+// xlsxAnnotationProperty directly maps the property element. This element
+// specifies a name and value pair of the annotation.
+type xlsxAnnotationProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// xlsxDatastoreItem directly maps the datastoreItem element in the namespace
+// http://schemas.openxmlformats.org/officeDocument/2006/customXml. This
+// element specifies the properties of the custom XML data part.
+type xlsxDatastoreItem struct {
+	XMLName    xml.Name                 `xml:"ds:datastoreItem"`
+	XMLNSDs    string                   `xml:"xmlns:ds,attr"`
+	ItemID     string                   `xml:"ds:itemID,attr"`
+	SchemaRefs *xlsxDatastoreSchemaRefs `xml:"ds:schemaRefs"`
+}
+
+// xlsxDatastoreSchemaRefs directly maps the schemaRefs element.
+type xlsxDatastoreSchemaRefs struct {
+	SchemaRef []*xlsxDatastoreSchemaRef `xml:"ds:schemaRef"`
+}
+
+// xlsxDatastoreSchemaRef directly maps the schemaRef element. This element
+// specifies the namespace of the XML schema in the custom XML data part.
+type xlsxDatastoreSchemaRef struct {
+	URI string `xml:"ds:uri,attr"`
+}
+
+// Annotation directly maps the provenance metadata of the cells, which is
+// stored in the workbook by the SetCellAnnotation function. The Range is the
+// cell or range reference of the annotation in the worksheet, such as A1 or
+// A1:B10, the Source is the origin of the data, such as a query or a file
+// name, the Time is the time when the data was produced, and the Properties
+// are arbitrary name and value pairs.
+type Annotation struct {
+	Range      string
+	Source     string
+	Time       time.Time
+	Properties map[string]string
+}
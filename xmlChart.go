@@ -22,12 +22,22 @@ type xlsxChartSpace struct {
 	Date1904       *attrValBool    `xml:"date1904"`
 	Lang           *attrValString  `xml:"lang"`
 	RoundedCorners *attrValBool    `xml:"roundedCorners"`
+	PivotSource    *cPivotSource   `xml:"pivotSource"`
 	Chart          cChart          `xml:"chart"`
 	SpPr           *cSpPr          `xml:"spPr"`
 	TxPr           *cTxPr          `xml:"txPr"`
 	PrintSettings  *cPrintSettings `xml:"printSettings"`
 }
 
+// cPivotSource directly maps the pivotSource element. This element specifies
+// the source pivot table of the pivot chart, the name is in the format of
+// [workbook]sheet!pivot table name, and the format ID specifies the chart
+// format of the pivot table.
+type cPivotSource struct {
+	Name  string      `xml:"name"`
+	FmtID *attrValInt `xml:"fmtId"`
+}
+
 // cThicknessSpPr directly maps the element that specifies the thickness of
 // the walls or floor as a percentage of the largest dimension of the plot
 // volume and SpPr element.
@@ -105,7 +115,7 @@ type aBodyPr struct {
 // paragraph of content in the document.
 type aP struct {
 	PPr        *aPPr        `xml:"a:pPr"`
-	R          *aR          `xml:"a:r"`
+	R          []*aR        `xml:"a:r"`
 	EndParaRPr *aEndParaRPr `xml:"a:endParaRPr"`
 }
 
@@ -249,13 +259,14 @@ type aContourClr struct {
 // shapes and text. The line allows for the specifying of many different types
 // of outlines including even line dashes and bevels.
 type aLn struct {
-	Algn      string      `xml:"algn,attr,omitempty"`
-	Cap       string      `xml:"cap,attr,omitempty"`
-	Cmpd      string      `xml:"cmpd,attr,omitempty"`
-	W         int         `xml:"w,attr,omitempty"`
-	NoFill    string      `xml:"a:noFill,omitempty"`
-	Round     string      `xml:"a:round,omitempty"`
-	SolidFill *aSolidFill `xml:"a:solidFill"`
+	Algn      string         `xml:"algn,attr,omitempty"`
+	Cap       string         `xml:"cap,attr,omitempty"`
+	Cmpd      string         `xml:"cmpd,attr,omitempty"`
+	W         int            `xml:"w,attr,omitempty"`
+	NoFill    string         `xml:"a:noFill,omitempty"`
+	Round     string         `xml:"a:round,omitempty"`
+	SolidFill *aSolidFill    `xml:"a:solidFill"`
+	PrstDash  *attrValString `xml:"a:prstDash"`
 }
 
 // cTxPr (Text Properties) directly maps the txPr element. This element
@@ -301,25 +312,36 @@ type cView3D struct {
 // cPlotArea directly maps the plotArea element. This element specifies the
 // plot area of the chart.
 type cPlotArea struct {
-	Layout         *string  `xml:"layout"`
-	AreaChart      *cCharts `xml:"areaChart"`
-	Area3DChart    *cCharts `xml:"area3DChart"`
-	BarChart       *cCharts `xml:"barChart"`
-	Bar3DChart     *cCharts `xml:"bar3DChart"`
-	BubbleChart    *cCharts `xml:"bubbleChart"`
-	DoughnutChart  *cCharts `xml:"doughnutChart"`
-	LineChart      *cCharts `xml:"lineChart"`
-	PieChart       *cCharts `xml:"pieChart"`
-	Pie3DChart     *cCharts `xml:"pie3DChart"`
-	OfPieChart     *cCharts `xml:"ofPieChart"`
-	RadarChart     *cCharts `xml:"radarChart"`
-	ScatterChart   *cCharts `xml:"scatterChart"`
-	Surface3DChart *cCharts `xml:"surface3DChart"`
-	SurfaceChart   *cCharts `xml:"surfaceChart"`
-	CatAx          []*cAxs  `xml:"catAx"`
-	ValAx          []*cAxs  `xml:"valAx"`
-	SerAx          []*cAxs  `xml:"serAx"`
-	SpPr           *cSpPr   `xml:"spPr"`
+	Layout          *string        `xml:"layout"`
+	AreaChart       *cCharts       `xml:"areaChart"`
+	Area3DChart     *cCharts       `xml:"area3DChart"`
+	BarChart        *cCharts       `xml:"barChart"`
+	Bar3DChart      *cCharts       `xml:"bar3DChart"`
+	BubbleChart     *cCharts       `xml:"bubbleChart"`
+	DoughnutChart   *cCharts       `xml:"doughnutChart"`
+	LineChart       *cCharts       `xml:"lineChart"`
+	PieChart        *cCharts       `xml:"pieChart"`
+	Pie3DChart      *cCharts       `xml:"pie3DChart"`
+	OfPieChart      *cCharts       `xml:"ofPieChart"`
+	RadarChart      *cCharts       `xml:"radarChart"`
+	ScatterChart    *cCharts       `xml:"scatterChart"`
+	StockChart      *cCharts       `xml:"stockChart"`
+	Surface3DChart  *cCharts       `xml:"surface3DChart"`
+	SurfaceChart    *cCharts       `xml:"surfaceChart"`
+	SecondaryCharts []*cChartGroup `xml:",any"`
+	CatAx           []*cAxs        `xml:"catAx"`
+	DateAx          []*cAxs        `xml:"dateAx"`
+	ValAx           []*cAxs        `xml:"valAx"`
+	SerAx           []*cAxs        `xml:"serAx"`
+	SpPr            *cSpPr         `xml:"spPr"`
+}
+
+// cChartGroup directly maps the chart group element, such as barChart and
+// lineChart, which is plotted on the secondary axes. The name of the element
+// is specified by the XMLName.
+type cChartGroup struct {
+	XMLName xml.Name
+	cCharts
 }
 
 // cCharts specifies the common element of the chart.
@@ -335,6 +357,9 @@ type cCharts struct {
 	Ser          *[]cSer        `xml:"ser"`
 	SerLines     *attrValString `xml:"serLines"`
 	DLbls        *cDLbls        `xml:"dLbls"`
+	HiLowLines   *cChartLines   `xml:"hiLowLines"`
+	UpDownBars   *cUpDownBars   `xml:"upDownBars"`
+	GapWidth     *attrValInt    `xml:"gapWidth"`
 	Shape        *attrValString `xml:"shape"`
 	HoleSize     *attrValInt    `xml:"holeSize"`
 	Smooth       *attrValBool   `xml:"smooth"`
@@ -350,6 +375,7 @@ type cAxs struct {
 	AxPos          *attrValString `xml:"axPos"`
 	MajorGridlines *cChartLines   `xml:"majorGridlines"`
 	MinorGridlines *cChartLines   `xml:"minorGridlines"`
+	Title          *cTitle        `xml:"title"`
 	NumFmt         *cNumFmt       `xml:"numFmt"`
 	MajorTickMark  *attrValString `xml:"majorTickMark"`
 	MinorTickMark  *attrValString `xml:"minorTickMark"`
@@ -358,17 +384,33 @@ type cAxs struct {
 	TxPr           *cTxPr         `xml:"txPr"`
 	CrossAx        *attrValInt    `xml:"crossAx"`
 	Crosses        *attrValString `xml:"crosses"`
+	CrossesAt      *attrValFloat  `xml:"crossesAt"`
 	CrossBetween   *attrValString `xml:"crossBetween"`
-	MajorUnit      *attrValFloat  `xml:"majorUnit"`
-	MinorUnit      *attrValFloat  `xml:"minorUnit"`
 	Auto           *attrValBool   `xml:"auto"`
 	LblAlgn        *attrValString `xml:"lblAlgn"`
 	LblOffset      *attrValInt    `xml:"lblOffset"`
+	BaseTimeUnit   *attrValString `xml:"baseTimeUnit"`
+	MajorUnit      *attrValFloat  `xml:"majorUnit"`
+	MajorTimeUnit  *attrValString `xml:"majorTimeUnit"`
+	MinorUnit      *attrValFloat  `xml:"minorUnit"`
+	MinorTimeUnit  *attrValString `xml:"minorTimeUnit"`
+	DispUnits      *cDispUnits    `xml:"dispUnits"`
 	TickLblSkip    *attrValInt    `xml:"tickLblSkip"`
 	TickMarkSkip   *attrValInt    `xml:"tickMarkSkip"`
 	NoMultiLvlLbl  *attrValBool   `xml:"noMultiLvlLbl"`
 }
 
+// cDispUnits directly maps the dispUnits element. This element specifies the
+// scaling value of the display units for the value axis.
+type cDispUnits struct {
+	BuiltInUnit  *attrValString `xml:"builtInUnit"`
+	DispUnitsLbl *cDispUnitsLbl `xml:"dispUnitsLbl"`
+}
+
+// cDispUnitsLbl directly maps the dispUnitsLbl element. This element
+// specifies the display units label shall be shown.
+type cDispUnitsLbl struct{}
+
 // cChartLines directly maps the chart lines content model.
 type cChartLines struct {
 	SpPr *cSpPr `xml:"spPr"`
@@ -390,24 +432,70 @@ type cNumFmt struct {
 	SourceLinked bool   `xml:"sourceLinked,attr"`
 }
 
+// cUpDownBars (Up/Down Bars) directly maps the upDownBars element. This
+// element specifies the up and down bars of the stock chart.
+type cUpDownBars struct {
+	GapWidth *attrValInt  `xml:"gapWidth"`
+	UpBars   *cChartLines `xml:"upBars"`
+	DownBars *cChartLines `xml:"downBars"`
+}
+
 // cSer directly maps the ser element. This element specifies a series on a
 // chart.
 type cSer struct {
-	IDx              *attrValInt  `xml:"idx"`
-	Order            *attrValInt  `xml:"order"`
-	Tx               *cTx         `xml:"tx"`
-	SpPr             *cSpPr       `xml:"spPr"`
-	DPt              []*cDPt      `xml:"dPt"`
-	DLbls            *cDLbls      `xml:"dLbls"`
-	Marker           *cMarker     `xml:"marker"`
-	InvertIfNegative *attrValBool `xml:"invertIfNegative"`
-	Cat              *cCat        `xml:"cat"`
-	Val              *cVal        `xml:"val"`
-	XVal             *cCat        `xml:"xVal"`
-	YVal             *cVal        `xml:"yVal"`
-	Smooth           *attrValBool `xml:"smooth"`
-	BubbleSize       *cVal        `xml:"bubbleSize"`
-	Bubble3D         *attrValBool `xml:"bubble3D"`
+	IDx              *attrValInt   `xml:"idx"`
+	Order            *attrValInt   `xml:"order"`
+	Tx               *cTx          `xml:"tx"`
+	SpPr             *cSpPr        `xml:"spPr"`
+	InvertIfNegative *attrValBool  `xml:"invertIfNegative"`
+	Marker           *cMarker      `xml:"marker"`
+	DPt              []*cDPt       `xml:"dPt"`
+	DLbls            *cDLbls       `xml:"dLbls"`
+	Trendline        []*cTrendline `xml:"trendline"`
+	ErrBars          []*cErrBars   `xml:"errBars"`
+	Cat              *cCat         `xml:"cat"`
+	Val              *cVal         `xml:"val"`
+	XVal             *cCat         `xml:"xVal"`
+	YVal             *cVal         `xml:"yVal"`
+	Smooth           *attrValBool  `xml:"smooth"`
+	BubbleSize       *cVal         `xml:"bubbleSize"`
+	Bubble3D         *attrValBool  `xml:"bubble3D"`
+}
+
+// cTrendline (Trendline) directly maps the trendline element. This element
+// specifies a trendline of the series.
+type cTrendline struct {
+	Name          string         `xml:"name,omitempty"`
+	SpPr          *cSpPr         `xml:"spPr"`
+	TrendlineType *attrValString `xml:"trendlineType"`
+	Order         *attrValInt    `xml:"order"`
+	Period        *attrValInt    `xml:"period"`
+	Forward       *attrValFloat  `xml:"forward"`
+	Backward      *attrValFloat  `xml:"backward"`
+	Intercept     *attrValFloat  `xml:"intercept"`
+	DispRSqr      *attrValBool   `xml:"dispRSqr"`
+	DispEq        *attrValBool   `xml:"dispEq"`
+	TrendlineLbl  *cTrendlineLbl `xml:"trendlineLbl"`
+}
+
+// cTrendlineLbl (Trendline Label) directly maps the trendlineLbl element.
+// This element specifies the label of the trendline which displays the
+// equation or the R-squared value.
+type cTrendlineLbl struct {
+	NumFmt *cNumFmt `xml:"numFmt"`
+}
+
+// cErrBars (Error Bars) directly maps the errBars element. This element
+// specifies the error bars of the series.
+type cErrBars struct {
+	ErrDir     *attrValString `xml:"errDir"`
+	ErrBarType *attrValString `xml:"errBarType"`
+	ErrValType *attrValString `xml:"errValType"`
+	NoEndCap   *attrValBool   `xml:"noEndCap"`
+	Plus       *cVal          `xml:"plus"`
+	Minus      *cVal          `xml:"minus"`
+	Val        *attrValFloat  `xml:"val"`
+	SpPr       *cSpPr         `xml:"spPr"`
 }
 
 // cMarker (Marker) directly maps the marker element. This element specifies a
@@ -422,6 +510,7 @@ type cMarker struct {
 // single data point.
 type cDPt struct {
 	IDx      *attrValInt  `xml:"idx"`
+	Marker   *cMarker     `xml:"marker"`
 	Bubble3D *attrValBool `xml:"bubble3D"`
 	SpPr     *cSpPr       `xml:"spPr"`
 }
@@ -492,11 +581,19 @@ type cDLbls struct {
 // cLegend (Legend) directly maps the legend element. This element specifies
 // the legend.
 type cLegend struct {
-	Layout    *string        `xml:"layout"`
-	LegendPos *attrValString `xml:"legendPos"`
-	Overlay   *attrValBool   `xml:"overlay"`
-	SpPr      *cSpPr         `xml:"spPr"`
-	TxPr      *cTxPr         `xml:"txPr"`
+	LegendPos   *attrValString  `xml:"legendPos"`
+	LegendEntry []*cLegendEntry `xml:"legendEntry"`
+	Layout      *string         `xml:"layout"`
+	Overlay     *attrValBool    `xml:"overlay"`
+	SpPr        *cSpPr          `xml:"spPr"`
+	TxPr        *cTxPr          `xml:"txPr"`
+}
+
+// cLegendEntry directly maps the legendEntry element. This element specifies
+// the properties of a legend entry.
+type cLegendEntry struct {
+	IDx    *attrValInt  `xml:"idx"`
+	Delete *attrValBool `xml:"delete"`
 }
 
 // cPrintSettings directly maps the printSettings element. This element
@@ -521,14 +618,17 @@ type cPageMargins struct {
 // formatChartAxis directly maps the format settings of the chart axis.
 type formatChartAxis struct {
 	None                bool    `json:"none"`
+	Name                string  `json:"name"`
 	Crossing            string  `json:"crossing"`
 	MajorGridlines      bool    `json:"major_grid_lines"`
 	MinorGridlines      bool    `json:"minor_grid_lines"`
 	MajorTickMark       string  `json:"major_tick_mark"`
 	MinorTickMark       string  `json:"minor_tick_mark"`
 	MinorUnitType       string  `json:"minor_unit_type"`
+	MinorUnit           float64 `json:"minor_unit"`
 	MajorUnit           float64 `json:"major_unit"`
 	MajorUnitType       string  `json:"major_unit_type"`
+	BaseUnit            string  `json:"base_unit"`
 	TickLabelSkip       int     `json:"tick_label_skip"`
 	DisplayUnits        string  `json:"display_units"`
 	DisplayUnitsVisible bool    `json:"display_units_visible"`
@@ -542,6 +642,7 @@ type formatChartAxis struct {
 		Bold      bool   `json:"bold"`
 		Italic    bool   `json:"italic"`
 		Underline bool   `json:"underline"`
+		Rotation  *int   `json:"rotation"`
 	} `json:"num_font"`
 	LogBase    float64      `json:"logbase"`
 	NameLayout formatLayout `json:"name_layout"`
@@ -563,6 +664,7 @@ type formatChart struct {
 	VaryColors bool                 `json:"vary_colors"`
 	XAxis      formatChartAxis      `json:"x_axis"`
 	YAxis      formatChartAxis      `json:"y_axis"`
+	Y2Axis     formatChartAxis      `json:"y2_axis"`
 	Chartarea  struct {
 		Border struct {
 			None bool `json:"none"`
@@ -600,7 +702,13 @@ type formatChart struct {
 	ShowHiddenData bool   `json:"show_hidden_data"`
 	SetRotation    int    `json:"set_rotation"`
 	SetHoleSize    int    `json:"set_hole_size"`
+	GapWidth       *int   `json:"gap_width"`
+	Overlap        *int   `json:"overlap"`
+	Template       string `json:"template"`
+	PivotTable     string `json:"pivot_table"`
 	order          int
+	template       *chartTemplateNode
+	pivotSource    *cPivotSource
 }
 
 // formatChartLegend directly maps the format settings of the chart legend.
@@ -609,6 +717,7 @@ type formatChartLegend struct {
 	DeleteSeries    []int        `json:"delete_series"`
 	Font            Font         `json:"font"`
 	Layout          formatLayout `json:"layout"`
+	Overlay         bool         `json:"overlay"`
 	Position        string       `json:"position"`
 	ShowLegendEntry bool         `json:"show_legend_entry"`
 	ShowLegendKey   bool         `json:"show_legend_key"`
@@ -619,6 +728,7 @@ type formatChartSeries struct {
 	Name       string `json:"name"`
 	Categories string `json:"categories"`
 	Values     string `json:"values"`
+	Sizes      string `json:"sizes"`
 	Line       struct {
 		None  bool    `json:"none"`
 		Color string  `json:"color"`
@@ -637,14 +747,81 @@ type formatChartSeries struct {
 			None  bool   `json:"none"`
 		} `json:"fill"`
 	} `json:"marker"`
+	Fill struct {
+		Color string `json:"color"`
+		None  bool   `json:"none"`
+	} `json:"fill"`
+	Smooth     bool                 `json:"smooth"`
+	Y2Axis     bool                 `json:"y2_axis"`
+	Points     []formatChartPoint   `json:"points"`
+	Trendline  formatChartTrendline `json:"trendline"`
+	XErrorBars formatChartErrorBars `json:"x_error_bars"`
+	YErrorBars formatChartErrorBars `json:"y_error_bars"`
+}
+
+// formatChartLine directly maps the format settings of the lines of the
+// trendline and the error bars in the chart series.
+type formatChartLine struct {
+	Color string  `json:"color"`
+	Width float64 `json:"width"`
+}
+
+// formatChartTrendline directly maps the format settings of the trendline in
+// the chart series.
+type formatChartTrendline struct {
+	Type            string          `json:"type"`
+	Name            string          `json:"name"`
+	Order           int             `json:"order"`
+	Period          int             `json:"period"`
+	Forward         float64         `json:"forward"`
+	Backward        float64         `json:"backward"`
+	Intercept       *float64        `json:"intercept"`
+	DisplayEquation bool            `json:"display_equation"`
+	DisplayRSquared bool            `json:"display_r_squared"`
+	Line            formatChartLine `json:"line"`
+}
+
+// formatChartErrorBars directly maps the format settings of the error bars
+// in the chart series.
+type formatChartErrorBars struct {
+	Type        string          `json:"type"`
+	Direction   string          `json:"direction"`
+	Value       float64         `json:"value"`
+	PlusValues  string          `json:"plus_values"`
+	MinusValues string          `json:"minus_values"`
+	NoEndCap    bool            `json:"no_end_cap"`
+	Line        formatChartLine `json:"line"`
+}
+
+// formatChartPoint directly maps the format settings of the data point in
+// the chart series.
+type formatChartPoint struct {
+	Index int `json:"index"`
+	Fill  struct {
+		Color string `json:"color"`
+	} `json:"fill"`
+	Border struct {
+		Color string  `json:"color"`
+		Width float64 `json:"width"`
+	} `json:"border"`
 }
 
 // formatChartTitle directly maps the format settings of the chart title.
 type formatChartTitle struct {
-	None    bool         `json:"none"`
-	Name    string       `json:"name"`
-	Overlay bool         `json:"overlay"`
-	Layout  formatLayout `json:"layout"`
+	None     bool          `json:"none"`
+	Name     string        `json:"name"`
+	RichText []RichTextRun `json:"rich_text"`
+	Font     *Font         `json:"font"`
+	Overlay  bool          `json:"overlay"`
+	Layout   formatLayout  `json:"layout"`
+	Fill     struct {
+		Color string `json:"color"`
+	} `json:"fill"`
+	Border struct {
+		Color string  `json:"color"`
+		Width float64 `json:"width"`
+		None  bool    `json:"none"`
+	} `json:"border"`
 }
 
 // formatLayout directly maps the format settings of the element layout.
@@ -654,3 +831,28 @@ type formatLayout struct {
 	Width  float64 `json:"width"`
 	Height float64 `json:"height"`
 }
+
+// Chart directly maps the chart in the worksheet. The name of the chart is
+// the name of the drawing object, which could be used to update the series
+// of the chart by the SetChartSeries function. The format and combo settings
+// of the chart are in the same format accepted by the AddChart function, and
+// the cell reference of the top left corner will be empty string for the
+// absolute anchored chart.
+type Chart struct {
+	Name   string
+	Cell   string
+	Format string
+	Combo  []string
+}
+
+// ChartSeries directly maps the data references of the chart series, which
+// used to update the series of the existing chart. The Name is the reference
+// of the cell which contains the series name, and the Categories, Values and
+// Sizes are the references of the cells which contain the category labels,
+// the values and the bubble sizes of the series.
+type ChartSeries struct {
+	Name       string
+	Categories string
+	Values     string
+	Sizes      string
+}
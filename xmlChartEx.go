@@ -0,0 +1,215 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxChartExSpace directly maps the chartSpace element of the chartex part.
+// The chartex namespace is for representing the extended chart types, such as
+// waterfall, funnel, treemap, sunburst, box and whisker, histogram and
+// pareto charts, the data of these charts are stored in the chartData element
+// and referenced by the series in the plot area.
+type xlsxChartExSpace struct {
+	XMLName   xml.Name    `xml:"http://schemas.microsoft.com/office/drawing/2014/chartex chartSpace"`
+	XMLNSa    string      `xml:"xmlns:a,attr"`
+	XMLNSr    string      `xml:"xmlns:r,attr"`
+	ChartData cxChartData `xml:"chartData"`
+	Chart     cxChart     `xml:"chart"`
+}
+
+// cxChartData directly maps the chartData element. This element specifies
+// the data used by the series of the chart.
+type cxChartData struct {
+	Data []*cxData `xml:"data"`
+}
+
+// cxData directly maps the data element. This element specifies the string
+// and numeric dimensions of the data referenced by the series with the same
+// identifier.
+type cxData struct {
+	ID     int      `xml:"id,attr"`
+	StrDim []*cxDim `xml:"strDim"`
+	NumDim []*cxDim `xml:"numDim"`
+}
+
+// cxDim directly maps the strDim and numDim element. This element specifies
+// the dimension type and the formula of the data.
+type cxDim struct {
+	Type string `xml:"type,attr"`
+	F    string `xml:"f"`
+}
+
+// cxChart directly maps the chart element of the chartex part.
+type cxChart struct {
+	Title    *cxTitle   `xml:"title"`
+	PlotArea cxPlotArea `xml:"plotArea"`
+	Legend   *cxLegend  `xml:"legend"`
+}
+
+// cxTitle directly maps the title element of the chartex part.
+type cxTitle struct {
+	Pos     string  `xml:"pos,attr"`
+	Align   string  `xml:"align,attr"`
+	Overlay bool    `xml:"overlay,attr"`
+	Tx      *cxText `xml:"tx"`
+}
+
+// cxText directly maps the tx element. This element specifies the text of
+// the title or the series name.
+type cxText struct {
+	TxData *cxTextData `xml:"txData"`
+}
+
+// cxTextData directly maps the txData element. This element specifies the
+// text by a formula or a literal value.
+type cxTextData struct {
+	F string `xml:"f,omitempty"`
+	V string `xml:"v,omitempty"`
+}
+
+// cxPlotArea directly maps the plotArea element of the chartex part.
+type cxPlotArea struct {
+	PlotAreaRegion cxPlotAreaRegion `xml:"plotAreaRegion"`
+	Axis           []*cxAxis        `xml:"axis"`
+}
+
+// cxPlotAreaRegion directly maps the plotAreaRegion element. This element
+// specifies the series of the chart.
+type cxPlotAreaRegion struct {
+	Series []*cxSeries `xml:"series"`
+}
+
+// cxSeries directly maps the series element of the chartex part. The layout
+// identifier specifies the chart type of the series.
+type cxSeries struct {
+	LayoutID   string        `xml:"layoutId,attr"`
+	OwnerIdx   *int          `xml:"ownerIdx,attr"`
+	Tx         *cxText       `xml:"tx"`
+	DataLabels *cxDataLabels `xml:"dataLabels"`
+	DataID     *attrValInt   `xml:"dataId"`
+	LayoutPr   *cxLayoutPr   `xml:"layoutPr"`
+	AxisID     []*attrValInt `xml:"axisId"`
+}
+
+// cxDataLabels directly maps the dataLabels element of the chartex part.
+type cxDataLabels struct {
+	Pos        string                 `xml:"pos,attr,omitempty"`
+	Visibility *cxDataLabelVisibility `xml:"visibility"`
+}
+
+// cxDataLabelVisibility directly maps the visibility element of the data
+// labels. This element specifies which contents are shown in the data labels.
+type cxDataLabelVisibility struct {
+	SeriesName   bool `xml:"seriesName,attr"`
+	CategoryName bool `xml:"categoryName,attr"`
+	Value        bool `xml:"value,attr"`
+}
+
+// cxLayoutPr directly maps the layoutPr element. This element specifies the
+// layout properties of the series depend on the chart type.
+type cxLayoutPr struct {
+	ParentLabelLayout *attrValString      `xml:"parentLabelLayout"`
+	Visibility        *cxSeriesVisibility `xml:"visibility"`
+	Aggregation       *cxEmptyElement     `xml:"aggregation"`
+	Binning           *cxBinning          `xml:"binning"`
+	Statistics        *cxStatistics       `xml:"statistics"`
+}
+
+// cxSeriesVisibility directly maps the visibility element of the series
+// layout properties. This element specifies which elements of the box and
+// whisker chart are shown.
+type cxSeriesVisibility struct {
+	MeanLine    bool `xml:"meanLine,attr"`
+	MeanMarker  bool `xml:"meanMarker,attr"`
+	Nonoutliers bool `xml:"nonoutliers,attr"`
+	Outliers    bool `xml:"outliers,attr"`
+}
+
+// cxBinning directly maps the binning element. This element specifies the
+// binning of the histogram and pareto charts.
+type cxBinning struct {
+	IntervalClosed string `xml:"intervalClosed,attr"`
+}
+
+// cxStatistics directly maps the statistics element. This element specifies
+// the quartile calculation method of the box and whisker chart.
+type cxStatistics struct {
+	QuartileMethod string `xml:"quartileMethod,attr"`
+}
+
+// cxAxis directly maps the axis element of the chartex part.
+type cxAxis struct {
+	ID             int             `xml:"id,attr"`
+	Hidden         bool            `xml:"hidden,attr,omitempty"`
+	CatScaling     *cxCatScaling   `xml:"catScaling"`
+	ValScaling     *cxValScaling   `xml:"valScaling"`
+	Units          *cxAxisUnits    `xml:"units"`
+	MajorGridlines *cxEmptyElement `xml:"majorGridlines"`
+	TickLabels     *cxEmptyElement `xml:"tickLabels"`
+}
+
+// cxCatScaling directly maps the catScaling element. This element specifies
+// the gap width between the categories of the category axis.
+type cxCatScaling struct {
+	GapWidth string `xml:"gapWidth,attr,omitempty"`
+}
+
+// cxValScaling directly maps the valScaling element. This element specifies
+// the maximum and minimum of the value axis.
+type cxValScaling struct {
+	Max string `xml:"max,attr,omitempty"`
+	Min string `xml:"min,attr,omitempty"`
+}
+
+// cxAxisUnits directly maps the units element. This element specifies the
+// display units of the axis.
+type cxAxisUnits struct {
+	Unit string `xml:"unit,attr"`
+}
+
+// cxLegend directly maps the legend element of the chartex part.
+type cxLegend struct {
+	Pos     string `xml:"pos,attr"`
+	Align   string `xml:"align,attr"`
+	Overlay bool   `xml:"overlay,attr"`
+}
+
+// cxEmptyElement directly maps the elements without attributes and children
+// in the chartex part, such as the aggregation, majorGridlines and tickLabels
+// element.
+type cxEmptyElement struct{}
+
+// xlsxChartExAlternateContent directly maps the mc:AlternateContent element
+// of the graphic frame which contains the extended chart in the drawing part.
+// The fallback shape will be displayed in the applications which doesn't
+// support the extended chart types.
+type xlsxChartExAlternateContent struct {
+	XMLName  xml.Name            `xml:"mc:AlternateContent"`
+	XMLNSMC  string              `xml:"xmlns:mc,attr"`
+	Choice   xlsxChartExChoice   `xml:"mc:Choice"`
+	Fallback xlsxChartExFallback `xml:"mc:Fallback"`
+}
+
+// xlsxChartExChoice directly maps the mc:Choice element of the extended
+// chart graphic frame.
+type xlsxChartExChoice struct {
+	XMLNSCX1     string `xml:"xmlns:cx1,attr,omitempty"`
+	XMLNSCX2     string `xml:"xmlns:cx2,attr,omitempty"`
+	Requires     string `xml:"Requires,attr"`
+	GraphicFrame xlsxGraphicFrame
+}
+
+// xlsxChartExFallback directly maps the mc:Fallback element of the extended
+// chart graphic frame.
+type xlsxChartExFallback struct {
+	Sp *xdrSp `xml:"xdr:sp"`
+}
@@ -25,6 +25,8 @@ type xlsxChartsheet struct {
 	PageSetup        *xlsxPageSetUp             `xml:"pageSetup"`
 	HeaderFooter     *xlsxHeaderFooter          `xml:"headerFooter"`
 	Drawing          *xlsxDrawing               `xml:"drawing"`
+	LegacyDrawing    *xlsxLegacyDrawing         `xml:"legacyDrawing"`
+	LegacyDrawingHF  *xlsxLegacyDrawingHF       `xml:"legacyDrawingHF"`
 	DrawingHF        *xlsxDrawingHF             `xml:"drawingHF"`
 	Picture          *xlsxPicture               `xml:"picture"`
 	WebPublishItems  *xlsxInnerXML              `xml:"webPublishItems"`
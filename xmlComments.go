@@ -74,8 +74,14 @@ type xlsxPhoneticRun struct {
 
 // formatComment directly maps the format settings of the comment.
 type formatComment struct {
-	Author string `json:"author"`
-	Text   string `json:"text"`
+	Author      string  `json:"author"`
+	Text        string  `json:"text"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	Fill        string  `json:"fill"`
+	BorderColor string  `json:"border_color"`
+	BorderWidth float64 `json:"border_width"`
+	Font        *Font   `json:"font"`
 }
 
 // Comment directly maps the comment information.
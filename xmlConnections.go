@@ -0,0 +1,87 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxConnections directly maps the connections element. This element
+// specifies the collection of all external data connections in the workbook.
+type xlsxConnections struct {
+	XMLName    xml.Name         `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main connections"`
+	Connection []xlsxConnection `xml:"connection"`
+}
+
+// xlsxConnection directly maps the connection element. This element specifies
+// a single external data connection, the properties of the connection depend
+// on the type of the data source. The attributes which are not modelled will
+// be preserved as-is.
+type xlsxConnection struct {
+	ID                    int               `xml:"id,attr"`
+	SourceFile            string            `xml:"sourceFile,attr,omitempty"`
+	OdcFile               string            `xml:"odcFile,attr,omitempty"`
+	KeepAlive             bool              `xml:"keepAlive,attr,omitempty"`
+	Interval              int               `xml:"interval,attr,omitempty"`
+	Name                  string            `xml:"name,attr,omitempty"`
+	Description           string            `xml:"description,attr,omitempty"`
+	Type                  int               `xml:"type,attr,omitempty"`
+	ReconnectionMethod    int               `xml:"reconnectionMethod,attr,omitempty"`
+	RefreshedVersion      int               `xml:"refreshedVersion,attr"`
+	MinRefreshableVersion int               `xml:"minRefreshableVersion,attr,omitempty"`
+	SavePassword          bool              `xml:"savePassword,attr,omitempty"`
+	New                   bool              `xml:"new,attr,omitempty"`
+	Deleted               bool              `xml:"deleted,attr,omitempty"`
+	OnlyUseConnectionFile bool              `xml:"onlyUseConnectionFile,attr,omitempty"`
+	Background            bool              `xml:"background,attr,omitempty"`
+	RefreshOnLoad         bool              `xml:"refreshOnLoad,attr,omitempty"`
+	SaveData              bool              `xml:"saveData,attr,omitempty"`
+	Credentials           string            `xml:"credentials,attr,omitempty"`
+	SingleSignOnID        string            `xml:"singleSignOnId,attr,omitempty"`
+	DbPr                  *xlsxDbPr         `xml:"dbPr"`
+	OlapPr                *xlsxConnectionPr `xml:"olapPr"`
+	WebPr                 *xlsxConnectionPr `xml:"webPr"`
+	TextPr                *xlsxConnectionPr `xml:"textPr"`
+	Parameters            *xlsxConnectionPr `xml:"parameters"`
+	ExtLst                *xlsxExtLst       `xml:"extLst"`
+	Attr                  []xml.Attr        `xml:",any,attr"`
+}
+
+// xlsxDbPr directly maps the dbPr element. This element specifies all
+// properties relating to an ODBC or OLE DB data connection.
+type xlsxDbPr struct {
+	Connection    string     `xml:"connection,attr"`
+	Command       string     `xml:"command,attr,omitempty"`
+	ServerCommand string     `xml:"serverCommand,attr,omitempty"`
+	CommandType   int        `xml:"commandType,attr,omitempty"`
+	Attr          []xml.Attr `xml:",any,attr"`
+}
+
+// xlsxConnectionPr directly maps the olapPr, webPr, textPr and parameters
+// element of the connection, the attributes and children elements will be
+// preserved as-is.
+type xlsxConnectionPr struct {
+	Attr    []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
+
+// Connection directly maps the settings of the external data connection. The
+// Query is the name of the Power Query (Data Mashup) query when the
+// connection is used to load the query.
+type Connection struct {
+	ID               int
+	Name             string
+	Description      string
+	Type             int
+	ConnectionString string
+	Command          string
+	RefreshOnLoad    bool
+	Query            string
+}
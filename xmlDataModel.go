@@ -0,0 +1,143 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// decodeWorkbookExt directly maps the extLst element in the workbook.
+type decodeWorkbookExt struct {
+	XMLName xml.Name            `xml:"extLst"`
+	Ext     []*xlsxWorksheetExt `xml:"ext"`
+}
+
+// decodeX15DataModel directly maps the dataModel element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2010/11/main. This
+// element specifies the tables and relationships of the workbook data model,
+// the model itself is stored as binary part in the xl/model folder.
+type decodeX15DataModel struct {
+	XMLName            xml.Name                     `xml:"dataModel"`
+	ModelTables        *decodeX15ModelTables        `xml:"modelTables"`
+	ModelRelationships *decodeX15ModelRelationships `xml:"modelRelationships"`
+}
+
+// decodeX15ModelTables directly maps the modelTables element.
+type decodeX15ModelTables struct {
+	ModelTable []*decodeX15ModelTable `xml:"modelTable"`
+}
+
+// decodeX15ModelTable directly maps the modelTable element.
+type decodeX15ModelTable struct {
+	ID         string `xml:"id,attr"`
+	Name       string `xml:"name,attr"`
+	Connection string `xml:"connection,attr"`
+}
+
+// decodeX15ModelRelationships directly maps the modelRelationships element.
+type decodeX15ModelRelationships struct {
+	ModelRelationship []*decodeX15ModelRelationship `xml:"modelRelationship"`
+}
+
+// decodeX15ModelRelationship directly maps the modelRelationship element.
+type decodeX15ModelRelationship struct {
+	FromTable  string `xml:"fromTable,attr"`
+	FromColumn string `xml:"fromColumn,attr"`
+	ToTable    string `xml:"toTable,attr"`
+	ToColumn   string `xml:"toColumn,attr"`
+}
+
+// decodePivotCacheMeasures directly maps the OLAP hierarchies and
+// calculated members of the pivotCacheDefinition element, which used to
+// inspect the measures of the workbook data model.
+type decodePivotCacheMeasures struct {
+	XMLName           xml.Name                 `xml:"pivotCacheDefinition"`
+	CacheHierarchies  *decodeCacheHierarchies  `xml:"cacheHierarchies"`
+	CalculatedMembers *decodeCalculatedMembers `xml:"calculatedMembers"`
+}
+
+// decodeCacheHierarchies directly maps the cacheHierarchies element.
+type decodeCacheHierarchies struct {
+	CacheHierarchy []*decodeCacheHierarchy `xml:"cacheHierarchy"`
+}
+
+// decodeCacheHierarchy directly maps the cacheHierarchy element.
+type decodeCacheHierarchy struct {
+	UniqueName   string `xml:"uniqueName,attr"`
+	Caption      string `xml:"caption,attr"`
+	Measure      bool   `xml:"measure,attr"`
+	MeasureGroup string `xml:"measureGroup,attr"`
+}
+
+// decodeCalculatedMembers directly maps the calculatedMembers element.
+type decodeCalculatedMembers struct {
+	CalculatedMember []*decodeCalculatedMember `xml:"calculatedMember"`
+}
+
+// decodeCalculatedMember directly maps the calculatedMember element.
+type decodeCalculatedMember struct {
+	Name   string                        `xml:"name,attr"`
+	Mdx    string                        `xml:"mdx,attr"`
+	ExtLst *decodeCalculatedMemberExtLst `xml:"extLst"`
+}
+
+// decodeCalculatedMemberExtLst directly maps the extLst element of the
+// calculated member.
+type decodeCalculatedMemberExtLst struct {
+	Ext []*decodeCalculatedMemberExt `xml:"ext"`
+}
+
+// decodeCalculatedMemberExt directly maps the ext element of the calculated
+// member.
+type decodeCalculatedMemberExt struct {
+	URI              string                     `xml:"uri,attr"`
+	CalculatedMember *decodeX15CalculatedMember `xml:"calculatedMember"`
+}
+
+// decodeX15CalculatedMember directly maps the calculatedMember element in the
+// namespace http://schemas.microsoft.com/office/spreadsheetml/2010/11/main.
+type decodeX15CalculatedMember struct {
+	MeasureGroup string `xml:"measureGroup,attr"`
+	Measure      bool   `xml:"measure,attr"`
+}
+
+// DataModelTable directly maps the table of the workbook data model.
+type DataModelTable struct {
+	ID         string
+	Name       string
+	Connection string
+}
+
+// DataModelRelationship directly maps the relationship between the tables of
+// the workbook data model.
+type DataModelRelationship struct {
+	FromTable  string
+	FromColumn string
+	ToTable    string
+	ToColumn   string
+}
+
+// DataModelMeasure directly maps the measure of the workbook data model. The
+// Formula is the DAX expression of the measure, which is only available for
+// the measures defined as calculated members in the pivot caches.
+type DataModelMeasure struct {
+	Name         string
+	Caption      string
+	MeasureGroup string
+	Formula      string
+}
+
+// DataModel directly maps the inspection result of the workbook data model.
+type DataModel struct {
+	Tables        []DataModelTable
+	Relationships []DataModelRelationship
+	Measures      []DataModelMeasure
+	Parts         []string
+}
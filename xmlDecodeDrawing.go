@@ -63,12 +63,13 @@ type decodeCNvSpPr struct {
 // changed after serialization and deserialization, two different structures
 // are defined. decodeWsDr just for deserialization.
 type decodeWsDr struct {
-	A             string              `xml:"xmlns a,attr"`
-	Xdr           string              `xml:"xmlns xdr,attr"`
-	R             string              `xml:"xmlns r,attr"`
-	OneCellAnchor []*decodeCellAnchor `xml:"oneCellAnchor,omitempty"`
-	TwoCellAnchor []*decodeCellAnchor `xml:"twoCellAnchor,omitempty"`
-	XMLName       xml.Name            `xml:"http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing wsDr,omitempty"`
+	A              string              `xml:"xmlns a,attr"`
+	Xdr            string              `xml:"xmlns xdr,attr"`
+	R              string              `xml:"xmlns r,attr"`
+	AbsoluteAnchor []*decodeCellAnchor `xml:"absoluteAnchor,omitempty"`
+	OneCellAnchor  []*decodeCellAnchor `xml:"oneCellAnchor,omitempty"`
+	TwoCellAnchor  []*decodeCellAnchor `xml:"twoCellAnchor,omitempty"`
+	XMLName        xml.Name            `xml:"http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing wsDr,omitempty"`
 }
 
 // decodeTwoCellAnchor directly maps the oneCellAnchor (One Cell Anchor Shape
@@ -76,10 +77,70 @@ type decodeWsDr struct {
 // specifies a two cell anchor placeholder for a group, a shape, or a drawing
 // element. It moves with cells and its extents are in EMU units.
 type decodeTwoCellAnchor struct {
-	From       *decodeFrom       `xml:"from"`
-	To         *decodeTo         `xml:"to"`
-	Pic        *decodePic        `xml:"pic,omitempty"`
-	ClientData *decodeClientData `xml:"clientData"`
+	Pos          *decodeOff          `xml:"pos"`
+	From         *decodeFrom         `xml:"from"`
+	To           *decodeTo           `xml:"to"`
+	Ext          *decodeExt          `xml:"ext"`
+	Sp           *decodeSp           `xml:"sp"`
+	Pic          *decodePic          `xml:"pic,omitempty"`
+	GraphicFrame *decodeGraphicFrame `xml:"graphicFrame"`
+	GrpSp        *decodeGrpSp        `xml:"grpSp"`
+	ClientData   *decodeClientData   `xml:"clientData"`
+}
+
+// decodeGrpSp directly maps the grpSp (Group Shape). This element specifies
+// a group shape that represents many shapes grouped together. This shape is
+// to be treated just as if it were a regular shape but instead of being
+// described by a single geometry it is made up of all the shape geometries
+// encompassed within it.
+type decodeGrpSp struct {
+	NvGrpSpPr    decodeNvGrpSpPr       `xml:"nvGrpSpPr"`
+	Sp           []*decodeSp           `xml:"sp"`
+	Pic          []*decodePic          `xml:"pic"`
+	GraphicFrame []*decodeGraphicFrame `xml:"graphicFrame"`
+	GrpSp        []*decodeGrpSp        `xml:"grpSp"`
+}
+
+// decodeNvGrpSpPr directly maps the nvGrpSpPr (Non-Visual Properties for a
+// Group Shape). This element specifies all non-visual properties for a group
+// shape.
+type decodeNvGrpSpPr struct {
+	CNvPr decodeCNvPr `xml:"cNvPr"`
+}
+
+// decodeGraphicFrame directly maps the graphicFrame (Graphic Frame). This
+// element specifies the existence of a graphics frame. This frame contains a
+// graphic that was generated by an external source and needs a container in
+// which to be displayed on the slide surface.
+type decodeGraphicFrame struct {
+	NvGraphicFramePr decodeNvGraphicFramePr `xml:"nvGraphicFramePr"`
+	Graphic          decodeGraphic          `xml:"graphic"`
+}
+
+// decodeNvGraphicFramePr directly maps the nvGraphicFramePr (Non-Visual
+// Properties for a Graphic Frame). This element specifies all non-visual
+// properties for a graphic frame.
+type decodeNvGraphicFramePr struct {
+	CNvPr decodeCNvPr `xml:"cNvPr"`
+}
+
+// decodeGraphic directly maps the graphic element. This element specifies the
+// existence of a single graphic object.
+type decodeGraphic struct {
+	GraphicData decodeGraphicData `xml:"graphicData"`
+}
+
+// decodeGraphicData directly maps the graphicData element. This element
+// specifies the reference to a graphic object within the document.
+type decodeGraphicData struct {
+	URI   string              `xml:"uri,attr"`
+	Chart *decodeGraphicChart `xml:"chart"`
+}
+
+// decodeGraphicChart directly maps the chart element in the graphic data.
+// This element specifies the relationship ID of the chart part.
+type decodeGraphicChart struct {
+	RID string `xml:"id,attr"`
 }
 
 // decodeCNvPr directly maps the cNvPr (Non-Visual Drawing Properties). This
@@ -87,10 +148,18 @@ type decodeTwoCellAnchor struct {
 // information that does not affect the appearance of the picture to be
 // stored.
 type decodeCNvPr struct {
-	ID    int    `xml:"id,attr"`
-	Name  string `xml:"name,attr"`
-	Descr string `xml:"descr,attr"`
-	Title string `xml:"title,attr,omitempty"`
+	ID         int               `xml:"id,attr"`
+	Name       string            `xml:"name,attr"`
+	Descr      string            `xml:"descr,attr"`
+	Title      string            `xml:"title,attr,omitempty"`
+	HlinkClick *decodeHlinkClick `xml:"hlinkClick"`
+}
+
+// decodeHlinkClick directly maps the hlinkClick (Click Hyperlink) element.
+// This element specifies the on-click hyperlink information to be applied to
+// the drawing object.
+type decodeHlinkClick struct {
+	RID string `xml:"id,attr"`
 }
 
 // decodePicLocks directly maps the picLocks (Picture Locks). This element
@@ -230,6 +299,6 @@ type decodeTo struct {
 // protected, and fPrintsWithSheet attribute (either true or false) determines
 // whether the object is printed when the sheet is printed.
 type decodeClientData struct {
-	FLocksWithSheet  bool `xml:"fLocksWithSheet,attr"`
-	FPrintsWithSheet bool `xml:"fPrintsWithSheet,attr"`
+	FLocksWithSheet  *bool `xml:"fLocksWithSheet,attr"`
+	FPrintsWithSheet *bool `xml:"fPrintsWithSheet,attr"`
 }
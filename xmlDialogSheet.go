@@ -0,0 +1,38 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxDialogsheet directly maps the dialogsheet element of Dialogsheet Parts
+// in a SpreadsheetML document. The dialog sheets are the legacy Excel 5.0
+// dialog sheets, the elements which are not managed by the library are kept
+// as is.
+type xlsxDialogsheet struct {
+	XMLName          xml.Name              `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main dialogsheet"`
+	SheetPr          *xlsxSheetPr          `xml:"sheetPr"`
+	SheetViews       *xlsxSheetViews       `xml:"sheetViews"`
+	SheetFormatPr    *xlsxSheetFormatPr    `xml:"sheetFormatPr"`
+	SheetProtection  *xlsxSheetProtection  `xml:"sheetProtection"`
+	CustomSheetViews *xlsxCustomSheetViews `xml:"customSheetViews"`
+	PrintOptions     *xlsxPrintOptions     `xml:"printOptions"`
+	PageMargins      *xlsxPageMargins      `xml:"pageMargins"`
+	PageSetUp        *xlsxPageSetUp        `xml:"pageSetup"`
+	HeaderFooter     *xlsxHeaderFooter     `xml:"headerFooter"`
+	Drawing          *xlsxDrawing          `xml:"drawing"`
+	LegacyDrawing    *xlsxLegacyDrawing    `xml:"legacyDrawing"`
+	LegacyDrawingHF  *xlsxLegacyDrawingHF  `xml:"legacyDrawingHF"`
+	DrawingHF        *xlsxDrawingHF        `xml:"drawingHF"`
+	OleObjects       *xlsxInnerXML         `xml:"oleObjects"`
+	Controls         *xlsxInnerXML         `xml:"controls"`
+	ExtLst           *xlsxExtLst           `xml:"extLst"`
+}
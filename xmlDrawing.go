@@ -37,7 +37,10 @@ var (
 // Source relationship and namespace.
 const (
 	SourceRelationshipOfficeDocument             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	SourceRelationshipCalcChain                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/calcChain"
 	SourceRelationshipChart                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"
+	SourceRelationshipChartEx                    = "http://schemas.microsoft.com/office/2014/relationships/chartEx"
+	SourceRelationshipCustomProperty             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customProperty"
 	SourceRelationshipComments                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
 	SourceRelationshipImage                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
 	SourceRelationshipTable                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/table"
@@ -47,10 +50,42 @@ const (
 	SourceRelationshipWorkSheet                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
 	SourceRelationshipChartsheet                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chartsheet"
 	SourceRelationshipDialogsheet                = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/dialogsheet"
+	SourceRelationshipMacrosheet                 = "http://schemas.microsoft.com/office/2006/relationships/xlMacrosheet"
 	SourceRelationshipPivotTable                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipPivotCache                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
+	SourceRelationshipPivotCacheRecords          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheRecords"
+	SourceRelationshipSheetMetadata              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sheetMetadata"
 	SourceRelationshipSharedStrings              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings"
+	SourceRelationshipStyles                     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	SourceRelationshipTimeline                   = "http://schemas.microsoft.com/office/2011/relationships/timeline"
+	SourceRelationshipTimelineCache              = "http://schemas.microsoft.com/office/2011/relationships/timelineCache"
+	SourceRelationshipTheme                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	SourceRelationshipExtendProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	SourceRelationshipCoreProperties             = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	SourceRelationshipCustomProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	SourceRelationshipCustomXML                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	SourceRelationshipCustomXMLProperties        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps"
+	SourceRelationshipClassificationLabels       = "http://schemas.microsoft.com/office/2020/02/relationships/classificationlabels"
+	SourceRelationshipFont                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/font"
+	SourceRelationshipOLEObject                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/oleObject"
+	SourceRelationshipPackage                    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/package"
+	SourceRelationshipExternalLink               = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLink"
+	SourceRelationshipExternalLinkPath           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLinkPath"
 	SourceRelationshipVBAProject                 = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
+	SourceRelationshipWebExtension               = "http://schemas.microsoft.com/office/2011/relationships/webextension"
+	SourceRelationshipWebExtensionTaskPanes      = "http://schemas.microsoft.com/office/2011/relationships/webextensiontaskpanes"
+	NameSpaceDrawingMLChartEx                    = "http://schemas.microsoft.com/office/drawing/2014/chartex"
+	NameSpaceDrawingMLChartEx1                   = "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"
+	NameSpaceDrawingMLChartEx2                   = "http://schemas.microsoft.com/office/drawing/2015/10/21/chartex"
+	NameSpaceDrawingMLTimeslicer                 = "http://schemas.microsoft.com/office/drawing/2012/timeslicer"
+	NameSpaceWebExtension                        = "http://schemas.microsoft.com/office/webextensions/webextension/2010/11"
+	NameSpaceWebExtensionTaskPanes               = "http://schemas.microsoft.com/office/webextensions/taskpanes/2010/11"
+	NameSpaceCustomProperties                    = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+	NameSpaceCustomXMLDataProperties             = "http://schemas.openxmlformats.org/officeDocument/2006/customXml"
+	NameSpaceCellAnnotations                     = "http://schemas.excelize.org/2021/cellAnnotations"
+	NameSpaceDynamicArray                        = "http://schemas.microsoft.com/office/spreadsheetml/2017/dynamicarray"
+	NameSpaceDocPropsVTypes                      = "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"
+	NameSpaceMIPLabelMetadata                    = "http://schemas.microsoft.com/office/2020/mipLabelMetadata"
 	NameSpaceXML                                 = "http://www.w3.org/XML/1998/namespace"
 	NameSpaceXMLSchemaInstance                   = "http://www.w3.org/2001/XMLSchema-instance"
 	StrictSourceRelationship                     = "http://purl.oclc.org/ooxml/officeDocument/relationships"
@@ -63,22 +98,42 @@ const (
 	NameSpaceDublinCoreTerms                     = "http://purl.org/dc/terms/"
 	NameSpaceDublinCoreMetadataIntiative         = "http://purl.org/dc/dcmitype/"
 	ContentTypeDrawing                           = "application/vnd.openxmlformats-officedocument.drawing+xml"
+	ContentTypeChartEx                           = "application/vnd.ms-office.chartex+xml"
 	ContentTypeDrawingML                         = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
+	ContentTypeObfuscatedFont                    = "application/vnd.openxmlformats-officedocument.obfuscatedFont"
+	ContentTypeFontTTF                           = "application/x-font-ttf"
+	ContentTypeFontOTF                           = "application/x-font-otf"
 	ContentTypeMacro                             = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypeSpreadSheetMLCalcChain            = "application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml"
 	ContentTypeSpreadSheetMLChartsheet           = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
+	ContentTypeSpreadSheetMLCustomProperty       = "application/vnd.openxmlformats-officedocument.spreadsheetml.customProperty"
 	ContentTypeSpreadSheetMLComments             = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
+	ContentTypeSpreadSheetMLPivotCacheRecords    = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheRecords+xml"
 	ContentTypeSpreadSheetMLPivotTable           = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
+	ContentTypeSpreadSheetMLSheetMetadata        = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheetMetadata+xml"
 	ContentTypeSpreadSheetMLSharedStrings        = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
 	ContentTypeSpreadSheetMLTable                = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
 	ContentTypeSpreadSheetMLWorksheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
+	ContentTypeSpreadSheetMLStyles               = "application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"
+	ContentTypeTimeline                          = "application/vnd.ms-excel.timeline+xml"
+	ContentTypeTimelineCache                     = "application/vnd.ms-excel.timelineCache+xml"
+	ContentTypeTheme                             = "application/vnd.openxmlformats-officedocument.theme+xml"
+	ContentTypeExtendedProperties                = "application/vnd.openxmlformats-officedocument.extended-properties+xml"
+	ContentTypeCoreProperties                    = "application/vnd.openxmlformats-package.core-properties+xml"
+	ContentTypeCustomProperties                  = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+	ContentTypeCustomXMLProperties               = "application/vnd.openxmlformats-officedocument.customXmlProperties+xml"
+	ContentTypeClassificationLabels              = "application/vnd.ms-office.classificationlabels+xml"
 	ContentTypeVBA                               = "application/vnd.ms-office.vbaProject"
 	ContentTypeVML                               = "application/vnd.openxmlformats-officedocument.vmlDrawing"
+	ContentTypeWebExtension                      = "application/vnd.ms-office.webextension+xml"
+	ContentTypeWebExtensionTaskPanes             = "application/vnd.ms-office.webextensiontaskpanes+xml"
 	// ExtURIConditionalFormattings is the extLst child element
 	// ([ISO/IEC29500-1:2016] section 18.2.10) of the worksheet element
 	// ([ISO/IEC29500-1:2016] section 18.3.1.99) is extended by the addition of
 	// new child ext elements ([ISO/IEC29500-1:2016] section 18.2.7)
 	ExtURIConditionalFormattings = "{78C0D931-6437-407D-A8EE-F0AAD7539E65}"
+	ExtURIConditionalFormatRule  = "{B025F937-C7B1-47D3-B67F-A62EFF666E3E}"
 	ExtURIDataValidations        = "{CCE6A557-97BC-4B89-ADB6-D9C93CAAB3DF}"
 	ExtURISparklineGroups        = "{05C60535-1F16-4fd2-B633-F4F36F0B64E0}"
 	ExtURISlicerListX14          = "{A8765BA9-456A-4DAB-B4F3-ACF838C121DE}"
@@ -88,8 +143,13 @@ const (
 	ExtURIIgnoredErrors          = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
 	ExtURIWebExtensions          = "{F7C9EE02-42E1-4005-9D12-6889AFFD525C}"
 	ExtURITimelineRefs           = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
+	ExtURITimelineCacheRefs      = "{D0CA8CA8-9F24-4464-BF8E-62219DCF47F9}"
+	ExtURIPivotCacheDefinition   = "{725AE2AE-9491-48be-B2B4-4EB974FC3084}"
 	ExtURIDrawingBlip            = "{28A0092B-C50C-407E-A947-70E740481C1C}"
 	ExtURIMacExcelMX             = "{64002731-A6B0-56B0-2670-7721B7C09600}"
+	ExtURIDataModel              = "{FCE2AD5D-F65C-4FA6-A056-5C36A1767C68}"
+	ExtURICalculatedMember       = "{0C70D0D5-359C-4a49-802D-23BBF952B5CE}"
+	ExtURIDynamicArrayProperties = "{bdbb8cdc-fa1e-496e-a857-3c3f30c029c3}"
 )
 
 // Excel specifications and limits
@@ -104,6 +164,8 @@ const (
 	TotalColumns         = 16384
 	TotalSheetHyperlinks = 65529
 	TotalCellChars       = 32767
+	MaxPasswordLength    = 255
+	MaxEncryptSpinCount  = 10000000
 	// pivotTableVersion should be greater than 3. One or more of the
 	// PivotTables chosen are created in a version of Excel earlier than
 	// Excel 2007 or in compatibility mode. Slicer can only be used with
@@ -354,8 +416,10 @@ type xlsxGraphic struct {
 // document. This graphic object is provided entirely by the document authors
 // who choose to persist this data within the document.
 type xlsxGraphicData struct {
-	URI   string     `xml:"uri,attr"`
-	Chart *xlsxChart `xml:"c:chart,omitempty"`
+	URI        string          `xml:"uri,attr"`
+	Chart      *xlsxChart      `xml:"c:chart,omitempty"`
+	ChartEx    *xlsxChartEx    `xml:"cx:chart,omitempty"`
+	Timeslicer *xlsxTimeslicer `xml:"tsle:timeslicer,omitempty"`
 }
 
 // xlsxChart (Chart) directly maps the c:chart element.
@@ -365,6 +429,14 @@ type xlsxChart struct {
 	R   string `xml:"xmlns:r,attr"`
 }
 
+// xlsxChartEx (Chart Extension) directly maps the cx:chart element, which
+// references the chartex part of the extended chart types.
+type xlsxChartEx struct {
+	CX  string `xml:"xmlns:cx,attr"`
+	RID string `xml:"r:id,attr"`
+	R   string `xml:"xmlns:r,attr"`
+}
+
 // xdrSp (Shape) directly maps the xdr:sp element. This element specifies the
 // existence of a single shape. A shape can either be a preset or a custom
 // geometry, defined using the SpreadsheetDrawingML framework. In addition to a
@@ -459,7 +531,66 @@ type formatPicture struct {
 	Hyperlink        string  `json:"hyperlink"`
 	HyperlinkType    string  `json:"hyperlink_type"`
 	Positioning      string  `json:"positioning"`
-}
+	Anchor           string  `json:"anchor"`
+	OffsetXEMU       int     `json:"x_offset_emu"`
+	OffsetYEMU       int     `json:"y_offset_emu"`
+	WidthEMU         int     `json:"width_emu"`
+	HeightEMU        int     `json:"height_emu"`
+	ToCell           string  `json:"to_cell"`
+	ToOffsetXEMU     int     `json:"to_x_offset_emu"`
+	ToOffsetYEMU     int     `json:"to_y_offset_emu"`
+	MaxWidth         int     `json:"max_width"`
+	MaxHeight        int     `json:"max_height"`
+	JPEGQuality      int     `json:"jpeg_quality"`
+	ConvertToJPEG    bool    `json:"convert_to_jpeg"`
+}
+
+// PictureAnchor directly maps the anchor settings of the picture in the
+// worksheet. The anchor type is one of "twoCell", "oneCell" and "absolute",
+// the positioning is the move and size behavior of the two cell anchored
+// picture, the position, offsets and sizes are in EMUs, and the cells
+// references will be empty string for the absolute anchored picture.
+type PictureAnchor struct {
+	Name        string
+	File        string
+	Anchor      string
+	Positioning string
+	FromCell    string
+	FromOffsetX int
+	FromOffsetY int
+	ToCell      string
+	ToOffsetX   int
+	ToOffsetY   int
+	X           int
+	Y           int
+	Width       int
+	Height      int
+}
+
+// DrawingObject directly maps the drawing object in the worksheet. The type
+// of the drawing object is one of "chart", "picture", "shape" and "group",
+// the ID is the unique identifier of the drawing object in the worksheet
+// drawing, and the cell reference of the top left corner will be empty
+// string for the absolute anchored drawing object.
+type DrawingObject struct {
+	ID   int
+	Name string
+	Type string
+	Cell string
+}
+
+// ZOrderAction is the action to change the z-order of the drawing object in
+// the worksheet, the objects with higher z-order are displayed in front of
+// the objects with lower z-order.
+type ZOrderAction byte
+
+// Z-order actions enumeration.
+const (
+	ZOrderBringToFront ZOrderAction = iota
+	ZOrderBringForward
+	ZOrderSendBackward
+	ZOrderSendToBack
+)
 
 // formatShape directly maps the format settings of the shape.
 type formatShape struct {
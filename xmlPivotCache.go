@@ -105,7 +105,7 @@ type xlsxCacheField struct {
 	SQLType             int              `xml:"sqlType,attr,omitempty"`
 	Hierarchy           int              `xml:"hierarchy,attr,omitempty"`
 	Level               int              `xml:"level,attr,omitempty"`
-	DatabaseField       bool             `xml:"databaseField,attr,omitempty"`
+	DatabaseField       *bool            `xml:"databaseField,attr"`
 	MappingCount        int              `xml:"mappingCount,attr,omitempty"`
 	MemberPropertyField bool             `xml:"memberPropertyField,attr,omitempty"`
 	SharedItems         *xlsxSharedItems `xml:"sharedItems"`
@@ -121,26 +121,26 @@ type xlsxCacheField struct {
 // those values that are referenced in multiple places across all the
 // PivotTable parts.
 type xlsxSharedItems struct {
-	ContainsSemiMixedTypes bool          `xml:"containsSemiMixedTypes,attr,omitempty"`
-	ContainsNonDate        bool          `xml:"containsNonDate,attr,omitempty"`
-	ContainsDate           bool          `xml:"containsDate,attr,omitempty"`
-	ContainsString         bool          `xml:"containsString,attr,omitempty"`
-	ContainsBlank          bool          `xml:"containsBlank,attr,omitempty"`
-	ContainsMixedTypes     bool          `xml:"containsMixedTypes,attr,omitempty"`
-	ContainsNumber         bool          `xml:"containsNumber,attr,omitempty"`
-	ContainsInteger        bool          `xml:"containsInteger,attr,omitempty"`
-	MinValue               float64       `xml:"minValue,attr,omitempty"`
-	MaxValue               float64       `xml:"maxValue,attr,omitempty"`
-	MinDate                string        `xml:"minDate,attr,omitempty"`
-	MaxDate                string        `xml:"maxDate,attr,omitempty"`
-	Count                  int           `xml:"count,attr"`
-	LongText               bool          `xml:"longText,attr,omitempty"`
-	M                      *xlsxMissing  `xml:"m"`
-	N                      *xlsxNumber   `xml:"n"`
-	B                      *xlsxBoolean  `xml:"b"`
-	E                      *xlsxError    `xml:"e"`
-	S                      *xlsxString   `xml:"s"`
-	D                      *xlsxDateTime `xml:"d"`
+	ContainsSemiMixedTypes *bool           `xml:"containsSemiMixedTypes,attr"`
+	ContainsNonDate        *bool           `xml:"containsNonDate,attr"`
+	ContainsDate           bool            `xml:"containsDate,attr,omitempty"`
+	ContainsString         *bool           `xml:"containsString,attr"`
+	ContainsBlank          bool            `xml:"containsBlank,attr,omitempty"`
+	ContainsMixedTypes     bool            `xml:"containsMixedTypes,attr,omitempty"`
+	ContainsNumber         bool            `xml:"containsNumber,attr,omitempty"`
+	ContainsInteger        bool            `xml:"containsInteger,attr,omitempty"`
+	MinValue               *float64        `xml:"minValue,attr"`
+	MaxValue               *float64        `xml:"maxValue,attr"`
+	MinDate                string          `xml:"minDate,attr,omitempty"`
+	MaxDate                string          `xml:"maxDate,attr,omitempty"`
+	Count                  int             `xml:"count,attr"`
+	LongText               bool            `xml:"longText,attr,omitempty"`
+	M                      []*xlsxMissing  `xml:"m"`
+	N                      []*xlsxNumber   `xml:"n"`
+	B                      []*xlsxBoolean  `xml:"b"`
+	E                      []*xlsxError    `xml:"e"`
+	S                      []*xlsxString   `xml:"s"`
+	D                      []*xlsxDateTime `xml:"d"`
 }
 
 // xlsxMissing represents a value that was not specified.
@@ -172,12 +172,14 @@ type xlsxTuples struct {
 
 // xlsxBoolean represents a boolean value for an item in the PivotTable.
 type xlsxBoolean struct {
+	V bool `xml:"v,attr"`
 }
 
 // xlsxError represents an error value. The use of this item indicates that an
 // error value is present in the PivotTable source. The error is recorded in
 // the value attribute.
 type xlsxError struct {
+	V string `xml:"v,attr"`
 }
 
 // xlsxString represents a character value in a PivotTable.
@@ -222,6 +224,55 @@ type xlsxTupleCache struct {
 
 // xlsxCalculatedItems represents the collection of calculated items.
 type xlsxCalculatedItems struct {
+	Count          int                   `xml:"count,attr"`
+	CalculatedItem []*xlsxCalculatedItem `xml:"calculatedItem"`
+}
+
+// xlsxCalculatedItem represents a calculated item defined in a PivotTable
+// field. The formula of the calculated item references the other items of
+// the field, and the pivot area specifies the shared item of the calculated
+// item in the cache field.
+type xlsxCalculatedItem struct {
+	Field     *int           `xml:"field,attr"`
+	Formula   string         `xml:"formula,attr,omitempty"`
+	PivotArea *xlsxPivotArea `xml:"pivotArea"`
+	ExtLst    *xlsxExtLst    `xml:"extLst"`
+}
+
+// xlsxPivotArea represents the rule to describe PivotTable selection, the
+// references select the items of the fields in the area.
+type xlsxPivotArea struct {
+	Field                       *int                     `xml:"field,attr"`
+	Type                        string                   `xml:"type,attr,omitempty"`
+	DataOnly                    *bool                    `xml:"dataOnly,attr"`
+	LabelOnly                   bool                     `xml:"labelOnly,attr,omitempty"`
+	GrandRow                    bool                     `xml:"grandRow,attr,omitempty"`
+	GrandCol                    bool                     `xml:"grandCol,attr,omitempty"`
+	CacheIndex                  bool                     `xml:"cacheIndex,attr,omitempty"`
+	Outline                     *bool                    `xml:"outline,attr"`
+	Offset                      string                   `xml:"offset,attr,omitempty"`
+	CollapsedLevelsAreSubtotals bool                     `xml:"collapsedLevelsAreSubtotals,attr,omitempty"`
+	Axis                        string                   `xml:"axis,attr,omitempty"`
+	FieldPosition               *int                     `xml:"fieldPosition,attr"`
+	References                  *xlsxPivotAreaReferences `xml:"references"`
+	ExtLst                      *xlsxExtLst              `xml:"extLst"`
+}
+
+// xlsxPivotAreaReferences represents the set of references in the pivot
+// area.
+type xlsxPivotAreaReferences struct {
+	Count     int                       `xml:"count,attr"`
+	Reference []*xlsxPivotAreaReference `xml:"reference"`
+}
+
+// xlsxPivotAreaReference represents a reference to the items of a field in
+// the pivot area, the x elements specify the index of the items.
+type xlsxPivotAreaReference struct {
+	Field    *int        `xml:"field,attr"`
+	Count    int         `xml:"count,attr"`
+	Selected *bool       `xml:"selected,attr"`
+	X        []*xlsxX    `xml:"x"`
+	ExtLst   *xlsxExtLst `xml:"extLst"`
 }
 
 // xlsxCalculatedMembers represents the collection of calculated members in an
@@ -241,3 +292,19 @@ type xlsxMeasureGroups struct {
 // xlsxMaps represents the PivotTable OLAP measure group - Dimension maps.
 type xlsxMaps struct {
 }
+
+// xlsxPivotCacheRecords represents the collection of records in the
+// PivotCache. This part stores the underlying source data that the PivotTable
+// aggregates.
+type xlsxPivotCacheRecords struct {
+	XMLName xml.Name      `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main pivotCacheRecords"`
+	Count   int           `xml:"count,attr"`
+	R       []*xlsxRecord `xml:"r"`
+}
+
+// xlsxRecord represents a single record of data in the PivotCache. The x
+// elements reference the index of the shared items of the cache fields in
+// the same order as the cache fields.
+type xlsxRecord struct {
+	X []*xlsxX `xml:"x"`
+}
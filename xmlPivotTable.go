@@ -70,7 +70,7 @@ type xlsxPivotTableDefinition struct {
 	Indent                  int                      `xml:"indent,attr,omitempty"`
 	ShowEmptyRow            bool                     `xml:"showEmptyRow,attr,omitempty"`
 	ShowEmptyCol            bool                     `xml:"showEmptyCol,attr,omitempty"`
-	ShowHeaders             bool                     `xml:"showHeaders,attr,omitempty"`
+	ShowHeaders             *bool                    `xml:"showHeaders,attr,omitempty"`
 	Compact                 bool                     `xml:"compact,attr"`
 	Outline                 bool                     `xml:"outline,attr"`
 	OutlineData             bool                     `xml:"outlineData,attr,omitempty"`
@@ -226,6 +226,7 @@ type xlsxI struct {
 
 // xlsxX represents an array of indexes to cached shared item values.
 type xlsxX struct {
+	V int `xml:"v,attr,omitempty"`
 }
 
 // xlsxColFields represents the collection of fields that are on the column
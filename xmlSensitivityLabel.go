@@ -0,0 +1,93 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxDocCustomProperties directly maps the Properties element in the namespace
+// http://schemas.openxmlformats.org/officeDocument/2006/custom-properties.
+// This element specifies the custom document properties of the package, the
+// Microsoft Information Protection sensitivity labels are stored as custom
+// properties with the name prefix MSIP_Label_.
+type xlsxDocCustomProperties struct {
+	XMLName  xml.Name                 `xml:"Properties"`
+	XMLNS    string                   `xml:"xmlns,attr"`
+	XMLNSVT  string                   `xml:"xmlns:vt,attr"`
+	Property []*xlsxDocCustomProperty `xml:"property"`
+}
+
+// xlsxDocCustomProperty directly maps the property element. This element
+// specifies a single custom property, the value element is kept as is to
+// preserve the properties which are not managed by the library.
+type xlsxDocCustomProperty struct {
+	FmtID string `xml:"fmtid,attr"`
+	PID   int    `xml:"pid,attr"`
+	Name  string `xml:"name,attr"`
+	Value string `xml:",innerxml"`
+}
+
+// decodeDocCustomProperties directly maps the Properties element.
+type decodeDocCustomProperties struct {
+	XMLName  xml.Name                   `xml:"Properties"`
+	Property []*decodeDocCustomProperty `xml:"property"`
+}
+
+// decodeDocCustomProperty directly maps the property element.
+type decodeDocCustomProperty struct {
+	FmtID   string `xml:"fmtid,attr"`
+	PID     int    `xml:"pid,attr"`
+	Name    string `xml:"name,attr"`
+	Lpwstr  string `xml:"lpwstr"`
+	Content string `xml:",innerxml"`
+}
+
+// xlsxClblLabelList directly maps the labelList element in the namespace
+// http://schemas.microsoft.com/office/2020/mipLabelMetadata. This element
+// specifies the sensitivity labels applied to the document.
+type xlsxClblLabelList struct {
+	XMLName   xml.Name         `xml:"clbl:labelList"`
+	XMLNSClbl string           `xml:"xmlns:clbl,attr"`
+	Label     []*xlsxClblLabel `xml:"clbl:label"`
+	ExtLst    *xlsxInnerXML    `xml:"clbl:extLst"`
+}
+
+// xlsxClblLabel directly maps the label element. This element specifies the
+// identifier, tenant and the state of a sensitivity label.
+type xlsxClblLabel struct {
+	ID          string `xml:"id,attr"`
+	Enabled     string `xml:"enabled,attr"`
+	Method      string `xml:"method,attr"`
+	SiteID      string `xml:"siteId,attr"`
+	ContentBits string `xml:"contentBits,attr,omitempty"`
+	Removed     string `xml:"removed,attr"`
+}
+
+// decodeClblLabelList directly maps the labelList element.
+type decodeClblLabelList struct {
+	XMLName xml.Name         `xml:"labelList"`
+	Label   []*xlsxClblLabel `xml:"label"`
+	ExtLst  *xlsxInnerXML    `xml:"extLst"`
+}
+
+// SensitivityLabel directly maps the settings of a Microsoft Information
+// Protection sensitivity label applied to the workbook.
+type SensitivityLabel struct {
+	ID          string
+	SiteID      string
+	Name        string
+	Method      string
+	Enabled     bool
+	Removed     bool
+	ContentBits int
+	SetDate     string
+	ActionID    string
+}
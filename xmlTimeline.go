@@ -0,0 +1,177 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxTimelines directly maps the timelines element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2010/11/main. This
+// element specifies the timelines in the worksheet.
+type xlsxTimelines struct {
+	XMLName   xml.Name        `xml:"timelines"`
+	XMLNS     string          `xml:"xmlns,attr"`
+	XMLNSMC   string          `xml:"xmlns:mc,attr"`
+	XMLNSX    string          `xml:"xmlns:x,attr"`
+	Ignorable string          `xml:"mc:Ignorable,attr"`
+	Timeline  []*xlsxTimeline `xml:"timeline"`
+}
+
+// xlsxTimeline directly maps the timeline element. This element specifies
+// the display settings of a timeline which filters the pivot tables on a
+// date field.
+type xlsxTimeline struct {
+	Name                    string `xml:"name,attr"`
+	Cache                   string `xml:"cache,attr"`
+	Caption                 string `xml:"caption,attr,omitempty"`
+	ShowHeader              bool   `xml:"showHeader,attr"`
+	ShowSelectionLabel      bool   `xml:"showSelectionLabel,attr"`
+	ShowTimeLevel           bool   `xml:"showTimeLevel,attr"`
+	ShowHorizontalScrollbar bool   `xml:"showHorizontalScrollbar,attr"`
+	Level                   int    `xml:"level,attr"`
+	SelectionLevel          int    `xml:"selectionLevel,attr"`
+	Style                   string `xml:"style,attr,omitempty"`
+}
+
+// decodeTimelines directly maps the timelines element for decoding.
+type decodeTimelines struct {
+	XMLName  xml.Name        `xml:"timelines"`
+	Timeline []*xlsxTimeline `xml:"timeline"`
+}
+
+// xlsxTimelineCacheDefinition directly maps the timelineCacheDefinition
+// element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2010/11/main. This
+// element specifies the source date field and the pivot tables filtered by
+// the timelines.
+type xlsxTimelineCacheDefinition struct {
+	XMLName     xml.Name                      `xml:"timelineCacheDefinition"`
+	XMLNS       string                        `xml:"xmlns,attr"`
+	XMLNSMC     string                        `xml:"xmlns:mc,attr"`
+	XMLNSX      string                        `xml:"xmlns:x,attr"`
+	Ignorable   string                        `xml:"mc:Ignorable,attr"`
+	Name        string                        `xml:"name,attr"`
+	SourceName  string                        `xml:"sourceName,attr"`
+	PivotTables *xlsxTimelineCachePivotTables `xml:"pivotTables"`
+	State       *xlsxTimelineState            `xml:"state"`
+}
+
+// xlsxTimelineCachePivotTables directly maps the pivotTables element of
+// the timeline cache.
+type xlsxTimelineCachePivotTables struct {
+	PivotTable []*xlsxTimelineCachePivotTable `xml:"pivotTable"`
+}
+
+// xlsxTimelineCachePivotTable directly maps the pivotTable element of the
+// timeline cache. This element specifies the sheet ID and the name of the
+// pivot table filtered by the timeline.
+type xlsxTimelineCachePivotTable struct {
+	TabID int    `xml:"tabId,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+// xlsxTimelineState directly maps the state element of the timeline cache.
+// This element specifies the pivot cache and the filter state of the
+// timeline.
+type xlsxTimelineState struct {
+	MinimalRefreshVersion int    `xml:"minimalRefreshVersion,attr"`
+	LastRefreshVersion    int    `xml:"lastRefreshVersion,attr"`
+	PivotCacheID          int    `xml:"pivotCacheId,attr"`
+	FilterType            string `xml:"filterType,attr"`
+}
+
+// xlsxX15TimelineRefs directly maps the timelineRefs element in the
+// worksheet extension list and the timelineCacheRefs element in the workbook
+// extension list.
+type xlsxX15TimelineRefs struct {
+	XMLName  xml.Name
+	XMLNSX15 string                `xml:"xmlns:x15,attr"`
+	Ref      []*xlsxX15TimelineRef `xml:",any"`
+}
+
+// xlsxX15TimelineRef directly maps the timelineRef and timelineCacheRef
+// element.
+type xlsxX15TimelineRef struct {
+	XMLName xml.Name
+	RID     string `xml:"r:id,attr"`
+}
+
+// decodeX15TimelineRefs directly maps the timelineRefs and timelineCacheRefs
+// element for decoding.
+type decodeX15TimelineRefs struct {
+	Ref []*struct {
+		RID string `xml:"id,attr"`
+	} `xml:",any"`
+}
+
+// xlsxX14PivotCacheDefinition directly maps the pivotCacheDefinition element
+// in the pivot cache definition extension list. This element specifies the
+// identifier of the pivot cache which is referenced by the slicers and
+// timelines.
+type xlsxX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"x14:pivotCacheDefinition"`
+	XMLNSX14     string   `xml:"xmlns:x14,attr"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
+
+// decodeX14PivotCacheDefinition directly maps the pivotCacheDefinition
+// element in the pivot cache definition extension list for decoding.
+type decodeX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"pivotCacheDefinition"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
+
+// xlsxTimelineAlternateContent directly maps the mc:AlternateContent element
+// of the graphic frame which contains the timeline in the drawing part. The
+// fallback shape will be displayed in the applications which doesn't
+// support the timelines.
+type xlsxTimelineAlternateContent struct {
+	XMLName  xml.Name            `xml:"mc:AlternateContent"`
+	XMLNSMC  string              `xml:"xmlns:mc,attr"`
+	Choice   xlsxTimelineChoice  `xml:"mc:Choice"`
+	Fallback xlsxChartExFallback `xml:"mc:Fallback"`
+}
+
+// xlsxTimelineChoice directly maps the mc:Choice element of the timeline
+// graphic frame.
+type xlsxTimelineChoice struct {
+	XMLNSTSLE    string `xml:"xmlns:tsle,attr"`
+	Requires     string `xml:"Requires,attr"`
+	GraphicFrame xlsxGraphicFrame
+}
+
+// xlsxTimeslicer directly maps the tsle:timeslicer element of the graphic
+// data, which references the timeline by name.
+type xlsxTimeslicer struct {
+	Name string `xml:"name,attr"`
+}
+
+// TimelineOptions directly maps the settings of the timeline. Name specifies
+// the unique name of the timeline, the field name will be used by default.
+// Cell specifies the top left cell of the timeline. PivotTable specifies the
+// name of the pivot table filtered by the timeline, and Field specifies the
+// date field of the pivot table data source. Level specifies the time level
+// of the timeline, which could be years, quarters, months (default) and days.
+type TimelineOptions struct {
+	Name                    string
+	Cell                    string
+	PivotTable              string
+	Field                   string
+	Caption                 string
+	Level                   string
+	Style                   string
+	Width                   int
+	Height                  int
+	ShowHeader              bool
+	ShowSelectionLabel      bool
+	ShowTimeLevel           bool
+	ShowHorizontalScrollbar bool
+}
@@ -0,0 +1,132 @@
+// Copyright 2016 - 2021 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Excel™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.15 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxWetpTaskpanes directly maps the taskpanes element in the namespace
+// http://schemas.microsoft.com/office/webextensions/taskpanes/2010/11. This
+// element specifies the task panes of the Office Add-ins in the workbook.
+type xlsxWetpTaskpanes struct {
+	XMLName   xml.Name            `xml:"wetp:taskpanes"`
+	XMLNSWetp string              `xml:"xmlns:wetp,attr"`
+	Taskpane  []*xlsxWetpTaskpane `xml:"wetp:taskpane"`
+}
+
+// xlsxWetpTaskpane directly maps the taskpane element. This element specifies
+// the dock state, visibility and size of an Office Add-in task pane.
+type xlsxWetpTaskpane struct {
+	DockState       string                   `xml:"dockstate,attr"`
+	Visibility      bool                     `xml:"visibility,attr"`
+	Width           float64                  `xml:"width,attr"`
+	Row             int                      `xml:"row,attr"`
+	Locked          bool                     `xml:"locked,attr,omitempty"`
+	WebExtensionRef *xlsxWetpWebExtensionRef `xml:"wetp:webextensionref"`
+}
+
+// xlsxWetpWebExtensionRef directly maps the webextensionref element. This
+// element specifies the relationship to the web extension part.
+type xlsxWetpWebExtensionRef struct {
+	XMLNSR string `xml:"xmlns:r,attr"`
+	RID    string `xml:"r:id,attr"`
+}
+
+// decodeWetpTaskpanes directly maps the taskpanes element.
+type decodeWetpTaskpanes struct {
+	XMLName  xml.Name              `xml:"taskpanes"`
+	Taskpane []*decodeWetpTaskpane `xml:"taskpane"`
+}
+
+// decodeWetpTaskpane directly maps the taskpane element.
+type decodeWetpTaskpane struct {
+	DockState       string                     `xml:"dockstate,attr"`
+	Visibility      bool                       `xml:"visibility,attr"`
+	Width           float64                    `xml:"width,attr"`
+	Row             int                        `xml:"row,attr"`
+	Locked          bool                       `xml:"locked,attr"`
+	WebExtensionRef *decodeWetpWebExtensionRef `xml:"webextensionref"`
+}
+
+// decodeWetpWebExtensionRef directly maps the webextensionref element.
+type decodeWetpWebExtensionRef struct {
+	RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+}
+
+// xlsxWeWebExtension directly maps the webextension element in the namespace
+// http://schemas.microsoft.com/office/webextensions/webextension/2010/11.
+// This element specifies the reference to the Office Add-in in the store and
+// the persisted properties of the add-in.
+type xlsxWeWebExtension struct {
+	XMLName             xml.Name          `xml:"we:webextension"`
+	XMLNSWe             string            `xml:"xmlns:we,attr"`
+	ID                  string            `xml:"id,attr"`
+	Reference           *xlsxWeReference  `xml:"we:reference"`
+	AlternateReferences *xlsxInnerXML     `xml:"we:alternateReferences"`
+	Properties          *xlsxWeProperties `xml:"we:properties"`
+	Bindings            *xlsxInnerXML     `xml:"we:bindings"`
+	Snapshot            *xlsxWeSnapshot   `xml:"we:snapshot"`
+}
+
+// xlsxWeReference directly maps the reference element. This element specifies
+// the identifier, version and store of the Office Add-in.
+type xlsxWeReference struct {
+	ID        string `xml:"id,attr"`
+	Version   string `xml:"version,attr"`
+	Store     string `xml:"store,attr"`
+	StoreType string `xml:"storeType,attr"`
+}
+
+// xlsxWeProperties directly maps the properties element.
+type xlsxWeProperties struct {
+	Property []*xlsxWeProperty `xml:"we:property"`
+}
+
+// xlsxWeProperty directly maps the property element. This element specifies
+// a name and value pair persisted by the Office Add-in.
+type xlsxWeProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// xlsxWeSnapshot directly maps the snapshot element.
+type xlsxWeSnapshot struct {
+	XMLNSR string `xml:"xmlns:r,attr"`
+}
+
+// decodeWeWebExtension directly maps the webextension element.
+type decodeWeWebExtension struct {
+	XMLName    xml.Name            `xml:"webextension"`
+	ID         string              `xml:"id,attr"`
+	Reference  *xlsxWeReference    `xml:"reference"`
+	Properties *decodeWeProperties `xml:"properties"`
+}
+
+// decodeWeProperties directly maps the properties element.
+type decodeWeProperties struct {
+	Property []*xlsxWeProperty `xml:"property"`
+}
+
+// WebExtension directly maps the settings of the Office Add-in and the task
+// pane which hosts the add-in.
+type WebExtension struct {
+	ID         string
+	AddinID    string
+	Version    string
+	Store      string
+	StoreType  string
+	Properties map[string]string
+	DockState  string
+	Visible    bool
+	Width      float64
+	Row        int
+	Locked     bool
+}
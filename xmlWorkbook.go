@@ -242,15 +242,15 @@ type xlsxDefinedName struct {
 // and details. Calculation is the process of computing formulas and then
 // displaying the results as values in the cells that contain the formulas.
 type xlsxCalcPr struct {
-	CalcCompleted         bool    `xml:"calcCompleted,attr,omitempty"`
+	CalcCompleted         *bool   `xml:"calcCompleted,attr"`
 	CalcID                string  `xml:"calcId,attr,omitempty"`
 	CalcMode              string  `xml:"calcMode,attr,omitempty"`
-	CalcOnSave            bool    `xml:"calcOnSave,attr,omitempty"`
+	CalcOnSave            *bool   `xml:"calcOnSave,attr"`
 	ConcurrentCalc        *bool   `xml:"concurrentCalc,attr"`
 	ConcurrentManualCount int     `xml:"concurrentManualCount,attr,omitempty"`
 	ForceFullCalc         bool    `xml:"forceFullCalc,attr,omitempty"`
 	FullCalcOnLoad        bool    `xml:"fullCalcOnLoad,attr,omitempty"`
-	FullPrecision         bool    `xml:"fullPrecision,attr,omitempty"`
+	FullPrecision         *bool   `xml:"fullPrecision,attr"`
 	Iterate               bool    `xml:"iterate,attr,omitempty"`
 	IterateCount          int     `xml:"iterateCount,attr,omitempty"`
 	IterateDelta          float64 `xml:"iterateDelta,attr,omitempty"`
@@ -284,7 +284,7 @@ type xlsxCustomWorkbookView struct {
 	IncludeHiddenRowCol  *bool   `xml:"includeHiddenRowCol,attr"`
 	IncludePrintSettings *bool   `xml:"includePrintSettings,attr"`
 	Maximized            *bool   `xml:"maximized,attr"`
-	MergeInterval        int     `xml:"mergeInterval,attr"`
+	MergeInterval        int     `xml:"mergeInterval,attr,omitempty"`
 	Minimized            *bool   `xml:"minimized,attr"`
 	Name                 *string `xml:"name,attr"`
 	OnlySync             *bool   `xml:"onlySync,attr"`
@@ -303,11 +303,58 @@ type xlsxCustomWorkbookView struct {
 	YWindow              *int    `xml:"yWindow,attr"`
 }
 
+// CustomView directly maps the settings of a custom view of the workbook. A
+// custom view is a named set of display, filter and optional print settings
+// of the worksheets. The ActiveSheet is the name of the active sheet when
+// the view is applied, IncludeHiddenRowCol and IncludePrintSettings specify
+// whether the hidden rows and columns and the print settings are part of the
+// view.
+type CustomView struct {
+	Name                 string
+	GUID                 string
+	ActiveSheet          string
+	PersonalView         bool
+	IncludeHiddenRowCol  bool
+	IncludePrintSettings bool
+	Sheets               []CustomSheetView
+}
+
+// CustomSheetView directly maps the settings of a worksheet in a custom view.
+// The FilterRange is the range of the auto filter saved with the view, the
+// View is one of "normal", "pageBreakPreview" and "pageLayout", the State is
+// one of "visible", "hidden" and "veryHidden".
+type CustomSheetView struct {
+	Sheet         string
+	TopLeftCell   string
+	Scale         int
+	View          string
+	State         string
+	ShowGridLines bool
+	HiddenRows    bool
+	HiddenColumns bool
+	FilterRange   string
+}
+
 // DefinedName directly maps the name for a cell or cell range on a
-// worksheet.
+// worksheet. Hidden specifies whether the defined name is hidden in the user
+// interface of the spreadsheet application.
 type DefinedName struct {
 	Name     string
 	Comment  string
 	RefersTo string
 	Scope    string
+	Hidden   bool
+}
+
+// SheetInfo directly maps the settings of a sheet in the workbook, include
+// the tab position, name, sheet ID, relationship ID, visibility state, sheet
+// type and the part path in the package.
+type SheetInfo struct {
+	Index   int
+	Name    string
+	SheetID int
+	RID     string
+	State   string
+	Type    string
+	Path    string
 }
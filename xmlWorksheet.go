@@ -46,7 +46,7 @@ type xlsxWorksheet struct {
 	HeaderFooter          *xlsxHeaderFooter            `xml:"headerFooter"`
 	RowBreaks             *xlsxBreaks                  `xml:"rowBreaks"`
 	ColBreaks             *xlsxBreaks                  `xml:"colBreaks"`
-	CustomProperties      *xlsxInnerXML                `xml:"customProperties"`
+	CustomProperties      *xlsxCustomProperties        `xml:"customProperties"`
 	CellWatches           *xlsxInnerXML                `xml:"cellWatches"`
 	IgnoredErrors         *xlsxInnerXML                `xml:"ignoredErrors"`
 	SmartTags             *xlsxInnerXML                `xml:"smartTags"`
@@ -68,6 +68,20 @@ type xlsxDrawing struct {
 	RID     string   `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
 }
 
+// xlsxCustomProperties directly maps the customProperties element. This
+// element specifies the collection of custom properties of the worksheet.
+type xlsxCustomProperties struct {
+	CustomPr []*xlsxCustomPr `xml:"customPr"`
+}
+
+// xlsxCustomPr directly maps the customPr element. This element specifies a
+// custom property of the worksheet, the name of the custom property and the
+// relationship ID of the custom property part which stores the value.
+type xlsxCustomPr struct {
+	Name string `xml:"name,attr"`
+	RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+}
+
 // xlsxHeaderFooter directly maps the headerFooter element in the namespace
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main - When printed or
 // viewed in page layout view (§18.18.69), each page of a worksheet can have a
@@ -363,7 +377,10 @@ type xlsxBreaks struct {
 	ManualBreakCount int        `xml:"manualBreakCount,attr,omitempty"`
 }
 
-// xlsxCustomSheetView directly maps the customSheetView element.
+// xlsxCustomSheetView directly maps the customSheetView element. This element
+// specifies the display and print settings of a worksheet for a custom view,
+// the attributes which default to true are pointers to keep the explicit
+// false values.
 type xlsxCustomSheetView struct {
 	Pane           *xlsxPane         `xml:"pane"`
 	Selection      *xlsxSelection    `xml:"selection"`
@@ -380,10 +397,10 @@ type xlsxCustomSheetView struct {
 	ColorID        int               `xml:"colorId,attr,omitempty"`
 	ShowPageBreaks bool              `xml:"showPageBreaks,attr,omitempty"`
 	ShowFormulas   bool              `xml:"showFormulas,attr,omitempty"`
-	ShowGridLines  bool              `xml:"showGridLines,attr,omitempty"`
-	ShowRowCol     bool              `xml:"showRowCol,attr,omitempty"`
-	OutlineSymbols bool              `xml:"outlineSymbols,attr,omitempty"`
-	ZeroValues     bool              `xml:"zeroValues,attr,omitempty"`
+	ShowGridLines  *bool             `xml:"showGridLines,attr"`
+	ShowRowCol     *bool             `xml:"showRowCol,attr"`
+	OutlineSymbols *bool             `xml:"outlineSymbols,attr"`
+	ZeroValues     *bool             `xml:"zeroValues,attr"`
 	FitToPage      bool              `xml:"fitToPage,attr,omitempty"`
 	PrintArea      bool              `xml:"printArea,attr,omitempty"`
 	Filter         bool              `xml:"filter,attr,omitempty"`
@@ -393,7 +410,7 @@ type xlsxCustomSheetView struct {
 	State          string            `xml:"state,attr,omitempty"`
 	FilterUnique   bool              `xml:"filterUnique,attr,omitempty"`
 	View           string            `xml:"view,attr,omitempty"`
-	ShowRuler      bool              `xml:"showRuler,attr,omitempty"`
+	ShowRuler      *bool             `xml:"showRuler,attr"`
 	TopLeftCell    string            `xml:"topLeftCell,attr,omitempty"`
 }
 
@@ -422,12 +439,15 @@ type xlsxDataValidations struct {
 }
 
 // DataValidation directly maps the a single item of data validation defined
-// on a range of the worksheet.
+// on a range of the worksheet. Note that the in-cell dropdown of the list
+// data validation will be suppressed when ShowDropDown is true, use the
+// SetShowDropDown function to set the visibility of the in-cell dropdown.
 type DataValidation struct {
 	AllowBlank       bool    `xml:"allowBlank,attr"`
 	Error            *string `xml:"error,attr"`
 	ErrorStyle       *string `xml:"errorStyle,attr"`
 	ErrorTitle       *string `xml:"errorTitle,attr"`
+	ImeMode          string  `xml:"imeMode,attr,omitempty"`
 	Operator         string  `xml:"operator,attr,omitempty"`
 	Prompt           *string `xml:"prompt,attr"`
 	PromptTitle      *string `xml:"promptTitle,attr"`
@@ -440,6 +460,25 @@ type DataValidation struct {
 	Formula2         string  `xml:",innerxml"`
 }
 
+// ChoiceColumnOptions directly maps the settings of the choice column, which
+// is a range of cells with in-cell dropdown of the choices and the
+// conditional formatting to highlight the cells by the selected choice.
+type ChoiceColumnOptions struct {
+	Choices      []Choice
+	Default      string
+	AllowBlank   bool
+	ErrorTitle   string
+	ErrorMessage string
+}
+
+// Choice directly maps the value and the highlight colors of a choice in the
+// choice column.
+type Choice struct {
+	Value     string
+	FillColor string
+	FontColor string
+}
+
 // xlsxC collection represents a cell in the worksheet. Information about the
 // cell's location (reference), value, data type, formatting, and formula is
 // expressed here.
@@ -459,8 +498,9 @@ type DataValidation struct {
 type xlsxC struct {
 	XMLName  xml.Name `xml:"c"`
 	XMLSpace xml.Attr `xml:"space,attr,omitempty"`
-	R        string   `xml:"r,attr,omitempty"` // Cell ID, e.g. A1
-	S        int      `xml:"s,attr,omitempty"` // Style reference.
+	R        string   `xml:"r,attr,omitempty"`  // Cell ID, e.g. A1
+	S        int      `xml:"s,attr,omitempty"`  // Style reference.
+	CM       int      `xml:"cm,attr,omitempty"` // Cell metadata index.
 	// Str string `xml:"str,attr,omitempty"` // Style reference.
 	T  string  `xml:"t,attr,omitempty"` // Type.
 	F  *xlsxF  `xml:"f,omitempty"`      // Formula
@@ -469,7 +509,7 @@ type xlsxC struct {
 }
 
 func (c *xlsxC) hasValue() bool {
-	return c.S != 0 || c.V != "" || c.F != nil || c.T != ""
+	return c.S != 0 || c.V != "" || c.F != nil || c.T != "" || c.IS != nil
 }
 
 // xlsxF represents a formula for the cell. The formula expression is
@@ -576,7 +616,7 @@ type xlsxDataBar struct {
 type xlsxIconSet struct {
 	Cfvo      []*xlsxCfvo `xml:"cfvo"`
 	IconSet   string      `xml:"iconSet,attr,omitempty"`
-	ShowValue bool        `xml:"showValue,attr,omitempty"`
+	ShowValue *bool       `xml:"showValue,attr"`
 	Percent   bool        `xml:"percent,attr,omitempty"`
 	Reverse   bool        `xml:"reverse,attr,omitempty"`
 }
@@ -584,7 +624,7 @@ type xlsxIconSet struct {
 // cfvo (Conditional Format Value Object) describes the values of the
 // interpolation points in a gradient scale.
 type xlsxCfvo struct {
-	Gte    bool        `xml:"gte,attr,omitempty"`
+	Gte    *bool       `xml:"gte,attr"`
 	Type   string      `xml:"type,attr,omitempty"`
 	Val    string      `xml:"val,attr,omitempty"`
 	ExtLst *xlsxExtLst `xml:"extLst"`
@@ -759,6 +799,159 @@ type xlsxX14Sparkline struct {
 	Sqref string `xml:"xm:sqref"`
 }
 
+// decodeX14ConditionalFormattings directly maps the conditionalFormattings
+// element.
+type decodeX14ConditionalFormattings struct {
+	XMLName               xml.Name                          `xml:"conditionalFormattings"`
+	ConditionalFormatting []*decodeX14ConditionalFormatting `xml:"conditionalFormatting"`
+	Content               string                            `xml:",innerxml"`
+}
+
+// decodeX14ConditionalFormatting directly maps the conditionalFormatting
+// element.
+type decodeX14ConditionalFormatting struct {
+	CfRule []*decodeX14CfRule `xml:"cfRule"`
+	Sqref  string             `xml:"sqref"`
+}
+
+// decodeX14CfRule directly maps the cfRule element.
+type decodeX14CfRule struct {
+	Type       string            `xml:"type,attr"`
+	Priority   int               `xml:"priority,attr"`
+	StopIfTrue bool              `xml:"stopIfTrue,attr"`
+	ID         string            `xml:"id,attr"`
+	IconSet    *decodeX14IconSet `xml:"iconSet"`
+	DataBar    *decodeX14DataBar `xml:"dataBar"`
+}
+
+// decodeX14DataBar directly maps the dataBar element.
+type decodeX14DataBar struct {
+	MinLength                            int              `xml:"minLength,attr"`
+	MaxLength                            int              `xml:"maxLength,attr"`
+	Gradient                             *bool            `xml:"gradient,attr"`
+	Border                               bool             `xml:"border,attr"`
+	Direction                            string           `xml:"direction,attr"`
+	NegativeBarColorSameAsPositive       bool             `xml:"negativeBarColorSameAsPositive,attr"`
+	NegativeBarBorderColorSameAsPositive *bool            `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string           `xml:"axisPosition,attr"`
+	Cfvo                                 []*decodeX14Cfvo `xml:"cfvo"`
+	BorderColor                          *xlsxColor       `xml:"borderColor"`
+	NegativeFillColor                    *xlsxColor       `xml:"negativeFillColor"`
+	NegativeBorderColor                  *xlsxColor       `xml:"negativeBorderColor"`
+	AxisColor                            *xlsxColor       `xml:"axisColor"`
+}
+
+// decodeX14CfRuleID directly maps the id element of the conditional
+// formatting rule extension.
+type decodeX14CfRuleID struct {
+	XMLName xml.Name `xml:"id"`
+	Value   string   `xml:",chardata"`
+}
+
+// decodeX14IconSet directly maps the iconSet element.
+type decodeX14IconSet struct {
+	IconSet   string             `xml:"iconSet,attr"`
+	ShowValue *bool              `xml:"showValue,attr"`
+	Reverse   bool               `xml:"reverse,attr"`
+	Custom    bool               `xml:"custom,attr"`
+	Cfvo      []*decodeX14Cfvo   `xml:"cfvo"`
+	CfIcon    []*decodeX14CfIcon `xml:"cfIcon"`
+}
+
+// decodeX14Cfvo directly maps the cfvo element.
+type decodeX14Cfvo struct {
+	Type string `xml:"type,attr"`
+	Gte  *bool  `xml:"gte,attr"`
+	F    string `xml:"f"`
+}
+
+// decodeX14CfIcon directly maps the cfIcon element.
+type decodeX14CfIcon struct {
+	IconSet string `xml:"iconSet,attr"`
+	IconID  int    `xml:"iconId,attr"`
+}
+
+// xlsxX14ConditionalFormattings directly maps the conditionalFormattings
+// element.
+type xlsxX14ConditionalFormattings struct {
+	XMLName xml.Name `xml:"x14:conditionalFormattings"`
+	Content string   `xml:",innerxml"`
+}
+
+// xlsxX14ConditionalFormatting directly maps the conditionalFormatting
+// element.
+type xlsxX14ConditionalFormatting struct {
+	XMLName xml.Name         `xml:"x14:conditionalFormatting"`
+	XMLNSXM string           `xml:"xmlns:xm,attr"`
+	CfRule  []*xlsxX14CfRule `xml:"x14:cfRule"`
+	Sqref   string           `xml:"xm:sqref"`
+}
+
+// xlsxX14CfRule directly maps the cfRule element.
+type xlsxX14CfRule struct {
+	Type       string          `xml:"type,attr,omitempty"`
+	Priority   int             `xml:"priority,attr,omitempty"`
+	StopIfTrue bool            `xml:"stopIfTrue,attr,omitempty"`
+	ID         string          `xml:"id,attr,omitempty"`
+	IconSet    *xlsxX14IconSet `xml:"x14:iconSet"`
+	DataBar    *xlsxX14DataBar `xml:"x14:dataBar"`
+}
+
+// xlsxX14DataBar directly maps the dataBar element.
+type xlsxX14DataBar struct {
+	MinLength                            int            `xml:"minLength,attr"`
+	MaxLength                            int            `xml:"maxLength,attr"`
+	Gradient                             *bool          `xml:"gradient,attr"`
+	Border                               bool           `xml:"border,attr,omitempty"`
+	Direction                            string         `xml:"direction,attr,omitempty"`
+	NegativeBarColorSameAsPositive       bool           `xml:"negativeBarColorSameAsPositive,attr,omitempty"`
+	NegativeBarBorderColorSameAsPositive *bool          `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string         `xml:"axisPosition,attr,omitempty"`
+	Cfvo                                 []*xlsxX14Cfvo `xml:"x14:cfvo"`
+	BorderColor                          *xlsxX14Color  `xml:"x14:borderColor"`
+	NegativeFillColor                    *xlsxX14Color  `xml:"x14:negativeFillColor"`
+	NegativeBorderColor                  *xlsxX14Color  `xml:"x14:negativeBorderColor"`
+	AxisColor                            *xlsxX14Color  `xml:"x14:axisColor"`
+}
+
+// xlsxX14Color directly maps the color settings of the data bar in the
+// worksheet extension list.
+type xlsxX14Color struct {
+	RGB string `xml:"rgb,attr,omitempty"`
+}
+
+// xlsxX14CfRuleID directly maps the extension of the conditional formatting
+// rule, which refers the rule in the worksheet extension list by its ID.
+type xlsxX14CfRuleID struct {
+	XMLName  xml.Name `xml:"ext"`
+	URI      string   `xml:"uri,attr"`
+	XMLNSX14 string   `xml:"xmlns:x14,attr"`
+	ID       string   `xml:"x14:id"`
+}
+
+// xlsxX14IconSet directly maps the iconSet element.
+type xlsxX14IconSet struct {
+	IconSet   string           `xml:"iconSet,attr,omitempty"`
+	ShowValue *bool            `xml:"showValue,attr"`
+	Reverse   bool             `xml:"reverse,attr,omitempty"`
+	Custom    bool             `xml:"custom,attr,omitempty"`
+	Cfvo      []*xlsxX14Cfvo   `xml:"x14:cfvo"`
+	CfIcon    []*xlsxX14CfIcon `xml:"x14:cfIcon"`
+}
+
+// xlsxX14Cfvo directly maps the cfvo element.
+type xlsxX14Cfvo struct {
+	Type string `xml:"type,attr"`
+	Gte  *bool  `xml:"gte,attr"`
+	F    string `xml:"xm:f,omitempty"`
+}
+
+// xlsxX14CfIcon directly maps the cfIcon element.
+type xlsxX14CfIcon struct {
+	IconSet string `xml:"iconSet,attr"`
+	IconID  int    `xml:"iconId,attr"`
+}
+
 // SparklineOption directly maps the settings of the sparkline.
 type SparklineOption struct {
 	Location      []string
@@ -805,29 +998,64 @@ type formatPanes struct {
 	} `json:"panes"`
 }
 
-// formatConditional directly maps the conditional format settings of the cells.
-type formatConditional struct {
-	Type         string `json:"type"`
-	AboveAverage bool   `json:"above_average"`
-	Percent      bool   `json:"percent"`
-	Format       int    `json:"format"`
-	Criteria     string `json:"criteria"`
-	Value        string `json:"value,omitempty"`
-	Minimum      string `json:"minimum,omitempty"`
-	Maximum      string `json:"maximum,omitempty"`
-	MinType      string `json:"min_type,omitempty"`
-	MidType      string `json:"mid_type,omitempty"`
-	MaxType      string `json:"max_type,omitempty"`
-	MinValue     string `json:"min_value,omitempty"`
-	MidValue     string `json:"mid_value,omitempty"`
-	MaxValue     string `json:"max_value,omitempty"`
-	MinColor     string `json:"min_color,omitempty"`
-	MidColor     string `json:"mid_color,omitempty"`
-	MaxColor     string `json:"max_color,omitempty"`
-	MinLength    string `json:"min_length,omitempty"`
-	MaxLength    string `json:"max_length,omitempty"`
-	MultiRange   string `json:"multi_range,omitempty"`
-	BarColor     string `json:"bar_color,omitempty"`
+// ConditionalFormatOptions directly maps the conditional format settings of
+// the cells. The Priority is the priority of the rule in the worksheet, the
+// Style is the resolved differential style definition of the Format, both of
+// them are returned by the GetConditionalFormats function. When creating the
+// rule, a new conditional style will be created by the Style if it's not
+// empty, which takes precedence over the Format.
+type ConditionalFormatOptions struct {
+	Type                   string                         `json:"type"`
+	AboveAverage           bool                           `json:"above_average"`
+	Percent                bool                           `json:"percent"`
+	Format                 int                            `json:"format"`
+	Style                  *Style                         `json:"style,omitempty"`
+	Priority               int                            `json:"priority,omitempty"`
+	StopIfTrue             bool                           `json:"stop_if_true,omitempty"`
+	Criteria               string                         `json:"criteria"`
+	Value                  string                         `json:"value,omitempty"`
+	Minimum                string                         `json:"minimum,omitempty"`
+	Maximum                string                         `json:"maximum,omitempty"`
+	MinType                string                         `json:"min_type,omitempty"`
+	MidType                string                         `json:"mid_type,omitempty"`
+	MaxType                string                         `json:"max_type,omitempty"`
+	MinValue               string                         `json:"min_value,omitempty"`
+	MidValue               string                         `json:"mid_value,omitempty"`
+	MaxValue               string                         `json:"max_value,omitempty"`
+	MinColor               string                         `json:"min_color,omitempty"`
+	MidColor               string                         `json:"mid_color,omitempty"`
+	MaxColor               string                         `json:"max_color,omitempty"`
+	MinLength              string                         `json:"min_length,omitempty"`
+	MaxLength              string                         `json:"max_length,omitempty"`
+	MultiRange             string                         `json:"multi_range,omitempty"`
+	BarColor               string                         `json:"bar_color,omitempty"`
+	BarSolid               bool                           `json:"bar_solid,omitempty"`
+	BarBorderColor         string                         `json:"bar_border_color,omitempty"`
+	BarNegativeColor       string                         `json:"bar_negative_color,omitempty"`
+	BarNegativeBorderColor string                         `json:"bar_negative_border_color,omitempty"`
+	BarDirection           string                         `json:"bar_direction,omitempty"`
+	BarAxisPosition        string                         `json:"bar_axis_position,omitempty"`
+	BarAxisColor           string                         `json:"bar_axis_color,omitempty"`
+	IconStyle              string                         `json:"icon_style,omitempty"`
+	ReverseIcons           bool                           `json:"reverse_icons,omitempty"`
+	IconsOnly              bool                           `json:"icons_only,omitempty"`
+	Icons                  []*ConditionalFormatIcon       `json:"icons,omitempty"`
+	CustomIcons            []*ConditionalFormatCustomIcon `json:"custom_icons,omitempty"`
+}
+
+// ConditionalFormatIcon directly maps the threshold settings of the icon in
+// the icon set conditional formatting rule.
+type ConditionalFormatIcon struct {
+	Criteria string `json:"criteria"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+}
+
+// ConditionalFormatCustomIcon directly maps the custom icon settings in the
+// icon set conditional formatting rule.
+type ConditionalFormatCustomIcon struct {
+	IconStyle string `json:"icon_style"`
+	IconIndex int    `json:"icon_index"`
 }
 
 // FormatSheetProtection directly maps the settings of worksheet protection.
@@ -864,6 +1092,21 @@ type FormatHeaderFooter struct {
 	FirstHeader      string
 }
 
+// FormatHeaderFooterImage directly maps the settings of a picture in the
+// header or footer. The Position is one of "left", "center" and "right", the
+// Page is one of "odd" (default), "even" and "first". The Width and Height
+// are in pixels, the size of the picture will be used by default.
+type FormatHeaderFooterImage struct {
+	Position  string
+	Page      string
+	IsFooter  bool
+	Title     string
+	Extension string
+	File      []byte
+	Width     int
+	Height    int
+}
+
 // FormatPageMargins directly maps the settings of page margins
 type FormatPageMargins struct {
 	Bottom string